@@ -8,7 +8,6 @@ import (
 	"github.com/Fantom-foundation/go-opera/config"
 	"github.com/Fantom-foundation/go-opera/config/flags"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
-	"github.com/ethereum/go-ethereum/params"
 	"os"
 	"os/signal"
 	"sort"
@@ -26,7 +25,7 @@ import (
 	ethmetrics "github.com/ethereum/go-ethereum/metrics"
 
 	"github.com/Fantom-foundation/go-opera/debug"
-	_ "github.com/Fantom-foundation/go-opera/version"
+	"github.com/Fantom-foundation/go-opera/version"
 )
 
 var (
@@ -61,6 +60,7 @@ func initFlags() {
 	}
 	performanceFlags = []cli.Flag{
 		flags.CacheFlag,
+		flags.MemoryBudgetFlag,
 	}
 	networkingFlags = []cli.Flag{
 		flags.BootnodesFlag,
@@ -73,6 +73,16 @@ func initFlags() {
 		flags.NetrestrictFlag,
 		flags.IPrestrictFlag,
 		flags.PrivateNodeFlag,
+		flags.LatencyDialFlag,
+		flags.SubnetQuotaFlag,
+		flags.P2PCaptureFlag,
+		flags.P2PCaptureTxsFlag,
+		flags.P2PCaptureMaxSizeFlag,
+		flags.P2PReplayFlag,
+		flags.TxPriorityFlag,
+		flags.PeerIdleTimeoutFlag,
+		flags.MinUsefulPeersFlag,
+		flags.RedialBackoffFlag,
 		flags.NodeKeyFileFlag,
 		flags.NodeKeyHexFlag,
 	}
@@ -88,6 +98,9 @@ func initFlags() {
 		flags.TxPoolAccountQueueFlag,
 		flags.TxPoolGlobalQueueFlag,
 		flags.TxPoolLifetimeFlag,
+		flags.TxPoolRebroadcastFlag,
+		flags.TxPoolRebroadcastExpiryFlag,
+		flags.TxPoolRebroadcastBumpAfterFlag,
 	}
 	operaFlags = []cli.Flag{
 		flags.IdentityFlag,
@@ -98,12 +111,23 @@ func initFlags() {
 		flags.SmartCardDaemonPathFlag,
 		flags.ExitWhenAgeFlag,
 		flags.ExitWhenEpochFlag,
+		flags.SkipIntegrityCheckFlag,
 		flags.LightKDFFlag,
 		flags.ConfigFileFlag,
 		flags.ValidatorIDFlag,
 		flags.ValidatorPubkeyFlag,
 		flags.ValidatorPasswordFlag,
+		flags.ValidatorRewardRecipientFlag,
+		flags.ValidatorRewardRecipientAuditFlag,
 		flags.ModeFlag,
+		flags.NetworkFlag,
+		flags.ArchivedContractsFlag,
+		flags.ReceiptsRetentionFlag,
+		flags.StateExpiryFlag,
+		flags.RemoteLogsIndexFlag,
+		flags.BloomBitsFlag,
+		flags.HistoryStartFlag,
+		flags.HistoryAnchorFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -124,8 +148,25 @@ func initFlags() {
 		flags.IPCPathFlag,
 		flags.RPCGlobalGasCapFlag,
 		flags.RPCGlobalEVMTimeoutFlag,
+		flags.RPCGlobalEVMMemoryLimitFlag,
+		flags.RPCGlobalReturnDataLimitFlag,
+		flags.RPCTraceWorkersFlag,
 		flags.RPCGlobalTxFeeCapFlag,
 		flags.RPCGlobalTimeoutFlag,
+		flags.RPCSlowCallThresholdFlag,
+		flags.RPCAccountsLimitFlag,
+		flags.RPCBalanceHistoryLimitFlag,
+		flags.RPCProofsLimitFlag,
+		flags.RPCMempoolTokenFlag,
+		flags.RPCFilterTimeoutFlag,
+		flags.RPCLogsTimeoutFlag,
+		flags.RPCDenyListFlag,
+		flags.RPCDenyListAuditFlag,
+		flags.RPCHeadLagFlag,
+		flags.RPCMaxStaleBlocksFlag,
+		flags.RPCAllowRollbackFlag,
+		flags.TxTracesPersistFlag,
+		flags.TxTracesRetentionFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -165,7 +206,7 @@ func initApp() {
 	app = cli.NewApp()
 	app.Name = "sonicd"
 	app.Usage = "the Sonic network client"
-	app.Version = params.VersionWithCommit(config.GitCommit, config.GitDate)
+	app.Version = version.WithCommit()
 	app.Action = lachesisMain
 	app.HideVersion = true // we have a command to print the version
 	app.Commands = []cli.Command{
@@ -2,18 +2,18 @@ package main
 
 import (
 	"fmt"
-	"github.com/Fantom-foundation/go-opera/config"
 	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/urfave/cli.v1"
 	"os"
 	"runtime"
 
 	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/version"
 )
 
 var (
 	versionCommand = cli.Command{
-		Action:    version,
+		Action:    printVersion,
 		Name:      "version",
 		Usage:     "Print version numbers",
 		ArgsUsage: " ",
@@ -24,14 +24,14 @@ The output of this command is supposed to be machine-readable.
 	}
 )
 
-func version(ctx *cli.Context) error {
-	fmt.Println(config.ClientIdentifier)
+func printVersion(ctx *cli.Context) error {
+	fmt.Println(version.ClientIdentifier)
 	fmt.Println("Version:", params.VersionWithMeta())
-	if config.GitCommit != "" {
-		fmt.Println("Git Commit:", config.GitCommit)
+	if version.GitCommit != "" {
+		fmt.Println("Git Commit:", version.GitCommit)
 	}
-	if config.GitDate != "" {
-		fmt.Println("Git Commit Date:", config.GitDate)
+	if version.GitDate != "" {
+		fmt.Println("Git Commit Date:", version.GitDate)
 	}
 	fmt.Println("Architecture:", runtime.GOARCH)
 	fmt.Println("Protocol Versions:", []uint{gossip.ProtocolVersion})
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	mptio "github.com/Fantom-foundation/Carmen/go/database/mpt/io"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/commitment"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+)
+
+var (
+	CommitmentHashFlag = cli.StringFlag{
+		Name:  "hash",
+		Usage: "Hash function of the commitment (" + strings.Join(commitmentHashers(), ", ") + ")",
+		Value: "sha256",
+	}
+	CommitmentBlockFlag = cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Commit the state of the block from the archive instead of the live state",
+	}
+)
+
+func commitmentHashers() []string {
+	names := make([]string, 0, len(commitment.Hashers))
+	for name := range commitment.Hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func stateCommitment(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	newHash, ok := commitment.Hashers[ctx.String(CommitmentHashFlag.Name)]
+	if !ok {
+		return fmt.Errorf("unknown hash function %q", ctx.String(CommitmentHashFlag.Name))
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// the state is exported in the world state format and committed on the fly
+	pr, pw := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		var err error
+		if ctx.IsSet(CommitmentBlockFlag.Name) {
+			block := ctx.Uint64(CommitmentBlockFlag.Name)
+			log.Info("Committing archive state", "block", block)
+			err = mptio.ExportBlockFromArchive(cancelCtx, mptio.NewLog(), filepath.Join(dataDir, "carmen", "archive"), pw, block)
+		} else {
+			log.Info("Committing live state")
+			err = mptio.Export(cancelCtx, mptio.NewLog(), filepath.Join(dataDir, "carmen", "live"), pw)
+		}
+		pw.CloseWithError(err)
+		exported <- err
+	}()
+	res, err := commitment.Compute(pr, newHash)
+	pr.CloseWithError(err)
+	if exportErr := <-exported; exportErr != nil {
+		return fmt.Errorf("failed to export the state: %w", exportErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to compute the commitment: %w", err)
+	}
+
+	log.Info("State commitment computed", "accounts", res.Accounts, "slots", res.Slots)
+	fmt.Printf("- State hash: %v \n", res.StateHash.Hex())
+	fmt.Printf("- Commitment (%s): %v \n", ctx.String(CommitmentHashFlag.Name), res.Root.Hex())
+	return nil
+}
@@ -0,0 +1,171 @@
+// Package commitment computes an experimental binary commitment over the flat state. It's intended for prototyping
+// ZK light clients, the commitment isn't a part of the consensus.
+//
+// The commitment is a binary Merkle tree (RFC 6962 shape) over the accounts, in the order of the state export:
+//
+//	leaf    = H(0x00 || data)
+//	node    = H(0x01 || left || right)
+//	account = 'A' || address || balance (32 bytes) || nonce (8 bytes) || code hash || storage root
+//	slot    = 'S' || key || value
+//
+// The storage root of an account is the binary Merkle tree over its slots, the root of an empty tree is H().
+package commitment
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Hashers are the supported hash functions of the commitment.
+var Hashers = map[string]func() hash.Hash{
+	"sha256":    sha256.New,
+	"keccak256": func() hash.Hash { return crypto.NewKeccakState() },
+}
+
+// Result is the commitment of a state.
+type Result struct {
+	// StateHash is the Ethereum state root of the committed state
+	StateHash common.Hash
+	// Root is the binary commitment
+	Root     common.Hash
+	Accounts uint64
+	Slots    uint64
+}
+
+// merkle accumulates the leaves of a binary Merkle tree, keeping only the roots of the complete subtrees.
+type merkle struct {
+	h     hash.Hash
+	stack []merkleNode
+}
+
+type merkleNode struct {
+	hash   common.Hash
+	height int
+}
+
+func (m *merkle) sum(prefix byte, data ...[]byte) common.Hash {
+	m.h.Reset()
+	m.h.Write([]byte{prefix})
+	for _, d := range data {
+		m.h.Write(d)
+	}
+	var res common.Hash
+	m.h.Sum(res[:0])
+	return res
+}
+
+func (m *merkle) add(data ...[]byte) {
+	n := merkleNode{hash: m.sum(0x00, data...)}
+	for len(m.stack) > 0 && m.stack[len(m.stack)-1].height == n.height {
+		top := m.stack[len(m.stack)-1]
+		m.stack = m.stack[:len(m.stack)-1]
+		n = merkleNode{hash: m.sum(0x01, top.hash[:], n.hash[:]), height: n.height + 1}
+	}
+	m.stack = append(m.stack, n)
+}
+
+// root folds the incomplete subtrees from the right and resets the accumulator.
+func (m *merkle) root() common.Hash {
+	defer func() {
+		m.stack = m.stack[:0]
+	}()
+	if len(m.stack) == 0 {
+		m.h.Reset()
+		var res common.Hash
+		m.h.Sum(res[:0])
+		return res
+	}
+	r := m.stack[len(m.stack)-1].hash
+	for i := len(m.stack) - 2; i >= 0; i-- {
+		r = m.sum(0x01, m.stack[i].hash[:], r[:])
+	}
+	return r
+}
+
+const (
+	stateMagic        = "Fantom-World-State"
+	stateVersion      = 1
+	accountEntryBytes = common.AddressLength + 32 + 8 + common.HashLength
+	slotEntryBytes    = 2 * common.HashLength
+)
+
+// Compute reads the state in the Fantom World State export format and computes its commitment.
+func Compute(in io.Reader, newHash func() hash.Hash) (Result, error) {
+	var res Result
+	r := bufio.NewReader(in)
+
+	header := make([]byte, len(stateMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return res, fmt.Errorf("failed to read the state header: %w", err)
+	}
+	if string(header[:len(stateMagic)]) != stateMagic {
+		return res, errors.New("not a world state export")
+	}
+	if v := header[len(stateMagic)]; v != stateVersion {
+		return res, fmt.Errorf("unsupported world state version %d", v)
+	}
+
+	accounts := merkle{h: newHash()}
+	storage := merkle{h: newHash()}
+	var account []byte // the account being read, it's committed once all its slots are read
+	commitAccount := func() {
+		if account != nil {
+			storageRoot := storage.root()
+			accounts.add([]byte{'A'}, account, storageRoot[:])
+			res.Accounts++
+		}
+	}
+	for {
+		tag, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return res, err
+		}
+		switch tag {
+		case 'H':
+			buf := make([]byte, 1+common.HashLength)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return res, fmt.Errorf("failed to read the state hash: %w", err)
+			}
+			res.StateHash = common.BytesToHash(buf[1:])
+		case 'C':
+			var size [2]byte
+			if _, err := io.ReadFull(r, size[:]); err != nil {
+				return res, fmt.Errorf("failed to read the code size: %w", err)
+			}
+			if _, err := r.Discard(int(size[0])<<8 | int(size[1])); err != nil {
+				return res, fmt.Errorf("failed to read the code: %w", err)
+			}
+		case 'A':
+			commitAccount()
+			account = make([]byte, accountEntryBytes)
+			if _, err := io.ReadFull(r, account); err != nil {
+				return res, fmt.Errorf("failed to read the account: %w", err)
+			}
+		case 'S':
+			if account == nil {
+				return res, errors.New("storage slot without an account")
+			}
+			slot := make([]byte, slotEntryBytes)
+			if _, err := io.ReadFull(r, slot); err != nil {
+				return res, fmt.Errorf("failed to read the storage slot: %w", err)
+			}
+			storage.add([]byte{'S'}, slot)
+			res.Slots++
+		default:
+			return res, fmt.Errorf("unexpected world state entry %q", tag)
+		}
+	}
+	commitAccount()
+	res.Root = accounts.root()
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package commitment
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func leaf(data ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func node(l, r []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(l)
+	h.Write(r)
+	return h.Sum(nil)
+}
+
+func account(b byte) []byte {
+	return bytes.Repeat([]byte{b}, accountEntryBytes)
+}
+
+func slot(b byte) []byte {
+	return bytes.Repeat([]byte{b}, slotEntryBytes)
+}
+
+func export(entries ...[]byte) []byte {
+	buf := append([]byte(stateMagic), stateVersion, 'H', 0)
+	buf = append(buf, bytes.Repeat([]byte{0xaa}, common.HashLength)...)
+	// codes are skipped
+	buf = append(buf, 'C', 0, 3, 1, 2, 3)
+	for _, e := range entries {
+		buf = append(buf, e...)
+	}
+	return buf
+}
+
+func TestCompute(t *testing.T) {
+	require := require.New(t)
+
+	empty := sha256.Sum256(nil)
+	res, err := Compute(bytes.NewReader(export()), sha256.New)
+	require.NoError(err)
+	require.Equal(common.Hash(empty), res.Root)
+	require.Equal(common.BytesToHash(bytes.Repeat([]byte{0xaa}, common.HashLength)), res.StateHash)
+
+	res, err = Compute(bytes.NewReader(export(
+		append([]byte{'A'}, account(1)...),
+		append([]byte{'S'}, slot(1)...),
+		append([]byte{'S'}, slot(2)...),
+		append([]byte{'S'}, slot(3)...),
+		append([]byte{'A'}, account(2)...),
+		append([]byte{'A'}, account(3)...),
+	)), sha256.New)
+	require.NoError(err)
+	require.Equal(uint64(3), res.Accounts)
+	require.Equal(uint64(3), res.Slots)
+
+	storage := node(node(leaf([]byte{'S'}, slot(1)), leaf([]byte{'S'}, slot(2))), leaf([]byte{'S'}, slot(3)))
+	a1 := leaf([]byte{'A'}, account(1), storage)
+	a2 := leaf([]byte{'A'}, account(2), empty[:])
+	a3 := leaf([]byte{'A'}, account(3), empty[:])
+	require.Equal(common.BytesToHash(node(node(a1, a2), a3)), res.Root)
+}
+
+func TestComputeRejectsMalformed(t *testing.T) {
+	_, err := Compute(bytes.NewReader([]byte("not a state")), sha256.New)
+	require.Error(t, err)
+
+	_, err = Compute(bytes.NewReader(export(append([]byte{'S'}, slot(1)...))), sha256.New)
+	require.Error(t, err)
+
+	_, err = Compute(bytes.NewReader(export([]byte{'A', 1, 2})), sha256.New)
+	require.Error(t, err)
+
+	_, err = Compute(bytes.NewReader(export([]byte{'X'})), sha256.New)
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
+)
+
+func datadirLayout(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	m, err := integration.DetectLayout(dataDir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		fmt.Println("Datadir is not initialized")
+		return nil
+	}
+	network := m.Network
+	if network == "" {
+		network = "unknown"
+	}
+	fmt.Printf("Layout version: %d (supported: %d)\n", m.Version, integration.LayoutVersion)
+	fmt.Printf("Network: %s\n", network)
+	switch {
+	case m.Version > integration.LayoutVersion:
+		fmt.Println("Datadir is written by a newer release, upgrade the tool")
+	case m.NeedsMigration():
+		fmt.Println("Datadir needs a migration, run: sonictool --datadir=<datadir> datadir migrate")
+	}
+	return nil
+}
+
+func datadirMigrate(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	m, err := integration.MigrateDatadir(dataDir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("datadir %s is not initialized", dataDir)
+	}
+	fmt.Printf("Datadir layout version is %d\n", m.Version)
+	return nil
+}
@@ -47,7 +47,8 @@ func RemoveDatabase(dataDir string) error {
 	err1 := os.RemoveAll(filepath.Join(dataDir, "chaindata"))
 	err2 := os.RemoveAll(filepath.Join(dataDir, "carmen"))
 	err3 := os.RemoveAll(filepath.Join(dataDir, "errlock"))
-	return errors.Join(err1, err2, err3)
+	err4 := os.RemoveAll(filepath.Join(dataDir, integration.ManifestFile))
+	return errors.Join(err1, err2, err3, err4)
 }
 
 func MakeDbProducer(chaindataDir string, cacheRatio cachescale.Func) (kvdb.FullDBProducer, error) {
@@ -80,8 +80,17 @@ func healGossipDb(producer kvdb.FlushableDBProducer, cfg gossip.StoreConfig, las
 	}
 	defer gdb.Close()
 
+	// a rollback scheduled by debug_setHead limits the epoch to revert to
+	maxEpoch := gdb.GetEpoch()
+	if target := gdb.GetRollbackTarget(); target != nil {
+		log.Info("Applying scheduled rollback", "epoch", *target)
+		if *target < maxEpoch {
+			maxEpoch = *target
+		}
+	}
+
 	// find the last closed epoch with the state available
-	epochIdx, blockState, epochState := getLastEpochWithState(gdb, lastCarmenBlock)
+	epochIdx, blockState, epochState := getLastEpochWithState(gdb, maxEpoch, lastCarmenBlock)
 	if blockState == nil || epochState == nil {
 		return nil, 0, fmt.Errorf("no epoch with available state found")
 	}
@@ -102,12 +111,14 @@ func healGossipDb(producer kvdb.FlushableDBProducer, cfg gossip.StoreConfig, las
 		return true
 	})
 
+	gdb.DelRollbackTarget()
+
 	return epochState, blockState.LastBlock.Idx, nil
 }
 
-// getLastEpochWithState finds the last closed epoch with the state available
-func getLastEpochWithState(gdb *gossip.Store, lastCarmenBlock idx.Block) (epochIdx idx.Epoch, blockState *iblockproc.BlockState, epochState *iblockproc.EpochState) {
-	currentEpoch := gdb.GetEpoch()
+// getLastEpochWithState finds the last closed epoch (not above maxEpoch) with the state available
+func getLastEpochWithState(gdb *gossip.Store, maxEpoch idx.Epoch, lastCarmenBlock idx.Block) (epochIdx idx.Epoch, blockState *iblockproc.BlockState, epochState *iblockproc.EpochState) {
+	currentEpoch := maxEpoch
 	epochsToTry := idx.Epoch(10000)
 	endEpoch := idx.Epoch(1)
 	if currentEpoch > epochsToTry {
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+const blocksPerEpoch = 3
+
+// epochStartBlock is the last block of the previous epoch, i.e. the block of the epoch checkpoint
+func epochStartBlock(epoch idx.Epoch) idx.Block {
+	return idx.Block(epoch) * blocksPerEpoch
+}
+
+// blockEpoch is the epoch of the block
+func blockEpoch(n idx.Block) idx.Epoch {
+	return idx.Epoch((n - 1) / blocksPerEpoch)
+}
+
+func stateRoot(n idx.Block) hash.Hash {
+	return hash.BytesToHash(n.Bytes())
+}
+
+func blockEpochState(epoch idx.Epoch, n idx.Block) (iblockproc.BlockState, iblockproc.EpochState) {
+	bs := iblockproc.BlockState{
+		LastBlock: iblockproc.BlockCtx{
+			Idx:  n,
+			Time: inter.Timestamp(n),
+		},
+		FinalizedStateRoot: stateRoot(n),
+	}
+	es := iblockproc.EpochState{
+		Epoch:      epoch,
+		Validators: pos.NewBuilder().Build(),
+		Rules:      opera.FakeNetRules(),
+	}
+	return bs, es
+}
+
+// makeChainStore makes the gossip DB with the epoch checkpoints and events of the epochs [firstEpoch, lastEpoch],
+// the head is a block within the lastEpoch, which isn't sealed yet.
+func makeChainStore(t *testing.T, producer *DummyScopedProducer, cfg gossip.StoreConfig, firstEpoch, lastEpoch idx.Epoch) map[idx.Epoch]hash.Event {
+	gdb, err := gossip.NewStore(producer, cfg)
+	require.NoError(t, err)
+	defer gdb.Close()
+
+	events := make(map[idx.Epoch]hash.Event)
+	for epoch := firstEpoch; epoch <= lastEpoch; epoch++ {
+		bs, es := blockEpochState(epoch, epochStartBlock(epoch))
+		gdb.SetHistoryBlockEpochState(epoch, bs, es)
+
+		me := &inter.MutableEventPayload{}
+		me.SetVersion(1)
+		me.SetEpoch(epoch)
+		me.SetLamport(1)
+		e := me.Build()
+		gdb.SetEvent(e)
+		events[epoch] = e.ID()
+	}
+	head := epochStartBlock(lastEpoch+1) - 1
+	gdb.SetBlockEpochState(blockEpochState(lastEpoch, head))
+	gdb.FlushBlockEpochState()
+	gdb.SetHighestLamport(1)
+	gdb.FlushHighestLamport()
+	return events
+}
+
+func TestHealGossipDbRollback(t *testing.T) {
+	logger.SetTestMode(t)
+
+	const (
+		firstEpoch = 2
+		lastEpoch  = 6
+	)
+	head := epochStartBlock(lastEpoch+1) - 1
+
+	for _, test := range []struct {
+		name            string
+		rollback        idx.Block // number of the blocks to roll back, zero for healing without a rollback
+		lastCarmenBlock idx.Block
+		expectEpoch     idx.Epoch
+	}{
+		{"no rollback", 0, head, lastEpoch},
+		{"within the epoch", 1, head, lastEpoch},
+		{"several epochs", 7, head, blockEpoch(head - 7)},
+		{"to the first epoch", head - epochStartBlock(firstEpoch) - 1, head, firstEpoch},
+		{"state is behind the rollback", 7, epochStartBlock(firstEpoch + 1), firstEpoch + 1},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			producer := &DummyScopedProducer{integration.GetRawDbProducer(t.TempDir(), integration.DBCacheConfig{
+				Cache:   16 * opt.MiB,
+				Fdlimit: 64,
+			})}
+			defer producer.Close()
+			cfg := gossip.MemTestStoreConfig(t.TempDir())
+			events := makeChainStore(t, producer, cfg, firstEpoch, lastEpoch)
+
+			if test.rollback != 0 {
+				// debug_setHead schedules the rollback to the checkpoint of the target block epoch
+				gdb, err := gossip.NewStore(producer, cfg)
+				require.NoError(err)
+				gdb.SetRollbackTarget(blockEpoch(head - test.rollback))
+				require.NoError(gdb.Close())
+			}
+
+			es, lastBlock, err := healGossipDb(producer, cfg, test.lastCarmenBlock)
+			require.NoError(err)
+			require.Equal(test.expectEpoch, es.Epoch)
+			require.Equal(epochStartBlock(test.expectEpoch), lastBlock)
+			require.LessOrEqual(lastBlock, head-test.rollback)
+			require.LessOrEqual(lastBlock, test.lastCarmenBlock)
+
+			gdb, err := gossip.NewStore(producer, cfg)
+			require.NoError(err)
+			defer gdb.Close()
+
+			// the store head and the state head are reverted to the checkpoint
+			require.Equal(test.expectEpoch, gdb.GetEpoch())
+			require.Equal(lastBlock, gdb.GetLatestBlockIndex())
+			require.Equal(stateRoot(lastBlock), gdb.GetBlockState().FinalizedStateRoot)
+			require.Nil(gdb.GetRollbackTarget())
+
+			// the events of the reverted epochs are removed
+			for epoch, id := range events {
+				require.Equal(epoch < test.expectEpoch, gdb.HasEvent(id), epoch)
+			}
+		})
+	}
+}
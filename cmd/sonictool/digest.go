@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+)
+
+var DigestStateFlag = cli.BoolFlag{
+	Name:  "state",
+	Usage: "Include the live world state into the digest",
+}
+
+// datadirDigest computes a digest of the canonical chain data of the datadir, which doesn't depend
+// on the physical layout of the databases, so the datadirs of two nodes may be compared after
+// a migration or a restore.
+func datadirDigest(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+	dbs, err := integration.GetDbProducer(filepath.Join(dataDir, "chaindata"), integration.DBCacheConfig{
+		Cache:   cacheRatio.U64(480 * opt.MiB),
+		Fdlimit: 100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make DB producer: %v", err)
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cachescale.Identity)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	total := sha256.New()
+	epochs, err := epochsDigest(cancelCtx, gdb)
+	if err != nil {
+		return err
+	}
+	total.Write(epochs.Bytes())
+	fmt.Printf("- Epochs digest: %v\n", epochs.Hex())
+
+	blocks, err := blocksDigest(cancelCtx, gdb)
+	if err != nil {
+		return err
+	}
+	total.Write(blocks.Bytes())
+	fmt.Printf("- Blocks digest: %v\n", blocks.Hex())
+
+	if ctx.Bool(DigestStateFlag.Name) {
+		log.Info("Hashing live world state")
+		h := sha256.New()
+		if err := gdb.EvmStore().ExportLiveWorldState(cancelCtx, h); err != nil {
+			return fmt.Errorf("failed to export the live state: %w", err)
+		}
+		state := common.BytesToHash(h.Sum(nil))
+		total.Write(state.Bytes())
+		fmt.Printf("- State digest: %v\n", state.Hex())
+	}
+
+	fmt.Printf("Datadir digest: %v\n", common.BytesToHash(total.Sum(nil)).Hex())
+	return nil
+}
+
+// epochsDigest hashes the full epoch records in ascending order.
+func epochsDigest(ctx context.Context, gdb *gossip.Store) (common.Hash, error) {
+	h := sha256.New()
+	to := gdb.GetEpoch()
+	log.Info("Hashing epochs", "to", to)
+	for i := idx.Epoch(1); i <= to; i++ {
+		er := gdb.GetFullEpochRecord(i)
+		if er == nil {
+			continue
+		}
+		if err := writeRLP(h, ier.LlrIdxFullEpochRecord{
+			LlrFullEpochRecord: *er,
+			Idx:                i,
+		}); err != nil {
+			return common.Hash{}, err
+		}
+		if err := ctx.Err(); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return common.BytesToHash(h.Sum(nil)), nil
+}
+
+// blocksDigest hashes the full block records, including the transactions and the receipts, in ascending order.
+func blocksDigest(ctx context.Context, gdb *gossip.Store) (common.Hash, error) {
+	h := sha256.New()
+	to := gdb.GetLatestBlockIndex()
+	log.Info("Hashing blocks", "to", to)
+	for i := idx.Block(1); i <= to; i++ {
+		br := gdb.GetFullBlockRecord(i)
+		if br == nil {
+			continue
+		}
+		if i%200000 == 0 {
+			log.Info("Hashing blocks", "last", i)
+		}
+		if err := writeRLP(h, ibr.LlrIdxFullBlockRecord{
+			LlrFullBlockRecord: *br,
+			Idx:                i,
+		}); err != nil {
+			return common.Hash{}, err
+		}
+		if err := ctx.Err(); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return common.BytesToHash(h.Sum(nil)), nil
+}
+
+func writeRLP(h hash.Hash, v interface{}) error {
+	b, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return err
+	}
+	_, err = h.Write(b)
+	return err
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
 	"github.com/Fantom-foundation/go-opera/cmd/sonictool/genesis"
 	"github.com/Fantom-foundation/go-opera/config/flags"
 	"github.com/Fantom-foundation/go-opera/integration/makefakegenesis"
+	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
 	futils "github.com/Fantom-foundation/go-opera/utils"
 	"github.com/Fantom-foundation/go-opera/utils/memory"
@@ -14,8 +16,11 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"gopkg.in/urfave/cli.v1"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 )
 
 var (
@@ -38,6 +43,39 @@ func gfileGenesisImport(ctx *cli.Context) error {
 	if dataDir == "" {
 		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
 	}
+	return importGenesisFile(ctx, dataDir, ctx.Args().First())
+}
+
+func gfileGenesisDownload(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument - the URL of the signed genesis manifest")
+	}
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return err
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	manifest, err := genesis.FetchManifest(cancelCtx, http.DefaultClient, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	log.Info("Downloading genesis file", "url", manifest.URL, "network", manifest.NetworkName, "size", manifest.Size)
+	fileName, err := genesis.Download(cancelCtx, http.DefaultClient, manifest, dataDir)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fileName)
+	log.Info("Genesis file verified", "sha256", manifest.SHA256.Hex())
+	return importGenesisFile(ctx, dataDir, fileName)
+}
+
+func importGenesisFile(ctx *cli.Context, dataDir string, fileName string) error {
 	validatorMode, err := isValidatorModeSet(ctx)
 	if err != nil {
 		return err
@@ -47,7 +85,7 @@ func gfileGenesisImport(ctx *cli.Context) error {
 		return err
 	}
 
-	genesisReader, err := os.Open(ctx.Args().First())
+	genesisReader, err := os.Open(fileName)
 	if err != nil {
 		return fmt.Errorf("failed to open the genesis file: %w", err)
 	}
@@ -121,7 +159,12 @@ func fakeGenesisImport(ctx *cli.Context) error {
 		return err
 	}
 
-	genesisStore := makefakegenesis.FakeGenesisStore(idx.Validator(validatorsNumber), futils.ToFtm(1000000000), futils.ToFtm(5000000))
+	rules, err := loadSpecRules(ctx, opera.FakeNetRules())
+	if err != nil {
+		return err
+	}
+
+	genesisStore := makefakegenesis.FakeGenesisStoreWithRules(idx.Validator(validatorsNumber), futils.ToFtm(1000000000), futils.ToFtm(5000000), rules)
 	defer genesisStore.Close()
 	return genesis.ImportGenesisStore(genesisStore, dataDir, validatorMode, cacheRatio)
 }
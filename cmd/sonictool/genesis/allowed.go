@@ -1,6 +1,7 @@
 package genesis
 
 import (
+	"github.com/Fantom-foundation/go-opera/config"
 	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/opera/genesis"
 	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
@@ -20,13 +21,13 @@ var (
 	}
 
 	mainnetHeader = genesis.Header{
-		GenesisID:   hash.HexToHash("0x4a53c5445584b3bfc20dbfb2ec18ae20037c716f3ba2d9e1da768a9deca17cb4"),
+		GenesisID:   config.MainnetGenesisID,
 		NetworkID:   opera.MainNetworkID,
 		NetworkName: "main",
 	}
 
 	testnetHeader = genesis.Header{
-		GenesisID:   hash.HexToHash("0xc4a5fc96e575a16a9a0c7349d44dc4d0f602a54e0a8543360c2fee4c3937b49e"),
+		GenesisID:   config.TestnetGenesisID,
 		NetworkID:   opera.TestNetworkID,
 		NetworkName: "test",
 	}
@@ -0,0 +1,154 @@
+package genesis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// maxManifestSize limits the size of a genesis manifest.
+const maxManifestSize = 64 * 1024
+
+// Manifest describes a genesis file published for download.
+type Manifest struct {
+	// URL of the genesis file
+	URL         string      `json:"url"`
+	SHA256      common.Hash `json:"sha256"`
+	Size        uint64      `json:"size"`
+	NetworkName string      `json:"networkName"`
+}
+
+// SignedManifest is a manifest signed by a trusted genesis signer.
+// The signature is made over keccak256 of the exact manifest bytes.
+type SignedManifest struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// VerifyManifest checks the manifest signature against the trusted genesis signers and decodes the manifest.
+func VerifyManifest(signed SignedManifest) (*Manifest, error) {
+	signature := common.CopyBytes(signed.Signature)
+	if err := CheckGenesisSignature(crypto.Keccak256(signed.Manifest), signature); err != nil {
+		return nil, fmt.Errorf("genesis manifest isn't trusted: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(signed.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode genesis manifest: %w", err)
+	}
+	if err := checkHTTPS(manifest.URL); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func checkHTTPS(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("URL %q must use https", rawURL)
+	}
+	return nil
+}
+
+func httpGet(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	if err := checkHTTPS(rawURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// FetchManifest downloads the signed manifest and verifies it.
+func FetchManifest(ctx context.Context, client *http.Client, manifestURL string) (*Manifest, error) {
+	resp, err := httpGet(ctx, client, manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var signed SignedManifest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&signed); err != nil {
+		return nil, fmt.Errorf("failed to decode signed genesis manifest: %w", err)
+	}
+	return VerifyManifest(signed)
+}
+
+// Download downloads the genesis file of the manifest into the directory and verifies its size and hash.
+// Returns the path of the downloaded file.
+func Download(ctx context.Context, client *http.Client, manifest *Manifest, dir string) (string, error) {
+	resp, err := httpGet(ctx, client, manifest.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	out, err := os.CreateTemp(dir, "genesis-download-*.g")
+	if err != nil {
+		return "", err
+	}
+	fileName := out.Name()
+	ok := false
+	defer func() {
+		out.Close()
+		if !ok {
+			_ = os.Remove(fileName)
+		}
+	}()
+
+	h := sha256.New()
+	progress := &progressWriter{total: manifest.Size, last: time.Now()}
+	// read at most one byte more than expected to detect an oversized file
+	n, err := io.Copy(io.MultiWriter(out, h, progress), io.LimitReader(resp.Body, int64(manifest.Size)+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download genesis file: %w", err)
+	}
+	if uint64(n) != manifest.Size {
+		return "", fmt.Errorf("genesis file size mismatch: got %d, expected %d", n, manifest.Size)
+	}
+	if got := common.BytesToHash(h.Sum(nil)); got != manifest.SHA256 {
+		return "", fmt.Errorf("genesis file hash mismatch: got %s, expected %s", got.Hex(), manifest.SHA256.Hex())
+	}
+	if err := out.Sync(); err != nil {
+		return "", err
+	}
+	ok = true
+	return fileName, nil
+}
+
+type progressWriter struct {
+	written uint64
+	total   uint64
+	last    time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += uint64(len(p))
+	if time.Since(w.last) >= 8*time.Second {
+		log.Info("Downloading genesis file", "downloaded", w.written, "total", w.total)
+		w.last = time.Now()
+	}
+	return len(p), nil
+}
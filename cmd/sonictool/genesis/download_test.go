@@ -0,0 +1,86 @@
+package genesis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	defer func(signers []common.Address) { allowedGenesisSigners = signers }(allowedGenesisSigners)
+	allowedGenesisSigners = append(allowedGenesisSigners, crypto.PubkeyToAddress(key.PublicKey))
+
+	content := []byte("genesis file content")
+	var signed SignedManifest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/genesis.g", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	})
+	mux.HandleFunc("/manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(signed)
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	sign := func(m Manifest) {
+		raw, err := json.Marshal(m)
+		require.NoError(err)
+		sig, err := crypto.Sign(crypto.Keccak256(raw), key)
+		require.NoError(err)
+		signed = SignedManifest{Manifest: raw, Signature: sig}
+	}
+	manifest := Manifest{
+		URL:         server.URL + "/genesis.g",
+		SHA256:      sha256.Sum256(content),
+		Size:        uint64(len(content)),
+		NetworkName: "test",
+	}
+	sign(manifest)
+
+	ctx := context.Background()
+	got, err := FetchManifest(ctx, server.Client(), server.URL+"/manifest.json")
+	require.NoError(err)
+	require.Equal(manifest, *got)
+
+	fileName, err := Download(ctx, server.Client(), got, t.TempDir())
+	require.NoError(err)
+	b, err := os.ReadFile(fileName)
+	require.NoError(err)
+	require.Equal(content, b)
+
+	// tampered manifest
+	tampered, err := json.Marshal(Manifest{URL: manifest.URL, SHA256: manifest.SHA256, Size: manifest.Size + 1, NetworkName: manifest.NetworkName})
+	require.NoError(err)
+	signed.Manifest = tampered
+	_, err = FetchManifest(ctx, server.Client(), server.URL+"/manifest.json")
+	require.Error(err)
+
+	// hash mismatch
+	dir := t.TempDir()
+	wrong := manifest
+	wrong.SHA256 = common.Hash{1}
+	_, err = Download(ctx, server.Client(), &wrong, dir)
+	require.ErrorContains(err, "hash mismatch")
+	files, err := os.ReadDir(dir)
+	require.NoError(err)
+	require.Empty(files)
+
+	// plain http isn't allowed
+	wrong = manifest
+	wrong.URL = "http://example.org/genesis.g"
+	sign(wrong)
+	_, err = FetchManifest(ctx, server.Client(), server.URL+"/manifest.json")
+	require.ErrorContains(err, "must use https")
+}
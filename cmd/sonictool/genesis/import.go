@@ -3,6 +3,7 @@ package genesis
 import (
 	"fmt"
 	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/go-opera/opera/genesis"
 	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
 	"github.com/Fantom-foundation/lachesis-base/abft"
@@ -70,6 +71,9 @@ func ImportGenesisStore(genesisStore *genesisstore.Store, dataDir string, valida
 		return err
 	}
 	setGenesisComplete(chaindataDir)
+	if err := integration.SetDatadirNetwork(dataDir, gdb.GetRules().Name); err != nil {
+		return fmt.Errorf("failed to write the datadir manifest: %w", err)
+	}
 	log.Info("Successfully imported genesis file")
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
 	"github.com/Fantom-foundation/go-opera/config"
 	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/log"
 	"gopkg.in/urfave/cli.v1"
@@ -89,6 +90,11 @@ func heal(ctx *cli.Context) error {
 		return fmt.Errorf("failed to re-create carmen live state from archive; %w", err)
 	}
 
+	// the databases are consistent again, the node may start without the integrity check
+	if err := integration.SetClean(chaindataDir); err != nil {
+		return fmt.Errorf("failed to clear the datadir dirty flag; %w", err)
+	}
+
 	log.Info("Healing finished")
 	return nil
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/load"
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	LoadKeyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "Hex private key of the account funding the load",
+	}
+	LoadFakeKeyFlag = cli.UintFlag{
+		Name:  "fakekey",
+		Usage: "Use the key of the N-th fakenet validator to fund the load",
+	}
+	LoadRateFlag = cli.Float64Flag{
+		Name:  "rate",
+		Usage: "Number of transactions sent per second",
+		Value: 100,
+	}
+	LoadDurationFlag = cli.DurationFlag{
+		Name:  "duration",
+		Usage: "Duration of the load",
+		Value: 5 * time.Minute,
+	}
+	LoadAccountsFlag = cli.IntFlag{
+		Name:  "accounts",
+		Usage: "Number of the sending accounts",
+		Value: 100,
+	}
+	LoadMixFlag = cli.StringFlag{
+		Name:  "mix",
+		Usage: "Weights of transfers, token calls and storage writes, comma separated",
+		Value: "70,20,10",
+	}
+	LoadStorageSlotsFlag = cli.IntFlag{
+		Name:  "slots",
+		Usage: "Number of new storage slots written by a storage writer transaction",
+		Value: 10,
+	}
+)
+
+func parseMix(s string) (load.Mix, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return load.Mix{}, fmt.Errorf("mix must have 3 comma separated weights, got %q", s)
+	}
+	var weights [3]uint
+	for i, p := range parts {
+		w, err := strconv.ParseUint(strings.TrimSpace(p), 10, 32)
+		if err != nil {
+			return load.Mix{}, fmt.Errorf("invalid mix weight %q: %w", p, err)
+		}
+		weights[i] = uint(w)
+	}
+	if weights[0]+weights[1]+weights[2] == 0 {
+		return load.Mix{}, fmt.Errorf("mix weights must not be all zero")
+	}
+	return load.Mix{Transfers: weights[0], TokenCalls: weights[1], StorageWrites: weights[2]}, nil
+}
+
+func generateLoad(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		return fmt.Errorf("this command requires an argument - the RPC endpoint of the node")
+	}
+	var key *ecdsa.PrivateKey
+	if ctx.IsSet(LoadKeyFlag.Name) {
+		var err error
+		if key, err = crypto.HexToECDSA(strings.TrimPrefix(ctx.String(LoadKeyFlag.Name), "0x")); err != nil {
+			return fmt.Errorf("invalid --%s: %w", LoadKeyFlag.Name, err)
+		}
+	} else if n := ctx.Uint(LoadFakeKeyFlag.Name); n != 0 {
+		key = evmcore.FakeKey(uint32(n))
+	} else {
+		return fmt.Errorf("either --%s or --%s must be set", LoadKeyFlag.Name, LoadFakeKeyFlag.Name)
+	}
+	mix, err := parseMix(ctx.String(LoadMixFlag.Name))
+	if err != nil {
+		return err
+	}
+	cfg := load.Config{
+		Rate:         ctx.Float64(LoadRateFlag.Name),
+		Duration:     ctx.Duration(LoadDurationFlag.Name),
+		Accounts:     ctx.Int(LoadAccountsFlag.Name),
+		Mix:          mix,
+		StorageSlots: ctx.Int(LoadStorageSlotsFlag.Name),
+		ReportPeriod: 10 * time.Second,
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid load config: %w", err)
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client, err := rpc.DialContext(cancelCtx, ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", ctx.Args().First(), err)
+	}
+	defer client.Close()
+
+	gen := load.NewGenerator(client, key, cfg)
+	if err := gen.Prepare(cancelCtx); err != nil {
+		return err
+	}
+	report, err := gen.Run(cancelCtx)
+	if err != nil {
+		return err
+	}
+	log.Info("Load finished", "elapsed", report.Elapsed.Round(time.Second), "sent", report.Sent, "failed", report.Failed,
+		"blocks", report.Blocks, "included", report.Included, "tps", fmt.Sprintf("%.1f", report.TPS()),
+		"utilization", fmt.Sprintf("%.1f%%", 100*report.Utilization()), "pending", report.PoolPend, "queued", report.PoolQueue)
+	return nil
+}
@@ -0,0 +1,35 @@
+package load
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// tokenCode is a minimal token with unchecked balances, which accepts transfer(address,uint256).
+	// The selector isn't checked: balance[caller] -= amount; balance[to] += amount.
+	tokenCode = common.FromHex("600435602435803354033355815401905500")
+
+	// storageWriterCode writes the number of slots given in the first calldata word.
+	// The slots are new in every block: for i < n { sstore(number<<32 + i, gas) }.
+	storageWriterCode = common.FromHex("60003560005b81811015601b575a814360201b0155600101600556" + "5b00")
+)
+
+// deployCode returns init code, which deploys the runtime code.
+func deployCode(runtime []byte) []byte {
+	// PUSH1 len DUP1 PUSH1 0x0b PUSH1 0 CODECOPY PUSH1 0 RETURN
+	return append([]byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, runtime...)
+}
+
+var transferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+func tokenTransferData(to common.Address, amount *big.Int) []byte {
+	data := append([]byte{}, transferSelector...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
+
+func storageWriterData(slots int) []byte {
+	return common.LeftPadBytes(big.NewInt(int64(slots)).Bytes(), 32)
+}
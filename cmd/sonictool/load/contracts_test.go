@@ -0,0 +1,42 @@
+package load
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenContract(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &runtime.Config{Origin: common.Address{1}}
+	code, addr, _, err := runtime.Create(deployCode(tokenCode), cfg)
+	require.NoError(err)
+	require.Equal(tokenCode, code)
+
+	to := common.Address{2}
+	_, _, err = runtime.Call(addr, tokenTransferData(to, big.NewInt(5)), cfg)
+	require.NoError(err)
+	require.Equal(common.BigToHash(big.NewInt(5)), cfg.State.GetState(addr, common.BytesToHash(to.Bytes())))
+	// balances are unchecked, so the sender's balance underflows
+	underflow := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 256), big.NewInt(5))
+	require.Equal(common.BigToHash(underflow), cfg.State.GetState(addr, common.BytesToHash(cfg.Origin.Bytes())))
+}
+
+func TestStorageWriterContract(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &runtime.Config{Origin: common.Address{1}, BlockNumber: big.NewInt(7), GasLimit: 10_000_000}
+	_, addr, _, err := runtime.Create(deployCode(storageWriterCode), cfg)
+	require.NoError(err)
+
+	_, _, err = runtime.Call(addr, storageWriterData(3), cfg)
+	require.NoError(err)
+	for i := int64(0); i < 4; i++ {
+		slot := common.BigToHash(big.NewInt(7<<32 + i))
+		require.Equal(i < 3, cfg.State.GetState(addr, slot) != common.Hash{}, i)
+	}
+}
@@ -0,0 +1,358 @@
+// Package load generates synthetic transaction load against a node for capacity testing of devnets and testnets.
+package load
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MaxRate is the highest supported rate of the load, the sending interval mustn't be shorter than a microsecond.
+const MaxRate = 1e6
+
+// Mix is the relative weights of the transaction kinds.
+type Mix struct {
+	Transfers     uint
+	TokenCalls    uint
+	StorageWrites uint
+}
+
+// Config of the load generator.
+type Config struct {
+	// Rate is the number of transactions sent per second
+	Rate     float64
+	Duration time.Duration
+	// Accounts is the number of the sending accounts, derived deterministically from the funder key
+	Accounts int
+	Mix      Mix
+	// StorageSlots is the number of new slots written by a storage writer transaction
+	StorageSlots int
+	// ReportPeriod is the period of the progress reports
+	ReportPeriod time.Duration
+}
+
+// Validate checks the config.
+func (c Config) Validate() error {
+	if !(c.Rate > 0 && c.Rate <= MaxRate) {
+		return fmt.Errorf("rate must be in range (0, %d], got %v", int(MaxRate), c.Rate)
+	}
+	if c.Accounts <= 0 {
+		return errors.New("number of accounts must be positive")
+	}
+	if c.Mix.Transfers+c.Mix.TokenCalls+c.Mix.StorageWrites == 0 {
+		return errors.New("empty transactions mix")
+	}
+	if c.ReportPeriod <= 0 {
+		return errors.New("report period must be positive")
+	}
+	return nil
+}
+
+// Report of the load generator run.
+type Report struct {
+	Sent   uint64
+	Failed uint64
+	Blocks uint64
+	// Included is the number of the generator's transactions included into blocks
+	Included  uint64
+	GasUsed   uint64
+	GasLimit  uint64
+	Elapsed   time.Duration
+	PoolPend  uint64
+	PoolQueue uint64
+}
+
+// TPS returns the number of the generator's transactions included into blocks per second.
+func (r Report) TPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Included) / r.Elapsed.Seconds()
+}
+
+// Utilization returns the ratio of the gas used by blocks to the blocks gas limit.
+func (r Report) Utilization() float64 {
+	if r.GasLimit == 0 {
+		return 0
+	}
+	return float64(r.GasUsed) / float64(r.GasLimit)
+}
+
+type account struct {
+	key   *ecdsa.PrivateKey
+	addr  common.Address
+	nonce uint64
+}
+
+type rpcBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+type poolStatus struct {
+	Pending hexutil.Uint64 `json:"pending"`
+	Queued  hexutil.Uint64 `json:"queued"`
+}
+
+// Generator sends the synthetic load to a node.
+type Generator struct {
+	cfg    Config
+	rpc    *rpc.Client
+	client *ethclient.Client
+	signer types.Signer
+	price  *big.Int
+	rand   *rand.Rand
+
+	funder   *account
+	accounts []*account
+	token    common.Address
+	writer   common.Address
+
+	// sent are the hashes of the load transactions, which aren't included into blocks yet
+	sent map[common.Hash]struct{}
+}
+
+// NewGenerator returns a generator, which funds the sending accounts from the funder.
+func NewGenerator(client *rpc.Client, funder *ecdsa.PrivateKey, cfg Config) *Generator {
+	return &Generator{
+		cfg:    cfg,
+		rpc:    client,
+		client: ethclient.NewClient(client),
+		rand:   rand.New(rand.NewSource(1)),
+		funder: newAccount(funder),
+		sent:   make(map[common.Hash]struct{}),
+	}
+}
+
+func newAccount(key *ecdsa.PrivateKey) *account {
+	return &account{
+		key:  key,
+		addr: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// deriveKey returns i-th account key, derived from the funder key.
+func deriveKey(funder *ecdsa.PrivateKey, i int) *ecdsa.PrivateKey {
+	seed := crypto.Keccak256(crypto.FromECDSA(funder), big.NewInt(int64(i)).Bytes())
+	key, err := crypto.ToECDSA(seed)
+	if err != nil {
+		// the probability of an invalid key is negligible
+		panic(err)
+	}
+	return key
+}
+
+func (g *Generator) send(ctx context.Context, from *account, to *common.Address, value *big.Int, gas uint64, data []byte) (*types.Transaction, error) {
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    from.nonce,
+		GasPrice: g.price,
+		Gas:      gas,
+		To:       to,
+		Value:    value,
+		Data:     data,
+	}), g.signer, from.key)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.client.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	from.nonce++
+	return tx, nil
+}
+
+func (g *Generator) waitReceipt(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	for {
+		r, err := g.client.TransactionReceipt(ctx, tx.Hash())
+		if err == nil {
+			return r, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// Prepare funds the sending accounts and deploys the contracts.
+func (g *Generator) Prepare(ctx context.Context) error {
+	chainID, err := g.client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	g.signer = types.NewEIP155Signer(chainID)
+	price, err := g.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+	// overpay to stay above the base fee growing under the load
+	g.price = new(big.Int).Mul(price, big.NewInt(2))
+	if g.funder.nonce, err = g.client.PendingNonceAt(ctx, g.funder.addr); err != nil {
+		return err
+	}
+
+	deploy := func(runtime []byte) (common.Address, error) {
+		tx, err := g.send(ctx, g.funder, nil, new(big.Int), 200000, deployCode(runtime))
+		if err != nil {
+			return common.Address{}, err
+		}
+		r, err := g.waitReceipt(ctx, tx)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if r.Status != types.ReceiptStatusSuccessful {
+			return common.Address{}, errors.New("contract deployment failed")
+		}
+		return r.ContractAddress, nil
+	}
+	if g.token, err = deploy(tokenCode); err != nil {
+		return fmt.Errorf("failed to deploy token: %w", err)
+	}
+	if g.writer, err = deploy(storageWriterCode); err != nil {
+		return fmt.Errorf("failed to deploy storage writer: %w", err)
+	}
+
+	// fund each account for an hour of transactions at the gas price
+	funding := new(big.Int).Mul(g.price, big.NewInt(int64(3600*(100000+20000*g.cfg.StorageSlots))))
+	var last *types.Transaction
+	for i := 0; i < g.cfg.Accounts; i++ {
+		acc := newAccount(deriveKey(g.funder.key, i))
+		if acc.nonce, err = g.client.PendingNonceAt(ctx, acc.addr); err != nil {
+			return err
+		}
+		if last, err = g.send(ctx, g.funder, &acc.addr, funding, 21000, nil); err != nil {
+			return fmt.Errorf("failed to fund account %s: %w", acc.addr.String(), err)
+		}
+		g.accounts = append(g.accounts, acc)
+	}
+	if last != nil {
+		if _, err := g.waitReceipt(ctx, last); err != nil {
+			return err
+		}
+	}
+	log.Info("Load generator prepared", "accounts", len(g.accounts), "token", g.token, "writer", g.writer)
+	return nil
+}
+
+// next sends the next transaction of the mix.
+func (g *Generator) next(ctx context.Context, i uint64) error {
+	from := g.accounts[i%uint64(len(g.accounts))]
+	to := g.accounts[(i+1)%uint64(len(g.accounts))].addr
+	mix := g.cfg.Mix
+	n := uint(g.rand.Intn(int(mix.Transfers + mix.TokenCalls + mix.StorageWrites)))
+	var (
+		tx  *types.Transaction
+		err error
+	)
+	switch {
+	case n < mix.Transfers:
+		tx, err = g.send(ctx, from, &to, big.NewInt(1), 21000, nil)
+	case n < mix.Transfers+mix.TokenCalls:
+		tx, err = g.send(ctx, from, &g.token, new(big.Int), 60000, tokenTransferData(to, big.NewInt(1)))
+	default:
+		tx, err = g.send(ctx, from, &g.writer, new(big.Int), uint64(50000+25000*g.cfg.StorageSlots), storageWriterData(g.cfg.StorageSlots))
+	}
+	if err != nil {
+		return err
+	}
+	g.sent[tx.Hash()] = struct{}{}
+	return nil
+}
+
+// observe accounts the blocks after the given one.
+func (g *Generator) observe(ctx context.Context, report *Report, last *uint64) error {
+	for {
+		var b *rpcBlock
+		if err := g.rpc.CallContext(ctx, &b, "eth_getBlockByNumber", hexutil.Uint64(*last+1), false); err != nil {
+			return err
+		}
+		if b == nil {
+			break
+		}
+		*last = uint64(b.Number)
+		report.Blocks++
+		// the blocks may include the transactions of other senders
+		for _, h := range b.Transactions {
+			if _, ok := g.sent[h]; ok {
+				delete(g.sent, h)
+				report.Included++
+			}
+		}
+		report.GasUsed += uint64(b.GasUsed)
+		report.GasLimit += uint64(b.GasLimit)
+	}
+	var status poolStatus
+	if err := g.rpc.CallContext(ctx, &status, "txpool_status"); err == nil {
+		report.PoolPend, report.PoolQueue = uint64(status.Pending), uint64(status.Queued)
+	}
+	return nil
+}
+
+// Run sends the load until the duration elapses or the context is cancelled.
+func (g *Generator) Run(ctx context.Context) (Report, error) {
+	if err := g.cfg.Validate(); err != nil {
+		return Report{}, err
+	}
+	if len(g.accounts) == 0 {
+		return Report{}, errors.New("no accounts prepared")
+	}
+	last, err := g.client.BlockNumber(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{}
+	start := time.Now()
+	deadline := time.After(g.cfg.Duration)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / g.cfg.Rate))
+	defer ticker.Stop()
+	reports := time.NewTicker(g.cfg.ReportPeriod)
+	defer reports.Stop()
+
+	for i := uint64(0); ; {
+		select {
+		case <-ctx.Done():
+			report.Elapsed = time.Since(start)
+			return report, nil
+		case <-deadline:
+			report.Elapsed = time.Since(start)
+			return report, g.observe(ctx, &report, &last)
+		case <-reports.C:
+			if err := g.observe(ctx, &report, &last); err != nil {
+				log.Warn("Failed to observe blocks", "err", err)
+			}
+			report.Elapsed = time.Since(start)
+			log.Info("Load progress", "sent", report.Sent, "failed", report.Failed, "blocks", report.Blocks,
+				"tps", fmt.Sprintf("%.1f", report.TPS()), "utilization", fmt.Sprintf("%.1f%%", 100*report.Utilization()),
+				"pending", report.PoolPend, "queued", report.PoolQueue)
+		case <-ticker.C:
+			if err := g.next(ctx, i); err != nil {
+				report.Failed++
+				log.Debug("Failed to send transaction", "err", err)
+				// resync the nonce after a rejected transaction
+				from := g.accounts[i%uint64(len(g.accounts))]
+				if nonce, err := g.client.PendingNonceAt(ctx, from.addr); err == nil {
+					from.nonce = nonce
+				}
+			} else {
+				report.Sent++
+			}
+			i++
+		}
+	}
+}
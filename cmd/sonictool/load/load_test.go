@@ -0,0 +1,31 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Rate:         100,
+		Duration:     time.Minute,
+		Accounts:     10,
+		Mix:          Mix{Transfers: 1},
+		ReportPeriod: time.Second,
+	}
+	require.NoError(t, valid.Validate())
+
+	for name, change := range map[string]func(c *Config){
+		"zero rate":      func(c *Config) { c.Rate = 0 },
+		"too high rate":  func(c *Config) { c.Rate = 2e9 },
+		"no accounts":    func(c *Config) { c.Accounts = 0 },
+		"empty mix":      func(c *Config) { c.Mix = Mix{} },
+		"no report time": func(c *Config) { c.ReportPeriod = 0 },
+	} {
+		cfg := valid
+		change(&cfg)
+		require.Error(t, cfg.Validate(), name)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+)
+
+var (
+	LogIndexerNodeFlag = cli.StringFlag{
+		Name:  "node",
+		Usage: "WebSocket or IPC endpoint of the node, the blocks of which are indexed",
+	}
+	LogIndexerAddrFlag = cli.StringFlag{
+		Name:  "addr",
+		Usage: "HTTP listening address of the log indexer API",
+		Value: "127.0.0.1:18547",
+	}
+)
+
+var logIndexerLastBlockKey = []byte("last")
+
+// blockReceiptLogs is the part of the eth_getBlockReceipts result consumed by the log indexer
+type blockReceiptLogs struct {
+	Logs []*types.Log `json:"logs"`
+}
+
+// runLogIndexer follows the blocks of a node and serves the logs index of them,
+// so the getLogs traffic may be isolated from the node process (see --logindex.remote).
+func runLogIndexer(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	endpoint := ctx.String(LogIndexerNodeFlag.Name)
+	if endpoint == "" {
+		return fmt.Errorf("--%s need to be set", LogIndexerNodeFlag.Name)
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+	producer := integration.GetRawDbProducer(filepath.Join(dataDir, "logindex"), integration.DBCacheConfig{
+		Cache:   cacheRatio.U64(480 * opt.MiB),
+		Fdlimit: 100,
+	})
+	db, err := producer.OpenDB("logindex")
+	if err != nil {
+		return fmt.Errorf("failed to open the logs index: %w", err)
+	}
+	defer db.Close()
+	index := topicsdb.NewWithThreadPool(db)
+	defer index.Close()
+	progress := table.New(db, []byte("_"))
+
+	client, err := rpc.DialContext(cancelCtx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", endpoint, err)
+	}
+	defer client.Close()
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	if err := srv.RegisterName(topicsdb.APINamespace, topicsdb.NewAPI(index)); err != nil {
+		return err
+	}
+	httpSrv := &http.Server{
+		Addr:              ctx.String(LogIndexerAddrFlag.Name),
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpSrv.ListenAndServe()
+	}()
+	defer httpSrv.Close()
+	log.Info("Log indexer API started", "addr", httpSrv.Addr)
+
+	heads := make(chan *struct {
+		Number hexutil.Uint64 `json:"number"`
+	}, 16)
+	sub, err := client.EthSubscribe(cancelCtx, heads, "newHeads")
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to the new blocks: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	// catch up with the current head before waiting for the new blocks
+	var head hexutil.Uint64
+	if err := client.CallContext(cancelCtx, &head, "eth_blockNumber"); err != nil {
+		return err
+	}
+	for {
+		if err := indexLogsUpTo(cancelCtx, client, index, progress, idx.Block(head)); err != nil {
+			return err
+		}
+		select {
+		case h := <-heads:
+			head = h.Number
+		case err := <-sub.Err():
+			return fmt.Errorf("new blocks subscription failed: %w", err)
+		case err := <-serveErr:
+			return err
+		case <-cancelCtx.Done():
+			return nil
+		}
+	}
+}
+
+// indexLogsUpTo indexes the logs of the blocks after the last indexed one up to the given block.
+func indexLogsUpTo(ctx context.Context, client *rpc.Client, index topicsdb.Index, progress kvdb.Store, to idx.Block) error {
+	last := idx.Block(0)
+	if b, err := progress.Get(logIndexerLastBlockKey); err != nil {
+		return err
+	} else if b != nil {
+		last = idx.BytesToBlock(b)
+	}
+	for n := last + 1; n <= to; n++ {
+		var receipts []blockReceiptLogs
+		err := client.CallContext(ctx, &receipts, "eth_getBlockReceipts", hexutil.Uint64(n))
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return fmt.Errorf("failed to get the receipts of block %d: %w", n, err)
+		}
+		for _, r := range receipts {
+			if err := index.Push(r.Logs...); err != nil {
+				return fmt.Errorf("failed to index the logs of block %d: %w", n, err)
+			}
+		}
+		if err := progress.Put(logIndexerLastBlockKey, n.Bytes()); err != nil {
+			return err
+		}
+		if n%10000 == 0 {
+			log.Info("Indexing logs", "last", n, "head", to)
+		}
+	}
+	return nil
+}
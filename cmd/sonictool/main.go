@@ -2,10 +2,8 @@ package main
 
 import (
 	"fmt"
-	"github.com/Fantom-foundation/go-opera/config"
 	"github.com/Fantom-foundation/go-opera/config/flags"
-	_ "github.com/Fantom-foundation/go-opera/version"
-	"github.com/ethereum/go-ethereum/params"
+	"github.com/Fantom-foundation/go-opera/version"
 	"gopkg.in/urfave/cli.v1"
 	"os"
 	"sort"
@@ -15,7 +13,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "sonictool"
 	app.Usage = "the Sonic management tool"
-	app.Version = params.VersionWithCommit(config.GitCommit, config.GitDate)
+	app.Version = version.WithCommit()
 	app.Flags = []cli.Flag{
 		flags.DataDirFlag,
 		flags.CacheFlag,
@@ -62,12 +60,43 @@ Initialize the database using data from the experimental genesis file.
 					Action:    fakeGenesisImport,
 					Flags: []cli.Flag{
 						ModeFlag,
+						SpecFlag,
 					},
 					Description: `
-    sonictool --datadir=<datadir> genesis fake <N> [--mode=validator]
+    sonictool --datadir=<datadir> genesis fake <N> [--mode=validator] [--spec=spec.json]
 
 Requires the number of validators in the fake network as the first argument.
 Initialize the database for a testing fakenet.
+Use --spec to launch the network with the rules of a chain spec file.
+`,
+				},
+				{
+					Name:      "spec",
+					Usage:     "Export network rules into a chain spec file",
+					ArgsUsage: "<filename>",
+					Action:    exportSpec,
+					Description: `
+    sonictool --datadir=<datadir> genesis spec spec.json
+
+Export the full network rule set and the upgrade heights of the database into a versioned JSON chain spec.
+The spec can be used to launch a custom network with 'genesis fake --spec'.
+`,
+				},
+				{
+					Name:      "download",
+					Usage:     "Download a genesis file by a signed manifest and initialize the database from it",
+					ArgsUsage: "<manifest URL>",
+					Action:    gfileGenesisDownload,
+					Flags: []cli.Flag{
+						ExperimentalFlag,
+						ModeFlag,
+					},
+					Description: `
+    sonictool --datadir=<datadir> genesis download https://example.org/genesis.manifest.json
+
+Requires a first argument of the HTTPS URL of the genesis manifest signed by a trusted genesis signer.
+The genesis file referenced by the manifest is downloaded into the datadir, its size and SHA-256 hash
+are verified against the manifest, and the database is initialized from it.
 `,
 				},
 				{
@@ -126,6 +155,155 @@ The archive state is used for RPC - allows to handle state-related RPC queries.
 			},
 		},
 
+		{
+			Name:      "commitment",
+			Usage:     "Compute an experimental binary commitment of the state",
+			ArgsUsage: "[--hash=sha256] [--block=N]",
+			Action:    stateCommitment,
+			Flags: []cli.Flag{
+				CommitmentHashFlag,
+				CommitmentBlockFlag,
+			},
+			Description: `
+    sonictool --datadir=<datadir> commitment
+
+Computes a binary Merkle commitment over the flat state, intended for prototyping ZK light clients.
+The commitment isn't a part of the consensus, it's printed together with the state hash it commits.
+By default the live state is committed, use --block to commit the state of a block from the archive.
+The node must not be running.
+`,
+		},
+
+		{
+			Name:  "state-chunks",
+			Usage: "Export the state of an epoch boundary in verifiable chunks",
+			Subcommands: []cli.Command{
+				{
+					Name:      "export",
+					Usage:     "Export the state of an epoch boundary into chunks",
+					ArgsUsage: "<directory> [--epoch=N]",
+					Action:    exportStateChunks,
+					Flags: []cli.Flag{
+						StateChunksEpochFlag,
+						StateChunksAccountsFlag,
+						StateChunksBytesFlag,
+					},
+					Description: `
+    sonictool --datadir=<datadir> state-chunks export <directory>
+
+Streams the archive state at the start of the current epoch, or of the epoch set by --epoch,
+into the chunk files of the directory. A chunk is either a set of contract codes, or a range of
+accounts with their storage. The manifest of the directory commits the chunks by a Merkle root,
+and each chunk is verifiable by its proof without the other chunks.
+An interrupted export is resumed by running the command again with the same arguments.
+The node must not be running.
+`,
+				},
+				{
+					Name:      "verify",
+					Usage:     "Verify the chunks of an exported state against the manifest",
+					ArgsUsage: "<directory>",
+					Action:    verifyStateChunks,
+					Description: `
+    sonictool state-chunks verify <directory>
+
+Verifies every chunk of the directory by its hash and its Merkle proof to the manifest root.
+`,
+				},
+			},
+		},
+
+		{
+			Name:      "digest",
+			Usage:     "Compute a digest of the canonical chain data for datadirs comparison",
+			ArgsUsage: "[--state]",
+			Action:    datadirDigest,
+			Flags: []cli.Flag{
+				DigestStateFlag,
+			},
+			Description: `
+    sonictool --datadir=<datadir> digest [--state]
+
+Computes a deterministic digest of the epoch and block records, including the transactions and the receipts,
+which doesn't depend on the physical layout of the databases. Equal digests of two datadirs mean
+their chain data is semantically identical, e.g. after a migration or a restore from a backup.
+Use --state to include the live world state into the digest. The node must not be running.
+`,
+		},
+
+		{
+			Name:   "datadir",
+			Usage:  "Show the layout of the datadir",
+			Action: datadirLayout,
+			Description: `
+    sonictool --datadir=<datadir> datadir
+
+Prints the manifest of the datadir: the layout version and the network.
+The layout of the datadirs without a manifest is detected.
+`,
+			Subcommands: []cli.Command{
+				{
+					Name:   "migrate",
+					Usage:  "Migrate the datadir into the layout of this release",
+					Action: datadirMigrate,
+					Description: `
+    sonictool --datadir=<datadir> datadir migrate
+
+Migrates the datadir into the layout of this release and writes the datadir manifest.
+The node must not be running.
+Sonicd performs the migration automatically on start.
+`,
+				},
+			},
+		},
+
+		{
+			Name:      "logindexer",
+			Usage:     "Run the EVM logs indexer out of the node process",
+			ArgsUsage: "--node=<endpoint> [--addr=127.0.0.1:18547]",
+			Action:    runLogIndexer,
+			Flags: []cli.Flag{
+				LogIndexerNodeFlag,
+				LogIndexerAddrFlag,
+			},
+			Description: `
+    sonictool --datadir=<datadir> logindexer --node=ws://127.0.0.1:18546
+
+Follows the new blocks of the node, indexes the logs of their receipts into <datadir>/logindex
+and serves the logs queries over HTTP. Start the node with --logindex.remote=http://127.0.0.1:18547
+to forward its eth_getLogs queries to the indexer, so the heavy logs traffic is isolated
+from the consensus-critical process. The indexer catches up from the last indexed block on restart.
+`,
+		},
+
+		{
+			Name:   "reindex-logs",
+			Usage:  "Rebuild the EVM logs index from the stored receipts",
+			Action: reindexLogs,
+			Description: `
+    sonictool --datadir=<datadir> reindex-logs
+
+Drops the EVM logs index and indexes the logs of the stored receipts again,
+e.g. to recover from a corrupted logs index without a full resync. The progress is saved periodically,
+an interrupted rebuild is resumed by running the command again. The node refuses the logs queries
+until an interrupted rebuild is finished. The node must not be running.
+`,
+		},
+
+		{
+			Name:   "migrate-logs",
+			Usage:  "Migrate the EVM logs index of an older release",
+			Action: migrateLogs,
+			Description: `
+    sonictool --datadir=<datadir> migrate-logs
+
+Converts the EVM logs index of an older release into the current layout. The node migrates
+the index on the start as well, the command allows to migrate it ahead of the start or once the local
+logs index is enabled again. An interrupted migration is resumed by running the command again.
+The node must not be running.
+`,
+		},
+
 		{
 			Name:        "heal",
 			Usage:       "Fix database in dirty state",
@@ -157,6 +335,29 @@ See https://github.com/ethereum/go-ethereum/wiki/JavaScript-Console.
 This command allows to open a console attached to a running Sonic node.`,
 		},
 
+		{
+			Name:      "load",
+			Usage:     "Send synthetic transaction load to a node for capacity testing",
+			ArgsUsage: "<endpoint>",
+			Action:    generateLoad,
+			Flags: []cli.Flag{
+				LoadKeyFlag,
+				LoadFakeKeyFlag,
+				LoadRateFlag,
+				LoadDurationFlag,
+				LoadAccountsFlag,
+				LoadMixFlag,
+				LoadStorageSlotsFlag,
+			},
+			Description: `
+    sonictool load --fakekey 1 --rate 200 --duration 10m --mix 70,20,10 http://localhost:18545
+
+Funds the sending accounts from the given key, deploys a token and a storage writer contract,
+and sends the mix of transfers, token calls and storage writes at the given rate.
+Reports the achieved TPS, block gas utilization and the transaction pool size periodically.
+For devnets and testnets only.`,
+		},
+
 		{
 			Name:     "events",
 			Usage:    "Export/import blockchain events",
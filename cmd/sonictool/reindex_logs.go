@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/integration"
+)
+
+func reindexLogs(ctx *cli.Context) error {
+	return withLogsIndexDb(ctx, func(cancelCtx context.Context, gdb *gossip.Store) error {
+		if err := gdb.RebuildLogsIndex(cancelCtx); err != nil {
+			return fmt.Errorf("failed to rebuild the logs index: %w", err)
+		}
+		return nil
+	})
+}
+
+func migrateLogs(ctx *cli.Context) error {
+	return withLogsIndexDb(ctx, func(cancelCtx context.Context, gdb *gossip.Store) error {
+		if err := gdb.MigrateLogsIndex(cancelCtx); err != nil {
+			return fmt.Errorf("failed to migrate the logs index: %w", err)
+		}
+		return nil
+	})
+}
+
+// withLogsIndexDb opens the gossip DB of the datadir and runs the logs index maintenance,
+// which is cancelled by SIGINT or SIGTERM.
+func withLogsIndexDb(ctx *cli.Context, run func(context.Context, *gossip.Store) error) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+	dbs, err := integration.GetDbProducer(filepath.Join(dataDir, "chaindata"), integration.DBCacheConfig{
+		Cache:   cacheRatio.U64(480 * opt.MiB),
+		Fdlimit: 100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make DB producer: %v", err)
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cachescale.Identity)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	return run(cancelCtx, gdb)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+var SpecFlag = cli.StringFlag{
+	Name:  "spec",
+	Usage: "Chain spec file with the network rules to launch the network with",
+}
+
+func exportSpec(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	fileName := ctx.Args().First()
+	if fileName == "" {
+		return fmt.Errorf("the output file name must be provided as an argument")
+	}
+
+	chaindataDir := filepath.Join(dataDir, "chaindata")
+	dbs, err := integration.GetDbProducer(chaindataDir, integration.DBCacheConfig{
+		Cache:   64 * opt.MiB,
+		Fdlimit: 100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make DB producer: %v", err)
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cachescale.Identity)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	b, err := opera.EncodeChainSpec(opera.ChainSpec{
+		Rules:          gdb.GetRules(),
+		UpgradeHeights: gdb.GetUpgradeHeights(),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, b, 0644)
+}
+
+// loadSpecRules returns the genesis rules of the chain spec file set by --spec, or the default rules.
+// The upgrades of the spec's upgrade heights are carried into the genesis rules.
+func loadSpecRules(ctx *cli.Context, rules opera.Rules) (opera.Rules, error) {
+	if !ctx.IsSet(SpecFlag.Name) {
+		return rules, nil
+	}
+	b, err := os.ReadFile(ctx.String(SpecFlag.Name))
+	if err != nil {
+		return rules, fmt.Errorf("failed to read chain spec: %w", err)
+	}
+	spec, err := opera.DecodeChainSpec(b)
+	if err != nil {
+		return rules, fmt.Errorf("failed to decode chain spec: %w", err)
+	}
+	return spec.GenesisRules(), nil
+}
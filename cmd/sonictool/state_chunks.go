@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	cc "github.com/Fantom-foundation/Carmen/go/common"
+	"github.com/Fantom-foundation/Carmen/go/database/mpt"
+	mptio "github.com/Fantom-foundation/Carmen/go/database/mpt/io"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/db"
+	"github.com/Fantom-foundation/go-opera/cmd/sonictool/statechunks"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
+)
+
+var (
+	StateChunksEpochFlag = cli.Uint64Flag{
+		Name:  "epoch",
+		Usage: "Export the state at the start of the epoch instead of the current epoch",
+	}
+	StateChunksAccountsFlag = cli.Uint64Flag{
+		Name:  "chunk.accounts",
+		Usage: "Max number of the accounts in a chunk",
+		Value: statechunks.DefaultConfig().MaxAccounts,
+	}
+	StateChunksBytesFlag = cli.Uint64Flag{
+		Name:  "chunk.bytes",
+		Usage: "Max size of a chunk in bytes, the storage of an account is never split",
+		Value: statechunks.DefaultConfig().MaxBytes,
+	}
+)
+
+func exportStateChunks(ctx *cli.Context) error {
+	dataDir := ctx.GlobalString(flags.DataDirFlag.Name)
+	if dataDir == "" {
+		return fmt.Errorf("--%s need to be set", flags.DataDirFlag.Name)
+	}
+	outDir := ctx.Args().First()
+	if outDir == "" {
+		return fmt.Errorf("the output directory must be provided as an argument")
+	}
+
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cacheRatio, err := cacheScaler(ctx)
+	if err != nil {
+		return err
+	}
+	dbs, err := integration.GetDbProducer(filepath.Join(dataDir, "chaindata"), integration.DBCacheConfig{
+		Cache:   cacheRatio.U64(480 * opt.MiB),
+		Fdlimit: 100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make DB producer: %v", err)
+	}
+	defer dbs.Close()
+
+	gdb, err := db.MakeGossipDb(dbs, dataDir, false, cachescale.Identity)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	// the state at the start of an epoch is the state of the last block of the previous epoch
+	epoch := gdb.GetEpoch()
+	if ctx.IsSet(StateChunksEpochFlag.Name) {
+		epoch = idx.Epoch(ctx.Uint64(StateChunksEpochFlag.Name))
+	}
+	bs, _ := gdb.GetHistoryBlockEpochState(epoch)
+	if bs == nil {
+		return fmt.Errorf("the state of epoch %d isn't available", epoch)
+	}
+	block := bs.LastBlock.Idx
+	cfg := statechunks.Config{
+		MaxAccounts: ctx.Uint64(StateChunksAccountsFlag.Name),
+		MaxBytes:    ctx.Uint64(StateChunksBytesFlag.Name),
+	}
+
+	pr, pw := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		log.Info("Exporting archive state", "epoch", epoch, "block", block)
+		err := mptio.ExportBlockFromArchive(cancelCtx, mptio.NewLog(), filepath.Join(dataDir, "carmen", "archive"), pw, uint64(block))
+		pw.CloseWithError(err)
+		exported <- err
+	}()
+	// the archive is opened for the witness proofs once the export is read, as the export holds its lock till then
+	prover := &archiveProver{dir: filepath.Join(dataDir, "carmen", "archive"), block: uint64(block)}
+	defer prover.close()
+	m, err := statechunks.Export(cancelCtx, pr, outDir, epoch, block, cfg, prover.prove)
+	pr.CloseWithError(err)
+	if exportErr := <-exported; exportErr != nil {
+		return fmt.Errorf("failed to export the state: %w", exportErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to split the state into chunks: %w", err)
+	}
+
+	log.Info("State chunks exported", "chunks", len(m.Chunks))
+	fmt.Printf("- State hash: %v \n", m.StateHash.Hex())
+	fmt.Printf("- Chunks root: %v \n", m.Root.Hex())
+	return nil
+}
+
+// archiveProver creates the witness proofs of the accounts of a block of the Carmen archive.
+type archiveProver struct {
+	dir     string
+	block   uint64
+	archive *mpt.ArchiveTrie
+}
+
+func (p *archiveProver) prove(addr common.Address) ([][]byte, error) {
+	if p.archive == nil {
+		info, err := mptio.CheckMptDirectoryAndGetInfo(p.dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read carmen archive: %w", err)
+		}
+		p.archive, err = mpt.OpenArchiveTrie(p.dir, info.Config, mpt.NodeCacheConfig{}, mpt.ArchiveConfig{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open carmen archive: %w", err)
+		}
+	}
+	proof, err := p.archive.CreateWitnessProof(p.block, cc.Address(addr))
+	if err != nil {
+		return nil, err
+	}
+	elements := proof.GetElements()
+	res := make([][]byte, len(elements))
+	for i, e := range elements {
+		res[i] = e.ToBytes()
+	}
+	return res, nil
+}
+
+func (p *archiveProver) close() {
+	if p.archive != nil {
+		if err := p.archive.Close(); err != nil {
+			log.Warn("Failed to close carmen archive", "err", err)
+		}
+	}
+}
+
+func verifyStateChunks(ctx *cli.Context) error {
+	dir := ctx.Args().First()
+	if dir == "" {
+		return fmt.Errorf("the chunks directory must be provided as an argument")
+	}
+	m, err := statechunks.Verify(dir)
+	if err != nil {
+		return err
+	}
+	if !m.Complete {
+		return fmt.Errorf("the export is incomplete, %d chunks are exported", len(m.Chunks))
+	}
+	log.Info("State chunks verified", "epoch", m.Epoch, "block", m.Block, "chunks", len(m.Chunks))
+	fmt.Printf("- State hash: %v \n", m.StateHash.Hex())
+	fmt.Printf("- Chunks root: %v \n", m.Root.Hex())
+	return nil
+}
@@ -0,0 +1,131 @@
+package statechunks
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func leafHash(data []byte) common.Hash {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	var res common.Hash
+	h.Sum(res[:0])
+	return res
+}
+
+func nodeHash(l, r common.Hash) common.Hash {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(l[:])
+	h.Write(r[:])
+	var res common.Hash
+	h.Sum(res[:0])
+	return res
+}
+
+// split returns the largest power of 2 less than n.
+func split(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// merkleRoot returns the root of the tree of the leaves, the root of an empty tree is sha256().
+func merkleRoot(leaves []common.Hash) common.Hash {
+	switch len(leaves) {
+	case 0:
+		return sha256.Sum256(nil)
+	case 1:
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return nodeHash(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+// merkleProof returns the audit path of the leaf, from the bottom up.
+func merkleProof(leaves []common.Hash, i int) []common.Hash {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := split(len(leaves))
+	if i < k {
+		return append(merkleProof(leaves[:k], i), merkleRoot(leaves[k:]))
+	}
+	return append(merkleProof(leaves[k:], i-k), merkleRoot(leaves[:k]))
+}
+
+// verifyProof checks the audit path of the leaf i of n leaves to the root.
+func verifyProof(leaf common.Hash, i, n int, proof []common.Hash, root common.Hash) bool {
+	if i >= n {
+		return false
+	}
+	// RFC 9162, 2.1.3.2
+	fn, sn := i, n-1
+	r := leaf
+	for _, p := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && r == root
+}
+
+// VerifyChunk checks the data of the chunk against the complete manifest, without the other chunks.
+func VerifyChunk(m *Manifest, index int, data []byte) error {
+	if !m.Complete {
+		return errors.New("the export is incomplete")
+	}
+	if index < 0 || index >= len(m.Chunks) {
+		return fmt.Errorf("no chunk %d", index)
+	}
+	c := m.Chunks[index]
+	if uint64(len(data)) != c.Size || leafHash(data) != c.Hash {
+		return fmt.Errorf("chunk %d doesn't match its hash", index)
+	}
+	if !verifyProof(c.Hash, index, len(m.Chunks), c.Proof, m.Root) {
+		return fmt.Errorf("chunk %d doesn't match the root", index)
+	}
+	if c.Kind == KindAccounts {
+		if err := verifyAccountsChunk(m, c, data); err != nil {
+			return fmt.Errorf("chunk %d doesn't match the state hash: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// Verify checks the chunks of the export directory against its manifest.
+func Verify(dir string) (*Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range m.Chunks {
+		data, err := os.ReadFile(filepath.Join(dir, c.File))
+		if err != nil {
+			return nil, err
+		}
+		if err := VerifyChunk(m, i, data); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
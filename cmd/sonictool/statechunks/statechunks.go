@@ -0,0 +1,311 @@
+// Package statechunks splits the world state export of a block into chunks, each of which is verifiable
+// individually, so the state of an epoch boundary can be fed to the snapshot sync and to the checkpoints
+// chunk by chunk, and an interrupted export can be resumed.
+//
+// A chunk is a range of the world state entries in the order of the export: either the contract codes,
+// or a range of the accounts together with their storage slots. The manifest commits the chunks by
+// a binary Merkle tree (RFC 6962 shape) over their hashes:
+//
+//	leaf = sha256(0x00 || chunk data)
+//	node = sha256(0x01 || left || right)
+//
+// Each chunk of the manifest carries its Merkle proof, so a chunk is verified against the root
+// without the other chunks. The boundary accounts of each accounts chunk carry their witness proofs
+// to the state hash, which anchors the chunks to the state root.
+package statechunks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// ManifestFile is the name of the manifest in the export directory.
+	ManifestFile = "manifest.json"
+
+	stateMagic        = "Fantom-World-State"
+	stateVersion      = 1
+	accountEntryBytes = common.AddressLength + 32 + 8 + common.HashLength
+	slotEntryBytes    = 2 * common.HashLength
+)
+
+// Chunk kinds.
+const (
+	KindCodes    = "codes"
+	KindAccounts = "accounts"
+)
+
+// Config limits the size of the chunks. A chunk is closed at the first account or code, which exceeds
+// one of the limits, so the storage of an account is never split.
+type Config struct {
+	MaxAccounts uint64 `json:"maxAccounts"`
+	MaxBytes    uint64 `json:"maxBytes"`
+}
+
+// DefaultConfig returns the default chunks limits.
+func DefaultConfig() Config {
+	return Config{
+		MaxAccounts: 10000,
+		MaxBytes:    64 * 1024 * 1024,
+	}
+}
+
+// Chunk describes a chunk file of the export.
+type Chunk struct {
+	Index int    `json:"index"`
+	File  string `json:"file"`
+	Kind  string `json:"kind"`
+	// First and Last are the addresses of the accounts range of an accounts chunk
+	First *common.Address `json:"first,omitempty"`
+	Last  *common.Address `json:"last,omitempty"`
+	// FirstProof and LastProof are the witness proofs of the First and Last accounts to the state hash
+	FirstProof []hexutil.Bytes `json:"firstProof,omitempty"`
+	LastProof  []hexutil.Bytes `json:"lastProof,omitempty"`
+	Accounts   uint64          `json:"accounts"`
+	Slots      uint64          `json:"slots"`
+	Codes      uint64          `json:"codes"`
+	Size       uint64          `json:"size"`
+	Hash       common.Hash     `json:"hash"`
+	// Proof is the Merkle audit path of the chunk hash to the manifest root, set once the export is complete
+	Proof []common.Hash `json:"proof,omitempty"`
+}
+
+// Manifest describes the exported state and its chunks.
+type Manifest struct {
+	Epoch     idx.Epoch   `json:"epoch"`
+	Block     idx.Block   `json:"block"`
+	StateHash common.Hash `json:"stateHash"`
+	Config    Config      `json:"config"`
+	Chunks    []Chunk     `json:"chunks"`
+	// Complete is false while the export is in progress, Root is set once it's complete
+	Complete bool        `json:"complete"`
+	Root     common.Hash `json:"root"`
+}
+
+// ReadManifest reads the manifest of the export directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := new(Manifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("malformed manifest: %w", err)
+	}
+	return m, nil
+}
+
+// writeManifest replaces the manifest atomically, so an interrupted export keeps a consistent progress.
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ManifestFile))
+}
+
+func chunkFile(index int) string {
+	return fmt.Sprintf("chunk-%06d.bin", index)
+}
+
+// Export splits the world state export of the given block into the chunks in the directory.
+// If the directory contains an incomplete export of the same block with the same config, it's resumed:
+// the export is read from the start, the chunks written already are verified against the export and kept.
+// Once the export is read, the prover is called for the boundary accounts of the accounts chunks.
+func Export(ctx context.Context, in io.Reader, dir string, epoch idx.Epoch, block idx.Block, cfg Config, prove Prover) (*Manifest, error) {
+	if cfg.MaxAccounts == 0 || cfg.MaxBytes == 0 {
+		return nil, errors.New("the chunks limits must be positive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	m, err := ReadManifest(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		m = &Manifest{
+			Epoch:  epoch,
+			Block:  block,
+			Config: cfg,
+		}
+	} else if err != nil {
+		return nil, err
+	} else if m.Epoch != epoch || m.Block != block || m.Config != cfg {
+		return nil, fmt.Errorf("the directory contains an export of epoch %d, block %d with other limits", m.Epoch, m.Block)
+	} else if m.Complete {
+		return m, nil
+	}
+	done := len(m.Chunks)
+
+	r := bufio.NewReader(in)
+	header := make([]byte, len(stateMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read the state header: %w", err)
+	}
+	if string(header[:len(stateMagic)]) != stateMagic {
+		return nil, errors.New("not a world state export")
+	}
+	if v := header[len(stateMagic)]; v != stateVersion {
+		return nil, fmt.Errorf("unsupported world state version %d", v)
+	}
+
+	var (
+		buf   bytes.Buffer
+		chunk Chunk
+		next  int
+	)
+	flush := func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		chunk.Index = next
+		chunk.File = chunkFile(next)
+		chunk.Size = uint64(buf.Len())
+		chunk.Hash = leafHash(buf.Bytes())
+		if next < done {
+			// the chunk is exported already, it's rewritten only if its file is damaged
+			if m.Chunks[next].Hash != chunk.Hash {
+				return fmt.Errorf("chunk %d differs from the resumed export", next)
+			}
+			if data, err := os.ReadFile(filepath.Join(dir, chunk.File)); err != nil || leafHash(data) != chunk.Hash {
+				if err := os.WriteFile(filepath.Join(dir, chunk.File), buf.Bytes(), 0644); err != nil {
+					return err
+				}
+			}
+		} else {
+			if err := os.WriteFile(filepath.Join(dir, chunk.File), buf.Bytes(), 0644); err != nil {
+				return err
+			}
+			m.Chunks = append(m.Chunks, chunk)
+			if err := writeManifest(dir, m); err != nil {
+				return err
+			}
+		}
+		next++
+		buf.Reset()
+		chunk = Chunk{}
+		return ctx.Err()
+	}
+	for {
+		tag, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var entry []byte
+		switch tag {
+		case 'H':
+			entry = make([]byte, 1+1+common.HashLength)
+			if _, err := io.ReadFull(r, entry[1:]); err != nil {
+				return nil, fmt.Errorf("failed to read the state hash: %w", err)
+			}
+			stateHash := common.BytesToHash(entry[2:])
+			if done != 0 && stateHash != m.StateHash {
+				return nil, fmt.Errorf("the state hash %s differs from the resumed export", stateHash.Hex())
+			}
+			m.StateHash = stateHash
+			continue
+		case 'C':
+			var size [2]byte
+			if _, err := io.ReadFull(r, size[:]); err != nil {
+				return nil, fmt.Errorf("failed to read the code size: %w", err)
+			}
+			entry = make([]byte, 1+2+(int(size[0])<<8|int(size[1])))
+			copy(entry[1:], size[:])
+			if _, err := io.ReadFull(r, entry[3:]); err != nil {
+				return nil, fmt.Errorf("failed to read the code: %w", err)
+			}
+			if chunk.Kind != KindCodes || buf.Len() >= int(cfg.MaxBytes) {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				chunk.Kind = KindCodes
+			}
+			chunk.Codes++
+		case 'A':
+			entry = make([]byte, 1+accountEntryBytes)
+			if _, err := io.ReadFull(r, entry[1:]); err != nil {
+				return nil, fmt.Errorf("failed to read the account: %w", err)
+			}
+			if chunk.Kind != KindAccounts || chunk.Accounts >= cfg.MaxAccounts || buf.Len() >= int(cfg.MaxBytes) {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				chunk.Kind = KindAccounts
+			}
+			addr := common.BytesToAddress(entry[1 : 1+common.AddressLength])
+			if chunk.First == nil {
+				chunk.First = &addr
+			}
+			chunk.Last = &addr
+			chunk.Accounts++
+		case 'S':
+			if chunk.Kind != KindAccounts {
+				return nil, errors.New("storage slot without an account")
+			}
+			entry = make([]byte, 1+slotEntryBytes)
+			if _, err := io.ReadFull(r, entry[1:]); err != nil {
+				return nil, fmt.Errorf("failed to read the storage slot: %w", err)
+			}
+			chunk.Slots++
+		default:
+			return nil, fmt.Errorf("unexpected world state entry %q", tag)
+		}
+		entry[0] = tag
+		buf.Write(entry)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if next < done {
+		return nil, errors.New("the export has less chunks than the resumed one")
+	}
+
+	for i := range m.Chunks {
+		c := &m.Chunks[i]
+		if c.Kind != KindAccounts || c.FirstProof != nil {
+			continue
+		}
+		first, err := prove(*c.First)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prove account %s: %w", c.First.Hex(), err)
+		}
+		last, err := prove(*c.Last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prove account %s: %w", c.Last.Hex(), err)
+		}
+		c.FirstProof, c.LastProof = toHexBytes(first), toHexBytes(last)
+		if err := writeManifest(dir, m); err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	leaves := make([]common.Hash, len(m.Chunks))
+	for i, c := range m.Chunks {
+		leaves[i] = c.Hash
+	}
+	m.Root = merkleRoot(leaves)
+	for i := range m.Chunks {
+		m.Chunks[i].Proof = merkleProof(leaves, i)
+	}
+	m.Complete = true
+	return m, writeManifest(dir, m)
+}
@@ -0,0 +1,215 @@
+package statechunks
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func account(addr common.Address, balance *big.Int, nonce uint64, codeHash common.Hash) []byte {
+	entry := append([]byte{'A'}, addr[:]...)
+	entry = append(entry, common.BigToHash(balance).Bytes()...)
+	entry = binary.BigEndian.AppendUint64(entry, nonce)
+	return append(entry, codeHash[:]...)
+}
+
+func slot(key, value common.Hash) []byte {
+	return append(append([]byte{'S'}, key[:]...), value[:]...)
+}
+
+func export(stateHash common.Hash, entries ...[]byte) []byte {
+	buf := append([]byte(stateMagic), stateVersion, 'H', 0)
+	buf = append(buf, stateHash[:]...)
+	buf = append(buf, 'C', 0, 3, 1, 2, 3)
+	buf = append(buf, 'C', 0, 2, 4, 5)
+	for _, e := range entries {
+		buf = append(buf, e...)
+	}
+	return buf
+}
+
+// testState returns the export of a state of 10 accounts and the prover of its accounts.
+func testState(t *testing.T) ([]byte, Prover) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	codes := [][]byte{{1, 2, 3}, {4, 5}}
+	var entries [][]byte
+	for i := byte(1); i <= 10; i++ {
+		addr := common.BytesToAddress(bytes.Repeat([]byte{i}, common.AddressLength))
+		balance := big.NewInt(int64(i) * 1000)
+		statedb.SetBalance(addr, balance)
+		statedb.SetNonce(addr, uint64(i))
+		codeHash := crypto.Keccak256Hash(nil)
+		if int(i) <= len(codes) {
+			statedb.SetCode(addr, codes[i-1])
+			codeHash = crypto.Keccak256Hash(codes[i-1])
+		}
+		entries = append(entries, account(addr, balance, uint64(i), codeHash))
+		for j := byte(0); j < i%3; j++ {
+			key, value := common.Hash{i*10 + j}, common.Hash{j + 1}
+			statedb.SetState(addr, key, value)
+			entries = append(entries, slot(key, value))
+		}
+	}
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	return export(root, entries...), statedb.GetProof
+}
+
+func TestExport(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	state, prove := testState(t)
+	m, err := Export(context.Background(), bytes.NewReader(state), dir, 5, 100, Config{MaxAccounts: 3, MaxBytes: 1 << 20}, prove)
+	require.NoError(err)
+	require.True(m.Complete)
+	require.Equal(common.BytesToHash(state[len(stateMagic)+3:len(stateMagic)+3+common.HashLength]), m.StateHash)
+
+	// the codes chunk and the accounts chunks of 3, 3, 3 and 1 accounts
+	require.Len(m.Chunks, 5)
+	require.Equal(KindCodes, m.Chunks[0].Kind)
+	require.Equal(uint64(2), m.Chunks[0].Codes)
+	var accounts, slots uint64
+	for _, c := range m.Chunks[1:] {
+		require.Equal(KindAccounts, c.Kind)
+		accounts += c.Accounts
+		slots += c.Slots
+	}
+	require.Equal(uint64(10), accounts)
+	require.Equal(uint64(10), slots)
+	require.Equal(common.BytesToAddress(bytes.Repeat([]byte{4}, common.AddressLength)), *m.Chunks[2].First)
+	require.Equal(common.BytesToAddress(bytes.Repeat([]byte{6}, common.AddressLength)), *m.Chunks[2].Last)
+
+	// the chunks are verified individually
+	for i, c := range m.Chunks {
+		data, err := os.ReadFile(filepath.Join(dir, c.File))
+		require.NoError(err)
+		require.NoError(VerifyChunk(m, i, data))
+		require.Error(VerifyChunk(m, (i+1)%len(m.Chunks), data))
+	}
+	read, err := Verify(dir)
+	require.NoError(err)
+	require.Equal(m, read)
+
+	// the accounts chunks are anchored to the state hash by the witness proofs of their boundaries
+	data, err := os.ReadFile(filepath.Join(dir, m.Chunks[2].File))
+	require.NoError(err)
+	anchored := *m
+	anchored.StateHash = common.Hash{1}
+	require.ErrorContains(VerifyChunk(&anchored, 2, data), "state hash")
+	anchored = *m
+	anchored.Chunks = append([]Chunk{}, m.Chunks...)
+	anchored.Chunks[2].LastProof = m.Chunks[3].LastProof
+	require.ErrorContains(VerifyChunk(&anchored, 2, data), "state hash")
+
+	// an account altered consistently with the chunks root is detected by the witness proof
+	altered := bytes.Clone(data)
+	altered[1+common.AddressLength+31]++
+	rebuilt := *m
+	rebuilt.Chunks = append([]Chunk{}, m.Chunks...)
+	rebuilt.Chunks[2].Hash = leafHash(altered)
+	leaves := make([]common.Hash, len(rebuilt.Chunks))
+	for i, c := range rebuilt.Chunks {
+		leaves[i] = c.Hash
+	}
+	rebuilt.Root = merkleRoot(leaves)
+	rebuilt.Chunks[2].Proof = merkleProof(leaves, 2)
+	require.ErrorContains(VerifyChunk(&rebuilt, 2, altered), "differs from the state")
+
+	// a damaged chunk is detected
+	require.NoError(os.WriteFile(filepath.Join(dir, m.Chunks[3].File), []byte("damaged"), 0644))
+	_, err = Verify(dir)
+	require.Error(err)
+}
+
+func TestMerkleProofs(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		leaves := make([]common.Hash, n)
+		for i := range leaves {
+			leaves[i] = leafHash([]byte{byte(i)})
+		}
+		root := merkleRoot(leaves)
+		for i := range leaves {
+			proof := merkleProof(leaves, i)
+			require.True(t, verifyProof(leaves[i], i, n, proof, root), "leaf %d of %d", i, n)
+			if n > 1 {
+				require.False(t, verifyProof(leaves[(i+1)%n], i, n, proof, root), "leaf %d of %d", i, n)
+			}
+		}
+	}
+}
+
+// failingReader fails once the given number of bytes is read.
+type failingReader struct {
+	r    io.Reader
+	left int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.left <= 0 {
+		return 0, errors.New("interrupted")
+	}
+	if len(p) > f.left {
+		p = p[:f.left]
+	}
+	n, err := f.r.Read(p)
+	f.left -= n
+	return n, err
+}
+
+func TestExportResume(t *testing.T) {
+	require := require.New(t)
+	state, prove := testState(t)
+	cfg := Config{MaxAccounts: 2, MaxBytes: 1 << 20}
+
+	exp, err := Export(context.Background(), bytes.NewReader(state), t.TempDir(), 5, 100, cfg, prove)
+	require.NoError(err)
+
+	dir := t.TempDir()
+	_, err = Export(context.Background(), &failingReader{bytes.NewReader(state), len(state) / 2}, dir, 5, 100, cfg, prove)
+	require.Error(err)
+	partial, err := ReadManifest(dir)
+	require.NoError(err)
+	require.False(partial.Complete)
+	require.NotEmpty(partial.Chunks)
+	require.Less(len(partial.Chunks), len(exp.Chunks))
+
+	// the export of another block or with other limits isn't mixed with the partial one
+	_, err = Export(context.Background(), bytes.NewReader(state), dir, 5, 101, cfg, prove)
+	require.Error(err)
+	_, err = Export(context.Background(), bytes.NewReader(state), dir, 5, 100, Config{MaxAccounts: 3, MaxBytes: 1 << 20}, prove)
+	require.Error(err)
+
+	// a chunk damaged before the resumption is rewritten
+	require.NoError(os.WriteFile(filepath.Join(dir, partial.Chunks[0].File), nil, 0644))
+	got, err := Export(context.Background(), bytes.NewReader(state), dir, 5, 100, cfg, prove)
+	require.NoError(err)
+	require.Equal(exp, got)
+	_, err = Verify(dir)
+	require.NoError(err)
+}
+
+func TestExportRejectsMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("not a state"),
+		export(common.Hash{}, slot(common.Hash{1}, common.Hash{2})),
+		export(common.Hash{}, []byte{'A', 1, 2}),
+		export(common.Hash{}, []byte{'X'}),
+	} {
+		_, err := Export(context.Background(), bytes.NewReader(data), t.TempDir(), 1, 1, DefaultConfig(), nil)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,104 @@
+package statechunks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Prover returns the witness proof of the account in the exported state, i.e. the MPT nodes
+// on the path from the state root to the account.
+type Prover func(addr common.Address) ([][]byte, error)
+
+// accountBoundaries returns the first and the last account entries of an accounts chunk data.
+func accountBoundaries(data []byte) (first, last []byte, err error) {
+	for pos := 0; pos < len(data); {
+		switch data[pos] {
+		case 'A':
+			if pos+1+accountEntryBytes > len(data) {
+				return nil, nil, errors.New("truncated account")
+			}
+			last = data[pos+1 : pos+1+accountEntryBytes]
+			if first == nil {
+				first = last
+			}
+			pos += 1 + accountEntryBytes
+		case 'S':
+			if first == nil {
+				return nil, nil, errors.New("storage slot without an account")
+			}
+			pos += 1 + slotEntryBytes
+		default:
+			return nil, nil, fmt.Errorf("unexpected accounts chunk entry %q", data[pos])
+		}
+	}
+	if first == nil {
+		return nil, nil, errors.New("no accounts")
+	}
+	return first, last, nil
+}
+
+// verifyAccount checks the account entry of the export against its witness proof to the state root.
+func verifyAccount(root common.Hash, entry []byte, proof []hexutil.Bytes) error {
+	addr := common.BytesToAddress(entry[:common.AddressLength])
+	db := memorydb.New()
+	for _, node := range proof {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	value, err := trie.VerifyProof(root, crypto.Keccak256(addr[:]), db)
+	if err != nil {
+		return fmt.Errorf("invalid witness proof of account %s: %w", addr.Hex(), err)
+	}
+	if value == nil {
+		return fmt.Errorf("account %s isn't in the state", addr.Hex())
+	}
+	var acc state.Account
+	if err := rlp.DecodeBytes(value, &acc); err != nil {
+		return fmt.Errorf("malformed account %s: %w", addr.Hex(), err)
+	}
+	entry = entry[common.AddressLength:]
+	balance := new(big.Int).SetBytes(entry[:32])
+	nonce := binary.BigEndian.Uint64(entry[32:40])
+	codeHash := entry[40:]
+	if acc.Balance.Cmp(balance) != 0 || acc.Nonce != nonce || !bytes.Equal(acc.CodeHash, codeHash) {
+		return fmt.Errorf("account %s differs from the state", addr.Hex())
+	}
+	return nil
+}
+
+// verifyAccountsChunk checks the boundary accounts of an accounts chunk against the state root.
+func verifyAccountsChunk(m *Manifest, c Chunk, data []byte) error {
+	first, last, err := accountBoundaries(data)
+	if err != nil {
+		return err
+	}
+	if c.First == nil || c.Last == nil ||
+		common.BytesToAddress(first[:common.AddressLength]) != *c.First ||
+		common.BytesToAddress(last[:common.AddressLength]) != *c.Last {
+		return errors.New("the accounts range differs from the manifest")
+	}
+	if err := verifyAccount(m.StateHash, first, c.FirstProof); err != nil {
+		return err
+	}
+	return verifyAccount(m.StateHash, last, c.LastProof)
+}
+
+func toHexBytes(proof [][]byte) []hexutil.Bytes {
+	res := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		res[i] = node
+	}
+	return res
+}
@@ -15,12 +15,13 @@ import (
 	"strings"
 
 	"github.com/Fantom-foundation/lachesis-base/abft"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p/enode"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/naoina/toml"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"gopkg.in/urfave/cli.v1"
@@ -30,18 +31,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/go-opera/utils/memory"
+	"github.com/Fantom-foundation/go-opera/utils/rpcendpoints"
 	"github.com/Fantom-foundation/go-opera/vecmt"
-)
-
-const (
-	// ClientIdentifier to advertise over the network.
-	ClientIdentifier = "Sonic"
-)
-
-var (
-	// Git SHA1 commit hash of the release (set via linker flags).
-	GitCommit = ""
-	GitDate   = ""
+	"github.com/Fantom-foundation/go-opera/version"
 )
 
 // These settings ensure that TOML keys use the same names as Go struct fields.
@@ -67,6 +59,8 @@ type Config struct {
 	LachesisStore abft.StoreConfig
 	VectorClock   vecmt.IndexConfig
 	DBs           integration.DBsConfig
+	// RPCEndpoints are the virtual RPC endpoints served along with the HTTP RPC of the node
+	RPCEndpoints []rpcendpoints.Config `toml:",omitempty"`
 }
 
 func (c *Config) AppConfigs() integration.Configs {
@@ -162,18 +156,120 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) error {
 func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) gossip.Config {
 	cfg := src
 
+	if ctx.GlobalIsSet(flags.LatencyDialFlag.Name) {
+		cfg.Dial.Enabled = ctx.GlobalBool(flags.LatencyDialFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.SubnetQuotaFlag.Name) {
+		cfg.Dial.SubnetQuota = ctx.GlobalInt(flags.SubnetQuotaFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.P2PCaptureFlag.Name) {
+		cfg.P2PCapture.Path = ctx.GlobalString(flags.P2PCaptureFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.P2PCaptureTxsFlag.Name) {
+		cfg.P2PCapture.Txs = ctx.GlobalBool(flags.P2PCaptureTxsFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.P2PCaptureMaxSizeFlag.Name) {
+		cfg.P2PCapture.MaxSize = uint64(ctx.GlobalInt(flags.P2PCaptureMaxSizeFlag.Name)) * opt.MiB
+	}
+	if ctx.GlobalIsSet(flags.P2PReplayFlag.Name) {
+		cfg.P2PCapture.Replay = ctx.GlobalString(flags.P2PReplayFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPriorityFlag.Name) {
+		cfg.Protocol.TxPriority.Enabled = ctx.GlobalBool(flags.TxPriorityFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.PeerIdleTimeoutFlag.Name) {
+		cfg.Protocol.PeerPolicy.IdleTimeout = ctx.GlobalDuration(flags.PeerIdleTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.MinUsefulPeersFlag.Name) {
+		cfg.Protocol.PeerPolicy.MinUsefulPeers = ctx.GlobalInt(flags.MinUsefulPeersFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RedialBackoffFlag.Name) {
+		redialMax := ctx.GlobalDuration(flags.RedialBackoffFlag.Name)
+		if redialMax <= 0 {
+			cfg.Protocol.PeerPolicy.Redial.Min = 0
+		} else if redialMax < cfg.Protocol.PeerPolicy.Redial.Min {
+			cfg.Protocol.PeerPolicy.Redial.Min = redialMax
+		}
+		cfg.Protocol.PeerPolicy.Redial.Max = redialMax
+	}
 	if ctx.GlobalIsSet(flags.RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.GlobalUint64(flags.RPCGlobalGasCapFlag.Name)
 	}
 	if ctx.GlobalIsSet(flags.RPCGlobalEVMTimeoutFlag.Name) {
 		cfg.RPCEVMTimeout = ctx.GlobalDuration(flags.RPCGlobalEVMTimeoutFlag.Name)
 	}
+	if ctx.GlobalIsSet(flags.RPCGlobalEVMMemoryLimitFlag.Name) {
+		cfg.RPCEVMMemoryLimit = ctx.GlobalUint64(flags.RPCGlobalEVMMemoryLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCGlobalReturnDataLimitFlag.Name) {
+		cfg.RPCReturnDataLimit = ctx.GlobalUint64(flags.RPCGlobalReturnDataLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCTraceWorkersFlag.Name) {
+		cfg.RPCTraceWorkers = ctx.GlobalInt(flags.RPCTraceWorkersFlag.Name)
+	}
 	if ctx.GlobalIsSet(flags.RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(flags.RPCGlobalTxFeeCapFlag.Name)
 	}
 	if ctx.GlobalIsSet(flags.RPCGlobalTimeoutFlag.Name) {
 		cfg.RPCTimeout = ctx.GlobalDuration(flags.RPCGlobalTimeoutFlag.Name)
 	}
+	if ctx.GlobalIsSet(flags.RPCSlowCallThresholdFlag.Name) {
+		cfg.RPCSlowCallThreshold = ctx.GlobalDuration(flags.RPCSlowCallThresholdFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCAccountsLimitFlag.Name) {
+		cfg.RPCAccountsLimit = ctx.GlobalInt(flags.RPCAccountsLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCBalanceHistoryLimitFlag.Name) {
+		cfg.RPCBalanceHistoryLimit = ctx.GlobalInt(flags.RPCBalanceHistoryLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCProofsLimitFlag.Name) {
+		cfg.RPCProofsLimit = ctx.GlobalInt(flags.RPCProofsLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCMempoolTokenFlag.Name) {
+		cfg.FilterAPI.MempoolStreamToken = ctx.GlobalString(flags.RPCMempoolTokenFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCFilterTimeoutFlag.Name) {
+		cfg.FilterAPI.FilterTimeout = ctx.GlobalDuration(flags.RPCFilterTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCLogsTimeoutFlag.Name) {
+		cfg.FilterAPI.LogsSearchTimeout = ctx.GlobalDuration(flags.RPCLogsTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCDenyListFlag.Name) {
+		cfg.RPCDenyList = ctx.GlobalString(flags.RPCDenyListFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.RPCDenyListAuditFlag.Name) {
+		cfg.RPCDenyListAudit = ctx.GlobalString(flags.RPCDenyListAuditFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.ValidatorRewardRecipientAuditFlag.Name) {
+		cfg.RewardRecipientAudit = ctx.GlobalString(flags.ValidatorRewardRecipientAuditFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.MemoryBudgetFlag.Name) {
+		cfg.MemoryBudget = uint64(ctx.GlobalInt(flags.MemoryBudgetFlag.Name)) * opt.MiB
+	}
+	if ctx.GlobalIsSet(flags.RPCHeadLagFlag.Name) {
+		cfg.RPCHeadLag = idx.Block(ctx.GlobalUint64(flags.RPCHeadLagFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.RPCMaxStaleBlocksFlag.Name) {
+		cfg.RPCMaxStaleBlocks = idx.Block(ctx.GlobalUint64(flags.RPCMaxStaleBlocksFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.RPCAllowRollbackFlag.Name) {
+		cfg.AllowRollback = ctx.GlobalBool(flags.RPCAllowRollbackFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxTracesPersistFlag.Name) {
+		cfg.TxTracesPersist = ctx.GlobalBool(flags.TxTracesPersistFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxTracesRetentionFlag.Name) {
+		cfg.TxTracesRetention = idx.Block(ctx.GlobalUint64(flags.TxTracesRetentionFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.TxPoolRebroadcastFlag.Name) {
+		cfg.TxRebroadcast.Interval = ctx.GlobalDuration(flags.TxPoolRebroadcastFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolRebroadcastExpiryFlag.Name) {
+		cfg.TxRebroadcast.Expiry = ctx.GlobalDuration(flags.TxPoolRebroadcastExpiryFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.TxPoolRebroadcastBumpAfterFlag.Name) {
+		cfg.TxRebroadcast.BumpAfter = ctx.GlobalInt(flags.TxPoolRebroadcastBumpAfterFlag.Name)
+	}
 
 	return cfg
 }
@@ -198,9 +294,52 @@ func setEvmStore(ctx *cli.Context, datadir string, src  evmstore.StoreConfig) (e
 			cfg.DisableTxHashesIndexing = true
 		}
 	}
+	if ctx.GlobalIsSet(flags.ArchivedContractsFlag.Name) {
+		cfg.ArchivedContracts = nil
+		for _, addr := range splitAndTrim(ctx.GlobalString(flags.ArchivedContractsFlag.Name)) {
+			if !common.IsHexAddress(addr) {
+				return cfg, fmt.Errorf("invalid account in --%s: %s", flags.ArchivedContractsFlag.Name, addr)
+			}
+			cfg.ArchivedContracts = append(cfg.ArchivedContracts, common.HexToAddress(addr))
+		}
+		if len(cfg.ArchivedContracts) != 0 && cfg.StateDb.Archive != carmen.NoArchive {
+			log.Warn("Full archive is disabled by the selective archive", "flag", flags.ArchivedContractsFlag.Name,
+				"contracts", len(cfg.ArchivedContracts), "archive", cfg.StateDb.Archive)
+			cfg.StateDb.Archive = carmen.NoArchive
+		}
+	}
+	if ctx.GlobalIsSet(flags.ReceiptsRetentionFlag.Name) {
+		cfg.ReceiptsRetentionEpochs = idx.Epoch(ctx.GlobalUint64(flags.ReceiptsRetentionFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.StateExpiryFlag.Name) {
+		cfg.StateExpiryEpochs = idx.Epoch(ctx.GlobalUint64(flags.StateExpiryFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.RemoteLogsIndexFlag.Name) {
+		cfg.RemoteLogsIndex = ctx.GlobalString(flags.RemoteLogsIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(flags.BloomBitsFlag.Name) {
+		cfg.BloomBitsIndexing = ctx.GlobalBool(flags.BloomBitsFlag.Name)
+	}
 	return cfg, nil
 }
 
+func setHistory(ctx *cli.Context, cfg *gossip.HistoryConfig) error {
+	if ctx.GlobalIsSet(flags.HistoryStartFlag.Name) {
+		cfg.StartBlock = idx.Block(ctx.GlobalUint64(flags.HistoryStartFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.HistoryAnchorFlag.Name) {
+		anchor, err := hexutil.Decode(ctx.GlobalString(flags.HistoryAnchorFlag.Name))
+		if err != nil || len(anchor) != common.HashLength {
+			return fmt.Errorf("invalid block hash in --%s: %s", flags.HistoryAnchorFlag.Name, ctx.GlobalString(flags.HistoryAnchorFlag.Name))
+		}
+		cfg.Anchor = common.BytesToHash(anchor)
+	}
+	if cfg.StartBlock != 0 && cfg.Anchor == (common.Hash{}) {
+		return fmt.Errorf("--%s is required for --%s", flags.HistoryAnchorFlag.Name, flags.HistoryStartFlag.Name)
+	}
+	return nil
+}
+
 // makeDatabaseHandles raises out the number of allowed file handles per process
 // and returns half of the allowance to assign to the database.
 func makeDatabaseHandles() (uint64, error) {
@@ -309,6 +448,11 @@ func MakeAllConfigsFromFile(ctx *cli.Context, configFile string) (*Config, error
 		return nil, err
 	}
 
+	err = setHistory(ctx, &cfg.OperaStore.History)
+	if err != nil {
+		return nil, err
+	}
+
 	err = setValidator(ctx, &cfg.Emitter)
 	if err != nil {
 		return nil, err
@@ -329,6 +473,12 @@ func MakeAllConfigsFromFile(ctx *cli.Context, configFile string) (*Config, error
 	if err := cfg.Opera.Validate(); err != nil {
 		return nil, err
 	}
+	if err := cfg.OperaStore.EVM.Validate(); err != nil {
+		return nil, err
+	}
+	if err := rpcendpoints.Validate(cfg.RPCEndpoints, &cfg.Node); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -339,8 +489,8 @@ func MakeAllConfigs(ctx *cli.Context) (*Config, error) {
 
 func DefaultNodeConfig() node.Config {
 	cfg := NodeDefaultConfig
-	cfg.Name = ClientIdentifier
-	cfg.Version = params.VersionWithCommit(GitCommit, GitDate)
+	cfg.Name = version.ClientIdentifier
+	cfg.Version = version.WithCommit()
 	cfg.HTTPModules = append(cfg.HTTPModules, "eth", "ftm", "dag", "abft", "web3")
 	cfg.WSModules = append(cfg.WSModules, "eth", "ftm", "dag", "abft", "web3")
 	cfg.IPCPath = "opera.ipc"
@@ -120,6 +120,20 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: ethconfig.Defaults.TxPool.Lifetime,
 	}
+	TxPoolRebroadcastFlag = cli.DurationFlag{
+		Name:  "txpool.rebroadcast",
+		Usage: "Time interval to rebroadcast the local transactions until their inclusion (0 = disabled)",
+	}
+	TxPoolRebroadcastExpiryFlag = cli.DurationFlag{
+		Name:  "txpool.rebroadcast.expiry",
+		Usage: "Maximum amount of time the local transactions are rebroadcast",
+		Value: gossip.DefaultConfig(cachescale.Identity).TxRebroadcast.Expiry,
+	}
+	TxPoolRebroadcastBumpAfterFlag = cli.IntFlag{
+		Name:  "txpool.rebroadcast.bumpafter",
+		Usage: "Number of rebroadcasts after which the next pre-signed replacement with a higher fee is submitted (0 = no fee bumps)",
+		Value: gossip.DefaultConfig(cachescale.Identity).TxRebroadcast.BumpAfter,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -263,6 +277,50 @@ var (
 		Name:  "iprestrict",
 		Usage: "Restricts network communication to the given IP addresses",
 	}
+	LatencyDialFlag = cli.BoolFlag{
+		Name:  "p2p.latencydial",
+		Usage: "Dials the discovered peers with the lowest measured RTT first, keeping the subnet diversity quotas",
+	}
+	SubnetQuotaFlag = cli.IntFlag{
+		Name:  "p2p.subnetquota",
+		Usage: "Maximum number of peers dialed within the same /24 (IPv4) or /48 (IPv6) subnet by the latency aware dialing (0 = no quota)",
+		Value: gossip.DefaultConfig(cachescale.Identity).Dial.SubnetQuota,
+	}
+	P2PCaptureFlag = cli.StringFlag{
+		Name:  "p2p.capture",
+		Usage: "Writes all inbound protocol messages into the file, the peers are pseudonymized",
+	}
+	P2PCaptureTxsFlag = cli.BoolFlag{
+		Name:  "p2p.capture.txs",
+		Usage: "Captures the transactions propagation messages too, which may reveal the origin of transactions",
+	}
+	P2PCaptureMaxSizeFlag = cli.IntFlag{
+		Name:  "p2p.capture.maxsize",
+		Usage: "Stops capturing once the capture file reaches the size in MiB (0 = no limit)",
+	}
+	P2PReplayFlag = cli.StringFlag{
+		Name:  "p2p.replay",
+		Usage: "Feeds the messages of the p2p capture file into the node once it's started, for testing purposes",
+	}
+	TxPriorityFlag = cli.BoolFlag{
+		Name:  "p2p.txpriority",
+		Usage: "Propagates the transactions with higher effective tips first, congested peers get only the hashes of the low priority ones",
+	}
+	PeerIdleTimeoutFlag = cli.DurationFlag{
+		Name:  "p2p.idletimeout",
+		Usage: "Drops the peers which didn't send new events, transactions or progress for the period (0 = disabled)",
+		Value: gossip.DefaultConfig(cachescale.Identity).Protocol.PeerPolicy.IdleTimeout,
+	}
+	MinUsefulPeersFlag = cli.IntFlag{
+		Name:  "p2p.minusefulpeers",
+		Usage: "Minimum number of useful peers, below which the disconnected peers are re-dialed without a backoff",
+		Value: gossip.DefaultConfig(cachescale.Identity).Protocol.PeerPolicy.MinUsefulPeers,
+	}
+	RedialBackoffFlag = cli.DurationFlag{
+		Name:  "p2p.redialbackoff",
+		Usage: "Maximum delay of re-dialing a repeatedly disconnected peer (0 = disabled)",
+		Value: gossip.DefaultConfig(cachescale.Identity).Protocol.PeerPolicy.Redial.Max,
+	}
 	PrivateNodeFlag = cli.StringFlag{
 		Name:  "privatenodes",
 		Usage: "Comma separated enode URLs which must not be advertised as peers to public network",
@@ -276,6 +334,10 @@ var (
 		Name:  "cache",
 		Usage: "Megabytes of memory allocated to internal caching",
 	}
+	MemoryBudgetFlag = cli.IntFlag{
+		Name:  "memory.budget",
+		Usage: "Megabytes of memory shared by caches, txpool and DAG buffers, caches are shrunk once it's exceeded (0 = no limit)",
+	}
 	RPCGlobalGasCapFlag = cli.Uint64Flag{
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in ftm_call/estimateGas (0=infinite)",
@@ -286,6 +348,21 @@ var (
 		Usage: "Sets a timeout used for eth_call (0=infinite)",
 		Value: gossip.DefaultConfig(cachescale.Identity).RPCEVMTimeout,
 	}
+	RPCGlobalEVMMemoryLimitFlag = cli.Uint64Flag{
+		Name:  "rpc.evmmemorylimit",
+		Usage: "Sets a limit of the EVM memory in bytes used by eth_call and tracing (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCEVMMemoryLimit,
+	}
+	RPCGlobalReturnDataLimitFlag = cli.Uint64Flag{
+		Name:  "rpc.returndatalimit",
+		Usage: "Sets a limit of the return data size in bytes of eth_call and tracing (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCReturnDataLimit,
+	}
+	RPCTraceWorkersFlag = cli.IntFlag{
+		Name:  "rpc.traceworkers",
+		Usage: "Sets a number of goroutines re-executing transactions for debug and trace methods (0 = number of CPUs)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCTraceWorkers,
+	}
 	RPCGlobalTxFeeCapFlag = cli.Float64Flag{
 		Name:  "rpc.txfeecap",
 		Usage: "Sets a cap on transaction fee (in FTM) that can be sent via the RPC APIs (0 = no cap)",
@@ -311,6 +388,102 @@ var (
 		Usage: "Limit maximum size in some RPC calls execution",
 		Value: gossip.DefaultConfig(cachescale.Identity).MaxResponseSize,
 	}
+	RPCSlowCallThresholdFlag = cli.DurationFlag{
+		Name:  "rpc.slowcall",
+		Usage: "Execution time of EVM executions, traces and logs queries over RPC, the slower calls are logged with their trace IDs (0 = disabled)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCSlowCallThreshold,
+	}
+	RPCAccountsLimitFlag = cli.IntFlag{
+		Name:  "rpc.accountslimit",
+		Usage: "Maximum number of addresses queried by eth_getAccounts (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCAccountsLimit,
+	}
+	RPCBalanceHistoryLimitFlag = cli.IntFlag{
+		Name:  "rpc.balancehistorylimit",
+		Usage: "Maximum number of blocks queried by eth_getBalanceHistory (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCBalanceHistoryLimit,
+	}
+	RPCProofsLimitFlag = cli.IntFlag{
+		Name:  "rpc.proofslimit",
+		Usage: "Maximum number of proofs (accounts and storage slots) queried by eth_getProofs (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).RPCProofsLimit,
+	}
+	RPCMempoolTokenFlag = cli.StringFlag{
+		Name:  "rpc.mempooltoken",
+		Usage: "Enables the mempool_subscribe streaming of full pending transactions for subscribers knowing the token",
+	}
+	RPCFilterTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc.filtertimeout",
+		Usage: "Time after which the log, block and pending transaction filters which aren't polled are removed",
+		Value: gossip.DefaultConfig(cachescale.Identity).FilterAPI.FilterTimeout,
+	}
+	RPCLogsTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc.logstimeout",
+		Usage: "Timeout of the logs index search of eth_getLogs and the log filters (0 = no timeout)",
+		Value: gossip.DefaultConfig(cachescale.Identity).FilterAPI.LogsSearchTimeout,
+	}
+	RPCDenyListFlag = cli.StringFlag{
+		Name:  "rpc.denylist",
+		Usage: "Path to the file of addresses, transactions from or to which are rejected at RPC submission (reloaded on change)",
+	}
+	RPCDenyListAuditFlag = cli.StringFlag{
+		Name:  "rpc.denylist.audit",
+		Usage: "Path to the audit log of transactions rejected by the RPC deny list",
+	}
+	RPCHeadLagFlag = cli.Uint64Flag{
+		Name:  "rpc.headlag",
+		Usage: "Number of blocks the RPC head lags behind the actual head (for testing purposes only)",
+	}
+	RPCMaxStaleBlocksFlag = cli.Uint64Flag{
+		Name:  "rpc.maxstale",
+		Usage: "Maximum number of blocks the state served by the eth_*Stale methods may be behind the requested block (0 = disabled)",
+		Value: uint64(gossip.DefaultConfig(cachescale.Identity).RPCMaxStaleBlocks),
+	}
+	RPCAllowRollbackFlag = cli.BoolFlag{
+		Name:  "rpc.allowrollback",
+		Usage: "Allow debug_setHead to schedule a rollback of the node to an epoch checkpoint",
+	}
+	TxTracesPersistFlag = cli.BoolFlag{
+		Name:  "txtraces.persist",
+		Usage: "Trace transactions at import time and persist the traces for the trace API",
+	}
+	TxTracesRetentionFlag = cli.Uint64Flag{
+		Name:  "txtraces.retention",
+		Usage: "Number of recent blocks whose persisted transaction traces are kept (0 keeps all)",
+		Value: uint64(gossip.DefaultConfig(cachescale.Identity).TxTracesRetention),
+	}
+	ArchivedContractsFlag = cli.StringFlag{
+		Name:  "archive.contracts",
+		Usage: "Comma separated list of contract addresses to keep the historical storage for, the full archive of the state is disabled",
+	}
+	ReceiptsRetentionFlag = cli.Uint64Flag{
+		Name:  "receipts.retention",
+		Usage: "Number of recent epochs whose receipts, transaction positions and logs index are kept (0 keeps all)",
+	}
+	StateExpiryFlag = cli.Uint64Flag{
+		Name:  "statedb.expiry",
+		Usage: "Number of epochs, after which the untouched accounts and storage slots are counted as expired by the state expiry experiment (0 disables)",
+	}
+	RemoteLogsIndexFlag = cli.StringFlag{
+		Name:  "logindex.remote",
+		Usage: "RPC endpoint of an out-of-process log indexer ('sonictool logindexer') serving the logs queries instead of the local index",
+	}
+	BloomBitsFlag = cli.BoolFlag{
+		Name:  "bloombits",
+		Usage: "Enables the bloombits index of the blocks, which speeds up eth_getLogs over wide blocks ranges",
+	}
+	HistoryStartFlag = cli.Uint64Flag{
+		Name:  "history.start",
+		Usage: "First block, the transactions, receipts and logs of which are imported and served (0 keeps the whole history)",
+	}
+	HistoryAnchorFlag = cli.StringFlag{
+		Name:  "history.anchor",
+		Usage: "Hash of the --history.start block, which the imported chain is verified against",
+	}
+	NetworkFlag = cli.StringFlag{
+		Name:  "network",
+		Usage: `Network the datadir must belong to ("mainnet", "testnet" or "custom"), selects the default bootnodes`,
+	}
 	ModeFlag = cli.StringFlag{
 		Name:  "mode",
 		Usage: `Mode of the node ("rpc" or "validator")`,
@@ -324,6 +497,10 @@ var (
 		Name:  "exitwhensynced.epoch",
 		Usage: "Exits after synchronisation reaches the required epoch",
 	}
+	SkipIntegrityCheckFlag = cli.BoolFlag{
+		Name:  "integrity.skip",
+		Usage: "Skips the integrity check of the databases after an unclean shutdown",
+	}
 
 	// Validator
 	ValidatorIDFlag = cli.UintFlag{
@@ -341,4 +518,13 @@ var (
 		Usage: "Password to unlock validator private key",
 		Value: "",
 	}
+	ValidatorRewardRecipientFlag = cli.StringFlag{
+		Name:  "validator.rewardrecipient",
+		Usage: "Advisory recipient of the validator rewards, reported by eth_coinbase (rewards are paid by the SFC to the stake owner)",
+		Value: "",
+	}
+	ValidatorRewardRecipientAuditFlag = cli.StringFlag{
+		Name:  "validator.rewardrecipient.audit",
+		Usage: "Path to the audit log of the reward recipient changes made with admin_setRewardRecipient",
+	}
 )
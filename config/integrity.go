@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/integration/check"
+	"github.com/Fantom-foundation/go-opera/config/flags"
+	"github.com/Fantom-foundation/go-opera/integration"
+)
+
+// checkUncleanShutdown verifies the integrity of the datadir if the node wasn't shut down cleanly last time,
+// so the node doesn't start over inconsistent databases. The check may be skipped by a flag or by the operator.
+func checkUncleanShutdown(ctx *cli.Context, dataDir string) error {
+	if !integration.IsDirty(path.Join(dataDir, "chaindata")) {
+		return nil
+	}
+	log.Warn("The node wasn't shut down cleanly, the databases may be inconsistent")
+	if ctx.GlobalBool(flags.SkipIntegrityCheckFlag.Name) {
+		log.Warn("Integrity check is skipped", "flag", flags.SkipIntegrityCheckFlag.Name)
+		return nil
+	}
+	if isTerminal(os.Stdin) {
+		run, err := prompt.Stdin.PromptConfirm("Verify the databases before starting? It may take a while")
+		if err != nil {
+			return err
+		}
+		if !run {
+			log.Warn("Integrity check is skipped by the operator")
+			return nil
+		}
+	}
+
+	log.Info("Verifying the databases after the unclean shutdown")
+	cancelCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := check.CheckLiveStateDb(cancelCtx, dataDir, cacheScaler(ctx)); err != nil {
+		return fmt.Errorf("integrity check failed, repair the datadir with 'sonictool heal' or start with --%s to ignore: %w",
+			flags.SkipIntegrityCheckFlag.Name, err)
+	}
+	log.Info("Integrity check passed")
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/go-opera/utils/errlock"
+	"github.com/Fantom-foundation/go-opera/utils/rpcendpoints"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/log"
@@ -34,29 +35,92 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		return nil, nil, nil, err
 	}
 
-	engine, dagIndex, gdb, cdb, blockProc, closeDBs, err := integration.MakeEngine(path.Join(cfg.Node.DataDir, "chaindata"), cfg.AppConfigs())
+	// migrate the datadir of an older release, and refuse the datadir of a newer one
+	manifest, err := integration.MigrateDatadir(cfg.Node.DataDir)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to migrate the datadir layout: %w", err)
+	}
+	if manifest != nil && manifest.Network != "" && ctx.GlobalIsSet(flags.NetworkFlag.Name) {
+		name := ctx.GlobalString(flags.NetworkFlag.Name)
+		if network, ok := Networks[name]; ok && network.RulesName != "" && network.RulesName != manifest.Network {
+			return nil, nil, nil, fmt.Errorf("datadir belongs to network %q, expected %q of --%s=%s",
+				manifest.Network, network.RulesName, flags.NetworkFlag.Name, name)
+		}
+	}
+
+	// verify the databases if the previous run wasn't shut down cleanly
+	chaindataDir := path.Join(cfg.Node.DataDir, "chaindata")
+	wasDirty := integration.IsDirty(chaindataDir)
+	if wasDirty {
+		cfg.Opera.ScanApiTables = true
+	}
+	if err := checkUncleanShutdown(ctx, cfg.Node.DataDir); err != nil {
+		return nil, nil, nil, err
+	}
+
+	engine, dagIndex, gdb, cdb, blockProc, closeDBs, err := integration.MakeEngine(chaindataDir, cfg.AppConfigs())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to make consensus engine: %w", err)
 	}
+	if err := integration.SetDirty(chaindataDir); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to set the datadir dirty flag: %w", err)
+	}
+	var svc *gossip.Service
 	cleanup = append(cleanup, func() {
+		// the dirty flag is removed only if the final commit and the closing of all the DBs succeeded,
+		// if the node failed to start, the flag is restored as the node didn't run
+		clean := svc != nil && svc.StoppedCleanly()
+		if !success {
+			clean = !wasDirty
+		}
 		if err := gdb.Close(); err != nil {
 			log.Warn("Failed to close gossip store", "err", err)
+			clean = false
 		}
 		if err := cdb.Close(); err != nil {
 			log.Warn("Failed to close consensus database", "err", err)
+			clean = false
 		}
 		if closeDBs != nil {
 			if err := closeDBs(); err != nil {
 				log.Warn("Failed to close databases", "err", err)
+				clean = false
+			}
+		}
+		if clean {
+			if err := integration.SetClean(chaindataDir); err != nil {
+				log.Warn("Failed to clear the datadir dirty flag", "err", err)
 			}
 		}
 	})
 
-	// substitute default bootnodes if requested
+	// check the datadir belongs to the selected network
 	networkName := ""
 	if gdb.HasBlockEpochState() {
 		networkName = gdb.GetRules().Name
+		if manifest != nil && manifest.Network != "" && manifest.Network != networkName {
+			return nil, nil, nil, fmt.Errorf("datadir manifest network %q doesn't match the databases network %q", manifest.Network, networkName)
+		}
+		if err := integration.SetDatadirNetwork(cfg.Node.DataDir, networkName); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to write the datadir manifest: %w", err)
+		}
+	}
+	if ctx.GlobalIsSet(flags.NetworkFlag.Name) {
+		name := ctx.GlobalString(flags.NetworkFlag.Name)
+		network, ok := Networks[name]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("unknown network %q, expected one of %v", name, NetworkNames())
+		}
+		if gdb.HasBlockEpochState() {
+			if err := network.Check(gdb.GetRules(), gdb.GetGenesisID()); err != nil {
+				return nil, nil, nil, fmt.Errorf("datadir doesn't match --%s=%s: %w", flags.NetworkFlag.Name, name, err)
+			}
+		} else if name != CustomNetwork {
+			networkName = network.RulesName
+		}
 	}
+
+	// substitute default bootnodes if requested
 	if needDefaultBootnodes(cfg.Node.P2P.BootstrapNodes) {
 		bootnodes := Bootnodes[networkName]
 		if bootnodes == nil {
@@ -117,7 +181,7 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		}
 		return false
 	}
-	svc, err := gossip.NewService(stack, cfg.Opera, gdb, blockProc, engine, dagIndex, newTxPool, haltCheck)
+	svc, err = gossip.NewService(stack, cfg.Opera, gdb, blockProc, engine, dagIndex, newTxPool, haltCheck)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create the gossip service: %w", err)
 	}
@@ -140,7 +204,11 @@ func MakeNode(ctx *cli.Context, cfg *Config) (*node.Node, *gossip.Service, func(
 		svc.RegisterEmitter(emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer)))
 	}
 
-	stack.RegisterAPIs(svc.APIs())
+	apis := svc.APIs()
+	stack.RegisterAPIs(apis)
+	if err := rpcendpoints.Register(stack, apis, cfg.RPCEndpoints); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to register the RPC endpoints: %w", err)
+	}
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
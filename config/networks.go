@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// CustomNetwork is the name of the registry entry for networks which aren't known to the binary.
+const CustomNetwork = "custom"
+
+var (
+	MainnetGenesisID = hash.HexToHash("0x4a53c5445584b3bfc20dbfb2ec18ae20037c716f3ba2d9e1da768a9deca17cb4")
+	TestnetGenesisID = hash.HexToHash("0xc4a5fc96e575a16a9a0c7349d44dc4d0f602a54e0a8543360c2fee4c3937b49e")
+)
+
+// Network is an entry of the networks registry.
+type Network struct {
+	// RulesName is the network name in the network rules
+	RulesName string
+	NetworkID uint64
+	// GenesisID is nil if any genesis is accepted
+	GenesisID *hash.Hash
+}
+
+// Networks is the registry of the networks selectable by --network.
+var Networks = map[string]Network{
+	"mainnet": {
+		RulesName: "main",
+		NetworkID: opera.MainNetworkID,
+		GenesisID: &MainnetGenesisID,
+	},
+	"testnet": {
+		RulesName: "test",
+		NetworkID: opera.TestNetworkID,
+		GenesisID: &TestnetGenesisID,
+	},
+	CustomNetwork: {},
+}
+
+// NetworkNames returns the sorted names of the registered networks.
+func NetworkNames() []string {
+	names := make([]string, 0, len(Networks))
+	for name := range Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bootnodes returns the default bootnodes of the network.
+func (n Network) Bootnodes() []string {
+	return Bootnodes[n.RulesName]
+}
+
+// Check returns an error if the datadir with the given rules and genesis doesn't belong to the network.
+func (n Network) Check(rules opera.Rules, genesisID *hash.Hash) error {
+	if len(n.RulesName) == 0 {
+		// custom network, anything goes
+		return nil
+	}
+	if rules.Name != n.RulesName || rules.NetworkID != n.NetworkID {
+		return fmt.Errorf("datadir belongs to network %q (id %d), expected %q (id %d)", rules.Name, rules.NetworkID, n.RulesName, n.NetworkID)
+	}
+	if n.GenesisID != nil {
+		if genesisID == nil {
+			return fmt.Errorf("datadir has no genesis, expected %s", n.GenesisID.String())
+		}
+		if *genesisID != *n.GenesisID {
+			return fmt.Errorf("datadir genesis %s doesn't match the network genesis %s", genesisID.String(), n.GenesisID.String())
+		}
+	}
+	return nil
+}
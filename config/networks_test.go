@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+func TestNetworkCheck(t *testing.T) {
+	require := require.New(t)
+
+	mainnet := Networks["mainnet"]
+	rules := opera.MainNetRules()
+	genesisID := MainnetGenesisID
+	require.NoError(mainnet.Check(rules, &genesisID))
+
+	otherID := hash.HexToHash("0x01")
+	require.Error(mainnet.Check(rules, &otherID), "genesis mismatch")
+	require.Error(mainnet.Check(rules, nil), "no genesis")
+	require.Error(mainnet.Check(opera.TestNetRules(), &genesisID), "rules mismatch")
+
+	require.NoError(Networks[CustomNetwork].Check(opera.FakeNetRules(), nil))
+	require.Equal([]string{CustomNetwork, "mainnet", "testnet"}, NetworkNames())
+}
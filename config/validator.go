@@ -6,6 +6,7 @@ import (
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/integration/makefakegenesis"
@@ -45,6 +46,14 @@ func setValidator(ctx *cli.Context, cfg *emitter.Config) error {
 		cfg.Validator.PubKey = pk
 	}
 
+	if ctx.GlobalIsSet(flags.ValidatorRewardRecipientFlag.Name) {
+		addr := ctx.GlobalString(flags.ValidatorRewardRecipientFlag.Name)
+		if !common.IsHexAddress(addr) {
+			return errors.Errorf("invalid reward recipient address %q", addr)
+		}
+		cfg.Validator.RewardRecipient = common.HexToAddress(addr)
+	}
+
 	if cfg.Validator.ID != 0 && cfg.Validator.PubKey.Empty() {
 		return errors.New("validator public key is not set")
 	}
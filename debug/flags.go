@@ -23,6 +23,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -31,6 +32,8 @@ import (
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
 	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/logger"
 )
 
 var Memsize memsizeui.Handler
@@ -50,6 +53,37 @@ var (
 		Name:  "log.json",
 		Usage: "Format logs with JSON",
 	}
+	logFileFlag = cli.StringFlag{
+		Name:  "log.file",
+		Usage: "Write logs to the given file instead of the console",
+	}
+	logRotateFlag = cli.BoolFlag{
+		Name:  "log.rotate",
+		Usage: "Rotate the log file by size (--log.maxsize) and time (--log.rotate.interval)",
+	}
+	logMaxSizeFlag = cli.IntFlag{
+		Name:  "log.maxsize",
+		Usage: "Size in MB after which the log file is rotated, 0 disables the size based rotation",
+		Value: 100,
+	}
+	logRotateIntervalFlag = cli.DurationFlag{
+		Name:  "log.rotate.interval",
+		Usage: "Time after which the log file is rotated (e.g. 24h), 0 disables the time based rotation",
+	}
+	logMaxBackupsFlag = cli.IntFlag{
+		Name:  "log.maxbackups",
+		Usage: "Number of the rotated log files to keep, 0 keeps all of them",
+		Value: 10,
+	}
+	logMaxAgeFlag = cli.IntFlag{
+		Name:  "log.maxage",
+		Usage: "Number of days to keep the rotated log files for, 0 keeps them regardless of age",
+		Value: 30,
+	}
+	logCompressFlag = cli.BoolFlag{
+		Name:  "log.compress",
+		Usage: "Compress the rotated log files with gzip",
+	}
 	backtraceAtFlag = cli.StringFlag{
 		Name:  "log.backtrace",
 		Usage: "Request a stack trace at a specific logging statement (e.g. \"block.go:271\")",
@@ -97,6 +131,13 @@ var Flags = []cli.Flag{
 	verbosityFlag,
 	vmoduleFlag,
 	logjsonFlag,
+	logFileFlag,
+	logRotateFlag,
+	logMaxSizeFlag,
+	logRotateIntervalFlag,
+	logMaxBackupsFlag,
+	logMaxAgeFlag,
+	logCompressFlag,
 	backtraceAtFlag,
 	debugFlag,
 	pprofFlag,
@@ -108,7 +149,10 @@ var Flags = []cli.Flag{
 	traceFlag,
 }
 
-var glogger *log.GlogHandler
+var (
+	glogger *log.GlogHandler
+	logFile io.WriteCloser
+)
 
 func init() {
 	glogger = log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
@@ -121,7 +165,20 @@ func init() {
 func Setup(ctx *cli.Context) error {
 	var ostream log.Handler
 	output := io.Writer(os.Stderr)
-	if ctx.GlobalBool(logjsonFlag.Name) {
+	if path := ctx.GlobalString(logFileFlag.Name); path != "" {
+		file, err := openLogFile(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logFile = file
+	}
+	if logFile != nil {
+		format := log.TerminalFormat(false)
+		if ctx.GlobalBool(logjsonFlag.Name) {
+			format = log.JSONFormat()
+		}
+		ostream = log.StreamHandler(logFile, format)
+	} else if ctx.GlobalBool(logjsonFlag.Name) {
 		ostream = log.StreamHandler(output, log.JSONFormat())
 	} else {
 		usecolor := (isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())) && os.Getenv("TERM") != "dumb"
@@ -190,6 +247,20 @@ func Setup(ctx *cli.Context) error {
 	return nil
 }
 
+// openLogFile opens the log file, which is rotated if --log.rotate is set.
+func openLogFile(ctx *cli.Context, path string) (io.WriteCloser, error) {
+	if !ctx.GlobalBool(logRotateFlag.Name) {
+		return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	}
+	return logger.OpenRotatingFile(path, logger.RotationConfig{
+		MaxSize:    int64(ctx.GlobalInt(logMaxSizeFlag.Name)) * 1024 * 1024,
+		Interval:   ctx.GlobalDuration(logRotateIntervalFlag.Name),
+		MaxBackups: ctx.GlobalInt(logMaxBackupsFlag.Name),
+		MaxAge:     time.Duration(ctx.GlobalInt(logMaxAgeFlag.Name)) * 24 * time.Hour,
+		Compress:   ctx.GlobalBool(logCompressFlag.Name),
+	})
+}
+
 func StartPProf(address string, withMetrics bool) {
 	// Hook go-metrics into expvar on any /debug/metrics request, load all vars
 	// from the registry into expvar, and execute regular expvar handler.
@@ -210,4 +281,7 @@ func StartPProf(address string, withMetrics bool) {
 func Exit() {
 	Handler.StopCPUProfile()
 	Handler.StopGoTrace()
+	if logFile != nil {
+		logFile.Close()
+	}
 }
@@ -23,7 +23,7 @@ import (
 	"fmt"
 	"math/big"
 	"runtime/debug"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
@@ -51,8 +51,10 @@ import (
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
 )
@@ -103,7 +105,7 @@ var errInvalidPercentile = errors.New("invalid reward percentile")
 func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
 	res := &feeHistoryResult{}
 	res.Reward = make([][]*hexutil.Big, 0, blockCount)
-	res.BaseFee = make([]*hexutil.Big, 0, blockCount)
+	res.BaseFee = make([]*hexutil.Big, 0, blockCount+1)
 	res.GasUsedRatio = make([]float64, 0, blockCount)
 	res.OldestBlock = (*hexutil.Big)(new(big.Int))
 
@@ -127,25 +129,55 @@ func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.Decim
 		return nil, err
 	}
 	oldest := last
-	if oldest > idx.Block(blockCount) {
+	if oldest >= idx.Block(blockCount) {
 		oldest -= idx.Block(blockCount - 1)
 	} else {
 		oldest = 0
 	}
 
-	baseFee := s.b.MinGasPrice()
-
-	tips := make([]*hexutil.Big, 0, len(rewardPercentiles))
-	for _, p := range rewardPercentiles {
-		tip := s.b.SuggestGasTipCap(ctx, uint64(gasprice.DecimalUnit*p/100.0))
-		tips = append(tips, (*hexutil.Big)(tip))
+	// the estimated fees are reported for the blocks without a fee history record
+	var estimatedTips []*hexutil.Big
+	estimateTips := func() []*hexutil.Big {
+		if estimatedTips == nil {
+			estimatedTips = make([]*hexutil.Big, 0, len(rewardPercentiles))
+			for _, p := range rewardPercentiles {
+				tip := s.b.SuggestGasTipCap(ctx, uint64(gasprice.DecimalUnit*p/100.0))
+				estimatedTips = append(estimatedTips, (*hexutil.Big)(tip))
+			}
+		}
+		return estimatedTips
 	}
+
 	res.OldestBlock.ToInt().SetUint64(uint64(oldest))
-	for i := uint64(0); i < uint64(last-oldest+1); i++ {
-		res.Reward = append(res.Reward, tips)
-		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(baseFee))
-		res.GasUsedRatio = append(res.GasUsedRatio, 0.99)
+	for n := oldest; n <= last; n++ {
+		h, err := s.b.GetBlockFeeHistory(ctx, rpc.BlockNumber(n))
+		if err != nil {
+			return nil, err
+		}
+		if h == nil {
+			if len(rewardPercentiles) != 0 {
+				res.Reward = append(res.Reward, estimateTips())
+			}
+			res.BaseFee = append(res.BaseFee, (*hexutil.Big)(s.b.MinGasPrice()))
+			res.GasUsedRatio = append(res.GasUsedRatio, 0.99)
+			continue
+		}
+		if len(rewardPercentiles) != 0 {
+			rewards := make([]*hexutil.Big, len(rewardPercentiles))
+			for i, p := range rewardPercentiles {
+				rewards[i] = (*hexutil.Big)(h.Reward(p))
+			}
+			res.Reward = append(res.Reward, rewards)
+		}
+		res.BaseFee = append(res.BaseFee, (*hexutil.Big)(h.BaseFee))
+		res.GasUsedRatio = append(res.GasUsedRatio, h.GasUsedRatio())
 	}
+	// the base fee of the block after the last one
+	nextBaseFee := s.b.MinGasPrice()
+	if next, _ := s.b.HeaderByNumber(ctx, rpc.BlockNumber(last+1)); next != nil && next.BaseFee != nil {
+		nextBaseFee = next.BaseFee
+	}
+	res.BaseFee = append(res.BaseFee, (*hexutil.Big)(nextBaseFee))
 	return res, nil
 }
 
@@ -197,7 +229,7 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range pending {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader.BaseFee)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader.BaseFee)
 		}
 		content["pending"][account.Hex()] = dump
 	}
@@ -205,7 +237,7 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range queue {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader.BaseFee)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader.BaseFee)
 		}
 		content["queued"][account.Hex()] = dump
 	}
@@ -221,14 +253,14 @@ func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string
 	// Build the pending transactions
 	dump := make(map[string]*RPCTransaction, len(pending))
 	for _, tx := range pending {
-		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader.BaseFee)
+		dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader.BaseFee)
 	}
 	content["pending"] = dump
 
 	// Build the queued transactions
 	dump = make(map[string]*RPCTransaction, len(queue))
 	for _, tx := range queue {
-		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader.BaseFee)
+		dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader.BaseFee)
 	}
 	content["queued"] = dump
 
@@ -480,7 +512,10 @@ func (s *PrivateAccountAPI) signTransaction(ctx context.Context, args *Transacti
 // SendTransaction will create a transaction from the given arguments and
 // tries to sign it with the key associated with args.From. If the given
 // passwd isn't able to decrypt the key it fails.
-func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args TransactionArgs, passwd string) (common.Hash, error) {
+func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args TransactionArgs, passwd string) (_ common.Hash, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "personal_sendTransaction", time.Now(), &err)
+
 	if args.Nonce == nil {
 		// Hold the addresse's mutex around signing to prevent concurrent assignment of
 		// the same nonce to multiple accounts.
@@ -499,7 +534,10 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args Transactio
 // tries to sign it with the key associated with args.From. If the given passwd isn't
 // able to decrypt the key it fails. The transaction is returned in RLP-form, not broadcast
 // to other nodes
-func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args TransactionArgs, passwd string) (*SignTransactionResult, error) {
+func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args TransactionArgs, passwd string) (_ *SignTransactionResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "personal_signTransaction", time.Now(), &err)
+
 	// No need to obtain the noncelock mutex, since we won't be sending this
 	// tx into the transaction pool, but right back to the user
 	if args.From == nil {
@@ -690,8 +728,8 @@ func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
 // GetBalance returns the amount of wei for the given address in the state of the
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
-func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash BlockNumberOrHash) (*hexutil.Big, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -699,6 +737,37 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
 }
 
+// AccountInfo is result struct for GetAccounts
+type AccountInfo struct {
+	Address  common.Address `json:"address"`
+	Balance  *hexutil.Big   `json:"balance"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	CodeHash common.Hash    `json:"codeHash"`
+}
+
+// GetAccounts returns balances, nonces and code hashes of the given addresses in the state of the
+// given block number. The number of addresses is limited by the node configuration.
+func (s *PublicBlockChainAPI) GetAccounts(ctx context.Context, addresses []common.Address, blockNrOrHash BlockNumberOrHash) ([]*AccountInfo, error) {
+	if limit := s.b.RPCAccountsLimit(); limit > 0 && len(addresses) > limit {
+		return nil, fmt.Errorf("too many addresses: %d (limit %d)", len(addresses), limit)
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
+	if state == nil || err != nil {
+		return nil, err
+	}
+	defer state.Release()
+	res := make([]*AccountInfo, len(addresses))
+	for i, address := range addresses {
+		res[i] = &AccountInfo{
+			Address:  address,
+			Balance:  (*hexutil.Big)(state.GetBalance(address)),
+			Nonce:    hexutil.Uint64(state.GetNonce(address)),
+			CodeHash: state.GetCodeHash(address),
+		}
+	}
+	return res, state.Error()
+}
+
 // AccountResult is result struct for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -718,29 +787,36 @@ type StorageResult struct {
 }
 
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
-func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash BlockNumberOrHash) (*AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
 	if state == nil || err != nil {
 		return nil, err
 	}
 	defer state.Release()
+	return accountProof(state, address, storageKeys)
+}
 
-	storageTrie := state.StorageTrie(address)
+// accountProof creates the Merkle-proof for the account and the storage keys in the state.
+func accountProof(state state.StateDB, address common.Address, storageKeys []string) (*AccountResult, error) {
 	storageHash := types.EmptyRootHash
 	codeHash := state.GetCodeHash(address)
 	storageProof := make([]StorageResult, len(storageKeys))
 
-	// if we have a storageTrie, (which means the account exists), we can update the storagehash
-	if storageTrie != nil {
-		storageHash = storageTrie.Hash()
+	exists := state.Exist(address)
+	if exists {
+		root, err := state.GetStorageRoot(address)
+		if err != nil {
+			return nil, err
+		}
+		storageHash = root
 	} else {
-		// no storageTrie means the account does not exist, so the codeHash is the hash of an empty bytearray.
+		// the account does not exist, so the codeHash is the hash of an empty bytearray.
 		codeHash = crypto.Keccak256Hash(nil)
 	}
 
 	// create the proof for the storageKeys
 	for i, key := range storageKeys {
-		if storageTrie != nil {
+		if exists {
 			proof, storageError := state.GetStorageProof(address, common.HexToHash(key))
 			if storageError != nil {
 				return nil, storageError
@@ -771,7 +847,8 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 // GetHeaderByNumber returns the requested canonical block header.
 // * When blockNr is -1 the chain head is returned.
 // * When blockNr is -2 the pending chain head is returned.
-func (s *PublicBlockChainAPI) GetHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
+func (s *PublicBlockChainAPI) GetHeaderByNumber(ctx context.Context, blockNr BlockNumber) (map[string]interface{}, error) {
+	number := blockNr.Rpc()
 	header, err := s.b.HeaderByNumber(ctx, number)
 	if header != nil && err == nil {
 		response := s.rpcMarshalHeader(header, s.calculateExtBlockApi(ctx, number))
@@ -817,7 +894,8 @@ func (s *PublicBlockChainAPI) calculateExtBlockApi(ctx context.Context, blkNumbe
 //   - When blockNr is -2 the pending chain head is returned.
 //   - When fullTx is true all transactions in the block are returned, otherwise
 //     only the transaction hash is returned.
-func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	number := blockNr.Rpc()
 	block, err := s.b.BlockByNumber(ctx, number)
 	if block != nil && err == nil {
 		response, err := s.rpcMarshalBlock(block, s.calculateExtBlockApi(ctx, number), true, fullTx)
@@ -883,8 +961,8 @@ func (s *PublicBlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, bloc
 }
 
 // GetCode returns the code stored at the given address in the state for the given block number.
-func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash BlockNumberOrHash) (hexutil.Bytes, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -896,8 +974,8 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 // GetStorageAt returns the storage from the state at the given address, key and
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
-func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNr rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNr)
+func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNr BlockNumberOrHash) (hexutil.Bytes, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNr.Rpc())
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -958,17 +1036,68 @@ func (diff *StateOverride) Apply(state state.StateDB) error {
 	return nil
 }
 
-func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+// BlockOverrides is a set of the block header fields to override during the execution of a message call.
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number"`
+	Time     *hexutil.Uint64 `json:"time"`
+	GasLimit *hexutil.Uint64 `json:"gasLimit"`
+	Coinbase *common.Address `json:"coinbase"`
+	BaseFee  *hexutil.Big    `json:"baseFee"`
+}
+
+// Apply returns a copy of the header with the overridden fields.
+func (diff *BlockOverrides) Apply(header *evmcore.EvmHeader) *evmcore.EvmHeader {
+	if diff == nil {
+		return header
+	}
+	cpy := *header
+	if diff.Number != nil {
+		cpy.Number = new(big.Int).Set(diff.Number.ToInt())
+	}
+	if diff.Time != nil {
+		cpy.Time = inter.FromUnix(int64(*diff.Time))
+	}
+	if diff.GasLimit != nil {
+		cpy.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		cpy.Coinbase = *diff.Coinbase
+	}
+	if diff.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(diff.BaseFee.ToInt())
+	}
+	return &cpy
+}
+
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
+	defer func(start time.Time) {
+		TraceLogger(ctx).Debug("Executing EVM call finished", "runtime", time.Since(start))
+	}(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
 	defer state.Release()
+	if err := checkExecutable(state); err != nil {
+		return nil, err
+	}
 	if err := overrides.Apply(state); err != nil {
 		return nil, err
 	}
+	return doCallAtState(ctx, b, args, state, blockOverrides.Apply(header), timeout, globalGasCap)
+}
+
+// checkExecutable refuses the EVM execution over a state, which keeps only a part of the state.
+func checkExecutable(statedb state.StateDB) error {
+	if partial, ok := statedb.(state.PartialStateDB); ok {
+		return partial.ExecutionError()
+	}
+	return nil
+}
+
+// doCallAtState executes the call on top of the given state, which is modified by the call.
+func doCallAtState(ctx context.Context, b Backend, args TransactionArgs, state state.StateDB, header *evmcore.EvmHeader, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -988,6 +1117,7 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 	}
 	vmConfig := opera.DefaultVMConfig
 	vmConfig.NoBaseFee = true
+	limits := withExecutionLimits(b, &vmConfig)
 	evm, vmError, err := b.GetEVM(ctx, msg, state, header, &vmConfig)
 	if err != nil {
 		return nil, err
@@ -1008,6 +1138,9 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 	if err := state.Error(); err != nil {
 		return nil, fmt.Errorf("StateDB error: %w", err)
 	}
+	if err := limits.Err(); err != nil {
+		return nil, err
+	}
 
 	// If the timer caused an abort, return an appropriate error message
 	if evm.Cancelled() {
@@ -1051,12 +1184,16 @@ func (e *revertError) ErrorData() interface{} {
 
 // Call executes the given transaction on the state for the given block number.
 //
-// Additionally, the caller can specify a batch of contract for fields overriding.
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// and the fields of the block header.
 //
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) (_ hexutil.Bytes, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_call", time.Now(), &err)
+
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash.Rpc(), overrides, blockOverrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -1068,7 +1205,7 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, bl
 }
 
 // DoEstimateGas - binary search the gas requirement, as it may be higher than the amount used
-func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1103,6 +1240,12 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 			return 0, err
 		}
 		defer state.Release()
+		if err := checkExecutable(state); err != nil {
+			return 0, err
+		}
+		if err := overrides.Apply(state); err != nil {
+			return 0, err
+		}
 		balance := state.GetBalance(*args.From) // from can't be nil
 		available := new(big.Int).Set(balance)
 		if args.Value != nil {
@@ -1119,14 +1262,14 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 			if transfer == nil {
 				transfer = new(hexutil.Big)
 			}
-			log.Warn("Gas estimation capped by limited funds", "original", hi, "balance", balance,
+			TraceLogger(ctx).Warn("Gas estimation capped by limited funds", "original", hi, "balance", balance,
 				"sent", transfer.ToInt(), "maxFeePerGas", feeCap, "fundable", allowance)
 			hi = allowance.Uint64()
 		}
 	}
 	// Recap the highest gas allowance with specified gascap.
 	if gasCap != 0 && hi > gasCap {
-		log.Warn("Caller gas above allowance, capping", "requested", hi, "cap", gasCap)
+		TraceLogger(ctx).Warn("Caller gas above allowance, capping", "requested", hi, "cap", gasCap)
 		hi = gasCap
 	}
 	cap = hi
@@ -1135,7 +1278,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	executable := func(gas uint64) (bool, *evmcore.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, 0, gasCap)
+		result, err := DoCall(ctx, b, args, blockNrOrHash, overrides, blockOverrides, 0, gasCap)
 		if err != nil {
 			if errors.Is(err, evmcore.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -1182,13 +1325,16 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block, optionally with the overridden state and block fields.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) (_ hexutil.Uint64, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_estimateGas", time.Now(), &err)
+
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 	if blockNrOrHash != nil {
-		bNrOrHash = *blockNrOrHash
+		bNrOrHash = blockNrOrHash.Rpc()
 	}
-	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+	return DoEstimateGas(ctx, s.b, args, bNrOrHash, overrides, blockOverrides, s.b.RPCGasCap())
 }
 
 // ExecutionResult groups all structured logs emitted by the EVM
@@ -1433,8 +1579,8 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 	return result
 }
 
-// newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
-func newRPCPendingTransaction(tx *types.Transaction, baseFee *big.Int) *RPCTransaction {
+// NewRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
+func NewRPCPendingTransaction(tx *types.Transaction, baseFee *big.Int) *RPCTransaction {
 	return newRPCTransaction(tx, common.Hash{}, 0, 0, baseFee)
 }
 
@@ -1478,10 +1624,13 @@ type accessListResult struct {
 
 // CreateAccessList creates a EIP-2930 type AccessList for the given transaction.
 // Reexec and BlockNrOrHash can be specified to create the accessList on top of a certain state.
-func (s *PublicBlockChainAPI) CreateAccessList(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*accessListResult, error) {
+func (s *PublicBlockChainAPI) CreateAccessList(ctx context.Context, args TransactionArgs, blockNrOrHash *BlockNumberOrHash) (_ *accessListResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_createAccessList", time.Now(), &err)
+
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
-		bNrOrHash = *blockNrOrHash
+		bNrOrHash = blockNrOrHash.Rpc()
 	}
 	acl, gasUsed, vmerr, err := AccessList(ctx, s.b, bNrOrHash, args)
 	if err != nil {
@@ -1592,8 +1741,8 @@ func NewPublicTransactionPoolAPI(b Backend, nonceLock *AddrLocker) *PublicTransa
 }
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
-func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
-	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
+func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr BlockNumber) *hexutil.Uint {
+	if block, _ := s.b.BlockByNumber(ctx, blockNr.Rpc()); block != nil {
 		n := hexutil.Uint(len(block.Transactions))
 		return &n
 	}
@@ -1610,8 +1759,8 @@ func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Co
 }
 
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
-func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
-	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
+func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr BlockNumber, index hexutil.Uint) *RPCTransaction {
+	if block, _ := s.b.BlockByNumber(ctx, blockNr.Rpc()); block != nil {
 		return newRPCTransactionFromBlockIndex(block, uint64(index))
 	}
 	return nil
@@ -1626,8 +1775,8 @@ func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context
 }
 
 // GetRawTransactionByBlockNumberAndIndex returns the bytes of the transaction for the given block number and index.
-func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) hexutil.Bytes {
-	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
+func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr BlockNumber, index hexutil.Uint) hexutil.Bytes {
+	if block, _ := s.b.BlockByNumber(ctx, blockNr.Rpc()); block != nil {
 		return newRPCRawTransactionFromBlockIndex(block, uint64(index))
 	}
 	return nil
@@ -1642,7 +1791,8 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockHashAndIndex(ctx cont
 }
 
 // GetTransactionCount returns the number of transactions the given address has sent for the given block number
-func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr BlockNumberOrHash) (*hexutil.Uint64, error) {
+	blockNrOrHash := blockNr.Rpc()
 	// Ask transaction pool for the nonce which includes pending transactions
 	if blockNr, ok := blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
 		nonce, err := s.b.GetPoolNonce(ctx, address)
@@ -1677,7 +1827,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
-		return newRPCPendingTransaction(tx, s.b.MinGasPrice()), nil
+		return NewRPCPendingTransaction(tx, s.b.MinGasPrice()), nil
 	}
 
 	// Transaction unknown, return as such
@@ -1765,35 +1915,30 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if header == nil || err != nil {
 		return nil, err
 	}
-	receipts, err := s.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(blockNumber))
-	if receipts == nil || err != nil {
+	receipt, err := s.b.GetReceipt(ctx, idx.Block(blockNumber), header.Hash, index, tx)
+	if receipt == nil || err != nil {
 		return nil, err
 	}
-	if receipts.Len() <= int(index) {
-		return nil, nil
-	}
-	return s.formatTxReceipt(header, tx, index, receipts[index]), nil
+	return s.formatTxReceipt(header, tx, index, receipt), nil
 }
 
 // GetBlockReceipts returns a set of transaction receipts for the given block by the extended block number.
-func (s *PublicTransactionPoolAPI) GetBlockReceipts(ctx context.Context, number rpc.BlockNumber) ([]map[string]interface{}, error) {
-	header, err := s.b.HeaderByNumber(ctx, number)
-	if header == nil || err != nil {
+// The receipts of the block are loaded at once, and the txs are taken from the block instead of the txs index,
+// so the method is cheaper than eth_getTransactionReceipt for each transaction of the block.
+// The block and the receipts are read from a consistent view of the DB.
+func (s *PublicTransactionPoolAPI) GetBlockReceipts(ctx context.Context, blockNr BlockNumber) ([]map[string]interface{}, error) {
+	block, receipts, err := s.b.BlockAndReceiptsByNumber(ctx, blockNr.Rpc())
+	if block == nil || receipts == nil || err != nil {
 		return nil, err
 	}
-
-	receipts, err := s.b.GetReceiptsByNumber(ctx, number)
-	if receipts == nil || err != nil {
-		return nil, err
+	header := block.Header()
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("receipts of block %d don't match its transactions", header.Number.Uint64())
 	}
 
 	blkReceipts := make([]map[string]interface{}, len(receipts))
 	for i, receipt := range receipts {
-		tx, _, index, err := s.b.GetTransaction(ctx, receipt.TxHash)
-		if err != nil {
-			return nil, err
-		}
-		blkReceipts[i] = s.formatTxReceipt(header, tx, index, receipt)
+		blkReceipts[i] = s.formatTxReceipt(header, block.Transactions[i], uint64(i), receipt)
 	}
 
 	return blkReceipts, nil
@@ -1843,7 +1988,10 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 
 // SendTransaction creates a transaction for the given argument, sign it and submit it to the
 // transaction pool.
-func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args TransactionArgs) (common.Hash, error) {
+func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args TransactionArgs) (_ common.Hash, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_sendTransaction", time.Now(), &err)
+
 	// Look up the wallet containing the requested signer
 	account := accounts.Account{Address: args.from()}
 
@@ -1876,7 +2024,10 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Tra
 // FillTransaction fills the defaults (nonce, gas, gasPrice or 1559 fields)
 // on a given unsigned transaction, and returns it to the caller for further
 // processing (signing + broadcast).
-func (s *PublicTransactionPoolAPI) FillTransaction(ctx context.Context, args TransactionArgs) (*SignTransactionResult, error) {
+func (s *PublicTransactionPoolAPI) FillTransaction(ctx context.Context, args TransactionArgs) (_ *SignTransactionResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_fillTransaction", time.Now(), &err)
+
 	// Set some sanity defaults and terminate on failure
 	if err := args.setDefaults(ctx, s.b); err != nil {
 		return nil, err
@@ -1934,7 +2085,10 @@ type SignTransactionResult struct {
 // SignTransaction will sign the given transaction with the from account.
 // The node needs to have the private key of the account corresponding with
 // the given from address and it needs to be unlocked.
-func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args TransactionArgs) (*SignTransactionResult, error) {
+func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args TransactionArgs) (_ *SignTransactionResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_signTransaction", time.Now(), &err)
+
 	if args.Gas == nil {
 		return nil, fmt.Errorf("gas not specified")
 	}
@@ -1980,7 +2134,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		from, _ := internaltx.Sender(s.signer, tx)
 		if _, exists := accounts[from]; exists {
-			transactions = append(transactions, newRPCPendingTransaction(tx, s.b.MinGasPrice()))
+			transactions = append(transactions, NewRPCPendingTransaction(tx, s.b.MinGasPrice()))
 		}
 	}
 	return transactions, nil
@@ -1988,7 +2142,10 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 
 // Resend accepts an existing transaction and a new gas price and limit. It will remove
 // the given transaction from the pool and reinsert it with the new gas price and limit.
-func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs TransactionArgs, gasPrice *hexutil.Big, gasLimit *hexutil.Uint64) (common.Hash, error) {
+func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs TransactionArgs, gasPrice *hexutil.Big, gasLimit *hexutil.Uint64) (_ common.Hash, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_resend", time.Now(), &err)
+
 	if sendArgs.Nonce == nil {
 		return common.Hash{}, fmt.Errorf("missing transaction nonce in transaction spec")
 	}
@@ -2126,11 +2283,16 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// TracerConfig is the config of a native tracer, e.g. {"onlyTopCall": true} of the callTracer
+	TracerConfig json.RawMessage
 }
 
 // TraceTransaction returns the structured logs created during the execution of EVM
 // and returns them as a JSON object.
-func (api *PublicDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+func (api *PublicDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (_ interface{}, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "debug_traceTransaction", time.Now(), &err)
+
 	tx, blockNumber, index, err := api.b.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, err
@@ -2164,6 +2326,43 @@ func (api *PublicDebugAPI) TraceTransaction(ctx context.Context, hash common.Has
 	return api.traceTx(ctx, msg, txctx, block.Header(), statedb, config)
 }
 
+// TraceCallConfig is the config of the traced call, which may override the state it's executed on.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *StateOverride
+}
+
+// TraceCall returns the structured logs created during the execution of EVM
+// if the given transaction was added on top of the provided block,
+// and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceCall(ctx context.Context, args TransactionArgs, blockNrOrHash BlockNumberOrHash, config *TraceCallConfig) (_ interface{}, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "debug_traceCall", time.Now(), &err)
+
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+
+	var traceConfig *TraceConfig
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		traceConfig = &config.TraceConfig
+	}
+	msg, err := args.ToMessage(api.b.RPCGasCap(), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	txctx := &tracers.Context{
+		BlockHash: header.Hash,
+	}
+	return api.traceTx(ctx, msg, txctx, header, statedb, traceConfig)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
@@ -2171,15 +2370,16 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 
 	defer func() {
 		if r := recover(); r != nil {
-			log.Error("debug trace transaction failed", "reason", r, "stack", string(debug.Stack()))
+			TraceLogger(ctx).Error("debug trace transaction failed", "reason", r, "stack", string(debug.Stack()))
 			reterr = fmt.Errorf("debug trace transaction failed with reason: %v", r)
 		}
 	}()
 
 	// Assemble the structured logger or the JavaScript tracer
 	var (
-		tracer vm.Tracer
-		err    error
+		tracer  vm.Tracer
+		err     error
+		execCtx = ctx
 	)
 
 	switch {
@@ -2197,11 +2397,25 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 		if rpcTimeout := api.b.RPCEVMTimeout(); rpcTimeout != 0 && rpcTimeout < timeout {
 			timeout = rpcTimeout
 		}
-		t, err := tracers.New(*config.Tracer, txctx)
+		// The built-in tracers implemented natively are preferred to their JS versions
+		var t interface {
+			vm.Tracer
+			Stop(err error)
+		}
+		native, ok, err := txtrace.NewNativeTracer(*config.Tracer, config.TracerConfig)
 		if err != nil {
 			return nil, err
 		}
-		defer t.Destroy()
+		if ok {
+			t = native
+		} else {
+			js, err := tracers.New(*config.Tracer, txctx)
+			if err != nil {
+				return nil, err
+			}
+			defer js.Destroy()
+			t = js
+		}
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
@@ -2211,6 +2425,7 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 		}()
 		defer cancel()
 		tracer = t
+		execCtx = deadlineCtx
 
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
@@ -2222,28 +2437,29 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 	evmconfig.Debug = true
 	evmconfig.NoBaseFee = true
 	evmconfig.InterpreterImpl = "geth" // use always geth, as lfvm does not support tracing now
+	limits := withExecutionLimits(api.b, &evmconfig)
 	vmenv, _, err := api.b.GetEVM(ctx, message, statedb, blockHeader, &evmconfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get EVM for tracing: %w", err)
 	}
 
-	// Wait for the context to be done and cancel the evm. Even if the
-	// EVM has finished, cancelling may be done (repeatedly)
-	go func() {
-		<-ctx.Done()
-		vmenv.Cancel()
-	}()
-
 	// Call Prepare to clear out the statedb access list
 	statedb.Prepare(txctx.TxHash, txctx.TxIndex)
 
-	result, err := evmcore.ApplyMessage(vmenv, message, new(evmcore.GasPool).AddGas(message.Gas()))
+	var result *evmcore.ExecutionResult
+	err = api.b.TraceSandbox().Run(execCtx, vmenv, func() (err error) {
+		result, err = evmcore.ApplyMessage(vmenv, message, new(evmcore.GasPool).AddGas(message.Gas()))
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
 	if err := statedb.Error(); err != nil {
 		return nil, fmt.Errorf("StateDB error while tracing tx %s: %w", txctx.TxHash, err)
 	}
+	if err := limits.Err(); err != nil {
+		return nil, err
+	}
 
 	// Depending on the tracer type, format and return the output.
 	switch tracer := tracer.(type) {
@@ -2267,30 +2483,13 @@ func (api *PublicDebugAPI) traceTx(ctx context.Context, message evmcore.Message,
 
 	case *tracers.Tracer:
 		result, err := tracer.GetResult()
-		if err != nil && result == nil {
-			// Only for tracer called callTracer
-			if config.Tracer != nil && strings.Compare(*config.Tracer, "callTracer") == 0 {
-				if strings.Contains(err.Error(), "cannot read property 'toString' of undefined") {
-					log.Debug("error when debug with callTracer", "err", err.Error())
-					callTracer, errTracer := tracers.New(*config.Tracer, txctx)
-					if errTracer != nil {
-						return nil, errTracer
-					}
-					defer callTracer.Destroy()
-					if message == nil || vmenv == nil {
-						return nil, err
-					}
-					toAddress := message.To()
-					if toAddress == nil {
-						toAddress = &common.Address{}
-					}
-					callTracer.CaptureStart(vmenv, message.From(), *toAddress, false, message.Data(), message.Gas(), message.Value())
-					callTracer.CaptureEnd([]byte{}, message.Gas(), time.Duration(0), fmt.Errorf("execution reverted"))
-					return callTracer.GetResult()
-				}
-			}
+		if responseSizeLimit > 0 && len(result) > responseSizeLimit {
+			return nil, ErrMaxResponseSize
 		}
+		return result, err
 
+	case txtrace.NativeTracer:
+		result, err := tracer.GetResult()
 		if responseSizeLimit > 0 && len(result) > responseSizeLimit {
 			return nil, ErrMaxResponseSize
 		}
@@ -2310,7 +2509,10 @@ type txTraceResult struct {
 
 // TraceBlockByNumber returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
-func (api *PublicDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+func (api *PublicDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) (_ []*txTraceResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "debug_traceBlockByNumber", time.Now(), &err)
+
 	block, err := api.b.BlockByNumber(ctx, number)
 	if err != nil {
 		return nil, err
@@ -2323,7 +2525,10 @@ func (api *PublicDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.Bl
 
 // TraceBlockByHash returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
-func (api *PublicDebugAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+func (api *PublicDebugAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) (_ []*txTraceResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "debug_traceBlockByHash", time.Now(), &err)
+
 	block, err := api.b.BlockByHash(ctx, hash)
 	if err != nil {
 		return nil, err
@@ -2402,7 +2607,11 @@ func (api *PublicDebugAPI) stateAtTransaction(ctx context.Context, block *evmcor
 			return msg, nil, err
 		}
 		statedb.Prepare(tx.Hash(), idx)
-		if _, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(tx.Gas())); err != nil {
+		err = api.b.TraceSandbox().Run(ctx, vmenv, func() error {
+			_, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(tx.Gas()))
+			return err
+		})
+		if err != nil {
 			statedb.Release()
 			return nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
@@ -2421,12 +2630,20 @@ func (api *PublicDebugAPI) stateAtTransaction(ctx context.Context, block *evmcor
 // debugging endpoint.
 type PrivateDebugAPI struct {
 	b Backend
+
+	rollbackMu      sync.Mutex
+	pendingRollback *pendingRollback
+
+	sessions *StateSessions
 }
 
 // NewPrivateDebugAPI creates a new API definition for the private debug methods
 // of the Ethereum service.
-func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
-	return &PrivateDebugAPI{b: b}
+func NewPrivateDebugAPI(b Backend, sessions *StateSessions) *PrivateDebugAPI {
+	return &PrivateDebugAPI{
+		b:        b,
+		sessions: sessions,
+	}
 }
 
 // ChaindbProperty returns leveldb properties of the key-value database.
@@ -2440,20 +2657,15 @@ func (api *PrivateDebugAPI) ChaindbCompact() error {
 	return errors.New("carmen state database does not use compaction")
 }
 
-// SetHead rewinds the head of the blockchain to a previous block.
-func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) error {
-	return errors.New("lachesis cannot rewind blocks due to the BFT algorithm")
-}
-
 // PublicNetAPI offers network related RPC methods
 type PublicNetAPI struct {
-	net            *p2p.Server
-	networkVersion uint64
+	net *p2p.Server
+	b   Backend
 }
 
 // NewPublicNetAPI creates a new net API instance.
-func NewPublicNetAPI(net *p2p.Server, networkVersion uint64) *PublicNetAPI {
-	return &PublicNetAPI{net, networkVersion}
+func NewPublicNetAPI(net *p2p.Server, b Backend) *PublicNetAPI {
+	return &PublicNetAPI{net, b}
 }
 
 // Listening returns an indication if the node is listening for network connections.
@@ -2466,9 +2678,9 @@ func (s *PublicNetAPI) PeerCount() hexutil.Uint {
 	return hexutil.Uint(s.net.PeerCount())
 }
 
-// Version returns the current ethereum protocol version.
+// Version returns the current network ID, which is the same as the chain ID.
 func (s *PublicNetAPI) Version() string {
-	return fmt.Sprintf("%d", s.networkVersion)
+	return s.b.ChainConfig().ChainID.String()
 }
 
 // checkTxFee is an internal function used to check whether the fee of
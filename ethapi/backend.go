@@ -36,6 +36,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -59,9 +60,16 @@ type Backend interface {
 	ExtRPCEnabled() bool
 	RPCGasCap() uint64            // global gas cap for eth_call over rpc: DoS protection
 	RPCEVMTimeout() time.Duration // global timeout for eth_call over rpc: DoS protection
+	RPCEVMMemoryLimit() uint64    // global memory limit of eth_call and tracing over rpc (0 = no limit)
+	RPCReturnDataLimit() uint64   // global return data limit of eth_call and tracing over rpc (0 = no limit)
 	RPCTxFeeCap() float64         // global tx fee cap for all transaction related APIs
+	TraceSandbox() *TraceSandbox  // pool of goroutines re-executing transactions for tracing
 	UnprotectedAllowed() bool     // allows only for EIP155 transactions.
 	CalcBlockExtApi() bool
+	RPCAccountsLimit() int       // maximum number of addresses in eth_getAccounts (0 = no limit)
+	RPCBalanceHistoryLimit() int // maximum number of blocks in eth_getBalanceHistory (0 = no limit)
+	RPCProofsLimit() int         // maximum number of proofs in eth_getProofs (0 = no limit)
+	RPCMaxStaleBlocks() uint64   // maximum number of blocks a stale state may be behind the requested one (0 = disabled)
 
 	// Blockchain API
 	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error)
@@ -71,6 +79,16 @@ type Backend interface {
 	ResolveRpcBlockNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (idx.Block, error)
 	BlockByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmBlock, error)
 	GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error)
+	BlockAndReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, types.Receipts, error)
+	GetReceipt(ctx context.Context, block idx.Block, hash common.Hash, index uint64, tx *types.Transaction) (*types.Receipt, error)
+	GetTxTraces(ctx context.Context, block idx.Block, txHash common.Hash) (*[]txtrace.ActionTrace, error)
+	GetBlockFees(ctx context.Context, number rpc.BlockNumber) (*iblockproc.FeeStats, error)
+	GetEpochFees(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.FeeStats, error)
+	GetBlockFeeHistory(ctx context.Context, number rpc.BlockNumber) (*evmcore.BlockFeeHistory, error)
+	GetContractCreation(ctx context.Context, addr common.Address) (*ContractCreation, error)
+	GetCodeHistory(ctx context.Context, addr common.Address) ([]CodeHistoryEvent, error)
+	GetBalanceChanges(ctx context.Context, addr common.Address, from, to idx.Block) ([]BalanceAtBlock, error)
+	GetTxsBySender(ctx context.Context, sender common.Address, from, to idx.Block, limit int) ([]SenderTx, error)
 	GetTd(hash common.Hash) *big.Int
 	GetEVM(ctx context.Context, msg evmcore.Message, state vm.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error)
 	MinGasPrice() *big.Int
@@ -81,6 +99,7 @@ type Backend interface {
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
+	GetPoolTransactionDrop(txHash common.Hash) (evmcore.TxDrop, bool)
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
@@ -102,9 +121,13 @@ type Backend interface {
 	GetDowntime(ctx context.Context, vid idx.ValidatorID) (idx.Block, inter.Timestamp, error)
 	GetUptime(ctx context.Context, vid idx.ValidatorID) (*big.Int, error)
 	GetOriginatedFee(ctx context.Context, vid idx.ValidatorID) (*big.Int, error)
+
+	// Disaster recovery API
+	RollbackTargetEpoch(ctx context.Context, block idx.Block) (idx.Epoch, error)
+	ScheduleRollback(ctx context.Context, epoch idx.Epoch) error
 }
 
-func GetAPIs(apiBackend Backend) []rpc.API {
+func GetAPIs(apiBackend Backend, stateSessions *StateSessions) []rpc.API {
 	nonceLock := new(AddrLocker)
 	return []rpc.API{
 		{
@@ -140,7 +163,7 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
-			Service:   NewPrivateDebugAPI(apiBackend),
+			Service:   NewPrivateDebugAPI(apiBackend, stateSessions),
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -162,6 +185,16 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTxTraceAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "ftm",
+			Version:   "1.0",
+			Service:   NewPublicDecoderAPI(),
+			Public:    true,
+		}, {
+			Namespace: "ftm",
+			Version:   "1.0",
+			Service:   NewPublicValidatorsAPI(apiBackend),
+			Public:    true,
 		},
 	}
 }
@@ -0,0 +1,64 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BalanceAtBlock is the balance of an account after the block.
+type BalanceAtBlock struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Balance     *hexutil.Big   `json:"balance"`
+}
+
+// GetBalanceHistory returns the balance of the address after each block in the range [fromBlock, toBlock].
+// The balance at fromBlock is read from the state, the following balances are taken from the balance changes index,
+// so the whole range costs a single state access. The number of blocks is limited by the node configuration.
+func (s *PublicBlockChainAPI) GetBalanceHistory(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) ([]BalanceAtBlock, error) {
+	from, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock))
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is above toBlock %d", from, to)
+	}
+	if limit := s.b.RPCBalanceHistoryLimit(); limit > 0 && uint64(to-from)+1 > uint64(limit) {
+		return nil, fmt.Errorf("too many blocks: %d (limit %d)", uint64(to-from)+1, limit)
+	}
+
+	changes, err := s.b.GetBalanceChanges(ctx, address, from+1, to)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(from)))
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	balance := (*hexutil.Big)(statedb.GetBalance(address))
+	err = statedb.Error()
+	statedb.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]BalanceAtBlock, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		for len(changes) != 0 && uint64(changes[0].BlockNumber) <= uint64(n) {
+			balance = changes[0].Balance
+			changes = changes[1:]
+		}
+		res = append(res, BalanceAtBlock{
+			BlockNumber: hexutil.Uint64(n),
+			Balance:     balance,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// FinalizedBlockTag is the tag of the latest block finalized by the consensus.
+	FinalizedBlockTag = "finalized"
+	// SafeBlockTag is the tag of the latest block which is safe from reorgs.
+	SafeBlockTag = "safe"
+)
+
+// IsFinalityTag reports whether the given block tag refers to a finalized block.
+// Lachesis provides instant finality: every block is derived from an Atropos
+// decided by aBFT consensus and cannot be reverted, so both "finalized" and
+// "safe" blocks are the latest processed block.
+func IsFinalityTag(tag string) bool {
+	return tag == FinalizedBlockTag || tag == SafeBlockTag
+}
+
+// BlockNumber is rpc.BlockNumber which also accepts the "finalized" and "safe" tags.
+// The tags are resolved to rpc.LatestBlockNumber.
+type BlockNumber rpc.BlockNumber
+
+// UnmarshalJSON parses the given JSON fragment into a BlockNumber.
+func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
+	input := strings.TrimSpace(string(data))
+	if len(input) >= 2 && input[0] == '"' && input[len(input)-1] == '"' {
+		input = input[1 : len(input)-1]
+	}
+	if IsFinalityTag(input) {
+		*bn = BlockNumber(rpc.LatestBlockNumber)
+		return nil
+	}
+	return (*rpc.BlockNumber)(bn).UnmarshalJSON(data)
+}
+
+// Rpc returns the underlying rpc.BlockNumber.
+func (bn BlockNumber) Rpc() rpc.BlockNumber {
+	return rpc.BlockNumber(bn)
+}
+
+// BlockNumberOrHash is rpc.BlockNumberOrHash which also accepts the "finalized" and "safe" tags.
+// The tags are resolved to rpc.LatestBlockNumber.
+type BlockNumberOrHash rpc.BlockNumberOrHash
+
+// UnmarshalJSON parses the given JSON fragment into a BlockNumberOrHash.
+func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
+	var input string
+	if err := json.Unmarshal(data, &input); err == nil && IsFinalityTag(input) {
+		*bnh = BlockNumberOrHash(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+		return nil
+	}
+	type object struct {
+		BlockNumber      *BlockNumber `json:"blockNumber,omitempty"`
+		BlockHash        *common.Hash `json:"blockHash,omitempty"`
+		RequireCanonical bool         `json:"requireCanonical,omitempty"`
+	}
+	var obj object
+	if err := json.Unmarshal(data, &obj); err == nil && obj.BlockNumber != nil && obj.BlockHash == nil {
+		*bnh = BlockNumberOrHash(rpc.BlockNumberOrHashWithNumber(obj.BlockNumber.Rpc()))
+		bnh.RequireCanonical = obj.RequireCanonical
+		return nil
+	}
+	return (*rpc.BlockNumberOrHash)(bnh).UnmarshalJSON(data)
+}
+
+// Rpc returns the underlying rpc.BlockNumberOrHash.
+func (bnh BlockNumberOrHash) Rpc() rpc.BlockNumberOrHash {
+	return rpc.BlockNumberOrHash(bnh)
+}
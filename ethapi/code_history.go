@@ -0,0 +1,36 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ContractCreation describes the deployment of a contract.
+type ContractCreation struct {
+	TransactionHash common.Hash    `json:"transactionHash"`
+	Creator         common.Address `json:"creator"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+}
+
+// CodeHistoryEvent describes the deployment or the destruction of a contract.
+type CodeHistoryEvent struct {
+	Type             string         `json:"type"` // "create" or "selfdestruct"
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+}
+
+// GetContractCreation returns the transaction, the creator and the block of the contract deployment.
+// Contracts created by other contracts are included, the creator is the address which executed the CREATE/CREATE2.
+// Returns nil if the contract isn't found in the index.
+func (s *PublicBlockChainAPI) GetContractCreation(ctx context.Context, address common.Address) (*ContractCreation, error) {
+	return s.b.GetContractCreation(ctx, address)
+}
+
+// GetCodeHistory returns the deployments and the destructions of the contract at the address in chronological order.
+// A contract may be deployed again at the same address after the destruction with CREATE2.
+func (s *PublicBlockChainAPI) GetCodeHistory(ctx context.Context, address common.Address) ([]CodeHistoryEvent, error) {
+	return s.b.GetCodeHistory(ctx, address)
+}
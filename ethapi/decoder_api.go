@@ -0,0 +1,218 @@
+package ethapi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// knownSignatures is the built-in 4-byte registry of the widespread methods, used when no ABI is provided.
+var knownSignatures = []string{
+	// ERC-20
+	"transfer(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"approve(address,uint256)",
+	"increaseAllowance(address,uint256)",
+	"decreaseAllowance(address,uint256)",
+	// ERC-721 and ERC-1155
+	"safeTransferFrom(address,address,uint256)",
+	"safeTransferFrom(address,address,uint256,bytes)",
+	"safeTransferFrom(address,address,uint256,uint256,bytes)",
+	"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+	"setApprovalForAll(address,bool)",
+	// wrapped native token
+	"deposit()",
+	"withdraw(uint256)",
+	// SFC
+	"delegate(uint256)",
+	"undelegate(uint256,uint256,uint256)",
+	"withdraw(uint256,uint256)",
+	"claimRewards(uint256)",
+	"restakeRewards(uint256)",
+	"lockStake(uint256,uint256,uint256)",
+	"unlockStake(uint256,uint256)",
+	"createValidator(bytes)",
+	// multicall
+	"multicall(bytes[])",
+}
+
+var knownMethods = func() map[[4]byte]abi.Method {
+	methods := make(map[[4]byte]abi.Method, len(knownSignatures))
+	for _, sig := range knownSignatures {
+		m, err := parseSignature(sig)
+		if err != nil {
+			panic(err)
+		}
+		var id [4]byte
+		copy(id[:], m.ID)
+		methods[id] = m
+	}
+	return methods
+}()
+
+// parseSignature parses a method signature of elementary types, e.g. transfer(address,uint256).
+func parseSignature(sig string) (abi.Method, error) {
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") {
+		return abi.Method{}, fmt.Errorf("invalid method signature %s", sig)
+	}
+	name := sig[:open]
+	var inputs abi.Arguments
+	if params := sig[open+1 : len(sig)-1]; params != "" {
+		for _, t := range strings.Split(params, ",") {
+			typ, err := abi.NewType(t, "", nil)
+			if err != nil {
+				return abi.Method{}, fmt.Errorf("invalid method signature %s: %w", sig, err)
+			}
+			inputs = append(inputs, abi.Argument{Type: typ})
+		}
+	}
+	return abi.NewMethod(name, name, abi.Function, "nonpayable", false, false, inputs, nil), nil
+}
+
+// DecodedArg is a decoded argument of a call.
+type DecodedArg struct {
+	Name  string      `json:"name,omitempty"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// DecodedCall is a decoded calldata of a call.
+type DecodedCall struct {
+	Selector  hexutil.Bytes `json:"selector"`
+	Signature string        `json:"signature"`
+	Args      []DecodedArg  `json:"args"`
+}
+
+// DecodedTransaction is a decoded raw transaction with its decoded calldata, if it's recognized.
+type DecodedTransaction struct {
+	*RPCTransaction
+	Call *DecodedCall `json:"call,omitempty"`
+}
+
+// PublicDecoderAPI provides helpers to decode transactions and calldata for debugging purposes.
+type PublicDecoderAPI struct{}
+
+// NewPublicDecoderAPI creates a new decoder API.
+func NewPublicDecoderAPI() *PublicDecoderAPI {
+	return &PublicDecoderAPI{}
+}
+
+// DecodeTransaction decodes the raw signed transaction, recovers the sender and decodes the calldata.
+// The calldata is decoded by the given JSON ABI, or by the built-in registry of the widespread methods.
+func (s *PublicDecoderAPI) DecodeTransaction(input hexutil.Bytes, abiJSON *string) (*DecodedTransaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	res := &DecodedTransaction{
+		RPCTransaction: NewRPCPendingTransaction(tx, nil),
+	}
+	if tx.To() == nil || len(tx.Data()) < 4 {
+		return res, nil
+	}
+	call, err := decodeCalldata(tx.Data(), abiJSON)
+	if err != nil && abiJSON != nil {
+		return nil, err
+	}
+	res.Call = call
+	return res, nil
+}
+
+// DecodeCalldata decodes the calldata by the given JSON ABI, or by the built-in registry of the widespread methods.
+func (s *PublicDecoderAPI) DecodeCalldata(data hexutil.Bytes, abiJSON *string) (*DecodedCall, error) {
+	return decodeCalldata(data, abiJSON)
+}
+
+func decodeCalldata(data []byte, abiJSON *string) (*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, errors.New("calldata is shorter than a method selector")
+	}
+	var method abi.Method
+	if abiJSON != nil {
+		parsed, err := abi.JSON(strings.NewReader(*abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI: %w", err)
+		}
+		m, err := parsed.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+		method = *m
+	} else {
+		var id [4]byte
+		copy(id[:], data[:4])
+		m, ok := knownMethods[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown method selector %s, provide the ABI", hexutil.Encode(data[:4]))
+		}
+		method = m
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode arguments of %s: %w", method.Sig, err)
+	}
+	call := &DecodedCall{
+		Selector:  data[:4],
+		Signature: method.Sig,
+		Args:      make([]DecodedArg, len(values)),
+	}
+	for i, v := range values {
+		call.Args[i] = DecodedArg{
+			Name:  method.Inputs[i].Name,
+			Type:  method.Inputs[i].Type.String(),
+			Value: formatDecodedValue(reflect.ValueOf(v)),
+		}
+	}
+	return call, nil
+}
+
+// formatDecodedValue converts the decoded ABI value into a JSON-friendly form:
+// integers are represented as decimal strings to keep the precision, byte arrays as hex.
+func formatDecodedValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return x.String()
+	case common.Address:
+		return x
+	case []byte:
+		return hexutil.Bytes(x)
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%d", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%d", v.Uint())
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Bytes(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = formatDecodedValue(v.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			fields[v.Type().Field(i).Name] = formatDecodedValue(v.Field(i))
+		}
+		return fields
+	}
+	return v.Interface()
+}
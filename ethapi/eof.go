@@ -0,0 +1,44 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore/eof"
+)
+
+// EOFValidationResult is the result of debug_validateEOF.
+type EOFValidationResult struct {
+	Valid             bool           `json:"valid"`
+	Error             string         `json:"error,omitempty"`
+	CodeSections      hexutil.Uint64 `json:"codeSections"`
+	ContainerSections hexutil.Uint64 `json:"containerSections"`
+	DataSize          hexutil.Uint64 `json:"dataSize"`
+	// Enforced is true if the current network rules reject deployments of invalid EOF contracts.
+	Enforced bool `json:"enforced"`
+}
+
+// ValidateEOF validates the EVM Object Format (EOF) v1 container.
+// Stack height validation isn't performed, so a container reported as valid may still be rejected by other clients.
+func (api *PublicDebugAPI) ValidateEOF(ctx context.Context, code hexutil.Bytes) (*EOFValidationResult, error) {
+	_, es, err := api.b.GetEpochBlockState(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	res := &EOFValidationResult{}
+	if es != nil {
+		res.Enforced = es.Rules.Upgrades.Eof
+	}
+	c, err := eof.Validate(code)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.Valid = true
+	res.CodeSections = hexutil.Uint64(len(c.Code))
+	res.ContainerSections = hexutil.Uint64(len(c.Containers))
+	res.DataSize = hexutil.Uint64(c.DataSize)
+	return res, nil
+}
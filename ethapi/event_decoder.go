@@ -0,0 +1,167 @@
+package ethapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodedEvent is a log decoded by the ABI of its event.
+type DecodedEvent struct {
+	Address     common.Address `json:"address"`
+	Event       string         `json:"event"`
+	Signature   string         `json:"signature"`
+	Args        []DecodedArg   `json:"args"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint   `json:"transactionIndex"`
+	Index       hexutil.Uint   `json:"logIndex"`
+	Removed     bool           `json:"removed"`
+}
+
+// EventDecoder decodes the logs of the given events.
+type EventDecoder struct {
+	events map[common.Hash]abi.Event
+}
+
+// NewEventDecoder creates a decoder of the events of the JSON ABI, or of the single event signature
+// with the parameter names, e.g. "Transfer(address indexed from, address indexed to, uint256 value)".
+// The anonymous events are skipped, as their logs can't be told apart.
+func NewEventDecoder(signature string, abiJSON *string) (*EventDecoder, error) {
+	d := &EventDecoder{
+		events: make(map[common.Hash]abi.Event),
+	}
+	if abiJSON != nil {
+		parsed, err := abi.JSON(strings.NewReader(*abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI: %w", err)
+		}
+		for _, event := range parsed.Events {
+			if !event.Anonymous {
+				d.events[event.ID] = event
+			}
+		}
+	} else {
+		event, err := parseEventSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		d.events[event.ID] = event
+	}
+	if len(d.events) == 0 {
+		return nil, errors.New("no events to decode")
+	}
+	return d, nil
+}
+
+// parseEventSignature parses an event signature of elementary types with optional indexed flags and names.
+func parseEventSignature(sig string) (abi.Event, error) {
+	sig = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "event "))
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") {
+		return abi.Event{}, fmt.Errorf("invalid event signature %s", sig)
+	}
+	name := strings.TrimSpace(sig[:open])
+	var inputs abi.Arguments
+	if params := strings.TrimSpace(sig[open+1 : len(sig)-1]); params != "" {
+		for _, param := range strings.Split(params, ",") {
+			fields := strings.Fields(param)
+			if len(fields) == 0 || len(fields) > 3 {
+				return abi.Event{}, fmt.Errorf("invalid event signature %s", sig)
+			}
+			typ, err := abi.NewType(fields[0], "", nil)
+			if err != nil {
+				return abi.Event{}, fmt.Errorf("invalid event signature %s: %w", sig, err)
+			}
+			arg := abi.Argument{Type: typ}
+			fields = fields[1:]
+			if len(fields) != 0 && fields[0] == "indexed" {
+				arg.Indexed = true
+				fields = fields[1:]
+			}
+			switch len(fields) {
+			case 0:
+			case 1:
+				arg.Name = fields[0]
+			default:
+				return abi.Event{}, fmt.Errorf("invalid event signature %s", sig)
+			}
+			inputs = append(inputs, arg)
+		}
+	}
+	return abi.NewEvent(name, name, false, inputs), nil
+}
+
+// IDs returns the first topics of the logs of the events.
+func (d *EventDecoder) IDs() []common.Hash {
+	ids := make([]common.Hash, 0, len(d.events))
+	for id := range d.events {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Decode decodes the log by the ABI of its event.
+func (d *EventDecoder) Decode(log *types.Log) (*DecodedEvent, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("anonymous log")
+	}
+	event, ok := d.events[log.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s", log.Topics[0].Hex())
+	}
+	indexed := 0
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed++
+		}
+	}
+	if len(log.Topics) != indexed+1 {
+		return nil, fmt.Errorf("log of %s has %d topics, expected %d", event.Sig, len(log.Topics), indexed+1)
+	}
+	values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data of %s: %w", event.Sig, err)
+	}
+
+	res := &DecodedEvent{
+		Address:     log.Address,
+		Event:       event.Name,
+		Signature:   event.Sig,
+		Args:        make([]DecodedArg, len(event.Inputs)),
+		BlockNumber: hexutil.Uint64(log.BlockNumber),
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		TxIndex:     hexutil.Uint(log.TxIndex),
+		Index:       hexutil.Uint(log.Index),
+		Removed:     log.Removed,
+	}
+	topics := log.Topics[1:]
+	for i, arg := range event.Inputs {
+		var value interface{}
+		if arg.Indexed {
+			// the dynamic types are represented by the hashes of their values
+			topic := map[string]interface{}{}
+			field := abi.Argument{Name: "value", Type: arg.Type, Indexed: true}
+			if err := abi.ParseTopicsIntoMap(topic, abi.Arguments{field}, topics[:1]); err != nil {
+				return nil, fmt.Errorf("failed to decode topic of %s: %w", event.Sig, err)
+			}
+			value, topics = topic["value"], topics[1:]
+		} else {
+			value, values = values[0], values[1:]
+		}
+		res.Args[i] = DecodedArg{
+			Name:  arg.Name,
+			Type:  arg.Type.String(),
+			Value: formatDecodedValue(reflect.ValueOf(value)),
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,140 @@
+package ethapi
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ExecutionLimitError is returned if an RPC-initiated execution exceeds the memory or the return data limit.
+type ExecutionLimitError struct {
+	Resource string // "memory" or "return data"
+	Size     uint64
+	Limit    uint64
+}
+
+func (e *ExecutionLimitError) Error() string {
+	return fmt.Sprintf("execution aborted (%s size %d exceeds limit %d)", e.Resource, e.Size, e.Limit)
+}
+
+// ErrorCode returns the JSON error code of an exceeded limit.
+func (e *ExecutionLimitError) ErrorCode() int {
+	return -32005
+}
+
+// limitTracer aborts the execution once the memory of all the call frames, or a return data exceeds the limit.
+// The calls are forwarded to the wrapped tracer, if any.
+type limitTracer struct {
+	inner vm.Tracer
+
+	maxMemory     uint64
+	maxReturnData uint64
+
+	frames []uint64 // memory sizes of the call frames, by depth
+	memory uint64   // total memory size of the call frames
+	err    *ExecutionLimitError
+}
+
+// withExecutionLimits sets the RPC execution limits into the VM config.
+// It returns nil if the limits are disabled.
+func withExecutionLimits(b Backend, cfg *vm.Config) *limitTracer {
+	maxMemory, maxReturnData := b.RPCEVMMemoryLimit(), b.RPCReturnDataLimit()
+	if maxMemory == 0 && maxReturnData == 0 {
+		return nil
+	}
+	t := &limitTracer{
+		inner:         cfg.Tracer,
+		maxMemory:     maxMemory,
+		maxReturnData: maxReturnData,
+	}
+	cfg.Tracer = t
+	cfg.Debug = true
+	cfg.InterpreterImpl = "geth" // lfvm doesn't call the tracer
+	return t
+}
+
+// Err returns the exceeded limit, if any.
+func (t *limitTracer) Err() error {
+	if t == nil || t.err == nil {
+		return nil
+	}
+	return t.err
+}
+
+func (t *limitTracer) abort(env *vm.EVM, resource string, size, limit uint64) {
+	if t.err == nil {
+		t.err = &ExecutionLimitError{resource, size, limit}
+	}
+	if env != nil {
+		env.Cancel()
+	}
+}
+
+func (t *limitTracer) checkReturnData(env *vm.EVM, data []byte) {
+	if t.maxReturnData != 0 && uint64(len(data)) > t.maxReturnData {
+		t.abort(env, "return data", uint64(len(data)), t.maxReturnData)
+	}
+}
+
+func (t *limitTracer) checkMemory(env *vm.EVM, scope *vm.ScopeContext, depth int) {
+	if t.maxMemory == 0 || depth <= 0 {
+		return
+	}
+	// the frames deeper than the current one are returned
+	for len(t.frames) > depth {
+		t.memory -= t.frames[len(t.frames)-1]
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+	for len(t.frames) < depth {
+		t.frames = append(t.frames, 0)
+	}
+	size := uint64(scope.Memory.Len())
+	t.memory = t.memory - t.frames[depth-1] + size
+	t.frames[depth-1] = size
+	if t.memory > t.maxMemory {
+		t.abort(env, "memory", t.memory, t.maxMemory)
+	}
+}
+
+func (t *limitTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	if t.inner != nil {
+		t.inner.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+func (t *limitTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	t.checkMemory(env, scope, depth)
+	t.checkReturnData(env, rData)
+	if t.inner != nil {
+		t.inner.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *limitTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.inner != nil {
+		t.inner.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *limitTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.checkReturnData(nil, output)
+	if t.inner != nil {
+		t.inner.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *limitTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.inner != nil {
+		t.inner.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *limitTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.checkReturnData(nil, output)
+	if t.inner != nil {
+		t.inner.CaptureEnd(output, gasUsed, d, err)
+	}
+}
@@ -0,0 +1,141 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/sfc"
+)
+
+var (
+	sfcConstsAddressMethod    = crypto.Keccak256([]byte("constsAddress()"))[:4]
+	sfcTreasuryAddressMethod  = crypto.Keccak256([]byte("treasuryAddress()"))[:4]
+	constsBurntFeeShareMethod = crypto.Keccak256([]byte("burntFeeShare()"))[:4]
+	constsTreasuryShareMethod = crypto.Keccak256([]byte("treasuryFeeShare()"))[:4]
+
+	// feeShareUnit is the unit of the fee shares of the SFC constants
+	feeShareUnit = big.NewInt(1e18)
+)
+
+// FeeStatsResult is the fees accounting of a block or an epoch.
+type FeeStatsResult struct {
+	Fees       *hexutil.Big `json:"fees"`
+	BaseFees   *hexutil.Big `json:"baseFees"`
+	Tips       *hexutil.Big `json:"tips"`
+	Originated *hexutil.Big `json:"originated"`
+	// The split of the fees by the SFC on the epoch sealing, set for the sealed epochs only.
+	// The rewards are rounded per validator by the SFC, so the split may differ by a few wei.
+	Rewards  *hexutil.Big `json:"rewards,omitempty"`
+	Treasury *hexutil.Big `json:"treasury,omitempty"`
+	Burnt    *hexutil.Big `json:"burnt,omitempty"`
+}
+
+func newFeeStatsResult(stats *iblockproc.FeeStats) *FeeStatsResult {
+	if stats == nil {
+		return nil
+	}
+	return &FeeStatsResult{
+		Fees:       (*hexutil.Big)(stats.Fees),
+		BaseFees:   (*hexutil.Big)(stats.BaseFees),
+		Tips:       (*hexutil.Big)(new(big.Int).Sub(stats.Fees, stats.BaseFees)),
+		Originated: (*hexutil.Big)(stats.Originated),
+	}
+}
+
+// GetBlockFees returns the fees paid by transactions of the block.
+func (s *PublicBlockChainAPI) GetBlockFees(ctx context.Context, blockNr rpc.BlockNumber) (*FeeStatsResult, error) {
+	stats, err := s.b.GetBlockFees(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return newFeeStatsResult(stats), nil
+}
+
+// GetEpochFees returns the fees paid by transactions of the epoch.
+// The fees of the current epoch are accumulated until the epoch is sealed.
+// The split of the fees between the validators rewards, the treasury and burning is
+// returned for the sealed epochs, it requires the state of the last block of the epoch.
+func (s *PublicBlockChainAPI) GetEpochFees(ctx context.Context, epoch rpc.BlockNumber) (*FeeStatsResult, error) {
+	stats, err := s.b.GetEpochFees(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	res := newFeeStatsResult(stats)
+	if res == nil || epoch < 0 || idx.Epoch(epoch) >= s.b.CurrentEpoch(ctx) {
+		return res, nil
+	}
+	// the block state of the next epoch start is the state of the sealing block
+	bs, _, err := s.b.GetEpochBlockState(ctx, epoch+1)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return res, nil
+	}
+	if err := s.splitEpochFees(ctx, res, rpc.BlockNumber(bs.LastBlock.Idx)); err != nil {
+		return nil, fmt.Errorf("failed to get the fees split: %w", err)
+	}
+	return res, nil
+}
+
+// splitEpochFees splits the fees the same way the SFC does on the epoch sealing: the validators
+// are rewarded and the treasury is paid by shares of the fees originated by validators,
+// while the rest of the fees is burnt.
+func (s *PublicBlockChainAPI) splitEpochFees(ctx context.Context, res *FeeStatsResult, block rpc.BlockNumber) error {
+	consts, err := s.callView(ctx, sfc.ContractAddress, sfcConstsAddressMethod, block)
+	if err != nil {
+		return err
+	}
+	burntShare, err := s.callView(ctx, common.BigToAddress(consts), constsBurntFeeShareMethod, block)
+	if err != nil {
+		return err
+	}
+	treasuryShare, err := s.callView(ctx, common.BigToAddress(consts), constsTreasuryShareMethod, block)
+	if err != nil {
+		return err
+	}
+	treasuryAddr, err := s.callView(ctx, sfc.ContractAddress, sfcTreasuryAddressMethod, block)
+	if err != nil {
+		return err
+	}
+
+	originated := res.Originated.ToInt()
+	rewardsShare := new(big.Int).Sub(feeShareUnit, burntShare)
+	rewardsShare.Sub(rewardsShare, treasuryShare)
+	rewards := new(big.Int).Div(new(big.Int).Mul(originated, rewardsShare), feeShareUnit)
+	treasury := new(big.Int)
+	if treasuryAddr.Sign() != 0 {
+		treasury.Div(new(big.Int).Mul(originated, treasuryShare), feeShareUnit)
+	}
+	burnt := new(big.Int).Sub(res.Fees.ToInt(), rewards)
+	burnt.Sub(burnt, treasury)
+
+	res.Rewards = (*hexutil.Big)(rewards)
+	res.Treasury = (*hexutil.Big)(treasury)
+	res.Burnt = (*hexutil.Big)(burnt)
+	return nil
+}
+
+// callView calls the view method without arguments, which returns a single word, at the block.
+func (s *PublicBlockChainAPI) callView(ctx context.Context, to common.Address, method []byte, block rpc.BlockNumber) (*big.Int, error) {
+	data := hexutil.Bytes(method)
+	result, err := DoCall(ctx, s.b, TransactionArgs{To: &to, Data: &data}, rpc.BlockNumberOrHashWithNumber(block), nil, nil, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	if len(result.ReturnData) != 32 {
+		return nil, fmt.Errorf("unexpected result of %x call to %s", method, to)
+	}
+	return new(big.Int).SetBytes(result.ReturnData), nil
+}
@@ -0,0 +1,21 @@
+package ethapi
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// HistoryUnavailableError is returned if the requested chain data precedes the history kept by a partial-history node.
+type HistoryUnavailableError struct {
+	First idx.Block // first block of the kept history
+}
+
+func (e *HistoryUnavailableError) Error() string {
+	return fmt.Sprintf("history unavailable before block %d", e.First)
+}
+
+// ErrorCode returns the JSON error code of the unavailable history, same as for the pruned history in geth.
+func (e *HistoryUnavailableError) ErrorCode() int {
+	return 4444
+}
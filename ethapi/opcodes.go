@@ -0,0 +1,43 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// ActiveOpcodesResult is the EVM instruction set of a block.
+type ActiveOpcodesResult struct {
+	Opcodes  []string        `json:"opcodes"`
+	Features map[string]bool `json:"features"`
+}
+
+// ActiveOpcodes returns the names of the EVM instructions active at the given block,
+// along with the activation flags of the EIPs introducing instructions, like transient storage (TSTORE) and MCOPY.
+func (api *PublicDebugAPI) ActiveOpcodes(ctx context.Context, blockNrOrHash BlockNumberOrHash) (*ActiveOpcodesResult, error) {
+	var header *evmcore.EvmHeader
+	var err error
+	rpcNrOrHash := blockNrOrHash.Rpc()
+	if hash, ok := rpcNrOrHash.Hash(); ok {
+		header, err = api.b.HeaderByHash(ctx, hash)
+	} else if number, ok := rpcNrOrHash.Number(); ok {
+		header, err = api.b.HeaderByNumber(ctx, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+	rules := api.b.ChainConfig().Rules(header.Number)
+	ops := evmcore.ActiveOpcodes(rules)
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = op.String()
+	}
+	return &ActiveOpcodesResult{
+		Opcodes:  names,
+		Features: evmcore.ActiveFeatures(rules),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProofRequest is an account and its storage keys to prove in GetProofs.
+type ProofRequest struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []string       `json:"storageKeys"`
+}
+
+// ProofsResult is result struct for GetProofs. The proofs refer to the trie nodes by their indexes in Nodes,
+// so the nodes shared by several proofs, like the upper levels of the accounts trie, are sent once.
+type ProofsResult struct {
+	Root     common.Hash           `json:"root"`
+	Nodes    []string              `json:"nodes"`
+	Accounts []*AccountProofResult `json:"accounts"`
+}
+
+// AccountProofResult is an account proof of GetProofs, it refers to the shared trie nodes.
+type AccountProofResult struct {
+	Address      common.Address       `json:"address"`
+	AccountProof []int                `json:"accountProof"`
+	Balance      *hexutil.Big         `json:"balance"`
+	CodeHash     common.Hash          `json:"codeHash"`
+	Nonce        hexutil.Uint64       `json:"nonce"`
+	StorageHash  common.Hash          `json:"storageHash"`
+	StorageProof []StorageProofResult `json:"storageProof"`
+}
+
+// StorageProofResult is a storage proof of GetProofs, it refers to the shared trie nodes.
+type StorageProofResult struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []int        `json:"proof"`
+}
+
+// proofNodes deduplicates the trie nodes of the proofs.
+type proofNodes struct {
+	nodes []string
+	index map[string]int
+}
+
+func (n *proofNodes) refs(proof []string) []int {
+	refs := make([]int, len(proof))
+	for i, node := range proof {
+		ref, ok := n.index[node]
+		if !ok {
+			ref = len(n.nodes)
+			n.nodes = append(n.nodes, node)
+			n.index[node] = ref
+		}
+		refs[i] = ref
+	}
+	return refs
+}
+
+// GetProofs returns the Merkle-proofs for the given accounts and their storage keys at a single state root.
+// It's an equivalent of multiple GetProof calls with the trie nodes deduplicated across all the proofs.
+// The total number of the accounts and the storage keys is limited by the node configuration.
+func (s *PublicBlockChainAPI) GetProofs(ctx context.Context, requests []ProofRequest, blockNrOrHash BlockNumberOrHash) (*ProofsResult, error) {
+	if limit := s.b.RPCProofsLimit(); limit > 0 {
+		total := len(requests)
+		for _, req := range requests {
+			total += len(req.StorageKeys)
+		}
+		if total > limit {
+			return nil, fmt.Errorf("too many proofs: %d (limit %d)", total, limit)
+		}
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
+	if state == nil || err != nil {
+		return nil, err
+	}
+	defer state.Release()
+
+	nodes := proofNodes{
+		index: make(map[string]int),
+	}
+	accounts := make([]*AccountProofResult, len(requests))
+	for i, req := range requests {
+		res, err := accountProof(state, req.Address, req.StorageKeys)
+		if err != nil {
+			return nil, err
+		}
+		storageProof := make([]StorageProofResult, len(res.StorageProof))
+		for j, sp := range res.StorageProof {
+			storageProof[j] = StorageProofResult{sp.Key, sp.Value, nodes.refs(sp.Proof)}
+		}
+		accounts[i] = &AccountProofResult{
+			Address:      res.Address,
+			AccountProof: nodes.refs(res.AccountProof),
+			Balance:      res.Balance,
+			CodeHash:     res.CodeHash,
+			Nonce:        res.Nonce,
+			StorageHash:  res.StorageHash,
+			StorageProof: storageProof,
+		}
+	}
+	return &ProofsResult{
+		Root:     header.Root,
+		Nodes:    nodes.nodes,
+		Accounts: accounts,
+	}, state.Error()
+}
@@ -0,0 +1,91 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// rollbackConfirmationTimeout is a time for confirming a requested rollback.
+const rollbackConfirmationTimeout = time.Minute
+
+type pendingRollback struct {
+	block        idx.Block
+	epoch        idx.Epoch
+	confirmation string
+	deadline     time.Time
+}
+
+// RollbackResult is the result of debug_setHead.
+type RollbackResult struct {
+	Epoch        hexutil.Uint64 `json:"epoch"`
+	Confirmation string         `json:"confirmation,omitempty"`
+	Scheduled    bool           `json:"scheduled"`
+}
+
+// SetHead rolls the processed head back to the beginning of the epoch containing the given block.
+// Lachesis cannot rewind single blocks, so the head is reverted to the epoch checkpoint.
+// The call is guarded:
+//   - the rollback must be enabled in the node config and is not allowed for validators,
+//   - the first call only returns a confirmation code, the rollback is scheduled by
+//     a second call with the same block and the code within a minute,
+//   - once scheduled, the node stops processing events and refuses to start again
+//     until the rollback is applied by "sonictool heal".
+func (api *PrivateDebugAPI) SetHead(ctx context.Context, number hexutil.Uint64, confirmation *string) (*RollbackResult, error) {
+	block := idx.Block(number)
+	epoch, err := api.b.RollbackTargetEpoch(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	api.rollbackMu.Lock()
+	defer api.rollbackMu.Unlock()
+
+	if confirmation == nil {
+		code, err := newConfirmationCode()
+		if err != nil {
+			return nil, err
+		}
+		api.pendingRollback = &pendingRollback{
+			block:        block,
+			epoch:        epoch,
+			confirmation: code,
+			deadline:     time.Now().Add(rollbackConfirmationTimeout),
+		}
+		log.Warn("Rollback requested, waiting for confirmation", "block", block, "epoch", epoch)
+		return &RollbackResult{
+			Epoch:        hexutil.Uint64(epoch),
+			Confirmation: code,
+		}, nil
+	}
+
+	pending := api.pendingRollback
+	api.pendingRollback = nil
+	if pending == nil || time.Now().After(pending.deadline) {
+		return nil, errors.New("no pending rollback request, request a new confirmation code")
+	}
+	if pending.confirmation != *confirmation || pending.block != block || pending.epoch != epoch {
+		return nil, errors.New("rollback confirmation mismatch, request a new confirmation code")
+	}
+	if err := api.b.ScheduleRollback(ctx, epoch); err != nil {
+		return nil, err
+	}
+	log.Warn("Rollback scheduled, stop the node and run 'sonictool heal' to apply it", "block", block, "epoch", epoch)
+	return &RollbackResult{
+		Epoch:     hexutil.Uint64(epoch),
+		Scheduled: true,
+	}, nil
+}
+
+func newConfirmationCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hexutil.Encode(b), nil
+}
@@ -0,0 +1,141 @@
+package ethapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// StaleStateResult is the result of a state query, which was allowed to be served by a slightly stale state.
+// It tells the block the result was actually read from, so a client behind a load balancer can tell
+// whether the replica which served the request was behind the requested block.
+type StaleStateResult struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	StaleBlocks hexutil.Uint64 `json:"staleBlocks"` // number of blocks the served block is behind the requested one
+	Result      interface{}    `json:"result"`
+}
+
+// staleStateAndHeader returns the state of the requested block. If the block isn't processed by the node yet,
+// the state of the latest block is returned instead, as long as it's no more than maxStale blocks behind
+// the requested one. The tolerance is capped by the node configuration.
+func (s *PublicBlockChainAPI) staleStateAndHeader(ctx context.Context, blockNr BlockNumberOrHash, maxStale hexutil.Uint64) (state.StateDB, *evmcore.EvmHeader, uint64, error) {
+	blockNrOrHash := blockNr.Rpc()
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb != nil && err == nil {
+		return statedb, header, 0, nil
+	}
+	requested, ok := blockNrOrHash.Number()
+	if !ok || requested < 0 {
+		return statedb, header, 0, err
+	}
+	limit := uint64(maxStale)
+	if max := s.b.RPCMaxStaleBlocks(); limit > max {
+		limit = max
+	}
+	if limit == 0 {
+		return statedb, header, 0, err
+	}
+	latest, latestHeader, latestErr := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if latest == nil || latestErr != nil {
+		return statedb, header, 0, err
+	}
+	head := latestHeader.Number.Uint64()
+	if head >= uint64(requested) || uint64(requested)-head > limit {
+		// the requested block is unavailable for another reason, or it's too far ahead
+		latest.Release()
+		return statedb, header, 0, err
+	}
+	if statedb != nil {
+		statedb.Release()
+	}
+	return latest, latestHeader, uint64(requested) - head, nil
+}
+
+// staleResult wraps the result of the query served by the state of the given header.
+func staleResult(header *evmcore.EvmHeader, stale uint64, result interface{}) *StaleStateResult {
+	return &StaleStateResult{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash,
+		StaleBlocks: hexutil.Uint64(stale),
+		Result:      result,
+	}
+}
+
+// GetBalanceStale returns the amount of wei for the given address in the state of the given block,
+// or of the latest block if the node is no more than maxStale blocks behind the given block.
+func (s *PublicBlockChainAPI) GetBalanceStale(ctx context.Context, address common.Address, blockNrOrHash BlockNumberOrHash, maxStale hexutil.Uint64) (*StaleStateResult, error) {
+	statedb, header, stale, err := s.staleStateAndHeader(ctx, blockNrOrHash, maxStale)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+	return staleResult(header, stale, (*hexutil.Big)(statedb.GetBalance(address))), statedb.Error()
+}
+
+// GetTransactionCountStale returns the nonce of the given address in the state of the given block,
+// or of the latest block if the node is no more than maxStale blocks behind the given block.
+func (s *PublicBlockChainAPI) GetTransactionCountStale(ctx context.Context, address common.Address, blockNrOrHash BlockNumberOrHash, maxStale hexutil.Uint64) (*StaleStateResult, error) {
+	statedb, header, stale, err := s.staleStateAndHeader(ctx, blockNrOrHash, maxStale)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+	return staleResult(header, stale, hexutil.Uint64(statedb.GetNonce(address))), statedb.Error()
+}
+
+// GetCodeStale returns the code of the given address in the state of the given block,
+// or of the latest block if the node is no more than maxStale blocks behind the given block.
+func (s *PublicBlockChainAPI) GetCodeStale(ctx context.Context, address common.Address, blockNrOrHash BlockNumberOrHash, maxStale hexutil.Uint64) (*StaleStateResult, error) {
+	statedb, header, stale, err := s.staleStateAndHeader(ctx, blockNrOrHash, maxStale)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+	return staleResult(header, stale, hexutil.Bytes(statedb.GetCode(address))), statedb.Error()
+}
+
+// GetStorageAtStale returns the storage slot of the given address in the state of the given block,
+// or of the latest block if the node is no more than maxStale blocks behind the given block.
+func (s *PublicBlockChainAPI) GetStorageAtStale(ctx context.Context, address common.Address, key string, blockNrOrHash BlockNumberOrHash, maxStale hexutil.Uint64) (*StaleStateResult, error) {
+	statedb, header, stale, err := s.staleStateAndHeader(ctx, blockNrOrHash, maxStale)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+	res := statedb.GetState(address, common.HexToHash(key))
+	return staleResult(header, stale, hexutil.Bytes(res[:])), statedb.Error()
+}
+
+// CallStale executes the given transaction on the state of the given block,
+// or of the latest block if the node is no more than maxStale blocks behind the given block.
+func (s *PublicBlockChainAPI) CallStale(ctx context.Context, args TransactionArgs, blockNrOrHash BlockNumberOrHash, maxStale hexutil.Uint64, overrides *StateOverride) (_ *StaleStateResult, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "eth_callStale", time.Now(), &err)
+
+	statedb, header, stale, err := s.staleStateAndHeader(ctx, blockNrOrHash, maxStale)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	// the call is executed on the served block, which is pinned by its number
+	statedb.Release()
+	served := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(header.Number.Int64()))
+	result, err := DoCall(ctx, s.b, args, served, overrides, nil, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if len(result.Revert()) > 0 {
+		return nil, newRevertError(result)
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return staleResult(header, stale, hexutil.Bytes(result.Return())), nil
+}
@@ -0,0 +1,267 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+const (
+	// maxStateSessions limits the number of the simultaneously pinned historical states
+	maxStateSessions = 16
+	// stateSessionIdleTimeout is the inactivity period after which a session is closed automatically
+	stateSessionIdleTimeout = 10 * time.Minute
+	// stateSessionsJanitorPeriod is the period of the checks for the idle sessions
+	stateSessionsJanitorPeriod = time.Minute
+)
+
+var (
+	errUnknownStateSession = errors.New("unknown or expired state session")
+	errStateSessionsClosed = errors.New("state sessions are closed")
+)
+
+// stateSession is a historical state pinned by debug_openState. The calls are executed on top of the state,
+// and their changes are reverted, so all the queries of a session see the same state.
+type stateSession struct {
+	mu       sync.Mutex
+	state    state.StateDB
+	header   *evmcore.EvmHeader
+	lastUsed time.Time
+}
+
+// StateSessions are the open state sessions. The idle sessions are closed by the janitor,
+// so the pinned states are released even if the sessions aren't queried anymore.
+type StateSessions struct {
+	mu       sync.Mutex
+	sessions map[rpc.ID]*stateSession
+	closed   bool
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func NewStateSessions() *StateSessions {
+	return &StateSessions{
+		sessions: make(map[rpc.ID]*stateSession),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start runs the janitor of the idle sessions.
+func (ss *StateSessions) Start() {
+	ss.wg.Add(1)
+	go ss.janitor()
+}
+
+// Stop stops the janitor and releases the states of all the sessions. No sessions are opened after it.
+func (ss *StateSessions) Stop() {
+	close(ss.quit)
+	ss.wg.Wait()
+
+	ss.mu.Lock()
+	sessions := ss.sessions
+	ss.sessions = make(map[rpc.ID]*stateSession)
+	ss.closed = true
+	ss.mu.Unlock()
+	for _, s := range sessions {
+		s.close()
+	}
+}
+
+func (ss *StateSessions) janitor() {
+	defer ss.wg.Done()
+	ticker := time.NewTicker(stateSessionsJanitorPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			ss.mu.Lock()
+			ss.expire(now)
+			ss.mu.Unlock()
+		case <-ss.quit:
+			return
+		}
+	}
+}
+
+// expire closes the idle sessions. The sessions which are being queried are skipped.
+func (ss *StateSessions) expire(now time.Time) {
+	for id, s := range ss.sessions {
+		if !s.mu.TryLock() {
+			continue
+		}
+		if now.Sub(s.lastUsed) > stateSessionIdleTimeout {
+			delete(ss.sessions, id)
+			s.release()
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (ss *StateSessions) open(st state.StateDB, header *evmcore.EvmHeader) (rpc.ID, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.closed {
+		return "", errStateSessionsClosed
+	}
+	ss.expire(time.Now())
+	if len(ss.sessions) >= maxStateSessions {
+		return "", fmt.Errorf("too many open state sessions (limit %d)", maxStateSessions)
+	}
+	id := rpc.NewID()
+	ss.sessions[id] = &stateSession{
+		state:    st,
+		header:   header,
+		lastUsed: time.Now(),
+	}
+	return id, nil
+}
+
+// use runs the function with the exclusively locked session.
+func (ss *StateSessions) use(id rpc.ID, fn func(s *stateSession) error) error {
+	ss.mu.Lock()
+	ss.expire(time.Now())
+	s := ss.sessions[id]
+	ss.mu.Unlock()
+	if s == nil {
+		return errUnknownStateSession
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return errUnknownStateSession
+	}
+	s.lastUsed = time.Now()
+	return fn(s)
+}
+
+func (ss *StateSessions) close(id rpc.ID) error {
+	ss.mu.Lock()
+	s := ss.sessions[id]
+	delete(ss.sessions, id)
+	ss.mu.Unlock()
+	if s == nil {
+		return errUnknownStateSession
+	}
+	s.close()
+	return nil
+}
+
+// close releases the state, once the running query of the session is finished.
+func (s *stateSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release()
+}
+
+func (s *stateSession) release() {
+	if s.state != nil {
+		s.state.Release()
+		s.state = nil
+	}
+}
+
+// OpenState pins the state of the block and returns the ID of the session, in which the state is queried.
+// The session is closed by CloseState, or automatically after a period of inactivity.
+func (api *PrivateDebugAPI) OpenState(ctx context.Context, blockNrOrHash BlockNumberOrHash) (rpc.ID, error) {
+	st, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash.Rpc())
+	if err != nil {
+		return "", err
+	}
+	if st == nil {
+		return "", errors.New("state not found")
+	}
+	id, err := api.sessions.open(st, header)
+	if err != nil {
+		st.Release()
+		return "", err
+	}
+	return id, nil
+}
+
+// CloseState closes the state session and releases the pinned state.
+func (api *PrivateDebugAPI) CloseState(id rpc.ID) error {
+	return api.sessions.close(id)
+}
+
+// StateBlock returns the header of the block, the state of which is pinned by the session.
+func (api *PrivateDebugAPI) StateBlock(id rpc.ID) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	err := api.sessions.use(id, func(s *stateSession) error {
+		res = RPCMarshalHeader(s.header, extBlockApi{})
+		return nil
+	})
+	return res, err
+}
+
+// StateCall executes the call in the state of the session. The changes of the call are reverted after it.
+func (api *PrivateDebugAPI) StateCall(ctx context.Context, id rpc.ID, args TransactionArgs) (_ hexutil.Bytes, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "debug_stateCall", time.Now(), &err)
+
+	var result *evmcore.ExecutionResult
+	err = api.sessions.use(id, func(s *stateSession) error {
+		snapshot := s.state.Snapshot()
+		defer s.state.RevertToSnapshot(snapshot)
+		var err error
+		result, err = doCallAtState(ctx, api.b, args, s.state, s.header, api.b.RPCEVMTimeout(), api.b.RPCGasCap())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if len(result.Revert()) > 0 {
+		return nil, newRevertError(result)
+	}
+	return result.Return(), result.Err
+}
+
+// StateGetBalance returns the balance of the address in the state of the session.
+func (api *PrivateDebugAPI) StateGetBalance(id rpc.ID, address common.Address) (*hexutil.Big, error) {
+	var res *hexutil.Big
+	err := api.sessions.use(id, func(s *stateSession) error {
+		res = (*hexutil.Big)(s.state.GetBalance(address))
+		return s.state.Error()
+	})
+	return res, err
+}
+
+// StateGetTransactionCount returns the nonce of the address in the state of the session.
+func (api *PrivateDebugAPI) StateGetTransactionCount(id rpc.ID, address common.Address) (hexutil.Uint64, error) {
+	var res hexutil.Uint64
+	err := api.sessions.use(id, func(s *stateSession) error {
+		res = hexutil.Uint64(s.state.GetNonce(address))
+		return s.state.Error()
+	})
+	return res, err
+}
+
+// StateGetCode returns the code of the address in the state of the session.
+func (api *PrivateDebugAPI) StateGetCode(id rpc.ID, address common.Address) (hexutil.Bytes, error) {
+	var res hexutil.Bytes
+	err := api.sessions.use(id, func(s *stateSession) error {
+		res = s.state.GetCode(address)
+		return s.state.Error()
+	})
+	return res, err
+}
+
+// StateGetStorageAt returns the storage slot of the address in the state of the session.
+func (api *PrivateDebugAPI) StateGetStorageAt(id rpc.ID, address common.Address, key string) (hexutil.Bytes, error) {
+	var res hexutil.Bytes
+	err := api.sessions.use(id, func(s *stateSession) error {
+		res = s.state.GetState(address, common.HexToHash(key)).Bytes()
+		return s.state.Error()
+	})
+	return res, err
+}
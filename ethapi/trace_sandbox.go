@@ -0,0 +1,96 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	traceBusyGauge   = metrics.GetOrRegisterGauge("rpc/trace/busy", nil)
+	traceWaitTimer   = metrics.GetOrRegisterTimer("rpc/trace/wait", nil)
+	traceCPUTimer    = metrics.GetOrRegisterTimer("rpc/trace/cpu", nil)
+	traceCancelMeter = metrics.GetOrRegisterMeter("rpc/trace/cancelled", nil)
+)
+
+// TraceSandbox runs the re-executions of the tracing RPC methods in a bounded pool of goroutines,
+// so the tracing can't occupy more CPUs than configured, whatever number of requests is served.
+// The time spent by the re-executions is accounted in the rpc/trace/cpu metric.
+type TraceSandbox struct {
+	slots chan struct{}
+}
+
+// NewTraceSandbox creates a pool of the given number of workers, or of the number of CPUs if it's not positive.
+func NewTraceSandbox(workers int) *TraceSandbox {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &TraceSandbox{
+		slots: make(chan struct{}, workers),
+	}
+}
+
+// Run executes the EVM call in a worker of the pool, once there is a free one.
+// The EVM is cancelled once the context is done, so the interpreter loop stops
+// and the worker is released right after the request is cancelled or its deadline is exceeded.
+// A nil sandbox executes the call in the current goroutine.
+func (s *TraceSandbox) Run(ctx context.Context, evm *vm.EVM, call func() error) error {
+	stop := cancelOnDone(ctx, evm)
+	defer stop()
+	if s == nil {
+		return call()
+	}
+
+	start := time.Now()
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		evm.Cancel()
+		traceCancelMeter.Mark(1)
+		return ctx.Err()
+	}
+	traceWaitTimer.UpdateSince(start)
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Trace re-execution failed", "reason", r, "stack", string(debug.Stack()))
+				done <- fmt.Errorf("trace re-execution failed with reason: %v", r)
+			}
+		}()
+		traceBusyGauge.Inc(1)
+		defer traceBusyGauge.Dec(1)
+		defer traceCPUTimer.UpdateSince(time.Now())
+		done <- call()
+	}()
+	// the EVM is cancelled, so the call returns shortly if the context is done
+	err := <-done
+	if ctx.Err() != nil {
+		traceCancelMeter.Mark(1)
+	}
+	return err
+}
+
+// cancelOnDone cancels the EVM once the context is done.
+// The returned function stops the watching of the context.
+func cancelOnDone(ctx context.Context, evm *vm.EVM) (stop func()) {
+	quit := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-quit:
+		}
+	}()
+	return func() {
+		close(quit)
+	}
+}
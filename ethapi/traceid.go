@@ -0,0 +1,118 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultErrorCode is the JSON error code of the RPC errors without their own code.
+const defaultErrorCode = -32000
+
+var (
+	// slowCallThreshold is the execution time of the traced RPC calls, the slower calls are logged as warnings.
+	slowCallThreshold time.Duration
+)
+
+// SetSlowCallThreshold sets the execution time of the traced RPC calls, the slower calls are logged as warnings.
+// Zero disables the slow calls records.
+func SetSlowCallThreshold(threshold time.Duration) {
+	slowCallThreshold = threshold
+}
+
+type traceIDKey struct{}
+
+// WithTraceID returns the context of an RPC call with a new trace ID.
+// The trace ID is kept if the context has one already, e.g. for a call made by another call.
+func WithTraceID(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if TraceID(ctx) != "" {
+		return ctx
+	}
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return context.WithValue(ctx, traceIDKey{}, hex.EncodeToString(id[:]))
+}
+
+// TraceID returns the trace ID of the RPC call, empty if the call isn't traced.
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// TraceLogger returns the logger, which records the trace ID of the RPC call.
+func TraceLogger(ctx context.Context) log.Logger {
+	if id := TraceID(ctx); id != "" {
+		return log.New("trace", id)
+	}
+	return log.Root()
+}
+
+// FinishTracedCall records the traced RPC call once it's executed and refers the error response to the trace ID.
+// It's deferred right after the trace ID is assigned.
+func FinishTracedCall(ctx context.Context, method string, start time.Time, errp *error) {
+	elapsed := time.Since(start)
+	logger := TraceLogger(ctx)
+	if slowCallThreshold > 0 && elapsed >= slowCallThreshold {
+		logger.Warn("Slow RPC call", "method", method, "runtime", elapsed, "err", *errp)
+	} else {
+		logger.Debug("Executed RPC call", "method", method, "runtime", elapsed, "err", *errp)
+	}
+
+	id := TraceID(ctx)
+	var traced *tracedError
+	if *errp == nil || id == "" || errors.As(*errp, &traced) {
+		return
+	}
+	*errp = &tracedError{
+		error:   *errp,
+		traceID: id,
+	}
+}
+
+// TracedErrorData is the data of an RPC error response, which refers to the trace ID of the failed call.
+type TracedErrorData struct {
+	TraceID string `json:"traceId"`
+	// Data is the own data of the error, e.g. the revert data of a call
+	Data interface{} `json:"data,omitempty"`
+}
+
+// tracedError is an RPC error response, which refers to the trace ID of the failed call.
+// The ID is returned in the error data, along with the own data of the error.
+type tracedError struct {
+	error
+	traceID string
+}
+
+func (e *tracedError) Unwrap() error {
+	return e.error
+}
+
+// ErrorCode returns the JSON error code of the underlying error.
+func (e *tracedError) ErrorCode() int {
+	if ec, ok := e.error.(rpc.Error); ok {
+		return ec.ErrorCode()
+	}
+	return defaultErrorCode
+}
+
+// ErrorData returns the trace ID and the data of the underlying error.
+func (e *tracedError) ErrorData() interface{} {
+	data := TracedErrorData{
+		TraceID: e.traceID,
+	}
+	if de, ok := e.error.(rpc.DataError); ok {
+		data.Data = de.ErrorData()
+	}
+	return data
+}
@@ -0,0 +1,135 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// Statuses of a transaction.
+const (
+	TxStatusUnknown  = "unknown"
+	TxStatusPending  = "pending"
+	TxStatusQueued   = "queued"
+	TxStatusIncluded = "included"
+	TxStatusDropped  = "dropped"
+	TxStatusReplaced = "replaced"
+)
+
+// TxStatus is the consolidated status of a transaction.
+type TxStatus struct {
+	Status string `json:"status"`
+
+	// pending transactions
+	PoolPosition  *hexutil.Uint `json:"poolPosition,omitempty"`  // number of the pending transactions with a higher effective tip
+	FeePercentile *float64      `json:"feePercentile,omitempty"` // share of the pending transactions with a lower effective tip, in percents
+
+	// included transactions
+	BlockHash     *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber   *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	Index         *hexutil.Uint64 `json:"transactionIndex,omitempty"`
+	Confirmations *hexutil.Uint64 `json:"confirmations,omitempty"`
+	Finalized     bool            `json:"finalized"`
+
+	// dropped and replaced transactions
+	Reason     string          `json:"reason,omitempty"`
+	ReplacedBy *common.Hash    `json:"replacedBy,omitempty"`
+	DroppedAt  *hexutil.Uint64 `json:"droppedAt,omitempty"` // unix time in seconds
+}
+
+// TransactionStatus returns the consolidated status of the transaction, gathered from the blocks index,
+// the pool and the recently dropped transactions.
+// The included transactions are always finalized, as the blocks are never reverted.
+func (s *PublicTxPoolAPI) TransactionStatus(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	// Try the finalized transactions first, as a transaction may stay in the pool for a while after the inclusion
+	tx, blockNumber, index, indexErr := s.b.GetTransaction(ctx, hash)
+	if indexErr == nil && tx != nil {
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+		if header == nil || err != nil {
+			return nil, err
+		}
+		latest := s.b.CurrentBlock().NumberU64()
+		confirmations := hexutil.Uint64(0)
+		if latest > blockNumber {
+			confirmations = hexutil.Uint64(latest - blockNumber)
+		}
+		return &TxStatus{
+			Status:        TxStatusIncluded,
+			BlockHash:     &header.Hash,
+			BlockNumber:   (*hexutil.Uint64)(&blockNumber),
+			Index:         (*hexutil.Uint64)(&index),
+			Confirmations: &confirmations,
+			Finalized:     true,
+		}, nil
+	}
+	if status := s.poolStatus(hash); status != nil {
+		return status, nil
+	}
+	if drop, ok := s.b.GetPoolTransactionDrop(hash); ok {
+		droppedAt := hexutil.Uint64(drop.Time.Unix())
+		status := &TxStatus{
+			Status:    TxStatusDropped,
+			Reason:    drop.Reason,
+			DroppedAt: &droppedAt,
+		}
+		if drop.Reason == evmcore.DropReplaced {
+			status.Status = TxStatusReplaced
+			status.ReplacedBy = &drop.ReplacedBy
+		}
+		return status, nil
+	}
+	// the transaction might be included, but the index is unavailable
+	if indexErr != nil {
+		return nil, indexErr
+	}
+	return &TxStatus{Status: TxStatusUnknown}, nil
+}
+
+// poolStatus returns the status of the transaction in the pool, or nil if it isn't in the pool.
+func (s *PublicTxPoolAPI) poolStatus(hash common.Hash) *TxStatus {
+	tx := s.b.GetPoolTransaction(hash)
+	if tx == nil {
+		return nil
+	}
+	pending, _ := s.b.TxPoolContent()
+	baseFee := s.b.CurrentBlock().Header().BaseFee
+	tip := tx.EffectiveGasTipValue(baseFee)
+
+	var (
+		found         bool
+		higher, lower uint
+		pendingTxs    uint
+	)
+	for _, txs := range pending {
+		for _, ptx := range txs {
+			if ptx.Hash() == hash {
+				found = true
+				continue
+			}
+			pendingTxs++
+			switch ptx.EffectiveGasTipValue(baseFee).Cmp(tip) {
+			case 1:
+				higher++
+			case -1:
+				lower++
+			}
+		}
+	}
+	if !found {
+		return &TxStatus{Status: TxStatusQueued}
+	}
+	percentile := float64(100)
+	if pendingTxs != 0 {
+		percentile = 100 * float64(lower) / float64(pendingTxs)
+	}
+	position := hexutil.Uint(higher)
+	return &TxStatus{
+		Status:        TxStatusPending,
+		PoolPosition:  &position,
+		FeePercentile: &percentile,
+	}
+}
@@ -10,10 +10,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
@@ -35,15 +35,19 @@ func NewPublicTxTraceAPI(b Backend) *PublicTxTraceAPI {
 }
 
 // Transaction - trace_transaction function returns transaction inner traces
-func (s *PublicTxTraceAPI) Transaction(ctx context.Context, hash common.Hash) (*[]txtrace.ActionTrace, error) {
+func (s *PublicTxTraceAPI) Transaction(ctx context.Context, hash common.Hash) (_ *[]txtrace.ActionTrace, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "trace_transaction", time.Now(), &err)
 	defer func(start time.Time) {
-		log.Debug("Executing trace_transaction call finished", "txHash", hash.String(), "runtime", time.Since(start))
+		TraceLogger(ctx).Debug("Executing trace_transaction call finished", "txHash", hash.String(), "runtime", time.Since(start))
 	}(time.Now())
 	return s.traceTxHash(ctx, hash, nil)
 }
 
 // Block - trace_block function returns transaction traces in given block
-func (s *PublicTxTraceAPI) Block(ctx context.Context, numberOrHash rpc.BlockNumberOrHash) (*[]txtrace.ActionTrace, error) {
+func (s *PublicTxTraceAPI) Block(ctx context.Context, numberOrHash rpc.BlockNumberOrHash) (_ *[]txtrace.ActionTrace, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "trace_block", time.Now(), &err)
 
 	blockNumber, _ := numberOrHash.Number()
 
@@ -61,7 +65,7 @@ func (s *PublicTxTraceAPI) Block(ctx context.Context, numberOrHash rpc.BlockNumb
 	}
 
 	defer func(start time.Time) {
-		log.Debug("Executing trace_block call finished", "block", blockNumber.Int64(), "runtime", time.Since(start))
+		TraceLogger(ctx).Debug("Executing trace_block call finished", "block", blockNumber.Int64(), "runtime", time.Since(start))
 	}(time.Now())
 
 	block, err := s.b.BlockByNumber(ctx, blockNumber)
@@ -79,9 +83,11 @@ func (s *PublicTxTraceAPI) Block(ctx context.Context, numberOrHash rpc.BlockNumb
 
 // Get - trace_get function returns transaction traces on specified index position of the traces
 // If index is nil, then just root trace is returned
-func (s *PublicTxTraceAPI) Get(ctx context.Context, hash common.Hash, traceIndex []hexutil.Uint) (*[]txtrace.ActionTrace, error) {
+func (s *PublicTxTraceAPI) Get(ctx context.Context, hash common.Hash, traceIndex []hexutil.Uint) (_ *[]txtrace.ActionTrace, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "trace_get", time.Now(), &err)
 	defer func(start time.Time) {
-		log.Debug("Executing trace_get call finished", "txHash", hash.String(), "index", traceIndex, "runtime", time.Since(start))
+		TraceLogger(ctx).Debug("Executing trace_get call finished", "txHash", hash.String(), "index", traceIndex, "runtime", time.Since(start))
 	}(time.Now())
 	return s.traceTxHash(ctx, hash, &traceIndex)
 }
@@ -95,6 +101,23 @@ func (s *PublicTxTraceAPI) traceTxHash(ctx context.Context, hash common.Hash, tr
 	if tx == nil {
 		return nil, fmt.Errorf("transaction %s not found", hash.String())
 	}
+
+	// use the traces persisted at import time, if any
+	stored, err := s.b.GetTxTraces(ctx, idx.Block(blockNumber), hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get stored traces of transaction %s: %v", hash.String(), err)
+	}
+	if stored != nil {
+		callTrace := txtrace.CallTrace{
+			Actions: make([]txtrace.ActionTrace, 0),
+		}
+		callTrace.AddTraces(stored, traceIndex)
+		if len(callTrace.Actions) == 0 {
+			return nil, nil
+		}
+		return &callTrace.Actions, nil
+	}
+
 	blkNr := rpc.BlockNumber(blockNumber)
 	block, err := s.b.BlockByNumber(ctx, blkNr)
 	if err != nil {
@@ -170,7 +193,7 @@ func (s *PublicTxTraceAPI) replayBlock(ctx context.Context, block *evmcore.EvmBl
 		} else {
 
 			// Replay transaction without tracing to prepare state for next transaction
-			log.Debug("Replaying transaction without trace", "txHash", tx.Hash().String())
+			TraceLogger(ctx).Debug("Replaying transaction without trace", "txHash", tx.Hash().String())
 			msg, err := evmcore.TxAsMessage(tx, signer, block.BaseFee)
 			if err != nil {
 				return nil, fmt.Errorf("cannot get message from transaction %s, error %s", tx.Hash().String(), err)
@@ -187,11 +210,18 @@ func (s *PublicTxTraceAPI) replayBlock(ctx context.Context, block *evmcore.EvmBl
 				return nil, fmt.Errorf("cannot initialize vm for transaction %s, error: %s", tx.Hash().String(), err.Error())
 			}
 
-			res, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
+			var res *evmcore.ExecutionResult
+			err = s.b.TraceSandbox().Run(ctx, vmenv, func() (err error) {
+				res, err = evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
+				return err
+			})
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			failed := false
 			if err != nil {
 				failed = true
-				log.Error("Cannot replay transaction", "txHash", tx.Hash().String(), "err", err.Error())
+				TraceLogger(ctx).Error("Cannot replay transaction", "txHash", tx.Hash().String(), "err", err.Error())
 			}
 			if err := state.Error(); err != nil {
 				return nil, fmt.Errorf("StateDB error when replaying tx %s: %w", tx.Hash().String(), err)
@@ -199,7 +229,7 @@ func (s *PublicTxTraceAPI) replayBlock(ctx context.Context, block *evmcore.EvmBl
 
 			if res != nil && res.Err != nil {
 				failed = true
-				log.Debug("Error replaying transaction", "txHash", tx.Hash().String(), "err", res.Err.Error())
+				TraceLogger(ctx).Debug("Error replaying transaction", "txHash", tx.Hash().String(), "err", res.Err.Error())
 			}
 
 			state.Finalise()
@@ -235,6 +265,7 @@ func (s *PublicTxTraceAPI) traceTx(
 	txTracer := txtrace.NewTraceStructLogger(block, tx, msg, uint(index), gasUsed)
 	cfg.Tracer = txTracer
 	cfg.NoBaseFee = true
+	limits := withExecutionLimits(b, &cfg)
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -254,17 +285,17 @@ func (s *PublicTxTraceAPI) traceTx(
 		return nil, fmt.Errorf("cannot initialize vm for transaction %s, error: %s", tx.Hash().String(), err.Error())
 	}
 
-	// Wait for the context to be done and cancel the evm. Even if the
-	// EVM has finished, cancelling may be done (repeatedly)
-	go func() {
-		<-ctx.Done()
-		vmenv.Cancel()
-	}()
-
 	// Setup the gas pool and stateDB
 	gp := new(evmcore.GasPool).AddGas(msg.Gas())
 	state.Prepare(tx.Hash(), int(index))
-	result, err := evmcore.ApplyMessage(vmenv, msg, gp)
+	var result *evmcore.ExecutionResult
+	err = b.TraceSandbox().Run(ctx, vmenv, func() (err error) {
+		result, err = evmcore.ApplyMessage(vmenv, msg, gp)
+		return err
+	})
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("EVM was cancelled when replaying tx")
+	}
 
 	traceActions := txTracer.GetResult()
 	state.Finalise()
@@ -283,6 +314,9 @@ func (s *PublicTxTraceAPI) traceTx(
 	if err := state.Error(); err != nil {
 		return nil, fmt.Errorf("StateDB error when replaying tx %s: %w", tx.Hash().String(), err)
 	}
+	if err := limits.Err(); err != nil {
+		return nil, err
+	}
 	// If the timer caused an abort, return an appropriate error message
 	if vmenv.Cancelled() {
 		return nil, fmt.Errorf("EVM was cancelled when replaying tx")
@@ -291,7 +325,7 @@ func (s *PublicTxTraceAPI) traceTx(
 	// result.Err is error during EVM execution
 	if result != nil && result.Err != nil {
 		if len(*traceActions) == 0 {
-			log.Error("error in result when replaying transaction:", "txHash", tx.Hash().String(), " err", result.Err.Error())
+			TraceLogger(ctx).Error("error in result when replaying transaction:", "txHash", tx.Hash().String(), " err", result.Err.Error())
 			errTrace := txtrace.GetErrorTraceFromMsg(&msg, block.Hash, *block.Number, tx.Hash(), index, result.Err)
 			at := make([]txtrace.ActionTrace, 0)
 			at = append(at, *errTrace)
@@ -335,11 +369,13 @@ type FilterArgs struct {
 }
 
 // Filter is function for trace_filter rpc call
-func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (json.RawMessage, error) {
+func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (_ json.RawMessage, err error) {
+	ctx = WithTraceID(ctx)
+	defer FinishTracedCall(ctx, "trace_filter", time.Now(), &err)
 	// add log after execution
 	defer func(start time.Time) {
 		data := getLogData(args, start)
-		log.Debug("Executing trace_filter call finished", data...)
+		TraceLogger(ctx).Debug("Executing trace_filter call finished", data...)
 	}(time.Now())
 
 	if args.Count == 0 && args.After == 0 {
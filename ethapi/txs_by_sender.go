@@ -0,0 +1,74 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+const (
+	// defaultTxsBySenderLimit is a number of txs returned by eth_getTransactionsBySender if the limit isn't specified
+	defaultTxsBySenderLimit = 100
+	// maxTxsBySenderLimit is a maximum number of txs returned by eth_getTransactionsBySender at once
+	maxTxsBySenderLimit = 1000
+)
+
+// SenderTx is a position of a transaction of a sender.
+type SenderTx struct {
+	Block idx.Block
+	Index uint64
+}
+
+// GetTransactionsBySender returns the transactions sent by the address in the blocks range [fromBlock, toBlock]
+// in chronological order. The txs are looked up in the senders index, so no external indexer is needed.
+// Use the block of the last returned tx as fromBlock of the next call to page through the results.
+func (s *PublicTransactionPoolAPI) GetTransactionsBySender(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, limit *hexutil.Uint) ([]*RPCTransaction, error) {
+	from, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock))
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is above toBlock %d", from, to)
+	}
+	n := defaultTxsBySenderLimit
+	if limit != nil {
+		n = int(*limit)
+	}
+	if n > maxTxsBySenderLimit {
+		return nil, fmt.Errorf("too many transactions requested: %d (limit %d)", n, maxTxsBySenderLimit)
+	}
+
+	positions, err := s.b.GetTxsBySender(ctx, address, from, to, n)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*RPCTransaction, 0, len(positions))
+	var block *evmcore.EvmBlock
+	for _, pos := range positions {
+		if block == nil || block.NumberU64() != uint64(pos.Block) {
+			block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(pos.Block))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block %d not found", pos.Block)
+			}
+		}
+		tx := newRPCTransactionFromBlockIndex(block, pos.Index)
+		if tx == nil {
+			return nil, fmt.Errorf("transaction %d of block %d not found", pos.Index, pos.Block)
+		}
+		res = append(res, tx)
+	}
+	return res, nil
+}
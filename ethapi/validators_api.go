@@ -0,0 +1,70 @@
+package ethapi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EpochValidator is a validator of an epoch.
+type EpochValidator struct {
+	ID     hexutil.Uint64 `json:"id"`
+	Weight hexutil.Uint64 `json:"weight"` // consensus weight
+	Stake  *hexutil.Big   `json:"stake"`  // total stake, the weight is derived from
+	PubKey string         `json:"pubkey"`
+}
+
+// EpochValidators is the validator set of an epoch.
+type EpochValidators struct {
+	Epoch       hexutil.Uint64   `json:"epoch"`
+	EpochStart  hexutil.Uint64   `json:"epochStart"` // unix time in nanoseconds
+	TotalWeight hexutil.Uint64   `json:"totalWeight"`
+	Quorum      hexutil.Uint64   `json:"quorum"`
+	Validators  []EpochValidator `json:"validators"`
+}
+
+// PublicValidatorsAPI provides an API to access the historical validator sets.
+type PublicValidatorsAPI struct {
+	b Backend
+}
+
+// NewPublicValidatorsAPI creates a new validators API.
+func NewPublicValidatorsAPI(b Backend) *PublicValidatorsAPI {
+	return &PublicValidatorsAPI{b}
+}
+
+// GetValidatorsAtEpoch returns the validator set of the epoch, sorted by validator ID.
+// The validator sets are kept with the history of the epoch states, so no contract state is replayed.
+func (s *PublicValidatorsAPI) GetValidatorsAtEpoch(ctx context.Context, epoch rpc.BlockNumber) (*EpochValidators, error) {
+	_, es, err := s.b.GetEpochBlockState(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if es == nil {
+		return nil, nil
+	}
+	ids := append([]idx.ValidatorID{}, es.Validators.IDs()...)
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	res := &EpochValidators{
+		Epoch:       hexutil.Uint64(es.Epoch),
+		EpochStart:  hexutil.Uint64(es.EpochStart),
+		TotalWeight: hexutil.Uint64(es.Validators.TotalWeight()),
+		Quorum:      hexutil.Uint64(es.Validators.Quorum()),
+		Validators:  make([]EpochValidator, 0, len(ids)),
+	}
+	for _, id := range ids {
+		profile := es.ValidatorProfiles[id]
+		res.Validators = append(res.Validators, EpochValidator{
+			ID:     hexutil.Uint64(id),
+			Weight: hexutil.Uint64(es.Validators.Get(id)),
+			Stake:  (*hexutil.Big)(profile.Weight),
+			PubKey: profile.PubKey.String(),
+		})
+	}
+	return res, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/core/types"
 
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/opera"
 )
@@ -19,6 +20,7 @@ var (
 	ErrTooBigExtra       = errors.New("event extra data is too large")
 	ErrWrongVersion      = errors.New("event has wrong version")
 	ErrUnsupportedTxType = errors.New("unsupported tx type")
+	ErrInvalidEOF        = evmcore.ErrInvalidEOF
 	ErrNotRelevant       = base.ErrNotRelevant
 	ErrAuth              = base.ErrAuth
 )
@@ -96,6 +98,12 @@ func CheckTxs(txs types.Transactions, rules opera.Rules) error {
 		if tx.GasFeeCapIntCmp(rules.Economy.MinGasPrice) < 0 {
 			return ErrUnderpriced
 		}
+		if err := evmcore.CheckInitCodeSize(tx, rules.Contracts.MaxInitCodeSize); err != nil {
+			return err
+		}
+		if err := evmcore.CheckEOF(tx, rules.Upgrades.Eof); err != nil {
+			return err
+		}
 	}
 	return nil
 }
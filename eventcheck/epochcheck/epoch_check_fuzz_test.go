@@ -0,0 +1,36 @@
+package epochcheck
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// FuzzCheckTxs checks that the validation of malformed transactions received from peers doesn't crash.
+func FuzzCheckTxs(f *testing.F) {
+	to := common.Address{1}
+	for _, tx := range []*types.Transaction{
+		types.NewTx(&types.LegacyTx{To: &to, Gas: 21000}),
+		types.NewTx(&types.AccessListTx{Data: []byte{0xef, 0x00, 0x01}}),
+		types.NewTx(&types.DynamicFeeTx{Data: []byte{0xef, 0x00, 0x01, 0x01, 0x00, 0x04, 0x02, 0x00, 0x01, 0x00, 0x01, 0xff, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00}}),
+	} {
+		b, err := tx.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+	rules := opera.FakeNetRules()
+	rules.Upgrades.Eof = true
+	rules.Contracts.MaxInitCodeSize = 49152
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return
+		}
+		_ = CheckTxs(types.Transactions{tx}, rules)
+	})
+}
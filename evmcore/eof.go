@@ -0,0 +1,59 @@
+package evmcore
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/Fantom-foundation/go-opera/evmcore/eof"
+)
+
+// EOF interpreters are the geth interpreters, which validate the EOF containers on deployment.
+const (
+	eofInterpreter            = "geth-eof"
+	eofPrecompilesInterpreter = "geth-precompiles-eof"
+)
+
+func init() {
+	vm.RegisterInterpreterFactory(eofInterpreter, func(evm *vm.EVM, cfg vm.Config) vm.EVMInterpreter {
+		return &eofEVMInterpreter{
+			EVMInterpreter: vm.NewEVMInterpreter(evm, cfg),
+			evm:            evm,
+		}
+	})
+	vm.RegisterInterpreterFactory(eofPrecompilesInterpreter, func(evm *vm.EVM, cfg vm.Config) vm.EVMInterpreter {
+		return &eofEVMInterpreter{
+			EVMInterpreter: newPrecompilesInterpreter(evm, cfg),
+			evm:            evm,
+		}
+	})
+}
+
+// eofEVMInterpreter validates the init code and the deployed code of every contract creation,
+// including CREATE and CREATE2 of the contracts, if they are prefixed with the EOF magic.
+// The transactions creating the invalid containers are rejected beforehand by CheckEOF.
+type eofEVMInterpreter struct {
+	vm.EVMInterpreter
+	evm *vm.EVM
+}
+
+func (in *eofEVMInterpreter) Run(contract *vm.Contract, input []byte, readOnly bool) ([]byte, error) {
+	// the creation runs the init code at the address of the new account, which has no code yet
+	deployment := contract.CodeAddr != nil && len(contract.Code) != 0 && in.evm.StateDB.GetCodeSize(*contract.CodeAddr) == 0
+	if !deployment {
+		return in.EVMInterpreter.Run(contract, input, readOnly)
+	}
+	if eof.HasMagic(contract.Code) {
+		if _, err := eof.Validate(contract.Code); err != nil {
+			// the creation fails and consumes all the gas, as for the invalid code
+			return nil, fmt.Errorf("%w: init code: %w", ErrInvalidEOF, err)
+		}
+	}
+	ret, err := in.EVMInterpreter.Run(contract, input, readOnly)
+	if err == nil && eof.HasMagic(ret) {
+		if _, err := eof.Validate(ret); err != nil {
+			return nil, fmt.Errorf("%w: deployed code: %w", ErrInvalidEOF, err)
+		}
+	}
+	return ret, err
+}
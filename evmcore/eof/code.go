@@ -0,0 +1,135 @@
+package eof
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	opSTOP           = 0x00
+	opPUSH1          = 0x60
+	opPUSH32         = 0x7f
+	opDATALOADN      = 0xd1
+	opRJUMP          = 0xe0
+	opRJUMPI         = 0xe1
+	opRJUMPV         = 0xe2
+	opCALLF          = 0xe3
+	opRETF           = 0xe4
+	opJUMPF          = 0xe5
+	opDUPN           = 0xe6
+	opSWAPN          = 0xe7
+	opEXCHANGE       = 0xe8
+	opEOFCREATE      = 0xec
+	opRETURNCONTRACT = 0xee
+	opRETURN         = 0xf3
+	opREVERT         = 0xfd
+	opINVALID        = 0xfe
+)
+
+// validOpcodes is the set of instructions allowed in EOF code.
+// Legacy code introspection, dynamic jumps, gas observability, CREATE* and CALL* instructions are disallowed.
+var validOpcodes = func() (valid [256]bool) {
+	ranges := [][2]int{
+		{0x00, 0x0b}, {0x10, 0x1d}, {0x20, 0x20},
+		{0x30, 0x37}, {0x3a, 0x3a}, {0x3d, 0x3e},
+		{0x40, 0x4a},
+		{0x50, 0x55}, {0x59, 0x59}, {0x5b, 0x7f},
+		{0x80, 0x9f}, {0xa0, 0xa4},
+		{0xd0, 0xd3}, {0xe0, 0xe8}, {0xec, 0xec}, {0xee, 0xee},
+		{0xf3, 0xf3}, {0xf7, 0xf9}, {0xfb, 0xfb}, {0xfd, 0xfe},
+	}
+	for _, r := range ranges {
+		for op := r[0]; op <= r[1]; op++ {
+			valid[op] = true
+		}
+	}
+	return valid
+}()
+
+func isTerminating(op byte) bool {
+	switch op {
+	case opSTOP, opRETF, opJUMPF, opRETURNCONTRACT, opRETURN, opREVERT, opINVALID, opRJUMP:
+		return true
+	}
+	return false
+}
+
+// immediateSize returns the size of the instruction immediate arguments.
+func immediateSize(code []byte, pos int) (int, bool) {
+	op := code[pos]
+	switch {
+	case op >= opPUSH1 && op <= opPUSH32:
+		return int(op-opPUSH1) + 1, true
+	case op == opRJUMP, op == opRJUMPI, op == opCALLF, op == opJUMPF, op == opDATALOADN:
+		return 2, true
+	case op == opDUPN, op == opSWAPN, op == opEXCHANGE, op == opEOFCREATE, op == opRETURNCONTRACT:
+		return 1, true
+	case op == opRJUMPV:
+		if pos+1 >= len(code) {
+			return 0, false
+		}
+		return 1 + 2*(int(code[pos+1])+1), true
+	}
+	return 0, true
+}
+
+func (c *Container) validateSection(code []byte) error {
+	// instruction boundaries and relative jump destinations
+	isInstruction := make([]bool, len(code))
+	var jumps []int
+	var last byte
+	for pos := 0; pos < len(code); {
+		op := code[pos]
+		if !validOpcodes[op] {
+			return fmt.Errorf("%w: undefined instruction 0x%02x at %d", ErrInvalidCode, op, pos)
+		}
+		size, ok := immediateSize(code, pos)
+		if !ok || pos+1+size > len(code) {
+			return fmt.Errorf("%w: at %d", ErrTruncatedCode, pos)
+		}
+		isInstruction[pos] = true
+		imm := code[pos+1 : pos+1+size]
+		next := pos + 1 + size
+
+		switch op {
+		case opRJUMP, opRJUMPI:
+			jumps = append(jumps, next+int(int16(binary.BigEndian.Uint16(imm))))
+		case opRJUMPV:
+			for i := 1; i < len(imm); i += 2 {
+				jumps = append(jumps, next+int(int16(binary.BigEndian.Uint16(imm[i:]))))
+			}
+		case opCALLF:
+			idx := int(binary.BigEndian.Uint16(imm))
+			if idx >= len(c.Types) {
+				return fmt.Errorf("%w: CALLF to %d", ErrInvalidSection, idx)
+			}
+			if c.Types[idx].Outputs == nonReturning {
+				return fmt.Errorf("%w: CALLF to non-returning section %d", ErrInvalidSection, idx)
+			}
+		case opJUMPF:
+			idx := int(binary.BigEndian.Uint16(imm))
+			if idx >= len(c.Types) {
+				return fmt.Errorf("%w: JUMPF to %d", ErrInvalidSection, idx)
+			}
+		case opDATALOADN:
+			if offset := int(binary.BigEndian.Uint16(imm)); offset+32 > int(c.DataSize) {
+				return fmt.Errorf("%w: DATALOADN at %d", ErrInvalidDataOffset, offset)
+			}
+		case opEOFCREATE, opRETURNCONTRACT:
+			if idx := int(imm[0]); idx >= len(c.Containers) {
+				return fmt.Errorf("%w: container %d", ErrInvalidSection, idx)
+			}
+		}
+		last = op
+		pos = next
+	}
+	if !isTerminating(last) {
+		return ErrMissingTerminator
+	}
+	for _, dest := range jumps {
+		if dest < 0 || dest >= len(code) || !isInstruction[dest] {
+			return fmt.Errorf("%w: %d", ErrInvalidJump, dest)
+		}
+	}
+	return nil
+}
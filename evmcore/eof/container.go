@@ -0,0 +1,249 @@
+// Package eof implements validation of EVM Object Format (EOF) v1 containers,
+// as specified by EIP-3540, EIP-3670, EIP-4200 and EIP-5450.
+package eof
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	Magic0  = 0xEF
+	Magic1  = 0x00
+	Version = 0x01
+
+	kindTypes     = 0x01
+	kindCode      = 0x02
+	kindContainer = 0x03
+	kindData      = 0xff
+	terminator    = 0x00
+
+	maxCodeSections      = 1024
+	maxContainerSections = 256
+	maxInputs            = 0x7f
+	maxOutputs           = 0x7f
+	nonReturning         = 0x80
+	maxStackIncrease     = 0x03ff
+	typeSize             = 4
+)
+
+var (
+	ErrInvalidMagic      = errors.New("invalid magic")
+	ErrInvalidVersion    = errors.New("invalid version")
+	ErrInvalidHeader     = errors.New("invalid header")
+	ErrInvalidTypes      = errors.New("invalid types section")
+	ErrInvalidSize       = errors.New("container size doesn't match the header")
+	ErrInvalidCode       = errors.New("invalid code")
+	ErrTruncatedCode     = errors.New("truncated instruction")
+	ErrInvalidJump       = errors.New("invalid relative jump destination")
+	ErrInvalidSection    = errors.New("invalid section index")
+	ErrInvalidDataOffset = errors.New("data offset is out of bounds")
+	ErrMissingTerminator = errors.New("code section doesn't end with a terminating instruction")
+	ErrInvalidStack      = errors.New("invalid stack height")
+)
+
+// FunctionType is a signature of a code section.
+type FunctionType struct {
+	Inputs           uint8
+	Outputs          uint8
+	MaxStackIncrease uint16
+}
+
+// Container is a parsed EOF container.
+type Container struct {
+	Types      []FunctionType
+	Code       [][]byte
+	Containers []*Container
+	Data       []byte
+	DataSize   uint16
+}
+
+// HasMagic returns true if the code is prefixed with the EOF magic.
+func HasMagic(code []byte) bool {
+	return len(code) >= 2 && code[0] == Magic0 && code[1] == Magic1
+}
+
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) byte() (byte, bool) {
+	if r.pos+1 > len(r.buf) {
+		return 0, false
+	}
+	r.pos++
+	return r.buf[r.pos-1], true
+}
+
+func (r *reader) uint16() (uint16, bool) {
+	if r.pos+2 > len(r.buf) {
+		return 0, false
+	}
+	r.pos += 2
+	return binary.BigEndian.Uint16(r.buf[r.pos-2:]), true
+}
+
+func (r *reader) uint32() (uint32, bool) {
+	if r.pos+4 > len(r.buf) {
+		return 0, false
+	}
+	r.pos += 4
+	return binary.BigEndian.Uint32(r.buf[r.pos-4:]), true
+}
+
+func (r *reader) bytes(n int) ([]byte, bool) {
+	if n < 0 || r.pos+n > len(r.buf) {
+		return nil, false
+	}
+	r.pos += n
+	return r.buf[r.pos-n : r.pos], true
+}
+
+func (r *reader) expect(b byte) bool {
+	v, ok := r.byte()
+	return ok && v == b
+}
+
+// Parse decodes the EOF container, checking its header and sections layout.
+// Instructions aren't validated, use Validate for the full validation.
+func Parse(code []byte) (*Container, error) {
+	r := &reader{buf: code}
+	if !r.expect(Magic0) || !r.expect(Magic1) {
+		return nil, ErrInvalidMagic
+	}
+	if !r.expect(Version) {
+		return nil, ErrInvalidVersion
+	}
+
+	// header
+	if !r.expect(kindTypes) {
+		return nil, fmt.Errorf("%w: types section expected", ErrInvalidHeader)
+	}
+	typesSize, ok := r.uint16()
+	if !ok {
+		return nil, ErrInvalidHeader
+	}
+	if !r.expect(kindCode) {
+		return nil, fmt.Errorf("%w: code section expected", ErrInvalidHeader)
+	}
+	numCode, ok := r.uint16()
+	if !ok || numCode == 0 || numCode > maxCodeSections {
+		return nil, fmt.Errorf("%w: invalid number of code sections", ErrInvalidHeader)
+	}
+	if int(typesSize) != int(numCode)*typeSize {
+		return nil, fmt.Errorf("%w: types section size mismatch", ErrInvalidHeader)
+	}
+	codeSizes := make([]int, numCode)
+	for i := range codeSizes {
+		size, ok := r.uint16()
+		if !ok || size == 0 {
+			return nil, fmt.Errorf("%w: invalid code section size", ErrInvalidHeader)
+		}
+		codeSizes[i] = int(size)
+	}
+	var containerSizes []int
+	kind, ok := r.byte()
+	if ok && kind == kindContainer {
+		numContainers, ok := r.uint16()
+		if !ok || numContainers == 0 || numContainers > maxContainerSections {
+			return nil, fmt.Errorf("%w: invalid number of container sections", ErrInvalidHeader)
+		}
+		containerSizes = make([]int, numContainers)
+		for i := range containerSizes {
+			size, ok := r.uint32()
+			if !ok || size == 0 {
+				return nil, fmt.Errorf("%w: invalid container section size", ErrInvalidHeader)
+			}
+			containerSizes[i] = int(size)
+		}
+		kind, ok = r.byte()
+	}
+	if !ok || kind != kindData {
+		return nil, fmt.Errorf("%w: data section expected", ErrInvalidHeader)
+	}
+	dataSize, ok := r.uint16()
+	if !ok {
+		return nil, ErrInvalidHeader
+	}
+	if !r.expect(terminator) {
+		return nil, fmt.Errorf("%w: terminator expected", ErrInvalidHeader)
+	}
+
+	// body
+	c := &Container{
+		Types:    make([]FunctionType, numCode),
+		Code:     make([][]byte, numCode),
+		DataSize: dataSize,
+	}
+	for i := range c.Types {
+		b, ok := r.bytes(typeSize)
+		if !ok {
+			return nil, ErrInvalidSize
+		}
+		c.Types[i] = FunctionType{
+			Inputs:           b[0],
+			Outputs:          b[1],
+			MaxStackIncrease: binary.BigEndian.Uint16(b[2:]),
+		}
+	}
+	for i, size := range codeSizes {
+		if c.Code[i], ok = r.bytes(size); !ok {
+			return nil, ErrInvalidSize
+		}
+	}
+	for _, size := range containerSizes {
+		b, ok := r.bytes(size)
+		if !ok {
+			return nil, ErrInvalidSize
+		}
+		sub, err := Parse(b)
+		if err != nil {
+			return nil, fmt.Errorf("subcontainer: %w", err)
+		}
+		c.Containers = append(c.Containers, sub)
+	}
+	c.Data = r.buf[r.pos:]
+	if len(c.Data) != int(dataSize) {
+		return nil, ErrInvalidSize
+	}
+
+	// types
+	if c.Types[0].Inputs != 0 || c.Types[0].Outputs != nonReturning {
+		return nil, fmt.Errorf("%w: first code section must have 0 inputs and be non-returning", ErrInvalidTypes)
+	}
+	for _, t := range c.Types {
+		if t.Inputs > maxInputs || (t.Outputs > maxOutputs && t.Outputs != nonReturning) || t.MaxStackIncrease > maxStackIncrease {
+			return nil, ErrInvalidTypes
+		}
+	}
+	return c, nil
+}
+
+// Validate parses the EOF container and validates the code of all its sections and subcontainers,
+// including the stack heights (EIP-5450).
+func Validate(code []byte) (*Container, error) {
+	c, err := Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.validateCode()
+}
+
+func (c *Container) validateCode() error {
+	for i, code := range c.Code {
+		if err := c.validateSection(code); err != nil {
+			return fmt.Errorf("code section %d: %w", i, err)
+		}
+		if err := c.validateStack(i); err != nil {
+			return fmt.Errorf("code section %d: %w", i, err)
+		}
+	}
+	for i, sub := range c.Containers {
+		if err := sub.validateCode(); err != nil {
+			return fmt.Errorf("subcontainer %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package eof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		code string
+		err  error
+	}{
+		{
+			name: "minimal",
+			code: "ef0001 010004 0200010001 ff0000 00 00800000 00",
+		},
+		{
+			name: "with data",
+			code: "ef0001 010004 0200010001 ff0002 00 00800000 00 aabb",
+		},
+		{
+			name: "with subcontainer",
+			code: "ef0001 010004 0200010001 03000100000014 ff0000 00 00800000 00" +
+				"ef0001 010004 0200010001 ff0000 00 00800000 00",
+		},
+		{
+			name: "relative jumps",
+			code: "ef0001 010004 0200010009 ff0000 00 00800001 6001 e10001 00 e0fffc",
+		},
+		{
+			name: "function call",
+			code: "ef0001 010008 0200020005 0003 ff0000 00 00800001 00010001 e300015000 6001e4",
+		},
+		{
+			name: "legacy code",
+			code: "6000",
+			err:  ErrInvalidMagic,
+		},
+		{
+			name: "wrong version",
+			code: "ef0002 010004 0200010001 ff0000 00 00800000 00",
+			err:  ErrInvalidVersion,
+		},
+		{
+			name: "no code sections",
+			code: "ef0001 010000 020000 ff0000 00",
+			err:  ErrInvalidHeader,
+		},
+		{
+			name: "truncated body",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 00",
+			err:  ErrInvalidSize,
+		},
+		{
+			name: "returning first section",
+			code: "ef0001 010004 0200010001 ff0000 00 00000000 00",
+			err:  ErrInvalidTypes,
+		},
+		{
+			name: "disallowed instruction",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 5800",
+			err:  ErrInvalidCode,
+		},
+		{
+			name: "truncated push",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 6100",
+			err:  ErrTruncatedCode,
+		},
+		{
+			name: "no terminating instruction",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 6000",
+			err:  ErrMissingTerminator,
+		},
+		{
+			name: "jump into immediate",
+			code: "ef0001 010004 0200010004 ff0000 00 00800000 e0ffff 00",
+			err:  ErrInvalidJump,
+		},
+		{
+			name: "data load out of bounds",
+			code: "ef0001 010004 0200010004 ff0000 00 00800000 d10000 00",
+			err:  ErrInvalidDataOffset,
+		},
+		{
+			name: "call to non-existing section",
+			code: "ef0001 010004 0200010004 ff0000 00 00800000 e30001 00",
+			err:  ErrInvalidSection,
+		},
+		{
+			name: "stack underflow",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 5000",
+			err:  ErrInvalidStack,
+		},
+		{
+			name: "wrong max stack increase",
+			code: "ef0001 010004 0200010003 ff0000 00 00800000 600100",
+			err:  ErrInvalidStack,
+		},
+		{
+			name: "unreachable instruction",
+			code: "ef0001 010004 0200010002 ff0000 00 00800000 0000",
+			err:  ErrInvalidStack,
+		},
+		{
+			name: "backward jump with different stack height",
+			code: "ef0001 010004 0200010005 ff0000 00 00800001 6001 e0fffb",
+			err:  ErrInvalidStack,
+		},
+		{
+			name: "return with wrong stack height",
+			code: "ef0001 010008 0200020005 0001 ff0000 00 00800001 00010000 e300015000 e4",
+			err:  ErrInvalidStack,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			code := common.FromHex(stripSpaces(tt.code))
+			_, err := Validate(code)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, tt.err), "expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func stripSpaces(s string) string {
+	res := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != ' ' {
+			res = append(res, s[i])
+		}
+	}
+	return string(res)
+}
@@ -0,0 +1,171 @@
+package eof
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	opDUP1  = 0x80
+	opSWAP1 = 0x90
+	opLOG0  = 0xa0
+
+	stackLimit = 1024
+)
+
+// stackEffect is the number of the stack items taken and put by an instruction.
+type stackEffect struct {
+	in, out int
+}
+
+// stackEffects of the valid instructions with a fixed stack effect.
+// DUPn, SWAPn, LOGn and the instructions with the effect defined by the immediates are handled separately.
+var stackEffects = func() (effects [256]stackEffect) {
+	for op, e := range map[byte]stackEffect{
+		0x01: {2, 1}, 0x02: {2, 1}, 0x03: {2, 1}, 0x04: {2, 1}, 0x05: {2, 1}, 0x06: {2, 1}, 0x07: {2, 1},
+		0x08: {3, 1}, 0x09: {3, 1}, 0x0a: {2, 1}, 0x0b: {2, 1},
+		0x10: {2, 1}, 0x11: {2, 1}, 0x12: {2, 1}, 0x13: {2, 1}, 0x14: {2, 1}, 0x15: {1, 1}, 0x16: {2, 1},
+		0x17: {2, 1}, 0x18: {2, 1}, 0x19: {1, 1}, 0x1a: {2, 1}, 0x1b: {2, 1}, 0x1c: {2, 1}, 0x1d: {2, 1},
+		0x20: {2, 1},
+		0x30: {0, 1}, 0x31: {1, 1}, 0x32: {0, 1}, 0x33: {0, 1}, 0x34: {0, 1}, 0x35: {1, 1}, 0x36: {0, 1}, 0x37: {3, 0},
+		0x3a: {0, 1}, 0x3d: {0, 1}, 0x3e: {3, 0},
+		0x40: {1, 1}, 0x41: {0, 1}, 0x42: {0, 1}, 0x43: {0, 1}, 0x44: {0, 1}, 0x45: {0, 1}, 0x46: {0, 1},
+		0x47: {0, 1}, 0x48: {0, 1}, 0x49: {1, 1}, 0x4a: {0, 1},
+		0x50: {1, 0}, 0x51: {1, 1}, 0x52: {2, 0}, 0x53: {2, 0}, 0x54: {1, 1}, 0x55: {2, 0},
+		0x59: {0, 1}, 0x5c: {1, 1}, 0x5d: {2, 0}, 0x5e: {3, 0}, 0x5f: {0, 1},
+		0xd0: {1, 1}, 0xd1: {0, 1}, 0xd2: {0, 1}, 0xd3: {3, 0},
+		opRJUMPI: {1, 0}, opRJUMPV: {1, 0},
+		opEOFCREATE: {4, 1}, opRETURNCONTRACT: {2, 0},
+		opRETURN: {2, 0}, 0xf7: {1, 1}, 0xf8: {4, 1}, 0xf9: {3, 1}, 0xfb: {3, 1}, opREVERT: {2, 0},
+	} {
+		effects[op] = e
+	}
+	for op := opPUSH1; op <= opPUSH32; op++ {
+		effects[op] = stackEffect{0, 1}
+	}
+	for n := 1; n <= 16; n++ {
+		effects[opDUP1+n-1] = stackEffect{n, n + 1}
+		effects[opSWAP1+n-1] = stackEffect{n + 1, n + 1}
+	}
+	for n := 0; n <= 4; n++ {
+		effects[opLOG0+n] = stackEffect{n + 2, 0}
+	}
+	return effects
+}()
+
+// stackBounds is the range of the stack heights an instruction may be reached with.
+type stackBounds struct {
+	min, max int
+}
+
+// validateStack validates the stack heights of the code section (EIP-5450): every instruction
+// is reachable, the stack doesn't underflow or overflow, and the section returns the declared outputs.
+// The section instructions must be validated by validateSection beforehand.
+func (c *Container) validateStack(section int) error {
+	code := c.Code[section]
+	typ := c.Types[section]
+	heights := make([]*stackBounds, len(code))
+	heights[0] = &stackBounds{int(typ.Inputs), int(typ.Inputs)}
+	maxHeight := int(typ.Inputs)
+	returning := false
+
+	for pos := 0; pos < len(code); {
+		op := code[pos]
+		cur := heights[pos]
+		if cur == nil {
+			return fmt.Errorf("%w: unreachable instruction at %d", ErrInvalidStack, pos)
+		}
+		size, _ := immediateSize(code, pos)
+		imm := code[pos+1 : pos+1+size]
+		next := pos + 1 + size
+
+		effect := stackEffects[op]
+		switch op {
+		case opCALLF, opJUMPF:
+			target := c.Types[binary.BigEndian.Uint16(imm)]
+			if cur.max+int(target.MaxStackIncrease) > stackLimit {
+				return fmt.Errorf("%w: stack overflow at %d", ErrInvalidStack, pos)
+			}
+			effect = stackEffect{int(target.Inputs), int(target.Outputs)}
+			if op == opJUMPF {
+				if target.Outputs == nonReturning {
+					effect.out = 0
+					break
+				}
+				// the outputs of the target are the outputs of the section
+				if typ.Outputs == nonReturning || target.Outputs > typ.Outputs {
+					return fmt.Errorf("%w: JUMPF to section with incompatible outputs at %d", ErrInvalidStack, pos)
+				}
+				want := int(typ.Outputs) + int(target.Inputs) - int(target.Outputs)
+				if cur.min != want || cur.max != want {
+					return fmt.Errorf("%w: JUMPF with stack height %d-%d instead of %d at %d", ErrInvalidStack, cur.min, cur.max, want, pos)
+				}
+				returning = true
+			}
+		case opRETF:
+			if typ.Outputs == nonReturning {
+				return fmt.Errorf("%w: RETF in non-returning section at %d", ErrInvalidStack, pos)
+			}
+			if cur.min != int(typ.Outputs) || cur.max != int(typ.Outputs) {
+				return fmt.Errorf("%w: RETF with stack height %d-%d instead of %d at %d", ErrInvalidStack, cur.min, cur.max, typ.Outputs, pos)
+			}
+			returning = true
+		case opDUPN:
+			effect = stackEffect{int(imm[0]) + 1, int(imm[0]) + 2}
+		case opSWAPN:
+			effect = stackEffect{int(imm[0]) + 2, int(imm[0]) + 2}
+		case opEXCHANGE:
+			n := int(imm[0]>>4) + int(imm[0]&0x0f) + 3
+			effect = stackEffect{n, n}
+		}
+		if cur.min < effect.in {
+			return fmt.Errorf("%w: stack underflow at %d", ErrInvalidStack, pos)
+		}
+		out := stackBounds{cur.min - effect.in + effect.out, cur.max - effect.in + effect.out}
+		if out.max > maxHeight {
+			maxHeight = out.max
+		}
+
+		var successors []int
+		if !isTerminating(op) {
+			successors = append(successors, next)
+		}
+		switch op {
+		case opRJUMP, opRJUMPI:
+			successors = append(successors, next+int(int16(binary.BigEndian.Uint16(imm))))
+		case opRJUMPV:
+			for i := 1; i < len(imm); i += 2 {
+				successors = append(successors, next+int(int16(binary.BigEndian.Uint16(imm[i:]))))
+			}
+		}
+		for _, s := range successors {
+			if s >= len(code) {
+				return fmt.Errorf("%w: no terminating instruction at %d", ErrInvalidStack, pos)
+			}
+			switch h := heights[s]; {
+			case s <= pos:
+				// a backward jump must keep the stack heights of the target
+				if h == nil || *h != out {
+					return fmt.Errorf("%w: backward jump with different stack height at %d", ErrInvalidStack, pos)
+				}
+			case h == nil:
+				heights[s] = &stackBounds{out.min, out.max}
+			default:
+				h.min = min(h.min, out.min)
+				h.max = max(h.max, out.max)
+			}
+		}
+		pos = next
+	}
+
+	if maxHeight > stackLimit {
+		return fmt.Errorf("%w: stack overflow", ErrInvalidStack)
+	}
+	if maxHeight-int(typ.Inputs) != int(typ.MaxStackIncrease) {
+		return fmt.Errorf("%w: max stack increase %d instead of declared %d", ErrInvalidStack, maxHeight-int(typ.Inputs), typ.MaxStackIncrease)
+	}
+	if returning != (typ.Outputs != nonReturning) {
+		return fmt.Errorf("%w: returning section doesn't return", ErrInvalidStack)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/evmcore/eof"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// createCode is the code of the factory, which deploys the call data as the init code by CREATE
+// and returns the address of the created contract, zero if the creation fails
+var createCode = hexutil.MustDecode("0x" +
+	"3660006000" + "37" + // CALLDATACOPY(0, 0, CALLDATASIZE)
+	"3660006000" + "f0" + // CREATE(0, 0, CALLDATASIZE)
+	"600052" + // MSTORE(0, address)
+	"60206000" + "f3") // RETURN(0, 0x20)
+
+// invalidContainer is an EOF container, which underflows the stack
+var invalidContainer = hexutil.MustDecode("0xef00010100040200010002ff00000000800000" + "5000")
+
+// exitTracer records the errors of the nested calls.
+type exitTracer struct {
+	errs []error
+}
+
+func (t *exitTracer) CaptureStart(*vm.EVM, common.Address, common.Address, bool, []byte, uint64, *big.Int) {
+}
+
+func (t *exitTracer) CaptureState(*vm.EVM, uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, []byte, int, error) {
+}
+
+func (t *exitTracer) CaptureEnter(vm.OpCode, common.Address, common.Address, []byte, uint64, *big.Int) {
+}
+
+func (t *exitTracer) CaptureExit(_ []byte, _ uint64, err error) {
+	t.errs = append(t.errs, err)
+}
+
+func (t *exitTracer) CaptureFault(*vm.EVM, uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, int, error) {
+}
+
+func (t *exitTracer) CaptureEnd([]byte, uint64, time.Duration, error) {}
+
+func TestVMConfigEofCreate(t *testing.T) {
+	require := require.New(t)
+
+	// create calls the factory with the init code, and returns the created address and the error of the creation
+	create := func(rules opera.Rules, initCode []byte) (common.Address, error) {
+		statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		require.NoError(err)
+		factory := common.Address{0xc0}
+		statedb.SetCode(factory, createCode)
+		blockCtx := vm.BlockContext{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			BlockNumber: big.NewInt(1),
+			Difficulty:  big.NewInt(0),
+			BaseFee:     big.NewInt(0),
+		}
+		tracer := &exitTracer{}
+		cfg := VMConfig(rules)
+		cfg.Debug = true
+		cfg.Tracer = tracer
+		evm := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, rules.EvmChainConfig(nil), cfg)
+		ret, _, err := evm.Call(vm.AccountRef(common.Address{0x01}), factory, initCode, 10_000_000, new(big.Int))
+		require.NoError(err)
+		require.Len(tracer.errs, 1)
+		return common.BytesToAddress(ret), tracer.errs[0]
+	}
+
+	rules := opera.FakeNetRules()
+	rules.Upgrades.Eof = true
+	for _, bls := range []bool{false, true} {
+		rules.Upgrades.Bls = bls
+
+		// the invalid init code is rejected by the validation
+		addr, err := create(rules, invalidContainer)
+		require.Equal(common.Address{}, addr)
+		require.ErrorIs(err, ErrInvalidEOF)
+		require.ErrorIs(err, eof.ErrInvalidStack)
+
+		// the init code returning the invalid container is rejected by the validation
+		deploy := append(hexutil.MustDecode("0x"+
+			"6015600c6000"+"39"+ // CODECOPY(0, 0x0c, 0x15)
+			"60156000"+"f3"), // RETURN(0, 0x15)
+			invalidContainer...)
+		addr, err = create(rules, deploy)
+		require.Equal(common.Address{}, addr)
+		require.ErrorIs(err, ErrInvalidEOF)
+		require.ErrorIs(err, eof.ErrInvalidStack)
+
+		// the legacy init code is deployed
+		addr, err = create(rules, hexutil.MustDecode("0x600060005360016000f3"))
+		require.NotEqual(common.Address{}, addr)
+		require.NoError(err)
+	}
+
+	// the containers aren't validated before the upgrade
+	rules.Upgrades.Eof = false
+	addr, err := create(rules, invalidContainer)
+	require.Equal(common.Address{}, addr)
+	require.Error(err)
+	require.NotErrorIs(err, ErrInvalidEOF)
+}
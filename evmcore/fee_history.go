@@ -0,0 +1,99 @@
+package evmcore
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
+)
+
+// FeeHistoryPercentileStep is the step of the reward percentiles recorded per block, in percents.
+const FeeHistoryPercentileStep = 5
+
+// BlockFeeHistory is the fee history record of a block, as reported by eth_feeHistory.
+type BlockFeeHistory struct {
+	BaseFee  *big.Int
+	GasUsed  uint64
+	GasLimit uint64
+	// Rewards are the effective tips at the percentiles 0, FeeHistoryPercentileStep, ..., 100 of the gas used
+	// by the block transactions, sorted by the tip. It's empty if the block has no transactions.
+	Rewards []*big.Int
+}
+
+// NewBlockFeeHistory calculates the fee history record of the block.
+// Internal transactions don't pay fees, so they aren't counted in the rewards.
+func NewBlockFeeHistory(baseFee *big.Int, gasUsed, gasLimit uint64, txs types.Transactions, receipts types.Receipts) BlockFeeHistory {
+	h := BlockFeeHistory{
+		BaseFee:  baseFee,
+		GasUsed:  gasUsed,
+		GasLimit: gasLimit,
+	}
+	if h.BaseFee == nil {
+		h.BaseFee = new(big.Int)
+	}
+
+	type txGasAndReward struct {
+		gasUsed uint64
+		reward  *big.Int
+	}
+	sorted := make([]txGasAndReward, 0, len(txs))
+	var total uint64
+	for i, tx := range txs {
+		if i >= len(receipts) || internaltx.IsInternal(tx) {
+			continue
+		}
+		reward, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			reward = new(big.Int)
+		}
+		sorted = append(sorted, txGasAndReward{receipts[i].GasUsed, reward})
+		total += receipts[i].GasUsed
+	}
+	if len(sorted) == 0 {
+		return h
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].reward.Cmp(sorted[j].reward) < 0
+	})
+
+	var txIndex int
+	sumGasUsed := sorted[0].gasUsed
+	for p := uint64(0); p <= 100; p += FeeHistoryPercentileStep {
+		threshold := total * p / 100
+		for sumGasUsed < threshold && txIndex < len(sorted)-1 {
+			txIndex++
+			sumGasUsed += sorted[txIndex].gasUsed
+		}
+		h.Rewards = append(h.Rewards, sorted[txIndex].reward)
+	}
+	return h
+}
+
+// GasUsedRatio returns the ratio of the gas used by the block to the block gas limit.
+func (h *BlockFeeHistory) GasUsedRatio() float64 {
+	if h.GasLimit == 0 {
+		return 0
+	}
+	return float64(h.GasUsed) / float64(h.GasLimit)
+}
+
+// Reward returns the effective tip at the given percentile of the gas used by the block.
+// The percentile is rounded up to the recorded one, so the tip is never underestimated.
+func (h *BlockFeeHistory) Reward(percentile float64) *big.Int {
+	if len(h.Rewards) == 0 {
+		return new(big.Int)
+	}
+	i := int(percentile / FeeHistoryPercentileStep)
+	if float64(i*FeeHistoryPercentileStep) < percentile {
+		i++
+	}
+	if i >= len(h.Rewards) {
+		i = len(h.Rewards) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return h.Rewards[i]
+}
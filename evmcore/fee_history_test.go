@@ -0,0 +1,70 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockFeeHistory(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	signer := types.NewLondonSigner(big.NewInt(1))
+	baseFee := big.NewInt(100)
+
+	var (
+		txs      types.Transactions
+		receipts types.Receipts
+	)
+	add := func(tx *types.Transaction, gasUsed uint64) {
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{GasUsed: gasUsed})
+	}
+	dynamicTx := func(nonce uint64, tip int64) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(1000),
+			Gas:       100000,
+			To:        &common.Address{},
+		})
+		require.NoError(err)
+		return tx
+	}
+	// internal transactions aren't signed and aren't counted
+	add(types.NewTransaction(0, common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil), 50000)
+	add(dynamicTx(0, 30), 30000)
+	add(dynamicTx(1, 10), 10000)
+	add(dynamicTx(2, 20), 60000)
+
+	h := NewBlockFeeHistory(baseFee, 150000, 300000, txs, receipts)
+	require.Equal(0.5, h.GasUsedRatio())
+	require.Len(h.Rewards, 100/FeeHistoryPercentileStep+1)
+
+	// the gas used by the tips: 10 -> 10%, 20 -> 70%, 30 -> 100%
+	require.Equal(big.NewInt(10), h.Reward(0))
+	require.Equal(big.NewInt(10), h.Reward(10))
+	require.Equal(big.NewInt(20), h.Reward(10.5)) // rounded up to 15%
+	require.Equal(big.NewInt(20), h.Reward(70))
+	require.Equal(big.NewInt(30), h.Reward(71))
+	require.Equal(big.NewInt(30), h.Reward(100))
+
+	b, err := rlp.EncodeToBytes(&h)
+	require.NoError(err)
+	var decoded BlockFeeHistory
+	require.NoError(rlp.DecodeBytes(b, &decoded))
+	require.Equal(h, decoded)
+
+	empty := NewBlockFeeHistory(nil, 0, 300000, nil, nil)
+	require.Zero(empty.GasUsedRatio())
+	require.Empty(empty.Rewards)
+	require.Equal(new(big.Int), empty.Reward(50))
+}
@@ -0,0 +1,71 @@
+package evmcore
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// opcodeForks defines the instructions introduced after Frontier.
+var opcodeForks = map[vm.OpCode]func(params.Rules) bool{
+	vm.DELEGATECALL:   func(r params.Rules) bool { return r.IsHomestead },
+	vm.RETURNDATASIZE: func(r params.Rules) bool { return r.IsByzantium },
+	vm.RETURNDATACOPY: func(r params.Rules) bool { return r.IsByzantium },
+	vm.STATICCALL:     func(r params.Rules) bool { return r.IsByzantium },
+	vm.REVERT:         func(r params.Rules) bool { return r.IsByzantium },
+	vm.SHL:            func(r params.Rules) bool { return r.IsConstantinople },
+	vm.SHR:            func(r params.Rules) bool { return r.IsConstantinople },
+	vm.SAR:            func(r params.Rules) bool { return r.IsConstantinople },
+	vm.EXTCODEHASH:    func(r params.Rules) bool { return r.IsConstantinople },
+	vm.CREATE2:        func(r params.Rules) bool { return r.IsConstantinople },
+	vm.CHAINID:        func(r params.Rules) bool { return r.IsIstanbul },
+	vm.SELFBALANCE:    func(r params.Rules) bool { return r.IsIstanbul },
+	vm.BASEFEE:        func(r params.Rules) bool { return r.IsLondon },
+}
+
+// opcodeFeatures defines the instructions of the EIPs, the activation of which is reported by flags.
+var opcodeFeatures = map[string][]vm.OpCode{
+	"transientStorage": {0x5c, 0x5d}, // EIP-1153 TLOAD, TSTORE
+	"mcopy":            {0x5e},       // EIP-5656 MCOPY
+	"push0":            {0x5f},       // EIP-3855 PUSH0
+}
+
+// ActiveOpcodes returns the instructions supported by the EVM under the chain rules.
+// EIP-1153 (TLOAD, TSTORE), EIP-3855 (PUSH0) and EIP-5656 (MCOPY) aren't implemented by the EVM,
+// so these instructions are never active.
+func ActiveOpcodes(rules params.Rules) []vm.OpCode {
+	ops := make([]vm.OpCode, 0, 256)
+	for i := 0; i < 256; i++ {
+		op := vm.OpCode(i)
+		// PUSH, DUP and SWAP are pseudo instructions
+		if op == vm.PUSH || op == vm.DUP || op == vm.SWAP {
+			continue
+		}
+		if strings.HasPrefix(op.String(), "opcode ") {
+			continue
+		}
+		if active, ok := opcodeForks[op]; ok && !active(rules) {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// ActiveFeatures returns the activation flags of the EIPs introducing instructions, like transient storage and MCOPY.
+// A feature is active if all its instructions are active under the chain rules.
+func ActiveFeatures(rules params.Rules) map[string]bool {
+	active := make(map[vm.OpCode]bool)
+	for _, op := range ActiveOpcodes(rules) {
+		active[op] = true
+	}
+	features := make(map[string]bool, len(opcodeFeatures))
+	for name, ops := range opcodeFeatures {
+		features[name] = true
+		for _, op := range ops {
+			features[name] = features[name] && active[op]
+		}
+	}
+	return features
+}
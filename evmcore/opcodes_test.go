@@ -0,0 +1,33 @@
+package evmcore
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+func TestActiveOpcodes(t *testing.T) {
+	require := require.New(t)
+
+	rules := opera.FakeNetRules()
+	beforeLondon := ActiveOpcodes(rules.EvmChainConfig(nil).Rules(common.Big0))
+	require.Contains(beforeLondon, vm.CHAINID)
+	require.Contains(beforeLondon, vm.PUSH32)
+	require.NotContains(beforeLondon, vm.BASEFEE)
+	require.NotContains(beforeLondon, vm.PUSH)
+
+	hh := []opera.UpgradeHeight{{Upgrades: opera.Upgrades{Berlin: true, London: true}}}
+	afterLondon := ActiveOpcodes(rules.EvmChainConfig(hh).Rules(common.Big0))
+	require.Contains(afterLondon, vm.BASEFEE)
+	require.Len(afterLondon, len(beforeLondon)+1)
+
+	// not supported by the EVM
+	for _, op := range []byte{0x5c, 0x5d, 0x5e, 0x5f} {
+		require.NotContains(afterLondon, vm.OpCode(op))
+	}
+	require.Equal(map[string]bool{"transientStorage": false, "mcopy": false, "push0": false}, ActiveFeatures(rules.EvmChainConfig(hh).Rules(common.Big0)))
+}
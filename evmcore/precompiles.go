@@ -0,0 +1,156 @@
+package evmcore
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// EIP-2537 precompiled contracts addresses
+var (
+	bls12381G1AddAddr      = common.BytesToAddress([]byte{10})
+	bls12381G1MulAddr      = common.BytesToAddress([]byte{11})
+	bls12381G1MultiExpAddr = common.BytesToAddress([]byte{12})
+	bls12381G2AddAddr      = common.BytesToAddress([]byte{13})
+	bls12381G2MulAddr      = common.BytesToAddress([]byte{14})
+	bls12381G2MultiExpAddr = common.BytesToAddress([]byte{15})
+	bls12381PairingAddr    = common.BytesToAddress([]byte{16})
+	bls12381MapG1Addr      = common.BytesToAddress([]byte{17})
+	bls12381MapG2Addr      = common.BytesToAddress([]byte{18})
+)
+
+// precompilesInterpreter is the name of the geth interpreter, which runs the stateless
+// precompiled contracts of the network rules called by STATICCALL, DELEGATECALL or CALLCODE.
+const precompilesInterpreter = "geth-precompiles"
+
+func init() {
+	vm.RegisterInterpreterFactory(precompilesInterpreter, func(evm *vm.EVM, cfg vm.Config) vm.EVMInterpreter {
+		return newPrecompilesInterpreter(evm, cfg)
+	})
+}
+
+func newPrecompilesInterpreter(evm *vm.EVM, cfg vm.Config) vm.EVMInterpreter {
+	return &precompilesEVMInterpreter{
+		EVMInterpreter: vm.NewEVMInterpreter(evm, cfg),
+		precompiles:    cfg.StatePrecompiles,
+	}
+}
+
+// VMConfig returns the EVM config with the precompiled contracts and the EOF validation enabled by the network rules.
+func VMConfig(rules opera.Rules) vm.Config {
+	cfg := opera.DefaultVMConfig
+	if rules.Upgrades.Bls {
+		cfg = blsVMConfig(rules)
+	}
+	if rules.Upgrades.Eof {
+		switch cfg.InterpreterImpl {
+		case "", "geth":
+			cfg.InterpreterImpl = eofInterpreter
+		case precompilesInterpreter:
+			cfg.InterpreterImpl = eofPrecompilesInterpreter
+		}
+	}
+	return cfg
+}
+
+func blsVMConfig(rules opera.Rules) vm.Config {
+	cfg := opera.DefaultVMConfig
+	cfg.InterpreterImpl = precompilesInterpreter
+	cfg.StatePrecompiles = make(map[common.Address]vm.PrecompiledStateContract, len(opera.DefaultVMConfig.StatePrecompiles)+len(vm.PrecompiledContractsBLS))
+	for addr, c := range opera.DefaultVMConfig.StatePrecompiles {
+		cfg.StatePrecompiles[addr] = c
+	}
+	gas := bls12381Gas(rules.Precompiles.Bls12381)
+	for addr, c := range vm.PrecompiledContractsBLS {
+		cfg.StatePrecompiles[addr] = &gasScheduledPrecompile{
+			contract: c,
+			gas:      gas[addr],
+		}
+	}
+	return cfg
+}
+
+// precompilesEVMInterpreter runs the stateless precompiled contracts of the network rules.
+// State precompiles are dispatched by the EVM only for CALL, the other calls run the interpreter
+// with the code of the contract address, which is empty for a precompiled contract.
+type precompilesEVMInterpreter struct {
+	vm.EVMInterpreter
+	precompiles map[common.Address]vm.PrecompiledStateContract
+}
+
+func (in *precompilesEVMInterpreter) Run(contract *vm.Contract, input []byte, readOnly bool) ([]byte, error) {
+	if contract.CodeAddr != nil {
+		if p, ok := in.precompiles[*contract.CodeAddr].(*gasScheduledPrecompile); ok {
+			ret, left, err := p.Run(nil, vm.BlockContext{}, vm.TxContext{}, *contract.CodeAddr, input, contract.Gas)
+			contract.Gas = left
+			return ret, err
+		}
+	}
+	return in.EVMInterpreter.Run(contract, input, readOnly)
+}
+
+// gasScheduledPrecompile runs a stateless precompiled contract with the gas cost defined by the network rules.
+type gasScheduledPrecompile struct {
+	contract vm.PrecompiledContract
+	gas      func(input []byte) uint64
+}
+
+func (p *gasScheduledPrecompile) Run(_ vm.StateDB, _ vm.BlockContext, _ vm.TxContext, _ common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
+	gasCost := p.gas(input)
+	if suppliedGas < gasCost {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	output, err := p.contract.Run(input)
+	return output, suppliedGas - gasCost, err
+}
+
+func orDefault(v, def uint64) uint64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func constGas(gas uint64) func([]byte) uint64 {
+	return func([]byte) uint64 {
+		return gas
+	}
+}
+
+// multiExpGas calculates the cost of the multi-exponentiation with the EIP-2537 discounts.
+func multiExpGas(pairSize int, mulGas uint64) func([]byte) uint64 {
+	return func(input []byte) uint64 {
+		k := len(input) / pairSize
+		if k == 0 {
+			return 0
+		}
+		table := params.Bls12381MultiExpDiscountTable
+		discount := table[len(table)-1]
+		if k < len(table) {
+			discount = table[k-1]
+		}
+		return (uint64(k) * mulGas * discount) / 1000
+	}
+}
+
+func bls12381Gas(rules opera.Bls12381GasRules) map[common.Address]func([]byte) uint64 {
+	g1Mul := orDefault(rules.G1MulGas, params.Bls12381G1MulGas)
+	g2Mul := orDefault(rules.G2MulGas, params.Bls12381G2MulGas)
+	pairingBase := orDefault(rules.PairingBaseGas, params.Bls12381PairingBaseGas)
+	pairingPerPair := orDefault(rules.PairingPerPairGas, params.Bls12381PairingPerPairGas)
+	return map[common.Address]func([]byte) uint64{
+		bls12381G1AddAddr:      constGas(orDefault(rules.G1AddGas, params.Bls12381G1AddGas)),
+		bls12381G1MulAddr:      constGas(g1Mul),
+		bls12381G1MultiExpAddr: multiExpGas(160, g1Mul),
+		bls12381G2AddAddr:      constGas(orDefault(rules.G2AddGas, params.Bls12381G2AddGas)),
+		bls12381G2MulAddr:      constGas(g2Mul),
+		bls12381G2MultiExpAddr: multiExpGas(288, g2Mul),
+		bls12381PairingAddr: func(input []byte) uint64 {
+			return pairingBase + uint64(len(input)/384)*pairingPerPair
+		},
+		bls12381MapG1Addr: constGas(orDefault(rules.MapG1Gas, params.Bls12381MapG1Gas)),
+		bls12381MapG2Addr: constGas(orDefault(rules.MapG2Gas, params.Bls12381MapG2Gas)),
+	}
+}
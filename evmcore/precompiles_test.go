@@ -0,0 +1,97 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/evmwriter"
+)
+
+func TestVMConfigBls(t *testing.T) {
+	require := require.New(t)
+
+	rules := opera.FakeNetRules()
+	cfg := VMConfig(rules)
+	require.Len(cfg.StatePrecompiles, 1)
+	require.NotNil(cfg.StatePrecompiles[evmwriter.ContractAddress])
+	_, ok := cfg.StatePrecompiles[bls12381G1AddAddr]
+	require.False(ok)
+
+	rules.Upgrades.Bls = true
+	rules.Precompiles.Bls12381.G1AddGas = 100
+	cfg = VMConfig(rules)
+	require.Len(cfg.StatePrecompiles, 1+len(vm.PrecompiledContractsBLS))
+	require.NotNil(cfg.StatePrecompiles[evmwriter.ContractAddress])
+	// default config isn't modified
+	require.Len(opera.DefaultVMConfig.StatePrecompiles, 1)
+
+	// configured gas
+	g1Add := cfg.StatePrecompiles[bls12381G1AddAddr].(*gasScheduledPrecompile)
+	require.Equal(uint64(100), g1Add.gas(nil))
+	_, left, err := g1Add.Run(nil, vm.BlockContext{}, vm.TxContext{}, bls12381G1AddAddr, nil, 50)
+	require.Equal(vm.ErrOutOfGas, err)
+	require.Zero(left)
+
+	// default gas
+	mapG1 := cfg.StatePrecompiles[bls12381MapG1Addr].(*gasScheduledPrecompile)
+	require.Equal(params.Bls12381MapG1Gas, mapG1.gas(nil))
+	pairing := cfg.StatePrecompiles[bls12381PairingAddr].(*gasScheduledPrecompile)
+	require.Equal(params.Bls12381PairingBaseGas+2*params.Bls12381PairingPerPairGas, pairing.gas(make([]byte, 2*384)))
+	multiExp := cfg.StatePrecompiles[bls12381G1MultiExpAddr].(*gasScheduledPrecompile)
+	require.Equal(params.Bls12381G1MulGas*params.Bls12381MultiExpDiscountTable[0]/1000, multiExp.gas(make([]byte, 160)))
+}
+
+// staticCallG1AddCode is the code, which passes the call data to the BLS12-381 G1 addition
+// by STATICCALL and returns the 128 bytes of the output followed by the call success flag
+var staticCallG1AddCode = hexutil.MustDecode("0x" +
+	"3660006000" + "37" + // CALLDATACOPY(0, 0, CALLDATASIZE)
+	"6080" + "610100" + "36" + "6000" + "600a" + "5a" + "fa" + // STATICCALL(GAS, 0x0a, 0, CALLDATASIZE, 0x100, 0x80)
+	"610180" + "52" + // MSTORE(0x180, success)
+	"60a0" + "610100" + "f3") // RETURN(0x100, 0xa0)
+
+func TestVMConfigBlsStaticCall(t *testing.T) {
+	require := require.New(t)
+
+	staticCall := func(rules opera.Rules, input []byte) (output []byte, success bool) {
+		statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		require.NoError(err)
+		contract := common.Address{0xc0}
+		statedb.SetCode(contract, staticCallG1AddCode)
+		blockCtx := vm.BlockContext{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			BlockNumber: big.NewInt(1),
+			Difficulty:  big.NewInt(0),
+			BaseFee:     big.NewInt(0),
+		}
+		evm := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, rules.EvmChainConfig(nil), VMConfig(rules))
+		ret, _, err := evm.Call(vm.AccountRef(common.Address{0x01}), contract, input, 10_000_000, new(big.Int))
+		require.NoError(err)
+		require.Len(ret, 0xa0)
+		return ret[:0x80], ret[0x9f] == 1
+	}
+
+	rules := opera.FakeNetRules()
+	rules.Upgrades.Bls = true
+	// the sum of two infinity points is the infinity point
+	output, success := staticCall(rules, make([]byte, 256))
+	require.True(success)
+	require.Equal(make([]byte, 128), output)
+	// the malformed input fails the precompiled contract
+	_, success = staticCall(rules, make([]byte, 255))
+	require.False(success)
+
+	// the address has no code without the upgrade
+	rules.Upgrades.Bls = false
+	_, success = staticCall(rules, make([]byte, 255))
+	require.True(success)
+}
@@ -36,8 +36,17 @@ import (
 //
 // StateProcessor implements Processor.
 type StateProcessor struct {
-	config *params.ChainConfig // Chain configuration options
-	bc     DummyChain          // Canonical block chain
+	config   *params.ChainConfig // Chain configuration options
+	bc       DummyChain          // Canonical block chain
+	txTracer TxTracer            // Tracer of the processed transactions, optional
+}
+
+// TxTracer traces the transactions executed by the state processor.
+type TxTracer interface {
+	// StartTx returns the tracer of the transaction at the index within the block, or nil if it isn't traced.
+	StartTx(block *EvmBlock, tx *types.Transaction, msg types.Message, index int) vm.Tracer
+	// EndTx is called once the traced transaction is applied. The receipt is nil if the transaction is skipped.
+	EndTx(tx *types.Transaction, receipt *types.Receipt)
 }
 
 // NewStateProcessor initialises a new StateProcessor.
@@ -48,6 +57,11 @@ func NewStateProcessor(config *params.ChainConfig, bc DummyChain) *StateProcesso
 	}
 }
 
+// SetTxTracer sets the tracer of the transactions executed by the processor.
+func (p *StateProcessor) SetTxTracer(t TxTracer) {
+	p.txTracer = t
+}
+
 // Process processes the state changes according to the Ethereum rules by running
 // the transaction messages using the statedb and applying any rewards to both
 // the processor (coinbase) and any included uncles.
@@ -80,7 +94,21 @@ func (p *StateProcessor) Process(
 		}
 
 		statedb.Prepare(tx.Hash(), i)
-		receipt, _, skip, err = applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv, onNewLog)
+		evm := vmenv
+		var tracer vm.Tracer
+		if p.txTracer != nil {
+			if tracer = p.txTracer.StartTx(block, tx, msg, i); tracer != nil {
+				// the interpreter keeps the config it's created with, so the traced tx gets its own EVM
+				tracedCfg := cfg
+				tracedCfg.Debug = true
+				tracedCfg.Tracer = tracer
+				evm = vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, tracedCfg)
+			}
+		}
+		receipt, _, skip, err = applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, evm, onNewLog)
+		if tracer != nil {
+			p.txTracer.EndTx(tx, receipt)
+		}
 		if skip {
 			skipped = append(skipped, uint32(i))
 			err = nil
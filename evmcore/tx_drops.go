@@ -0,0 +1,64 @@
+package evmcore
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+)
+
+// txDropsLimit is the number of the recently dropped transactions, the reasons of which are remembered.
+const txDropsLimit = 16384
+
+// Reasons of the transactions removal from the pool before they are included into a block.
+const (
+	DropReplaced    = "replaced"
+	DropUnderpriced = "underpriced"
+	DropNonceTooLow = "nonce too low"
+	DropUnpayable   = "insufficient funds or gas limit exceeded"
+	DropPoolLimit   = "pool limit exceeded"
+	DropExpired     = "lifetime expired"
+)
+
+// TxDrop is a record of a transaction removed from the pool.
+type TxDrop struct {
+	Reason     string
+	ReplacedBy common.Hash // hash of the replacing transaction, if the Reason is DropReplaced
+	Time       time.Time
+}
+
+// txDrops remembers the reasons of the recently dropped transactions.
+type txDrops struct {
+	cache *lru.Cache[common.Hash, TxDrop]
+}
+
+func newTxDrops(limit int) *txDrops {
+	return &txDrops{
+		cache: lru.New[common.Hash, TxDrop](uint(limit), limit, nil),
+	}
+}
+
+func (d *txDrops) add(hash common.Hash, reason string, replacedBy common.Hash) {
+	d.cache.Add(hash, TxDrop{
+		Reason:     reason,
+		ReplacedBy: replacedBy,
+		Time:       time.Now(),
+	})
+}
+
+func (d *txDrops) addAll(txs []*types.Transaction, reason string) {
+	for _, tx := range txs {
+		d.add(tx.Hash(), reason, common.Hash{})
+	}
+}
+
+// revive forgets the drop of the transaction which is added to the pool again.
+func (d *txDrops) revive(hash common.Hash) {
+	d.cache.Remove(hash)
+}
+
+func (d *txDrops) get(hash common.Hash) (TxDrop, bool) {
+	return d.cache.Peek(hash)
+}
@@ -18,6 +18,7 @@ package evmcore
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
@@ -34,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/Fantom-foundation/go-opera/evmcore/eof"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 	"github.com/Fantom-foundation/go-opera/utils/txtime"
 )
@@ -87,6 +89,14 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrMaxInitCodeSizeExceeded is returned if creation transaction provides the init code bigger
+	// than init code size limit.
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+
+	// ErrInvalidEOF is returned if creation transaction provides the init code with the EOF magic,
+	// which isn't a valid EOF container.
+	ErrInvalidEOF = errors.New("invalid EOF contract")
 )
 
 var (
@@ -157,6 +167,8 @@ type StateReader interface {
 	MinGasPrice() *big.Int
 	EffectiveMinTip() *big.Int
 	MaxGasLimit() uint64
+	MaxInitCodeSize() uint64
+	EofEnabled() bool
 	SubscribeNewBlock(ch chan<- ChainHeadNotify) notify.Subscription
 	Config() *params.ChainConfig
 }
@@ -259,6 +271,8 @@ type TxPool struct {
 	currentState  TxPoolStateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
+	maxInitCode   uint64         // Current init code size limit of creation transactions (0 = no limit)
+	eof           bool           // Whether EOF contracts are validated on deployment
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -268,6 +282,7 @@ type TxPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
+	drops   *txDrops                     // Reasons of the recently dropped transactions
 
 	chainHeadCh     chan ChainHeadNotify
 	chainHeadSub    notify.Subscription
@@ -299,6 +314,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain State
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
 		all:             newTxLookup(),
+		drops:           newTxDrops(txDropsLimit),
 		chainHeadCh:     make(chan ChainHeadNotify, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
@@ -398,6 +414,7 @@ func (pool *TxPool) loop() {
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true)
 					}
+					pool.drops.addAll(list, DropExpired)
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
@@ -492,6 +509,16 @@ func (pool *TxPool) Count() int {
 	return pool.all.Count()
 }
 
+// MemoryUsage returns the total encoded size of the pooled transactions.
+func (pool *TxPool) MemoryUsage() uint64 {
+	size := uint64(0)
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
+		size += uint64(tx.Size())
+		return true
+	}, true, true)
+	return size
+}
+
 // stats retrieves the current pool stats, namely the number of pending and the
 // number of queued (non-executable) transactions.
 func (pool *TxPool) stats() (int, int) {
@@ -657,6 +684,14 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if uint64(tx.Size()) > txMaxSize {
 		return ErrOversizedData
 	}
+	// Reject creation transactions with the init code over the limit, they would fail anyway
+	if err := CheckInitCodeSize(tx, pool.maxInitCode); err != nil {
+		return err
+	}
+	// Reject creation transactions with the malformed EOF init code, they would fail anyway
+	if err := CheckEOF(tx, pool.eof); err != nil {
+		return err
+	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -719,6 +754,24 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// CheckInitCodeSize checks the init code size of the creation transaction against the limit (0 = no limit).
+func CheckInitCodeSize(tx *types.Transaction, limit uint64) error {
+	if limit != 0 && tx.To() == nil && uint64(len(tx.Data())) > limit {
+		return fmt.Errorf("%w: code size %d, limit %d", ErrMaxInitCodeSizeExceeded, len(tx.Data()), limit)
+	}
+	return nil
+}
+
+// CheckEOF validates the EOF init code of the creation transaction, if the EOF validation is enabled.
+func CheckEOF(tx *types.Transaction, enabled bool) error {
+	if enabled && tx.To() == nil && eof.HasMagic(tx.Data()) {
+		if _, err := eof.Validate(tx.Data()); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEOF, err)
+		}
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -771,6 +824,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
 			pool.removeTx(tx.Hash(), false) // don't remove from priced, already removed by Discard
+			pool.drops.add(tx.Hash(), DropUnderpriced, common.Hash{})
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
@@ -786,8 +840,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
+			pool.drops.add(old.Hash(), DropReplaced, hash)
 			pendingReplaceMeter.Mark(1)
 		}
+		pool.drops.revive(hash)
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
@@ -848,6 +904,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.drops.add(old.Hash(), DropReplaced, hash)
 		queuedReplaceMeter.Mark(1)
 	} else {
 		// Nothing was replaced, bump the queued counter
@@ -859,6 +916,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		log.Error("Missing transaction in lookup set, please report the issue", "hash", hash)
 	}
 	if addAll {
+		pool.drops.revive(hash)
 		pool.all.Add(tx, local)
 		pool.priced.Put(tx, local)
 	}
@@ -897,6 +955,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.drops.add(hash, DropUnderpriced, common.Hash{})
 		pendingDiscardMeter.Mark(1)
 		return false
 	}
@@ -904,6 +963,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.drops.add(old.Hash(), DropReplaced, hash)
 		pendingReplaceMeter.Mark(1)
 	} else {
 		// Nothing was replaced, bump the pending counter
@@ -1059,6 +1119,13 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 	return status
 }
 
+// Dropped returns the reason of removal of a recently dropped transaction.
+// Note, the transactions included into a block are recorded as DropNonceTooLow too,
+// so the inclusion has to be checked first.
+func (pool *TxPool) Dropped(hash common.Hash) (TxDrop, bool) {
+	return pool.drops.get(hash)
+}
+
 // Get returns a transaction if it is contained in the pool and nil otherwise.
 func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
@@ -1391,6 +1458,8 @@ func (pool *TxPool) reset(oldHead, newHead *EvmHeader) {
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = pool.chain.MaxGasLimit()
+	pool.maxInitCode = pool.chain.MaxInitCodeSize()
+	pool.eof = pool.chain.EofEnabled()
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
@@ -1423,6 +1492,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.drops.addAll(forwards, DropNonceTooLow)
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
@@ -1430,6 +1500,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.drops.addAll(drops, DropUnpayable)
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
@@ -1453,6 +1524,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 				pool.all.Remove(hash)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
+			pool.drops.addAll(caps, DropPoolLimit)
 			queuedRateLimitMeter.Mark(int64(len(caps)))
 		}
 		// Mark all the items dropped as removed
@@ -1519,6 +1591,7 @@ func (pool *TxPool) truncatePending() {
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
+					pool.drops.addAll(caps, DropPoolLimit)
 					pool.priced.Removed(len(caps))
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
@@ -1546,6 +1619,7 @@ func (pool *TxPool) truncatePending() {
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
+				pool.drops.addAll(caps, DropPoolLimit)
 				pool.priced.Removed(len(caps))
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
@@ -1588,6 +1662,7 @@ func (pool *TxPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true)
+				pool.drops.add(tx.Hash(), DropPoolLimit, common.Hash{})
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1597,6 +1672,7 @@ func (pool *TxPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true)
+			pool.drops.add(txs[i].Hash(), DropPoolLimit, common.Hash{})
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1622,6 +1698,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		pool.drops.addAll(olds, DropNonceTooLow)
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
@@ -1629,6 +1706,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
 		}
+		pool.drops.addAll(drops, DropUnpayable)
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
 		for _, tx := range invalids {
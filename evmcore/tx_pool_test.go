@@ -110,6 +110,12 @@ func (bc *testBlockChain) EffectiveMinTip() *big.Int {
 func (bc *testBlockChain) MaxGasLimit() uint64 {
 	return bc.CurrentBlock().GasLimit
 }
+func (bc *testBlockChain) MaxInitCodeSize() uint64 {
+	return 0
+}
+func (bc *testBlockChain) EofEnabled() bool {
+	return false
+}
 func (bc *testBlockChain) Config() *params.ChainConfig {
 	return nil
 }
@@ -341,6 +347,25 @@ func TestInvalidTransactions(t *testing.T) {
 	if err := pool.AddLocal(tx); err != nil {
 		t.Error("expected", nil, "got", err)
 	}
+
+	pool.maxInitCode = 10
+	creation, _ := types.SignTx(types.NewContractCreation(2, big.NewInt(0), 100000, big.NewInt(1000), make([]byte, 11)), types.HomesteadSigner{}, key)
+	if err := pool.AddLocal(creation); !errors.Is(err, ErrMaxInitCodeSizeExceeded) {
+		t.Error("expected", ErrMaxInitCodeSizeExceeded, "got", err)
+	}
+
+	pool.maxInitCode = 0
+	pool.eof = true
+	malformed := common.FromHex("ef00020100040200010001ff0000000080000000") // wrong version
+	creation, _ = types.SignTx(types.NewContractCreation(2, big.NewInt(0), 100000, big.NewInt(1000), malformed), types.HomesteadSigner{}, key)
+	if err := pool.AddLocal(creation); !errors.Is(err, ErrInvalidEOF) {
+		t.Error("expected", ErrInvalidEOF, "got", err)
+	}
+	valid := common.FromHex("ef00010100040200010001ff0000000080000000")
+	creation, _ = types.SignTx(types.NewContractCreation(2, big.NewInt(0), 100000, big.NewInt(1000), valid), types.HomesteadSigner{}, key)
+	if err := pool.AddLocal(creation); err != nil {
+		t.Error("expected", nil, "got", err)
+	}
 }
 
 func TestTransactionQueue(t *testing.T) {
@@ -2215,6 +2240,39 @@ func TestTransactionReplacement(t *testing.T) {
 	}
 }
 
+// Tests that the pool remembers the replaced transactions and the replacing ones.
+func TestTransactionReplacementDropped(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	for _, nonce := range []uint64{0, 2} { // pending and queued
+		orig := pricedTransaction(nonce, 100000, big.NewInt(1), key)
+		replacing := pricedTransaction(nonce, 100000, big.NewInt(2), key)
+		if err := pool.addRemoteSync(orig); err != nil {
+			t.Fatalf("failed to add original transaction: %v", err)
+		}
+		if _, ok := pool.Dropped(orig.Hash()); ok {
+			t.Fatalf("pooled transaction %d is dropped", nonce)
+		}
+		if err := pool.addRemoteSync(replacing); err != nil {
+			t.Fatalf("failed to replace transaction: %v", err)
+		}
+		drop, ok := pool.Dropped(orig.Hash())
+		if !ok || drop.Reason != DropReplaced || drop.ReplacedBy != replacing.Hash() {
+			t.Fatalf("replaced transaction %d drop mismatch: have %v %v, want %s by %s", nonce, ok, drop, DropReplaced, replacing.Hash())
+		}
+		if _, ok := pool.Dropped(replacing.Hash()); ok {
+			t.Fatalf("replacing transaction %d is dropped", nonce)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that the pool rejects replacement dynamic fee transactions that don't
 // meet the minimum price bump required.
 func TestTransactionReplacementDynamicFee(t *testing.T) {
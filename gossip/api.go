@@ -16,13 +16,16 @@ func NewPublicEthereumAPI(s *Service) *PublicEthereumAPI {
 	return &PublicEthereumAPI{s}
 }
 
-// Etherbase returns the zero address for web3 compatibility
+// Etherbase returns the validator reward recipient, or the zero address for web3 compatibility
 func (api *PublicEthereumAPI) Etherbase() (common.Address, error) {
-	return common.Address{}, nil
+	return api.Coinbase()
 }
 
-// Coinbase returns the zero address for web3 compatibility
+// Coinbase returns the validator reward recipient, or the zero address for web3 compatibility
 func (api *PublicEthereumAPI) Coinbase() (common.Address, error) {
+	if em := api.s.validatorEmitter(); em != nil {
+		return em.RewardRecipient(), nil
+	}
 	return common.Address{}, nil
 }
 
@@ -33,5 +36,5 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 
 // ChainId is the EIP-155 replay-protection chain id for the current ethereum chain config.
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
-	return hexutil.Uint64(api.s.store.GetRules().NetworkID)
+	return hexutil.Uint64(api.s.store.GetRules().ChainID().Uint64())
 }
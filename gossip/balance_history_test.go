@@ -0,0 +1,47 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestGetBalanceHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 2, t)
+	defer env.Close()
+
+	var blocks []rpc.BlockNumber
+	for i := 0; i < 3; i++ {
+		receipts, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, big.NewInt(1)))
+		require.NoError(err)
+		blocks = append(blocks, rpc.BlockNumber(receipts[0].BlockNumber.Int64()))
+	}
+	first, _ := env.store.evm.BalanceHistoryRange()
+
+	api := ethapi.NewPublicBlockChainAPI(env.EthAPI)
+	history, err := api.GetBalanceHistory(context.Background(), env.Address(2), blocks[0], blocks[2])
+	require.NoError(err)
+	require.Len(history, int(blocks[2]-blocks[0])+1)
+	for i := 1; i < len(history); i++ {
+		if diff := new(big.Int).Sub(history[i].Balance.ToInt(), history[i-1].Balance.ToInt()); diff.Sign() != 0 {
+			require.Equal(big.NewInt(1), diff)
+		}
+	}
+	require.Equal(big.NewInt(2), new(big.Int).Sub(history[len(history)-1].Balance.ToInt(), history[0].Balance.ToInt()))
+
+	// the history before the journal start isn't returned truncated
+	require.Greater(first, idx.Block(1))
+	_, err = api.GetBalanceHistory(context.Background(), env.Address(2), rpc.BlockNumber(first)-2, blocks[2])
+	var unavailable *ethapi.HistoryUnavailableError
+	require.ErrorAs(err, &unavailable)
+}
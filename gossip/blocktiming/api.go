@@ -0,0 +1,60 @@
+package blocktiming
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BlockTimings are the durations of the phases of a block processing, in milliseconds.
+type BlockTimings struct {
+	Block       hexutil.Uint64 `json:"block"`
+	Txs         hexutil.Uint64 `json:"transactions"`
+	Execution   float64        `json:"execution"`
+	Commit      float64        `json:"commit"`
+	Receipts    float64        `json:"receipts"`
+	LogIndexing float64        `json:"logIndexing"`
+	Write       float64        `json:"write"`
+	Total       float64        `json:"total"`
+}
+
+// PublicAPI exposes the block processing timings.
+type PublicAPI struct {
+	t *Tracker
+}
+
+// NewPublicAPI creates a new block timings API.
+func NewPublicAPI(t *Tracker) *PublicAPI {
+	return &PublicAPI{t}
+}
+
+// BlockTimings returns the phases durations of the block, or of the latest block if the number isn't specified.
+// Only the latest blocks processed since the node start are available.
+func (api *PublicAPI) BlockTimings(number *hexutil.Uint64) *BlockTimings {
+	var (
+		t  Timings
+		ok bool
+	)
+	if number == nil {
+		t, ok = api.t.Latest()
+	} else {
+		t, ok = api.t.Get(idx.Block(*number))
+	}
+	if !ok {
+		return nil
+	}
+	return &BlockTimings{
+		Block:       hexutil.Uint64(t.Block),
+		Txs:         hexutil.Uint64(t.Txs),
+		Execution:   millis(t.Execution),
+		Commit:      millis(t.Commit),
+		Receipts:    millis(t.Receipts),
+		LogIndexing: millis(t.LogIndexing),
+		Write:       millis(t.Write),
+		Total:       millis(t.Total),
+	}
+}
+
+// BlockTimingsStats returns the distributions of the phases durations over the latest blocks.
+func (api *PublicAPI) BlockTimingsStats() Stats {
+	return api.t.Stats()
+}
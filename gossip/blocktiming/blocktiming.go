@@ -0,0 +1,178 @@
+// Package blocktiming records how the time of the block processing is spent among its phases.
+package blocktiming
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxBlocks is the number of the latest blocks, the timings of which are kept
+const maxBlocks = 1024
+
+var (
+	executionTimer   = metrics.GetOrRegisterTimer("chain/phase/execution", nil)
+	commitTimer      = metrics.GetOrRegisterTimer("chain/phase/commit", nil)
+	receiptsTimer    = metrics.GetOrRegisterTimer("chain/phase/receipts", nil)
+	logIndexingTimer = metrics.GetOrRegisterTimer("chain/phase/logindexing", nil)
+	writeTimer       = metrics.GetOrRegisterTimer("chain/phase/write", nil)
+)
+
+// Timings are the durations of the phases of a block processing.
+type Timings struct {
+	Block idx.Block
+	Txs   int
+	// Execution is the execution of the transactions, including the internal ones
+	Execution time.Duration
+	// Commit is the finalization of the block and the commit of the state
+	Commit time.Duration
+	// Receipts is the writing of the receipts and the transactions positions
+	Receipts time.Duration
+	// LogIndexing is the indexing of the logs
+	LogIndexing time.Duration
+	// Write is the writing of the block, the block and epoch states and the rest of the block data
+	Write time.Duration
+	// Total is the whole block processing, including the consensus callbacks and the phases above
+	Total time.Duration
+}
+
+// Tracker keeps the timings of the latest blocks.
+// All methods are safe for concurrent use, and a nil Tracker ignores all calls.
+type Tracker struct {
+	mu     sync.Mutex
+	blocks []Timings
+	next   int
+}
+
+// New creates a new Tracker.
+func New() *Tracker {
+	return &Tracker{
+		blocks: make([]Timings, 0, maxBlocks),
+	}
+}
+
+// Add records the timings of a processed block.
+func (t *Tracker) Add(timings Timings) {
+	if t == nil {
+		return
+	}
+	executionTimer.Update(timings.Execution)
+	commitTimer.Update(timings.Commit)
+	receiptsTimer.Update(timings.Receipts)
+	logIndexingTimer.Update(timings.LogIndexing)
+	writeTimer.Update(timings.Write)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.blocks) < maxBlocks {
+		t.blocks = append(t.blocks, timings)
+		return
+	}
+	t.blocks[t.next] = timings
+	t.next = (t.next + 1) % maxBlocks
+}
+
+// Get returns the timings of the block, if it's one of the latest blocks.
+func (t *Tracker) Get(n idx.Block) (Timings, bool) {
+	if t == nil {
+		return Timings{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, b := range t.blocks {
+		if b.Block == n {
+			return b, true
+		}
+	}
+	return Timings{}, false
+}
+
+// Latest returns the timings of the latest recorded block.
+func (t *Tracker) Latest() (Timings, bool) {
+	if t == nil {
+		return Timings{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.blocks) == 0 {
+		return Timings{}, false
+	}
+	return t.blocks[(t.next+len(t.blocks)-1)%len(t.blocks)], true
+}
+
+// Distribution summarizes the durations of a phase, in milliseconds.
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Stats are the distributions of the phases durations over the latest blocks.
+type Stats struct {
+	Blocks      int          `json:"blocks"`
+	Execution   Distribution `json:"execution"`
+	Commit      Distribution `json:"commit"`
+	Receipts    Distribution `json:"receipts"`
+	LogIndexing Distribution `json:"logIndexing"`
+	Write       Distribution `json:"write"`
+	Total       Distribution `json:"total"`
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func distribution(samples []time.Duration) Distribution {
+	if len(samples) == 0 {
+		return Distribution{}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	var sum time.Duration
+	for _, d := range samples {
+		sum += d
+	}
+	percentile := func(p int) float64 {
+		return millis(samples[(len(samples)-1)*p/100])
+	}
+	return Distribution{
+		Mean: millis(sum / time.Duration(len(samples))),
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P99:  percentile(99),
+		Max:  millis(samples[len(samples)-1]),
+	}
+}
+
+// Stats returns the distributions of the phases durations over the latest blocks.
+func (t *Tracker) Stats() Stats {
+	if t == nil {
+		return Stats{}
+	}
+	t.mu.Lock()
+	blocks := append([]Timings{}, t.blocks...)
+	t.mu.Unlock()
+
+	phase := func(get func(b Timings) time.Duration) Distribution {
+		samples := make([]time.Duration, len(blocks))
+		for i, b := range blocks {
+			samples[i] = get(b)
+		}
+		return distribution(samples)
+	}
+	return Stats{
+		Blocks:      len(blocks),
+		Execution:   phase(func(b Timings) time.Duration { return b.Execution }),
+		Commit:      phase(func(b Timings) time.Duration { return b.Commit }),
+		Receipts:    phase(func(b Timings) time.Duration { return b.Receipts }),
+		LogIndexing: phase(func(b Timings) time.Duration { return b.LogIndexing }),
+		Write:       phase(func(b Timings) time.Duration { return b.Write }),
+		Total:       phase(func(b Timings) time.Duration { return b.Total }),
+	}
+}
@@ -0,0 +1,53 @@
+package blocktiming
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+
+	tracker := New()
+	_, ok := tracker.Latest()
+	require.False(ok)
+
+	for n := idx.Block(1); n <= maxBlocks+10; n++ {
+		tracker.Add(Timings{
+			Block:     n,
+			Execution: time.Duration(n) * time.Millisecond,
+			Total:     2 * time.Duration(n) * time.Millisecond,
+		})
+	}
+	latest, ok := tracker.Latest()
+	require.True(ok)
+	require.Equal(idx.Block(maxBlocks+10), latest.Block)
+
+	// the oldest blocks are dropped
+	_, ok = tracker.Get(10)
+	require.False(ok)
+	b, ok := tracker.Get(11)
+	require.True(ok)
+	require.Equal(11*time.Millisecond, b.Execution)
+
+	stats := tracker.Stats()
+	require.Equal(maxBlocks, stats.Blocks)
+	require.Equal(float64(maxBlocks+10), stats.Execution.Max)
+	require.Equal(float64(2*(maxBlocks+10)), stats.Total.Max)
+	require.Equal(float64(11+(maxBlocks-1)/2), stats.Execution.P50)
+	require.Equal(Distribution{}, stats.Commit)
+}
+
+func TestTrackerNil(t *testing.T) {
+	require := require.New(t)
+
+	var tracker *Tracker
+	tracker.Add(Timings{Block: 1})
+	_, ok := tracker.Get(1)
+	require.False(ok)
+	require.Equal(Stats{}, tracker.Stats())
+	require.Nil(NewPublicAPI(tracker).BlockTimings(nil))
+}
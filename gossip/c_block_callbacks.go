@@ -20,12 +20,15 @@ import (
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/blockproc/verwatcher"
+	"github.com/Fantom-foundation/go-opera/gossip/blocktiming"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+	"github.com/Fantom-foundation/go-opera/gossip/eventtiming"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/utils"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 )
 
 var (
@@ -56,13 +59,18 @@ func (s *Service) GetConsensusCallbacks() lachesis.ConsensusCallbacks {
 			s.blockProcTasks,
 			&s.blockProcWg,
 			&s.blockBusyFlag,
+			&s.executionLag,
 			s.store,
 			s.blockProcModules,
 			s.config.TxIndex,
+			s.config.TxTracesPersist,
+			s.config.TxTracesRetention,
 			&s.feed,
 			&s.emitters,
 			s.verWatcher,
 			&s.bootstrapping,
+			s.timings,
+			s.blockTimings,
 		),
 	}
 }
@@ -73,13 +81,18 @@ func consensusCallbackBeginBlockFn(
 	parallelTasks *workers.Workers,
 	wg *sync.WaitGroup,
 	blockBusyFlag *uint32,
+	executionLag *int64,
 	store *Store,
 	blockProc BlockProc,
 	txIndex bool,
+	txTraces bool,
+	txTracesRetention idx.Block,
 	feed *ServiceFeed,
 	emitters *[]*emitter.Emitter,
 	verWatcher *verwatcher.VerWarcher,
 	bootstrapping *bool,
+	timings *eventtiming.Tracker,
+	blockTimings *blocktiming.Tracker,
 ) lachesis.BeginBlockFn {
 	return func(cBlock *lachesis.Block) lachesis.BlockCallbacks {
 		if *bootstrapping {
@@ -97,6 +110,8 @@ func consensusCallbackBeginBlockFn(
 		// Note: take copies to avoid race conditions with API calls
 		bs := store.GetBlockState().Copy()
 		es := store.GetEpochState().Copy()
+		// the txs of the blocks before the history start aren't indexed
+		txIndex := txIndex && store.hasHistory(bs.LastBlock.Idx+1)
 
 		// merge cheaters to ensure that every cheater will get punished even if only previous (not current) Atropos observed a doublesign
 		// this feature is needed because blocks may be skipped even if cheaters list isn't empty
@@ -108,6 +123,13 @@ func consensusCallbackBeginBlockFn(
 		if err != nil {
 			log.Crit("Failed to open StateDB", "err", err)
 		}
+		if expiry := store.cfg.EVM.StateExpiryEpochs; expiry != 0 {
+			statedb = store.evm.WrapAccessTracking(statedb, es.Epoch, expiry)
+		}
+		if txIndex {
+			statedb = store.evm.WrapCodeHistory(statedb)
+			statedb = store.evm.WrapBalanceHistory(statedb)
+		}
 		evmStateReader := &EvmStateReader{
 			ServiceFeed: feed,
 			store:       store,
@@ -119,6 +141,8 @@ func consensusCallbackBeginBlockFn(
 		atroposDegenerate := true
 		// events with txs
 		confirmedEvents := make(hash.OrderedEvents, 0, 3*es.Validators.Len())
+		// all the events, for the timings tracking
+		var orderedEvents []hash.Event
 
 		mpsCheatersMap := make(map[idx.ValidatorID]struct{})
 		reportCheater := func(reporter, cheater idx.ValidatorID) {
@@ -185,6 +209,10 @@ func consensusCallbackBeginBlockFn(
 				for _, em := range *emitters {
 					em.OnEventConfirmed(e)
 				}
+				if timings != nil {
+					timings.Ordered(e.ID(), time.Now())
+					orderedEvents = append(orderedEvents, e.ID())
+				}
 				confirmedEventsMeter.Mark(1)
 			},
 			EndBlock: func() (newValidators *pos.Validators) {
@@ -225,8 +253,13 @@ func consensusCallbackBeginBlockFn(
 					// save the latest block state even if block is skipped
 					store.SetBlockEpochState(bs, es)
 					log.Debug("Frame is skipped", "atropos", cBlock.Atropos.String())
+					// the confirmed events have nothing to execute
+					timings.Executed(orderedEvents, time.Now())
 					return nil
 				}
+				if err := store.CheckHistoryAnchor(blockCtx.Idx, cBlock.Atropos); err != nil {
+					log.Crit("Chain mismatches the history anchor", "err", err)
+				}
 
 				sealer := blockProc.SealerModule.Start(blockCtx, bs, es)
 				sealing := sealer.EpochSealing()
@@ -251,12 +284,22 @@ func consensusCallbackBeginBlockFn(
 					})
 				}
 
-				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, onNewLogAll, es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()))
+				// the traces are persisted along with the receipts, as the trace API locates the txs by the index
+				var txTracer *blockTxTracer
+				var evmTxTracer evmcore.TxTracer
+				if txIndex && txTraces {
+					txTracer = newBlockTxTracer()
+					evmTxTracer = txTracer
+				}
+				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, onNewLogAll, es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()), evmTxTracer)
 				executionStart := time.Now()
+				phases := blocktiming.Timings{Block: blockCtx.Idx}
 
 				// Execute pre-internal transactions
 				preInternalTxs := blockProc.PreTxTransactor.PopInternalTxs(blockCtx, bs, es, sealing, statedb)
+				phaseStart := time.Now()
 				preInternalReceipts := evmProcessor.Execute(preInternalTxs)
+				phases.Execution += time.Since(phaseStart)
 				bs = txListener.Finalize()
 				for _, r := range preInternalReceipts {
 					if r.Status == 0 {
@@ -284,7 +327,9 @@ func consensusCallbackBeginBlockFn(
 				blockFn := func() {
 					// Execute post-internal transactions
 					internalTxs := blockProc.PostTxTransactor.PopInternalTxs(blockCtx, bs, es, sealing, statedb)
+					phaseStart := time.Now()
 					internalReceipts := evmProcessor.Execute(internalTxs)
+					phases.Execution += time.Since(phaseStart)
 					for _, r := range internalReceipts {
 						if r.Status == 0 {
 							log.Warn("Internal transaction reverted", "txid", r.TxHash.String())
@@ -310,9 +355,14 @@ func consensusCallbackBeginBlockFn(
 						txs = append(txs, e.Txs()...)
 					}
 
+					phaseStart = time.Now()
 					_ = evmProcessor.Execute(txs)
+					phases.Execution += time.Since(phaseStart)
 
+					phaseStart = time.Now()
 					evmBlock, skippedTxs, allReceipts := evmProcessor.Finalize()
+					phases.Commit = time.Since(phaseStart)
+					phases.Txs = len(evmBlock.Transactions)
 					block.SkippedTxs = skippedTxs
 					block.Root = hash.Hash(evmBlock.Root)
 					block.GasUsed = evmBlock.GasUsed
@@ -344,33 +394,61 @@ func consensusCallbackBeginBlockFn(
 					}
 
 					// call OnNewReceipt
+					blockFees := iblockproc.NewFeeStats()
 					for i, r := range allReceipts {
 						creator := txPositions[r.TxHash].EventCreator
 						if creator != 0 && es.Validators.Get(creator) == 0 {
 							creator = 0
 						}
 						txListener.OnNewReceipt(evmBlock.Transactions[i], r, creator)
+
+						fee, baseFee := txFees(evmBlock.Transactions[i], r, evmBlock.BaseFee)
+						blockFees.Fees.Add(blockFees.Fees, fee)
+						blockFees.BaseFees.Add(blockFees.BaseFees, baseFee)
+						if creator != 0 {
+							blockFees.Originated.Add(blockFees.Originated, fee)
+						}
 					}
+					store.SetBlockFees(es.Epoch, blockCtx.Idx, blockFees)
 					bs = txListener.Finalize() // TODO: refactor to not mutate the bs
 					bs.FinalizedStateRoot = block.Root
 					// At this point, block state is finalized
 
 					// Build index for not skipped txs
+					var removedLogs []*types.Log
 					if txIndex {
+						phaseStart = time.Now()
 						for _, tx := range evmBlock.Transactions {
 							// not skipped txs only
 							store.evm.SetTxPosition(tx.Hash(), txPositions[tx.Hash()].TxPosition)
 						}
+						indexTxSenders(store, gsignercache.Wrap(types.LatestSignerForChainID(es.Rules.ChainID())), blockCtx.Idx, evmBlock.Transactions)
 
 						// Index receipts
 						// Note: it's possible for receipts to get indexed twice by BR and block processing
 						if allReceipts.Len() != 0 {
+							receiptsStorage := make([]*types.ReceiptForStorage, allReceipts.Len())
+							for i, r := range allReceipts {
+								receiptsStorage[i] = (*types.ReceiptForStorage)(r)
+							}
+							removedLogs = store.evm.ReplacedLogs(blockCtx.Idx, receiptsStorage, common.Hash(block.Atropos), evmBlock.Transactions)
 							store.evm.SetReceipts(blockCtx.Idx, allReceipts)
+							phases.Receipts = time.Since(phaseStart)
+							phaseStart = time.Now()
 							for _, r := range allReceipts {
 								store.evm.IndexLogs(r.Logs...)
 							}
+							phases.LogIndexing = time.Since(phaseStart)
+						} else {
+							phases.Receipts = time.Since(phaseStart)
+						}
+						store.evm.SetBlockBloom(blockCtx.Idx, allReceipts)
+						store.evm.SetBlockFeeHistory(blockCtx.Idx, evmcore.NewBlockFeeHistory(evmBlock.BaseFee, evmBlock.GasUsed, es.Rules.Blocks.MaxBlockGas, evmBlock.Transactions, allReceipts))
+						if txTracer != nil {
+							txTracer.persist(store, blockCtx.Idx, txTracesRetention)
 						}
 					}
+					phaseStart = time.Now()
 					for _, tx := range append(preInternalTxs, internalTxs...) {
 						store.evm.SetTx(tx.Hash(), tx)
 					}
@@ -380,16 +458,23 @@ func consensusCallbackBeginBlockFn(
 					if sealing {
 						store.SetHistoryBlockEpochState(es.Epoch, bs, es)
 						store.SetEpochBlock(blockCtx.Idx+1, es.Epoch)
+						if expiry := store.cfg.EVM.StateExpiryEpochs; expiry != 0 {
+							store.evm.QueueExpireState(es.Epoch, expiry)
+						}
 					}
 					store.SetBlock(blockCtx.Idx, block)
 					store.SetBlockIndex(block.Atropos, blockCtx.Idx)
 					store.SetBlockEpochState(bs, es)
+					store.EvmStore().SetTxRoot(blockCtx.Idx, evmBlock.TxHash)
 					store.EvmStore().SetCachedEvmBlock(blockCtx.Idx, evmBlock)
 					updateLowestBlockToFill(blockCtx.Idx, store)
 					updateLowestEpochToFill(es.Epoch, store)
+					phases.Write = time.Since(phaseStart)
 
 					// Update the metrics touched during block processing
 					blockExecutionTimer.Update(time.Since(executionStart))
+					phases.Total = time.Since(start)
+					blockTimings.Add(phases)
 
 					// Update the metrics touched by new block
 					headBlockGauge.Update(int64(blockCtx.Idx))
@@ -398,6 +483,9 @@ func consensusCallbackBeginBlockFn(
 
 					// Notify about new block
 					if feed != nil {
+						if len(removedLogs) != 0 {
+							feed.removedLogs.Send(evmcore.RemovedLogsNotify{Logs: removedLogs})
+						}
 						feed.newBlock.Send(evmcore.ChainHeadNotify{Block: evmBlock})
 						var logs []*types.Log
 						for _, r := range allReceipts {
@@ -414,9 +502,12 @@ func consensusCallbackBeginBlockFn(
 						evmBlock.GasUsed, "txs", fmt.Sprintf("%d/%d", len(evmBlock.Transactions), len(block.SkippedTxs)),
 						"age", utils.PrettyDuration(blockAge), "t", utils.PrettyDuration(now.Sub(start)))
 					blockAgeGauge.Update(int64(blockAge.Nanoseconds()))
+					atomic.StoreInt64(executionLag, int64(blockAge))
 
 					processedTxsMeter.Mark(int64(len(evmBlock.Transactions)))
 					skippedTxsMeter.Mark(int64(len(block.SkippedTxs)))
+
+					timings.Executed(orderedEvents, now)
 				}
 				if confirmedEvents.Len() != 0 {
 					atomic.StoreUint32(blockBusyFlag, 1)
@@ -10,12 +10,15 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Fantom-foundation/go-opera/eventcheck"
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/inter/ibr"
 	"github.com/Fantom-foundation/go-opera/inter/ier"
 	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
 )
 
 var errValidatorNotExist = errors.New("validator does not exist")
@@ -105,25 +108,42 @@ func indexRawReceipts(s *Store, receiptsForStorage []*types.ReceiptForStorage, t
 	for _, r := range receipts {
 		s.evm.IndexLogs(r.Logs...)
 	}
+	s.evm.SetBlockBloom(blockIdx, receipts)
 }
 
+// indexTxSenders indexes the positions of the block txs by their senders.
+func indexTxSenders(s *Store, signer types.Signer, blockIdx idx.Block, txs types.Transactions) {
+	for i, tx := range txs {
+		sender, err := internaltx.Sender(signer, tx)
+		if err != nil {
+			s.Log.Warn("Failed to index tx sender", "tx", tx.Hash(), "err", err)
+			continue
+		}
+		s.evm.SetTxSender(sender, blockIdx, uint32(i))
+	}
+}
+
+// WriteFullBlockRecord writes the block of the record. The txs, receipts and logs are written
+// only if the block isn't before the history start.
 func (s *Store) WriteFullBlockRecord(br ibr.LlrIdxFullBlockRecord) {
 	txHashes := make([]common.Hash, 0, len(br.Txs))
 	for _, tx := range br.Txs {
 		txHashes = append(txHashes, tx.Hash())
-		s.EvmStore().SetTx(tx.Hash(), tx)
 	}
 
-	if len(br.Receipts) != 0 {
-		// Note: it's possible for receipts to get indexed twice by BR and block processing
-		indexRawReceipts(s, br.Receipts, br.Txs, br.Idx, br.Atropos)
-	}
-	for i, tx := range br.Txs {
-		s.EvmStore().SetTx(tx.Hash(), tx)
-		s.EvmStore().SetTxPosition(tx.Hash(), evmstore.TxPosition{
-			Block:       br.Idx,
-			BlockOffset: uint32(i),
-		})
+	if s.hasHistory(br.Idx) {
+		if len(br.Receipts) != 0 {
+			// Note: it's possible for receipts to get indexed twice by BR and block processing
+			indexRawReceipts(s, br.Receipts, br.Txs, br.Idx, br.Atropos)
+		}
+		for i, tx := range br.Txs {
+			s.EvmStore().SetTx(tx.Hash(), tx)
+			s.EvmStore().SetTxPosition(tx.Hash(), evmstore.TxPosition{
+				Block:       br.Idx,
+				BlockOffset: uint32(i),
+			})
+		}
+		indexTxSenders(s, gsignercache.Wrap(types.LatestSignerForChainID(s.GetRules().ChainID())), br.Idx, br.Txs)
 	}
 	s.SetBlock(br.Idx, &inter.Block{
 		Time:        br.Time,
@@ -136,6 +156,7 @@ func (s *Store) WriteFullBlockRecord(br ibr.LlrIdxFullBlockRecord) {
 		Root:        br.Root,
 	})
 	s.SetBlockIndex(br.Atropos, br.Idx)
+	s.EvmStore().SetTxRoot(br.Idx, evmcore.NewEvmBlock(&evmcore.EvmHeader{}, br.Txs).TxHash)
 }
 
 func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
@@ -153,8 +174,18 @@ func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
 	if br.Hash() != *res {
 		return errors.New("block record hash mismatch")
 	}
+	if err := s.store.CheckHistoryAnchor(br.Idx, br.Atropos); err != nil {
+		return err
+	}
 
+	var removedLogs []*types.Log
+	if len(br.Receipts) != 0 && s.store.hasHistory(br.Idx) {
+		removedLogs = s.store.evm.ReplacedLogs(br.Idx, br.Receipts, common.Hash(br.Atropos), br.Txs)
+	}
 	s.store.WriteFullBlockRecord(br)
+	if len(removedLogs) != 0 {
+		s.feed.removedLogs.Send(evmcore.RemovedLogsNotify{Logs: removedLogs})
+	}
 	s.engineMu.Lock()
 	defer s.engineMu.Unlock()
 	if s.verWatcher != nil {
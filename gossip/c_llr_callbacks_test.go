@@ -396,9 +396,11 @@ func (r repeater) compareParams() {
 		require.NoError(r.t, err)
 
 		// compare Receipts
-		genReceipts := r.generator.store.evm.GetReceipts(blockIdx, r.generator.EthAPI.signer, genEvmBlock.Hash, genEvmBlock.Transactions)
+		genReceipts, err := r.generator.store.evm.GetReceipts(blockIdx, r.generator.EthAPI.signer, genEvmBlock.Hash, genEvmBlock.Transactions)
+		require.NoError(r.t, err)
 		require.NotNil(r.t, genReceipts)
-		procReceipts := r.processor.store.evm.GetReceipts(blockIdx, r.processor.EthAPI.signer, procEvmBlock.Hash, procEvmBlock.Transactions)
+		procReceipts, err := r.processor.store.evm.GetReceipts(blockIdx, r.processor.EthAPI.signer, procEvmBlock.Hash, procEvmBlock.Transactions)
+		require.NoError(r.t, err)
 		require.NotNil(r.t, procReceipts)
 
 		testParams := newTestParams(r.t, genEvmBlock, procEvmBlock, genReceipts, procReceipts)
@@ -454,7 +456,8 @@ func (r repeater) compareLogsByFilterCriteria() {
 			block, err := r.generator.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(blockIdx))
 			require.NotNil(r.t, block)
 			require.NoError(r.t, err)
-			receipts := r.generator.store.evm.GetReceipts(blockIdx, r.generator.EthAPI.signer, block.Hash, block.Transactions)
+			receipts, err := r.generator.store.evm.GetReceipts(blockIdx, r.generator.EthAPI.signer, block.Hash, block.Transactions)
+			require.NoError(r.t, err)
 			for _, r := range receipts {
 				// we add only non empty logs
 				if len(r.Logs) > 0 {
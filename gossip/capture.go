@@ -0,0 +1,153 @@
+package gossip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-opera/gossip/p2pcapture"
+)
+
+// maxReplayGap caps the pause between the replayed messages, so the idle periods of a capture are skipped.
+const maxReplayGap = time.Second
+
+// txMsgCodes are the transactions propagation messages, which aren't captured unless enabled explicitly.
+var txMsgCodes = []uint64{EvmTxsMsg, NewEvmTxHashesMsg, GetEvmTxsMsg}
+
+func openCapture(cfg p2pcapture.Config) (*p2pcapture.Writer, error) {
+	if cfg.Txs {
+		return p2pcapture.Create(cfg.Path, cfg.MaxSize)
+	}
+	return p2pcapture.Create(cfg.Path, cfg.MaxSize, txMsgCodes...)
+}
+
+// captureMsg writes the inbound message into the capture. The payload is buffered, so the message
+// can be handled as usual afterwards.
+func (h *handler) captureMsg(p *peer, msg *p2p.Msg) error {
+	payload, err := io.ReadAll(msg.Payload)
+	if err != nil {
+		return err
+	}
+	msg.Payload = bytes.NewReader(payload)
+	if _, err := h.capture.Write(p.id, p.version, msg.Code, payload, msg.ReceivedAt); err != nil {
+		h.Log.Warn("Failed to capture p2p message", "err", err)
+	}
+	return nil
+}
+
+// replayMsgReadWriter feeds the replayed messages of a peer to the handler and discards the answers.
+type replayMsgReadWriter struct {
+	msg p2p.Msg // the next message, set before handling it
+}
+
+func (rw *replayMsgReadWriter) ReadMsg() (p2p.Msg, error) {
+	return rw.msg, nil
+}
+
+func (rw *replayMsgReadWriter) WriteMsg(msg p2p.Msg) error {
+	return msg.Discard()
+}
+
+// ReplayStats summarizes a replayed capture.
+type ReplayStats struct {
+	Messages int
+	Failed   int
+	Peers    int
+}
+
+// replayCapture feeds the captured messages into the handler, as if they were received from the captured peers.
+// Every captured peer is simulated by a fake peer, the answers to it are discarded.
+// The original pacing of the messages is kept, except for the pauses longer than maxReplayGap.
+func (h *handler) replayCapture(path string) (ReplayStats, error) {
+	var stats ReplayStats
+	f, err := os.Open(path)
+	if err != nil {
+		return stats, err
+	}
+	defer f.Close()
+	r, err := p2pcapture.NewReader(f)
+	if err != nil {
+		return stats, err
+	}
+
+	type replayPeer struct {
+		*peer
+		rw *replayMsgReadWriter
+	}
+	peers := make(map[uint64]replayPeer)
+	defer func() {
+		for _, p := range peers {
+			p.Close()
+		}
+	}()
+	var prev time.Time
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return stats, nil
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		at := rec.ReceivedAt()
+		if !prev.IsZero() {
+			gap := at.Sub(prev)
+			if gap > maxReplayGap {
+				gap = maxReplayGap
+			}
+			if gap > 0 {
+				select {
+				case <-time.After(gap):
+				case <-h.quitSync:
+					return stats, nil
+				}
+			}
+		}
+		prev = at
+
+		p, ok := peers[rec.Peer]
+		if !ok {
+			var id enode.ID
+			binary.BigEndian.PutUint64(id[:], rec.Peer)
+			name := fmt.Sprintf("replay-%016x", rec.Peer)
+			rw := &replayMsgReadWriter{}
+			p = replayPeer{newPeer(rec.Version, p2p.NewPeer(id, name, nil), rw, h.config.Protocol.PeerCache), rw}
+			peers[rec.Peer] = p
+			stats.Peers++
+		}
+		p.rw.msg = p2p.Msg{
+			Code:       rec.Code,
+			Size:       uint32(len(rec.Payload)),
+			Payload:    bytes.NewReader(rec.Payload),
+			ReceivedAt: time.Now(),
+		}
+		stats.Messages++
+		if err := h.handleMsg(p.peer); err != nil {
+			stats.Failed++
+			h.Log.Debug("Replayed p2p message failed", "peer", p.id, "code", rec.Code, "err", err)
+		}
+	}
+}
+
+// startReplay replays the capture in background once the handler is started.
+func (h *handler) startReplay(path string) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.Log.Info("Replaying p2p capture", "path", path)
+		stats, err := h.replayCapture(path)
+		if err != nil {
+			h.Log.Error("Failed to replay p2p capture", "path", path, "err", err)
+			return
+		}
+		h.Log.Info("P2P capture is replayed", "messages", stats.Messages, "failed", stats.Failed, "peers", stats.Peers)
+	}()
+}
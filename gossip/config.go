@@ -11,12 +11,15 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/dag"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 
 	"github.com/Fantom-foundation/go-opera/eventcheck/heavycheck"
+	"github.com/Fantom-foundation/go-opera/gossip/dialsched"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/gossip/filters"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/gossip/p2pcapture"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamseeder"
@@ -28,10 +31,19 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamseeder"
+	"github.com/Fantom-foundation/go-opera/gossip/telemetry"
+	"github.com/Fantom-foundation/go-opera/gossip/webhook"
 )
 
 const nominalSize uint = 1
 
+// Estimated memory sizes of the cached entries, which aren't weighted by their actual size.
+const (
+	eventHeaderMemSize     = 512
+	blockHashMemSize       = 96
+	blockEpochStateMemSize = 64 * 1024
+)
+
 type (
 	// ProtocolConfig is config for p2p protocol
 	ProtocolConfig struct {
@@ -67,9 +79,41 @@ type (
 		MaxRandomTxHashesSend    int
 		RandomTxHashesSendPeriod time.Duration
 
+		// TxPriority is the prioritization of the transactions propagation under a constrained bandwidth
+		TxPriority TxPriorityConfig
+
+		// PeerPolicy is the dropping of the stale peers and the re-dialing policy
+		PeerPolicy PeerPolicyConfig
+
 		PeerCache PeerCacheConfig
 	}
 
+	// TxPriorityConfig is the prioritization of the propagated transactions by the likeliness of their inclusion.
+	TxPriorityConfig struct {
+		// Enabled orders the propagated transactions by the effective tip, keeping the nonce order of a sender
+		Enabled bool
+		// CongestedQueue is the fill level of a peer's broadcast queue, in percents, starting from which
+		// the peer is considered congested
+		CongestedQueue int
+		// CongestedFullShare is the share of the top priority transactions, in percents, which are sent in full
+		// to a congested peer. Only the hashes of the rest are announced
+		CongestedFullShare int
+	}
+
+	// PeerPolicyConfig is the policy of dropping the stale peers and re-dialing.
+	PeerPolicyConfig struct {
+		// IdleTimeout is the period without useful messages (new events, transactions or progress ahead of ours),
+		// after which a peer is dropped, 0 disables dropping. Trusted and static peers are never dropped
+		IdleTimeout time.Duration
+		// MinUsefulPeers is the number of the useful peers, below which the re-dial backoff is bypassed.
+		// Also the idle peers aren't dropped if there are not more peers than it
+		MinUsefulPeers int
+		// CheckPeriod is the period of checking the peers
+		CheckPeriod time.Duration
+		// Redial is the backoff of re-dialing the disconnected peers
+		Redial dialsched.BackoffConfig
+	}
+
 	// Config for the gossip service.
 	Config struct {
 		FilterAPI filters.Config
@@ -77,6 +121,11 @@ type (
 		// This can be set to list of enrtree:// URLs which will be queried
 		// for nodes to connect to.
 		OperaDiscoveryURLs []string
+		// Dial is the latency aware ordering of the candidates discovered by OperaDiscoveryURLs
+		Dial dialsched.Config
+
+		// P2PCapture is the capturing of the inbound protocol messages, and the replaying of a capture
+		P2PCapture p2pcapture.Config
 
 		TxIndex bool // Whether to enable indexing transactions and receipts or not
 
@@ -94,6 +143,15 @@ type (
 		// RPCEVMTimeout is the global timeout for eth-call.
 		RPCEVMTimeout time.Duration
 
+		// RPCEVMMemoryLimit is the global limit of the EVM memory of all the call frames of eth-call and tracing, in bytes.
+		RPCEVMMemoryLimit uint64
+
+		// RPCReturnDataLimit is the global limit of a return data size of eth-call and tracing, in bytes.
+		RPCReturnDataLimit uint64
+
+		// RPCTraceWorkers is the number of goroutines re-executing transactions for the tracing RPC methods (0 = number of CPUs).
+		RPCTraceWorkers int
+
 		// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
 		// send-transction variants. The unit is ether.
 		RPCTxFeeCap float64 `toml:",omitempty"`
@@ -101,6 +159,15 @@ type (
 		// RPCTimeout is a global time limit for RPC methods execution.
 		RPCTimeout time.Duration
 
+		// RPCAccountsLimit is maximum number of addresses queried by eth_getAccounts.
+		RPCAccountsLimit int
+
+		// RPCBalanceHistoryLimit is maximum number of blocks queried by eth_getBalanceHistory.
+		RPCBalanceHistoryLimit int
+
+		// RPCProofsLimit is maximum number of proofs (accounts and storage slots) queried by eth_getProofs.
+		RPCProofsLimit int
+
 		// allows only for EIP155 transactions.
 		AllowUnprotectedTxs bool
 
@@ -113,7 +180,70 @@ type (
 		// MaxResponseSize is a limit for maximum response size in some RPC calls
 		MaxResponseSize int
 
+		// RPCSlowCallThreshold is the execution time of the EVM executions, traces and logs queries over RPC,
+		// the slower calls are logged as warnings with their trace IDs. Zero disables the slow calls records.
+		RPCSlowCallThreshold time.Duration
+
 		RPCBlockExt bool
+
+		// RPCHeadLag is a number of blocks the head served over RPC lags behind the actual head.
+		// The blocks and logs subscriptions lag as well.
+		// Intended only for staging environments to test finality handling of clients.
+		RPCHeadLag idx.Block
+
+		// RPCMaxStaleBlocks is a maximum number of blocks the state served by the eth_*Stale methods
+		// may be behind the requested block, so a slightly lagging replica can serve the request.
+		RPCMaxStaleBlocks idx.Block
+
+		// AllowRollback enables debug_setHead for rolling back the processed head
+		// to an epoch checkpoint (for disaster recovery).
+		AllowRollback bool
+
+		// TxTracesPersist enables tracing of transactions at import time and persisting the traces,
+		// so the trace API doesn't re-execute transactions of recent blocks.
+		TxTracesPersist bool
+		// TxTracesRetention is a number of recent blocks whose persisted traces are kept.
+		TxTracesRetention idx.Block
+
+		// RPCDenyList is a path to the file of addresses, transactions from or to which are rejected
+		// at RPC submission. It's never applied to transactions from peers or to block validation.
+		RPCDenyList string `toml:",omitempty"`
+		// RPCDenyListAudit is a path to the audit log of the rejected submissions.
+		RPCDenyListAudit string `toml:",omitempty"`
+
+		// RewardRecipientAudit is a path to the audit log of the validator reward recipient changes.
+		RewardRecipientAudit string `toml:",omitempty"`
+
+		// Webhooks are the HTTP notifications of the chain conditions
+		Webhooks webhook.Config
+
+		// Telemetry is the opt-in reporting of the anonymized performance data
+		Telemetry telemetry.Config
+
+		// MemoryBudget is a limit in bytes for the total memory of the caches, txpool and DAG buffers.
+		// The caches are shrunk by their priorities once it's exceeded. Zero disables the limit.
+		MemoryBudget uint64 `toml:",omitempty"`
+
+		// ScanApiTables enables a background verification of the API-only tables on start.
+		// The corrupted data is quarantined and repaired, while the rest of the data is served.
+		// It's enabled automatically after an unclean shutdown.
+		ScanApiTables bool `toml:",omitempty"`
+
+		// TxRebroadcast is the periodic rebroadcasting of the local transactions, which are stuck
+		TxRebroadcast TxRebroadcastConfig
+	}
+
+	// TxRebroadcastConfig is a config of the rebroadcasting of the local transactions until their inclusion.
+	TxRebroadcastConfig struct {
+		// Interval between the rebroadcasts of a not included transaction, 0 disables the rebroadcasting
+		Interval time.Duration `toml:",omitempty"`
+		// Expiry is the time since the submission, after which a transaction isn't rebroadcast anymore
+		Expiry time.Duration
+		// BumpAfter is the number of rebroadcasts, after which the next pre-signed replacement
+		// of the transaction with a higher fee is submitted. Zero disables the fee bumps.
+		BumpAfter int
+		// MaxTxs is a limit of the tracked transactions, the new ones aren't tracked once it's reached
+		MaxTxs int
 	}
 
 	StoreCacheConfig struct {
@@ -132,6 +262,15 @@ type (
 		LlrEpochVotesIndexes int
 	}
 
+	// HistoryConfig limits the chain data, which is imported and served over RPC, to the blocks
+	// starting from a checkpoint. It's intended for the RPC nodes not interested in the ancient history.
+	HistoryConfig struct {
+		// StartBlock is the first block, the txs, receipts and logs of which are imported (0 = full history)
+		StartBlock idx.Block
+		// Anchor is the hash of StartBlock, the chain is verified against once the block is known
+		Anchor common.Hash
+	}
+
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
@@ -139,6 +278,11 @@ type (
 		EVM               evmstore.StoreConfig
 		MaxNonFlushedSize int
 		MaxNonFlushedPeriod time.Duration
+		// History limits the imported chain data to the blocks after a checkpoint
+		History HistoryConfig
+		// BulkCompactionMaxWait is the maximum delay of the EVM store compactions (receipts, logs index and other API tables)
+		// while the DBs are being flushed. The writes aren't delayed. Zero disables the delay.
+		BulkCompactionMaxWait time.Duration
 	}
 )
 
@@ -150,6 +294,9 @@ type PeerCacheConfig struct {
 	// contain a single transaction, or thousands.
 	MaxQueuedItems idx.Event
 	MaxQueuedSize  uint64
+	// BatchEvents coalesces the queued broadcasts of events into a single message,
+	// if the peer supports it (FTM64+)
+	BatchEvents bool
 }
 
 // DefaultConfig returns the default configurations for the gossip service.
@@ -157,6 +304,8 @@ func DefaultConfig(scale cachescale.Func) Config {
 	cfg := Config{
 		FilterAPI: filters.DefaultConfig(),
 
+		Dial: dialsched.DefaultConfig(),
+
 		TxIndex: true,
 
 		HeavyCheck: heavycheck.DefaultConfig(),
@@ -213,9 +362,22 @@ func DefaultConfig(scale cachescale.Func) Config {
 			MaxRandomTxHashesSend:    250, // match softLimitItems to fit into one message
 			RandomTxHashesSendPeriod: 1 * time.Second,
 			PeerCache:                DefaultPeerCacheConfig(scale),
+			TxPriority: TxPriorityConfig{
+				Enabled:            false,
+				CongestedQueue:     50,
+				CongestedFullShare: 25,
+			},
+			PeerPolicy: PeerPolicyConfig{
+				IdleTimeout:    15 * time.Minute,
+				MinUsefulPeers: 5,
+				CheckPeriod:    time.Minute,
+				Redial:         dialsched.DefaultBackoffConfig(),
+			},
 		},
 
-		RPCEVMTimeout: 5 * time.Second,
+		RPCEVMTimeout:      5 * time.Second,
+		RPCEVMMemoryLimit:  64 * opt.MiB,
+		RPCReturnDataLimit: 4 * opt.MiB,
 
 		GPO: gasprice.Config{
 			MaxGasPrice:      gasprice.DefaultMaxGasPrice,
@@ -229,11 +391,30 @@ func DefaultConfig(scale cachescale.Func) Config {
 		RPCTxFeeCap: 100, // 100 FTM
 		RPCTimeout:  5 * time.Second,
 
+		RPCAccountsLimit:       1000,
+		RPCBalanceHistoryLimit: 10000,
+		RPCProofsLimit:         1000,
+		RPCMaxStaleBlocks:      10,
+
 		BatchRequestLimit: 1000,
 
 		JSTracerLimit: 1000,
 
 		MaxResponseSize: 25 * 1024 * 1024,
+
+		RPCSlowCallThreshold: 10 * time.Second,
+
+		TxTracesRetention: 100000,
+
+		Webhooks: webhook.DefaultConfig(),
+
+		Telemetry: telemetry.DefaultConfig(),
+
+		TxRebroadcast: TxRebroadcastConfig{
+			Expiry:    3 * time.Hour,
+			BumpAfter: 3,
+			MaxTxs:    4096,
+		},
 	}
 	sessionCfg := cfg.Protocol.DagStreamLeecher.Session
 	cfg.Protocol.DagProcessor.EventsBufferLimit.Num = idx.Event(sessionCfg.ParallelChunksDownload)*
@@ -273,6 +454,9 @@ func (c *Config) Validate() error {
 	if p.DagProcessor.EventsBufferLimit.Size < protocolMaxMsgSize {
 		return fmt.Errorf("EventsBufferLimit.Size has to be at least %d", protocolMaxMsgSize)
 	}
+	if c.TxRebroadcast.Interval < 0 {
+		return fmt.Errorf("TxRebroadcast.Interval has to be non-negative, got %s", c.TxRebroadcast.Interval)
+	}
 
 	return nil
 }
@@ -290,9 +474,10 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			LlrBlockVotesIndexes: scale.I(100),
 			LlrEpochVotesIndexes: scale.I(5),
 		},
-		EVM:                 evmstore.DefaultStoreConfig(scale),
-		MaxNonFlushedSize:   21*opt.MiB + scale.I(2*opt.MiB),
-		MaxNonFlushedPeriod: 30 * time.Minute,
+		EVM:                   evmstore.DefaultStoreConfig(scale),
+		MaxNonFlushedSize:     21*opt.MiB + scale.I(2*opt.MiB),
+		MaxNonFlushedPeriod:   30 * time.Minute,
+		BulkCompactionMaxWait: 20 * time.Millisecond,
 	}
 }
 
@@ -311,5 +496,6 @@ func DefaultPeerCacheConfig(scale cachescale.Func) PeerCacheConfig {
 		MaxKnownEvents: 24576*3/4 + scale.I(24576/4),
 		MaxQueuedItems: 4096*3/4 + scale.Events(4096/4),
 		MaxQueuedSize:  protocolMaxMsgSize*3/4 + 1024 + scale.U64(protocolMaxMsgSize/4),
+		BatchEvents:    true,
 	}
 }
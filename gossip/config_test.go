@@ -0,0 +1,20 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidateTxRebroadcast(t *testing.T) {
+	cfg := DefaultConfig(cachescale.Identity)
+	require.NoError(t, cfg.Validate())
+
+	cfg.TxRebroadcast.Interval = time.Minute
+	require.NoError(t, cfg.Validate())
+
+	cfg.TxRebroadcast.Interval = -time.Minute
+	require.Error(t, cfg.Validate())
+}
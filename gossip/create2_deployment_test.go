@@ -0,0 +1,79 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/filters"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestCreate2DeploymentSubscription(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 3, t)
+	defer env.Close()
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	require.NoError(srv.RegisterName("eth", filters.NewPublicFilterAPI(env.EthAPI, env.config.FilterAPI)))
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	// the init code of the child returns the single STOP opcode as the code
+	childInitCode := common.FromHex("0x600060005360016000f3")
+	// the factory deploys the child by CREATE2 with zero salt on every call
+	receipts, err := env.ApplyTxs(sameEpoch, env.Contract(1, new(big.Int), "0x6018600c60003960186000f369600060005360016000f36000526000600a60166000f500"))
+	require.NoError(err)
+	require.Equal(types.ReceiptStatusSuccessful, receipts[0].Status)
+	factory := receipts[0].ContractAddress
+
+	var (
+		salt         = common.Hash{}
+		initCodeHash = crypto.Keccak256Hash(childInitCode)
+		child        = crypto.CreateAddress2(factory, salt, initCodeHash.Bytes())
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	deployments := make(chan *filters.Create2Deployment, 1)
+	sub, err := client.EthSubscribe(ctx, deployments, "create2Deployment", factory, salt, initCodeHash)
+	require.NoError(err)
+	defer sub.Unsubscribe()
+
+	// no notification until the child is deployed
+	select {
+	case d := <-deployments:
+		require.FailNow("notified before the deployment", d.Address)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sender := env.Address(1)
+	nonce, _ := env.PendingNonceAt(nil, sender)
+	env.incNonce(sender)
+	tx, err := types.SignTx(types.NewTransaction(nonce, factory, new(big.Int), maxGasLimit, big.NewInt(1e12), nil), env.EthAPI.signer, env.privateKey(1))
+	require.NoError(err)
+	receipts, err = env.ApplyTxs(sameEpoch, tx)
+	require.NoError(err)
+	require.Equal(types.ReceiptStatusSuccessful, receipts[0].Status)
+
+	select {
+	case d := <-deployments:
+		require.Equal(child, d.Address)
+		require.Equal(receipts[0].BlockNumber.Uint64(), uint64(d.BlockNumber))
+		require.Equal(crypto.Keccak256Hash([]byte{0x00}), d.CodeHash)
+	case err := <-sub.Err():
+		require.FailNow("subscription failed", err)
+	case <-ctx.Done():
+		require.FailNow("deployment isn't notified")
+	}
+}
@@ -0,0 +1,139 @@
+package gossip
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// denyListReloadPeriod is a period of checking the deny list file for changes.
+const denyListReloadPeriod = 5 * time.Second
+
+var errDeniedAddress = errors.New("transaction rejected by the node policy")
+
+// denyList rejects transactions submitted over RPC from or to the listed addresses.
+// It's a node-local policy, it's never applied to transactions received from peers or to block validation.
+// The list file contains one address per line, empty lines and lines starting with '#' are ignored.
+// The file is reloaded once it's modified.
+type denyList struct {
+	path      string
+	auditPath string
+
+	mu       sync.Mutex
+	addrs    map[common.Address]bool
+	modTime  time.Time
+	lastStat time.Time
+}
+
+func newDenyList(path, auditPath string) (*denyList, error) {
+	l := &denyList{
+		path:      path,
+		auditPath: auditPath,
+	}
+	if err := l.reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *denyList) reload() error {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return err
+	}
+	l.lastStat = time.Now()
+	if info.ModTime().Equal(l.modTime) && l.addrs != nil {
+		return nil
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	addrs := make(map[common.Address]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if len(s) == 0 || strings.HasPrefix(s, "#") {
+			continue
+		}
+		if !common.IsHexAddress(s) {
+			return fmt.Errorf("invalid address %q at %s:%d", s, l.path, line)
+		}
+		addrs[common.HexToAddress(s)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	l.addrs = addrs
+	l.modTime = info.ModTime()
+	log.Info("Loaded RPC deny list", "path", l.path, "addresses", len(addrs))
+	return nil
+}
+
+// check returns an error if the transaction is sent from or to a denied address.
+func (l *denyList) check(tx *types.Transaction, signer types.Signer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Since(l.lastStat) >= denyListReloadPeriod {
+		if err := l.reload(); err != nil {
+			// keep the previous list
+			log.Warn("Failed to reload RPC deny list", "path", l.path, "err", err)
+		}
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	denied := from
+	if !l.addrs[from] {
+		if tx.To() == nil || !l.addrs[*tx.To()] {
+			return nil
+		}
+		denied = *tx.To()
+	}
+	l.audit(tx, from, denied)
+	return errDeniedAddress
+}
+
+type denyListAuditRecord struct {
+	Time    time.Time       `json:"time"`
+	TxHash  common.Hash     `json:"txHash"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Address common.Address  `json:"address"`
+}
+
+// audit records the rejected submission into the audit log.
+func (l *denyList) audit(tx *types.Transaction, from, denied common.Address) {
+	log.Info("Rejected transaction by RPC deny list", "tx", tx.Hash(), "from", from, "address", denied)
+	if len(l.auditPath) == 0 {
+		return
+	}
+	b, _ := json.Marshal(denyListAuditRecord{
+		Time:    time.Now().UTC(),
+		TxHash:  tx.Hash(),
+		From:    from,
+		To:      tx.To(),
+		Address: denied,
+	})
+	f, err := os.OpenFile(l.auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Error("Failed to open RPC deny list audit log", "path", l.auditPath, "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		log.Error("Failed to write RPC deny list audit log", "path", l.auditPath, "err", err)
+	}
+}
@@ -0,0 +1,56 @@
+package gossip
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenyList(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "denylist.txt")
+	auditPath := filepath.Join(dir, "audit.log")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	denied := common.Address{1}
+	signer := types.HomesteadSigner{}
+	sign := func(to common.Address) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(1), nil), signer, key)
+		require.NoError(err)
+		return tx
+	}
+
+	require.NoError(os.WriteFile(listPath, []byte("# sanctioned\n"+denied.Hex()+"\n\n"), 0600))
+	l, err := newDenyList(listPath, auditPath)
+	require.NoError(err)
+
+	require.NoError(l.check(sign(common.Address{2}), signer))
+	deniedTx := sign(denied)
+	require.ErrorIs(l.check(deniedTx, signer), errDeniedAddress)
+
+	b, err := os.ReadFile(auditPath)
+	require.NoError(err)
+	record := denyListAuditRecord{}
+	require.NoError(json.Unmarshal(b, &record))
+	require.Equal(deniedTx.Hash(), record.TxHash)
+	require.Equal(from, record.From)
+	require.Equal(denied, record.Address)
+
+	// the modified list is reloaded
+	require.NoError(os.WriteFile(listPath, []byte(from.Hex()+"\n"), 0600))
+	require.NoError(os.Chtimes(listPath, time.Now(), time.Now().Add(time.Minute)))
+	l.lastStat = time.Time{}
+	require.ErrorIs(l.check(sign(common.Address{2}), signer), errDeniedAddress)
+}
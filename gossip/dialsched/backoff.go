@@ -0,0 +1,141 @@
+package dialsched
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+)
+
+// BackoffConfig is the backoff of re-dialing the disconnected peers.
+type BackoffConfig struct {
+	// Min is the delay of re-dialing a peer after its first disconnection, 0 disables the backoff
+	Min time.Duration
+	// Max caps the delay
+	Max time.Duration
+	// Factor multiplies the delay after every next disconnection
+	Factor float64
+	// Stable is the duration of a connection after which the peer's delay is reset to Min
+	Stable time.Duration
+	// CacheSize is the number of the peers whose backoff is tracked
+	CacheSize int
+}
+
+// DefaultBackoffConfig returns the default re-dial backoff config.
+func DefaultBackoffConfig() BackoffConfig {
+	return BackoffConfig{
+		Min:       30 * time.Second,
+		Max:       30 * time.Minute,
+		Factor:    2,
+		Stable:    10 * time.Minute,
+		CacheSize: 4096,
+	}
+}
+
+type backoffState struct {
+	delay       time.Duration
+	connectedAt time.Time
+	next        time.Time
+}
+
+// Backoff delays re-dialing of the disconnected peers, the delay grows with every disconnection.
+// A nil Backoff allows all the peers and ignores the peers changes.
+type Backoff struct {
+	cfg    BackoffConfig
+	bypass uint32
+
+	mu    sync.Mutex
+	peers *lru.Cache[enode.ID, *backoffState]
+}
+
+// NewBackoff creates the re-dial backoff, or returns nil if it's disabled.
+func NewBackoff(cfg BackoffConfig) *Backoff {
+	if cfg.Min <= 0 {
+		return nil
+	}
+	if cfg.Max < cfg.Min {
+		cfg.Max = cfg.Min
+	}
+	if cfg.Factor < 1 {
+		cfg.Factor = 1
+	}
+	return &Backoff{
+		cfg:   cfg,
+		peers: lru.New[enode.ID, *backoffState](uint(cfg.CacheSize), cfg.CacheSize, nil),
+	}
+}
+
+// SetBypass makes all the peers allowed regardless of their backoff, e.g. if there is lack of the useful peers.
+func (b *Backoff) SetBypass(bypass bool) {
+	if b == nil {
+		return
+	}
+	v := uint32(0)
+	if bypass {
+		v = 1
+	}
+	atomic.StoreUint32(&b.bypass, v)
+}
+
+// Allowed returns true if the peer may be dialed.
+func (b *Backoff) Allowed(id enode.ID, now time.Time) bool {
+	if b == nil || atomic.LoadUint32(&b.bypass) != 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.peers.Peek(id)
+	return !ok || !now.Before(st.next)
+}
+
+// Filter returns the iterator which skips the peers, which may not be dialed yet.
+func (b *Backoff) Filter(it enode.Iterator) enode.Iterator {
+	if b == nil {
+		return it
+	}
+	return enode.Filter(it, func(n *enode.Node) bool {
+		return b.Allowed(n.ID(), time.Now())
+	})
+}
+
+// Connected records the connected peer.
+func (b *Backoff) Connected(id enode.ID, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.peers.Get(id)
+	if !ok {
+		st = &backoffState{}
+		b.peers.Add(id, st)
+	}
+	st.connectedAt = now
+}
+
+// Disconnected records the disconnected peer and delays its re-dialing.
+func (b *Backoff) Disconnected(id enode.ID, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.peers.Get(id)
+	if !ok {
+		st = &backoffState{}
+		b.peers.Add(id, st)
+	}
+	if st.delay == 0 || (!st.connectedAt.IsZero() && now.Sub(st.connectedAt) >= b.cfg.Stable) {
+		st.delay = b.cfg.Min
+	} else {
+		st.delay = time.Duration(float64(st.delay) * b.cfg.Factor)
+		if st.delay > b.cfg.Max {
+			st.delay = b.cfg.Max
+		}
+	}
+	st.connectedAt = time.Time{}
+	st.next = now.Add(st.delay)
+}
@@ -0,0 +1,72 @@
+package dialsched
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultBackoffConfig()
+	cfg.Min = time.Second
+	cfg.Max = 3 * time.Second
+	cfg.Stable = time.Minute
+	b := NewBackoff(cfg)
+
+	id := enode.ID{1}
+	now := time.Unix(1000, 0)
+	require.True(b.Allowed(id, now))
+
+	// the delay grows with every disconnection up to the limit
+	b.Connected(id, now)
+	b.Disconnected(id, now)
+	require.False(b.Allowed(id, now))
+	require.True(b.Allowed(id, now.Add(time.Second)))
+
+	now = now.Add(time.Second)
+	b.Connected(id, now)
+	b.Disconnected(id, now)
+	require.False(b.Allowed(id, now.Add(time.Second)))
+	require.True(b.Allowed(id, now.Add(2*time.Second)))
+
+	now = now.Add(2 * time.Second)
+	b.Disconnected(id, now)
+	require.False(b.Allowed(id, now.Add(2*time.Second)))
+	require.True(b.Allowed(id, now.Add(3*time.Second)))
+
+	// bypassed if there is lack of peers
+	b.SetBypass(true)
+	require.True(b.Allowed(id, now))
+	b.SetBypass(false)
+
+	// the delay is reset after a stable connection
+	now = now.Add(3 * time.Second)
+	b.Connected(id, now)
+	now = now.Add(time.Minute)
+	b.Disconnected(id, now)
+	require.True(b.Allowed(id, now.Add(time.Second)))
+
+	// other peers aren't affected
+	require.True(b.Allowed(enode.ID{2}, now))
+}
+
+func TestBackoffDisabled(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultBackoffConfig()
+	cfg.Min = 0
+	b := NewBackoff(cfg)
+	require.Nil(b)
+
+	// nil backoff allows everything
+	b.Connected(enode.ID{1}, time.Now())
+	b.Disconnected(enode.ID{1}, time.Now())
+	b.SetBypass(true)
+	require.True(b.Allowed(enode.ID{1}, time.Now()))
+	it := enode.IterNodes(nil)
+	require.Equal(it, b.Filter(it))
+}
@@ -0,0 +1,331 @@
+// Package dialsched implements a latency aware ordering of the dial candidates.
+// Candidates are collected ahead from the discovery, their RTT is measured by a TCP connect probe,
+// and the fastest ones are dialed first. Diversity is kept by a quota of peers per subnet
+// and by a share of candidates taken in the discovery order regardless of their RTT.
+package dialsched
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+)
+
+// unreachable is the RTT of the candidates which can't be probed.
+const unreachable = time.Duration(math.MaxInt64)
+
+// Config is the dial scheduler config.
+type Config struct {
+	// Enabled turns on the latency aware ordering, otherwise the candidates are dialed in the discovery order
+	Enabled bool
+	// Lookahead is the number of the candidates collected and probed ahead, the best of them is dialed
+	Lookahead int
+	// SubnetQuota is the maximum number of the connected and dialed peers in the same subnet
+	// (/24 for IPv4, /48 for IPv6), 0 means no quota
+	SubnetQuota int
+	// ExploreEvery makes every N-th dialed candidate be taken in the discovery order regardless of the RTT,
+	// so the distant peers are still connected, 0 disables it
+	ExploreEvery int
+	// ProbeTimeout limits a single RTT probe, the candidates which didn't answer are dialed last
+	ProbeTimeout time.Duration
+	// ProbeConcurrency is the maximum number of the simultaneous probes
+	ProbeConcurrency int
+	// DialTimeout is the period during which a dialed candidate counts towards the subnet quota
+	DialTimeout time.Duration
+	// RTTCacheSize is the number of the measured RTTs kept to not probe the same node again
+	RTTCacheSize int
+}
+
+// DefaultConfig returns the default dial scheduler config.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:          false,
+		Lookahead:        32,
+		SubnetQuota:      2,
+		ExploreEvery:     4,
+		ProbeTimeout:     2 * time.Second,
+		ProbeConcurrency: 8,
+		DialTimeout:      30 * time.Second,
+		RTTCacheSize:     4096,
+	}
+}
+
+// Prober measures the RTT to the node.
+type Prober func(n *enode.Node, timeout time.Duration) (time.Duration, error)
+
+// TCPProbe measures the time of establishing a TCP connection to the node.
+func TCPProbe(n *enode.Node, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(n.IP().String(), strconv.Itoa(n.TCP())), timeout)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	_ = conn.Close()
+	return rtt, nil
+}
+
+type candidate struct {
+	node *enode.Node
+	rtt  time.Duration
+	seq  uint64
+}
+
+type dialed struct {
+	subnet string
+	at     time.Time
+}
+
+// Scheduler is an enode.Iterator, which reorders the candidates of the source iterator.
+// Connected and Disconnected must be called on the peers changes to maintain the subnet quotas.
+// A nil Scheduler ignores the peers changes.
+type Scheduler struct {
+	cfg   Config
+	src   enode.Iterator
+	probe Prober
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pool      []candidate
+	picks     uint64
+	srcDone   bool
+	closed    bool
+	connected map[enode.ID]string
+	dialing   map[enode.ID]dialed
+	rtts      *lru.Cache[enode.ID, time.Duration]
+
+	cur *enode.Node
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New starts the scheduler over the source iterator.
+func New(cfg Config, src enode.Iterator, probe Prober) *Scheduler {
+	if cfg.Lookahead < 1 {
+		cfg.Lookahead = 1
+	}
+	if cfg.ProbeConcurrency < 1 {
+		cfg.ProbeConcurrency = 1
+	}
+	s := &Scheduler{
+		cfg:       cfg,
+		src:       src,
+		probe:     probe,
+		connected: make(map[enode.ID]string),
+		dialing:   make(map[enode.ID]dialed),
+		rtts:      lru.New[enode.ID, time.Duration](uint(cfg.RTTCacheSize), cfg.RTTCacheSize, nil),
+		quit:      make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	s.wg.Add(1)
+	go s.feed()
+	return s
+}
+
+// subnet returns the subnet of the IP, which the quota is applied to.
+func subnet(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+func (s *Scheduler) feed() {
+	defer s.wg.Done()
+	sem := make(chan struct{}, s.cfg.ProbeConcurrency)
+	var probes sync.WaitGroup
+	defer func() {
+		probes.Wait()
+		s.mu.Lock()
+		s.srcDone = true
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}()
+	// candidates are numbered in the discovery order, as the probes complete in any order
+	var seq uint64
+	for s.src.Next() {
+		n := s.src.Node()
+		seq++
+		c := candidate{node: n, seq: seq}
+		select {
+		case sem <- struct{}{}:
+		case <-s.quit:
+			return
+		}
+		probes.Add(1)
+		go func() {
+			defer probes.Done()
+			defer func() { <-sem }()
+			c.rtt = s.measure(c.node)
+			s.add(c)
+		}()
+	}
+}
+
+func (s *Scheduler) measure(n *enode.Node) time.Duration {
+	if rtt, ok := s.rtts.Get(n.ID()); ok {
+		return rtt
+	}
+	rtt := unreachable
+	if n.IP() != nil && n.TCP() != 0 {
+		if measured, err := s.probe(n, s.cfg.ProbeTimeout); err == nil {
+			rtt = measured
+		} else {
+			log.Trace("Dial candidate probe failed", "id", n.ID(), "err", err)
+		}
+	}
+	s.rtts.Add(n.ID(), rtt)
+	return rtt
+}
+
+func (s *Scheduler) add(c candidate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.pool) >= s.cfg.Lookahead && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return
+	}
+	s.pool = append(s.pool, c)
+	s.cond.Broadcast()
+}
+
+// overQuota returns true if the subnet has no room for another peer.
+func (s *Scheduler) overQuota(sn string, now time.Time) bool {
+	if s.cfg.SubnetQuota <= 0 || sn == "" {
+		return false
+	}
+	count := 0
+	for _, c := range s.connected {
+		if c == sn {
+			count++
+		}
+	}
+	for id, d := range s.dialing {
+		if now.Sub(d.at) > s.cfg.DialTimeout {
+			delete(s.dialing, id)
+			continue
+		}
+		if d.subnet == sn {
+			count++
+		}
+	}
+	return count >= s.cfg.SubnetQuota
+}
+
+// pick takes the next candidate from the pool. The connected candidates and the ones over the subnet quota
+// are dropped, the discovery will offer them again later.
+func (s *Scheduler) pick(now time.Time) *enode.Node {
+	kept := s.pool[:0]
+	for _, c := range s.pool {
+		if _, ok := s.connected[c.node.ID()]; ok {
+			continue
+		}
+		if !s.overQuota(subnet(c.node.IP()), now) {
+			kept = append(kept, c)
+		}
+	}
+	s.pool = kept
+	if len(s.pool) == 0 {
+		return nil
+	}
+
+	s.picks++
+	explore := s.cfg.ExploreEvery > 0 && s.picks%uint64(s.cfg.ExploreEvery) == 0
+	best := 0
+	for i, c := range s.pool {
+		b := s.pool[best]
+		if explore {
+			if c.seq < b.seq {
+				best = i
+			}
+		} else if c.rtt < b.rtt || (c.rtt == b.rtt && c.seq < b.seq) {
+			best = i
+		}
+	}
+	n := s.pool[best].node
+	s.pool = append(s.pool[:best], s.pool[best+1:]...)
+	s.dialing[n.ID()] = dialed{
+		subnet: subnet(n.IP()),
+		at:     now,
+	}
+	return n
+}
+
+// Next implements enode.Iterator. It blocks until a candidate is available.
+func (s *Scheduler) Next() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if s.closed {
+			s.cur = nil
+			return false
+		}
+		if n := s.pick(time.Now()); n != nil {
+			s.cur = n
+			s.cond.Broadcast()
+			return true
+		}
+		if s.srcDone {
+			s.cur = nil
+			return false
+		}
+		s.cond.Wait()
+	}
+}
+
+// Node implements enode.Iterator.
+func (s *Scheduler) Node() *enode.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// Close implements enode.Iterator. It closes the source iterator.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	close(s.quit)
+	s.src.Close()
+	s.wg.Wait()
+}
+
+// Connected records the connected peer.
+func (s *Scheduler) Connected(n *enode.Node) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dialing, n.ID())
+	s.connected[n.ID()] = subnet(n.IP())
+}
+
+// Disconnected records the disconnected peer.
+func (s *Scheduler) Disconnected(id enode.ID) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.connected, id)
+	s.cond.Broadcast()
+}
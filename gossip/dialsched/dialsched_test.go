@@ -0,0 +1,137 @@
+package dialsched
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/stretchr/testify/require"
+)
+
+func testNode(id byte, ip string) *enode.Node {
+	var r enr.Record
+	r.Set(enr.IP(net.ParseIP(ip)))
+	r.Set(enr.TCP(5050))
+	return enode.SignNull(&r, enode.ID{id})
+}
+
+func testProber(rtts map[enode.ID]time.Duration) Prober {
+	return func(n *enode.Node, _ time.Duration) (time.Duration, error) {
+		rtt, ok := rtts[n.ID()]
+		if !ok {
+			return 0, errors.New("no answer")
+		}
+		return rtt, nil
+	}
+}
+
+func startScheduler(t *testing.T, cfg Config, nodes []*enode.Node, rtts map[enode.ID]time.Duration) *Scheduler {
+	s := New(cfg, enode.IterNodes(nodes), testProber(rtts))
+	// wait until all the candidates are collected to make the order deterministic
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.srcDone
+	}, time.Second, time.Millisecond)
+	return s
+}
+
+func dialAll(s *Scheduler) []enode.ID {
+	var ids []enode.ID
+	for s.Next() {
+		ids = append(ids, s.Node().ID())
+	}
+	return ids
+}
+
+func TestSchedulerPrefersLowRTT(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.ExploreEvery = 0
+	nodes := []*enode.Node{
+		testNode(1, "10.0.1.1"),
+		testNode(2, "10.0.2.1"),
+		testNode(3, "10.0.3.1"),
+		testNode(4, "10.0.4.1"),
+	}
+	s := startScheduler(t, cfg, nodes, map[enode.ID]time.Duration{
+		{1}: 80 * time.Millisecond,
+		{2}: 10 * time.Millisecond,
+		{4}: 40 * time.Millisecond,
+	})
+	defer s.Close()
+
+	// the unreachable node is dialed last
+	require.Equal([]enode.ID{{2}, {4}, {1}, {3}}, dialAll(s))
+}
+
+func TestSchedulerExplores(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.ExploreEvery = 2
+	nodes := []*enode.Node{
+		testNode(1, "10.0.1.1"),
+		testNode(2, "10.0.2.1"),
+		testNode(3, "10.0.3.1"),
+	}
+	s := startScheduler(t, cfg, nodes, map[enode.ID]time.Duration{
+		{1}: 300 * time.Millisecond,
+		{2}: 200 * time.Millisecond,
+		{3}: 100 * time.Millisecond,
+	})
+	defer s.Close()
+
+	// every second candidate is the earliest discovered one
+	require.Equal([]enode.ID{{3}, {1}, {2}}, dialAll(s))
+}
+
+func TestSchedulerSubnetQuota(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.ExploreEvery = 0
+	cfg.SubnetQuota = 2
+	nodes := []*enode.Node{
+		testNode(1, "10.0.1.1"),
+		testNode(2, "10.0.1.2"),
+		testNode(3, "10.0.1.3"),
+		testNode(4, "10.0.2.1"),
+	}
+	rtts := map[enode.ID]time.Duration{
+		{1}: 10 * time.Millisecond,
+		{2}: 20 * time.Millisecond,
+		{3}: 30 * time.Millisecond,
+		{4}: 90 * time.Millisecond,
+	}
+
+	// a peer of the subnet is already connected
+	s := New(cfg, enode.IterNodes(nodes), testProber(rtts))
+	s.Connected(testNode(5, "10.0.1.5"))
+	require.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.srcDone
+	}, time.Second, time.Millisecond)
+	defer s.Close()
+
+	// only one more peer of the crowded subnet is dialed
+	require.Equal([]enode.ID{{1}, {4}}, dialAll(s))
+
+	// nil scheduler ignores the peers changes
+	var disabled *Scheduler
+	disabled.Connected(nodes[0])
+	disabled.Disconnected(nodes[0].ID())
+}
+
+func TestSubnet(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("10.0.1.0/24", subnet(net.ParseIP("10.0.1.200")))
+	require.Equal("2001:db8:1::/48", subnet(net.ParseIP("2001:db8:1:2::1")))
+	require.Equal("", subnet(nil))
+}
@@ -128,6 +128,10 @@ func (p *dummyTxPool) OnlyNotExisting(txids []common.Hash) []common.Hash {
 	return notExisting
 }
 
+func (p *dummyTxPool) Dropped(txid common.Hash) (evmcore.TxDrop, bool) {
+	return evmcore.TxDrop{}, false
+}
+
 func (p *dummyTxPool) SampleHashes(max int) []common.Hash {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
@@ -0,0 +1,67 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SimulatedTx is a pool transaction, which the next event of the validator would originate.
+type SimulatedTx struct {
+	Hash      common.Hash    `json:"hash"`
+	From      common.Address `json:"from"`
+	Nonce     hexutil.Uint64 `json:"nonce"`
+	Gas       hexutil.Uint64 `json:"gas"`
+	GasFeeCap *hexutil.Big   `json:"maxFeePerGas"`
+	GasTipCap *hexutil.Big   `json:"maxPriorityFeePerGas"`
+}
+
+// EmissionSimulation is the result of a dry run of the next event emission.
+type EmissionSimulation struct {
+	Epoch        hexutil.Uint64 `json:"epoch"`
+	Seq          hexutil.Uint64 `json:"seq"`
+	Transactions []SimulatedTx  `json:"transactions"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasPowerLeft hexutil.Uint64 `json:"gasPowerLeft"`
+	MaxGasToUse  hexutil.Uint64 `json:"maxGasToUse"`
+	EmitAllowed  bool           `json:"emitAllowed"`
+	Paused       bool           `json:"paused"`
+	Skipped      map[string]int `json:"skipped"`
+}
+
+// SimulateEmission reports which pool transactions the next event of the validator would originate
+// under the current gas power and emission policy, and their gas. Nothing is emitted.
+func (api *PrivateValidatorAPI) SimulateEmission() (*EmissionSimulation, error) {
+	em := api.s.validatorEmitter()
+	if em == nil {
+		return nil, errNotValidator
+	}
+	sim, err := em.Simulate()
+	if err != nil {
+		return nil, err
+	}
+	signer := api.s.EthAPI.signer
+	txs := make([]SimulatedTx, len(sim.Txs))
+	for i, tx := range sim.Txs {
+		from, _ := types.Sender(signer, tx)
+		txs[i] = SimulatedTx{
+			Hash:      tx.Hash(),
+			From:      from,
+			Nonce:     hexutil.Uint64(tx.Nonce()),
+			Gas:       hexutil.Uint64(tx.Gas()),
+			GasFeeCap: (*hexutil.Big)(tx.GasFeeCap()),
+			GasTipCap: (*hexutil.Big)(tx.GasTipCap()),
+		}
+	}
+	return &EmissionSimulation{
+		Epoch:        hexutil.Uint64(sim.Epoch),
+		Seq:          hexutil.Uint64(sim.Seq),
+		Transactions: txs,
+		Gas:          hexutil.Uint64(sim.Gas),
+		GasPowerLeft: hexutil.Uint64(sim.GasPowerLeft),
+		MaxGasToUse:  hexutil.Uint64(sim.MaxGasToUse),
+		EmitAllowed:  sim.Allowed,
+		Paused:       sim.Paused,
+		Skipped:      sim.Skipped,
+	}, nil
+}
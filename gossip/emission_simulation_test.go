@@ -0,0 +1,43 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateEmission(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv(2, 1, t)
+	defer env.Close()
+
+	api := NewPrivateValidatorAPI(env.Service)
+	epoch := env.store.GetEpoch()
+	vid := env.store.GetValidators().GetID(0)
+
+	sim, err := api.SimulateEmission()
+	require.NoError(err)
+	require.Empty(sim.Transactions)
+	require.Zero(sim.Gas)
+
+	tx := env.Transfer(1, 1, big.NewInt(1))
+	env.txpool.AddRemotes(types.Transactions{tx})
+	defer env.txpool.(*dummyTxPool).Clear()
+
+	sim, err = api.SimulateEmission()
+	require.NoError(err)
+	require.Equal(uint64(1), uint64(sim.Seq))
+	require.Len(sim.Transactions, 1)
+	require.Equal(tx.Hash(), sim.Transactions[0].Hash)
+	require.Equal(env.Address(idx.ValidatorID(1)), sim.Transactions[0].From)
+	require.Equal(tx.Gas(), uint64(sim.Gas))
+	require.Greater(uint64(sim.GasPowerLeft), uint64(sim.Gas))
+
+	// nothing is emitted by the simulation
+	require.Nil(env.store.GetLastEvent(epoch, vid))
+	require.True(env.txpool.Has(tx.Hash()))
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
@@ -23,6 +24,9 @@ type EmitIntervals struct {
 type ValidatorConfig struct {
 	ID     idx.ValidatorID
 	PubKey validatorpk.PubKey
+	// RewardRecipient is the advisory recipient of the validator rewards. The rewards are paid by the SFC
+	// to the stake owner and the blocks coinbase is always zero, so it's only reported to the tooling.
+	RewardRecipient common.Address `toml:",omitempty"`
 }
 
 type FileConfig struct {
@@ -52,6 +56,8 @@ type Config struct {
 	PrevEmittedEventFile FileConfig
 	PrevBlockVotesFile   FileConfig
 	PrevEpochVoteFile    FileConfig
+
+	Pressure PressureConfig
 }
 
 // DefaultConfig returns the default configurations for the events emitter.
@@ -76,6 +82,17 @@ func DefaultConfig() Config {
 		EmergencyThreshold:  opera.DefaultEventGas * 5,
 
 		TxsCacheInvalidation: 200 * time.Millisecond,
+
+		Pressure: PressureConfig{
+			EventQueueLimit:   64,
+			ExecutionLagLimit: 5 * time.Second,
+			DiskLatencyLimit:  5 * time.Second,
+			SlowdownLevel:     100,
+			PauseLevel:        200,
+			Hysteresis:        20,
+			SlowdownFactor:    4,
+			MaxPause:          5 * time.Second,
+		},
 	}
 }
 
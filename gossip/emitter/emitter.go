@@ -16,6 +16,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/inter/pos"
 	"github.com/Fantom-foundation/lachesis-base/utils/piecefunc"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Fantom-foundation/go-opera/gossip/emitter/originatedtxs"
@@ -100,6 +101,13 @@ type Emitter struct {
 	emittedEvFile    *os.File
 	busyRate         *rate.Gauge
 
+	pressure pressureMonitor
+
+	rewardMu        sync.RWMutex
+	rewardRecipient common.Address
+
+	paused uint32
+
 	logger.Periodic
 }
 
@@ -119,6 +127,8 @@ func NewEmitter(
 		originatedTxs:            originatedtxs.New(SenderCountBufferSize),
 		intervals:                config.EmitIntervals,
 		globalConfirmingInterval: config.EmitIntervals.Confirming,
+		pressure:                 pressureMonitor{cfg: config.Pressure},
+		rewardRecipient:          config.Validator.RewardRecipient,
 		Periodic:                 logger.Periodic{Instance: logger.New()},
 	}
 }
@@ -208,7 +218,7 @@ func (em *Emitter) tick() {
 
 	em.recheckChallenges()
 	em.recheckIdleTime()
-	if time.Since(em.prevEmittedAtTime) >= em.intervals.Min {
+	if time.Since(em.prevEmittedAtTime) >= em.pressureInterval() {
 		_, err := em.EmitEvent()
 		if err != nil {
 			em.Log.Error("Event emitting error", "err", err)
@@ -226,23 +236,31 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 		return em.cache.sortedTxs.Copy()
 	}
 	// Build the cache
-	pendingTxs, err := em.world.TxPool.Pending(true)
+	sortedTxs, err := em.sortPendingTxs()
 	if err != nil {
 		em.Log.Error("Tx pool transactions fetching error", "err", err)
 		return nil
 	}
+	em.cache.sortedTxs = sortedTxs
+	em.cache.poolCount = poolCount
+	em.cache.poolBlock = em.world.GetLatestBlockIndex()
+	em.cache.poolTime = time.Now()
+	return sortedTxs.Copy()
+}
+
+// sortPendingTxs returns the pending pool transactions in the order of origination, bypassing the cache.
+func (em *Emitter) sortPendingTxs() (*types.TransactionsByPriceAndNonce, error) {
+	pendingTxs, err := em.world.TxPool.Pending(true)
+	if err != nil {
+		return nil, err
+	}
 	for from, txs := range pendingTxs {
 		// Filter the excessive transactions from each sender
 		if len(txs) > em.config.MaxTxsPerAddress {
 			pendingTxs[from] = txs[:em.config.MaxTxsPerAddress]
 		}
 	}
-	sortedTxs := types.NewTransactionsByPriceAndNonce(em.world.TxSigner, pendingTxs, em.world.GetRules().Economy.MinGasPrice)
-	em.cache.sortedTxs = sortedTxs
-	em.cache.poolCount = poolCount
-	em.cache.poolBlock = em.world.GetLatestBlockIndex()
-	em.cache.poolTime = time.Now()
-	return sortedTxs.Copy()
+	return types.NewTransactionsByPriceAndNonce(em.world.TxSigner, pendingTxs, em.world.GetRules().Economy.MinGasPrice), nil
 }
 
 func (em *Emitter) EmitEvent() (*inter.EventPayload, error) {
@@ -318,18 +336,16 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 		return nil, nil
 	}
 
+	if em.Paused() {
+		em.Periodic.Info(7*time.Second, "Emitting is paused", "reason", "read-only mode")
+		return nil, nil
+	}
+
 	if synced := em.logSyncStatus(em.isSyncedToEmit()); !synced {
 		// I'm reindexing my old events, so don't create events until connect all the existing self-events
 		return nil, nil
 	}
 
-	var (
-		selfParentSeq  idx.Event
-		selfParentTime inter.Timestamp
-		parents        hash.Events
-		maxLamport     idx.Lamport
-	)
-
 	// Find parents
 	selfParent, parents, ok := em.chooseParents(em.epoch, em.config.Validator.ID)
 	if !ok {
@@ -342,6 +358,92 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 		}
 	}
 
+	draft, err := em.buildEventDraft(selfParent, parents)
+	if err != nil {
+		if err == errSelfFork {
+			em.Periodic.Error(5*time.Second, "I've created a fork, events emitting isn't allowed", "creator", em.config.Validator.ID)
+		} else if err == ErrNotEnoughGasPower {
+			em.Periodic.Warn(time.Second, "Not enough gas power to emit event. Too small stake?",
+				"stake%", 100*float64(em.validators.Get(em.config.Validator.ID))/float64(em.validators.TotalWeight()))
+		} else {
+			em.Log.Warn("Dropped event while emitting", "err", err)
+		}
+		return nil, nil
+	}
+	mutEvent, parentHeaders, selfParentHeader, metric := draft.event, draft.parents, draft.selfParent, draft.metric
+
+	// Pre-check if event should be emitted
+	// It is checked in advance to avoid adding transactions just to immediately drop the event later
+	if !em.isAllowedToEmit(mutEvent, true, metric, selfParentHeader) {
+		return nil, nil
+	}
+
+	// Add txs
+	em.addTxs(mutEvent, sortedTxs)
+
+	// Check if event should be emitted
+	// Check only if no txs were added, since check in a case with added txs was performed above
+	if mutEvent.Txs().Len() == 0 {
+		if !em.isAllowedToEmit(mutEvent, mutEvent.Txs().Len() != 0, metric, selfParentHeader) {
+			return nil, nil
+		}
+	}
+
+	// calc Payload hash
+	mutEvent.SetPayloadHash(inter.CalcPayloadHash(mutEvent))
+
+	// sign
+	bSig, err := em.world.Signer.Sign(em.config.Validator.PubKey, mutEvent.HashToSign().Bytes())
+	if err != nil {
+		em.Periodic.Error(time.Second, "Failed to sign event", "err", err)
+		return nil, err
+	}
+	var sig inter.Signature
+	copy(sig[:], bSig)
+	mutEvent.SetSig(sig)
+
+	// build clean event
+	event := mutEvent.Build()
+
+	// check
+	if err := em.world.Check(event, parentHeaders); err != nil {
+		em.Periodic.Error(time.Second, "Emitted incorrect event", "err", err)
+		return nil, err
+	}
+
+	// set mutEvent name for debug
+	em.nameEventForDebug(event)
+
+	for _, tx := range event.Txs() {
+		txTime := txtime.Get(tx.Hash()) // time when was the tx seen first time
+		if !txTime.Equal(time.Time{}) {
+			txTimeToEmitTimer.Update(time.Since(txTime))
+		}
+	}
+
+	return event, nil
+}
+
+// errSelfFork is returned if the validator has multiple heads, i.e. it has created a fork.
+var errSelfFork = errors.New("multiple self-parents")
+
+// eventDraft is an unsigned event without transactions, built on top of the current heads.
+type eventDraft struct {
+	event      *inter.MutableEventPayload
+	parents    inter.Events
+	selfParent *inter.Event
+	metric     ancestor.Metric
+}
+
+// buildEventDraft creates the next event of the validator and sets its consensus fields and gas power.
+// The event isn't connected, so the draft can be dropped.
+func (em *Emitter) buildEventDraft(selfParent *hash.Event, parents hash.Events) (*eventDraft, error) {
+	var (
+		selfParentSeq  idx.Event
+		selfParentTime inter.Timestamp
+		maxLamport     idx.Lamport
+	)
+
 	// Set parent-dependent fields
 	parentHeaders := make(inter.Events, len(parents))
 	for i, p := range parents {
@@ -352,14 +454,11 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 		parentHeaders[i] = parent
 		if parentHeaders[i].Creator() == em.config.Validator.ID && i != 0 {
 			// there are 2 heads from me, i.e. due to a fork, chooseParents could have found multiple self-parents
-			em.Periodic.Error(5*time.Second, "I've created a fork, events emitting isn't allowed", "creator", em.config.Validator.ID)
-			return nil, nil
+			return nil, errSelfFork
 		}
 		maxLamport = idx.MaxLamport(maxLamport, parent.Lamport())
 	}
 
-	selfParentSeq = 0
-	selfParentTime = 0
 	var selfParentHeader *inter.Event
 	if selfParent != nil {
 		selfParentHeader = parentHeaders[0]
@@ -415,65 +514,14 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 		}
 	})
 	if err != nil {
-		if err == ErrNotEnoughGasPower {
-			em.Periodic.Warn(time.Second, "Not enough gas power to emit event. Too small stake?",
-				"stake%", 100*float64(em.validators.Get(em.config.Validator.ID))/float64(em.validators.TotalWeight()))
-		} else {
-			em.Log.Warn("Dropped event while emitting", "err", err)
-		}
-		return nil, nil
-	}
-
-	// Pre-check if event should be emitted
-	// It is checked in advance to avoid adding transactions just to immediately drop the event later
-	if !em.isAllowedToEmit(mutEvent, true, metric, selfParentHeader) {
-		return nil, nil
-	}
-
-	// Add txs
-	em.addTxs(mutEvent, sortedTxs)
-
-	// Check if event should be emitted
-	// Check only if no txs were added, since check in a case with added txs was performed above
-	if mutEvent.Txs().Len() == 0 {
-		if !em.isAllowedToEmit(mutEvent, mutEvent.Txs().Len() != 0, metric, selfParentHeader) {
-			return nil, nil
-		}
-	}
-
-	// calc Payload hash
-	mutEvent.SetPayloadHash(inter.CalcPayloadHash(mutEvent))
-
-	// sign
-	bSig, err := em.world.Signer.Sign(em.config.Validator.PubKey, mutEvent.HashToSign().Bytes())
-	if err != nil {
-		em.Periodic.Error(time.Second, "Failed to sign event", "err", err)
 		return nil, err
 	}
-	var sig inter.Signature
-	copy(sig[:], bSig)
-	mutEvent.SetSig(sig)
-
-	// build clean event
-	event := mutEvent.Build()
-
-	// check
-	if err := em.world.Check(event, parentHeaders); err != nil {
-		em.Periodic.Error(time.Second, "Emitted incorrect event", "err", err)
-		return nil, err
-	}
-
-	// set mutEvent name for debug
-	em.nameEventForDebug(event)
-
-	for _, tx := range event.Txs() {
-		txTime := txtime.Get(tx.Hash()) // time when was the tx seen first time
-		if !txTime.Equal(time.Time{}) {
-			txTimeToEmitTimer.Update(time.Since(txTime))
-		}
-	}
-
-	return event, nil
+	return &eventDraft{
+		event:      mutEvent,
+		parents:    parentHeaders,
+		selfParent: selfParentHeader,
+		metric:     metric,
+	}, nil
 }
 
 func (em *Emitter) idle() bool {
@@ -55,6 +55,15 @@ func TestEmitter(t *testing.T) {
 	external.EXPECT().StateDB().
 		Return(nil).
 		AnyTimes()
+	external.EXPECT().EventQueueLen().
+		Return(0).
+		AnyTimes()
+	external.EXPECT().ExecutionLag().
+		Return(time.Duration(0)).
+		AnyTimes()
+	external.EXPECT().DiskLatency().
+		Return(time.Duration(0)).
+		AnyTimes()
 
 	em := NewEmitter(cfg, World{
 		External: external,
@@ -7,6 +7,7 @@ package mock
 import (
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	inter "github.com/Fantom-foundation/go-opera/inter"
 	validatorpk "github.com/Fantom-foundation/go-opera/inter/validatorpk"
@@ -98,6 +99,48 @@ func (mr *MockExternalMockRecorder) DagIndex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DagIndex", reflect.TypeOf((*MockExternal)(nil).DagIndex))
 }
 
+// DiskLatency mocks base method.
+func (m *MockExternal) DiskLatency() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskLatency")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DiskLatency indicates an expected call of DiskLatency.
+func (mr *MockExternalMockRecorder) DiskLatency() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskLatency", reflect.TypeOf((*MockExternal)(nil).DiskLatency))
+}
+
+// EventQueueLen mocks base method.
+func (m *MockExternal) EventQueueLen() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventQueueLen")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// EventQueueLen indicates an expected call of EventQueueLen.
+func (mr *MockExternalMockRecorder) EventQueueLen() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventQueueLen", reflect.TypeOf((*MockExternal)(nil).EventQueueLen))
+}
+
+// ExecutionLag mocks base method.
+func (m *MockExternal) ExecutionLag() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecutionLag")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// ExecutionLag indicates an expected call of ExecutionLag.
+func (mr *MockExternalMockRecorder) ExecutionLag() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecutionLag", reflect.TypeOf((*MockExternal)(nil).ExecutionLag))
+}
+
 // GetBlockEpoch mocks base method.
 func (m *MockExternal) GetBlockEpoch(arg0 idx.Block) idx.Epoch {
 	m.ctrl.T.Helper()
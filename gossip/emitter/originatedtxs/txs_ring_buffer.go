@@ -2,16 +2,17 @@ package originatedtxs
 
 import (
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
 )
 
 type Buffer struct {
-	senderCount *simplelru.LRU // sender address -> number of transactions
+	senderCount *lru.Cache[common.Address, int] // sender address -> number of transactions
 }
 
 func New(maxAddresses int) *Buffer {
 	ring := &Buffer{}
-	ring.senderCount, _ = simplelru.NewLRU(maxAddresses, nil)
+	ring.senderCount = lru.New[common.Address, int](uint(maxAddresses), maxAddresses, nil)
 	return ring
 }
 
@@ -19,9 +20,9 @@ func New(maxAddresses int) *Buffer {
 func (ring *Buffer) Inc(sender common.Address) {
 	cur, ok := ring.senderCount.Peek(sender)
 	if ok {
-		ring.senderCount.Add(sender, cur.(int)+1)
+		ring.senderCount.Add(sender, cur+1)
 	} else {
-		ring.senderCount.Add(sender, int(1))
+		ring.senderCount.Add(sender, 1)
 	}
 }
 
@@ -31,10 +32,10 @@ func (ring *Buffer) Dec(sender common.Address) {
 	if !ok {
 		return
 	}
-	if cur.(int) <= 1 {
+	if cur <= 1 {
 		ring.senderCount.Remove(sender)
 	} else {
-		ring.senderCount.Add(sender, cur.(int)-1)
+		ring.senderCount.Add(sender, cur-1)
 	}
 }
 
@@ -49,7 +50,7 @@ func (ring *Buffer) TotalOf(sender common.Address) int {
 	if !ok {
 		return 0
 	}
-	return cur.(int)
+	return cur
 }
 
 // Empty is not safe for concurrent use
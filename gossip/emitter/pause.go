@@ -0,0 +1,17 @@
+package emitter
+
+import "sync/atomic"
+
+// Paused returns true if the events emitting is paused by SetPaused.
+func (em *Emitter) Paused() bool {
+	return atomic.LoadUint32(&em.paused) != 0
+}
+
+// SetPaused pauses or resumes the events emitting and returns the previous state.
+func (em *Emitter) SetPaused(paused bool) bool {
+	v := uint32(0)
+	if paused {
+		v = 1
+	}
+	return atomic.SwapUint32(&em.paused, v) != 0
+}
@@ -0,0 +1,133 @@
+package emitter
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	pressureLevelGauge = metrics.GetOrRegisterGauge("emitter/pressure/level", nil) // highest signal to its limit ratio, in percents
+	pressureStateGauge = metrics.GetOrRegisterGauge("emitter/pressure/state", nil) // 0 - normal, 1 - slowed down, 2 - paused
+	slowdownsCounter   = metrics.GetOrRegisterCounter("emitter/pressure/slowdowns", nil)
+	pausesCounter      = metrics.GetOrRegisterCounter("emitter/pressure/pauses", nil)
+)
+
+// PressureConfig is the configuration of the emission throttling, which makes a node falling behind
+// not emit the events it can't back with a timely execution.
+// The pressure level is the highest ratio of a load signal to its limit, in percents. A zero limit disables the signal.
+type PressureConfig struct {
+	EventQueueLimit   int           // queued events processing tasks
+	ExecutionLagLimit time.Duration // age of a block at the moment of its execution
+	DiskLatencyLimit  time.Duration // duration of a DB flush
+
+	SlowdownLevel uint64 // level at which the emission is slowed down
+	PauseLevel    uint64 // level at which the emission is paused
+	Hysteresis    uint64 // how much the level has to fall below the state level to leave the state
+
+	SlowdownFactor uint64        // multiplier of the min emit interval when slowed down
+	MaxPause       time.Duration // min emit interval when paused, not to halt the consensus
+}
+
+type pressureState int
+
+const (
+	pressureNormal pressureState = iota
+	pressureSlowdown
+	pressurePause
+)
+
+// pressureMonitor tracks the pressure state with a hysteresis, so the emission isn't flapping
+// while the load stays around a threshold.
+type pressureMonitor struct {
+	cfg   PressureConfig
+	state pressureState
+}
+
+func ratio(value, limit uint64) uint64 {
+	if limit == 0 {
+		return 0
+	}
+	return value * 100 / limit
+}
+
+func (m *pressureMonitor) level(eventQueue int, executionLag, diskLatency time.Duration) uint64 {
+	level := ratio(uint64(eventQueue), uint64(m.cfg.EventQueueLimit))
+	if l := ratio(uint64(executionLag), uint64(m.cfg.ExecutionLagLimit)); l > level {
+		level = l
+	}
+	if l := ratio(uint64(diskLatency), uint64(m.cfg.DiskLatencyLimit)); l > level {
+		level = l
+	}
+	return level
+}
+
+// below returns true if the level is low enough to leave the state of the threshold.
+func (m *pressureMonitor) below(level, threshold uint64) bool {
+	return level+m.cfg.Hysteresis < threshold
+}
+
+func (m *pressureMonitor) update(level uint64) pressureState {
+	prev := m.state
+	switch {
+	case m.cfg.PauseLevel != 0 && level >= m.cfg.PauseLevel:
+		m.state = pressurePause
+	case m.cfg.SlowdownLevel != 0 && level >= m.cfg.SlowdownLevel:
+		if m.state != pressurePause || m.below(level, m.cfg.PauseLevel) {
+			m.state = pressureSlowdown
+		}
+	default:
+		if m.state == pressurePause && !m.below(level, m.cfg.PauseLevel) {
+			break
+		}
+		if m.state != pressureNormal && m.cfg.SlowdownLevel != 0 && !m.below(level, m.cfg.SlowdownLevel) {
+			m.state = pressureSlowdown
+			break
+		}
+		m.state = pressureNormal
+	}
+
+	pressureLevelGauge.Update(int64(level))
+	pressureStateGauge.Update(int64(m.state))
+	if m.state != prev {
+		switch m.state {
+		case pressureSlowdown:
+			slowdownsCounter.Inc(1)
+		case pressurePause:
+			pausesCounter.Inc(1)
+		}
+	}
+	return m.state
+}
+
+// minInterval returns the min emit interval in the current state.
+func (m *pressureMonitor) minInterval(interval time.Duration) time.Duration {
+	switch m.state {
+	case pressurePause:
+		if m.cfg.MaxPause > interval {
+			return m.cfg.MaxPause
+		}
+	case pressureSlowdown:
+		if m.cfg.SlowdownFactor > 1 {
+			return interval * time.Duration(m.cfg.SlowdownFactor)
+		}
+	}
+	return interval
+}
+
+// pressureInterval updates the pressure state and returns the min emit interval.
+func (em *Emitter) pressureInterval() time.Duration {
+	prev := em.pressure.state
+	level := em.pressure.level(em.world.EventQueueLen(), em.world.ExecutionLag(), em.world.DiskLatency())
+	if state := em.pressure.update(level); state != prev {
+		switch state {
+		case pressureNormal:
+			em.Log.Info("Node load is normal, emission is restored", "level", level)
+		case pressureSlowdown:
+			em.Log.Warn("Node is falling behind, emission is slowed down", "level", level)
+		case pressurePause:
+			em.Log.Warn("Node is falling behind, emission is paused", "level", level)
+		}
+	}
+	return em.pressure.minInterval(em.intervals.Min)
+}
@@ -0,0 +1,57 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPressureMonitor(t *testing.T) {
+	require := require.New(t)
+
+	m := pressureMonitor{cfg: DefaultConfig().Pressure}
+	m.cfg.MaxPause = 5 * time.Second
+	base := 150 * time.Millisecond
+
+	// the level is the highest ratio of the signals to their limits
+	require.Equal(uint64(0), m.level(0, 0, 0))
+	require.Equal(uint64(50), m.level(m.cfg.EventQueueLimit/2, 0, 0))
+	require.Equal(uint64(200), m.level(0, 2*m.cfg.ExecutionLagLimit, m.cfg.DiskLatencyLimit))
+	require.Equal(uint64(150), m.level(0, 0, m.cfg.DiskLatencyLimit*3/2))
+
+	for _, step := range []struct {
+		level    uint64
+		expected pressureState
+	}{
+		{0, pressureNormal},
+		{99, pressureNormal},
+		{100, pressureSlowdown},
+		// hysteresis keeps the state until the level falls low enough
+		{85, pressureSlowdown},
+		{79, pressureNormal},
+		{210, pressurePause},
+		{190, pressurePause},
+		{150, pressureSlowdown},
+		{195, pressureSlowdown},
+		{200, pressurePause},
+		// a sudden drop leaves both the states
+		{10, pressureNormal},
+	} {
+		require.Equal(step.expected, m.update(step.level), step.level)
+		switch step.expected {
+		case pressureNormal:
+			require.Equal(base, m.minInterval(base))
+		case pressureSlowdown:
+			require.Equal(4*base, m.minInterval(base))
+		case pressurePause:
+			require.Equal(m.cfg.MaxPause, m.minInterval(base))
+		}
+	}
+
+	// zero limits disable the signals
+	disabled := pressureMonitor{}
+	require.Equal(uint64(0), disabled.level(1000, time.Hour, time.Hour))
+	require.Equal(pressureNormal, disabled.update(1000))
+	require.Equal(base, disabled.minInterval(base))
+}
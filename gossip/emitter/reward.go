@@ -0,0 +1,28 @@
+package emitter
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RewardRecipient returns the current reward recipient of the validator.
+func (em *Emitter) RewardRecipient() common.Address {
+	em.rewardMu.RLock()
+	defer em.rewardMu.RUnlock()
+	return em.rewardRecipient
+}
+
+// SetRewardRecipient updates the reward recipient of the validator and returns the previous one.
+// The update isn't persisted, the configured recipient is used after a restart.
+func (em *Emitter) SetRewardRecipient(addr common.Address) common.Address {
+	em.rewardMu.Lock()
+	defer em.rewardMu.Unlock()
+	prev := em.rewardRecipient
+	em.rewardRecipient = addr
+	return prev
+}
+
+// ValidatorID returns the ID of the validator the emitter creates events from.
+func (em *Emitter) ValidatorID() idx.ValidatorID {
+	return em.config.Validator.ID
+}
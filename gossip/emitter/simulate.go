@@ -0,0 +1,80 @@
+package emitter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	errNotValidator = errors.New("not a validator of the current epoch")
+	errNoParents    = errors.New("no parents to build the next event on")
+)
+
+// Simulation is a dry run of the next event emission against the current pool contents.
+type Simulation struct {
+	Epoch idx.Epoch
+	Seq   idx.Event
+	// Txs are the transactions the event would originate, in the order of origination
+	Txs types.Transactions
+	// Gas is the gas of Txs, which is consumed from the validator gas power
+	Gas uint64
+	// GasPowerLeft is the gas power of the event before the transactions are originated
+	GasPowerLeft uint64
+	// MaxGasToUse is the gas power the policy allows the event to consume by transactions
+	MaxGasToUse uint64
+	// Allowed is true if the emission policy would emit the event now
+	Allowed bool
+	Paused  bool
+	// Skipped is the number of the pool transactions not originated, by reason
+	Skipped map[string]int
+}
+
+// Simulate builds the next event of the validator without signing or connecting it, and reports
+// which pool transactions it would originate under the current gas power and policy.
+// The simulation doesn't affect the emitter state, except for the metrics of the validators turns.
+func (em *Emitter) Simulate() (*Simulation, error) {
+	sortedTxs, err := em.sortPendingTxs()
+	if err != nil {
+		return nil, err
+	}
+
+	em.world.Lock()
+	defer em.world.Unlock()
+
+	if !em.isValidator() {
+		return nil, errNotValidator
+	}
+	selfParent, parents, ok := em.chooseParents(em.epoch, em.config.Validator.ID)
+	if !ok {
+		return nil, errNoParents
+	}
+	draft, err := em.buildEventDraft(selfParent, parents)
+	if err != nil {
+		return nil, err
+	}
+	e := draft.event
+
+	res := &Simulation{
+		Epoch:        e.Epoch(),
+		Seq:          e.Seq(),
+		GasPowerLeft: e.GasPowerLeft().Min(),
+		MaxGasToUse:  em.maxGasPowerToUse(e),
+		Paused:       em.Paused(),
+		Skipped:      make(map[string]int),
+	}
+	// the same checks as done by createEvent
+	res.Allowed = em.isAllowedToEmit(e, true, draft.metric, draft.selfParent)
+	gasUsed := e.GasPowerUsed()
+	em.selectTxs(e, sortedTxs, time.Now(), func(_ *types.Transaction, reason string) {
+		res.Skipped[reason]++
+	})
+	if res.Allowed && e.Txs().Len() == 0 {
+		res.Allowed = em.isAllowedToEmit(e, false, draft.metric, draft.selfParent)
+	}
+	res.Txs = e.Txs()
+	res.Gas = e.GasPowerUsed() - gasUsed
+	return res, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/pos"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/Fantom-foundation/go-opera/eventcheck/epochcheck"
@@ -142,7 +143,34 @@ func (em *Emitter) isMyTxTurn(txHash common.Hash, sender common.Address, account
 	return false
 }
 
+// Reasons of the pool transactions not being originated by an event.
+const (
+	txSkippedEpochRules        = "epochRules"
+	txSkippedNoValidatorGas    = "noValidatorGas"
+	txSkippedConflictingSender = "conflictingSender"
+	txSkippedNotMyTurn         = "notMyTurn"
+	txSkippedOutdated          = "outdated"
+)
+
+var txsSkippedCounters = map[string]metrics.Counter{
+	txSkippedEpochRules:        txsSkippedEpochRules,
+	txSkippedNoValidatorGas:    txsSkippedNoValidatorGas,
+	txSkippedConflictingSender: txsSkippedConflictingSender,
+	txSkippedNotMyTurn:         txsSkippedNotMyTurn,
+	txSkippedOutdated:          txsSkippedOutdated,
+}
+
+func countSkippedTx(_ *types.Transaction, reason string) {
+	txsSkippedCounters[reason].Inc(1)
+}
+
 func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce) {
+	em.selectTxs(e, sorted, time.Now(), countSkippedTx)
+}
+
+// selectTxs originates the sorted transactions by the event, as long as the policy and the gas power allow it.
+// onSkip is called for every transaction, which isn't originated.
+func (em *Emitter) selectTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce, now time.Time, onSkip func(tx *types.Transaction, reason string)) {
 	maxGasUsed := em.maxGasPowerToUse(e)
 	if maxGasUsed <= e.GasPowerUsed() {
 		return
@@ -154,13 +182,13 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 		sender, _ := types.Sender(em.world.TxSigner, tx)
 		// check transaction epoch rules (tx type, gas price)
 		if epochcheck.CheckTxs(types.Transactions{tx}, rules) != nil {
-			txsSkippedEpochRules.Inc(1)
+			onSkip(tx, txSkippedEpochRules)
 			sorted.Pop()
 			continue
 		}
 		// check there's enough gas power to originate the transaction
 		if tx.Gas() >= e.GasPowerLeft().Min() || e.GasPowerUsed()+tx.Gas() >= maxGasUsed {
-			txsSkippedNoValidatorGas.Inc(1)
+			onSkip(tx, txSkippedNoValidatorGas)
 			if params.TxGas >= e.GasPowerLeft().Min() || e.GasPowerUsed()+params.TxGas >= maxGasUsed {
 				// stop if cannot originate even an empty transaction
 				break
@@ -170,19 +198,19 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 		}
 		// check not conflicted with already originated txs (in any connected event)
 		if em.originatedTxs.TotalOf(sender) != 0 {
-			txsSkippedConflictingSender.Inc(1)
+			onSkip(tx, txSkippedConflictingSender)
 			sorted.Pop()
 			continue
 		}
 		// my turn, i.e. try to not include the same tx simultaneously by different validators
-		if !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), time.Now(), em.validators, e.Creator(), em.epoch) {
-			txsSkippedNotMyTurn.Inc(1)
+		if !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), now, em.validators, e.Creator(), em.epoch) {
+			onSkip(tx, txSkippedNotMyTurn)
 			sorted.Pop()
 			continue
 		}
 		// check transaction is not outdated
 		if !em.world.TxPool.Has(tx.Hash()) {
-			txsSkippedOutdated.Inc(1)
+			onSkip(tx, txSkippedOutdated)
 			sorted.Pop()
 			continue
 		}
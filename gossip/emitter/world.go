@@ -3,6 +3,7 @@ package emitter
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -37,6 +38,11 @@ type (
 		IsSynced() bool
 		PeersNum() int
 
+		// load signals, which throttle the emission
+		EventQueueLen() int
+		ExecutionLag() time.Duration
+		DiskLatency() time.Duration
+
 		StateDB() state.StateDB
 	}
 
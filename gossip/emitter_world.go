@@ -2,6 +2,7 @@ package gossip
 
 import (
 	"sync/atomic"
+	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -61,6 +62,18 @@ func (ew *emitterWorldProc) IsBusy() bool {
 	return atomic.LoadUint32(&ew.s.eventBusyFlag) != 0 || atomic.LoadUint32(&ew.s.blockBusyFlag) != 0
 }
 
+func (ew *emitterWorldProc) EventQueueLen() int {
+	return ew.s.handler.dagProcessor.TasksCount()
+}
+
+func (ew *emitterWorldProc) ExecutionLag() time.Duration {
+	return time.Duration(atomic.LoadInt64(&ew.s.executionLag))
+}
+
+func (ew *emitterWorldProc) DiskLatency() time.Duration {
+	return ew.s.store.FlushLatency()
+}
+
 func (ew *emitterWorldProc) StateDB() state.StateDB {
 	statedb, err := ew.s.store.evm.GetTxPoolStateDB()
 	if err != nil {
@@ -2,6 +2,7 @@ package gossip
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	notify "github.com/ethereum/go-ethereum/event"
@@ -28,6 +30,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
 	"github.com/Fantom-foundation/go-opera/tracing"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 )
 
 // EthAPIBackend implements ethapi.Backend.
@@ -50,44 +53,76 @@ func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
 }
 
 func (b *EthAPIBackend) CurrentBlock() *evmcore.EvmBlock {
-	return b.state.CurrentBlock()
+	if b.svc.config.RPCHeadLag == 0 {
+		return b.state.CurrentBlock()
+	}
+	return b.state.GetBlock(common.Hash{}, uint64(b.latestBlockIndex()))
 }
 
-func (b *EthAPIBackend) ResolveRpcBlockNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (idx.Block, error) {
+// latestBlockIndex returns the index of the latest block served over RPC.
+// It lags behind the actual head by RPCHeadLag blocks.
+func (b *EthAPIBackend) latestBlockIndex() idx.Block {
 	latest := b.svc.store.GetLatestBlockIndex()
+	if latest < b.svc.config.RPCHeadLag {
+		return 0
+	}
+	return latest - b.svc.config.RPCHeadLag
+}
+
+// isAboveHead reports whether the block is not served yet because of RPCHeadLag.
+func (b *EthAPIBackend) isAboveHead(n idx.Block) bool {
+	return b.svc.config.RPCHeadLag != 0 && n > b.latestBlockIndex()
+}
+
+// checkHistory returns an error if the block precedes the history kept by the node.
+func (b *EthAPIBackend) checkHistory(n idx.Block) error {
+	if start := b.svc.store.HistoryStart(); n < start {
+		return &ethapi.HistoryUnavailableError{First: start}
+	}
+	return nil
+}
+
+func (b *EthAPIBackend) ResolveRpcBlockNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (idx.Block, error) {
+	latest := b.latestBlockIndex()
 	if number, ok := blockNrOrHash.Number(); ok && (number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber) {
 		return latest, nil
 	} else if number, ok := blockNrOrHash.Number(); ok {
 		if idx.Block(number) > latest {
 			return 0, errors.New("block not found")
 		}
-		return idx.Block(number), nil
+		return idx.Block(number), b.checkHistory(idx.Block(number))
 	} else if h, ok := blockNrOrHash.Hash(); ok {
 		index := b.svc.store.GetBlockIndex(hash.Event(h))
-		if index == nil {
+		if index == nil || b.isAboveHead(*index) {
 			return 0, errors.New("block not found")
 		}
-		return *index, nil
+		return *index, b.checkHistory(*index)
 	}
 	return 0, errors.New("unknown header selector")
 }
 
 // HeaderByNumber returns evm block header by its number, or nil if not exists.
+// The txs of the block aren't read, unless the txs root of the block isn't stored.
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
-	blk, err := b.BlockByNumber(ctx, number)
-	if err != nil {
-		return nil, err
+	if number == rpc.PendingBlockNumber {
+		number = rpc.LatestBlockNumber
 	}
-	if blk == nil {
+	if number == rpc.LatestBlockNumber {
+		return b.state.GetCompleteHeader(common.Hash{}, uint64(b.latestBlockIndex())), nil
+	}
+	if b.isAboveHead(idx.Block(number)) {
 		return nil, nil
 	}
-	return blk.Header(), err
+	if err := b.checkHistory(idx.Block(number)); err != nil {
+		return nil, err
+	}
+	return b.state.GetCompleteHeader(common.Hash{}, uint64(number.Int64())), nil
 }
 
 // HeaderByHash returns evm block header by its (atropos) hash, or nil if not exists.
 func (b *EthAPIBackend) HeaderByHash(ctx context.Context, h common.Hash) (*evmcore.EvmHeader, error) {
 	index := b.svc.store.GetBlockIndex(hash.Event(h))
-	if index == nil {
+	if index == nil || b.isAboveHead(*index) {
 		return nil, nil
 	}
 	return b.HeaderByNumber(ctx, rpc.BlockNumber(*index))
@@ -101,8 +136,11 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	// Otherwise, resolve and return the block
 	var blk *evmcore.EvmBlock
 	if number == rpc.LatestBlockNumber {
-		blk = b.state.CurrentBlock()
-	} else {
+		blk = b.CurrentBlock()
+	} else if !b.isAboveHead(idx.Block(number)) {
+		if err := b.checkHistory(idx.Block(number)); err != nil {
+			return nil, err
+		}
 		n := uint64(number.Int64())
 		blk = b.state.GetBlock(common.Hash{}, n)
 	}
@@ -119,13 +157,24 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get latest block number; %v", err)
 		}
+		if b.isAboveHead(idx.Block(header.Number.Uint64())) {
+			header = b.state.GetHeader(common.Hash{}, uint64(b.latestBlockIndex()))
+		}
 	} else if number, ok := blockNrOrHash.Number(); ok {
-		header = b.state.GetHeader(common.Hash{}, uint64(number))
+		if err := b.checkHistory(idx.Block(number)); err != nil {
+			return nil, nil, err
+		}
+		if !b.isAboveHead(idx.Block(number)) {
+			header = b.state.GetHeader(common.Hash{}, uint64(number))
+		}
 	} else if h, ok := blockNrOrHash.Hash(); ok {
 		index := b.svc.store.GetBlockIndex(hash.Event(h))
-		if index == nil {
+		if index == nil || b.isAboveHead(*index) {
 			return nil, nil, errors.New("header not found")
 		}
+		if err := b.checkHistory(*index); err != nil {
+			return nil, nil, err
+		}
 		header = b.state.GetHeader(common.Hash{}, uint64(*index))
 	} else {
 		return nil, nil, errors.New("unknown header selector")
@@ -255,13 +304,16 @@ func (b *EthAPIBackend) ForEachEpochEvent(ctx context.Context, epoch rpc.BlockNu
 
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error) {
 	index := b.svc.store.GetBlockIndex(hash.Event(h))
-	if index == nil {
+	if index == nil || b.isAboveHead(*index) {
 		return nil, nil
 	}
 
 	if rpc.BlockNumber(*index) == rpc.PendingBlockNumber {
 		return nil, errors.New("pending block request isn't allowed")
 	}
+	if err := b.checkHistory(*index); err != nil {
+		return nil, err
+	}
 	// Otherwise resolve and return the block
 	var blk *evmcore.EvmBlock
 	if rpc.BlockNumber(*index) == rpc.LatestBlockNumber {
@@ -284,15 +336,103 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 		number = rpc.LatestBlockNumber
 	}
 	if number == rpc.LatestBlockNumber {
-		header := b.state.CurrentHeader()
-		number = rpc.BlockNumber(header.Number.Uint64())
+		number = rpc.BlockNumber(b.latestBlockIndex())
+	} else if b.isAboveHead(idx.Block(number)) {
+		return nil, nil
+	}
+	if err := b.checkHistory(idx.Block(number)); err != nil {
+		return nil, err
 	}
 
 	block := b.state.GetBlock(common.Hash{}, uint64(number))
-	receipts := b.svc.store.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
+	receipts, err := b.svc.store.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
+	if err != nil {
+		return nil, err
+	}
+	if receipts == nil {
+		// the receipts may be found corrupted by the read
+		return nil, b.svc.store.evm.CheckQuarantine(evmstore.ReceiptsTable, idx.Block(number))
+	}
 	return receipts, nil
 }
 
+// BlockAndReceiptsByNumber returns the block and its receipts read from a single snapshot of the DB,
+// so they are consistent with each other even if new blocks are written concurrently.
+func (b *EthAPIBackend) BlockAndReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, types.Receipts, error) {
+	if !b.svc.config.TxIndex {
+		return nil, nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+	}
+	if number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(b.latestBlockIndex())
+	} else if b.isAboveHead(idx.Block(number)) {
+		return nil, nil, nil
+	}
+	if err := b.checkHistory(idx.Block(number)); err != nil {
+		return nil, nil, err
+	}
+
+	// the block number is resolved before the snapshot is taken, so the snapshot has the block
+	snap, err := b.svc.store.Snapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer snap.Release()
+	reader := &EvmStateReader{
+		ServiceFeed: &b.svc.feed,
+		store:       snap.Store,
+	}
+	block := reader.GetBlock(common.Hash{}, uint64(number))
+	if block == nil {
+		return nil, nil, nil
+	}
+	receipts, err := snap.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if receipts == nil {
+		return block, nil, b.svc.store.evm.CheckQuarantine(evmstore.ReceiptsTable, idx.Block(number))
+	}
+	return block, receipts, nil
+}
+
+// GetTxTraces returns transaction traces persisted at import time, or nil if they aren't stored.
+func (b *EthAPIBackend) GetTxTraces(ctx context.Context, block idx.Block, txHash common.Hash) (*[]txtrace.ActionTrace, error) {
+	if !b.svc.config.TxTracesPersist || b.isAboveHead(block) {
+		return nil, nil
+	}
+	if err := b.checkHistory(block); err != nil {
+		return nil, err
+	}
+	// the traces of the blocks processed before the tracing was enabled aren't stored
+	if !b.svc.store.evm.HasTxTraces(block) {
+		return nil, nil
+	}
+	buf, err := b.svc.store.evm.GetTxTraces(block, txHash)
+	if buf == nil || err != nil {
+		return nil, err
+	}
+	traces := make([]txtrace.ActionTrace, 0)
+	if err := json.Unmarshal(buf, &traces); err != nil {
+		return nil, err
+	}
+	return &traces, nil
+}
+
+// GetReceipt returns the receipt of the transaction at the given index of the block,
+// decoding only the requested receipt.
+func (b *EthAPIBackend) GetReceipt(ctx context.Context, block idx.Block, hash common.Hash, index uint64, tx *types.Transaction) (*types.Receipt, error) {
+	if !b.svc.config.TxIndex {
+		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+	}
+	if b.isAboveHead(block) {
+		return nil, nil
+	}
+	if err := b.checkHistory(block); err != nil {
+		return nil, err
+	}
+	return b.svc.store.evm.GetReceipt(block, int(index), b.signer, hash, tx)
+}
+
 // GetReceipts retrieves the receipts for all transactions in a given block.
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, block common.Hash) (types.Receipts, error) {
 	number := b.svc.store.GetBlockIndex(hash.Event(block))
@@ -315,6 +455,45 @@ func (b *EthAPIBackend) GetLogs(ctx context.Context, block common.Hash) ([][]*ty
 	return logs, nil
 }
 
+// GetBlockFees returns the fee stats of the block, or nil if the block isn't found.
+func (b *EthAPIBackend) GetBlockFees(ctx context.Context, number rpc.BlockNumber) (*iblockproc.FeeStats, error) {
+	header, err := b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return b.svc.store.GetBlockFees(idx.Block(header.Number.Uint64())), nil
+}
+
+// GetBlockFeeHistory returns the fee history record of the block, or nil if the block isn't found.
+// The blocks processed without the fee history index are indexed on the fly, as long as their receipts are available.
+func (b *EthAPIBackend) GetBlockFeeHistory(ctx context.Context, number rpc.BlockNumber) (*evmcore.BlockFeeHistory, error) {
+	header, err := b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	n := idx.Block(header.Number.Uint64())
+	if h, err := b.svc.store.evm.GetBlockFeeHistory(n); h != nil || err != nil {
+		return h, err
+	}
+	if !b.svc.config.TxIndex {
+		return nil, nil
+	}
+	block, receipts, err := b.BlockAndReceiptsByNumber(ctx, rpc.BlockNumber(n))
+	if block == nil || err != nil || len(receipts) != len(block.Transactions) {
+		return nil, err
+	}
+	h := evmcore.NewBlockFeeHistory(block.BaseFee, block.GasUsed, b.svc.store.GetRules().Blocks.MaxBlockGas, block.Transactions, receipts)
+	return &h, nil
+}
+
+// GetEpochFees returns the fee stats of the epoch, or nil if the epoch isn't found.
+func (b *EthAPIBackend) GetEpochFees(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.FeeStats, error) {
+	if epoch == rpc.PendingBlockNumber || epoch == rpc.LatestBlockNumber {
+		epoch = rpc.BlockNumber(b.svc.store.GetEpoch())
+	}
+	return b.svc.store.GetEpochFees(idx.Epoch(epoch)), nil
+}
+
 func (b *EthAPIBackend) GetTd(_ common.Hash) *big.Int {
 	return big.NewInt(0)
 }
@@ -325,26 +504,65 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg evmcore.Message, state v
 	if vmConfig == nil {
 		vmConfig = &opera.DefaultVMConfig
 	}
+	// enable the precompiled contracts and the EOF validation of the block epoch rules
+	cfg := *vmConfig
+	rulesCfg := evmcore.VMConfig(b.blockRules(idx.Block(header.Number.Uint64())))
+	cfg.StatePrecompiles = rulesCfg.StatePrecompiles
+	if rulesCfg.InterpreterImpl != opera.DefaultVMConfig.InterpreterImpl {
+		// the precompiles and EOF interpreters are geth interpreters, so they support the tracing
+		cfg.InterpreterImpl = rulesCfg.InterpreterImpl
+	}
 	txContext := evmcore.NewEVMTxContext(msg)
 	context := evmcore.NewEVMBlockContext(header, b.state, nil)
 	config := b.ChainConfig()
-	return vm.NewEVM(context, txContext, state, config, *vmConfig), vmError, nil
+	return vm.NewEVM(context, txContext, state, config, cfg), vmError, nil
+}
+
+// blockRules returns the rules of the block epoch, or the current rules if the block isn't processed yet.
+func (b *EthAPIBackend) blockRules(block idx.Block) opera.Rules {
+	if block <= b.svc.store.GetLatestBlockIndex() {
+		if es := b.svc.store.GetHistoryEpochState(b.svc.store.FindBlockEpoch(block)); es != nil {
+			return es.Rules
+		}
+	}
+	return b.svc.store.GetRules()
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.svc.ReadOnly() {
+		return errReadOnly
+	}
+	if b.svc.denyList != nil {
+		if err := b.svc.denyList.check(signedTx, b.signer); err != nil {
+			return err
+		}
+	}
 	err := b.svc.txpool.AddLocal(signedTx)
 	if err == nil {
 		// NOTE: only sent txs tracing, see TxPool.addTxs() for all
 		tracing.StartTx(signedTx.Hash(), "EthAPIBackend.SendTx()")
+		if b.svc.txRebroadcaster != nil {
+			b.svc.txRebroadcaster.Track(signedTx)
+		}
 	}
 	return err
 }
 
 func (b *EthAPIBackend) SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscription {
+	if b.svc.config.RPCHeadLag != 0 {
+		return b.subscribeLaggedLogs(ch)
+	}
 	return b.svc.feed.SubscribeNewLogs(ch)
 }
 
+func (b *EthAPIBackend) SubscribeRemovedLogsNotify(ch chan<- evmcore.RemovedLogsNotify) notify.Subscription {
+	return b.svc.feed.SubscribeRemovedLogs(ch)
+}
+
 func (b *EthAPIBackend) SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
+	if b.svc.config.RPCHeadLag != 0 {
+		return b.subscribeLaggedBlocks(ch)
+	}
 	return b.svc.feed.SubscribeNewBlock(ch)
 }
 
@@ -368,8 +586,112 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 	return b.svc.txpool.Get(hash)
 }
 
-func (b *EthAPIBackend) GetTxPosition(txHash common.Hash) *evmstore.TxPosition {
-	return b.svc.store.evm.GetTxPosition(txHash)
+func (b *EthAPIBackend) GetPoolTransactionDrop(hash common.Hash) (evmcore.TxDrop, bool) {
+	return b.svc.txpool.Dropped(hash)
+}
+
+// GetContractCreation returns the deployment of the contract, or nil if the contract isn't indexed.
+func (b *EthAPIBackend) GetContractCreation(ctx context.Context, addr common.Address) (*ethapi.ContractCreation, error) {
+	if !b.svc.config.TxIndex {
+		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+	}
+	c, err := b.svc.store.evm.GetContractCreation(addr)
+	if c == nil || err != nil {
+		return nil, err
+	}
+	return &ethapi.ContractCreation{
+		TransactionHash: c.TxHash,
+		Creator:         c.Creator,
+		BlockNumber:     hexutil.Uint64(c.Block),
+	}, nil
+}
+
+// GetCodeHistory returns the deployments and the destructions of the contract.
+func (b *EthAPIBackend) GetCodeHistory(ctx context.Context, addr common.Address) ([]ethapi.CodeHistoryEvent, error) {
+	if !b.svc.config.TxIndex {
+		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+	}
+	events := b.svc.store.evm.GetCodeHistory(addr)
+	res := make([]ethapi.CodeHistoryEvent, len(events))
+	for i, e := range events {
+		typ := "create"
+		if e.Kind == evmstore.CodeDestructed {
+			typ = "selfdestruct"
+		}
+		res[i] = ethapi.CodeHistoryEvent{
+			Type:             typ,
+			BlockNumber:      hexutil.Uint64(e.Block),
+			TransactionIndex: hexutil.Uint(e.TxIndex),
+			TransactionHash:  e.TxHash,
+		}
+	}
+	return res, nil
+}
+
+// GetBalanceChanges returns the balances of the account after the blocks in the range [from, to], which changed it.
+func (b *EthAPIBackend) GetBalanceChanges(ctx context.Context, addr common.Address, from, to idx.Block) ([]ethapi.BalanceAtBlock, error) {
+	if !b.svc.config.TxIndex {
+		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+	}
+	if err := b.checkHistory(from); err != nil {
+		return nil, err
+	}
+	if b.isAboveHead(to) {
+		to = b.latestBlockIndex()
+	}
+	if from <= to {
+		// the journal is recorded only since the transactions index is enabled
+		first, end := b.svc.store.evm.BalanceHistoryRange()
+		if first == end {
+			return nil, errors.New("balance history isn't recorded yet")
+		}
+		if from < first {
+			// the balance at the block before the first recorded one is read from the state
+			return nil, &ethapi.HistoryUnavailableError{First: first - 1}
+		}
+		if to >= end {
+			return nil, fmt.Errorf("balance history isn't recorded yet beyond block %d", end-1)
+		}
+	}
+	changes := b.svc.store.evm.GetBalanceChanges(addr, from, to)
+	res := make([]ethapi.BalanceAtBlock, len(changes))
+	for i, c := range changes {
+		res[i] = ethapi.BalanceAtBlock{
+			BlockNumber: hexutil.Uint64(c.Block),
+			Balance:     (*hexutil.Big)(c.Balance),
+		}
+	}
+	return res, nil
+}
+
+// GetTxsBySender returns up to limit positions of the txs of the sender in the blocks range [from, to].
+func (b *EthAPIBackend) GetTxsBySender(ctx context.Context, sender common.Address, from, to idx.Block, limit int) ([]ethapi.SenderTx, error) {
+	if !b.svc.config.TxIndex {
+		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+	}
+	if err := b.checkHistory(from); err != nil {
+		return nil, err
+	}
+	if b.isAboveHead(to) {
+		to = b.latestBlockIndex()
+	}
+	positions := b.svc.store.evm.GetTxsBySender(sender, from, to, limit)
+	res := make([]ethapi.SenderTx, len(positions))
+	for i, p := range positions {
+		res[i] = ethapi.SenderTx{
+			Block: p.Block,
+			Index: uint64(p.TxIndex),
+		}
+	}
+	return res, nil
+}
+
+func (b *EthAPIBackend) GetTxPosition(txHash common.Hash) (*evmstore.TxPosition, error) {
+	position, err := b.svc.store.evm.GetTxPosition(txHash)
+	if position != nil && b.isAboveHead(position.Block) {
+		return nil, err
+	}
+	return position, err
 }
 
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
@@ -377,14 +699,20 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
 	}
 
-	position := b.svc.store.evm.GetTxPosition(txHash)
-	if position == nil {
+	position, err := b.svc.store.evm.GetTxPosition(txHash)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if position == nil || b.isAboveHead(position.Block) {
 		return nil, 0, 0, nil
 	}
 
 	var tx *types.Transaction
 	if position.Event.IsZero() {
-		tx = b.svc.store.evm.GetTx(txHash)
+		tx, err = b.svc.store.evm.GetTx(txHash)
+		if err != nil {
+			return nil, 0, 0, err
+		}
 	} else {
 		event := b.svc.store.GetEventPayload(position.Event)
 		if position.EventOffset > uint32(event.Txs().Len()) {
@@ -461,14 +789,57 @@ func (b *EthAPIBackend) RPCEVMTimeout() time.Duration {
 	return b.svc.config.RPCEVMTimeout
 }
 
+func (b *EthAPIBackend) RPCEVMMemoryLimit() uint64 {
+	return b.svc.config.RPCEVMMemoryLimit
+}
+
+func (b *EthAPIBackend) RPCReturnDataLimit() uint64 {
+	return b.svc.config.RPCReturnDataLimit
+}
+
+func (b *EthAPIBackend) TraceSandbox() *ethapi.TraceSandbox {
+	return b.svc.traceSandbox
+}
+
 func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.svc.config.RPCTxFeeCap
 }
 
+func (b *EthAPIBackend) RPCAccountsLimit() int {
+	return b.svc.config.RPCAccountsLimit
+}
+
+func (b *EthAPIBackend) RPCBalanceHistoryLimit() int {
+	return b.svc.config.RPCBalanceHistoryLimit
+}
+
+func (b *EthAPIBackend) RPCProofsLimit() int {
+	return b.svc.config.RPCProofsLimit
+}
+
+func (b *EthAPIBackend) RPCMaxStaleBlocks() uint64 {
+	return uint64(b.svc.config.RPCMaxStaleBlocks)
+}
+
 func (b *EthAPIBackend) EvmLogIndex() topicsdb.Index {
 	return b.svc.store.evm.EvmLogs
 }
 
+// BloomStatus returns the section size of the bloombits index and the range [first, end) of its indexed sections.
+func (b *EthAPIBackend) BloomStatus() (sectionSize, first, end uint64) {
+	first, end = b.svc.store.evm.BloomBitsSections()
+	return evmstore.BloomBitsSectionSize, first, end
+}
+
+// HistoryStart returns the first block of the history kept by the node.
+func (b *EthAPIBackend) HistoryStart() idx.Block {
+	return b.svc.store.HistoryStart()
+}
+
+func (b *EthAPIBackend) GetBloomBits(bit uint, section uint64) ([]byte, error) {
+	return b.svc.store.evm.GetBloomBits(bit, section)
+}
+
 // CurrentEpoch returns current epoch number.
 func (b *EthAPIBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
 	return b.svc.store.GetEpoch()
@@ -540,3 +911,11 @@ func (b *EthAPIBackend) SealedEpochTiming(ctx context.Context) (start inter.Time
 	es := b.svc.store.GetEpochState()
 	return es.PrevEpochStart, es.EpochStart
 }
+
+func (b *EthAPIBackend) RollbackTargetEpoch(ctx context.Context, block idx.Block) (idx.Epoch, error) {
+	return b.svc.rollbackTargetEpoch(block)
+}
+
+func (b *EthAPIBackend) ScheduleRollback(ctx context.Context, epoch idx.Epoch) error {
+	return b.svc.scheduleRollback(epoch)
+}
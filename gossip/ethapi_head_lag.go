@@ -0,0 +1,78 @@
+package gossip
+
+import (
+	"context"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	notify "github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// subscribeLagged calls onBlock for every block, which reaches the head served over RPC,
+// once a new block is processed. It's used to lag the subscriptions behind the actual head by RPCHeadLag.
+func (b *EthAPIBackend) subscribeLagged(onBlock func(n idx.Block, quit <-chan struct{}) bool) notify.Subscription {
+	heads := make(chan evmcore.ChainHeadNotify, 16)
+	sub := b.svc.feed.SubscribeNewBlock(heads)
+	next := b.latestBlockIndex() + 1
+	return notify.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-heads:
+				for latest := b.latestBlockIndex(); next <= latest; next++ {
+					if !onBlock(next, quit) {
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// subscribeLaggedBlocks notifies about the blocks, which reach the head served over RPC.
+func (b *EthAPIBackend) subscribeLaggedBlocks(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
+	return b.subscribeLagged(func(n idx.Block, quit <-chan struct{}) bool {
+		block := b.state.GetBlock(common.Hash{}, uint64(n))
+		if block == nil {
+			return true
+		}
+		select {
+		case ch <- evmcore.ChainHeadNotify{Block: block}:
+			return true
+		case <-quit:
+			return false
+		}
+	})
+}
+
+// subscribeLaggedLogs notifies about the logs of the blocks, which reach the head served over RPC.
+func (b *EthAPIBackend) subscribeLaggedLogs(ch chan<- []*types.Log) notify.Subscription {
+	return b.subscribeLagged(func(n idx.Block, quit <-chan struct{}) bool {
+		receipts, err := b.GetReceiptsByNumber(context.Background(), rpc.BlockNumber(n))
+		if err != nil {
+			b.svc.Log.Warn("Failed to get the logs of a lagged block", "block", n, "err", err)
+			return true
+		}
+		var logs []*types.Log
+		for _, r := range receipts {
+			logs = append(logs, r.Logs...)
+		}
+		if len(logs) == 0 {
+			return true
+		}
+		select {
+		case ch <- logs:
+			return true
+		case <-quit:
+			return false
+		}
+	})
+}
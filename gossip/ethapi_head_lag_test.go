@@ -0,0 +1,192 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func newHeadLagTestEnv(t *testing.T, lag idx.Block) *testEnv {
+	cfg := DefaultConfig(cachescale.Identity)
+	cfg.RPCHeadLag = lag
+	return newTestEnvWithConfig(2, 3, cfg, t)
+}
+
+func TestHeadLagExplicitNumbers(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	const lag = 2
+	env := newHeadLagTestEnv(t, lag)
+	defer env.Close()
+
+	var last *big.Int
+	for i := 0; i < 2*lag; i++ {
+		receipts, err := env.ApplyTxs(sameEpoch, env.Transfer(1, 2, big.NewInt(1)))
+		require.NoError(err)
+		last = receipts[0].BlockNumber
+	}
+	ctx := context.Background()
+	head := env.store.GetLatestBlockIndex()
+	lagged := head - lag
+	require.GreaterOrEqual(uint64(head), last.Uint64())
+
+	// the latest block is lagged
+	header, err := env.EthAPI.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	require.NoError(err)
+	require.Equal(uint64(lagged), header.Number.Uint64())
+
+	// the explicit numbers above the lagged head aren't served
+	for n := lagged + 1; n <= head; n++ {
+		header, err := env.EthAPI.HeaderByNumber(ctx, rpc.BlockNumber(n))
+		require.NoError(err)
+		require.Nil(header, n)
+		block, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n))
+		require.NoError(err)
+		require.Nil(block, n)
+		receipts, err := env.EthAPI.GetReceiptsByNumber(ctx, rpc.BlockNumber(n))
+		require.NoError(err)
+		require.Nil(receipts, n)
+		_, err = env.EthAPI.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		require.Error(err, n)
+	}
+
+	// the explicit numbers up to the lagged head are served
+	header, err = env.EthAPI.HeaderByNumber(ctx, rpc.BlockNumber(lagged))
+	require.NoError(err)
+	require.NotNil(header)
+	resolved, err := env.EthAPI.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(lagged)))
+	require.NoError(err)
+	require.Equal(lagged, resolved)
+}
+
+func TestHeadLagSubscription(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	const lag = 2
+	env := newHeadLagTestEnv(t, lag)
+	defer env.Close()
+
+	heads := make(chan evmcore.ChainHeadNotify)
+	sub := env.EthAPI.SubscribeNewBlockNotify(heads)
+	defer sub.Unsubscribe()
+
+	// the heads are received concurrently, as the block processing waits for the subscribers
+	var (
+		mu        sync.Mutex
+		notified  []idx.Block
+		aboveHead []idx.Block
+	)
+	go func() {
+		for {
+			select {
+			case head := <-heads:
+				n := idx.Block(head.Block.Number.Uint64())
+				mu.Lock()
+				notified = append(notified, n)
+				if n+lag > env.store.GetLatestBlockIndex() {
+					aboveHead = append(aboveHead, n)
+				}
+				mu.Unlock()
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	first := env.EthAPI.latestBlockIndex() + 1
+	for i := 0; i < 2*lag; i++ {
+		_, err := env.ApplyTxs(sameEpoch, env.Transfer(1, 2, big.NewInt(1)))
+		require.NoError(err)
+	}
+	lagged := env.store.GetLatestBlockIndex() - lag
+	require.Greater(lagged, first)
+
+	// the blocks are notified in order, once they reach the lagged head
+	var expect []idx.Block
+	for n := first; n <= lagged; n++ {
+		if env.EthAPI.state.GetBlock(common.Hash{}, uint64(n)) == nil {
+			// the blocks before the genesis head don't exist
+			continue
+		}
+		expect = append(expect, n)
+	}
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(notified) >= len(expect)
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(expect, notified[:len(expect)])
+	require.Empty(aboveHead)
+}
+
+func TestHeadLagLogsSubscription(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	const lag = 2
+	env := newHeadLagTestEnv(t, lag)
+	defer env.Close()
+
+	// the contract emits an empty log on every call
+	receipts, err := env.ApplyTxs(sameEpoch, env.Contract(1, new(big.Int), "0x6006600c60003960066000f360006000a000"))
+	require.NoError(err)
+	contract := receipts[0].ContractAddress
+	call := func() *types.Transaction {
+		sender := env.Address(1)
+		nonce, _ := env.PendingNonceAt(nil, sender)
+		env.incNonce(sender)
+		tx, err := types.SignTx(types.NewTransaction(nonce, contract, new(big.Int), 2*gasLimit, big.NewInt(1e12), nil), env.EthAPI.signer, env.privateKey(1))
+		require.NoError(err)
+		return tx
+	}
+	// the genesis blocks with the logs of the system contracts reach the lagged head before the subscription
+	for i := 0; i < lag; i++ {
+		_, err := env.ApplyTxs(sameEpoch, env.Transfer(1, 2, big.NewInt(1)))
+		require.NoError(err)
+	}
+
+	logs := make(chan []*types.Log, 100)
+	sub := env.EthAPI.SubscribeLogsNotify(logs)
+	defer sub.Unsubscribe()
+
+	var called []*types.Receipt
+	for i := 0; i < 2*lag; i++ {
+		receipts, err := env.ApplyTxs(sameEpoch, call())
+		require.NoError(err)
+		require.Len(receipts[0].Logs, 1)
+		called = append(called, receipts[0])
+	}
+	lagged := env.store.GetLatestBlockIndex() - lag
+
+	// the logs are notified once their blocks reach the lagged head
+	for _, r := range called {
+		if idx.Block(r.BlockNumber.Uint64()) > lagged {
+			break
+		}
+		select {
+		case notified := <-logs:
+			require.Len(notified, 1)
+			require.Equal(contract, notified[0].Address)
+			require.Equal(r.TxHash, notified[0].TxHash)
+			require.LessOrEqual(idx.Block(notified[0].BlockNumber)+lag, env.store.GetLatestBlockIndex())
+		case <-time.After(5 * time.Second):
+			require.FailNow("logs aren't notified", r.BlockNumber)
+		}
+	}
+}
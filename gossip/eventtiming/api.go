@@ -0,0 +1,32 @@
+package eventtiming
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// TimingStats are the latency distributions of the events processing, aggregated per event creator and per peer.
+type TimingStats struct {
+	Validators map[idx.ValidatorID]Stats `json:"validators"`
+	Peers      map[string]Stats          `json:"peers"`
+}
+
+// PublicAPI exposes the events timing statistics.
+type PublicAPI struct {
+	t *Tracker
+}
+
+// NewPublicAPI creates a new events timing API.
+func NewPublicAPI(t *Tracker) *PublicAPI {
+	return &PublicAPI{t}
+}
+
+// EventTimings returns the latencies of the events received from peers, from the arrival till the execution:
+// validation (received -> validated), ordering (validated -> confirmed by consensus),
+// execution (confirmed -> executed in a block) and total.
+func (api *PublicAPI) EventTimings() *TimingStats {
+	validators, peers := api.t.Stats()
+	return &TimingStats{
+		Validators: validators,
+		Peers:      peers,
+	}
+}
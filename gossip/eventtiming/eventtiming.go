@@ -0,0 +1,242 @@
+package eventtiming
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+)
+
+const (
+	// maxPending is the number of the received events awaiting the execution which are tracked
+	maxPending = 10000
+	// maxValidators and maxPeers limit the number of the tracked sources, the least recently updated ones are dropped
+	maxValidators = 1000
+	maxPeers      = 256
+	// samplesNum is the number of the latest measurements kept per a latency
+	samplesNum = 512
+)
+
+type record struct {
+	creator idx.ValidatorID
+	peer    string
+
+	received  time.Time
+	validated time.Time
+	ordered   time.Time
+}
+
+// window keeps the latest measurements of a latency.
+type window struct {
+	samples []time.Duration
+	next    int
+	count   uint64
+}
+
+func (w *window) add(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	if len(w.samples) < samplesNum {
+		w.samples = append(w.samples, d)
+	} else {
+		w.samples[w.next] = d
+		w.next = (w.next + 1) % samplesNum
+	}
+	w.count++
+}
+
+func (w *window) distribution() Distribution {
+	if len(w.samples) == 0 {
+		return Distribution{}
+	}
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	percentile := func(p int) float64 {
+		return ms(sorted[(len(sorted)-1)*p/100])
+	}
+	return Distribution{
+		Count: w.count,
+		Mean:  ms(sum / time.Duration(len(sorted))),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   ms(sorted[len(sorted)-1]),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Distribution summarizes the latest measurements of a latency, in milliseconds.
+type Distribution struct {
+	Count uint64  `json:"count"` // total number of measurements, including the ones dropped from the window
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+type stages struct {
+	validation window
+	ordering   window
+	execution  window
+	total      window
+}
+
+func (s *stages) stats() Stats {
+	return Stats{
+		Validation: s.validation.distribution(),
+		Ordering:   s.ordering.distribution(),
+		Execution:  s.execution.distribution(),
+		Total:      s.total.distribution(),
+	}
+}
+
+// Stats are the latency distributions of the events processing stages.
+type Stats struct {
+	Validation Distribution `json:"validation"` // received -> validated
+	Ordering   Distribution `json:"ordering"`   // validated -> ordered by consensus
+	Execution  Distribution `json:"execution"`  // ordered -> executed in a block
+	Total      Distribution `json:"total"`      // received -> executed
+}
+
+// Tracker collects the timings of the events received from peers, from the arrival till the execution,
+// and aggregates the latencies per event creator and per peer which delivered the event.
+// The self-emitted and the not received events aren't tracked.
+// All methods are safe for concurrent use, and a nil Tracker ignores all calls.
+type Tracker struct {
+	mu         sync.Mutex
+	pending    *lru.Cache[hash.Event, *record]
+	validators *lru.Cache[idx.ValidatorID, *stages]
+	peers      *lru.Cache[string, *stages]
+}
+
+// New creates a new Tracker.
+func New() *Tracker {
+	return &Tracker{
+		pending:    lru.New[hash.Event, *record](maxPending, maxPending, nil),
+		validators: lru.New[idx.ValidatorID, *stages](maxValidators, maxValidators, nil),
+		peers:      lru.New[string, *stages](maxPeers, maxPeers, nil),
+	}
+}
+
+func (t *Tracker) stagesOf(r *record) (validator, peer *stages) {
+	validator, ok := t.validators.Get(r.creator)
+	if !ok {
+		validator = new(stages)
+		t.validators.Add(r.creator, validator)
+	}
+	peer, ok = t.peers.Get(r.peer)
+	if !ok {
+		peer = new(stages)
+		t.peers.Add(r.peer, peer)
+	}
+	return validator, peer
+}
+
+// Received records the arrival of the event from the peer. Only the first arrival is taken into account.
+func (t *Tracker) Received(id hash.Event, creator idx.ValidatorID, peer string, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending.Contains(id) {
+		return
+	}
+	t.pending.Add(id, &record{
+		creator:  creator,
+		peer:     peer,
+		received: at,
+	})
+}
+
+// Validated records that the event passed the checks and is being connected into the DAG.
+func (t *Tracker) Validated(id hash.Event, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.pending.Peek(id)
+	if !ok || !r.validated.IsZero() {
+		return
+	}
+	r.validated = at
+	validator, peer := t.stagesOf(r)
+	validator.validation.add(at.Sub(r.received))
+	peer.validation.add(at.Sub(r.received))
+}
+
+// Ordered records that the event is confirmed by consensus.
+func (t *Tracker) Ordered(id hash.Event, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.pending.Peek(id)
+	if !ok || r.validated.IsZero() || !r.ordered.IsZero() {
+		return
+	}
+	r.ordered = at
+	validator, peer := t.stagesOf(r)
+	validator.ordering.add(at.Sub(r.validated))
+	peer.ordering.add(at.Sub(r.validated))
+}
+
+// Executed records that the block, which includes the ordered events, is executed.
+// The events are not tracked afterwards.
+func (t *Tracker) Executed(ids []hash.Event, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range ids {
+		r, ok := t.pending.Peek(id)
+		if !ok || r.ordered.IsZero() {
+			continue
+		}
+		t.pending.Remove(id)
+		validator, peer := t.stagesOf(r)
+		validator.execution.add(at.Sub(r.ordered))
+		peer.execution.add(at.Sub(r.ordered))
+		validator.total.add(at.Sub(r.received))
+		peer.total.add(at.Sub(r.received))
+	}
+}
+
+// Stats returns the latency distributions per event creator and per peer.
+func (t *Tracker) Stats() (validators map[idx.ValidatorID]Stats, peers map[string]Stats) {
+	validators = make(map[idx.ValidatorID]Stats)
+	peers = make(map[string]Stats)
+	if t == nil {
+		return validators, peers
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range t.validators.Keys() {
+		s, _ := t.validators.Peek(id)
+		validators[id] = s.stats()
+	}
+	for _, id := range t.peers.Keys() {
+		s, _ := t.peers.Peek(id)
+		peers[id] = s.stats()
+	}
+	return validators, peers
+}
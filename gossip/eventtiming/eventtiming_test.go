@@ -0,0 +1,70 @@
+package eventtiming
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+
+	tracker := New()
+	start := time.Now()
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	a, b, unknown := hash.Event{1}, hash.Event{2}, hash.Event{3}
+	tracker.Received(a, 1, "peer1", at(0))
+	tracker.Received(a, 1, "peer2", at(5)) // only the first arrival counts
+	tracker.Received(b, 2, "peer2", at(0))
+
+	tracker.Validated(a, at(10))
+	tracker.Validated(b, at(20))
+	tracker.Validated(unknown, at(20))
+	tracker.Ordered(a, at(110))
+	tracker.Ordered(b, at(220))
+	tracker.Executed([]hash.Event{a, b, unknown}, at(310))
+	// the executed events are not tracked anymore
+	tracker.Executed([]hash.Event{a}, at(1000))
+
+	validators, peers := tracker.Stats()
+	require.Len(validators, 2)
+	require.Len(peers, 2)
+	require.Equal(Stats{
+		Validation: Distribution{Count: 1, Mean: 10, P50: 10, P90: 10, P99: 10, Max: 10},
+		Ordering:   Distribution{Count: 1, Mean: 100, P50: 100, P90: 100, P99: 100, Max: 100},
+		Execution:  Distribution{Count: 1, Mean: 200, P50: 200, P90: 200, P99: 200, Max: 200},
+		Total:      Distribution{Count: 1, Mean: 310, P50: 310, P90: 310, P99: 310, Max: 310},
+	}, validators[idx.ValidatorID(1)])
+	require.Equal(validators[1], peers["peer1"])
+	require.Equal(validators[2], peers["peer2"])
+	require.Equal(float64(200), validators[2].Ordering.Max)
+	require.Equal(float64(90), validators[2].Execution.Max)
+
+	// nil tracker is a no-op
+	var disabled *Tracker
+	disabled.Received(a, 1, "peer1", at(0))
+	validators, peers = disabled.Stats()
+	require.Empty(validators)
+	require.Empty(peers)
+}
+
+func TestWindowDistribution(t *testing.T) {
+	require := require.New(t)
+
+	var w window
+	for i := 1; i <= samplesNum+100; i++ {
+		w.add(time.Duration(i) * time.Millisecond)
+	}
+	d := w.distribution()
+	require.Equal(uint64(samplesNum+100), d.Count)
+	// the oldest measurements are dropped
+	require.Equal(float64(samplesNum+100), d.Max)
+	require.Equal(float64(101+(samplesNum-1)*50/100), d.P50)
+	require.Equal(float64(101+(samplesNum-1)*99/100), d.P99)
+}
@@ -1,9 +1,11 @@
 package gossip
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
@@ -58,6 +60,16 @@ func (r *EvmStateReader) MaxGasLimit() uint64 {
 	return rules.Economy.Gas.MaxEventGas - maxEmptyEventGas
 }
 
+// MaxInitCodeSize returns current limit of the init code size of creation transactions
+func (r *EvmStateReader) MaxInitCodeSize() uint64 {
+	return r.store.GetRules().Contracts.MaxInitCodeSize
+}
+
+// EofEnabled returns true if EOF contracts are validated on deployment
+func (r *EvmStateReader) EofEnabled() bool {
+	return r.store.GetRules().Upgrades.Eof
+}
+
 func (r *EvmStateReader) Config() *params.ChainConfig {
 	return r.store.GetEvmChainConfig()
 }
@@ -79,6 +91,10 @@ func (r *EvmStateReader) LastHeaderWithArchiveState() (*evmcore.EvmHeader, error
 
 	// make sure the block is present in the archive
 	latestArchiveBlock, empty, err := r.store.evm.GetArchiveBlockHeight()
+	if errors.Is(err, carmen.NoArchiveError) {
+		// only the live state is available, it's the state of the latest block
+		return r.getBlock(hash.Event{}, latestBlock, false).Header(), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest archive block; %v", err)
 	}
@@ -93,11 +109,31 @@ func (r *EvmStateReader) GetHeader(h common.Hash, n uint64) *evmcore.EvmHeader {
 	return r.getBlock(hash.Event(h), idx.Block(n), false).Header()
 }
 
+// GetCompleteHeader returns the header of the block including the txs root.
+// The txs of the block are read only if the txs root of the block isn't stored.
+func (r *EvmStateReader) GetCompleteHeader(h common.Hash, n uint64) *evmcore.EvmHeader {
+	header := r.GetHeader(h, n)
+	if header == nil || header.TxHash != (common.Hash{}) {
+		return header
+	}
+	return r.GetBlock(h, n).Header()
+}
+
 func (r *EvmStateReader) GetBlock(h common.Hash, n uint64) *evmcore.EvmBlock {
 	return r.getBlock(hash.Event(h), idx.Block(n), true)
 }
 
 func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmcore.EvmBlock {
+	if readTxs {
+		if cached := r.store.EvmStore().GetCachedEvmBlock(n); cached != nil {
+			return checkBlockHash(h, cached)
+		}
+	} else if cached := r.store.EvmStore().GetCachedEvmHeader(n); cached != nil {
+		return checkBlockHash(h, &evmcore.EvmBlock{
+			EvmHeader: *cached,
+		})
+	}
+
 	block := r.store.GetBlock(n)
 	if block == nil {
 		return nil
@@ -105,11 +141,6 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 	if (h != hash.Event{}) && (h != block.Atropos) {
 		return nil
 	}
-	if readTxs {
-		if cached := r.store.EvmStore().GetCachedEvmBlock(n); cached != nil {
-			return cached
-		}
-	}
 
 	var transactions types.Transactions
 	if readTxs {
@@ -139,7 +170,11 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 		evmBlock = evmcore.NewEvmBlock(evmHeader, transactions)
 		r.store.EvmStore().SetCachedEvmBlock(n, evmBlock)
 	} else {
-		// not completed block here
+		// the header is complete only if the txs root is stored
+		if root, ok := r.store.EvmStore().GetTxRoot(n); ok {
+			evmHeader.TxHash = root
+			r.store.EvmStore().SetCachedEvmHeader(n, evmHeader)
+		}
 		evmBlock = &evmcore.EvmBlock{
 			EvmHeader: *evmHeader,
 		}
@@ -148,6 +183,14 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 	return evmBlock
 }
 
+// checkBlockHash returns the block if its hash matches the requested one, the empty hash matches any block.
+func checkBlockHash(h hash.Event, block *evmcore.EvmBlock) *evmcore.EvmBlock {
+	if (h != hash.Event{}) && (h != hash.Event(block.Hash)) {
+		return nil
+	}
+	return block
+}
+
 // GetTxPoolStateDB obtains StateDB for TxPool
 func (r *EvmStateReader) GetTxPoolStateDB() (evmcore.TxPoolStateDB, error) {
 	return r.store.evm.GetTxPoolStateDB()
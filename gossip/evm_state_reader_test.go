@@ -0,0 +1,37 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils"
+)
+
+func TestEvmStateReaderCompleteHeader(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 3, t)
+	defer env.Close()
+
+	_, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+	require.NoError(err)
+
+	reader := env.GetEvmStateReader()
+	latest := uint64(env.store.GetLatestBlockIndex())
+	block := reader.GetBlock(common.Hash{}, latest)
+	require.NotNil(block)
+	require.NotEmpty(block.Transactions)
+
+	// the txs root is stored along with the block, so the header is complete without the txs
+	root, ok := env.store.EvmStore().GetTxRoot(env.store.GetLatestBlockIndex())
+	require.True(ok)
+	require.Equal(block.TxHash, root)
+	require.Equal(block.Header(), reader.GetCompleteHeader(common.Hash{}, latest))
+	require.Equal(block.Header(), reader.GetCompleteHeader(block.Hash, latest))
+	require.Nil(reader.GetCompleteHeader(common.Hash{1}, latest))
+	require.Nil(reader.GetCompleteHeader(common.Hash{}, latest+1))
+}
@@ -0,0 +1,81 @@
+package evmstore
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// balanceHistoryStateDB records the balances changed by blocks processing, including the internal transfers.
+// The balance before the first touch of the account in the block is kept, so the accounts touched
+// without a net change (e.g. a reverted transfer or a zero value call) aren't recorded.
+type balanceHistoryStateDB struct {
+	state.StateDB
+	store *Store
+
+	block  idx.Block
+	before map[common.Address]*big.Int
+}
+
+// WrapBalanceHistory returns StateDB, which records the changed balances.
+// The changes are flushed on Commit.
+func (s *Store) WrapBalanceHistory(db state.StateDB) state.StateDB {
+	return &balanceHistoryStateDB{
+		StateDB: db,
+		store:   s,
+		before:  make(map[common.Address]*big.Int),
+	}
+}
+
+func (b *balanceHistoryStateDB) BeginBlock(number uint64) {
+	b.block = idx.Block(number)
+	b.StateDB.BeginBlock(number)
+}
+
+func (b *balanceHistoryStateDB) touch(addr common.Address) {
+	if _, ok := b.before[addr]; !ok {
+		b.before[addr] = b.StateDB.GetBalance(addr)
+	}
+}
+
+func (b *balanceHistoryStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	b.touch(addr)
+	b.StateDB.AddBalance(addr, amount)
+}
+
+func (b *balanceHistoryStateDB) SubBalance(addr common.Address, amount *big.Int) {
+	b.touch(addr)
+	b.StateDB.SubBalance(addr, amount)
+}
+
+func (b *balanceHistoryStateDB) SetBalance(addr common.Address, amount *big.Int) {
+	b.touch(addr)
+	b.StateDB.SetBalance(addr, amount)
+}
+
+func (b *balanceHistoryStateDB) Suicide(addr common.Address) bool {
+	b.touch(addr)
+	return b.StateDB.Suicide(addr)
+}
+
+func (b *balanceHistoryStateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
+	after := make(map[common.Address]*big.Int, len(b.before))
+	for addr := range b.before {
+		after[addr] = b.StateDB.GetBalance(addr)
+	}
+	root, err := b.StateDB.Commit(deleteEmptyObjects)
+	if err != nil {
+		return root, err
+	}
+	for addr, balance := range after {
+		if balance.Cmp(b.before[addr]) != 0 {
+			b.store.SetBalanceChange(addr, b.block, balance)
+		}
+	}
+	b.store.setBalanceHistoryBlock(b.block)
+	b.before = make(map[common.Address]*big.Int)
+	return root, nil
+}
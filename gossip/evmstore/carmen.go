@@ -4,14 +4,16 @@ import (
 	"errors"
 	cc "github.com/Fantom-foundation/Carmen/go/common"
 	"github.com/Fantom-foundation/Carmen/go/common/amount"
+	"github.com/Fantom-foundation/Carmen/go/common/witness"
 	carmen "github.com/Fantom-foundation/Carmen/go/state"
 	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/ethereum/go-ethereum/common"
-	ethstate "github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
 )
 
+var errProofsUnsupported = errors.New("proofs are supported by the archive state only")
+
 func CreateCarmenStateDb(carmenStateDb carmen.VmStateDB) state.StateDB {
 	return &CarmenStateDB{
 		db: carmenStateDb,
@@ -120,20 +122,66 @@ func (c *CarmenStateDB) GetState(addr common.Address, hash common.Hash) common.H
 	return common.Hash(c.db.GetState(cc.Address(addr), cc.Key(hash)))
 }
 
+// witnessProof creates the witness proof of the account and the storage keys, and returns it along with the state root.
+// The proofs are created by the archive states only.
+func (c *CarmenStateDB) witnessProof(addr common.Address, keys ...common.Hash) (_ witness.Proof, _ cc.Hash, err error) {
+	db, ok := c.db.(carmen.NonCommittableStateDB)
+	if !ok {
+		return nil, cc.Hash{}, errProofsUnsupported
+	}
+	defer func() {
+		// the live state panics as it doesn't implement the proofs
+		if r := recover(); r != nil {
+			err = errProofsUnsupported
+		}
+	}()
+	carmenKeys := make([]cc.Key, len(keys))
+	for i, key := range keys {
+		carmenKeys[i] = cc.Key(key)
+	}
+	proof, err := db.CreateWitnessProof(cc.Address(addr), carmenKeys...)
+	if err != nil {
+		return nil, cc.Hash{}, err
+	}
+	return proof, c.db.GetHash(), nil
+}
+
 func (c *CarmenStateDB) GetProof(addr common.Address) ([][]byte, error) {
-	panic("not supported")
+	proof, root, err := c.witnessProof(addr)
+	if err != nil {
+		return nil, err
+	}
+	return proofPath(proof.GetElements(), common.Hash(root), addr[:])
 }
 
-func (c *CarmenStateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte, error) {
-	panic("not supported")
+func (c *CarmenStateDB) GetStorageRoot(addr common.Address) (common.Hash, error) {
+	proof, root, err := c.witnessProof(addr)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	_, storageRoot, _ := proof.GetStorageElements(root, cc.Address(addr))
+	if storageRoot == (cc.Hash{}) {
+		// the account doesn't exist
+		return types.EmptyRootHash, nil
+	}
+	return common.Hash(storageRoot), nil
 }
 
-func (c *CarmenStateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
-	return common.Hash(c.db.GetCommittedState(cc.Address(addr), cc.Key(hash)))
+func (c *CarmenStateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	proof, root, err := c.witnessProof(addr, key)
+	if err != nil {
+		return nil, err
+	}
+	elements, storageRoot, _ := proof.GetStorageElements(root, cc.Address(addr), cc.Key(key))
+	if storageRoot == (cc.Hash{}) {
+		// the account doesn't exist
+		return [][]byte{}, nil
+	}
+	return proofPath(elements, common.Hash(storageRoot), key[:])
 }
 
-func (c *CarmenStateDB) StorageTrie(addr common.Address) ethstate.Trie {
-	panic("not supported")
+func (c *CarmenStateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
+	return common.Hash(c.db.GetCommittedState(cc.Address(addr), cc.Key(hash)))
 }
 
 func (c *CarmenStateDB) HasSuicided(addr common.Address) bool {
@@ -166,8 +214,15 @@ func (c *CarmenStateDB) SubBalance(addr common.Address, amountInt *big.Int) {
 	c.db.SubBalance(cc.Address(addr), am)
 }
 
+// SetBalance sets the balance of the account by adding or subtracting the difference from the current one.
 func (c *CarmenStateDB) SetBalance(addr common.Address, amount *big.Int) {
-	panic("not supported")
+	diff := new(big.Int).Sub(amount, c.GetBalance(addr))
+	switch diff.Sign() {
+	case 1:
+		c.AddBalance(addr, diff)
+	case -1:
+		c.SubBalance(addr, diff.Neg(diff))
+	}
 }
 
 func (c *CarmenStateDB) SetNonce(addr common.Address, nonce uint64) {
@@ -182,8 +237,16 @@ func (c *CarmenStateDB) SetState(addr common.Address, key, value common.Hash) {
 	c.db.SetState(cc.Address(addr), cc.Key(key), cc.Value(value))
 }
 
+// SetStorage replaces the whole storage of the account.
+// The storage is cleared by the re-creation of the account, which keeps its balance, the nonce and the code are restored.
 func (c *CarmenStateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
-	panic("not supported")
+	nonce, code := c.GetNonce(addr), c.GetCode(addr)
+	c.db.CreateAccount(cc.Address(addr))
+	c.db.SetNonce(cc.Address(addr), nonce)
+	c.db.SetCode(cc.Address(addr), code)
+	for key, value := range storage {
+		c.SetState(addr, key, value)
+	}
 }
 
 func (c *CarmenStateDB) Suicide(addr common.Address) bool {
@@ -0,0 +1,148 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+type codeEvent struct {
+	addr    common.Address
+	creator common.Address
+	kind    CodeEventKind
+	txHash  common.Hash
+	txIndex uint32
+}
+
+// codeHistoryStateDB records contracts deployed and destructed by blocks processing, including the internal ones.
+// The EVM increments the creator's nonce right before creating the contract account, and sets the contract code
+// once the init code succeeds. Events reverted by the EVM are dropped.
+type codeHistoryStateDB struct {
+	state.StateDB
+	store *Store
+
+	block   idx.Block
+	txHash  common.Hash
+	txIndex uint32
+
+	nonceSet  *common.Address
+	pending   map[common.Address]common.Address
+	events    []codeEvent
+	snapshots map[int]int
+}
+
+// WrapCodeHistory returns StateDB, which records the deployed and destructed contracts.
+// The events are flushed on Commit.
+func (s *Store) WrapCodeHistory(db state.StateDB) state.StateDB {
+	return &codeHistoryStateDB{
+		StateDB:   db,
+		store:     s,
+		pending:   make(map[common.Address]common.Address),
+		snapshots: make(map[int]int),
+	}
+}
+
+func (c *codeHistoryStateDB) BeginBlock(number uint64) {
+	c.block = idx.Block(number)
+	c.StateDB.BeginBlock(number)
+}
+
+func (c *codeHistoryStateDB) Prepare(txHash common.Hash, txIndex int) {
+	c.txHash = txHash
+	c.txIndex = uint32(txIndex)
+	c.nonceSet = nil
+	c.pending = make(map[common.Address]common.Address)
+	c.StateDB.Prepare(txHash, txIndex)
+}
+
+func (c *codeHistoryStateDB) record(kind CodeEventKind, addr, creator common.Address) {
+	c.events = append(c.events, codeEvent{
+		addr:    addr,
+		creator: creator,
+		kind:    kind,
+		txHash:  c.txHash,
+		txIndex: c.txIndex,
+	})
+}
+
+func (c *codeHistoryStateDB) SetNonce(addr common.Address, nonce uint64) {
+	c.nonceSet = &addr
+	c.StateDB.SetNonce(addr, nonce)
+}
+
+func (c *codeHistoryStateDB) CreateAccount(addr common.Address) {
+	if c.nonceSet != nil {
+		c.pending[addr] = *c.nonceSet
+		c.nonceSet = nil
+	}
+	c.StateDB.CreateAccount(addr)
+}
+
+func (c *codeHistoryStateDB) SetCode(addr common.Address, code []byte) {
+	if creator, ok := c.pending[addr]; ok {
+		c.record(CodeCreated, addr, creator)
+		delete(c.pending, addr)
+	}
+	c.StateDB.SetCode(addr, code)
+}
+
+func (c *codeHistoryStateDB) Suicide(addr common.Address) bool {
+	c.record(CodeDestructed, addr, common.Address{})
+	return c.StateDB.Suicide(addr)
+}
+
+func (c *codeHistoryStateDB) Snapshot() int {
+	id := c.StateDB.Snapshot()
+	c.snapshots[id] = len(c.events)
+	return id
+}
+
+func (c *codeHistoryStateDB) RevertToSnapshot(id int) {
+	if n, ok := c.snapshots[id]; ok && n < len(c.events) {
+		c.events = c.events[:n]
+	}
+	c.StateDB.RevertToSnapshot(id)
+}
+
+// destructed returns true if the i-th event destructed the contract.
+// Pre-Cancun SELFDESTRUCT always removes the account, while since EIP-6780 it only transfers the balance
+// unless the contract is created by the same transaction. So the destruction is confirmed by the account
+// being removed after the block or re-created by a later transaction of the block.
+func (c *codeHistoryStateDB) destructed(i int) bool {
+	e := c.events[i]
+	for _, later := range c.events[i+1:] {
+		if later.addr == e.addr && later.kind == CodeCreated && later.txIndex != e.txIndex {
+			return true
+		}
+	}
+	return !c.StateDB.Exist(e.addr)
+}
+
+func (c *codeHistoryStateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
+	root, err := c.StateDB.Commit(deleteEmptyObjects)
+	if err != nil {
+		return root, err
+	}
+	for i, e := range c.events {
+		if e.kind == CodeDestructed && !c.destructed(i) {
+			continue
+		}
+		c.store.SetCodeEvent(e.addr, CodeEvent{
+			Kind:    e.kind,
+			Block:   c.block,
+			TxIndex: e.txIndex,
+			TxHash:  e.txHash,
+		})
+		if e.kind == CodeCreated {
+			c.store.SetContractCreation(e.addr, ContractCreation{
+				TxHash:  e.txHash,
+				Creator: e.creator,
+				Block:   c.block,
+			})
+		}
+	}
+	c.events = nil
+	c.snapshots = make(map[int]int)
+	return root, nil
+}
@@ -1,9 +1,15 @@
 package evmstore
 
 import (
+	"errors"
+
 	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/Fantom-foundation/go-opera/topicsdb"
 )
 
 type (
@@ -15,10 +21,12 @@ type (
 		ReceiptsBlocks int
 		// Cache size for TxPositions.
 		TxPositions int
-		// Cache size for EvmBlock (number of blocks).
+		// Cache size for EvmBlock bodies (number of blocks).
 		EvmBlocksNum int
-		// Cache size for EvmBlock (size in bytes).
+		// Cache size for EvmBlock bodies (size in bytes).
 		EvmBlocksSize uint
+		// Cache size for EvmHeader (number of blocks).
+		EvmHeadersNum int
 	}
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
@@ -31,9 +39,56 @@ type (
 		DisableLogsIndexing bool
 		// Disables storing of txs positions
 		DisableTxHashesIndexing bool
+		// ArchivedContracts enables the selective archive: the historical storage is kept
+		// only for the listed contracts, while the rest of the state isn't archived
+		ArchivedContracts []common.Address
+		// ReceiptsRetentionEpochs is a number of recent epochs whose receipts, txs positions
+		// and logs index are kept, the older ones are pruned in background. Zero keeps all.
+		ReceiptsRetentionEpochs idx.Epoch
+		// RemoteLogsIndex is an RPC endpoint of an out-of-process log indexer, which serves
+		// the logs queries instead of the local index. Empty keeps the index in the node.
+		RemoteLogsIndex string
+		// CacheSizeMB is a memory budget of the receipts and txs positions caches in megabytes.
+		// If set, it replaces the ReceiptsSize, ReceiptsBlocks and TxPositions limits of the cache config,
+		// which are kept for the existing config files. Zero keeps the old limits.
+		CacheSizeMB uint
+		// BloomBitsIndexing enables the bloombits index of the blocks, which is used by
+		// the logs queries over the wide blocks ranges in addition to the logs index.
+		// Only the blocks processed since the indexing is enabled are indexed
+		BloomBitsIndexing bool
+		// LogsIndexParallelism is the number of the topic positions and the address scanned concurrently
+		// by a logs query filtering by several of them, 1 scans them sequentially
+		LogsIndexParallelism int
+		// StateExpiryEpochs enables the state expiry experiment: the accounts and storage slots untouched
+		// for the number of epochs are counted as expired. The expired state stays in the StateDB.
+		// Zero disables the experiment
+		StateExpiryEpochs idx.Epoch
 	}
 )
 
+// Validate checks the config.
+func (c StoreConfig) Validate() error {
+	if len(c.ArchivedContracts) != 0 && c.StateDb.Archive != carmen.NoArchive {
+		return errors.New("the selective archive of contracts requires the full archive to be disabled")
+	}
+	return nil
+}
+
+// apiCacheConfig returns the cache config, in which the receipts and txs positions limits
+// are derived from CacheSizeMB if it's set. The receipts take 3/5 of the budget.
+func (c StoreConfig) apiCacheConfig() StoreCacheConfig {
+	cache := c.Cache
+	if c.CacheSizeMB == 0 {
+		return cache
+	}
+	budget := c.CacheSizeMB * opt.MiB
+	cache.ReceiptsSize = budget / 5 * 3
+	// the number of blocks is limited only to not keep too many blocks with no receipts
+	cache.ReceiptsBlocks = int(cache.ReceiptsSize / receiptMemSize)
+	cache.TxPositions = int((budget - cache.ReceiptsSize) / txPositionSize)
+	return cache
+}
+
 // DefaultStoreConfig for product.
 func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 	return StoreConfig{
@@ -43,6 +98,7 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			TxPositions:       scale.I(20000),
 			EvmBlocksNum:      scale.I(5000),
 			EvmBlocksSize:     scale.U(6 * opt.MiB),
+			EvmHeadersNum:     scale.I(10000),
 		},
 		StateDb: carmen.Parameters{
 			Variant:      "go-file",
@@ -51,6 +107,7 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			LiveCache:    scale.I64(1940 * opt.MiB),
 			ArchiveCache: scale.I64(1940 * opt.MiB),
 		},
+		LogsIndexParallelism: topicsdb.DefaultConfig().Parallelism,
 	}
 }
 
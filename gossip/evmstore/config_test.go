@@ -0,0 +1,38 @@
+package evmstore
+
+import (
+	"testing"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+func TestApiCacheConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	// the old limits are kept unless the budget is set
+	require.Equal(cfg.Cache, cfg.apiCacheConfig())
+
+	cfg.CacheSizeMB = 100
+	cache := cfg.apiCacheConfig()
+	require.Equal(uint(60*opt.MiB), cache.ReceiptsSize)
+	require.Equal(int(60*opt.MiB/receiptMemSize), cache.ReceiptsBlocks)
+	require.Equal(int(40*opt.MiB/txPositionSize), cache.TxPositions)
+	require.Equal(cfg.Cache.EvmBlocksSize, cache.EvmBlocksSize)
+}
+
+func TestStoreConfigValidate(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	require.NoError(cfg.Validate())
+
+	cfg.ArchivedContracts = []common.Address{{1}}
+	require.Error(cfg.Validate())
+
+	cfg.StateDb.Archive = carmen.NoArchive
+	require.NoError(cfg.Validate())
+}
@@ -0,0 +1,128 @@
+// Package evmstoretest provides deterministic in-memory evmstore fixtures for unit tests,
+// so that the store behavior can be tested without running a node.
+package evmstoretest
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+)
+
+// ChainID is the chain ID the fixture transactions are signed for.
+var ChainID = big.NewInt(0xfa3)
+
+// TransferTopic is the topic of the logs emitted by the fixture receipts.
+var TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Block is a block of the fixture.
+type Block struct {
+	Number   idx.Block
+	Hash     common.Hash
+	Txs      types.Transactions
+	Receipts types.Receipts
+}
+
+// Logs returns the logs of the block in the order they're indexed.
+func (b *Block) Logs() []*types.Log {
+	var logs []*types.Log
+	for _, r := range b.Receipts {
+		logs = append(logs, r.Logs...)
+	}
+	return logs
+}
+
+// Fixture is a store pre-populated with blocks, transactions, receipts and logs.
+// The content depends only on the fixture parameters, so it may be used as golden data.
+type Fixture struct {
+	Store  *evmstore.Store
+	Signer types.Signer
+	Blocks []Block
+}
+
+// BlockHash returns the hash of the fixture block.
+func BlockHash(n idx.Block) common.Hash {
+	return crypto.Keccak256Hash([]byte("block"), n.Bytes())
+}
+
+// NewFixture returns a store over an in-memory database with the given number of blocks, starting from block 1.
+// The i-th transaction of a block is sent by the fake account i+1 and emits one Transfer log.
+// Every third transaction fails and emits no logs.
+func NewFixture(blocks, txsPerBlock int) *Fixture {
+	f := &Fixture{
+		Store:  evmstore.NewMemStore(),
+		Signer: types.NewEIP155Signer(ChainID),
+	}
+	nonces := make(map[int]uint64)
+	for b := 1; b <= blocks; b++ {
+		block := Block{
+			Number: idx.Block(b),
+			Hash:   BlockHash(idx.Block(b)),
+		}
+		cumulativeGas := uint64(0)
+		logIndex := uint(0)
+		for i := 0; i < txsPerBlock; i++ {
+			key := evmcore.FakeKey(uint32(i + 1))
+			to := common.BigToAddress(big.NewInt(int64(b*1000 + i)))
+			tx, err := types.SignTx(types.NewTransaction(nonces[i], to, big.NewInt(int64(b)), 50000, big.NewInt(1e9), []byte{byte(i)}), f.Signer, key)
+			if err != nil {
+				panic(err)
+			}
+			nonces[i]++
+
+			cumulativeGas += 21000 + uint64(i)
+			r := &types.Receipt{
+				Type:              tx.Type(),
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: cumulativeGas,
+				TxHash:            tx.Hash(),
+				GasUsed:           21000 + uint64(i),
+				BlockHash:         block.Hash,
+				BlockNumber:       big.NewInt(int64(b)),
+				TransactionIndex:  uint(i),
+			}
+			if i%3 == 2 {
+				r.Status = types.ReceiptStatusFailed
+			} else {
+				r.Logs = []*types.Log{{
+					Address:     to,
+					Topics:      []common.Hash{TransferTopic, crypto.PubkeyToAddress(key.PublicKey).Hash(), to.Hash()},
+					Data:        common.LeftPadBytes(big.NewInt(int64(b)).Bytes(), 32),
+					BlockNumber: uint64(b),
+					TxHash:      tx.Hash(),
+					TxIndex:     uint(i),
+					BlockHash:   block.Hash,
+					Index:       logIndex,
+				}}
+				logIndex++
+			}
+			r.Bloom = types.CreateBloom(types.Receipts{r})
+
+			block.Txs = append(block.Txs, tx)
+			block.Receipts = append(block.Receipts, r)
+
+			f.Store.SetTx(tx.Hash(), tx)
+			f.Store.SetTxPosition(tx.Hash(), evmstore.TxPosition{
+				Block:       block.Number,
+				BlockOffset: uint32(i),
+			})
+		}
+		f.Store.SetReceipts(block.Number, block.Receipts)
+		f.Store.IndexLogs(block.Logs()...)
+		f.Blocks = append(f.Blocks, block)
+	}
+	return f
+}
+
+// Block returns the fixture block by number, or nil if it's not in the fixture.
+func (f *Fixture) Block(n idx.Block) *Block {
+	if n == 0 || int(n) > len(f.Blocks) {
+		return nil
+	}
+	return &f.Blocks[n-1]
+}
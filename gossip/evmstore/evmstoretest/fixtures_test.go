@@ -0,0 +1,84 @@
+package evmstoretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestFixture(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	f := NewFixture(3, 4)
+	require.Len(f.Blocks, 3)
+	require.Nil(f.Block(0))
+	require.Nil(f.Block(4))
+
+	// the fixture is deterministic
+	again := NewFixture(3, 4)
+	for i := range f.Blocks {
+		require.Equal(f.Blocks[i].Hash, again.Blocks[i].Hash)
+		for j := range f.Blocks[i].Txs {
+			require.Equal(f.Blocks[i].Txs[j].Hash(), again.Blocks[i].Txs[j].Hash())
+		}
+	}
+
+	for _, b := range f.Blocks {
+		receipts, err := f.Store.GetReceipts(b.Number, f.Signer, b.Hash, b.Txs)
+		require.NoError(err)
+		require.Len(receipts, len(b.Txs))
+		for i, tx := range b.Txs {
+			got, err := f.Store.GetTx(tx.Hash())
+			require.NoError(err)
+			require.Equal(tx.Hash(), got.Hash())
+			pos, err := f.Store.GetTxPosition(tx.Hash())
+			require.NoError(err)
+			require.NotNil(pos)
+			require.Equal(b.Number, pos.Block)
+			require.Equal(uint32(i), pos.BlockOffset)
+			require.Equal(b.Receipts[i].Status, receipts[i].Status)
+			require.Equal(b.Receipts[i].CumulativeGasUsed, receipts[i].CumulativeGasUsed)
+		}
+	}
+
+	// logs of the failed transactions aren't emitted
+	logs, err := f.Store.EvmLogs.FindInBlocks(context.Background(), 1, 3, [][]common.Hash{{}, {TransferTopic}})
+	require.NoError(err)
+	require.Len(logs, 3*3)
+	found := make(map[common.Hash]bool)
+	for _, l := range logs {
+		found[l.TxHash] = true
+	}
+	for _, b := range f.Blocks {
+		for _, l := range b.Logs() {
+			require.True(found[l.TxHash])
+		}
+	}
+}
+
+func TestFixtureGetReceipt(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	f := NewFixture(2, 5)
+	for _, b := range f.Blocks {
+		expect, err := f.Store.GetReceipts(b.Number, f.Signer, b.Hash, b.Txs)
+		require.NoError(err)
+		for i, tx := range b.Txs {
+			got, err := f.Store.GetReceipt(b.Number, i, f.Signer, b.Hash, tx)
+			require.NoError(err)
+			require.Equal(expect[i], got)
+		}
+		got, err := f.Store.GetReceipt(b.Number, len(b.Txs), f.Signer, b.Hash, b.Txs[0])
+		require.NoError(err)
+		require.Nil(got)
+	}
+	got, err := f.Store.GetReceipt(3, 0, f.Signer, BlockHash(3), f.Blocks[0].Txs[0])
+	require.NoError(err)
+	require.Nil(got)
+}
@@ -0,0 +1,96 @@
+package evmstore
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	receiptsCacheHitMeter     = metrics.GetOrRegisterMeter("evm/cache/receipts/hit", nil)
+	receiptsCacheMissMeter    = metrics.GetOrRegisterMeter("evm/cache/receipts/miss", nil)
+	txPositionsCacheHitMeter  = metrics.GetOrRegisterMeter("evm/cache/txpositions/hit", nil)
+	txPositionsCacheMissMeter = metrics.GetOrRegisterMeter("evm/cache/txpositions/miss", nil)
+)
+
+// meteredTable counts the bytes read from and written into a table.
+type meteredTable struct {
+	kvdb.Store
+	read  metrics.Meter
+	write metrics.Meter
+}
+
+// meteredBatch counts the bytes of the batch once it's written.
+type meteredBatch struct {
+	kvdb.Batch
+	write metrics.Meter
+}
+
+type meteredIterator struct {
+	kvdb.Iterator
+	read metrics.Meter
+}
+
+// meterTables wraps the tables of the struct with the counters of read and written bytes,
+// which are named after the fields, e.g. evm/table/txpositions/read.
+func meterTables(tables interface{}) {
+	v := reflect.ValueOf(tables).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		store, ok := field.Interface().(kvdb.Store)
+		if !ok || store == nil {
+			continue
+		}
+		name := "evm/table/" + strings.ToLower(v.Type().Field(i).Name)
+		field.Set(reflect.ValueOf(&meteredTable{
+			Store: store,
+			read:  metrics.GetOrRegisterMeter(name+"/read", nil),
+			write: metrics.GetOrRegisterMeter(name+"/write", nil),
+		}))
+	}
+}
+
+func (t *meteredTable) Get(key []byte) ([]byte, error) {
+	val, err := t.Store.Get(key)
+	t.read.Mark(int64(len(val)))
+	return val, err
+}
+
+func (t *meteredTable) Put(key []byte, value []byte) error {
+	t.write.Mark(int64(len(key) + len(value)))
+	return t.Store.Put(key, value)
+}
+
+func (t *meteredTable) Delete(key []byte) error {
+	t.write.Mark(int64(len(key)))
+	return t.Store.Delete(key)
+}
+
+func (t *meteredTable) NewBatch() kvdb.Batch {
+	return &meteredBatch{
+		Batch: t.Store.NewBatch(),
+		write: t.write,
+	}
+}
+
+func (b *meteredBatch) Write() error {
+	b.write.Mark(int64(b.ValueSize()))
+	return b.Batch.Write()
+}
+
+func (t *meteredTable) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	return &meteredIterator{
+		Iterator: t.Store.NewIterator(prefix, start),
+		read:     t.read,
+	}
+}
+
+func (it *meteredIterator) Next() bool {
+	if !it.Iterator.Next() {
+		return false
+	}
+	it.read.Mark(int64(len(it.Key()) + len(it.Value())))
+	return true
+}
@@ -0,0 +1,43 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeteredTable(t *testing.T) {
+	require := require.New(t)
+
+	read, write := metrics.NewMeterForced(), metrics.NewMeterForced()
+	defer read.Stop()
+	defer write.Stop()
+	table := &meteredTable{
+		Store: memorydb.New(),
+		read:  read,
+		write: write,
+	}
+
+	require.NoError(table.Put([]byte("k1"), []byte("value")))
+	require.EqualValues(7, write.Count())
+
+	require.NoError(table.Delete([]byte("k1")))
+	require.EqualValues(9, write.Count())
+
+	// the batch is counted once it's written
+	batch := table.NewBatch()
+	require.NoError(batch.Put([]byte("k2"), []byte("value")))
+	require.NoError(batch.Delete([]byte("k3")))
+	require.EqualValues(9, write.Count())
+	size := batch.ValueSize()
+	require.NotZero(size)
+	require.NoError(batch.Write())
+	require.EqualValues(9+size, write.Count())
+
+	val, err := table.Get([]byte("k2"))
+	require.NoError(err)
+	require.Equal([]byte("value"), val)
+	require.EqualValues(5, read.Count())
+}
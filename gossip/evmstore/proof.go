@@ -0,0 +1,130 @@
+package evmstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/Carmen/go/common/immutable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var errIncompleteProof = errors.New("witness proof misses a trie node")
+
+// proofPath orders the trie nodes of a witness proof along the path from the root to the hashed key,
+// as expected by eth_getProof. The nodes off the path are skipped. If the key is missing in the trie,
+// the path ends at the node which proves its absence.
+func proofPath(elements []immutable.Bytes, root common.Hash, key []byte) ([][]byte, error) {
+	nodes := make(map[common.Hash][]byte, len(elements))
+	for _, e := range elements {
+		node := e.ToBytes()
+		nodes[crypto.Keccak256Hash(node)] = node
+	}
+	path := keyNibbles(crypto.Keccak256(key))
+
+	res := [][]byte{}
+	if root == types.EmptyRootHash {
+		return res, nil
+	}
+	hash, node := root, []byte(nil)
+	for {
+		if node == nil {
+			var ok bool
+			node, ok = nodes[hash]
+			if !ok {
+				return nil, fmt.Errorf("%w %x", errIncompleteProof, hash)
+			}
+			res = append(res, node)
+		}
+		items, _, err := rlp.SplitList(node)
+		if err != nil {
+			return nil, err
+		}
+		count, err := rlp.CountValues(items)
+		if err != nil {
+			return nil, err
+		}
+		var child []byte
+		switch count {
+		case 17: // branch node
+			if len(path) == 0 {
+				return res, nil
+			}
+			for i := byte(0); i < path[0]; i++ {
+				if _, items, err = splitItem(items); err != nil {
+					return nil, err
+				}
+			}
+			if child, _, err = splitItem(items); err != nil {
+				return nil, err
+			}
+			path = path[1:]
+		case 2: // extension or leaf node
+			encodedKey, rest, err := rlp.SplitString(items)
+			if err != nil {
+				return nil, err
+			}
+			nibbles, leaf := compactToNibbles(encodedKey)
+			if leaf || len(path) < len(nibbles) || string(path[:len(nibbles)]) != string(nibbles) {
+				return res, nil
+			}
+			if child, _, err = splitItem(rest); err != nil {
+				return nil, err
+			}
+			path = path[len(nibbles):]
+		default:
+			return nil, fmt.Errorf("invalid trie node with %d items", count)
+		}
+
+		kind, content, _, err := rlp.Split(child)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case kind == rlp.List:
+			// the node is embedded into its parent
+			hash, node = common.Hash{}, child
+		case len(content) == 0:
+			return res, nil
+		case len(content) == common.HashLength:
+			hash, node = common.BytesToHash(content), nil
+		default:
+			return nil, fmt.Errorf("invalid trie node reference of %d bytes", len(content))
+		}
+	}
+}
+
+// splitItem returns the first encoded RLP item and the rest of the items.
+func splitItem(b []byte) (item, rest []byte, err error) {
+	_, _, rest, err = rlp.Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b[:len(b)-len(rest)], rest, nil
+}
+
+// keyNibbles splits the key into nibbles.
+func keyNibbles(key []byte) []byte {
+	nibbles := make([]byte, 2*len(key))
+	for i, b := range key {
+		nibbles[2*i] = b >> 4
+		nibbles[2*i+1] = b & 0x0f
+	}
+	return nibbles
+}
+
+// compactToNibbles decodes the hex-prefix encoded path of a trie node, and tells if the node is a leaf.
+func compactToNibbles(compact []byte) ([]byte, bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+	nibbles := keyNibbles(compact)
+	leaf := nibbles[0] >= 2
+	// the odd flag means there is a single padding nibble, otherwise two
+	if nibbles[0]&1 == 1 {
+		return nibbles[1:], leaf
+	}
+	return nibbles[2:], leaf
+}
@@ -0,0 +1,142 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/Carmen/go/common/immutable"
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+// orderedProof records the trie nodes in the order they are written by trie.Prove.
+type orderedProof struct {
+	nodes [][]byte
+}
+
+func (p *orderedProof) Put(key []byte, value []byte) error {
+	p.nodes = append(p.nodes, common.CopyBytes(value))
+	return nil
+}
+
+func (p *orderedProof) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func verifyProof(t *testing.T, root common.Hash, key []byte, proof [][]byte) []byte {
+	db := memorydb.New()
+	for _, node := range proof {
+		require.NoError(t, db.Put(crypto.Keccak256(node), node))
+	}
+	value, err := trie.VerifyProof(root, crypto.Keccak256(key), db)
+	require.NoError(t, err)
+	return value
+}
+
+func TestProofPath(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(err)
+	var keys [][]byte
+	for i := 0; i < 300; i++ {
+		key := big.NewInt(int64(i)).Bytes()
+		keys = append(keys, key)
+		tr.Update(crypto.Keccak256(key), []byte{byte(i), 1})
+	}
+	root := tr.Hash()
+
+	// the witness of all the keys, in no particular order
+	var elements []immutable.Bytes
+	all := memorydb.New()
+	for _, key := range keys {
+		require.NoError(tr.Prove(crypto.Keccak256(key), 0, all))
+	}
+	it := all.NewIterator(nil, nil)
+	for it.Next() {
+		elements = append(elements, immutable.NewBytes(it.Value()))
+	}
+	it.Release()
+
+	for _, key := range append(keys, []byte("missing"), []byte("absent")) {
+		expected := &orderedProof{}
+		require.NoError(tr.Prove(crypto.Keccak256(key), 0, expected))
+		got, err := proofPath(elements, root, key)
+		require.NoError(err)
+		require.Equal(expected.nodes, got)
+	}
+
+	got, err := proofPath(nil, types.EmptyRootHash, keys[0])
+	require.NoError(err)
+	require.Empty(got)
+	_, err = proofPath(elements[:1], root, keys[0])
+	require.ErrorIs(err, errIncompleteProof)
+}
+
+func TestCarmenStateDBProofs(t *testing.T) {
+	require := require.New(t)
+
+	params := LiteStoreConfig().StateDb
+	params.Directory = t.TempDir()
+	st, err := carmen.NewState(params)
+	require.NoError(err)
+	defer st.Close()
+
+	var (
+		addr    = common.Address{1}
+		missing = common.Address{2}
+		slot    = common.Hash{3}
+		value   = common.Hash{31: 4}
+	)
+	liveDb := carmen.CreateStateDBUsing(st)
+	live := CreateCarmenStateDb(liveDb)
+	live.BeginBlock(1)
+	live.SetNonce(addr, 5)
+	live.AddBalance(addr, big.NewInt(100))
+	live.SetState(addr, slot, value)
+	live.Finalise()
+	live.EndBlock(1)
+	_, err = live.GetProof(addr)
+	require.ErrorIs(err, errProofsUnsupported)
+
+	require.NoError(st.Flush())
+	archiveDb, err := liveDb.GetArchiveStateDB(1)
+	require.NoError(err)
+	db := CreateCarmenStateDb(archiveDb)
+	defer db.Release()
+	root, err := db.Commit(true)
+	require.NoError(err)
+
+	proof, err := db.GetProof(addr)
+	require.NoError(err)
+	require.Equal(root, crypto.Keccak256Hash(proof[0]))
+	var account ethstate.Account
+	require.NoError(rlp.DecodeBytes(verifyProof(t, root, addr[:], proof), &account))
+	require.Equal(uint64(5), account.Nonce)
+	require.Equal(big.NewInt(100), account.Balance)
+
+	storageRoot, err := db.GetStorageRoot(addr)
+	require.NoError(err)
+	require.Equal(account.Root, storageRoot)
+	storageProof, err := db.GetStorageProof(addr, slot)
+	require.NoError(err)
+	var stored []byte
+	require.NoError(rlp.DecodeBytes(verifyProof(t, storageRoot, slot[:], storageProof), &stored))
+	require.Equal(value, common.BytesToHash(stored))
+
+	// the absence of an account is proven too
+	proof, err = db.GetProof(missing)
+	require.NoError(err)
+	require.Nil(verifyProof(t, root, missing[:], proof))
+	storageRoot, err = db.GetStorageRoot(missing)
+	require.NoError(err)
+	require.Equal(types.EmptyRootHash, storageRoot)
+}
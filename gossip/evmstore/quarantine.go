@@ -0,0 +1,152 @@
+package evmstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/snappy"
+)
+
+// Names of the API-only tables, the corrupted blocks of which are quarantined.
+const (
+	ReceiptsTable = "receipts"
+	TxTracesTable = "txtraces"
+)
+
+// QuarantinedError is returned for the data of a block, which is found corrupted.
+// The rest of the data is served as usual.
+type QuarantinedError struct {
+	Table string
+	Block idx.Block
+}
+
+func (e *QuarantinedError) Error() string {
+	return fmt.Sprintf("%s of block %d are corrupted and quarantined until repaired", e.Table, e.Block)
+}
+
+// quarantine is the set of the blocks of API-only tables, which are found corrupted.
+// It isn't persisted, the corrupted entries are found again by the next scan or read.
+type quarantine struct {
+	mu     sync.RWMutex
+	blocks map[string]map[idx.Block]struct{}
+}
+
+// Quarantine marks the data of the block in the table as corrupted,
+// so it's reported by QuarantinedError until it's repaired or re-indexed.
+func (s *Store) Quarantine(table string, n idx.Block) {
+	s.quarantine.mu.Lock()
+	defer s.quarantine.mu.Unlock()
+	if s.quarantine.blocks == nil {
+		s.quarantine.blocks = make(map[string]map[idx.Block]struct{})
+	}
+	if s.quarantine.blocks[table] == nil {
+		s.quarantine.blocks[table] = make(map[idx.Block]struct{})
+	}
+	if _, ok := s.quarantine.blocks[table][n]; !ok {
+		s.Log.Error("Corrupted data is quarantined", "table", table, "block", n)
+		s.quarantine.blocks[table][n] = struct{}{}
+	}
+}
+
+// CheckQuarantine returns QuarantinedError if the data of the block in the table is quarantined.
+func (s *Store) CheckQuarantine(table string, n idx.Block) error {
+	s.quarantine.mu.RLock()
+	defer s.quarantine.mu.RUnlock()
+	if _, ok := s.quarantine.blocks[table][n]; ok {
+		return &QuarantinedError{Table: table, Block: n}
+	}
+	return nil
+}
+
+// Quarantined returns the quarantined blocks of each table, in ascending order.
+func (s *Store) Quarantined() map[string][]idx.Block {
+	s.quarantine.mu.RLock()
+	defer s.quarantine.mu.RUnlock()
+	res := make(map[string][]idx.Block, len(s.quarantine.blocks))
+	for table, blocks := range s.quarantine.blocks {
+		if len(blocks) == 0 {
+			continue
+		}
+		sorted := make([]idx.Block, 0, len(blocks))
+		for n := range blocks {
+			sorted = append(sorted, n)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+		res[table] = sorted
+	}
+	return res
+}
+
+func (s *Store) releaseQuarantine(table string, n idx.Block) {
+	s.quarantine.mu.Lock()
+	defer s.quarantine.mu.Unlock()
+	if _, ok := s.quarantine.blocks[table][n]; ok {
+		s.Log.Info("Quarantined data is repaired", "table", table, "block", n)
+		delete(s.quarantine.blocks[table], n)
+	}
+}
+
+// ScanApiTables verifies the encoding of all the entries of the API-only tables keyed by block,
+// quarantines the corrupted blocks and returns the number of the corrupted entries found.
+func (s *Store) ScanApiTables(ctx context.Context) (int, error) {
+	corrupted := 0
+
+	it := s.table.Receipts.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return corrupted, err
+		}
+		if len(it.Key()) != 8 {
+			continue
+		}
+		if _, err := DecodeRawReceipts(it.Value()); err != nil {
+			s.Quarantine(ReceiptsTable, idx.BytesToBlock(it.Key()))
+			corrupted++
+		}
+	}
+	if err := it.Error(); err != nil {
+		return corrupted, err
+	}
+
+	tracesIt := s.table.TxTraces.NewIterator(nil, nil)
+	defer tracesIt.Release()
+	for tracesIt.Next() {
+		if err := ctx.Err(); err != nil {
+			return corrupted, err
+		}
+		// skip the per-block index keys
+		if len(tracesIt.Key()) != 8+32 {
+			continue
+		}
+		if _, err := snappy.Decode(nil, tracesIt.Value()); err != nil {
+			s.Quarantine(TxTracesTable, idx.BytesToBlock(tracesIt.Key()[:8]))
+			corrupted++
+		}
+	}
+	return corrupted, tracesIt.Error()
+}
+
+// RepairQuarantined repairs the quarantined data, which may be restored locally.
+// The transaction traces are erased, so they're traced again on demand.
+// The receipts are rebuilt by the re-execution of the block, they stay quarantined if it fails.
+func (s *Store) RepairQuarantined(reexecute func(n idx.Block) (types.Receipts, error)) {
+	for _, n := range s.Quarantined()[TxTracesTable] {
+		s.DelTxTraces(n)
+		s.releaseQuarantine(TxTracesTable, n)
+	}
+	for _, n := range s.Quarantined()[ReceiptsTable] {
+		receipts, err := reexecute(n)
+		if err != nil {
+			s.Log.Warn("Failed to rebuild quarantined receipts", "block", n, "err", err)
+			continue
+		}
+		s.SetReceipts(n, receipts)
+	}
+}
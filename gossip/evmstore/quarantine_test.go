@@ -0,0 +1,71 @@
+package evmstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreQuarantine(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: tx.Hash(), Logs: []*types.Log{}}}
+	store.SetReceipts(1, receipts)
+	store.SetReceipts(2, receipts)
+	store.SetTxTraces(1, tx.Hash(), []byte(`[]`))
+
+	// corrupt the receipts of block 2 and the traces of block 1
+	require.NoError(store.table.Receipts.Put(idx.Block(2).Bytes(), []byte{0xff, 0x01}))
+	require.NoError(store.table.TxTraces.Put(append(idx.Block(1).Bytes(), common.Hash{2}.Bytes()...), []byte{0xff}))
+
+	corrupted, err := store.ScanApiTables(context.Background())
+	require.NoError(err)
+	require.Equal(2, corrupted)
+	require.Equal(map[string][]idx.Block{
+		ReceiptsTable: {2},
+		TxTracesTable: {1},
+	}, store.Quarantined())
+
+	// the rest of the data is served
+	got, err := store.GetReceipt(1, 0, nil, common.Hash{}, tx)
+	require.NoError(err)
+	require.NotNil(got)
+
+	// the quarantined data is reported
+	var qErr *QuarantinedError
+	_, err = store.GetReceipt(2, 0, nil, common.Hash{}, tx)
+	require.True(errors.As(err, &qErr))
+	require.Equal(idx.Block(2), qErr.Block)
+	_, err = store.GetTxTraces(1, tx.Hash())
+	require.True(errors.As(err, &qErr))
+	require.Equal(TxTracesTable, qErr.Table)
+
+	// traces are repaired locally, receipts stay quarantined until the block is re-executed
+	store.RepairQuarantined(func(n idx.Block) (types.Receipts, error) {
+		return nil, errors.New("state is missing")
+	})
+	traces, err := store.GetTxTraces(1, tx.Hash())
+	require.NoError(err)
+	require.Nil(traces)
+	require.Error(store.CheckQuarantine(ReceiptsTable, 2))
+
+	store.RepairQuarantined(func(n idx.Block) (types.Receipts, error) {
+		require.Equal(idx.Block(2), n)
+		return receipts, nil
+	})
+	require.NoError(store.CheckQuarantine(ReceiptsTable, 2))
+	require.Empty(store.Quarantined())
+	got, err = store.GetReceipt(2, 0, nil, common.Hash{}, tx)
+	require.NoError(err)
+	require.Equal(receipts[0].CumulativeGasUsed, got.CumulativeGasUsed)
+}
@@ -0,0 +1,306 @@
+package evmstore
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+type slotKey struct {
+	addr common.Address
+	slot common.Hash
+}
+
+// archivingStateDB records the storage changes of the archived contracts made by blocks processing.
+type archivingStateDB struct {
+	state.StateDB
+	store *Store
+
+	block      idx.Block
+	prev       map[slotKey]common.Hash // values of the written slots before the block
+	destructed map[common.Address]bool
+}
+
+func (s *Store) wrapArchivingStateDB(db state.StateDB) state.StateDB {
+	return &archivingStateDB{
+		StateDB: db,
+		store:   s,
+	}
+}
+
+func (a *archivingStateDB) BeginBlock(number uint64) {
+	a.block = idx.Block(number)
+	a.prev = make(map[slotKey]common.Hash)
+	a.destructed = make(map[common.Address]bool)
+	a.StateDB.BeginBlock(number)
+}
+
+func (a *archivingStateDB) touch(addr common.Address, slot common.Hash) {
+	key := slotKey{addr, slot}
+	if _, ok := a.prev[key]; !ok {
+		// the slot isn't written by the previous transactions of the block
+		a.prev[key] = a.StateDB.GetCommittedState(addr, slot)
+	}
+}
+
+func (a *archivingStateDB) SetState(addr common.Address, slot, value common.Hash) {
+	if a.store.IsArchivedContract(addr) {
+		a.touch(addr, slot)
+	}
+	a.StateDB.SetState(addr, slot, value)
+}
+
+func (a *archivingStateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	if a.store.IsArchivedContract(addr) {
+		a.destructed[addr] = true
+		for slot := range storage {
+			a.touch(addr, slot)
+		}
+	}
+	a.StateDB.SetStorage(addr, storage)
+}
+
+func (a *archivingStateDB) Suicide(addr common.Address) bool {
+	if a.store.IsArchivedContract(addr) {
+		a.destructed[addr] = true
+	}
+	return a.StateDB.Suicide(addr)
+}
+
+func (a *archivingStateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
+	root, err := a.StateDB.Commit(deleteEmptyObjects)
+	if err != nil {
+		return root, err
+	}
+
+	for addr := range a.store.archivedContracts {
+		a.store.SetArchiveStart(a.block, addr)
+	}
+	for addr := range a.destructed {
+		if !a.StateDB.Exist(addr) {
+			a.store.SetArchivedDestruct(a.block, addr)
+		}
+	}
+	for key, prev := range a.prev {
+		value := a.StateDB.GetState(key.addr, key.slot)
+		if value != prev {
+			a.store.SetArchivedStorage(a.block, key.addr, key.slot, prev, value)
+		}
+	}
+	return root, nil
+}
+
+// ErrHistoricalStateUnavailable is returned by the selective archive for the historical reads
+// of the state, which isn't archived, i.e. everything except the storage of the archived contracts.
+var ErrHistoricalStateUnavailable = errors.New("historical state not available")
+
+// selectiveArchiveStateDB is a read-only StateDB for RPC, which serves the storage
+// of the archived contracts as of a historical block. The reads of the rest of the state fail.
+type selectiveArchiveStateDB struct {
+	state.StateDB
+	store *Store
+	block idx.Block
+
+	history    map[slotKey]common.Hash
+	destructed map[common.Address]bool
+	// slots of the archived contracts written by the current transaction, journaled by snapshots
+	dirty      []slotKey
+	dirtyCount map[slotKey]int
+	snapshots  map[int]int
+	// slots of the archived contracts written by the previous transactions
+	committed map[slotKey]bool
+
+	err error
+}
+
+func (s *Store) newSelectiveArchiveStateDB(db state.StateDB, block idx.Block) *selectiveArchiveStateDB {
+	return &selectiveArchiveStateDB{
+		StateDB:    db,
+		store:      s,
+		block:      block,
+		history:    make(map[slotKey]common.Hash),
+		destructed: make(map[common.Address]bool),
+		dirtyCount: make(map[slotKey]int),
+		snapshots:  make(map[int]int),
+		committed:  make(map[slotKey]bool),
+	}
+}
+
+func (a *selectiveArchiveStateDB) historical(addr common.Address, slot common.Hash) common.Hash {
+	key := slotKey{addr, slot}
+	if value, ok := a.history[key]; ok {
+		return value
+	}
+	value, live, err := a.store.GetArchivedStorage(a.block, addr, slot)
+	if err != nil {
+		a.err = errors.Join(a.err, err)
+		return common.Hash{}
+	}
+	if live {
+		value = a.StateDB.GetCommittedState(addr, slot)
+	}
+	a.history[key] = value
+	return value
+}
+
+func (a *selectiveArchiveStateDB) archived(addr common.Address) bool {
+	return a.store.IsArchivedContract(addr) && !a.destructed[addr]
+}
+
+// unavailable records the read of the state, which isn't archived.
+func (a *selectiveArchiveStateDB) unavailable() {
+	if !errors.Is(a.err, ErrHistoricalStateUnavailable) {
+		a.err = errors.Join(a.err, ErrHistoricalStateUnavailable)
+	}
+}
+
+// ExecutionError refuses the EVM execution, as it would read the state, which isn't archived.
+func (a *selectiveArchiveStateDB) ExecutionError() error {
+	return ErrHistoricalStateUnavailable
+}
+
+func (a *selectiveArchiveStateDB) GetBalance(addr common.Address) *big.Int {
+	a.unavailable()
+	return new(big.Int)
+}
+
+func (a *selectiveArchiveStateDB) GetNonce(addr common.Address) uint64 {
+	a.unavailable()
+	return 0
+}
+
+func (a *selectiveArchiveStateDB) GetCode(addr common.Address) []byte {
+	a.unavailable()
+	return nil
+}
+
+func (a *selectiveArchiveStateDB) GetCodeHash(addr common.Address) common.Hash {
+	a.unavailable()
+	return common.Hash{}
+}
+
+func (a *selectiveArchiveStateDB) GetCodeSize(addr common.Address) int {
+	a.unavailable()
+	return 0
+}
+
+func (a *selectiveArchiveStateDB) Exist(addr common.Address) bool {
+	a.unavailable()
+	return false
+}
+
+func (a *selectiveArchiveStateDB) Empty(addr common.Address) bool {
+	a.unavailable()
+	return true
+}
+
+// GetProof isn't supported, as the selective archive keeps no historical tries.
+func (a *selectiveArchiveStateDB) GetProof(addr common.Address) ([][]byte, error) {
+	return nil, errProofsUnsupported
+}
+
+func (a *selectiveArchiveStateDB) GetStorageRoot(addr common.Address) (common.Hash, error) {
+	return common.Hash{}, errProofsUnsupported
+}
+
+func (a *selectiveArchiveStateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	return nil, errProofsUnsupported
+}
+
+func (a *selectiveArchiveStateDB) Error() error {
+	return errors.Join(a.StateDB.Error(), a.err)
+}
+
+func (a *selectiveArchiveStateDB) GetState(addr common.Address, slot common.Hash) common.Hash {
+	if !a.store.IsArchivedContract(addr) {
+		a.unavailable()
+		return common.Hash{}
+	}
+	key := slotKey{addr, slot}
+	if !a.archived(addr) || a.dirtyCount[key] != 0 || a.committed[key] {
+		return a.StateDB.GetState(addr, slot)
+	}
+	return a.historical(addr, slot)
+}
+
+func (a *selectiveArchiveStateDB) GetCommittedState(addr common.Address, slot common.Hash) common.Hash {
+	if !a.store.IsArchivedContract(addr) {
+		a.unavailable()
+		return common.Hash{}
+	}
+	if !a.archived(addr) || a.committed[slotKey{addr, slot}] {
+		return a.StateDB.GetCommittedState(addr, slot)
+	}
+	return a.historical(addr, slot)
+}
+
+func (a *selectiveArchiveStateDB) SetState(addr common.Address, slot, value common.Hash) {
+	if a.archived(addr) {
+		key := slotKey{addr, slot}
+		a.dirty = append(a.dirty, key)
+		a.dirtyCount[key]++
+	}
+	a.StateDB.SetState(addr, slot, value)
+}
+
+func (a *selectiveArchiveStateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	a.destructed[addr] = true
+	a.StateDB.SetStorage(addr, storage)
+}
+
+func (a *selectiveArchiveStateDB) Suicide(addr common.Address) bool {
+	a.destructed[addr] = true
+	return a.StateDB.Suicide(addr)
+}
+
+func (a *selectiveArchiveStateDB) Snapshot() int {
+	id := a.StateDB.Snapshot()
+	a.snapshots[id] = len(a.dirty)
+	return id
+}
+
+func (a *selectiveArchiveStateDB) RevertToSnapshot(id int) {
+	a.StateDB.RevertToSnapshot(id)
+	if n, ok := a.snapshots[id]; ok {
+		for _, key := range a.dirty[n:] {
+			a.dirtyCount[key]--
+		}
+		a.dirty = a.dirty[:n]
+	}
+}
+
+func (a *selectiveArchiveStateDB) Finalise() {
+	for _, key := range a.dirty {
+		a.committed[key] = true
+	}
+	a.dirty = a.dirty[:0]
+	a.dirtyCount = make(map[slotKey]int)
+	a.snapshots = make(map[int]int)
+	a.StateDB.Finalise()
+}
+
+func (a *selectiveArchiveStateDB) Copy() state.StateDB {
+	cp := a.store.newSelectiveArchiveStateDB(a.StateDB.Copy(), a.block)
+	for key, value := range a.history {
+		cp.history[key] = value
+	}
+	for addr := range a.destructed {
+		cp.destructed[addr] = true
+	}
+	cp.dirty = append(cp.dirty, a.dirty...)
+	for key, n := range a.dirtyCount {
+		cp.dirtyCount[key] = n
+	}
+	for id, n := range a.snapshots {
+		cp.snapshots[id] = n
+	}
+	for key := range a.committed {
+		cp.committed[key] = true
+	}
+	cp.err = a.err
+	return cp
+}
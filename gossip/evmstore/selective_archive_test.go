@@ -0,0 +1,77 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestSelectiveArchiveHistoricalReads(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	contract := common.Address{1}
+	account := common.Address{2}
+	slot := common.Hash{3}
+
+	cfg := LiteStoreConfig()
+	cfg.StateDb.Directory = t.TempDir()
+	cfg.StateDb.Archive = carmen.NoArchive
+	cfg.StateDb.LiveCache = 100 // bytes, to be overridden by the minimal value
+	cfg.ArchivedContracts = []common.Address{contract}
+	store := NewStore(memorydb.New(), cfg)
+	require.NoError(store.Open())
+	defer store.Close()
+
+	process := func(n uint64, balance int64, value common.Hash) common.Hash {
+		root, err := store.carmenState.GetHash()
+		require.NoError(err)
+		live, err := store.GetLiveStateDb(hash.Hash(root))
+		require.NoError(err)
+		live.BeginBlock(n)
+		live.SetBalance(account, big.NewInt(balance))
+		live.SetNonce(contract, 1)
+		live.SetState(contract, slot, value)
+		live.Finalise()
+		live.EndBlock(n)
+		newRoot, err := live.Commit(true)
+		require.NoError(err)
+		return newRoot
+	}
+	root1 := process(1, 100, common.Hash{0xa})
+	root2 := process(2, 200, common.Hash{0xb})
+
+	// no archive of the whole state is available
+	_, _, err := store.GetArchiveBlockHeight()
+	require.ErrorIs(err, carmen.NoArchiveError)
+
+	// the state of the latest block is the live one
+	latest, err := store.GetRpcStateDb(big.NewInt(2), root2)
+	require.NoError(err)
+	require.Equal(big.NewInt(200), latest.GetBalance(account))
+	require.Equal(common.Hash{0xb}, latest.GetState(contract, slot))
+	require.NoError(latest.Error())
+	_, partial := latest.(state.PartialStateDB)
+	require.False(partial)
+
+	// the historical storage of the archived contract is served
+	historical, err := store.GetRpcStateDb(big.NewInt(1), root1)
+	require.NoError(err)
+	require.Equal(common.Hash{0xa}, historical.GetState(contract, slot))
+	require.NoError(historical.Error())
+
+	// the historical balance isn't archived, the live value isn't returned instead
+	require.NotEqual(big.NewInt(200), historical.GetBalance(account))
+	require.ErrorIs(historical.Error(), ErrHistoricalStateUnavailable)
+
+	// the EVM execution over the historical state is refused
+	require.ErrorIs(historical.(state.PartialStateDB).ExecutionError(), ErrHistoricalStateUnavailable)
+}
@@ -0,0 +1,141 @@
+package evmstore
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-opera/inter/state"
+)
+
+// accessTrackingStateDB records the accounts and storage slots accessed by blocks processing,
+// for the state expiry experiment.
+type accessTrackingStateDB struct {
+	state.StateDB
+	store *Store
+
+	epoch  idx.Epoch
+	expiry idx.Epoch
+
+	accounts map[common.Address]bool
+	slots    map[slotKey]bool
+}
+
+// WrapAccessTracking returns StateDB, which records accesses to the state made in the epoch.
+// The accesses are queued to be recorded on Commit.
+func (s *Store) WrapAccessTracking(db state.StateDB, epoch, expiry idx.Epoch) state.StateDB {
+	return &accessTrackingStateDB{
+		StateDB:  db,
+		store:    s,
+		epoch:    epoch,
+		expiry:   expiry,
+		accounts: make(map[common.Address]bool),
+		slots:    make(map[slotKey]bool),
+	}
+}
+
+func (a *accessTrackingStateDB) account(addr common.Address) {
+	a.accounts[addr] = true
+}
+
+func (a *accessTrackingStateDB) slot(addr common.Address, slot common.Hash) {
+	a.accounts[addr] = true
+	a.slots[slotKey{addr, slot}] = true
+}
+
+func (a *accessTrackingStateDB) CreateAccount(addr common.Address) {
+	a.account(addr)
+	a.StateDB.CreateAccount(addr)
+}
+
+func (a *accessTrackingStateDB) SubBalance(addr common.Address, amount *big.Int) {
+	a.account(addr)
+	a.StateDB.SubBalance(addr, amount)
+}
+
+func (a *accessTrackingStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	a.account(addr)
+	a.StateDB.AddBalance(addr, amount)
+}
+
+func (a *accessTrackingStateDB) GetBalance(addr common.Address) *big.Int {
+	a.account(addr)
+	return a.StateDB.GetBalance(addr)
+}
+
+func (a *accessTrackingStateDB) GetNonce(addr common.Address) uint64 {
+	a.account(addr)
+	return a.StateDB.GetNonce(addr)
+}
+
+func (a *accessTrackingStateDB) SetNonce(addr common.Address, nonce uint64) {
+	a.account(addr)
+	a.StateDB.SetNonce(addr, nonce)
+}
+
+func (a *accessTrackingStateDB) GetCodeHash(addr common.Address) common.Hash {
+	a.account(addr)
+	return a.StateDB.GetCodeHash(addr)
+}
+
+func (a *accessTrackingStateDB) GetCode(addr common.Address) []byte {
+	a.account(addr)
+	return a.StateDB.GetCode(addr)
+}
+
+func (a *accessTrackingStateDB) SetCode(addr common.Address, code []byte) {
+	a.account(addr)
+	a.StateDB.SetCode(addr, code)
+}
+
+func (a *accessTrackingStateDB) GetCodeSize(addr common.Address) int {
+	a.account(addr)
+	return a.StateDB.GetCodeSize(addr)
+}
+
+func (a *accessTrackingStateDB) GetCommittedState(addr common.Address, slot common.Hash) common.Hash {
+	a.slot(addr, slot)
+	return a.StateDB.GetCommittedState(addr, slot)
+}
+
+func (a *accessTrackingStateDB) GetState(addr common.Address, slot common.Hash) common.Hash {
+	a.slot(addr, slot)
+	return a.StateDB.GetState(addr, slot)
+}
+
+func (a *accessTrackingStateDB) SetState(addr common.Address, slot, value common.Hash) {
+	a.slot(addr, slot)
+	a.StateDB.SetState(addr, slot, value)
+}
+
+func (a *accessTrackingStateDB) Suicide(addr common.Address) bool {
+	a.account(addr)
+	return a.StateDB.Suicide(addr)
+}
+
+func (a *accessTrackingStateDB) Exist(addr common.Address) bool {
+	a.account(addr)
+	return a.StateDB.Exist(addr)
+}
+
+func (a *accessTrackingStateDB) Empty(addr common.Address) bool {
+	a.account(addr)
+	return a.StateDB.Empty(addr)
+}
+
+func (a *accessTrackingStateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
+	root, err := a.StateDB.Commit(deleteEmptyObjects)
+	if err != nil {
+		return root, err
+	}
+	a.store.queueStateExpiry(stateExpiryTask{
+		epoch:    a.epoch,
+		expiry:   a.expiry,
+		accounts: a.accounts,
+		slots:    a.slots,
+	})
+	a.accounts = make(map[common.Address]bool)
+	a.slots = make(map[slotKey]bool)
+	return root, nil
+}
@@ -20,6 +20,9 @@ func (s *Store) GetLiveStateDb(stateRoot hash.Hash) (state.StateDB, error) {
 	if s.liveStateDb.GetHash() != cc.Hash(stateRoot) {
 		return nil, fmt.Errorf("unable to get Carmen live StateDB - unexpected state root (%x != %x)", s.liveStateDb.GetHash(), stateRoot)
 	}
+	if s.archivedContracts != nil {
+		return s.wrapArchivingStateDB(CreateCarmenStateDb(s.liveStateDb)), nil
+	}
 	return CreateCarmenStateDb(s.liveStateDb), nil
 }
 
@@ -39,6 +42,9 @@ func (s *Store) GetArchiveBlockHeight() (height uint64, empty bool, err error) {
 	if s.liveStateDb == nil {
 		return 0, true, fmt.Errorf("unable to get archive block height - EvmStore is not open")
 	}
+	if s.archivedContracts != nil {
+		return 0, false, carmen.NoArchiveError // the selective archive keeps the archived contracts storage only
+	}
 	return s.liveStateDb.GetArchiveBlockHeight()
 }
 
@@ -48,6 +54,18 @@ func (s *Store) GetRpcStateDb(blockNum *big.Int, stateRoot common.Hash) (state.S
 	if s.liveStateDb == nil {
 		return nil, fmt.Errorf("unable to get RPC StateDb - EvmStore is not open")
 	}
+	if s.archivedContracts != nil {
+		liveRoot, err := s.carmenState.GetHash()
+		if err != nil {
+			return nil, err
+		}
+		stateDb := CreateCarmenStateDb(carmen.CreateNonCommittableStateDBUsing(s.carmenState))
+		if liveRoot == cc.Hash(stateRoot) {
+			return stateDb, nil
+		}
+		// the selective archive keeps the history of the archived contracts storage only
+		return s.newSelectiveArchiveStateDB(stateDb, idx.Block(blockNum.Uint64())), nil
+	}
 	stateDb, err := s.liveStateDb.GetArchiveStateDB(blockNum.Uint64())
 	if err != nil {
 		return nil, err
@@ -3,18 +3,40 @@ package evmstore
 import (
 	"fmt"
 	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/autocompact"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+	"github.com/Fantom-foundation/go-opera/utils/membudget"
 	"github.com/Fantom-foundation/go-opera/utils/rlpstore"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
-	"github.com/Fantom-foundation/lachesis-base/utils/wlru"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
-const nominalSize uint = 1
+const (
+	// txPositionSize is an estimated memory size of a cached TxPosition with its key.
+	txPositionSize = 128
+	// receiptMemSize is an estimated memory size of a decoded receipt without logs.
+	receiptMemSize = 512
+	// logMemSize is an estimated memory size of a decoded log without topics and data.
+	logMemSize = 192
+	// evmHeaderMemSize is an estimated memory size of a cached EvmHeader.
+	evmHeaderMemSize = 320
+	// prunedCompactionLimit is the estimated size of the deleted data, after which the pruned range is compacted.
+	prunedCompactionLimit = 64 * opt.MiB
+	// logsIndexQueueSize is a number of receipts, the logs of which may wait for the indexing in background.
+	logsIndexQueueSize = 4096
+)
 
 // Store is a node persistent storage working over physical key-value database.
 type Store struct {
@@ -26,18 +48,52 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
+		TxTraces    kvdb.Store `table:"y"`
+		TxSenders   kvdb.Store `table:"s"`
+		TxRoots     kvdb.Store `table:"O"`
+		// Fee history index, per block
+		FeeHistory kvdb.Store `table:"W"`
+		// Contracts deployments and destructions index
+		ContractCreations kvdb.Store `table:"C"`
+		CodeHistory       kvdb.Store `table:"Z"`
+		// Balance changes journal
+		BalanceHistory kvdb.Store `table:"N"`
+		// Selective archive
+		ArchivedStorage kvdb.Store `table:"A"`
+		// State expiry experiment
+		StateAccess kvdb.Store `table:"L"`
+		// Bloombits index
+		Blooms    kvdb.Store `table:"c"`
+		BloomBits kvdb.Store `table:"d"`
+	}
+
+	// pruned tables, the deleted ranges of which are compacted once enough of them is accumulated
+	pruned struct {
+		TxTraces    *autocompact.Store
+		StateAccess *autocompact.Store
 	}
 
 	EvmLogs  topicsdb.Index
 
 	cache struct {
-		TxPositions *wlru.Cache `cache:"-"` // store by pointer
-		Receipts    *wlru.Cache `cache:"-"` // store by value
-		EvmBlocks   *wlru.Cache `cache:"-"` // store by pointer
+		TxPositions *lru.Cache[common.Hash, *TxPosition]      `cache:"-"`
+		Receipts    *lru.Cache[idx.Block, types.Receipts]     `cache:"-"`
+		EvmHeaders  *lru.Cache[idx.Block, *evmcore.EvmHeader] `cache:"-"`
+		EvmBodies   *lru.Cache[idx.Block, types.Transactions] `cache:"-"`
 	}
 
 	rlp rlpstore.Helper
 
+	archivedContracts map[common.Address]bool
+	archiveStarted    map[common.Address]bool
+
+	stateExpiry        chan stateExpiryTask
+	stateExpiryStopped chan struct{}
+
+	quarantine quarantine
+
+	bloomBitsMu sync.Mutex
+
 	logger.Instance
 
 	parameters carmen.Parameters
@@ -56,17 +112,54 @@ func NewStore(mainDB kvdb.Store, cfg StoreConfig) *Store {
 	}
 
 	table.MigrateTables(&s.table, s.mainDB)
+	meterTables(&s.table)
+	// the pruned tables delete the ranges by the main DB, the prefixes are the ones of the tables
+	s.pruned.TxTraces = autocompact.Wrap(rangedel.NewTable(s.mainDB, []byte("y")), prunedCompactionLimit, autocompact.NewForwardCont, "tx-traces")
+	s.pruned.StateAccess = autocompact.Wrap(rangedel.NewTable(s.mainDB, []byte("L")), prunedCompactionLimit, autocompact.NewForwardCont, "state-access")
 
-	if cfg.DisableLogsIndexing {
+	if len(cfg.ArchivedContracts) != 0 {
+		s.archivedContracts = make(map[common.Address]bool, len(cfg.ArchivedContracts))
+		for _, addr := range cfg.ArchivedContracts {
+			s.archivedContracts[addr] = true
+		}
+		s.archiveStarted = make(map[common.Address]bool, len(cfg.ArchivedContracts))
+	}
+
+	if cfg.RemoteLogsIndex != "" {
+		s.EvmLogs = topicsdb.NewRemote(cfg.RemoteLogsIndex)
+	} else if cfg.DisableLogsIndexing {
 		s.EvmLogs = topicsdb.NewDummy()
 	} else {
-		s.EvmLogs = topicsdb.NewWithThreadPool(mainDB)
+		s.EvmLogs = topicsdb.NewAsync(topicsdb.NewWithThreadPoolConfig(mainDB, topicsdb.Config{
+			Parallelism: cfg.LogsIndexParallelism,
+		}), logsIndexQueueSize)
+		if legacy, err := topicsdb.HasLegacy(mainDB); err != nil {
+			s.Log.Crit("Failed to read the logs index", "err", err)
+		} else if legacy {
+			s.Log.Warn("Logs index of an older release isn't migrated, the logs queries are refused until it's migrated")
+			s.EvmLogs = topicsdb.NewUnavailable(s.EvmLogs, topicsdb.ErrLegacyIndex)
+		} else if next, err := topicsdb.RebuildProgress(mainDB); err != nil {
+			s.Log.Crit("Failed to read the logs index rebuild progress", "err", err)
+		} else if next != 0 {
+			// the index has only the logs of the blocks before the interrupted rebuild position
+			s.Log.Warn("Logs index rebuild isn't finished, the logs queries are refused until `sonictool reindex-logs` is run", "block", next)
+			s.EvmLogs = topicsdb.NewUnavailable(s.EvmLogs, topicsdb.ErrUnfinishedRebuild)
+		}
 	}
 	s.initCache()
+	if cfg.StateExpiryEpochs != 0 {
+		s.startStateExpiry()
+	}
 
 	return s
 }
 
+// NewMemStore creates store over an in-memory db without caches and the EVM state.
+// It's intended for tests, see also the evmstoretest package.
+func NewMemStore() *Store {
+	return NewStore(memorydb.New(), StoreConfig{})
+}
+
 // Open the StateDB database (after the genesis import)
 func (s *Store) Open() error {
 	err := s.initCarmen()
@@ -83,8 +176,15 @@ func (s *Store) Open() error {
 
 // Close closes underlying database.
 func (s *Store) Close() error {
+	s.stopStateExpiry()
 	// set all table/cache fields to nil
 	table.MigrateTables(&s.table, nil)
+	// compact the pruned ranges, which haven't reached the compaction limit yet
+	if s.pruned.TxTraces != nil {
+		s.pruned.TxTraces.CompactPending()
+		s.pruned.StateAccess.CompactPending()
+	}
+	s.pruned.TxTraces, s.pruned.StateAccess = nil, nil
 	table.MigrateCaches(&s.cache, func() interface{} {
 		return nil
 	})
@@ -104,30 +204,40 @@ func (s *Store) Close() error {
 }
 
 func (s *Store) initCache() {
-	s.cache.Receipts = s.makeCache(s.cfg.Cache.ReceiptsSize, s.cfg.Cache.ReceiptsBlocks)
-	s.cache.TxPositions = s.makeCache(nominalSize*uint(s.cfg.Cache.TxPositions), s.cfg.Cache.TxPositions)
-	s.cache.EvmBlocks = s.makeCache(s.cfg.Cache.EvmBlocksSize, s.cfg.Cache.EvmBlocksNum)
+	apiCache := s.cfg.apiCacheConfig()
+	s.cache.Receipts = lru.New(apiCache.ReceiptsSize, apiCache.ReceiptsBlocks, receiptsMemSize)
+	s.cache.TxPositions = lru.New(txPositionSize*uint(apiCache.TxPositions), apiCache.TxPositions, func(common.Hash, *TxPosition) uint {
+		return txPositionSize
+	})
+	s.cache.EvmHeaders = lru.New(evmHeaderMemSize*uint(s.cfg.Cache.EvmHeadersNum), s.cfg.Cache.EvmHeadersNum, func(idx.Block, *evmcore.EvmHeader) uint {
+		return evmHeaderMemSize
+	})
+	s.cache.EvmBodies = lru.New(s.cfg.Cache.EvmBlocksSize, s.cfg.Cache.EvmBlocksNum, func(_ idx.Block, txs types.Transactions) uint {
+		return uint((&evmcore.EvmBlock{Transactions: txs}).EstimateSize())
+	})
 }
 
-// IndexLogs indexes EVM logs
+// IndexLogs indexes EVM logs in background.
+// The queries to the index wait until the logs pushed before are indexed.
 func (s *Store) IndexLogs(recs ...*types.Log) {
 	err := s.EvmLogs.Push(recs...)
 	if err != nil {
-		s.Log.Crit("DB logs index error", "err", err)
+		s.Log.Error("DB logs index error", "err", err)
 	}
 }
 
-/*
- * Utils:
- */
+// FlushLogsIndex waits until the logs pushed before are indexed.
+func (s *Store) FlushLogsIndex() error {
+	return topicsdb.Flush(s.EvmLogs)
+}
 
-func (s *Store) makeCache(weight uint, size int) *wlru.Cache {
-	cache, err := wlru.New(weight, size)
-	if err != nil {
-		s.Log.Crit("Failed to create LRU cache", "err", err)
-		return nil
-	}
-	return cache
+// RegisterMemory registers the caches of the store in the memory accountant.
+// All of them are used only by the API, so they are shrunk first.
+func (s *Store) RegisterMemory(a *membudget.Accountant) {
+	a.Register("evm/receipts", membudget.PriorityLow, membudget.Cache(s.cache.Receipts))
+	a.Register("evm/txpositions", membudget.PriorityLow, membudget.Cache(s.cache.TxPositions))
+	a.Register("evm/headers", membudget.PriorityLow, membudget.Cache(s.cache.EvmHeaders))
+	a.Register("evm/bodies", membudget.PriorityLow, membudget.Cache(s.cache.EvmBodies))
 }
 
 func (s *Store) initCarmen() error {
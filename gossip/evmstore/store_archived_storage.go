@@ -0,0 +1,145 @@
+package evmstore
+
+/*
+	Selective archive keeps the history of storage slots of the configured contracts:
+	  's' + address + slot + ^block -> value after the block (the latest write is iterated first)
+	  'p' + address + slot -> block + value before the first recorded write
+	  'd' + address + ^block -> destructed in the block
+	  'f' + address -> the first block with recorded history
+*/
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	archivedWritePrefix    = 's'
+	archivedFirstPrefix    = 'p'
+	archivedDestructPrefix = 'd'
+	archivedStartPrefix    = 'f'
+)
+
+func archivedKey(prefix byte, parts ...[]byte) []byte {
+	key := []byte{prefix}
+	for _, p := range parts {
+		key = append(key, p...)
+	}
+	return key
+}
+
+// IsArchivedContract returns true if the history of the contract storage is kept by the selective archive.
+func (s *Store) IsArchivedContract(addr common.Address) bool {
+	return s.archivedContracts[addr]
+}
+
+// SetArchivedStorage records the storage slot change made by the block.
+func (s *Store) SetArchivedStorage(n idx.Block, addr common.Address, slot common.Hash, prev, value common.Hash) {
+	firstKey := archivedKey(archivedFirstPrefix, addr.Bytes(), slot.Bytes())
+	if ok, err := s.table.ArchivedStorage.Has(firstKey); err != nil {
+		s.Log.Crit("Failed to get key", "err", err)
+	} else if !ok {
+		if err := s.table.ArchivedStorage.Put(firstKey, append(n.Bytes(), prev.Bytes()...)); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+
+	writeKey := archivedKey(archivedWritePrefix, addr.Bytes(), slot.Bytes(), (^n).Bytes())
+	if err := s.table.ArchivedStorage.Put(writeKey, value.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// SetArchivedDestruct records the contract destruction made by the block.
+func (s *Store) SetArchivedDestruct(n idx.Block, addr common.Address) {
+	key := archivedKey(archivedDestructPrefix, addr.Bytes(), (^n).Bytes())
+	if err := s.table.ArchivedStorage.Put(key, []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// SetArchiveStart records the first block of the contract history, unless it's recorded already.
+func (s *Store) SetArchiveStart(n idx.Block, addr common.Address) {
+	if s.archiveStarted[addr] {
+		return
+	}
+	start, err := s.GetArchiveStart(addr)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if start == nil {
+		key := archivedKey(archivedStartPrefix, addr.Bytes())
+		if err := s.table.ArchivedStorage.Put(key, n.Bytes()); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+	s.archiveStarted[addr] = true
+}
+
+// GetArchiveStart returns the first block of the contract history.
+func (s *Store) GetArchiveStart(addr common.Address) (*idx.Block, error) {
+	buf, err := s.table.ArchivedStorage.Get(archivedKey(archivedStartPrefix, addr.Bytes()))
+	if err != nil || buf == nil {
+		return nil, err
+	}
+	n := idx.BytesToBlock(buf)
+	return &n, nil
+}
+
+// lastArchivedBlock returns the latest block of records with the prefix, which isn't above the given block.
+func (s *Store) lastArchivedBlock(prefix []byte, n idx.Block) (block idx.Block, value []byte, ok bool) {
+	it := s.table.ArchivedStorage.NewIterator(prefix, (^n).Bytes())
+	defer it.Release()
+	if !it.Next() {
+		return 0, nil, false
+	}
+	key := it.Key()
+	return ^idx.BytesToBlock(key[len(key)-8:]), common.CopyBytes(it.Value()), true
+}
+
+// GetArchivedStorage returns the value of the contract storage slot after the given block.
+// If the slot isn't changed since the block, live is true and the current value applies.
+func (s *Store) GetArchivedStorage(n idx.Block, addr common.Address, slot common.Hash) (value common.Hash, live bool, err error) {
+	start, err := s.GetArchiveStart(addr)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	if start == nil || n+1 < *start {
+		return common.Hash{}, false, fmt.Errorf("storage of contract %s at block %d is not archived", addr.String(), n)
+	}
+
+	slotPrefix := archivedKey(archivedWritePrefix, addr.Bytes(), slot.Bytes())
+	destructPrefix := archivedKey(archivedDestructPrefix, addr.Bytes())
+
+	written, buf, wOk := s.lastArchivedBlock(slotPrefix, n)
+	destructed, _, dOk := s.lastArchivedBlock(destructPrefix, n)
+	if wOk {
+		if dOk && destructed > written {
+			return common.Hash{}, false, nil
+		}
+		return common.BytesToHash(buf), false, nil
+	}
+	if dOk {
+		return common.Hash{}, false, nil
+	}
+
+	// the slot isn't written at or before the block, so the value is the one before the first write
+	first, err := s.table.ArchivedStorage.Get(archivedKey(archivedFirstPrefix, addr.Bytes(), slot.Bytes()))
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	// storage of a destructed contract isn't enumerable, so a slot value before the destruction is unknown
+	unknownSince := idx.Block(1<<64 - 1)
+	if first != nil {
+		unknownSince = idx.BytesToBlock(first[:8])
+	}
+	if destructed, _, ok := s.lastArchivedBlock(destructPrefix, unknownSince); ok && destructed > n {
+		return common.Hash{}, false, fmt.Errorf("storage of contract %s at block %d is not archived", addr.String(), n)
+	}
+	if first == nil {
+		return common.Hash{}, true, nil
+	}
+	return common.BytesToHash(first[8:]), false, nil
+}
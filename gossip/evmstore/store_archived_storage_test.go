@@ -0,0 +1,79 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreArchivedStorage(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	contract := common.Address{1}
+	slot := common.Hash{2}
+	other := common.Hash{3}
+
+	cfg := StoreConfig{ArchivedContracts: []common.Address{contract}}
+	store := NewStore(memorydb.New(), cfg)
+	require.True(store.IsArchivedContract(contract))
+	require.False(store.IsArchivedContract(common.Address{2}))
+
+	get := func(n idx.Block, slot common.Hash) (common.Hash, bool, error) {
+		return store.GetArchivedStorage(n, contract, slot)
+	}
+
+	// history isn't started
+	_, _, err := get(10, slot)
+	require.Error(err)
+
+	store.SetArchiveStart(10, contract)
+	store.SetArchivedStorage(10, contract, slot, common.Hash{0xa}, common.Hash{0xb})
+	store.SetArchivedStorage(20, contract, slot, common.Hash{0xb}, common.Hash{0xc})
+
+	// before the archive start
+	_, _, err = get(8, slot)
+	require.Error(err)
+
+	for _, c := range []struct {
+		block idx.Block
+		value common.Hash
+	}{
+		{9, common.Hash{0xa}},
+		{10, common.Hash{0xb}},
+		{19, common.Hash{0xb}},
+		{20, common.Hash{0xc}},
+		{100, common.Hash{0xc}},
+	} {
+		value, live, err := get(c.block, slot)
+		require.NoError(err)
+		require.False(live)
+		require.Equal(c.value, value, c.block)
+	}
+
+	// not written slot has the current value
+	_, live, err := get(15, other)
+	require.NoError(err)
+	require.True(live)
+
+	// destruction
+	store.SetArchivedDestruct(30, contract)
+	value, _, err := get(30, slot)
+	require.NoError(err)
+	require.Equal(common.Hash{}, value)
+	value, _, err = get(25, slot)
+	require.NoError(err)
+	require.Equal(common.Hash{0xc}, value)
+	// value of a not written slot before the destruction is unknown
+	_, _, err = get(25, other)
+	require.Error(err)
+	value, live, err = get(30, other)
+	require.NoError(err)
+	require.False(live)
+	require.Equal(common.Hash{}, value)
+}
@@ -0,0 +1,85 @@
+package evmstore
+
+/*
+	Balance history keeps the balances changed by blocks:
+	  BalanceHistory: address + block -> balance after the block
+	  BalanceHistory: "r" -> first block + block after the last one of the contiguous range of recorded blocks
+*/
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BalanceChange is the balance of an account after the block which changed it.
+type BalanceChange struct {
+	Block   idx.Block
+	Balance *big.Int
+}
+
+var balanceHistoryRangeKey = []byte("r")
+
+func balanceHistoryKey(addr common.Address, block idx.Block) []byte {
+	key := make([]byte, 0, common.AddressLength+8)
+	key = append(key, addr.Bytes()...)
+	return append(key, block.Bytes()...)
+}
+
+// SetBalanceChange stores the balance of the account after the block.
+func (s *Store) SetBalanceChange(addr common.Address, block idx.Block, balance *big.Int) {
+	if err := s.table.BalanceHistory.Put(balanceHistoryKey(addr, block), balance.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetBalanceChanges returns the balance changes of the account in the blocks range [from, to] in chronological order.
+func (s *Store) GetBalanceChanges(addr common.Address, from, to idx.Block) []BalanceChange {
+	it := s.table.BalanceHistory.NewIterator(addr.Bytes(), from.Bytes())
+	defer it.Release()
+	var changes []BalanceChange
+	for it.Next() {
+		block := idx.BytesToBlock(it.Key()[common.AddressLength:])
+		if block > to {
+			break
+		}
+		changes = append(changes, BalanceChange{
+			Block:   block,
+			Balance: new(big.Int).SetBytes(it.Value()),
+		})
+	}
+	return changes
+}
+
+// BalanceHistoryRange returns the contiguous range [first, end) of the blocks, the balance changes of which are recorded.
+func (s *Store) BalanceHistoryRange() (first, end idx.Block) {
+	b, err := s.table.BalanceHistory.Get(balanceHistoryRangeKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(b) != 16 {
+		return 0, 0
+	}
+	return idx.Block(binary.BigEndian.Uint64(b[:8])), idx.Block(binary.BigEndian.Uint64(b[8:]))
+}
+
+// setBalanceHistoryBlock marks the block as recorded. If the block doesn't continue the recorded range,
+// e.g. the journal was disabled for a while, the range restarts from the block, as the history before it is incomplete.
+func (s *Store) setBalanceHistoryBlock(n idx.Block) {
+	first, end := s.BalanceHistoryRange()
+	switch {
+	case first <= n && n < end:
+		// reprocessed block
+	case n == end && first != end:
+		end++
+	default:
+		first, end = n, n+1
+	}
+	b := binary.BigEndian.AppendUint64(nil, uint64(first))
+	b = binary.BigEndian.AppendUint64(b, uint64(end))
+	if err := s.table.BalanceHistory.Put(balanceHistoryRangeKey, b); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
@@ -0,0 +1,63 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreBalanceHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+
+	addr := common.Address{1}
+	require.Empty(store.GetBalanceChanges(addr, 0, 1000))
+
+	changes := []BalanceChange{
+		{Block: 2, Balance: big.NewInt(100)},
+		{Block: 3, Balance: big.NewInt(0)},
+		{Block: 256, Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}
+	// stored out of order
+	for _, i := range []int{2, 0, 1} {
+		store.SetBalanceChange(addr, changes[i].Block, changes[i].Balance)
+	}
+	store.SetBalanceChange(common.Address{2}, 3, big.NewInt(1))
+
+	require.Equal(changes, store.GetBalanceChanges(addr, 0, 1000))
+	require.Equal(changes[1:2], store.GetBalanceChanges(addr, 3, 255))
+	require.Equal(changes[:2], store.GetBalanceChanges(addr, 2, 3))
+	require.Empty(store.GetBalanceChanges(addr, 4, 255))
+}
+
+func TestStoreBalanceHistoryRange(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+
+	first, end := store.BalanceHistoryRange()
+	require.Equal(first, end)
+
+	for n := idx.Block(5); n < 10; n++ {
+		store.setBalanceHistoryBlock(n)
+	}
+	store.setBalanceHistoryBlock(7)
+	first, end = store.BalanceHistoryRange()
+	require.Equal([2]idx.Block{5, 10}, [2]idx.Block{first, end})
+
+	// the history is incomplete once blocks are skipped
+	store.setBalanceHistoryBlock(12)
+	first, end = store.BalanceHistoryRange()
+	require.Equal([2]idx.Block{12, 13}, [2]idx.Block{first, end})
+
+	// the range isn't taken for a balance change
+	require.Empty(store.GetBalanceChanges(common.Address{'r'}, 0, 1000))
+}
@@ -0,0 +1,297 @@
+package evmstore_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	carmen "github.com/Fantom-foundation/Carmen/go/state"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore/evmstoretest"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+)
+
+// benchDatadirEnv is the environment variable with a path to a datadir, which the benchmarks
+// are additionally run against. It must be a copy of a real datadir, as the state is modified.
+const benchDatadirEnv = "SONIC_BENCH_DATADIR"
+
+const (
+	benchBlocks      = 200
+	benchTxsPerBlock = 30
+	// benchDatadirRange is the number of the latest blocks of the datadir the benchmarks read
+	benchDatadirRange = 1000
+)
+
+var erc20ApprovalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+func BenchmarkGetReceipts(b *testing.B) {
+	logger.SetTestMode(b)
+
+	b.Run("fixture", func(b *testing.B) {
+		f := evmstoretest.NewFixture(benchBlocks, benchTxsPerBlock)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			block := &f.Blocks[i%len(f.Blocks)]
+			if r, _ := f.Store.GetReceipts(block.Number, f.Signer, block.Hash, block.Txs); len(r) != len(block.Txs) {
+				b.Fatal("invalid result")
+			}
+		}
+	})
+	b.Run("fixture/single", func(b *testing.B) {
+		f := evmstoretest.NewFixture(benchBlocks, benchTxsPerBlock)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			block := &f.Blocks[i%len(f.Blocks)]
+			index := i % len(block.Txs)
+			if r, _ := f.Store.GetReceipt(block.Number, index, f.Signer, block.Hash, block.Txs[index]); r == nil {
+				b.Fatal("invalid result")
+			}
+		}
+	})
+	b.Run("datadir", func(b *testing.B) {
+		gdb := openBenchDatadir(b)
+		from, to := benchDatadirBlocks(gdb)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			gdb.EvmStore().GetRawReceipts(from + idx.Block(i)%(to-from+1))
+		}
+	})
+}
+
+func BenchmarkSetReceipts(b *testing.B) {
+	logger.SetTestMode(b)
+
+	f := evmstoretest.NewFixture(1, benchTxsPerBlock)
+	block := f.Blocks[0]
+	store := evmstore.NewMemStore()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		store.SetReceipts(idx.Block(i+1), block.Receipts)
+	}
+}
+
+func BenchmarkFindLogs(b *testing.B) {
+	logger.SetTestMode(b)
+
+	b.Run("fixture", func(b *testing.B) {
+		f := evmstoretest.NewFixture(benchBlocks, benchTxsPerBlock)
+		first := f.Blocks[0].Logs()[0]
+		// patterns of decreasing selectivity
+		patterns := map[string][][]common.Hash{
+			"every log":   {{}, {evmstoretest.TransferTopic}},
+			"one sender":  {{}, {evmstoretest.TransferTopic}, {first.Topics[1]}},
+			"one address": {{first.Address.Hash()}},
+			"no match":    {{}, {erc20ApprovalTopic}},
+		}
+		for name, pattern := range patterns {
+			b.Run(name, func(b *testing.B) {
+				benchFindLogs(b, f.Store.EvmLogs, 1, benchBlocks, pattern)
+			})
+		}
+	})
+	b.Run("datadir", func(b *testing.B) {
+		gdb := openBenchDatadir(b)
+		from, to := benchDatadirBlocks(gdb)
+		patterns := map[string][][]common.Hash{
+			"erc20 transfers": {{}, {evmstoretest.TransferTopic}},
+			"erc20 approvals": {{}, {erc20ApprovalTopic}},
+			"no match":        {{}, {common.Hash{1}}},
+		}
+		for name, pattern := range patterns {
+			b.Run(name, func(b *testing.B) {
+				benchFindLogs(b, gdb.EvmStore().EvmLogs, from, to, pattern)
+			})
+		}
+	})
+}
+
+func benchFindLogs(b *testing.B, index topicsdb.Index, from, to idx.Block, pattern [][]common.Hash) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := index.FindInBlocks(context.Background(), from, to, pattern); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStateDbOpen(b *testing.B) {
+	logger.SetTestMode(b)
+
+	b.Run("synthetic", func(b *testing.B) {
+		dir := b.TempDir()
+		store := openBenchStateDb(b, benchStateParams(dir))
+		live := benchLiveStateDb(b, store)
+		live.BeginBlock(1)
+		fillBenchState(live, 10000, 4)
+		live.EndBlock(1)
+		if err := store.Close(); err != nil {
+			b.Fatal(err)
+		}
+		benchStateDbOpen(b, benchStateParams(dir))
+	})
+	b.Run("datadir", func(b *testing.B) {
+		benchStateDbOpen(b, benchStateParams(filepath.Join(benchDatadir(b), "carmen")))
+	})
+}
+
+func benchStateDbOpen(b *testing.B, params carmen.Parameters) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store := openBenchStateDb(b, params)
+		if err := store.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStateDbCommit(b *testing.B) {
+	logger.SetTestMode(b)
+
+	for _, slots := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("synthetic/%d slots", slots), func(b *testing.B) {
+			store := openBenchStateDb(b, benchStateParams(b.TempDir()))
+			defer store.Close()
+			live := benchLiveStateDb(b, store)
+			live.BeginBlock(1)
+			fillBenchState(live, 10000, 4)
+			live.EndBlock(1)
+			benchStateDbCommit(b, live, 2, slots)
+		})
+	}
+	b.Run("datadir", func(b *testing.B) {
+		gdb := openBenchDatadir(b)
+		last := gdb.GetLatestBlockIndex()
+		live, err := gdb.EvmStore().GetLiveStateDb(gdb.GetBlock(last).Root)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchStateDbCommit(b, live, last+1, 100)
+	})
+}
+
+func benchStateDbCommit(b *testing.B, live state.StateDB, first idx.Block, slots int) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		block := uint64(first) + uint64(i)
+		live.BeginBlock(block)
+		for j := 0; j < slots; j++ {
+			addr := common.BigToAddress(big.NewInt(int64(j)))
+			live.SetState(addr, common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(int64(block))))
+		}
+		live.EndBlock(block)
+		if _, err := live.Commit(true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func fillBenchState(db state.StateDB, accounts, slots int) {
+	for i := 0; i < accounts; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i)))
+		db.CreateAccount(addr)
+		db.SetNonce(addr, 1)
+		db.AddBalance(addr, big.NewInt(1e18))
+		for j := 0; j < slots; j++ {
+			db.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.Hash{1})
+		}
+	}
+}
+
+func benchStateParams(dir string) carmen.Parameters {
+	params := evmstore.LiteStoreConfig().StateDb
+	params.Directory = dir
+	params.Archive = carmen.NoArchive
+	if stat, err := os.Stat(filepath.Join(dir, "archive")); err == nil && stat.IsDir() {
+		params.Archive = carmen.S5Archive
+	}
+	return params
+}
+
+func openBenchStateDb(b *testing.B, params carmen.Parameters) *evmstore.Store {
+	store := evmstore.NewStore(memorydb.New(), evmstore.StoreConfig{StateDb: params})
+	if err := store.Open(); err != nil {
+		b.Fatal(err)
+	}
+	return store
+}
+
+// benchLiveStateDb returns the live StateDB at whatever the current state root is.
+func benchLiveStateDb(b *testing.B, store *evmstore.Store) state.StateDB {
+	latest, err := store.GetTxPoolStateDB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	root, _ := latest.Commit(true)
+	latest.Release()
+	live, err := store.GetLiveStateDb(hash.Hash(root))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return live
+}
+
+// benchDatadir returns the datadir the benchmarks are run against, or skips the benchmark if it's not set.
+func benchDatadir(b *testing.B) string {
+	dir := os.Getenv(benchDatadirEnv)
+	if len(dir) == 0 {
+		b.Skip(benchDatadirEnv + " is not set")
+	}
+	return dir
+}
+
+// openBenchDatadir opens the datadir store with the EVM state, it's closed when the benchmark ends.
+func openBenchDatadir(b *testing.B) *gossip.Store {
+	dataDir := benchDatadir(b)
+	carmenDir := filepath.Join(dataDir, "carmen")
+
+	dbs, err := integration.GetDbProducer(filepath.Join(dataDir, "chaindata"), integration.DBCacheConfig{
+		Cache:   480 * opt.MiB,
+		Fdlimit: 100,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	cfg := gossip.DefaultStoreConfig(cachescale.Identity)
+	cfg.EVM.StateDb = benchStateParams(carmenDir)
+	gdb, err := gossip.NewStore(dbs, cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := gdb.EvmStore().Open(); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		gdb.Close()
+		dbs.Close()
+	})
+	return gdb
+}
+
+// benchDatadirBlocks returns the range of the latest blocks of the datadir.
+func benchDatadirBlocks(gdb *gossip.Store) (from, to idx.Block) {
+	to = gdb.GetLatestBlockIndex()
+	from = 1
+	if to > benchDatadirRange {
+		from = to - benchDatadirRange + 1
+	}
+	return from, to
+}
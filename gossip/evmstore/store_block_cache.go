@@ -3,23 +3,64 @@ package evmstore
 import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
 )
 
+// GetCachedEvmBlock returns the block composed of the cached header and body, or nil if any of them isn't cached.
 func (s *Store) GetCachedEvmBlock(n idx.Block) *evmcore.EvmBlock {
-	c, ok := s.cache.EvmBlocks.Get(n)
+	h, ok := s.cache.EvmHeaders.Get(n)
+	if !ok {
+		return nil
+	}
+	txs, ok := s.cache.EvmBodies.Get(n)
 	if !ok {
 		return nil
 	}
 
-	return c.(*evmcore.EvmBlock)
+	return &evmcore.EvmBlock{
+		EvmHeader:    *h,
+		Transactions: txs,
+	}
 }
 
+// SetCachedEvmBlock caches the header and the body of the completed block.
 func (s *Store) SetCachedEvmBlock(n idx.Block, b *evmcore.EvmBlock) {
 	var empty = common.Hash{}
 	if b.EvmHeader.TxHash == empty {
 		panic("You have to cache only completed blocks (with txs)")
 	}
-	s.cache.EvmBlocks.Add(n, b, uint(b.EstimateSize()))
+	h := b.EvmHeader
+	s.cache.EvmHeaders.Add(n, &h)
+	s.cache.EvmBodies.Add(n, b.Transactions)
+}
+
+// SetCachedEvmHeader caches the complete header (with txs root) of the block, the body of which isn't read.
+func (s *Store) SetCachedEvmHeader(n idx.Block, h *evmcore.EvmHeader) {
+	var empty = common.Hash{}
+	if h.TxHash == empty {
+		panic("You have to cache only complete headers (with txs root)")
+	}
+	s.cache.EvmHeaders.Add(n, h)
+}
+
+// GetCachedEvmHeader returns the cached header of the completed block.
+func (s *Store) GetCachedEvmHeader(n idx.Block) *evmcore.EvmHeader {
+	h, ok := s.cache.EvmHeaders.Get(n)
+	if !ok {
+		return nil
+	}
+
+	return h
+}
+
+// GetCachedEvmBody returns the cached transactions of the block.
+func (s *Store) GetCachedEvmBody(n idx.Block) types.Transactions {
+	txs, ok := s.cache.EvmBodies.Get(n)
+	if !ok {
+		return nil
+	}
+
+	return txs
 }
@@ -0,0 +1,84 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreCachedEvmBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	require.Nil(store.GetCachedEvmBlock(1))
+	require.Nil(store.GetCachedEvmHeader(1))
+	require.Nil(store.GetCachedEvmBody(1))
+
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewTransaction(1, common.Address{2}, big.NewInt(2), 21000, big.NewInt(1), []byte{1, 2, 3}),
+	}
+	block := evmcore.NewEvmBlock(&evmcore.EvmHeader{
+		Number: big.NewInt(1),
+		Hash:   common.Hash{0xaa},
+	}, txs)
+	store.SetCachedEvmBlock(1, block)
+
+	header := store.GetCachedEvmHeader(1)
+	require.NotNil(header)
+	require.Equal(block.Hash, header.Hash)
+	require.Equal(block.TxHash, header.TxHash)
+	require.Equal(txs, store.GetCachedEvmBody(1))
+
+	got := store.GetCachedEvmBlock(1)
+	require.NotNil(got)
+	require.Equal(block.EvmHeader, got.EvmHeader)
+	require.Equal(txs, got.Transactions)
+
+	// the block isn't composed if the body is evicted
+	store.cache.EvmBodies.Remove(1)
+	require.Nil(store.GetCachedEvmBlock(1))
+	require.NotNil(store.GetCachedEvmHeader(1))
+
+	// incomplete blocks can't be cached
+	require.Panics(func() {
+		store.SetCachedEvmBlock(2, &evmcore.EvmBlock{
+			EvmHeader: evmcore.EvmHeader{Number: big.NewInt(2)},
+		})
+	})
+}
+
+func TestStoreCachedEvmHeader(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	header := &evmcore.EvmHeader{
+		Number: big.NewInt(1),
+		Hash:   common.Hash{0xaa},
+		TxHash: types.EmptyRootHash,
+	}
+	store.SetCachedEvmHeader(1, header)
+	require.Equal(header, store.GetCachedEvmHeader(1))
+	// the block isn't composed without the body
+	require.Nil(store.GetCachedEvmBlock(1))
+
+	// incomplete headers can't be cached
+	require.Panics(func() {
+		store.SetCachedEvmHeader(2, &evmcore.EvmHeader{Number: big.NewInt(2)})
+	})
+
+	_, ok := store.GetTxRoot(1)
+	require.False(ok)
+	store.SetTxRoot(1, common.Hash{0xbb})
+	root, ok := store.GetTxRoot(1)
+	require.True(ok)
+	require.Equal(common.Hash{0xbb}, root)
+}
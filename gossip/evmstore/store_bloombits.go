@@ -0,0 +1,210 @@
+package evmstore
+
+/*
+	Bloombits index keeps the blooms of the blocks rotated into bit vectors per section, like the geth chain indexer:
+	  Blooms: block -> bloom of the block receipts
+	  BloomBits: section + bit -> compressed vector of the bit over the blocks of the section
+	  BloomBits: section -> nil, once all the bit vectors of the section are written
+	  BloomBits: "r" -> first section + section after the last one of the contiguous range of generated sections
+	  BloomBits: "b" -> first block + block after the last one of the contiguous range of blocks with indexed blooms
+*/
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BloomBitsSectionSize is a number of blocks in a section of the bloombits index.
+const BloomBitsSectionSize = 4096
+
+var (
+	bloomBitsRangeKey = []byte("r")
+	bloomsRangeKey    = []byte("b")
+)
+
+// ErrBloomBitsNotFound is returned if the bit vector of the section isn't indexed.
+var ErrBloomBitsNotFound = errors.New("bloombits section not found")
+
+func bloomBitsSectionKey(section uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, section)
+}
+
+func bloomBitsKey(section uint64, bit uint) []byte {
+	return binary.BigEndian.AppendUint16(bloomBitsSectionKey(section), uint16(bit))
+}
+
+// SetBlockBloom indexes the bloom of the block receipts. The empty blooms aren't stored.
+// Once all the blocks of a section are indexed, the bit vectors of the section are generated.
+// The sections with the blocks indexed before the indexing was enabled, or while it was disabled, are never generated,
+// as the missing blooms of such blocks would be taken for the empty ones.
+func (s *Store) SetBlockBloom(n idx.Block, receipts types.Receipts) {
+	if !s.cfg.BloomBitsIndexing {
+		return
+	}
+	if bloom := types.CreateBloom(receipts); bloom != (types.Bloom{}) {
+		if err := s.table.Blooms.Put(n.Bytes(), bloom.Bytes()); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+
+	s.bloomBitsMu.Lock()
+	first, end := s.bloomsRange()
+	switch {
+	case first <= n && n < end:
+		// reindexed block
+	case n == end && first != end:
+		end++
+	case n+1 == first:
+		first--
+	default:
+		first, end = n, n+1
+	}
+	s.setBloomsRange(first, end)
+	s.bloomBitsMu.Unlock()
+
+	section := uint64(n) / BloomBitsSectionSize
+	sectionFirst, sectionLast := idx.Block(section*BloomBitsSectionSize), idx.Block((section+1)*BloomBitsSectionSize-1)
+	if (n == sectionFirst || n == sectionLast) && first <= sectionFirst && sectionLast < end {
+		s.generateBloomBits(section)
+	}
+}
+
+// bloomsRange returns the contiguous range [first, end) of the blocks, the blooms of which are indexed.
+func (s *Store) bloomsRange() (first, end idx.Block) {
+	b, err := s.table.BloomBits.Get(bloomsRangeKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(b) != 16 {
+		return 0, 0
+	}
+	return idx.Block(binary.BigEndian.Uint64(b[:8])), idx.Block(binary.BigEndian.Uint64(b[8:]))
+}
+
+func (s *Store) setBloomsRange(first, end idx.Block) {
+	b := binary.BigEndian.AppendUint64(nil, uint64(first))
+	b = binary.BigEndian.AppendUint64(b, uint64(end))
+	if err := s.table.BloomBits.Put(bloomsRangeKey, b); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// getBlockBloom returns the bloom of the block, an empty one if the block has no receipts.
+func (s *Store) getBlockBloom(n idx.Block) types.Bloom {
+	b, err := s.table.Blooms.Get(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return types.BytesToBloom(b)
+}
+
+// generateBloomBits rotates the blooms of the section blocks into the bit vectors.
+func (s *Store) generateBloomBits(section uint64) {
+	gen, err := bloombits.NewGenerator(BloomBitsSectionSize)
+	if err != nil {
+		s.Log.Crit("Failed to create bloombits generator", "err", err)
+	}
+	for i := uint64(0); i < BloomBitsSectionSize; i++ {
+		if err := gen.AddBloom(uint(i), s.getBlockBloom(idx.Block(section*BloomBitsSectionSize+i))); err != nil {
+			s.Log.Crit("Failed to add bloom", "err", err)
+		}
+	}
+
+	batch := s.table.BloomBits.NewBatch()
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		bits, err := gen.Bitset(bit)
+		if err != nil {
+			s.Log.Crit("Failed to get bloombits", "err", err)
+		}
+		if err := batch.Put(bloomBitsKey(section, bit), bitutil.CompressBytes(bits)); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+	if err := batch.Put(bloomBitsSectionKey(section), []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	if err := batch.Write(); err != nil {
+		s.Log.Crit("Failed to write batch", "err", err)
+	}
+
+	s.bloomBitsMu.Lock()
+	defer s.bloomBitsMu.Unlock()
+	first, end := s.BloomBitsSections()
+	switch {
+	case first == end:
+		first, end = section, section+1
+	case section == end:
+		end++
+	case section+1 == first:
+		first--
+	case first <= section && section < end:
+		// regenerated section
+	default:
+		// the gap can't be filled later, so only the recent sections are kept in the range
+		first, end = section, section+1
+	}
+	s.setBloomBitsSections(first, end)
+}
+
+// BloomBitsSections returns the contiguous range [first, end) of the sections, the bit vectors of which are generated.
+func (s *Store) BloomBitsSections() (first, end uint64) {
+	b, err := s.table.BloomBits.Get(bloomBitsRangeKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(b) != 16 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+}
+
+func (s *Store) setBloomBitsSections(first, end uint64) {
+	b := binary.BigEndian.AppendUint64(nil, first)
+	b = binary.BigEndian.AppendUint64(b, end)
+	if err := s.table.BloomBits.Put(bloomBitsRangeKey, b); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetBloomBits returns the decompressed vector of the bloom bit over the blocks of the section.
+func (s *Store) GetBloomBits(bit uint, section uint64) ([]byte, error) {
+	compressed, err := s.table.BloomBits.Get(bloomBitsKey(section, bit))
+	if err != nil {
+		return nil, err
+	}
+	if len(compressed) == 0 {
+		// the vectors of zeros are compressed into empty values
+		if ok, err := s.table.BloomBits.Has(bloomBitsSectionKey(section)); err != nil || !ok {
+			return nil, ErrBloomBitsNotFound
+		}
+	}
+	return bitutil.DecompressBytes(compressed, BloomBitsSectionSize/8)
+}
+
+// DelBlockBloom removes the bloom of the block.
+// The bit vectors of the sections, all the blocks of which are not above the removed one, are removed too.
+func (s *Store) DelBlockBloom(n idx.Block) {
+	if err := s.table.Blooms.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	s.bloomBitsMu.Lock()
+	defer s.bloomBitsMu.Unlock()
+	first, end := s.BloomBitsSections()
+	for ; first < end && (first+1)*BloomBitsSectionSize-1 <= uint64(n); first++ {
+		if err := s.table.BloomBits.Delete(bloomBitsSectionKey(first)); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+		for bit := uint(0); bit < types.BloomBitLength; bit++ {
+			if err := s.table.BloomBits.Delete(bloomBitsKey(first, bit)); err != nil {
+				s.Log.Crit("Failed to delete key", "err", err)
+			}
+		}
+		s.setBloomBitsSections(first+1, end)
+	}
+}
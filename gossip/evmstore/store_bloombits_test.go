@@ -0,0 +1,94 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreBloomBits(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := NewStore(memorydb.New(), StoreConfig{BloomBitsIndexing: true})
+
+	addr := common.Address{1}
+	matched := idx.Block(BloomBitsSectionSize + 5)
+	for n := idx.Block(0); n < 3*BloomBitsSectionSize; n++ {
+		var receipts types.Receipts
+		if n == matched {
+			receipts = types.Receipts{{Logs: []*types.Log{{Address: addr}}}}
+		}
+		store.SetBlockBloom(n, receipts)
+		if n == 2*BloomBitsSectionSize-2 {
+			first, end := store.BloomBitsSections()
+			require.Equal([2]uint64{0, 1}, [2]uint64{first, end})
+		}
+	}
+	first, end := store.BloomBitsSections()
+	require.Equal([2]uint64{0, 3}, [2]uint64{first, end})
+
+	// the bits of the address bloom are set only for the matched block
+	bloom := types.BytesToBloom(nil)
+	bloom.Add(addr.Bytes())
+	for section := uint64(0); section < 3; section++ {
+		for bit := uint(0); bit < types.BloomBitLength; bit++ {
+			bits, err := store.GetBloomBits(bit, section)
+			require.NoError(err)
+			require.Len(bits, BloomBitsSectionSize/8)
+			expected := make([]byte, BloomBitsSectionSize/8)
+			if bloom[types.BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0 && uint64(matched)/BloomBitsSectionSize == section {
+				i := uint64(matched) % BloomBitsSectionSize
+				expected[i/8] |= 1 << (7 - i%8)
+			}
+			require.Equal(expected, bits, "section %d, bit %d", section, bit)
+		}
+	}
+
+	_, err := store.GetBloomBits(0, 3)
+	require.ErrorIs(err, ErrBloomBitsNotFound)
+
+	// pruning of the blocks removes the sections below them
+	store.DelBlockBloom(BloomBitsSectionSize - 2)
+	first, end = store.BloomBitsSections()
+	require.Equal([2]uint64{0, 3}, [2]uint64{first, end})
+	store.DelBlockBloom(2*BloomBitsSectionSize - 1)
+	first, end = store.BloomBitsSections()
+	require.Equal([2]uint64{2, 3}, [2]uint64{first, end})
+	_, err = store.GetBloomBits(0, 1)
+	require.ErrorIs(err, ErrBloomBitsNotFound)
+}
+
+func TestStoreBloomBitsPartialSections(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := NewStore(memorydb.New(), StoreConfig{BloomBitsIndexing: true})
+
+	// the indexing is enabled in the middle of the section 1
+	for n := idx.Block(BloomBitsSectionSize + 3); n < 3*BloomBitsSectionSize; n++ {
+		store.SetBlockBloom(n, nil)
+	}
+	first, end := store.BloomBitsSections()
+	require.Equal([2]uint64{2, 3}, [2]uint64{first, end})
+	_, err := store.GetBloomBits(0, 1)
+	require.ErrorIs(err, ErrBloomBitsNotFound)
+
+	// the blocks skipped while the indexing was disabled break the sections too
+	for n := idx.Block(3*BloomBitsSectionSize + 1); n < 5*BloomBitsSectionSize; n++ {
+		store.SetBlockBloom(n, nil)
+	}
+	first, end = store.BloomBitsSections()
+	require.Equal([2]uint64{4, 5}, [2]uint64{first, end})
+
+	// the block indexed backwards completes the section, once it's the first block of the section
+	store.SetBlockBloom(3*BloomBitsSectionSize, nil)
+	first, end = store.BloomBitsSections()
+	require.Equal([2]uint64{3, 5}, [2]uint64{first, end})
+}
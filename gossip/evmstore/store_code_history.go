@@ -0,0 +1,80 @@
+package evmstore
+
+/*
+	Code history keeps deployments and destructions of contracts:
+	  ContractCreations: address -> the latest deployment
+	  CodeHistory: address + block + tx index + kind -> tx hash
+*/
+
+import (
+	"encoding/binary"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CodeEventKind is a kind of the contract code change.
+type CodeEventKind byte
+
+const (
+	CodeCreated    CodeEventKind = 'c'
+	CodeDestructed CodeEventKind = 'd'
+)
+
+// ContractCreation describes the deployment of a contract.
+type ContractCreation struct {
+	TxHash  common.Hash
+	Creator common.Address
+	Block   idx.Block
+}
+
+// CodeEvent describes the deployment or the destruction of a contract.
+type CodeEvent struct {
+	Kind    CodeEventKind
+	Block   idx.Block
+	TxIndex uint32
+	TxHash  common.Hash
+}
+
+// SetContractCreation stores the deployment of the contract.
+func (s *Store) SetContractCreation(addr common.Address, c ContractCreation) {
+	s.rlp.Set(s.table.ContractCreations, addr.Bytes(), &c)
+}
+
+// GetContractCreation returns the latest deployment of the contract, or nil if the contract isn't indexed.
+func (s *Store) GetContractCreation(addr common.Address) (*ContractCreation, error) {
+	c, err := s.rlp.TryGet(s.table.ContractCreations, addr.Bytes(), &ContractCreation{})
+	if c == nil || err != nil {
+		return nil, err
+	}
+	return c.(*ContractCreation), nil
+}
+
+// SetCodeEvent stores the deployment or the destruction of the contract.
+func (s *Store) SetCodeEvent(addr common.Address, e CodeEvent) {
+	key := make([]byte, 0, common.AddressLength+8+4+1)
+	key = append(key, addr.Bytes()...)
+	key = append(key, e.Block.Bytes()...)
+	key = binary.BigEndian.AppendUint32(key, e.TxIndex)
+	key = append(key, byte(e.Kind))
+	if err := s.table.CodeHistory.Put(key, e.TxHash.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetCodeHistory returns the deployments and the destructions of the contract in chronological order.
+func (s *Store) GetCodeHistory(addr common.Address) []CodeEvent {
+	it := s.table.CodeHistory.NewIterator(addr.Bytes(), nil)
+	defer it.Release()
+	var events []CodeEvent
+	for it.Next() {
+		key := it.Key()[common.AddressLength:]
+		events = append(events, CodeEvent{
+			Block:   idx.BytesToBlock(key[:8]),
+			TxIndex: binary.BigEndian.Uint32(key[8:12]),
+			Kind:    CodeEventKind(key[12]),
+			TxHash:  common.BytesToHash(it.Value()),
+		})
+	}
+	return events
+}
@@ -0,0 +1,61 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreContractCreations(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+
+	contract := common.Address{1}
+	got, err := store.GetContractCreation(contract)
+	require.NoError(err)
+	require.Nil(got)
+
+	c := ContractCreation{
+		TxHash:  common.Hash{2},
+		Creator: common.Address{3},
+		Block:   4,
+	}
+	store.SetContractCreation(contract, c)
+	got, err = store.GetContractCreation(contract)
+	require.NoError(err)
+	require.Equal(&c, got)
+
+	// a malformed record is an error of the request rather than a crash of the node
+	require.NoError(store.table.ContractCreations.Put(common.Address{5}.Bytes(), []byte{0xff}))
+	got, err = store.GetContractCreation(common.Address{5})
+	require.Error(err)
+	require.Nil(got)
+}
+
+func TestStoreCodeHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+
+	contract := common.Address{1}
+	require.Empty(store.GetCodeHistory(contract))
+
+	events := []CodeEvent{
+		{Kind: CodeCreated, Block: 2, TxIndex: 0, TxHash: common.Hash{1}},
+		{Kind: CodeDestructed, Block: 2, TxIndex: 3, TxHash: common.Hash{2}},
+		{Kind: CodeCreated, Block: 256, TxIndex: 1, TxHash: common.Hash{3}},
+	}
+	// stored out of order
+	store.SetCodeEvent(contract, events[2])
+	store.SetCodeEvent(contract, events[0])
+	store.SetCodeEvent(contract, events[1])
+	store.SetCodeEvent(common.Address{2}, CodeEvent{Kind: CodeCreated, Block: 1, TxHash: common.Hash{4}})
+
+	require.Equal(events, store.GetCodeHistory(contract))
+}
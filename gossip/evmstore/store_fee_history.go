@@ -0,0 +1,21 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// SetBlockFeeHistory stores the fee history record of the block.
+func (s *Store) SetBlockFeeHistory(n idx.Block, h evmcore.BlockFeeHistory) {
+	s.rlp.Set(s.table.FeeHistory, n.Bytes(), &h)
+}
+
+// GetBlockFeeHistory returns the fee history record of the block, or nil if the block isn't indexed.
+func (s *Store) GetBlockFeeHistory(n idx.Block) (*evmcore.BlockFeeHistory, error) {
+	h, err := s.rlp.TryGet(s.table.FeeHistory, n.Bytes(), &evmcore.BlockFeeHistory{})
+	if h == nil || err != nil {
+		return nil, err
+	}
+	return h.(*evmcore.BlockFeeHistory), nil
+}
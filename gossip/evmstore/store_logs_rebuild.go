@@ -0,0 +1,140 @@
+package evmstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+)
+
+// logsRebuildCommitBlocks is the number of blocks, the progress of the logs index rebuild is saved after
+const logsRebuildCommitBlocks = 10000
+
+var ErrNoLocalLogsIndex = errors.New("logs index is remote or disabled")
+
+// BlockInfo returns the hash and the txs of the block, false if the block is missing.
+type BlockInfo func(n idx.Block) (hash common.Hash, txs types.Transactions, ok bool)
+
+// RebuildLogsIndex drops the logs index and indexes the logs of the stored receipts of the blocks up to the given one.
+// The progress is saved periodically, so an interrupted rebuild is resumed by the next call instead of starting over.
+func (s *Store) RebuildLogsIndex(ctx context.Context, to idx.Block, blockInfo BlockInfo) error {
+	if s.cfg.RemoteLogsIndex != "" || s.cfg.DisableLogsIndexing {
+		return ErrNoLocalLogsIndex
+	}
+
+	// the logs pushed before must not get into the index after it's dropped
+	if err := topicsdb.Flush(s.EvmLogs); err != nil {
+		return err
+	}
+	next, err := topicsdb.RebuildProgress(s.mainDB)
+	if err != nil {
+		return err
+	}
+	if next == 0 {
+		s.Log.Info("Dropping the logs index")
+		if err := topicsdb.Reset(s.mainDB); err != nil {
+			return err
+		}
+		next = 1
+		if err := topicsdb.SetRebuildProgress(s.mainDB, next); err != nil {
+			return err
+		}
+	} else {
+		s.Log.Info("Resuming the logs index rebuild", "block", next)
+	}
+
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		first   = next
+		blocks  = 0
+		indexed = 0
+		unwrap  = s.EvmLogs.WrapTablesAsBatched()
+	)
+	commit := func(next idx.Block) error {
+		unwrap()
+		if err := topicsdb.Flush(s.EvmLogs); err != nil {
+			return err
+		}
+		return topicsdb.SetRebuildProgress(s.mainDB, next)
+	}
+	for n := next; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			if err := commit(n); err != nil {
+				return err
+			}
+			s.Log.Warn("Logs index rebuild is interrupted, run it again to resume", "block", n)
+			return err
+		}
+		if n%logsRebuildCommitBlocks == 0 {
+			if err := commit(n); err != nil {
+				return err
+			}
+			unwrap = s.EvmLogs.WrapTablesAsBatched()
+		}
+		if time.Since(logged) >= 8*time.Second {
+			s.Log.Info("Rebuilding the logs index", "block", n, "to", to, "logs", indexed,
+				"elapsed", common.PrettyDuration(time.Since(start)), "eta", rebuildEta(start, first, n, to))
+			logged = time.Now()
+		}
+
+		raw, _ := s.GetRawReceipts(n)
+		if len(raw) == 0 {
+			continue
+		}
+		hash, txs, ok := blockInfo(n)
+		if !ok {
+			s.Log.Warn("Block is missing, its logs aren't indexed", "block", n)
+			continue
+		}
+		receipts, err := UnwrapStorageReceipts(raw, n, nil, hash, txs)
+		if err != nil {
+			s.Log.Warn("Failed to derive the receipts, their logs aren't indexed", "block", n, "err", err)
+			continue
+		}
+		for _, r := range receipts {
+			if err := s.EvmLogs.Push(r.Logs...); err != nil {
+				unwrap()
+				return err
+			}
+			indexed += len(r.Logs)
+		}
+		blocks++
+	}
+	if err := commit(0); err != nil {
+		return err
+	}
+	s.Log.Info("Logs index is rebuilt", "blocks", blocks, "logs", indexed, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// MigrateLogsIndex converts the logs index of the older releases into the current layout,
+// the index serves the queries once it's migrated. An interrupted migration is resumed by the next call.
+// It must not be called concurrently with the logs queries.
+func (s *Store) MigrateLogsIndex(ctx context.Context) error {
+	if s.cfg.RemoteLogsIndex != "" || s.cfg.DisableLogsIndexing {
+		return ErrNoLocalLogsIndex
+	}
+	// the logs pushed before are indexed in the current layout
+	if err := topicsdb.Flush(s.EvmLogs); err != nil {
+		return err
+	}
+	if err := topicsdb.MigrateLegacy(ctx, s.mainDB); err != nil {
+		return err
+	}
+	s.EvmLogs = topicsdb.UnwrapUnavailable(s.EvmLogs, topicsdb.ErrLegacyIndex)
+	return nil
+}
+
+func rebuildEta(start time.Time, first, n, to idx.Block) common.PrettyDuration {
+	if n <= first {
+		return 0
+	}
+	perBlock := time.Since(start) / time.Duration(n-first)
+	return common.PrettyDuration(perBlock * time.Duration(to-n))
+}
@@ -0,0 +1,122 @@
+package evmstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+)
+
+func TestStoreRebuildLogsIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	addr := common.Address{1}
+	txs := make(map[idx.Block]types.Transactions)
+	for n := idx.Block(1); n <= 3; n++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(n)})
+		txs[n] = types.Transactions{tx}
+		store.SetReceipts(n, types.Receipts{{
+			Status: types.ReceiptStatusSuccessful,
+			Logs: []*types.Log{{
+				Address: addr,
+				Topics:  []common.Hash{{0xaa}},
+			}},
+		}})
+	}
+	blockInfo := func(n idx.Block) (common.Hash, types.Transactions, bool) {
+		return common.Hash{byte(n)}, txs[n], txs[n] != nil
+	}
+	// a stale log, which isn't in the receipts
+	store.IndexLogs(&types.Log{Address: addr, BlockNumber: 2, TxHash: common.Hash{0xff}})
+
+	find := func() []*types.Log {
+		logs, err := store.EvmLogs.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{addr.Hash()}})
+		require.NoError(err)
+		return logs
+	}
+
+	require.NoError(store.RebuildLogsIndex(context.Background(), 3, blockInfo))
+	logs := find()
+	require.Equal(3, len(logs))
+	for i, l := range logs {
+		n := idx.Block(i + 1)
+		require.Equal(uint64(n), l.BlockNumber)
+		require.Equal(common.Hash{byte(n)}, l.BlockHash)
+		require.Equal(txs[n][0].Hash(), l.TxHash)
+	}
+	next, err := topicsdb.RebuildProgress(store.mainDB)
+	require.NoError(err)
+	require.Zero(next)
+
+	// an interrupted rebuild is resumed from the saved block
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(store.RebuildLogsIndex(ctx, 3, blockInfo), context.Canceled)
+	next, err = topicsdb.RebuildProgress(store.mainDB)
+	require.NoError(err)
+	require.Equal(idx.Block(1), next)
+	require.Empty(find())
+
+	require.NoError(topicsdb.SetRebuildProgress(store.mainDB, 3))
+	require.NoError(store.RebuildLogsIndex(context.Background(), 3, blockInfo))
+	logs = find()
+	require.Equal(1, len(logs))
+	require.Equal(uint64(3), logs[0].BlockNumber)
+}
+
+func TestStoreMigrateLogsIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	// a key of the logs index of an older release
+	db := memorydb.New()
+	require.NoError(db.Put(append([]byte("t"), make([]byte, common.HashLength+1+8+common.HashLength+8)...), []byte{0}))
+	find := func(store *Store) error {
+		_, err := store.EvmLogs.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{{}}})
+		return err
+	}
+
+	// the queries are refused until the index is migrated
+	store := NewStore(db, StoreConfig{})
+	require.ErrorIs(find(store), topicsdb.ErrLegacyIndex)
+	require.NoError(store.MigrateLogsIndex(context.Background()))
+	require.NoError(find(store))
+	store.EvmLogs.Close()
+
+	store = NewStore(db, StoreConfig{})
+	require.NoError(find(store))
+	store.EvmLogs.Close()
+}
+
+func TestStoreUnfinishedLogsIndexRebuild(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	db := memorydb.New()
+	require.NoError(topicsdb.SetRebuildProgress(db, 2))
+	find := func(store *Store) error {
+		_, err := store.EvmLogs.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{{}}})
+		return err
+	}
+
+	// the queries are refused until the rebuild is finished
+	store := NewStore(db, StoreConfig{})
+	require.ErrorIs(find(store), topicsdb.ErrUnfinishedRebuild)
+	require.NoError(store.RebuildLogsIndex(context.Background(), 1, func(idx.Block) (common.Hash, types.Transactions, bool) {
+		return common.Hash{}, nil, false
+	}))
+	store.EvmLogs.Close()
+
+	store = NewStore(db, StoreConfig{})
+	require.NoError(find(store))
+	store.EvmLogs.Close()
+}
@@ -5,9 +5,14 @@ package evmstore
 */
 
 import (
+	"bytes"
+	"fmt"
+	"math/big"
+
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -18,10 +23,10 @@ func (s *Store) SetReceipts(n idx.Block, receipts types.Receipts) {
 		receiptsStorage[i] = (*types.ReceiptForStorage)(r)
 	}
 
-	size := s.SetRawReceipts(n, receiptsStorage)
+	s.SetRawReceipts(n, receiptsStorage)
 
 	// Add to LRU cache.
-	s.cache.Receipts.Add(n, receipts, uint(size))
+	s.cache.Receipts.Add(n, receipts)
 }
 
 // SetRawReceipts stores raw transaction receipts.
@@ -34,6 +39,7 @@ func (s *Store) SetRawReceipts(n idx.Block, receipts []*types.ReceiptForStorage)
 	if err := s.table.Receipts.Put(n.Bytes(), buf); err != nil {
 		s.Log.Crit("Failed to put key-value", "err", err)
 	}
+	s.releaseQuarantine(ReceiptsTable, n)
 
 	// Remove from LRU cache.
 	s.cache.Receipts.Remove(n)
@@ -41,28 +47,73 @@ func (s *Store) SetRawReceipts(n idx.Block, receipts []*types.ReceiptForStorage)
 	return len(buf)
 }
 
-func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
+// GetRawReceiptsRLP returns RLP of the stored receipts of the block.
+func (s *Store) GetRawReceiptsRLP(n idx.Block) (rlp.RawValue, error) {
 	buf, err := s.table.Receipts.Get(n.Bytes())
 	if err != nil {
-		s.Log.Crit("Failed to get key-value", "err", err)
+		return nil, fmt.Errorf("failed to get receipts of block %d: %w", n, err)
 	}
-	return buf
+	return buf, nil
 }
 
 func (s *Store) GetRawReceipts(n idx.Block) ([]*types.ReceiptForStorage, int) {
-	buf := s.GetRawReceiptsRLP(n)
+	buf, err := s.GetRawReceiptsRLP(n)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
 	if buf == nil {
 		return nil, 0
 	}
 
-	var receiptsStorage []*types.ReceiptForStorage
-	err := rlp.DecodeBytes(buf, &receiptsStorage)
+	receiptsStorage, err := DecodeRawReceipts(buf)
 	if err != nil {
-		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
+		s.Log.Error("Failed to decode rlp", "block", n, "err", err, "size", len(buf))
+		s.Quarantine(ReceiptsTable, n)
+		return nil, 0
 	}
 	return receiptsStorage, len(buf)
 }
 
+// ReplacedLogs returns the logs of the stored receipts of the block, if they differ from the given ones,
+// e.g. the receipts indexed by a block record are replaced by the receipts of the processed block.
+// The logs are marked as removed. It returns nil if no receipts of the block are stored.
+func (s *Store) ReplacedLogs(n idx.Block, receipts []*types.ReceiptForStorage, hash common.Hash, txs types.Transactions) []*types.Log {
+	buf, err := s.GetRawReceiptsRLP(n)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return nil
+	}
+	if replacement, err := rlp.EncodeToBytes(receipts); err == nil && bytes.Equal(buf, replacement) {
+		return nil
+	}
+	stored, err := DecodeRawReceipts(buf)
+	if err != nil {
+		return nil
+	}
+	// the txs may mismatch the stored receipts, then the logs get only the block fields
+	replaced, _ := UnwrapStorageReceipts(stored, n, nil, hash, txs)
+	var logs []*types.Log
+	for _, r := range replaced {
+		for _, l := range r.Logs {
+			l.BlockNumber = uint64(n)
+			l.BlockHash = hash
+			l.Index = uint(len(logs))
+			l.Removed = true
+			logs = append(logs, l)
+		}
+	}
+	return logs
+}
+
+// DecodeRawReceipts decodes RLP of receipts stored by SetRawReceipts.
+func DecodeRawReceipts(buf []byte) ([]*types.ReceiptForStorage, error) {
+	var receiptsStorage []*types.ReceiptForStorage
+	err := rlp.DecodeBytes(buf, &receiptsStorage)
+	return receiptsStorage, err
+}
+
 func UnwrapStorageReceipts(receiptsStorage []*types.ReceiptForStorage, n idx.Block, signer types.Signer, hash common.Hash, txs types.Transactions) (types.Receipts, error) {
 	receipts := make(types.Receipts, len(receiptsStorage))
 	for i, r := range receiptsStorage {
@@ -73,23 +124,186 @@ func UnwrapStorageReceipts(receiptsStorage []*types.ReceiptForStorage, n idx.Blo
 }
 
 // GetReceipts returns stored transaction receipts.
-func (s *Store) GetReceipts(n idx.Block, signer types.Signer, hash common.Hash, txs types.Transactions) types.Receipts {
+// The receipts which can't be derived for the given txs, e.g. the pruned ones, are returned as nil.
+func (s *Store) GetReceipts(n idx.Block, signer types.Signer, hash common.Hash, txs types.Transactions) (types.Receipts, error) {
 	// Get data from LRU cache first.
 	if s.cache.Receipts != nil {
 		if c, ok := s.cache.Receipts.Get(n); ok {
-			return c.(types.Receipts)
+			receiptsCacheHitMeter.Mark(1)
+			return c, nil
 		}
+		receiptsCacheMissMeter.Mark(1)
 	}
 
-	receiptsStorage, size := s.GetRawReceipts(n)
+	buf, err := s.GetRawReceiptsRLP(n)
+	if err != nil {
+		return nil, err
+	}
+	var receiptsStorage []*types.ReceiptForStorage
+	if buf != nil {
+		receiptsStorage, err = DecodeRawReceipts(buf)
+		if err != nil {
+			s.Log.Error("Failed to decode rlp", "block", n, "err", err, "size", len(buf))
+			s.Quarantine(ReceiptsTable, n)
+			return nil, &QuarantinedError{Table: ReceiptsTable, Block: n}
+		}
+	}
 
 	receipts, err := UnwrapStorageReceipts(receiptsStorage, n, signer, hash, txs)
 	if err != nil {
-		s.Log.Crit("Failed to derive receipts", "err", err)
+		s.Log.Error("Failed to derive receipts", "block", n, "err", err)
+		return nil, nil
 	}
 
 	// Add to LRU cache.
-	s.cache.Receipts.Add(n, receipts, uint(size))
+	s.cache.Receipts.Add(n, receipts)
+
+	return receipts, nil
+}
 
-	return receipts
+// receiptsMemSize is an estimated memory size of the decoded receipts.
+func receiptsMemSize(_ idx.Block, receipts types.Receipts) uint {
+	size := uint(0)
+	for _, r := range receipts {
+		size += receiptMemSize
+		for _, l := range r.Logs {
+			size += logMemSize + uint(len(l.Topics))*common.HashLength + uint(len(l.Data))
+		}
+	}
+	return size
+}
+
+// GetReceipt returns the stored receipt of the transaction at the given index of the block.
+// Unless the block receipts are cached, only the requested receipt is decoded,
+// the preceding ones are scanned in place for their cumulative gas used and the number of logs.
+// A DB error is returned to the caller rather than halting the node, as the method serves RPC only.
+func (s *Store) GetReceipt(n idx.Block, index int, signer types.Signer, hash common.Hash, tx *types.Transaction) (*types.Receipt, error) {
+	if s.cache.Receipts != nil {
+		if receipts, ok := s.cache.Receipts.Get(n); ok {
+			receiptsCacheHitMeter.Mark(1)
+			if index >= len(receipts) {
+				return nil, nil
+			}
+			return receipts[index], nil
+		}
+		receiptsCacheMissMeter.Mark(1)
+	}
+
+	if err := s.CheckQuarantine(ReceiptsTable, n); err != nil {
+		return nil, err
+	}
+	buf, err := s.GetRawReceiptsRLP(n)
+	if err != nil {
+		return nil, err
+	}
+	if buf == nil {
+		return nil, nil
+	}
+	raw, prevGasUsed, logIndex, err := scanRawReceipts(buf, index)
+	if err != nil {
+		s.Log.Error("Failed to scan receipts rlp", "block", n, "index", index, "err", err, "size", len(buf))
+		s.Quarantine(ReceiptsTable, n)
+		return nil, &QuarantinedError{Table: ReceiptsTable, Block: n}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	stored := new(types.ReceiptForStorage)
+	if err := rlp.DecodeBytes(raw, stored); err != nil {
+		s.Log.Error("Failed to decode rlp", "block", n, "index", index, "err", err, "size", len(raw))
+		s.Quarantine(ReceiptsTable, n)
+		return nil, &QuarantinedError{Table: ReceiptsTable, Block: n}
+	}
+
+	// derive the fields the same way as Receipts.DeriveFields does
+	r := (*types.Receipt)(stored)
+	r.Type = tx.Type()
+	r.TxHash = tx.Hash()
+	r.BlockHash = hash
+	r.BlockNumber = new(big.Int).SetUint64(uint64(n))
+	r.TransactionIndex = uint(index)
+	if signer != nil && tx.To() == nil {
+		from, _ := types.Sender(signer, tx)
+		r.ContractAddress = crypto.CreateAddress(from, tx.Nonce())
+	}
+	r.GasUsed = r.CumulativeGasUsed - prevGasUsed
+	for _, l := range r.Logs {
+		l.BlockNumber = uint64(n)
+		l.BlockHash = hash
+		l.TxHash = r.TxHash
+		l.TxIndex = uint(index)
+		l.Index = logIndex
+		logIndex++
+	}
+	return r, nil
+}
+
+// scanRawReceipts finds the encoded receipt at the given index within RLP of the block receipts.
+// The returned slice refers to the buffer. Returns the cumulative gas used and the number of logs
+// of the preceding receipts, which are required to derive the receipt fields.
+// Returns nil slice if there's no receipt at the index.
+func scanRawReceipts(buf []byte, index int) (raw []byte, prevGasUsed uint64, logIndex uint, err error) {
+	list, _, err := rlp.SplitList(buf)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for i := 0; len(list) != 0; i++ {
+		kind, content, rest, err := rlp.Split(list)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if kind != rlp.List {
+			return nil, 0, 0, fmt.Errorf("receipt %d isn't a list", i)
+		}
+		if i == index {
+			return list[:len(list)-len(rest)], prevGasUsed, logIndex, nil
+		}
+		gas, logs, err := scanStoredReceipt(content)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("receipt %d: %w", i, err)
+		}
+		prevGasUsed = gas
+		logIndex += uint(logs)
+		list = rest
+	}
+	return nil, 0, 0, nil
+}
+
+// scanStoredReceipt returns the cumulative gas used and the number of logs of the encoded receipt, without decoding it.
+// All the storage formats accepted by types.ReceiptForStorage are supported.
+func scanStoredReceipt(content []byte) (cumulativeGasUsed uint64, logs int, err error) {
+	fields, err := rlp.CountValues(content)
+	if err != nil {
+		return 0, 0, err
+	}
+	var logsPos int
+	switch fields {
+	case 3: // status, cumulative gas, logs
+		logsPos = 2
+	case 6: // v4: status, cumulative gas, tx hash, contract address, logs, gas used
+		logsPos = 4
+	case 7: // v3: status, cumulative gas, bloom, tx hash, contract address, logs, gas used
+		logsPos = 5
+	default:
+		return 0, 0, fmt.Errorf("unexpected number of receipt fields %d", fields)
+	}
+	_, rest, err := rlp.SplitString(content)
+	if err != nil {
+		return 0, 0, err
+	}
+	cumulativeGasUsed, rest, err = rlp.SplitUint64(rest)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := 2; i < logsPos; i++ {
+		if _, _, rest, err = rlp.Split(rest); err != nil {
+			return 0, 0, err
+		}
+	}
+	logsList, _, err := rlp.SplitList(rest)
+	if err != nil {
+		return 0, 0, err
+	}
+	logs, err = rlp.CountValues(logsList)
+	return cumulativeGasUsed, logs, err
 }
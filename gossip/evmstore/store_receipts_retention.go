@@ -0,0 +1,46 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptsBlocks returns up to limit blocks not above the given one, the receipts of which are stored,
+// in ascending order.
+func (s *Store) ReceiptsBlocks(to idx.Block, limit int) []idx.Block {
+	var blocks []idx.Block
+	it := s.table.Receipts.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() && len(blocks) < limit {
+		if len(it.Key()) != 8 {
+			continue
+		}
+		n := idx.BytesToBlock(it.Key())
+		if n > to {
+			break
+		}
+		blocks = append(blocks, n)
+	}
+	return blocks
+}
+
+// DelReceipts removes the receipts of the block together with the positions of its txs, its indexed logs and bloom.
+// The receipts must be derived, so the txs hashes and the logs positions are known.
+func (s *Store) DelReceipts(n idx.Block, receipts types.Receipts) {
+	for _, r := range receipts {
+		if err := s.EvmLogs.Delete(r.Logs...); err != nil {
+			s.Log.Crit("Failed to delete logs index", "err", err)
+		}
+		if !s.cfg.DisableTxHashesIndexing {
+			s.DelTxPosition(r.TxHash)
+		}
+	}
+
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+	s.DelBlockBloom(n)
+
+	// Remove from LRU cache.
+	s.cache.Receipts.Remove(n)
+}
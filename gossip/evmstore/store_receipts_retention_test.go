@@ -0,0 +1,59 @@
+package evmstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreDelReceipts(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	addr := common.Address{1}
+	receiptsOf := func(n idx.Block) types.Receipts {
+		txHash := common.Hash{byte(n)}
+		return types.Receipts{{
+			Status: types.ReceiptStatusSuccessful,
+			TxHash: txHash,
+			Logs: []*types.Log{{
+				Address:     addr,
+				Topics:      []common.Hash{{0xaa}},
+				BlockNumber: uint64(n),
+				TxHash:      txHash,
+			}},
+		}}
+	}
+	for n := idx.Block(1); n <= 3; n++ {
+		receipts := receiptsOf(n)
+		store.SetReceipts(n, receipts)
+		store.SetTxPosition(receipts[0].TxHash, TxPosition{Block: n})
+		store.IndexLogs(receipts[0].Logs...)
+	}
+
+	require.Equal([]idx.Block{1, 2}, store.ReceiptsBlocks(2, 10))
+	require.Equal([]idx.Block{1}, store.ReceiptsBlocks(3, 1))
+
+	store.DelReceipts(1, receiptsOf(1))
+	require.Equal([]idx.Block{2, 3}, store.ReceiptsBlocks(3, 10))
+	receiptsRLP, err := store.GetRawReceiptsRLP(1)
+	require.NoError(err)
+	require.Nil(receiptsRLP)
+	pos, err := store.GetTxPosition(common.Hash{1})
+	require.NoError(err)
+	require.Nil(pos)
+	pos, err = store.GetTxPosition(common.Hash{2})
+	require.NoError(err)
+	require.NotNil(pos)
+
+	logs, err := store.EvmLogs.FindInBlocks(context.Background(), 0, 3, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Equal(2, len(logs))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-opera/logger"
@@ -103,3 +104,45 @@ func fakeReceipts() (idx.Block, []*types.ReceiptForStorage) {
 			},
 		}
 }
+
+// FuzzGetRawReceipts checks that malformed stored receipts are reported as missing instead of crashing the node.
+func FuzzGetRawReceipts(f *testing.F) {
+	_, receipts := fakeReceipts()
+	buf, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		logger.SetTestMode(t)
+		store := nonCachedStore()
+		if err := store.table.Receipts.Put(idx.Block(1).Bytes(), data); err != nil {
+			t.Fatal(err)
+		}
+		decoded, _ := store.GetRawReceipts(1)
+		if _, err := DecodeRawReceipts(data); err != nil && decoded != nil {
+			t.Fatal("malformed receipts are returned")
+		}
+		_, _ = store.GetReceipts(1, types.HomesteadSigner{}, common.Hash{}, nil)
+	})
+}
+
+func TestStoreGetMalformedTxRecords(t *testing.T) {
+	logger.SetTestMode(t)
+	store := nonCachedStore()
+
+	txid := common.Hash{1}
+	if err := store.table.TxPositions.Put(txid.Bytes(), []byte{0xff}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.table.Txs.Put(txid.Bytes(), []byte{0xff}); err != nil {
+		t.Fatal(err)
+	}
+
+	pos, err := store.GetTxPosition(txid)
+	assert.Error(t, err)
+	assert.Nil(t, pos)
+	tx, err := store.GetTx(txid)
+	assert.Error(t, err)
+	assert.Nil(t, tx)
+}
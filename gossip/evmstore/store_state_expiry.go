@@ -0,0 +1,227 @@
+package evmstore
+
+/*
+	State expiry experiment tracks the last epoch in which accounts and storage slots were touched:
+	  'a' + address -> epoch of the last access of the account
+	  's' + address + slot -> epoch of the last access of the storage slot
+	  'b' + epoch + 'a' + address, 'b' + epoch + 's' + address + slot -> accessed in the epoch
+	  'n' -> numbers of expired accounts and slots
+*/
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
+)
+
+const (
+	accessAccountPrefix = 'a'
+	accessSlotPrefix    = 's'
+	accessBucketPrefix  = 'b'
+)
+
+var accessCountersKey = []byte{'n'}
+
+// stateExpiryQueueSize is the number of the blocks accesses and the epochs expirations queued to the worker
+const stateExpiryQueueSize = 256
+
+var (
+	expiredAccountsGauge   = metrics.GetOrRegisterGauge("statedb/expiry/accounts", nil)
+	expiredSlotsGauge      = metrics.GetOrRegisterGauge("statedb/expiry/slots", nil)
+	revivedAccountsCounter = metrics.GetOrRegisterCounter("statedb/expiry/revived/accounts", nil)
+	revivedSlotsCounter    = metrics.GetOrRegisterCounter("statedb/expiry/revived/slots", nil)
+	stateExpiryQueueGauge  = metrics.GetOrRegisterGauge("statedb/expiry/queue", nil)
+)
+
+// ExpiredStateStats is the size of the expired state.
+type ExpiredStateStats struct {
+	Accounts uint64
+	Slots    uint64
+}
+
+// GetExpiredStateStats returns the size of the expired state.
+func (s *Store) GetExpiredStateStats() ExpiredStateStats {
+	stats, _ := s.rlp.Get(s.table.StateAccess, accessCountersKey, &ExpiredStateStats{}).(*ExpiredStateStats)
+	if stats == nil {
+		return ExpiredStateStats{}
+	}
+	return *stats
+}
+
+func (s *Store) setExpiredStateStats(stats ExpiredStateStats) {
+	s.rlp.Set(s.table.StateAccess, accessCountersKey, &stats)
+	expiredAccountsGauge.Update(int64(stats.Accounts))
+	expiredSlotsGauge.Update(int64(stats.Slots))
+}
+
+// touch records the access to the account (or to the storage slot) in the epoch into the batch.
+// It returns true if the accessed state was expired.
+func (s *Store) touch(batch kvdb.Batch, epoch, expiry idx.Epoch, key []byte) (revived bool) {
+	buf, err := s.table.StateAccess.Get(key)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf != nil {
+		last := idx.BytesToEpoch(buf)
+		if last == epoch {
+			return false
+		}
+		// the state is expired by ExpireState of the epoch before the current one
+		revived = last+expiry+1 < epoch
+	}
+	if err := batch.Put(key, epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	bucketKey := append(append([]byte{accessBucketPrefix}, epoch.Bytes()...), key...)
+	if err := batch.Put(bucketKey, []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	return revived
+}
+
+// TouchState records accesses to the accounts and storage slots made in the epoch.
+// Accessing the expired state revives it. The accesses are written in a single batch.
+func (s *Store) TouchState(epoch, expiry idx.Epoch, accounts map[common.Address]bool, slots map[slotKey]bool) {
+	var revivedAccounts, revivedSlots uint64
+	batch := s.table.StateAccess.NewBatch()
+	for addr := range accounts {
+		if s.touch(batch, epoch, expiry, append([]byte{accessAccountPrefix}, addr.Bytes()...)) {
+			revivedAccounts++
+		}
+	}
+	for key := range slots {
+		if s.touch(batch, epoch, expiry, append(append([]byte{accessSlotPrefix}, key.addr.Bytes()...), key.slot.Bytes()...)) {
+			revivedSlots++
+		}
+	}
+	if err := batch.Write(); err != nil {
+		s.Log.Crit("Failed to write batch", "err", err)
+	}
+	if revivedAccounts == 0 && revivedSlots == 0 {
+		return
+	}
+	stats := s.GetExpiredStateStats()
+	stats.Accounts -= min(stats.Accounts, revivedAccounts)
+	stats.Slots -= min(stats.Slots, revivedSlots)
+	s.setExpiredStateStats(stats)
+	revivedAccountsCounter.Inc(int64(revivedAccounts))
+	revivedSlotsCounter.Inc(int64(revivedSlots))
+}
+
+// ExpireState marks the accounts and storage slots, which are untouched since the epoch-expiry, as expired.
+func (s *Store) ExpireState(epoch, expiry idx.Epoch) {
+	if epoch <= expiry {
+		return
+	}
+	last := epoch - expiry - 1
+
+	stats := s.GetExpiredStateStats()
+	// the expired buckets are counted first and then deleted at once
+	var first []byte
+	it := s.table.StateAccess.NewIterator([]byte{accessBucketPrefix}, nil)
+	for it.Next() {
+		key := it.Key()
+		bucket := idx.BytesToEpoch(key[1:5])
+		if bucket > last {
+			break
+		}
+		if first == nil {
+			first = common.CopyBytes(key)
+		}
+		accessKey := key[5:]
+		buf, err := s.table.StateAccess.Get(accessKey)
+		if err != nil {
+			s.Log.Crit("Failed to get key-value", "err", err)
+		}
+		// the state is expired only if it's not accessed in a later epoch
+		if buf != nil && idx.BytesToEpoch(buf) == bucket {
+			if accessKey[0] == accessAccountPrefix {
+				stats.Accounts++
+			} else {
+				stats.Slots++
+			}
+		}
+	}
+	it.Release()
+	if first == nil {
+		return
+	}
+	limit := append([]byte{accessBucketPrefix}, (last + 1).Bytes()...)
+	if _, err := rangedel.DeleteRange(s.pruned.StateAccess, first, limit); err != nil {
+		s.Log.Crit("Failed to delete key range", "err", err)
+	}
+	s.setExpiredStateStats(stats)
+}
+
+// stateExpiryTask is a TouchState or an ExpireState call queued to the state expiry worker,
+// or a flush request if done is set.
+type stateExpiryTask struct {
+	epoch, expiry idx.Epoch
+	accounts      map[common.Address]bool
+	slots         map[slotKey]bool
+	expire        bool
+	done          chan struct{}
+}
+
+// startStateExpiry starts the worker, which runs the state expiry experiment off the blocks processing.
+// The queued calls are processed in order, so the result is the same as of the direct calls.
+func (s *Store) startStateExpiry() {
+	s.stateExpiry = make(chan stateExpiryTask, stateExpiryQueueSize)
+	s.stateExpiryStopped = make(chan struct{})
+	go func() {
+		defer close(s.stateExpiryStopped)
+		for task := range s.stateExpiry {
+			stateExpiryQueueGauge.Update(int64(len(s.stateExpiry)))
+			switch {
+			case task.done != nil:
+				close(task.done)
+			case task.expire:
+				s.ExpireState(task.epoch, task.expiry)
+			default:
+				s.TouchState(task.epoch, task.expiry, task.accounts, task.slots)
+			}
+		}
+	}()
+}
+
+// stopStateExpiry processes the queued calls and stops the worker.
+func (s *Store) stopStateExpiry() {
+	if s.stateExpiry == nil {
+		return
+	}
+	close(s.stateExpiry)
+	<-s.stateExpiryStopped
+	s.stateExpiry = nil
+}
+
+// queueStateExpiry queues the call to the worker, or runs it if the worker isn't started.
+// Once the queue is full, it blocks until there is a room in it.
+func (s *Store) queueStateExpiry(task stateExpiryTask) {
+	if s.stateExpiry != nil {
+		s.stateExpiry <- task
+		return
+	}
+	if task.expire {
+		s.ExpireState(task.epoch, task.expiry)
+	} else {
+		s.TouchState(task.epoch, task.expiry, task.accounts, task.slots)
+	}
+}
+
+// QueueExpireState calls ExpireState in background, after the queued accesses are recorded.
+func (s *Store) QueueExpireState(epoch, expiry idx.Epoch) {
+	s.queueStateExpiry(stateExpiryTask{epoch: epoch, expiry: expiry, expire: true})
+}
+
+// FlushStateExpiry waits until the queued accesses and expirations are processed.
+func (s *Store) FlushStateExpiry() {
+	if s.stateExpiry == nil {
+		return
+	}
+	done := make(chan struct{})
+	s.stateExpiry <- stateExpiryTask{done: done}
+	<-done
+}
@@ -0,0 +1,96 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreStateExpiry(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	const expiry = 2
+
+	a1 := common.Address{1}
+	a2 := common.Address{2}
+	slot := slotKey{a1, common.Hash{1}}
+
+	store.TouchState(1, expiry, map[common.Address]bool{a1: true, a2: true}, map[slotKey]bool{slot: true})
+	store.TouchState(2, expiry, map[common.Address]bool{a2: true}, nil)
+
+	// nothing is expired yet
+	store.ExpireState(3, expiry)
+	require.Equal(ExpiredStateStats{}, store.GetExpiredStateStats())
+
+	// a1 and the slot are untouched since epoch 1
+	store.ExpireState(4, expiry)
+	require.Equal(ExpiredStateStats{Accounts: 1, Slots: 1}, store.GetExpiredStateStats())
+
+	// a2 is untouched since epoch 2
+	store.ExpireState(5, expiry)
+	require.Equal(ExpiredStateStats{Accounts: 2, Slots: 1}, store.GetExpiredStateStats())
+
+	// accessing the slot revives it with the account
+	store.TouchState(5, expiry, map[common.Address]bool{a1: true}, map[slotKey]bool{slot: true})
+	require.Equal(ExpiredStateStats{Accounts: 1, Slots: 0}, store.GetExpiredStateStats())
+
+	// revived state expires again
+	store.ExpireState(8, expiry)
+	require.Equal(ExpiredStateStats{Accounts: 2, Slots: 1}, store.GetExpiredStateStats())
+}
+
+func TestStoreStateExpiryBoundary(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	const expiry = 2
+
+	a1 := common.Address{1}
+	a2 := common.Address{2}
+
+	store.TouchState(1, expiry, map[common.Address]bool{a1: true}, nil)
+	store.TouchState(2, expiry, map[common.Address]bool{a2: true}, nil)
+
+	// sealing of the epoch 4 expires a1, a2 is untouched for exactly expiry epochs and isn't expired
+	store.ExpireState(4, expiry)
+	require.Equal(ExpiredStateStats{Accounts: 1}, store.GetExpiredStateStats())
+
+	// touching a2 in the next epoch doesn't revive it
+	store.TouchState(5, expiry, map[common.Address]bool{a2: true}, nil)
+	require.Equal(ExpiredStateStats{Accounts: 1}, store.GetExpiredStateStats())
+
+	// touching a1 revives it
+	store.TouchState(5, expiry, map[common.Address]bool{a1: true}, nil)
+	require.Equal(ExpiredStateStats{}, store.GetExpiredStateStats())
+}
+
+func TestStoreStateExpiryQueue(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	const expiry = 2
+	store := NewStore(memorydb.New(), StoreConfig{StateExpiryEpochs: expiry})
+	defer store.Close()
+
+	a1 := common.Address{1}
+	a2 := common.Address{2}
+
+	// the queued calls are processed in order
+	store.queueStateExpiry(stateExpiryTask{epoch: 1, expiry: expiry, accounts: map[common.Address]bool{a1: true}})
+	store.queueStateExpiry(stateExpiryTask{epoch: 2, expiry: expiry, accounts: map[common.Address]bool{a2: true}})
+	store.QueueExpireState(4, expiry)
+	store.FlushStateExpiry()
+	require.Equal(ExpiredStateStats{Accounts: 1}, store.GetExpiredStateStats())
+
+	store.queueStateExpiry(stateExpiryTask{epoch: 5, expiry: expiry, accounts: map[common.Address]bool{a1: true}})
+	store.QueueExpireState(5, expiry)
+	store.FlushStateExpiry()
+	require.Equal(ExpiredStateStats{Accounts: 1}, store.GetExpiredStateStats())
+}
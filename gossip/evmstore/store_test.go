@@ -12,8 +12,5 @@ func cachedStore() *Store {
 }
 
 func nonCachedStore() *Store {
-	cfg := StoreConfig{}
-
-	store := NewStore(memorydb.New(), cfg)
-	return store
+	return NewMemStore()
 }
@@ -16,19 +16,25 @@ func (s *Store) SetTx(txid common.Hash, tx *types.Transaction) {
 }
 
 // GetTx returns stored non-event transaction.
-func (s *Store) GetTx(txid common.Hash) *types.Transaction {
-	tx, _ := s.rlp.Get(s.table.Txs, txid.Bytes(), &types.Transaction{}).(*types.Transaction)
-	return tx
+func (s *Store) GetTx(txid common.Hash) (*types.Transaction, error) {
+	tx, err := s.rlp.TryGet(s.table.Txs, txid.Bytes(), &types.Transaction{})
+	if tx == nil || err != nil {
+		return nil, err
+	}
+	return tx.(*types.Transaction), nil
 }
 
 func (s *Store) GetBlockTxs(n idx.Block, block inter.Block, getEventPayload func(hash.Event) *inter.EventPayload) types.Transactions {
-	if cached := s.GetCachedEvmBlock(n); cached != nil {
-		return cached.Transactions
+	if cached := s.GetCachedEvmBody(n); cached != nil {
+		return cached
 	}
 
 	transactions := make(types.Transactions, 0, len(block.Txs)+len(block.InternalTxs)+len(block.Events)*10)
 	for _, txid := range block.InternalTxs {
-		tx := s.GetTx(txid)
+		tx, err := s.GetTx(txid)
+		if err != nil {
+			log.Crit("Failed to get internal tx", "tx", txid.String(), "err", err)
+		}
 		if tx == nil {
 			log.Crit("Internal tx not found", "tx", txid.String())
 			continue
@@ -36,7 +42,10 @@ func (s *Store) GetBlockTxs(n idx.Block, block inter.Block, getEventPayload func
 		transactions = append(transactions, tx)
 	}
 	for _, txid := range block.Txs {
-		tx := s.GetTx(txid)
+		tx, err := s.GetTx(txid)
+		if err != nil {
+			log.Crit("Failed to get tx", "tx", txid.String(), "err", err)
+		}
 		if tx == nil {
 			log.Crit("Tx not found", "tx", txid.String())
 			continue
@@ -26,28 +26,40 @@ func (s *Store) SetTxPosition(txid common.Hash, position TxPosition) {
 	s.rlp.Set(s.table.TxPositions, txid.Bytes(), &position)
 
 	// Add to LRU cache.
-	s.cache.TxPositions.Add(txid.String(), &position, nominalSize)
+	s.cache.TxPositions.Add(txid, &position)
+}
+
+// DelTxPosition removes stored transaction block and position.
+func (s *Store) DelTxPosition(txid common.Hash) {
+	if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.TxPositions.Remove(txid)
 }
 
 // GetTxPosition returns stored transaction block and position.
-func (s *Store) GetTxPosition(txid common.Hash) *TxPosition {
+func (s *Store) GetTxPosition(txid common.Hash) (*TxPosition, error) {
 	if s.cfg.DisableTxHashesIndexing {
-		return nil
+		return nil, nil
 	}
 
 	// Get data from LRU cache first.
-	if c, ok := s.cache.TxPositions.Get(txid.String()); ok {
-		if b, ok := c.(*TxPosition); ok {
-			return b
-		}
+	if c, ok := s.cache.TxPositions.Get(txid); ok {
+		txPositionsCacheHitMeter.Mark(1)
+		return c, nil
 	}
+	txPositionsCacheMissMeter.Mark(1)
 
-	txPosition, _ := s.rlp.Get(s.table.TxPositions, txid.Bytes(), &TxPosition{}).(*TxPosition)
+	v, err := s.rlp.TryGet(s.table.TxPositions, txid.Bytes(), &TxPosition{})
+	if v == nil || err != nil {
+		return nil, err
+	}
+	txPosition := v.(*TxPosition)
 
 	// Add to LRU cache.
-	if txPosition != nil {
-		s.cache.TxPositions.Add(txid.String(), txPosition, nominalSize)
-	}
+	s.cache.TxPositions.Add(txid, txPosition)
 
-	return txPosition
+	return txPosition, nil
 }
@@ -0,0 +1,25 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SetTxRoot stores the transactions root of the block, so the header of the block is composed without reading its txs.
+func (s *Store) SetTxRoot(n idx.Block, root common.Hash) {
+	if err := s.table.TxRoots.Put(n.Bytes(), root.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetTxRoot returns the transactions root of the block, or false if it isn't stored.
+func (s *Store) GetTxRoot(n idx.Block) (common.Hash, bool) {
+	b, err := s.table.TxRoots.Get(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(b) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(b), true
+}
@@ -0,0 +1,62 @@
+package evmstore
+
+/*
+	Senders index keeps the positions of the txs of each sender:
+	  TxSenders: sender + block + tx index in block -> nil
+*/
+
+import (
+	"encoding/binary"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SenderTxPosition is a position of a transaction of a sender.
+type SenderTxPosition struct {
+	Block   idx.Block
+	TxIndex uint32
+}
+
+func txSenderKey(sender common.Address, block idx.Block, txIndex uint32) []byte {
+	key := make([]byte, common.AddressLength+8+4)
+	copy(key, sender.Bytes())
+	copy(key[common.AddressLength:], block.Bytes())
+	binary.BigEndian.PutUint32(key[common.AddressLength+8:], txIndex)
+	return key
+}
+
+// SetTxSender indexes the position of the tx of the sender.
+func (s *Store) SetTxSender(sender common.Address, block idx.Block, txIndex uint32) {
+	if s.cfg.DisableTxHashesIndexing {
+		return
+	}
+	if err := s.table.TxSenders.Put(txSenderKey(sender, block, txIndex), []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// DelTxSender removes the position of the tx of the sender.
+func (s *Store) DelTxSender(sender common.Address, block idx.Block, txIndex uint32) {
+	if err := s.table.TxSenders.Delete(txSenderKey(sender, block, txIndex)); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// GetTxsBySender returns up to limit positions of the txs of the sender in the blocks range [from, to] in chronological order.
+func (s *Store) GetTxsBySender(sender common.Address, from, to idx.Block, limit int) []SenderTxPosition {
+	it := s.table.TxSenders.NewIterator(sender.Bytes(), from.Bytes())
+	defer it.Release()
+	var txs []SenderTxPosition
+	for len(txs) < limit && it.Next() {
+		block := idx.BytesToBlock(it.Key()[common.AddressLength : common.AddressLength+8])
+		if block > to {
+			break
+		}
+		txs = append(txs, SenderTxPosition{
+			Block:   block,
+			TxIndex: binary.BigEndian.Uint32(it.Key()[common.AddressLength+8:]),
+		})
+	}
+	return txs
+}
@@ -0,0 +1,40 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreTxSenders(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+
+	sender := common.Address{1}
+	require.Empty(store.GetTxsBySender(sender, 0, 1000, 10))
+
+	txs := []SenderTxPosition{
+		{Block: 2, TxIndex: 0},
+		{Block: 2, TxIndex: 5},
+		{Block: 3, TxIndex: 1},
+		{Block: 256, TxIndex: 0},
+	}
+	// stored out of order
+	for _, i := range []int{3, 1, 0, 2} {
+		store.SetTxSender(sender, txs[i].Block, txs[i].TxIndex)
+	}
+	store.SetTxSender(common.Address{2}, 3, 0)
+
+	require.Equal(txs, store.GetTxsBySender(sender, 0, 1000, 10))
+	require.Equal(txs[:2], store.GetTxsBySender(sender, 0, 1000, 2))
+	require.Equal(txs[2:3], store.GetTxsBySender(sender, 3, 255, 10))
+	require.Empty(store.GetTxsBySender(sender, 4, 255, 10))
+
+	store.DelTxSender(sender, 2, 5)
+	require.Equal([]SenderTxPosition{txs[0], txs[2]}, store.GetTxsBySender(sender, 2, 3, 10))
+}
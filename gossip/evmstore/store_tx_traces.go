@@ -0,0 +1,70 @@
+package evmstore
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/snappy"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
+)
+
+// SetTxTraces stores encoded transaction traces, compressed.
+func (s *Store) SetTxTraces(n idx.Block, txid common.Hash, traces []byte) {
+	key := append(n.Bytes(), txid.Bytes()...)
+	if err := s.table.TxTraces.Put(key, snappy.Encode(nil, traces)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetTxTraces returns stored encoded transaction traces.
+// A DB error is returned to the caller rather than halting the node, as the traces are read by RPC only.
+func (s *Store) GetTxTraces(n idx.Block, txid common.Hash) ([]byte, error) {
+	if err := s.CheckQuarantine(TxTracesTable, n); err != nil {
+		return nil, err
+	}
+	key := append(n.Bytes(), txid.Bytes()...)
+	buf, err := s.table.TxTraces.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction traces: %w", err)
+	}
+	if buf == nil {
+		return nil, nil
+	}
+	traces, err := snappy.Decode(nil, buf)
+	if err != nil {
+		s.Log.Error("Failed to decompress transaction traces", "block", n, "tx", txid, "err", err)
+		s.Quarantine(TxTracesTable, n)
+		return nil, &QuarantinedError{Table: TxTracesTable, Block: n}
+	}
+	return traces, nil
+}
+
+// SetTxTracesIndex marks the transaction traces of the block as stored.
+// The index is keyed by the block number only, so it precedes the traces of the block in the table.
+func (s *Store) SetTxTracesIndex(n idx.Block, txs int) {
+	if err := s.table.TxTraces.Put(n.Bytes(), bigendian.Uint32ToBytes(uint32(txs))); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// HasTxTraces returns true if the transaction traces of the block are stored.
+func (s *Store) HasTxTraces(n idx.Block) bool {
+	ok, err := s.table.TxTraces.Has(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return ok
+}
+
+// DelTxTraces removes stored transaction traces of the block and its index.
+func (s *Store) DelTxTraces(n idx.Block) {
+	if err := s.table.TxTraces.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+	if _, err := rangedel.DeleteRange(s.pruned.TxTraces, n.Bytes(), (n + 1).Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key range", "err", err)
+	}
+}
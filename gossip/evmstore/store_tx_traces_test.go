@@ -0,0 +1,46 @@
+package evmstore
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreTxTraces(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := nonCachedStore()
+	tx1 := common.Hash{1}
+	tx2 := common.Hash{2}
+	traces := []byte(`[{"type":"call"}]`)
+
+	store.SetTxTraces(idx.Block(5), tx1, traces)
+	store.SetTxTraces(idx.Block(5), tx2, traces)
+	store.SetTxTraces(idx.Block(6), tx1, traces)
+	store.SetTxTracesIndex(idx.Block(5), 2)
+	store.SetTxTracesIndex(idx.Block(6), 1)
+
+	get := func(n idx.Block, txid common.Hash) []byte {
+		got, err := store.GetTxTraces(n, txid)
+		require.NoError(err)
+		return got
+	}
+
+	require.Equal(traces, get(idx.Block(5), tx1))
+	require.Equal(traces, get(idx.Block(5), tx2))
+	require.Nil(get(idx.Block(4), tx1))
+	require.True(store.HasTxTraces(idx.Block(5)))
+	require.False(store.HasTxTraces(idx.Block(4)))
+
+	store.DelTxTraces(idx.Block(5))
+	require.Nil(get(idx.Block(5), tx1))
+	require.Nil(get(idx.Block(5), tx2))
+	require.False(store.HasTxTraces(idx.Block(5)))
+	require.Equal(traces, get(idx.Block(6), tx1))
+	require.True(store.HasTxTraces(idx.Block(6)))
+}
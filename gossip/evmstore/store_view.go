@@ -0,0 +1,22 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+)
+
+// View returns a read-only store over the given snapshot of the main DB.
+// The view has own caches, so it never returns the data written after the snapshot,
+// while the logs index and the EVM state aren't available in it.
+func (s *Store) View(db kvdb.Store) *Store {
+	v := &Store{
+		cfg:      s.cfg,
+		mainDB:   db,
+		Instance: s.Instance,
+		rlp:      s.rlp,
+		EvmLogs:  s.EvmLogs,
+	}
+	table.MigrateTables(&v.table, v.mainDB)
+	v.initCache()
+	return v
+}
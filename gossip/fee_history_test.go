@@ -0,0 +1,42 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+)
+
+func TestFeeHistoryIndex(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv(2, 1, t)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 1, big.NewInt(1)))
+	require.NoError(err)
+	require.Len(receipts, 1)
+	n := idx.Block(receipts[0].BlockNumber.Uint64())
+
+	h, err := env.store.evm.GetBlockFeeHistory(n)
+	require.NoError(err)
+	require.NotNil(h)
+	require.NotZero(h.GasUsed)
+	require.Equal(env.store.GetRules().Blocks.MaxBlockGas, h.GasLimit)
+	require.NotEmpty(h.Rewards)
+
+	api := ethapi.NewPublicEthereumAPI(env.EthAPI)
+	res, err := api.FeeHistory(context.Background(), 1, rpc.BlockNumber(n), []float64{0, 50, 100})
+	require.NoError(err)
+	require.Equal(uint64(n), res.OldestBlock.ToInt().Uint64())
+	require.Equal([]float64{h.GasUsedRatio()}, res.GasUsedRatio)
+	require.Len(res.BaseFee, 2)
+	require.Equal(h.BaseFee, res.BaseFee[0].ToInt())
+	require.Len(res.Reward, 1)
+	require.Equal(h.Reward(50), res.Reward[0][1].ToInt())
+}
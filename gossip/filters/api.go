@@ -30,11 +30,21 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+)
+
+const (
+	defaultFilterTimeout = 5 * time.Minute // consider a filter inactive if it has not been polled for within the timeout
 )
 
 var (
-	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
+	activeFiltersGauge       = metrics.GetOrRegisterGauge("rpc/filters/active", nil)
+	expiredFiltersCounter    = metrics.GetOrRegisterCounter("rpc/filters/expired", nil)
+	activeSubscriptionsGauge = metrics.GetOrRegisterGauge("rpc/subscriptions/active", nil)
+	deadSubscriptionsCounter = metrics.GetOrRegisterCounter("rpc/subscriptions/dead", nil)
 )
 
 // filter is a helper struct that holds meta information over the filter type
@@ -54,12 +64,20 @@ type Config struct {
 	IndexedLogsBlockRangeLimit idx.Block
 	// Block range limit for logs search (unindexed).
 	UnindexedLogsBlockRangeLimit idx.Block
+	// Token of the mempool streaming subscribers (empty disables the mempool API).
+	MempoolStreamToken string `toml:",omitempty"`
+	// Time after which the filters which aren't polled are removed.
+	FilterTimeout time.Duration
+	// Timeout of a logs index search (0 = no timeout).
+	LogsSearchTimeout time.Duration
 }
 
 func DefaultConfig() Config {
 	return Config{
 		IndexedLogsBlockRangeLimit:   999999999999999999,
 		UnindexedLogsBlockRangeLimit: 100,
+		FilterTimeout:                defaultFilterTimeout,
+		LogsSearchTimeout:            time.Minute,
 	}
 }
 
@@ -71,6 +89,7 @@ type PublicFilterAPI struct {
 	events    *EventSystem
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
+	timeout   time.Duration
 }
 
 // NewPublicFilterAPI returns a new PublicFilterAPI instance.
@@ -80,8 +99,12 @@ func NewPublicFilterAPI(backend Backend, cfg Config) *PublicFilterAPI {
 		backend: backend,
 		events:  NewEventSystem(backend),
 		filters: make(map[rpc.ID]*filter),
+		timeout: cfg.FilterTimeout,
+	}
+	if api.timeout <= 0 {
+		api.timeout = defaultFilterTimeout
 	}
-	go api.timeoutLoop(5 * time.Minute)
+	go api.timeoutLoop(api.timeout)
 
 	return api
 }
@@ -99,12 +122,13 @@ func (api *PublicFilterAPI) timeoutLoop(timeout time.Duration) {
 			select {
 			case <-f.deadline.C:
 				toUninstall = append(toUninstall, f.s)
-				delete(api.filters, id)
+				api.deleteFilter(id)
 			default:
 				continue
 			}
 		}
 		api.filtersMu.Unlock()
+		expiredFiltersCounter.Inc(int64(len(toUninstall)))
 
 		// Unsubscribes are processed outside the lock to avoid the following scenario:
 		// event loop attempts broadcasting events to still active filters while
@@ -116,6 +140,33 @@ func (api *PublicFilterAPI) timeoutLoop(timeout time.Duration) {
 	}
 }
 
+// addFilter registers the polled filter. The caller must hold filtersMu.
+func (api *PublicFilterAPI) addFilter(id rpc.ID, f *filter) {
+	f.deadline = time.NewTimer(api.timeout)
+	api.filters[id] = f
+	activeFiltersGauge.Inc(1)
+}
+
+// deleteFilter removes the polled filter, if it's still registered. The caller must hold filtersMu.
+func (api *PublicFilterAPI) deleteFilter(id rpc.ID) (*filter, bool) {
+	f, found := api.filters[id]
+	if found {
+		delete(api.filters, id)
+		activeFiltersGauge.Dec(1)
+	}
+	return f, found
+}
+
+// notifyFailed checks the result of a WS subscription notification.
+// A failed notification means the connection is dead, so the subscription must be dropped.
+func notifyFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	deadSubscriptionsCounter.Inc(1)
+	return true
+}
+
 // NewPendingTransactionFilter creates a filter that fetches pending transaction hashes
 // as transactions enter the pending state.
 //
@@ -130,7 +181,7 @@ func (api *PublicFilterAPI) NewPendingTransactionFilter() rpc.ID {
 	)
 
 	api.filtersMu.Lock()
-	api.filters[pendingTxSub.ID] = &filter{typ: PendingTransactionsSubscription, deadline: time.NewTimer(deadline), hashes: make([]common.Hash, 0), s: pendingTxSub}
+	api.addFilter(pendingTxSub.ID, &filter{typ: PendingTransactionsSubscription, hashes: make([]common.Hash, 0), s: pendingTxSub})
 	api.filtersMu.Unlock()
 
 	go func() {
@@ -144,7 +195,7 @@ func (api *PublicFilterAPI) NewPendingTransactionFilter() rpc.ID {
 				api.filtersMu.Unlock()
 			case <-pendingTxSub.Err():
 				api.filtersMu.Lock()
-				delete(api.filters, pendingTxSub.ID)
+				api.deleteFilter(pendingTxSub.ID)
 				api.filtersMu.Unlock()
 				return
 			}
@@ -165,6 +216,8 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
+		activeSubscriptionsGauge.Inc(1)
+		defer activeSubscriptionsGauge.Dec(1)
 		txHashes := make(chan []common.Hash, 128)
 		pendingTxSub := api.events.SubscribePendingTxs(txHashes)
 
@@ -174,7 +227,10 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 				// To keep the original behaviour, send a single tx hash in one notification.
 				// TODO(rjl493456442) Send a batch of tx hashes in one notification
 				for _, h := range hashes {
-					_ = notifier.Notify(rpcSub.ID, h)
+					if notifyFailed(notifier.Notify(rpcSub.ID, h)) {
+						pendingTxSub.Unsubscribe()
+						return
+					}
 				}
 			case <-rpcSub.Err():
 				pendingTxSub.Unsubscribe()
@@ -200,7 +256,7 @@ func (api *PublicFilterAPI) NewBlockFilter() rpc.ID {
 	)
 
 	api.filtersMu.Lock()
-	api.filters[headerSub.ID] = &filter{typ: BlocksSubscription, deadline: time.NewTimer(deadline), hashes: make([]common.Hash, 0), s: headerSub}
+	api.addFilter(headerSub.ID, &filter{typ: BlocksSubscription, hashes: make([]common.Hash, 0), s: headerSub})
 	api.filtersMu.Unlock()
 
 	go func() {
@@ -214,7 +270,7 @@ func (api *PublicFilterAPI) NewBlockFilter() rpc.ID {
 				api.filtersMu.Unlock()
 			case <-headerSub.Err():
 				api.filtersMu.Lock()
-				delete(api.filters, headerSub.ID)
+				api.deleteFilter(headerSub.ID)
 				api.filtersMu.Unlock()
 				return
 			}
@@ -234,13 +290,18 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
+		activeSubscriptionsGauge.Inc(1)
+		defer activeSubscriptionsGauge.Dec(1)
 		headers := make(chan *types.Header)
 		headersSub := api.events.SubscribeNewHeads(headers)
 
 		for {
 			select {
 			case h := <-headers:
-				_ = notifier.Notify(rpcSub.ID, h)
+				if notifyFailed(notifier.Notify(rpcSub.ID, h)) {
+					headersSub.Unsubscribe()
+					return
+				}
 			case <-rpcSub.Err():
 				headersSub.Unsubscribe()
 				return
@@ -254,6 +315,13 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 	return rpcSub, nil
 }
 
+// FinalizedHeads send a notification each time a new (header) block is finalized.
+// Blocks are finalized by aBFT consensus at the moment they're produced,
+// so a head is notified as soon as the block is processed.
+func (api *PublicFilterAPI) FinalizedHeads(ctx context.Context) (*rpc.Subscription, error) {
+	return api.NewHeads(ctx)
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
@@ -272,12 +340,17 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	}
 
 	go func() {
+		activeSubscriptionsGauge.Inc(1)
+		defer activeSubscriptionsGauge.Dec(1)
 
 		for {
 			select {
 			case logs := <-matchedLogs:
 				for _, log := range logs {
-					_ = notifier.Notify(rpcSub.ID, &log)
+					if notifyFailed(notifier.Notify(rpcSub.ID, &log)) {
+						logsSub.Unsubscribe()
+						return
+					}
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
@@ -317,7 +390,7 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 	}
 
 	api.filtersMu.Lock()
-	api.filters[logsSub.ID] = &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
+	api.addFilter(logsSub.ID, &filter{typ: LogsSubscription, crit: crit, logs: make([]*types.Log, 0), s: logsSub})
 	api.filtersMu.Unlock()
 
 	go func() {
@@ -331,7 +404,7 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 				api.filtersMu.Unlock()
 			case <-logsSub.Err():
 				api.filtersMu.Lock()
-				delete(api.filters, logsSub.ID)
+				api.deleteFilter(logsSub.ID)
 				api.filtersMu.Unlock()
 				return
 			}
@@ -344,41 +417,87 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 // GetLogs returns logs matching the given argument that are stored within the state.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
-func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
-	var filter *Filter
-	if crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, api.config, *crit.BlockHash, crit.Addresses, crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics)
-	}
+func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) (_ []*types.Log, err error) {
+	ctx = ethapi.WithTraceID(ctx)
+	defer ethapi.FinishTracedCall(ctx, "eth_getLogs", time.Now(), &err)
+
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
+	logs, err := api.criteriaFilter(crit).Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return returnLogs(logs), err
 }
 
+// GetLogsCount returns the number of the logs matching the given argument, counted from the logs index
+// without fetching the logs. Either addresses or topics must be specified.
+func (api *PublicFilterAPI) GetLogsCount(ctx context.Context, crit FilterCriteria) (_ hexutil.Uint64, err error) {
+	ctx = ethapi.WithTraceID(ctx)
+	defer ethapi.FinishTracedCall(ctx, "eth_getLogsCount", time.Now(), &err)
+
+	count, err := api.criteriaFilter(crit).Count(ctx)
+	return hexutil.Uint64(count), err
+}
+
+// HasLogs reports whether there is a log matching the given argument, based on the logs index
+// without fetching the logs. Either addresses or topics must be specified.
+func (api *PublicFilterAPI) HasLogs(ctx context.Context, crit FilterCriteria) (_ bool, err error) {
+	ctx = ethapi.WithTraceID(ctx)
+	defer ethapi.FinishTracedCall(ctx, "eth_hasLogs", time.Now(), &err)
+
+	return api.criteriaFilter(crit).Exists(ctx)
+}
+
+// criteriaFilter constructs the single-shot filter of the given argument, either of a block or of a blocks range.
+func (api *PublicFilterAPI) criteriaFilter(crit FilterCriteria) *Filter {
+	if crit.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		return NewBlockFilter(api.backend, api.config, *crit.BlockHash, crit.Addresses, crit.Topics)
+	}
+	// Convert the RPC block numbers into internal representations
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	// Construct the range filter
+	return NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics)
+}
+
+// GetLogsHistogram returns the numbers of the logs matching the given argument per bucket of blocks,
+// counted from the logs index without fetching the logs. Only the buckets with logs are returned.
+// The default bucket size is a single block.
+func (api *PublicFilterAPI) GetLogsHistogram(ctx context.Context, crit FilterCriteria, bucketSize *hexutil.Uint64) (_ []LogsBucket, err error) {
+	ctx = ethapi.WithTraceID(ctx)
+	defer ethapi.FinishTracedCall(ctx, "eth_getLogsHistogram", time.Now(), &err)
+
+	if crit.BlockHash != nil {
+		return nil, errors.New("histogram of a single block isn't supported")
+	}
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	bucket := idx.Block(1)
+	if bucketSize != nil {
+		bucket = idx.Block(*bucketSize)
+	}
+	return NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics).Histogram(ctx, bucket)
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_uninstallfilter
 func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Lock()
-	f, found := api.filters[id]
-	if found {
-		delete(api.filters, id)
-	}
+	f, found := api.deleteFilter(id)
 	api.filtersMu.Unlock()
 	if found {
 		f.s.Unsubscribe()
@@ -391,7 +510,10 @@ func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
 // If the filter could not be found an empty array of logs is returned.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getfilterlogs
-func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Log, error) {
+func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) (_ []*types.Log, err error) {
+	ctx = ethapi.WithTraceID(ctx)
+	defer ethapi.FinishTracedCall(ctx, "eth_getFilterLogs", time.Now(), &err)
+
 	api.filtersMu.Lock()
 	f, found := api.filters[id]
 	api.filtersMu.Unlock()
@@ -442,7 +564,7 @@ func (api *PublicFilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
 			// receive timer value and reset timer
 			<-f.deadline.C
 		}
-		f.deadline.Reset(deadline)
+		f.deadline.Reset(api.timeout)
 
 		switch f.typ {
 		case PendingTransactionsSubscription, BlocksSubscription:
@@ -485,11 +607,11 @@ func returnLogs(logs []*types.Log) []*types.Log {
 // UnmarshalJSON sets *args fields with given data.
 func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	type input struct {
-		BlockHash *common.Hash     `json:"blockHash"`
-		FromBlock *rpc.BlockNumber `json:"fromBlock"`
-		ToBlock   *rpc.BlockNumber `json:"toBlock"`
-		Addresses interface{}      `json:"address"`
-		Topics    []interface{}    `json:"topics"`
+		BlockHash *common.Hash        `json:"blockHash"`
+		FromBlock *ethapi.BlockNumber `json:"fromBlock"`
+		ToBlock   *ethapi.BlockNumber `json:"toBlock"`
+		Addresses interface{}         `json:"address"`
+		Topics    []interface{}       `json:"topics"`
 	}
 
 	var raw input
@@ -505,11 +627,11 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		args.BlockHash = raw.BlockHash
 	} else {
 		if raw.FromBlock != nil {
-			args.FromBlock = big.NewInt(raw.FromBlock.Int64())
+			args.FromBlock = big.NewInt(raw.FromBlock.Rpc().Int64())
 		}
 
 		if raw.ToBlock != nil {
-			args.ToBlock = big.NewInt(raw.ToBlock.Int64())
+			args.ToBlock = big.NewInt(raw.ToBlock.Rpc().Int64())
 		}
 	}
 
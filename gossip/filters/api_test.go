@@ -182,4 +182,17 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 	if len(test7.Topics[2]) != 0 {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
+
+	// finality tags
+	var test8 FilterCriteria
+	vector = `{"fromBlock":"finalized","toBlock":"safe"}`
+	if err := json.Unmarshal([]byte(vector), &test8); err != nil {
+		t.Fatal(err)
+	}
+	if test8.FromBlock.Int64() != rpc.LatestBlockNumber.Int64() {
+		t.Fatalf("expected FromBlock %d, got %d", rpc.LatestBlockNumber, test8.FromBlock)
+	}
+	if test8.ToBlock.Int64() != rpc.LatestBlockNumber.Int64() {
+		t.Fatalf("expected ToBlock %d, got %d", rpc.LatestBlockNumber, test8.ToBlock)
+	}
 }
@@ -0,0 +1,132 @@
+package filters
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// bloomFilterThreads is the number of goroutines used per filter to
+	// multiplex the bloombits requests.
+	bloomFilterThreads = 3
+
+	// bloomRetrievalBatch is the maximum number of bloombits retrievals to serve
+	// in a single batch.
+	bloomRetrievalBatch = 16
+
+	// bloomRetrievalWait is the maximum time to wait for enough bloombits requests
+	// to accumulate an entire batch.
+	bloomRetrievalWait = time.Duration(0)
+)
+
+// bloomRange returns the range of blocks [from, to] within [begin, end], which consists of the whole sections
+// of the bloombits index. It returns false if there is no such section.
+func (f *Filter) bloomRange(begin, end idx.Block) (from, to idx.Block, ok bool) {
+	size, first, last := f.backend.BloomStatus()
+	if size == 0 || first >= last {
+		return 0, 0, false
+	}
+	firstSection := (uint64(begin) + size - 1) / size
+	if firstSection < first {
+		firstSection = first
+	}
+	endSection := (uint64(end) + 1) / size
+	if endSection > last {
+		endSection = last
+	}
+	if firstSection >= endSection {
+		return 0, 0, false
+	}
+	return idx.Block(firstSection * size), idx.Block(endSection*size - 1), true
+}
+
+// bloomLogs returns the logs matching the filter criteria within the blocks range
+// covered by the bloombits index. The candidate blocks are checked by their receipts.
+func (f *Filter) bloomLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
+	size, _, _ := f.backend.BloomStatus()
+
+	filters := make([][][]byte, 0, len(f.topics)+1)
+	if len(f.addresses) > 0 {
+		filter := make([][]byte, len(f.addresses))
+		for i, addr := range f.addresses {
+			filter[i] = addr.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+	for _, topics := range f.topics {
+		filter := make([][]byte, len(topics))
+		for i, topic := range topics {
+			filter[i] = topic.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+	matcher := bloombits.NewMatcher(size, filters)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	matches := make(chan uint64, 64)
+	session, err := matcher.Start(ctx, uint64(begin), uint64(end), matches)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	requests := make(chan chan *bloombits.Retrieval)
+	for i := 0; i < bloomFilterThreads; i++ {
+		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, requests)
+	}
+	go f.serveBloomBits(ctx, requests)
+
+	var logs []*types.Log
+	for {
+		select {
+		case number, ok := <-matches:
+			if !ok {
+				if err := session.Error(); err != nil {
+					return nil, err
+				}
+				return logs, nil
+			}
+			header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return logs, err
+			}
+			found, err := f.blockLogs(ctx, header.Hash)
+			if err != nil {
+				return nil, err
+			}
+			logs = append(logs, found...)
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// serveBloomBits serves the bloombits retrievals of the matcher session until the context is done.
+func (f *Filter) serveBloomBits(ctx context.Context, requests chan chan *bloombits.Retrieval) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case request := <-requests:
+			task := <-request
+			task.Bitsets = make([][]byte, len(task.Sections))
+			for i, section := range task.Sections {
+				bits, err := f.backend.GetBloomBits(task.Bit, section)
+				if err != nil {
+					task.Error = err
+				}
+				task.Bitsets[i] = bits
+			}
+			request <- task
+		}
+	}
+}
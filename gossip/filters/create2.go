@@ -0,0 +1,122 @@
+package filters
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// create2RetryPeriod is the period of the checks of the blocks, which state wasn't available
+const create2RetryPeriod = 500 * time.Millisecond
+
+// Create2Deployment is sent once the code appears at the watched CREATE2 address.
+type Create2Deployment struct {
+	Address     common.Address `json:"address"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	CodeHash    common.Hash    `json:"codeHash"`
+}
+
+// Create2Address computes the address of a contract deployed with CREATE2 by the deployer,
+// the same way the EVM does: keccak256(0xff ++ deployer ++ salt ++ initCodeHash)[12:].
+func (api *PublicFilterAPI) Create2Address(deployer common.Address, salt common.Hash, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes())
+}
+
+// codeAt returns the hash of the code at the address after the block, or zero hash if there's no code,
+// along with the number of the block.
+func (api *PublicFilterAPI) codeAt(ctx context.Context, addr common.Address, blockNr rpc.BlockNumber) (common.Hash, rpc.BlockNumber, error) {
+	statedb, header, err := api.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(blockNr))
+	if statedb == nil || err != nil {
+		return common.Hash{}, 0, err
+	}
+	defer statedb.Release()
+	n := rpc.BlockNumber(header.Number.Int64())
+	if statedb.GetCodeSize(addr) == 0 {
+		return common.Hash{}, n, statedb.Error()
+	}
+	return statedb.GetCodeHash(addr), n, statedb.Error()
+}
+
+// Create2Deployment creates a subscription which fires once when a code appears at the CREATE2 address computed
+// from the deployer, the salt and the init code hash. If the contract is already deployed, it fires right away.
+// The blocks are checked in order, so the notified block is the block of the deployment. As the archive state
+// of a new block may be not written yet, the blocks, which state isn't available, are retried periodically.
+func (api *PublicFilterAPI) Create2Deployment(ctx context.Context, deployer common.Address, salt common.Hash, initCodeHash common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	addr := api.Create2Address(deployer, salt, initCodeHash)
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		blocksCh := make(chan evmcore.ChainHeadNotify, blocksChanSize)
+		blocksSub := api.backend.SubscribeNewBlockNotify(blocksCh)
+		defer blocksSub.Unsubscribe()
+		retry := time.NewTicker(create2RetryPeriod)
+		defer retry.Stop()
+
+		notify := func(n rpc.BlockNumber, codeHash common.Hash) {
+			_ = notifier.Notify(rpcSub.ID, &Create2Deployment{
+				Address:     addr,
+				BlockNumber: hexutil.Uint64(n),
+				CodeHash:    codeHash,
+			})
+		}
+
+		// next is the first block to check, zero until it's known
+		var next, head rpc.BlockNumber
+		codeHash, latest, err := api.codeAt(context.Background(), addr, rpc.LatestBlockNumber)
+		if err == nil && latest != 0 {
+			if codeHash != (common.Hash{}) {
+				notify(latest, codeHash)
+				return
+			}
+			next, head = latest+1, latest
+		}
+		// check the blocks in order until the code is found, or the state of a block isn't available yet
+		check := func() bool {
+			for ; next != 0 && next <= head; next++ {
+				codeHash, _, err := api.codeAt(context.Background(), addr, next)
+				if err != nil {
+					return false
+				}
+				if codeHash != (common.Hash{}) {
+					notify(next, codeHash)
+					return true
+				}
+			}
+			return false
+		}
+
+		for {
+			select {
+			case ev := <-blocksCh:
+				n := rpc.BlockNumber(ev.Block.Number.Int64())
+				if next == 0 {
+					next = n
+				}
+				if n > head {
+					head = n
+				}
+			case <-retry.C:
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+			if check() {
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
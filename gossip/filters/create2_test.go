@@ -0,0 +1,19 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate2Address(t *testing.T) {
+	api := &PublicFilterAPI{}
+
+	// example 5 of EIP-1014
+	deployer := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	salt := common.HexToHash("0x00000000000000000000000000000000000000000000000000000000cafebabe")
+	initCodeHash := crypto.Keccak256Hash(common.FromHex("0xdeadbeef"))
+	require.Equal(t, common.HexToAddress("0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"), api.Create2Address(deployer, salt, initCodeHash))
+}
@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"context"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+)
+
+// DecodedLogsCriteria is a request of the decoded logs subscription. The events are given either
+// by the signature with the parameter names, e.g. "Transfer(address indexed from, address indexed to, uint256 value)",
+// or by the JSON ABI, in which case the logs of all its events are decoded.
+type DecodedLogsCriteria struct {
+	Addresses []common.Address `json:"address"`
+	Event     string           `json:"event"`
+	ABI       *string          `json:"abi"`
+}
+
+// DecodedLogs creates a subscription that fires for all new logs of the given events, decoded into
+// the named and typed values. The indexed values of the dynamic types are represented by their hashes.
+func (api *PublicFilterAPI) DecodedLogs(ctx context.Context, crit DecodedLogsCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	decoder, err := ethapi.NewEventDecoder(crit.Event, crit.ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		rpcSub      = notifier.CreateSubscription()
+		matchedLogs = make(chan []*types.Log)
+	)
+
+	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery{
+		Addresses: crit.Addresses,
+		Topics:    [][]common.Hash{decoder.IDs()},
+	}, matchedLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		activeSubscriptionsGauge.Inc(1)
+		defer activeSubscriptionsGauge.Dec(1)
+
+		for {
+			select {
+			case logs := <-matchedLogs:
+				for _, log := range logs {
+					decoded, err := decoder.Decode(log)
+					if err != nil {
+						// the log of an event with the same signature, but differently indexed parameters
+						continue
+					}
+					if notifyFailed(notifier.Notify(rpcSub.ID, decoded)) {
+						logsSub.Unsubscribe()
+						return
+					}
+				}
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				logsSub.Unsubscribe()
+				return
+			case <-notifier.Closed(): // connection dropped
+				logsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+)
+
+func TestDecodedLogs(t *testing.T) {
+	require := require.New(t)
+
+	backend := newTestBackend()
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	require.NoError(srv.RegisterName("eth", NewPublicFilterAPI(backend, testConfig())))
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	var (
+		token    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		other    = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		from     = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		to       = common.HexToAddress("0x4444444444444444444444444444444444444444")
+		transfer = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+		approval = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events := make(chan *ethapi.DecodedEvent)
+	sub, err := client.EthSubscribe(ctx, events, "decodedLogs", DecodedLogsCriteria{
+		Addresses: []common.Address{token},
+		Event:     "Transfer(address indexed from, address indexed to, uint256 value)",
+	})
+	require.NoError(err)
+	defer sub.Unsubscribe()
+
+	_, err = client.EthSubscribe(ctx, events, "decodedLogs", DecodedLogsCriteria{Event: "Transfer(address"})
+	require.Error(err)
+
+	value := common.BigToHash(big.NewInt(1000))
+	logs := []*types.Log{
+		// other contract
+		{Address: other, Topics: []common.Hash{transfer, from.Hash(), to.Hash()}, Data: value[:]},
+		// other event
+		{Address: token, Topics: []common.Hash{approval, from.Hash(), to.Hash()}, Data: value[:]},
+		// differently indexed event
+		{Address: token, Topics: []common.Hash{transfer, from.Hash()}, Data: append(to.Hash().Bytes(), value[:]...)},
+		{Address: token, Topics: []common.Hash{transfer, from.Hash(), to.Hash()}, Data: value[:], BlockNumber: 5, Index: 2},
+	}
+	require.Eventually(func() bool {
+		return backend.logsFeed.Send(logs) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case ev := <-events:
+		require.Equal(token, ev.Address)
+		require.Equal("Transfer", ev.Event)
+		require.Equal("Transfer(address,address,uint256)", ev.Signature)
+		require.Equal(uint64(5), uint64(ev.BlockNumber))
+		require.Equal(uint(2), uint(ev.Index))
+		require.Len(ev.Args, 3)
+		require.Equal(ethapi.DecodedArg{Name: "from", Type: "address", Value: from.Hex()}, ev.Args[0])
+		require.Equal(ethapi.DecodedArg{Name: "to", Type: "address", Value: to.Hex()}, ev.Args[1])
+		require.Equal(ethapi.DecodedArg{Name: "value", Type: "uint256", Value: "1000"}, ev.Args[2])
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-ctx.Done():
+		t.Fatal("no decoded event")
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -21,17 +21,21 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	notify "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/Fantom-foundation/go-opera/ethapi"
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
 )
 
@@ -41,13 +45,18 @@ type Backend interface {
 	GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
 	GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error)
 	GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error)
-	GetTxPosition(txid common.Hash) *evmstore.TxPosition
+	GetTxPosition(txid common.Hash) (*evmstore.TxPosition, error)
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (state.StateDB, *evmcore.EvmHeader, error)
 
 	SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotify) notify.Subscription
 	SubscribeNewTxsNotify(chan<- evmcore.NewTxsNotify) notify.Subscription
 	SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscription
+	SubscribeRemovedLogsNotify(ch chan<- evmcore.RemovedLogsNotify) notify.Subscription
 
 	EvmLogIndex() topicsdb.Index
+	BloomStatus() (sectionSize, first, end uint64)
+	GetBloomBits(bit uint, section uint64) ([]byte, error)
+	HistoryStart() idx.Block
 
 	CalcBlockExtApi() bool
 }
@@ -130,6 +139,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if begin > end {
 		return []*types.Log{}, nil
 	}
+	if start := f.backend.HistoryStart(); begin < start {
+		return nil, &ethapi.HistoryUnavailableError{First: start}
+	}
 
 	if isEmpty(f.topics) && len(f.addresses) == 0 {
 		return f.unindexedLogs(ctx, begin, end)
@@ -138,12 +150,45 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 }
 
-// indexedLogs returns the logs matching the filter criteria based on topics index.
+// indexedLogs returns the logs matching the filter criteria based on bloombits and topics indexes.
+// The whole sections of the bloombits index within the range are searched by bloombits, the rest by topics index.
+// The blocks range limit applies only to the blocks searched by topics index.
 func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
-	if end-begin > f.config.IndexedLogsBlockRangeLimit {
-		return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+	from, to, ok := f.bloomRange(begin, end)
+	if !ok {
+		if end-begin > f.config.IndexedLogsBlockRangeLimit {
+			return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+		}
+		return f.topicsLogs(ctx, begin, end)
+	}
+	if (from-begin)+(end-to) > f.config.IndexedLogsBlockRangeLimit {
+		return nil, fmt.Errorf("too wide blocks range outside of the bloombits index, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+	}
+	var logs []*types.Log
+	if begin < from {
+		found, err := f.topicsLogs(ctx, begin, from-1)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, found...)
+	}
+	found, err := f.bloomLogs(ctx, from, to)
+	if err != nil {
+		return nil, err
 	}
+	logs = append(logs, found...)
+	if to < end {
+		found, err := f.topicsLogs(ctx, to+1, end)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, found...)
+	}
+	return logs, nil
+}
 
+// topicsLogs returns the logs matching the filter criteria based on topics index.
+func (f *Filter) topicsLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
 	addresses := make([]common.Hash, len(f.addresses))
 	for i, addr := range f.addresses {
 		addresses[i] = addr.Hash()
@@ -153,13 +198,21 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 	pattern[0] = addresses
 	pattern = append(pattern, f.topics...)
 
-	logs, err := f.backend.EvmLogIndex().FindInBlocks(ctx, begin, end, pattern)
+	searchCtx, cancel := f.searchContext(ctx)
+	defer cancel()
+	logs, err := f.backend.EvmLogIndex().FindInBlocks(searchCtx, begin, end, pattern)
 	if err != nil {
-		return nil, err
+		return nil, f.searchError(err)
 	}
 
 	for _, l := range logs {
-		pos := f.backend.GetTxPosition(l.TxHash)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pos, err := f.backend.GetTxPosition(l.TxHash)
+		if err != nil {
+			return nil, err
+		}
 		if pos != nil {
 			l.TxIndex = uint(pos.BlockOffset)
 		} else {
@@ -170,6 +223,175 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 	return logs, nil
 }
 
+// searchContext limits the logs index search by the configured timeout.
+// The search is aborted also once the request context is done, e.g. the client is disconnected.
+func (f *Filter) searchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.config.LogsSearchTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, f.config.LogsSearchTimeout)
+}
+
+// searchError explains the error of the logs index search aborted by timeout.
+func (f *Filter) searchError(err error) error {
+	if f.config.LogsSearchTimeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("logs search is aborted (timeout = %v), narrow the blocks range or the filter criteria", f.config.LogsSearchTimeout)
+	}
+	return err
+}
+
+// LogsBucket is a number of the logs matching the filter criteria within a range of blocks.
+type LogsBucket struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+	Count     hexutil.Uint64 `json:"count"`
+}
+
+// Histogram counts the logs matching the filter criteria per bucket of blocks, based on topics index,
+// without fetching the logs. Only the buckets with logs are returned, in ascending order.
+func (f *Filter) Histogram(ctx context.Context, bucket idx.Block) ([]LogsBucket, error) {
+	if f.block != common.Hash(hash.Zero) {
+		return nil, errors.New("histogram of a single block isn't supported")
+	}
+	if isEmpty(f.topics) && len(f.addresses) == 0 {
+		return nil, errors.New("address or topics must be specified")
+	}
+	if bucket == 0 {
+		return nil, errors.New("bucket size must be positive")
+	}
+	begin, end, ok, err := f.indexedRange(ctx)
+	if err != nil || !ok {
+		return []LogsBucket{}, err
+	}
+
+	addresses := make([]common.Hash, len(f.addresses))
+	for i, addr := range f.addresses {
+		addresses[i] = addr.Hash()
+	}
+	pattern := make([][]common.Hash, 1, len(f.topics)+1)
+	pattern[0] = addresses
+	pattern = append(pattern, f.topics...)
+
+	counts := make(map[idx.Block]uint64)
+	searchCtx, cancel := f.searchContext(ctx)
+	defer cancel()
+	err = f.backend.EvmLogIndex().CountInBlocks(searchCtx, begin, end, pattern, func(n idx.Block) bool {
+		counts[begin+(n-begin)/bucket*bucket]++
+		return true
+	})
+	if err != nil {
+		return nil, f.searchError(err)
+	}
+
+	buckets := make([]LogsBucket, 0, len(counts))
+	for from, count := range counts {
+		to := from + bucket - 1
+		if to > end || to < from {
+			to = end
+		}
+		buckets = append(buckets, LogsBucket{
+			FromBlock: hexutil.Uint64(from),
+			ToBlock:   hexutil.Uint64(to),
+			Count:     hexutil.Uint64(count),
+		})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].FromBlock < buckets[j].FromBlock
+	})
+	return buckets, nil
+}
+
+// Count returns the number of the logs matching the filter criteria, based on topics index
+// without fetching the logs and their receipts.
+func (f *Filter) Count(ctx context.Context) (uint64, error) {
+	q, ok, err := f.indexedQuery(ctx)
+	if err != nil || !ok {
+		return 0, err
+	}
+	searchCtx, cancel := f.searchContext(ctx)
+	defer cancel()
+	count, err := f.backend.EvmLogIndex().Count(searchCtx, q)
+	if err != nil {
+		return 0, f.searchError(err)
+	}
+	return count, nil
+}
+
+// Exists reports whether there is a log matching the filter criteria, based on topics index
+// without fetching the logs and their receipts. The search stops at the first matched log.
+func (f *Filter) Exists(ctx context.Context) (bool, error) {
+	q, ok, err := f.indexedQuery(ctx)
+	if err != nil || !ok {
+		return false, err
+	}
+	searchCtx, cancel := f.searchContext(ctx)
+	defer cancel()
+	exists, err := f.backend.EvmLogIndex().Exists(searchCtx, q)
+	if err != nil {
+		return false, f.searchError(err)
+	}
+	return exists, nil
+}
+
+// indexedQuery returns the topics index query of the filter criteria, either of the single block or of the blocks range.
+// ok is false if there are no blocks to search.
+func (f *Filter) indexedQuery(ctx context.Context) (q topicsdb.Query, ok bool, err error) {
+	if isEmpty(f.topics) && len(f.addresses) == 0 {
+		return q, false, errors.New("address or topics must be specified")
+	}
+	var begin, end idx.Block
+	if f.block != common.Hash(hash.Zero) {
+		header, err := f.backend.HeaderByHash(ctx, f.block)
+		if err != nil {
+			return q, false, err
+		}
+		if header == nil {
+			return q, false, errors.New("unknown block")
+		}
+		begin = idx.Block(header.Number.Uint64())
+		end = begin
+	} else {
+		begin, end, ok, err = f.indexedRange(ctx)
+		if err != nil || !ok {
+			return q, false, err
+		}
+	}
+	return topicsdb.Query{
+		From:      begin,
+		To:        end,
+		Addresses: f.addresses,
+		Topics:    f.topics,
+	}, true, nil
+}
+
+// indexedRange resolves the blocks range of the filter searched by topics index, ok is false if the range is empty.
+func (f *Filter) indexedRange(ctx context.Context) (begin, end idx.Block, ok bool, err error) {
+	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil {
+		return 0, 0, false, nil
+	}
+	head := idx.Block(header.Number.Uint64())
+
+	begin = idx.Block(f.begin)
+	if f.begin < 0 {
+		begin = head
+	}
+	end = idx.Block(f.end)
+	if f.end < 0 {
+		end = head
+	}
+	if begin > end {
+		return 0, 0, false, nil
+	}
+	if start := f.backend.HistoryStart(); begin < start {
+		return 0, 0, false, &ethapi.HistoryUnavailableError{First: start}
+	}
+	if end-begin > f.config.IndexedLogsBlockRangeLimit {
+		return 0, 0, false, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+	}
+	return begin, end, true, nil
+}
+
 // indexedLogs returns the logs matching the filter criteria based on raw block
 // iteration.
 func (f *Filter) unindexedLogs(ctx context.Context, begin, end idx.Block) (logs []*types.Log, err error) {
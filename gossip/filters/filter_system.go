@@ -65,6 +65,8 @@ const (
 	txChanSize = 4096
 	// logsChanSize is the size of channel listening to LogsEvent.
 	logsChanSize = 10
+	// rmLogsChanSize is the size of channel listening to RemovedLogsEvent.
+	rmLogsChanSize = 10
 	// blocksChanSize is the size of channel listening to BlocksEvent.
 	blocksChanSize = 10
 )
@@ -93,14 +95,16 @@ type EventSystem struct {
 	// Subscriptions
 	txsSub    notify.Subscription // Subscription for new transaction notify
 	logsSub   notify.Subscription // Subscription for new log notify
+	rmLogsSub notify.Subscription // Subscription for removed log notify
 	blocksSub notify.Subscription // Subscription for new chain notify
 
 	// Channels
-	install   chan *subscription           // install filter for event notification
-	uninstall chan *subscription           // remove filter for event notification
-	txsCh     chan evmcore.NewTxsNotify    // Channel to receive new transactions notify
-	logsCh    chan []*types.Log            // Channel to receive new log notify
-	blocksCh  chan evmcore.ChainHeadNotify // Channel to receive new chain notify
+	install   chan *subscription             // install filter for event notification
+	uninstall chan *subscription             // remove filter for event notification
+	txsCh     chan evmcore.NewTxsNotify      // Channel to receive new transactions notify
+	logsCh    chan []*types.Log              // Channel to receive new log notify
+	rmLogsCh  chan evmcore.RemovedLogsNotify // Channel to receive removed log notify
+	blocksCh  chan evmcore.ChainHeadNotify   // Channel to receive new chain notify
 }
 
 // NewEventSystem creates a new manager that listens for event on the given chans,
@@ -116,15 +120,17 @@ func NewEventSystem(backend Backend) *EventSystem {
 		blocksCh:  make(chan evmcore.ChainHeadNotify, blocksChanSize),
 		txsCh:     make(chan evmcore.NewTxsNotify, txChanSize),
 		logsCh:    make(chan []*types.Log, logsChanSize),
+		rmLogsCh:  make(chan evmcore.RemovedLogsNotify, rmLogsChanSize),
 	}
 
 	// Subscribe events
 	m.blocksSub = m.backend.SubscribeNewBlockNotify(m.blocksCh)
 	m.txsSub = m.backend.SubscribeNewTxsNotify(m.txsCh)
 	m.logsSub = m.backend.SubscribeLogsNotify(m.logsCh)
+	m.rmLogsSub = m.backend.SubscribeRemovedLogsNotify(m.rmLogsCh)
 
 	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.blocksSub == nil {
+	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.blocksSub == nil {
 		log.Crit("Subscribe for event system failed")
 	}
 
@@ -316,6 +322,13 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 				}
 			}
 		}
+	case evmcore.RemovedLogsNotify:
+		removed := markRemoved(e.Logs)
+		for _, f := range filters[LogsSubscription] {
+			if matchedLogs := filterLogs(removed, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
+				f.logs <- matchedLogs
+			}
+		}
 	case evmcore.NewTxsNotify:
 		hashes := make([]common.Hash, 0, len(e.Txs))
 		for _, tx := range e.Txs {
@@ -334,6 +347,18 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 	}
 }
 
+// markRemoved returns copies of the logs with the Removed flag set,
+// so subscribers can distinguish reverted logs from the new ones.
+func markRemoved(logs []*types.Log) []*types.Log {
+	removed := make([]*types.Log, len(logs))
+	for i, l := range logs {
+		cp := *l
+		cp.Removed = true
+		removed[i] = &cp
+	}
+	return removed
+}
+
 // calculateExtBlockApi doubles ethapi/PublicBlockChainAPI.calculateExtBlockApi() functionality.
 // TODO: common code.
 func (es *EventSystem) calculateExtBlockApi(h *types.Header) {
@@ -361,6 +386,7 @@ func (es *EventSystem) eventLoop() {
 		es.blocksSub.Unsubscribe()
 		es.txsSub.Unsubscribe()
 		es.logsSub.Unsubscribe()
+		es.rmLogsSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -375,6 +401,8 @@ func (es *EventSystem) eventLoop() {
 			es.broadcast(index, ev)
 		case ev := <-es.logsCh:
 			es.broadcast(index, ev)
+		case ev := <-es.rmLogsCh:
+			es.broadcast(index, ev)
 		case ev := <-es.blocksCh:
 			es.broadcast(index, ev)
 
@@ -391,6 +419,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-es.logsSub.Err():
 			return
+		case <-es.rmLogsSub.Err():
+			return
 		case <-es.blocksSub.Err():
 			return
 		}
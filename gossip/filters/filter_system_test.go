@@ -18,11 +18,13 @@ package filters
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -32,8 +34,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/Fantom-foundation/go-opera/ethapi"
 	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter/state"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
 )
 
@@ -43,6 +47,11 @@ type testBackend struct {
 	blocksFeed *notify.Feed
 	txsFeed    *notify.Feed
 	logsFeed   *notify.Feed
+	rmLogsFeed *notify.Feed
+	// bloombits index, nil if disabled
+	blooms *evmstore.Store
+	// first block of the kept history
+	historyStart idx.Block
 }
 
 func newTestBackend() *testBackend {
@@ -52,6 +61,7 @@ func newTestBackend() *testBackend {
 		blocksFeed: new(notify.Feed),
 		txsFeed:    new(notify.Feed),
 		logsFeed:   new(notify.Feed),
+		rmLogsFeed: new(notify.Feed),
 	}
 }
 
@@ -128,6 +138,10 @@ func (b *testBackend) SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscri
 	return b.logsFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeRemovedLogsNotify(ch chan<- evmcore.RemovedLogsNotify) notify.Subscription {
+	return b.rmLogsFeed.Subscribe(ch)
+}
+
 func (b *testBackend) SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
 	return b.blocksFeed.Subscribe(ch)
 }
@@ -136,6 +150,25 @@ func (b *testBackend) EvmLogIndex() topicsdb.Index {
 	return b.logIndex
 }
 
+func (b *testBackend) BloomStatus() (uint64, uint64, uint64) {
+	if b.blooms == nil {
+		return evmstore.BloomBitsSectionSize, 0, 0
+	}
+	first, end := b.blooms.BloomBitsSections()
+	return evmstore.BloomBitsSectionSize, first, end
+}
+
+func (b *testBackend) GetBloomBits(bit uint, section uint64) ([]byte, error) {
+	if b.blooms == nil {
+		return nil, evmstore.ErrBloomBitsNotFound
+	}
+	return b.blooms.GetBloomBits(bit, section)
+}
+
+func (b *testBackend) HistoryStart() idx.Block {
+	return b.historyStart
+}
+
 func (b *testBackend) MustPushLogs(recs ...*types.Log) {
 	err := b.logIndex.Push(recs...)
 	if err != nil {
@@ -143,8 +176,12 @@ func (b *testBackend) MustPushLogs(recs ...*types.Log) {
 	}
 }
 
-func (b *testBackend) GetTxPosition(txid common.Hash) *evmstore.TxPosition {
-	return nil
+func (b *testBackend) GetTxPosition(txid common.Hash) (*evmstore.TxPosition, error) {
+	return nil, nil
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (state.StateDB, *evmcore.EvmHeader, error) {
+	return nil, nil, nil
 }
 
 func (b *testBackend) CalcBlockExtApi() bool {
@@ -293,6 +330,36 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 	}
 }
 
+// TestGetLogsTraceID tests whether the failed logs requests refer to their trace IDs.
+func TestGetLogsTraceID(t *testing.T) {
+	var (
+		backend   = newTestBackend()
+		api       = NewPublicFilterAPI(backend, testConfig())
+		blockHash = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+		crit      = FilterCriteria{BlockHash: &blockHash, FromBlock: big.NewInt(100)}
+	)
+
+	traceIDs := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		_, err := api.GetLogs(context.Background(), crit)
+		dataErr, ok := err.(rpc.DataError)
+		if !ok {
+			t.Fatalf("Expected a data error, got %v", err)
+		}
+		data, ok := dataErr.ErrorData().(ethapi.TracedErrorData)
+		if !ok || data.TraceID == "" {
+			t.Fatalf("Expected a trace ID in the error data, got %v", dataErr.ErrorData())
+		}
+		traceIDs[data.TraceID] = true
+		if err.Error() != errors.Unwrap(err).Error() {
+			t.Errorf("Expected the original error message, got %v", err)
+		}
+	}
+	if len(traceIDs) != 2 {
+		t.Errorf("Expected a new trace ID per call")
+	}
+}
+
 // TestLogFilter tests whether log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
@@ -392,3 +459,114 @@ func TestLogFilter(t *testing.T) {
 		}
 	}
 }
+
+// TestRemovedLogs tests whether log filters deliver reverted logs with the removed flag
+// when a block is replaced, followed by the logs of the replacing block.
+func TestRemovedLogs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newTestBackend()
+		api     = NewPublicFilterAPI(backend, testConfig())
+
+		addr        = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		otherAddr   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		firstTopic  = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+		secondTopic = common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222")
+
+		// block 2 is replaced after the events were reordered in the DAG
+		originalLogs = []*types.Log{
+			{Address: addr, Topics: []common.Hash{firstTopic}, BlockNumber: 1, TxHash: common.Hash{1}},
+			{Address: addr, Topics: []common.Hash{firstTopic}, BlockNumber: 2, TxHash: common.Hash{2}},
+			{Address: otherAddr, Topics: []common.Hash{secondTopic}, BlockNumber: 2, TxHash: common.Hash{3}},
+		}
+		replacingLogs = []*types.Log{
+			{Address: otherAddr, Topics: []common.Hash{secondTopic}, BlockNumber: 2, TxHash: common.Hash{3}},
+			{Address: addr, Topics: []common.Hash{firstTopic}, BlockNumber: 2, TxHash: common.Hash{2}, Index: 1},
+		}
+	)
+
+	id, err := api.NewFilter(FilterCriteria{Addresses: []common.Address{addr}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		txHash  common.Hash
+		removed bool
+	}{
+		{common.Hash{1}, false},
+		{common.Hash{2}, false},
+		{common.Hash{2}, true},
+		{common.Hash{2}, false},
+	}
+
+	// the logs and the removed logs are delivered by different feeds, so the next notification
+	// is sent once the previous one is fetched to keep the order
+	var fetched []*types.Log
+	fetch := func(n int) {
+		timeout := time.Now().Add(1 * time.Second)
+		for len(fetched) < n && time.Now().Before(timeout) {
+			results, err := api.GetFilterChanges(id)
+			if err != nil {
+				t.Fatalf("Unable to fetch logs: %v", err)
+			}
+			fetched = append(fetched, results.([]*types.Log)...)
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	time.Sleep(1 * time.Second)
+	backend.logsFeed.Send(originalLogs)
+	fetch(2)
+	backend.rmLogsFeed.Send(evmcore.RemovedLogsNotify{Logs: originalLogs[1:]})
+	fetch(3)
+	backend.logsFeed.Send(replacingLogs)
+	fetch(len(expected))
+
+	if len(fetched) != len(expected) {
+		t.Fatalf("invalid number of logs, want %d log(s), got %d", len(expected), len(fetched))
+	}
+	for i, exp := range expected {
+		if fetched[i].TxHash != exp.txHash {
+			t.Errorf("invalid tx hash of log %d, want %x, got %x", i, exp.txHash, fetched[i].TxHash)
+		}
+		if fetched[i].Removed != exp.removed {
+			t.Errorf("invalid removed flag of log %d, want %v, got %v", i, exp.removed, fetched[i].Removed)
+		}
+	}
+	if originalLogs[1].Removed {
+		t.Error("original log must not be mutated")
+	}
+}
+
+// TestFilterTimeout tests that the filters which aren't polled are removed, while the polled ones are kept.
+func TestFilterTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.FilterTimeout = 100 * time.Millisecond
+	var (
+		backend = newTestBackend()
+		api     = NewPublicFilterAPI(backend, cfg)
+	)
+
+	polled := api.NewBlockFilter()
+	abandoned := api.NewBlockFilter()
+
+	for i := 0; i < 6; i++ {
+		time.Sleep(50 * time.Millisecond)
+		if _, err := api.GetFilterChanges(polled); err != nil {
+			t.Fatalf("polled filter is removed: %v", err)
+		}
+	}
+	if _, err := api.GetFilterChanges(abandoned); err == nil {
+		t.Fatal("abandoned filter isn't removed")
+	}
+	if api.UninstallFilter(abandoned) {
+		t.Fatal("abandoned filter is uninstalled twice")
+	}
+	if !api.UninstallFilter(polled) {
+		t.Fatal("polled filter isn't uninstalled")
+	}
+}
@@ -24,9 +24,16 @@ import (
 	"math/big"
 	"os"
 	"path"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
@@ -262,4 +269,185 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 
+	// the log of the block 999 is indexed at the block 0, as its block number isn't set
+	filter = NewRangeFilter(backend, testConfig(), 0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	buckets, err := filter.Histogram(context.Background(), 500)
+	if err != nil {
+		t.Error(err)
+	}
+	expect := []LogsBucket{
+		{FromBlock: 0, ToBlock: 499, Count: 3},
+		{FromBlock: 500, ToBlock: 999, Count: 1},
+	}
+	if !reflect.DeepEqual(buckets, expect) {
+		t.Errorf("expected buckets %v, got %v", expect, buckets)
+	}
+	count, err := filter.Count(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 4 {
+		t.Error("expected 4 logs, got", count)
+	}
+	exists, err := filter.Exists(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if !exists {
+		t.Error("expected the logs to exist")
+	}
+
+	filter = NewRangeFilter(backend, testConfig(), 0, -1, nil, [][]common.Hash{{failHash}})
+	if exists, err = filter.Exists(context.Background()); err != nil || exists {
+		t.Error("expected no logs, got", exists, err)
+	}
+
+	filter = NewRangeFilter(backend, testConfig(), 0, -1, nil, nil)
+	if _, err = filter.Histogram(context.Background(), 1); err == nil {
+		t.Error("expected error of the unindexed histogram")
+	}
+}
+
+func TestFiltersBloomBits(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+		topic   = common.BytesToHash([]byte("topic"))
+		blocks  = evmstore.BloomBitsSectionSize + 100
+	)
+	backend.blooms = evmstore.NewStore(memorydb.New(), evmstore.StoreConfig{BloomBitsIndexing: true})
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, blocks, func(i int, gen *core.BlockGen) {
+		if i != 10 && i != evmstore.BloomBitsSectionSize+10 {
+			return
+		}
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{
+			{
+				BlockNumber: uint64(i + 1),
+				Address:     addr,
+				Topics:      []common.Hash{topic},
+			},
+		}
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		// the logs of the bloombits section aren't in the topics index, so they are found only by bloombits
+		if i > evmstore.BloomBitsSectionSize {
+			backend.MustPushLogs(receipt.Logs...)
+		}
+	})
+	backend.blooms.SetBlockBloom(0, nil)
+	for i, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+		rawdb.WriteReceipts(backend.db, block.Hash(), block.NumberU64(), receipts[i])
+		backend.blooms.SetBlockBloom(idx.Block(block.NumberU64()), receipts[i])
+	}
+
+	// the range limit applies only to the blocks outside of the bloombits section
+	cfg := testConfig()
+	cfg.IndexedLogsBlockRangeLimit = 101
+
+	for _, crit := range []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		expected  int
+	}{
+		{[]common.Address{addr}, nil, 2},
+		{nil, [][]common.Hash{{topic}}, 2},
+		{[]common.Address{addr}, [][]common.Hash{{}, {topic}}, 0},
+		{[]common.Address{{1}}, nil, 0},
+	} {
+		filter := NewRangeFilter(backend, cfg, 0, -1, crit.addresses, crit.topics)
+		logs, err := filter.Logs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != crit.expected {
+			t.Fatalf("expected %d logs, got %d", crit.expected, len(logs))
+		}
+	}
+
+	cfg.IndexedLogsBlockRangeLimit = 100
+	filter := NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr}, nil)
+	if _, err := filter.Logs(context.Background()); err == nil {
+		t.Fatal("expected error of too wide blocks range")
+	}
+}
+
+func TestFiltersHistoryStart(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+	)
+	backend.historyStart = 5
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr, big.NewInt(1000000))
+	chain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, 10, func(i int, gen *core.BlockGen) {})
+	for _, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+	}
+
+	filter := NewRangeFilter(backend, testConfig(), 4, -1, []common.Address{addr}, nil)
+	_, err := filter.Logs(context.Background())
+	if histErr, ok := err.(*ethapi.HistoryUnavailableError); !ok || histErr.First != 5 {
+		t.Fatalf("expected history unavailable error, got %v", err)
+	}
+	if _, err = filter.Histogram(context.Background(), 1); err == nil {
+		t.Error("expected history unavailable error of the histogram")
+	}
+
+	filter = NewRangeFilter(backend, testConfig(), 5, -1, []common.Address{addr}, nil)
+	if _, err = filter.Logs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stuckIndex is a logs index, which searches until the context is done
+type stuckIndex struct {
+	topicsdb.Index
+}
+
+func (stuckIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) ([]*types.Log, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestFiltersSearchTimeout(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+	)
+	backend.logIndex = stuckIndex{backend.logIndex}
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr, big.NewInt(1000000))
+	chain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, 10, func(i int, gen *core.BlockGen) {})
+	for _, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+	}
+
+	cfg := testConfig()
+	cfg.LogsSearchTimeout = 10 * time.Millisecond
+	filter := NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr}, nil)
+	_, err := filter.Logs(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+
+	// the search is aborted once the request is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	filter = NewRangeFilter(backend, testConfig(), 0, -1, []common.Address{addr}, nil)
+	if _, err = filter.Logs(ctx); err != context.Canceled {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
 }
@@ -0,0 +1,120 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// MempoolCriteria filters the streamed pending transactions.
+// Empty criteria match all the transactions.
+type MempoolCriteria struct {
+	// To matches recipients of the transactions
+	To []common.Address `json:"to"`
+	// Selectors matches the first 4 bytes of the transactions data
+	Selectors []hexutil.Bytes `json:"selectors"`
+	// MinGasPrice matches transactions with the gas fee cap not lower than the value
+	MinGasPrice *hexutil.Big `json:"minGasPrice"`
+}
+
+func (crit *MempoolCriteria) matches(tx *types.Transaction) bool {
+	if len(crit.To) != 0 {
+		if tx.To() == nil {
+			return false
+		}
+		found := false
+		for _, to := range crit.To {
+			if to == *tx.To() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(crit.Selectors) != 0 {
+		data := tx.Data()
+		found := false
+		for _, selector := range crit.Selectors {
+			if len(selector) != 0 && bytes.HasPrefix(data, selector) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if crit.MinGasPrice != nil && tx.GasFeeCapIntCmp(crit.MinGasPrice.ToInt()) < 0 {
+		return false
+	}
+	return true
+}
+
+// MempoolAPI streams full bodies of the transactions entering the pool.
+// The API is private and available only to subscribers knowing the configured token.
+type MempoolAPI struct {
+	backend Backend
+	token   string
+}
+
+// NewMempoolAPI returns a new MempoolAPI instance.
+func NewMempoolAPI(backend Backend, token string) *MempoolAPI {
+	return &MempoolAPI{
+		backend: backend,
+		token:   token,
+	}
+}
+
+// PendingTransactions creates a subscription that is triggered each time a transaction matching
+// the criteria enters the transaction pool. The full transaction is sent in the notification.
+func (api *MempoolAPI) PendingTransactions(ctx context.Context, token string, crit MempoolCriteria) (*rpc.Subscription, error) {
+	if len(api.token) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(api.token)) != 1 {
+		return &rpc.Subscription{}, errUnauthorized
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		activeSubscriptionsGauge.Inc(1)
+		defer activeSubscriptionsGauge.Dec(1)
+		txsCh := make(chan evmcore.NewTxsNotify, txChanSize)
+		txsSub := api.backend.SubscribeNewTxsNotify(txsCh)
+		defer txsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-txsCh:
+				for _, tx := range ev.Txs {
+					if crit.matches(tx) {
+						if notifyFailed(notifier.Notify(rpcSub.ID, ethapi.NewRPCPendingTransaction(tx, nil))) {
+							return
+						}
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,33 @@
+package filters
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMempoolCriteria(t *testing.T) {
+	require := require.New(t)
+
+	to := common.Address{1}
+	tx := types.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(100), []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01})
+	creation := types.NewContractCreation(0, big.NewInt(0), 21000, big.NewInt(100), nil)
+
+	require.True((&MempoolCriteria{}).matches(tx))
+	require.True((&MempoolCriteria{}).matches(creation))
+
+	byTo := &MempoolCriteria{To: []common.Address{{2}, to}}
+	require.True(byTo.matches(tx))
+	require.False(byTo.matches(creation))
+
+	bySelector := &MempoolCriteria{Selectors: []hexutil.Bytes{{0xa9, 0x05, 0x9c, 0xbb}}}
+	require.True(bySelector.matches(tx))
+	require.False((&MempoolCriteria{Selectors: []hexutil.Bytes{{0x09, 0x5e, 0xa7, 0xb3}}}).matches(tx))
+
+	require.True((&MempoolCriteria{MinGasPrice: (*hexutil.Big)(big.NewInt(100))}).matches(tx))
+	require.False((&MempoolCriteria{MinGasPrice: (*hexutil.Big)(big.NewInt(101))}).matches(tx))
+}
@@ -27,9 +27,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
-	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils/lru"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -82,7 +82,7 @@ type Oracle struct {
 	cfg Config
 
 	eCache effectiveMinGasPriceCache
-	tCache *lru.Cache
+	tCache *lru.Cache[uint64, tipCache]
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -110,7 +110,7 @@ func NewOracle(params Config) *Oracle {
 	params.MaxGasPrice = sanitizeBigInt(params.MaxGasPrice, nil, nil, DefaultMaxGasPrice, "MaxGasPrice")
 	params.MinGasPrice = sanitizeBigInt(params.MinGasPrice, nil, nil, new(big.Int), "MinGasPrice")
 	params.DefaultCertainty = sanitizeBigInt(new(big.Int).SetUint64(params.DefaultCertainty), big.NewInt(0), DecimalUnitBn, big.NewInt(DecimalUnit/2), "DefaultCertainty").Uint64()
-	tCache, _ := lru.New(100)
+	tCache := lru.New[uint64, tipCache](100, 100, nil)
 	return &Oracle{
 		cfg:    params,
 		tCache: tCache,
@@ -172,8 +172,7 @@ func (gpo *Oracle) SuggestTip(certainty uint64) *big.Int {
 
 	const cacheSlack = DecimalUnit * 0.05
 	roundedCertainty := certainty / cacheSlack
-	if cached, ok := gpo.tCache.Get(roundedCertainty); ok {
-		cache := cached.(tipCache)
+	if cache, ok := gpo.tCache.Get(roundedCertainty); ok {
 		if time.Since(cache.upd) < statUpdatePeriod {
 			return new(big.Int).Set(cache.tip)
 		} else {
@@ -18,6 +18,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/eventcheck/heavycheck"
 	"github.com/Fantom-foundation/go-opera/eventcheck/parentlesscheck"
 	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/dialsched"
+	"github.com/Fantom-foundation/go-opera/gossip/eventtiming"
+	"github.com/Fantom-foundation/go-opera/gossip/p2pcapture"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
@@ -37,6 +40,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter/ibr"
 	"github.com/Fantom-foundation/go-opera/inter/ier"
 	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils/chaos"
 	"github.com/Fantom-foundation/go-opera/utils/txtime"
 	"github.com/Fantom-foundation/lachesis-base/gossip/dagprocessor"
 	"github.com/Fantom-foundation/lachesis-base/gossip/itemsfetcher"
@@ -65,6 +69,7 @@ const (
 
 var (
 	broadcastedTxsCounter = metrics.GetOrRegisterCounter("p2p_txs_broadcasted", nil)
+	congestedTxsCounter   = metrics.GetOrRegisterCounter("p2p_txs_congested", nil) // txs only announced to congested peers
 )
 
 func errResp(code errCode, format string, v ...interface{}) error {
@@ -98,22 +103,26 @@ type processCallback struct {
 // handlerConfig is the collection of initialization parameters to create a full
 // node network handler.
 type handlerConfig struct {
-	config   Config
-	notifier dagNotifier
-	txpool   TxPool
-	engineMu sync.Locker
-	checkers *eventcheck.Checkers
-	s        *Store
-	process  processCallback
+	config    Config
+	notifier  dagNotifier
+	txpool    TxPool
+	txSigner  types.Signer
+	engineMu  sync.Locker
+	checkers  *eventcheck.Checkers
+	s         *Store
+	process   processCallback
+	timings   *eventtiming.Tracker
+	dialSched *dialsched.Scheduler
+	redial    *dialsched.Backoff
 }
 
 type handler struct {
-	NetworkID uint64
-	config    Config
+	config Config
 
 	syncStatus syncStatus
 
 	txpool   TxPool
+	txSigner types.Signer
 	maxPeers int
 
 	peers *peerSet
@@ -145,10 +154,16 @@ type handler struct {
 	checkers *eventcheck.Checkers
 
 	msgSemaphore *datasemaphore.DataSemaphore
+	dagSemaphore *datasemaphore.DataSemaphore
 
 	store    *Store
 	engineMu sync.Locker
 
+	timings   *eventtiming.Tracker
+	dialSched *dialsched.Scheduler
+	redial    *dialsched.Backoff
+	capture   *p2pcapture.Writer
+
 	notifier             dagNotifier
 	emittedEventsCh      chan *inter.EventPayload
 	emittedEventsSub     notify.Subscription
@@ -180,16 +195,19 @@ func newHandler(
 ) {
 	// Create the protocol manager with the base fields
 	h := &handler{
-		NetworkID:            c.s.GetRules().NetworkID,
 		config:               c.config,
 		notifier:             c.notifier,
 		txpool:               c.txpool,
+		txSigner:             c.txSigner,
 		msgSemaphore:         datasemaphore.New(c.config.Protocol.MsgsSemaphoreLimit, getSemaphoreWarningFn("P2P messages")),
 		store:                c.s,
 		process:              c.process,
 		checkers:             c.checkers,
 		peers:                newPeerSet(),
 		engineMu:             c.engineMu,
+		timings:              c.timings,
+		dialSched:            c.dialSched,
+		redial:               c.redial,
 		txsyncCh:             make(chan *txsync),
 		quitSync:             make(chan struct{}),
 		quitProgressBradcast: make(chan struct{}),
@@ -355,6 +373,14 @@ func newHandler(
 		Iterate: h.store.IterateEpochPacksRLP,
 	})
 
+	if path := h.config.P2PCapture.Path; path != "" {
+		var err error
+		h.capture, err = openCapture(h.config.P2PCapture)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create p2p capture %s: %w", path, err)
+		}
+	}
+
 	return h, nil
 }
 
@@ -367,6 +393,11 @@ func (h *handler) peerMisbehaviour(peer string, err error) bool {
 	return false
 }
 
+// bufferedSize returns the size of the received messages and events being processed.
+func (h *handler) bufferedSize() uint64 {
+	return h.msgSemaphore.Processing().Size + h.dagSemaphore.Processing().Size
+}
+
 func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.Processor {
 	// checkers
 	lightCheck := func(e dag.Event) error {
@@ -409,7 +440,8 @@ func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.
 		HeavyCheck: &heavycheck.EventsOnly{Checker: checkers.Heavycheck},
 		LightCheck: lightCheck,
 	}
-	newProcessor := dagprocessor.New(datasemaphore.New(h.config.Protocol.EventsSemaphoreLimit, getSemaphoreWarningFn("DAG events")), h.config.Protocol.DagProcessor, dagprocessor.Callback{
+	h.dagSemaphore = datasemaphore.New(h.config.Protocol.EventsSemaphoreLimit, getSemaphoreWarningFn("DAG events"))
+	newProcessor := dagprocessor.New(h.dagSemaphore, h.config.Protocol.DagProcessor, dagprocessor.Callback{
 		// DAG callbacks
 		Event: dagprocessor.EventCallback{
 			Process: func(_e dag.Event) error {
@@ -587,6 +619,8 @@ func (h *handler) unregisterPeer(id string) {
 	if err := h.peers.UnregisterPeer(id); err != nil {
 		log.Error("Peer removal failed", "peer", id, "err", err)
 	}
+	h.dialSched.Disconnected(peer.ID())
+	h.redial.Disconnected(peer.ID(), time.Now())
 }
 
 func (h *handler) Start(maxPeers int) {
@@ -596,8 +630,9 @@ func (h *handler) Start(maxPeers int) {
 	h.txsCh = make(chan evmcore.NewTxsNotify, txChanSize)
 	h.txsSub = h.txpool.SubscribeNewTxsNotify(h.txsCh)
 
-	h.loopsWg.Add(1)
+	h.loopsWg.Add(2)
 	go h.txBroadcastLoop()
+	go h.peerPolicyLoop()
 
 	if h.notifier != nil {
 		// broadcast mined events
@@ -635,6 +670,10 @@ func (h *handler) Start(maxPeers int) {
 	h.brSeeder.Start()
 	h.brLeecher.Start()
 	h.started.Done()
+
+	if path := h.config.P2PCapture.Replay; path != "" {
+		h.startReplay(path)
+	}
 }
 
 func (h *handler) Stop() {
@@ -685,6 +724,12 @@ func (h *handler) Stop() {
 	h.wg.Wait()
 	h.peerWG.Wait()
 
+	if h.capture != nil {
+		if err := h.capture.Close(); err != nil {
+			log.Warn("Failed to close p2p capture", "err", err)
+		}
+	}
+
 	log.Info("Fantom protocol stopped")
 }
 
@@ -742,7 +787,7 @@ func (h *handler) handle(p *peer) error {
 		genesis    = *h.store.GetGenesisID()
 		myProgress = h.myProgress()
 	)
-	if err := p.Handshake(h.NetworkID, myProgress, common.Hash(genesis)); err != nil {
+	if err := p.Handshake(h.networkID(), myProgress, common.Hash(genesis)); err != nil {
 		p.Log().Debug("Handshake failed", "err", err)
 		if !useless {
 			discfilter.Ban(p.ID())
@@ -762,11 +807,13 @@ func (h *handler) handle(p *peer) error {
 		p.Log().Warn("Peer registration failed", "err", err)
 		return err
 	}
+	h.dialSched.Connected(p.Node())
+	h.redial.Connected(p.ID(), time.Now())
 	if err := h.dagLeecher.RegisterPeer(p.id); err != nil {
 		p.Log().Warn("Leecher peer registration failed", "err", err)
 		return err
 	}
-	if p.RunningCap(ProtocolName, []uint{FTM63}) {
+	if p.RunningCap(ProtocolName, []uint{FTM63, FTM64}) {
 		if err := h.epLeecher.RegisterPeer(p.id); err != nil {
 			p.Log().Warn("Leecher peer registration failed", "err", err)
 			return err
@@ -849,6 +896,9 @@ func (h *handler) handleTxs(p *peer, txs types.Transactions) {
 		txtime.Saw(txid, now)
 		p.MarkTransaction(txid)
 	}
+	if len(txs) != 0 {
+		p.MarkUseful(now)
+	}
 	h.txpool.AddRemotes(txs)
 }
 
@@ -886,7 +936,11 @@ func (h *handler) handleEvents(p *peer, events dag.Events, ordered bool) {
 		for _, tx := range e.(inter.EventPayloadI).Txs() {
 			txtime.Saw(tx.Hash(), now)
 		}
+		h.timings.Received(e.ID(), e.Creator(), p.id, now)
 		p.MarkEvent(e.ID())
+		if !h.store.HasEvent(e.ID()) {
+			p.MarkUseful(now)
+		}
 	}
 	// filter too high events
 	notTooHigh := make(dag.Events, 0, len(events))
@@ -929,6 +983,14 @@ func (h *handler) handleMsg(p *peer) error {
 		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, protocolMaxMsgSize)
 	}
 	defer msg.Discard()
+	if chaos.DropMessage() {
+		return nil
+	}
+	if h.capture != nil {
+		if err := h.captureMsg(p, &msg); err != nil {
+			return err
+		}
+	}
 	// Acquire semaphore for serialized messages
 	eventsSizeEst := dag.Metric{
 		Num:  1,
@@ -952,6 +1014,10 @@ func (h *handler) handleMsg(p *peer) error {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		p.SetProgress(progress)
+		// a peer which isn't behind may serve the new blocks and events
+		if progress.LastBlockIdx >= h.store.GetLatestBlockIndex() {
+			p.MarkUseful(time.Now())
+		}
 
 	case msg.Code == EvmTxsMsg:
 		// Transactions arrived, make sure we have a valid and fresh graph to handle them
@@ -1321,6 +1387,9 @@ func (h *handler) BroadcastEvent(event *inter.EventPayload, passed time.Duration
 // already have the given transaction.
 func (h *handler) BroadcastTxs(txs types.Transactions) {
 	broadcastedTxsCounter.Inc(int64(txs.Len()))
+	if h.config.Protocol.TxPriority.Enabled {
+		txs = prioritizeTxs(h.txSigner, txs, h.store.GetRules().Economy.MinGasPrice)
+	}
 	var txset = make(map[*peer]types.Transactions)
 
 	// Broadcast transactions to a batch of peers not knowing about it
@@ -1335,22 +1404,40 @@ func (h *handler) BroadcastTxs(txs types.Transactions) {
 	}
 	fullRecipients := h.decideBroadcastAggressiveness(int(totalSize), time.Second, len(txset))
 	i := 0
+	sendHashes := func(peer *peer, batch types.Transactions) {
+		txids := make([]common.Hash, batch.Len())
+		for i, tx := range batch {
+			txids[i] = tx.Hash()
+		}
+		peer.AsyncSendTransactionHashes(txids, peer.queue)
+	}
 	for peer, txs := range txset {
-		SplitTransactions(txs, func(batch types.Transactions) {
+		full, announced := h.txsToPropagate(peer, txs)
+		SplitTransactions(full, func(batch types.Transactions) {
 			if i < fullRecipients {
 				peer.AsyncSendTransactions(batch, peer.queue)
 			} else {
-				txids := make([]common.Hash, batch.Len())
-				for i, tx := range batch {
-					txids[i] = tx.Hash()
-				}
-				peer.AsyncSendTransactionHashes(txids, peer.queue)
+				sendHashes(peer, batch)
 			}
 		})
+		SplitTransactions(announced, func(batch types.Transactions) {
+			sendHashes(peer, batch)
+		})
 		i++
 	}
 }
 
+// RebroadcastTxs will send a batch of transactions to all peers, whether they are known to
+// already have the given transactions or not, as the peers may have dropped them.
+func (h *handler) RebroadcastTxs(txs types.Transactions) {
+	for _, peer := range h.peers.List() {
+		SplitTransactions(txs, func(batch types.Transactions) {
+			peer.AsyncSendTransactions(batch, peer.queue)
+		})
+	}
+	log.Trace("Rebroadcast transactions", "count", len(txs))
+}
+
 // Mined broadcast loop
 func (h *handler) emittedBroadcastLoop() {
 	defer h.loopsWg.Done()
@@ -1388,6 +1475,53 @@ func (h *handler) progressBroadcastLoop() {
 	}
 }
 
+// peerPolicyLoop drops the idle peers and bypasses the re-dial backoff if there is lack of the useful peers
+func (h *handler) peerPolicyLoop() {
+	defer h.loopsWg.Done()
+	policy := h.config.Protocol.PeerPolicy
+	if policy.CheckPeriod <= 0 {
+		return
+	}
+	ticker := time.NewTicker(policy.CheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.applyPeerPolicy(time.Now())
+		case <-h.quitProgressBradcast:
+			return
+		}
+	}
+}
+
+func (h *handler) applyPeerPolicy(now time.Time) {
+	policy := h.config.Protocol.PeerPolicy
+	peers := h.peers.List()
+	useful := 0
+	idle := make([]*peer, 0, len(peers))
+	for _, p := range peers {
+		if policy.IdleTimeout <= 0 || now.Sub(p.LastUseful()) < policy.IdleTimeout {
+			useful++
+			continue
+		}
+		info := p.Peer.Info().Network
+		if !info.Trusted && !info.Static {
+			idle = append(idle, p)
+		}
+	}
+	h.redial.SetBypass(useful < policy.MinUsefulPeers)
+	// keep at least MinUsefulPeers connections, even if they are idle
+	connected := len(peers)
+	for _, p := range idle {
+		if connected <= policy.MinUsefulPeers {
+			break
+		}
+		p.Log().Debug("Dropping idle peer", "idle", common.PrettyDuration(now.Sub(p.LastUseful())))
+		h.removePeer(p.id)
+		connected--
+	}
+}
+
 func (h *handler) onNewEpochLoop() {
 	defer h.loopsWg.Done()
 	for {
@@ -1439,11 +1573,16 @@ type NodeInfo struct {
 	//Config  *params.ChainConfig `json:"config"`  // Chain configuration for the fork rules
 }
 
+// networkID returns the network ID of the handshake, which is the same as the chain ID.
+func (h *handler) networkID() uint64 {
+	return h.store.GetRules().ChainID().Uint64()
+}
+
 // NodeInfo retrieves some protocol metadata about the running host node.
 func (h *handler) NodeInfo() *NodeInfo {
 	numOfBlocks := h.store.GetLatestBlockIndex()
 	return &NodeInfo{
-		Network:     h.NetworkID,
+		Network:     h.networkID(),
 		Genesis:     common.Hash(*h.store.GetGenesisID()),
 		Epoch:       h.store.GetEpoch(),
 		NumOfBlocks: numOfBlocks,
@@ -104,6 +104,7 @@ func makeFuzzedHandler() (h *handler, err error) {
 			config:   config,
 			notifier: feed,
 			txpool:   txpool,
+			txSigner: txSigner,
 			engineMu: mu,
 			checkers: checkers,
 			s:        store,
@@ -0,0 +1,188 @@
+// Package p2pcapture implements the file format of the captured inbound protocol messages.
+// A capture starts with a header, followed by the RLP encoded records. The peers are identified
+// by pseudonyms derived with a random per-capture salt, so the captures can't be linked to the node IDs.
+package p2pcapture
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	magic         = "opera-p2p-capture"
+	formatVersion = 1
+)
+
+var (
+	// ErrNotCapture is returned if the file isn't a capture of a known format
+	ErrNotCapture = errors.New("not a p2p capture")
+)
+
+// Config is the capture mode config.
+type Config struct {
+	// Path of the capture file, capturing is disabled if empty
+	Path string
+	// Txs enables capturing of the transactions propagation, which may reveal the origin of transactions
+	Txs bool
+	// MaxSize stops capturing once the file reaches the size, 0 means no limit
+	MaxSize uint64
+	// Replay is the path of the capture, which is fed into the node once it's started
+	Replay string
+}
+
+// Record is a captured inbound message.
+type Record struct {
+	Time    uint64 // unix time in nanoseconds
+	Peer    uint64 // pseudonym of the peer
+	Version uint   // protocol version of the peer
+	Code    uint64
+	Payload []byte
+}
+
+// ReceivedAt returns the time of the message arrival.
+func (r *Record) ReceivedAt() time.Time {
+	return time.Unix(0, int64(r.Time))
+}
+
+// Writer appends the records to a capture file. It's safe for concurrent use.
+type Writer struct {
+	exclude map[uint64]bool
+	maxSize uint64
+
+	mu      sync.Mutex
+	salt    [32]byte
+	file    *os.File
+	buf     *bufio.Writer
+	size    uint64
+	stopped bool
+}
+
+// Create creates the capture file. The messages with the excluded codes are never captured.
+func Create(path string, maxSize uint64, exclude ...uint64) (*Writer, error) {
+	w := &Writer{
+		exclude: make(map[uint64]bool, len(exclude)),
+		maxSize: maxSize,
+	}
+	for _, code := range exclude {
+		w.exclude[code] = true
+	}
+	if _, err := rand.Read(w.salt[:]); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	w.file = f
+	w.buf = bufio.NewWriter(f)
+	header := append([]byte(magic), formatVersion)
+	if _, err := w.buf.Write(header); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w.size = uint64(len(header))
+	return w, nil
+}
+
+// Pseudonym returns the pseudonym of the peer within the capture.
+func (w *Writer) Pseudonym(peer string) uint64 {
+	h := sha256.New()
+	h.Write(w.salt[:])
+	h.Write([]byte(peer))
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+// Write captures the message received from the peer. Returns false if the message isn't captured
+// because its code is excluded or the size limit is reached.
+func (w *Writer) Write(peer string, version uint, code uint64, payload []byte, at time.Time) (bool, error) {
+	if w.exclude[code] {
+		return false, nil
+	}
+	rec := Record{
+		Time:    uint64(at.UnixNano()),
+		Peer:    w.Pseudonym(peer),
+		Version: version,
+		Code:    code,
+		Payload: payload,
+	}
+	raw, err := rlp.EncodeToBytes(&rec)
+	if err != nil {
+		return false, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return false, nil
+	}
+	if w.maxSize != 0 && w.size+uint64(len(raw)) > w.maxSize {
+		w.stopped = true
+		return false, nil
+	}
+	if _, err := w.buf.Write(raw); err != nil {
+		return false, err
+	}
+	w.size += uint64(len(raw))
+	return true, nil
+}
+
+// Size returns the size of the capture.
+func (w *Writer) Size() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Close flushes and closes the capture file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	if err := w.buf.Flush(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// Reader reads the records of a capture.
+type Reader struct {
+	stream *rlp.Stream
+}
+
+// NewReader checks the capture header and returns the reader of the records.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrNotCapture
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrNotCapture
+	}
+	if v := header[len(magic)]; v != formatVersion {
+		return nil, fmt.Errorf("unsupported p2p capture version %d", v)
+	}
+	return &Reader{
+		stream: rlp.NewStream(br, 0),
+	}, nil
+}
+
+// Next returns the next record, or io.EOF if the capture is over.
+func (r *Reader) Next() (*Record, error) {
+	rec := &Record{}
+	if err := r.stream.Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -0,0 +1,102 @@
+package p2pcapture
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "capture")
+	w, err := Create(path, 0, 2, 3)
+	require.NoError(err)
+
+	start := time.Unix(1700000000, 123)
+	ok, err := w.Write("peer1", 63, 7, []byte{1, 2, 3}, start)
+	require.NoError(err)
+	require.True(ok)
+	// excluded codes aren't captured
+	ok, err = w.Write("peer1", 63, 2, []byte{4}, start)
+	require.NoError(err)
+	require.False(ok)
+	ok, err = w.Write("peer2", 62, 5, nil, start.Add(time.Second))
+	require.NoError(err)
+	require.True(ok)
+	ok, err = w.Write("peer1", 63, 1, []byte{5}, start.Add(2*time.Second))
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(w.Close())
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	r, err := NewReader(f)
+	require.NoError(err)
+
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		records = append(records, rec)
+	}
+	require.Len(records, 3)
+	require.Equal(uint64(7), records[0].Code)
+	require.Equal(uint(63), records[0].Version)
+	require.Equal([]byte{1, 2, 3}, records[0].Payload)
+	require.Equal(start, records[0].ReceivedAt())
+	require.Equal(uint64(5), records[1].Code)
+	require.Empty(records[1].Payload)
+
+	// peers are pseudonymized consistently within the capture
+	require.Equal(records[0].Peer, records[2].Peer)
+	require.NotEqual(records[0].Peer, records[1].Peer)
+	require.Equal(w.Pseudonym("peer1"), records[0].Peer)
+
+	// but not across the captures
+	other, err := Create(filepath.Join(t.TempDir(), "other"), 0)
+	require.NoError(err)
+	defer other.Close()
+	require.NotEqual(w.Pseudonym("peer1"), other.Pseudonym("peer1"))
+}
+
+func TestCaptureMaxSize(t *testing.T) {
+	require := require.New(t)
+
+	w, err := Create(filepath.Join(t.TempDir(), "capture"), 100)
+	require.NoError(err)
+	defer w.Close()
+
+	ok, err := w.Write("peer", 63, 7, make([]byte, 10), time.Now())
+	require.NoError(err)
+	require.True(ok)
+	ok, err = w.Write("peer", 63, 7, make([]byte, 100), time.Now())
+	require.NoError(err)
+	require.False(ok)
+	// capturing is stopped once the limit is reached
+	ok, err = w.Write("peer", 63, 7, nil, time.Now())
+	require.NoError(err)
+	require.False(ok)
+	require.LessOrEqual(w.Size(), uint64(100))
+}
+
+func TestReaderRejectsNonCapture(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("not a capture at all")))
+	require.ErrorIs(t, err, ErrNotCapture)
+
+	_, err = NewReader(bytes.NewReader(nil))
+	require.ErrorIs(t, err, ErrNotCapture)
+
+	_, err = NewReader(bytes.NewReader(append([]byte(magic), formatVersion+1)))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotCapture)
+}
@@ -34,6 +34,7 @@ var (
 	droppedTxsPromotedCounter = metrics.GetOrRegisterCounter("p2p_dropped_txs_promoted", nil)
 	sentTxsRequestedCounter = metrics.GetOrRegisterCounter("p2p_sent_txs_requested", nil)
 	sentTxHashesCounter = metrics.GetOrRegisterCounter("p2p_sent_tx_hashes", nil)
+	batchedEventsMsgsCounter = metrics.GetOrRegisterCounter("p2p_batched_events_msgs", nil) // EventsMsg saved by batching
 )
 
 const (
@@ -72,6 +73,8 @@ type peer struct {
 	progress PeerProgress
 
 	useless uint32
+	// lastUseful is the unix time in nanoseconds of the last new event, transaction or progress received from the peer
+	lastUseful int64
 
 	sync.RWMutex
 }
@@ -84,6 +87,16 @@ func (p *peer) SetUseless() {
 	atomic.StoreUint32(&p.useless, 1)
 }
 
+// MarkUseful records that the peer has sent a useful message.
+func (p *peer) MarkUseful(now time.Time) {
+	atomic.StoreInt64(&p.lastUseful, now.UnixNano())
+}
+
+// LastUseful returns the time of the last useful message of the peer, or of the connection if there were none.
+func (p *peer) LastUseful() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&p.lastUseful))
+}
+
 func (p *peer) SetProgress(x PeerProgress) {
 	p.Lock()
 	defer p.Unlock()
@@ -129,6 +142,7 @@ func newPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, cfg PeerCacheConfi
 		queue:               make(chan broadcastItem, cfg.MaxQueuedItems),
 		queuedDataSemaphore: datasemaphore.New(dag.Metric{cfg.MaxQueuedItems, cfg.MaxQueuedSize}, getSemaphoreWarningFn("Peers queue")),
 		term:                make(chan struct{}),
+		lastUseful:          time.Now().UnixNano(),
 	}
 
 	go peer.broadcast(peer.queue)
@@ -140,16 +154,85 @@ func newPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, cfg PeerCacheConfi
 // and transaction broadcasts into the remote peer. The goal is to have an async
 // writer that does not lock up node internals.
 func (p *peer) broadcast(queue chan broadcastItem) {
+	batchEvents := p.cfg.BatchEvents && p.version >= FTM64
+	var next *broadcastItem
 	for {
+		var item broadcastItem
+		if next != nil {
+			item, next = *next, nil
+		} else {
+			select {
+			case item = <-queue:
+			case <-p.term:
+				return
+			}
+		}
+		if batchEvents && item.Code == EventsMsg {
+			next = p.sendEventsBatch(item, queue)
+			continue
+		}
+		_ = p2p.Send(p.rw, item.Code, item.Raw)
+		p.queuedDataSemaphore.Release(memSize(item.Raw))
+	}
+}
+
+// sendEventsBatch sends the events of the item together with the events of the EventsMsg items queued
+// right after it, as a single message. It never waits for more events, so the batches are formed only
+// during the bursts when the broadcasts are queued faster than they're sent.
+// Returns the queued item which didn't fit into the batch, if any.
+func (p *peer) sendEventsBatch(first broadcastItem, queue chan broadcastItem) *broadcastItem {
+	items := []broadcastItem{first}
+	events, err := splitRLPList(first.Raw)
+	size := len(first.Raw)
+	var next *broadcastItem
+batching:
+	for err == nil && len(events) < softLimitItems && size < softResponseLimitSize {
 		select {
 		case item := <-queue:
-			_ = p2p.Send(p.rw, item.Code, item.Raw)
-			p.queuedDataSemaphore.Release(memSize(item.Raw))
+			if item.Code != EventsMsg {
+				next = &item
+				break batching
+			}
+			itemEvents, err := splitRLPList(item.Raw)
+			if err != nil || len(events)+len(itemEvents) > softLimitItems || size+len(item.Raw) > softResponseLimitSize {
+				next = &item
+				break batching
+			}
+			items = append(items, item)
+			events = append(events, itemEvents...)
+			size += len(item.Raw)
+		default:
+			break batching
+		}
+	}
+	if len(items) == 1 {
+		_ = p2p.Send(p.rw, first.Code, first.Raw)
+	} else {
+		_ = p2p.Send(p.rw, EventsMsg, events)
+		batchedEventsMsgsCounter.Inc(int64(len(items) - 1))
+	}
+	for _, item := range items {
+		p.queuedDataSemaphore.Release(memSize(item.Raw))
+	}
+	return next
+}
 
-		case <-p.term:
-			return
+// splitRLPList returns the raw elements of the RLP list.
+func splitRLPList(raw rlp.RawValue) ([]rlp.RawValue, error) {
+	content, _, err := rlp.SplitList(raw)
+	if err != nil {
+		return nil, err
+	}
+	var elems []rlp.RawValue
+	for len(content) != 0 {
+		_, _, rest, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
 		}
+		elems = append(elems, content[:len(content)-len(rest)])
+		content = rest
 	}
+	return elems, nil
 }
 
 // Close signals the broadcast goroutine to terminate.
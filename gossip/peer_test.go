@@ -0,0 +1,68 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/utils/datasemaphore"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendEventsBatch(t *testing.T) {
+	require := require.New(t)
+
+	local, remote := p2p.MsgPipe()
+	defer local.Close()
+	defer remote.Close()
+	p := &peer{
+		version:             FTM64,
+		rw:                  local,
+		queuedDataSemaphore: datasemaphore.New(dag.Metric{Num: 10, Size: 1 << 20}, getSemaphoreWarningFn("test")),
+		term:                make(chan struct{}),
+	}
+	queue := make(chan broadcastItem, 10)
+	enqueue := func(code uint64, v interface{}) broadcastItem {
+		raw, err := rlp.EncodeToBytes(v)
+		require.NoError(err)
+		require.True(p.queuedDataSemaphore.TryAcquire(memSize(raw)))
+		item := broadcastItem{Code: code, Raw: raw}
+		queue <- item
+		return item
+	}
+	enqueue(EventsMsg, []uint{1})
+	enqueue(EventsMsg, []uint{2, 3})
+	progress := enqueue(ProgressMsg, []uint{7})
+	enqueue(EventsMsg, []uint{4})
+
+	nextC := make(chan *broadcastItem, 1)
+	go func() {
+		nextC <- p.sendEventsBatch(<-queue, queue)
+	}()
+	msg, err := remote.ReadMsg()
+	require.NoError(err)
+	require.Equal(uint64(EventsMsg), msg.Code)
+	var events []uint
+	require.NoError(msg.Decode(&events))
+	// the events queued before another message are sent together
+	require.Equal([]uint{1, 2, 3}, events)
+	require.Equal(&progress, <-nextC)
+	require.Len(queue, 1)
+}
+
+func TestSplitRLPList(t *testing.T) {
+	require := require.New(t)
+
+	raw, err := rlp.EncodeToBytes([]string{"a", "bc", ""})
+	require.NoError(err)
+	elems, err := splitRLPList(raw)
+	require.NoError(err)
+	require.Len(elems, 3)
+	var s string
+	require.NoError(rlp.DecodeBytes(elems[1], &s))
+	require.Equal("bc", s)
+
+	_, err = splitRLPList(rlp.RawValue{0x01})
+	require.Error(err)
+}
@@ -0,0 +1,67 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+)
+
+func TestGetProofs(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv(2, 2, t)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, big.NewInt(1)))
+	require.NoError(err)
+	require.Len(receipts, 1)
+	// the proofs are served by the archive state of the latest block
+	block := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	api := ethapi.NewPublicBlockChainAPI(env.EthAPI)
+	requests := []ethapi.ProofRequest{
+		{Address: env.Address(1), StorageKeys: []string{"0x01"}},
+		{Address: env.Address(2)},
+	}
+	res, err := api.GetProofs(context.Background(), requests, ethapi.BlockNumberOrHash(block))
+	require.NoError(err)
+	require.Len(res.Accounts, 2)
+
+	nodes := memorydb.New()
+	for _, node := range res.Nodes {
+		b, err := hexutil.Decode(node)
+		require.NoError(err)
+		require.NoError(nodes.Put(crypto.Keccak256(b), b))
+	}
+	for i, acc := range res.Accounts {
+		require.Equal(requests[i].Address, acc.Address)
+		// the nodes shared by the proofs are sent once
+		require.Equal(0, acc.AccountProof[0])
+
+		value, err := trie.VerifyProof(res.Root, crypto.Keccak256(acc.Address[:]), nodes)
+		require.NoError(err)
+		var account ethstate.Account
+		require.NoError(rlp.DecodeBytes(value, &account))
+		require.Equal(acc.Balance.ToInt(), account.Balance)
+		require.Equal(uint64(acc.Nonce), account.Nonce)
+
+		single, err := api.GetProof(context.Background(), acc.Address, requests[i].StorageKeys, ethapi.BlockNumberOrHash(block))
+		require.NoError(err)
+		require.Len(acc.AccountProof, len(single.AccountProof))
+		for j, ref := range acc.AccountProof {
+			require.Equal(single.AccountProof[j], res.Nodes[ref])
+		}
+	}
+	require.Len(res.Accounts[0].StorageProof, 1)
+}
@@ -19,17 +19,18 @@ import (
 const (
 	FTM62           = 62
 	FTM63           = 63
-	ProtocolVersion = FTM63
+	FTM64           = 64 // accepts the broadcasted events batched into a single EventsMsg
+	ProtocolVersion = FTM64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "opera"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{FTM62, FTM63}
+var ProtocolVersions = []uint{FTM62, FTM63, FTM64}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1}
+var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1, FTM64: EPsStreamResponse + 1}
 
 const protocolMaxMsgSize = inter.ProtocolMaxMsgSize // Maximum cap on the size of a protocol message
 
@@ -112,6 +113,7 @@ type TxPool interface {
 	AddLocal(tx *types.Transaction) error
 
 	Get(common.Hash) *types.Transaction
+	Dropped(common.Hash) (evmcore.TxDrop, bool)
 
 	OnlyNotExisting(hashes []common.Hash) []common.Hash
 	SampleHashes(max int) []common.Hash
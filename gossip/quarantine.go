@@ -0,0 +1,114 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// apiTablesScanner verifies the API-only tables in background, so the corrupted data is quarantined
+// and the rest of the data is served while the quarantined data is repaired.
+type apiTablesScanner struct {
+	svc *Service
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newApiTablesScanner(svc *Service) *apiTablesScanner {
+	return &apiTablesScanner{
+		svc: svc,
+	}
+}
+
+func (sc *apiTablesScanner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	sc.cancel = cancel
+	sc.wg.Add(1)
+	go func() {
+		defer sc.wg.Done()
+		sc.scan(ctx)
+	}()
+}
+
+func (sc *apiTablesScanner) Stop() {
+	sc.cancel()
+	sc.wg.Wait()
+}
+
+func (sc *apiTablesScanner) scan(ctx context.Context) {
+	sc.svc.Log.Info("Verifying API-only tables")
+	corrupted, err := sc.svc.store.evm.ScanApiTables(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			sc.svc.Log.Error("Failed to verify API-only tables", "err", err)
+		}
+		return
+	}
+	if corrupted == 0 {
+		sc.svc.Log.Info("API-only tables are verified")
+		return
+	}
+	sc.svc.Log.Warn("Corrupted entries of API-only tables are quarantined", "entries", corrupted)
+	sc.svc.store.evm.RepairQuarantined(sc.reexecute)
+}
+
+// reexecute rebuilds the receipts of the block by its re-execution on the archive state of the previous block.
+func (sc *apiTablesScanner) reexecute(n idx.Block) (types.Receipts, error) {
+	b := sc.svc.EthAPI
+	block := b.state.GetBlock(common.Hash{}, uint64(n))
+	if block == nil || n == 0 {
+		return nil, errors.New("block is missing")
+	}
+	parent := b.state.GetHeader(common.Hash{}, uint64(n-1))
+	if parent == nil {
+		return nil, errors.New("parent block is missing")
+	}
+	statedb, err := sc.svc.store.evm.GetRpcStateDb(parent.Number, parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer statedb.Release()
+
+	var gasUsed uint64
+	processor := evmcore.NewStateProcessor(b.ChainConfig(), b.state)
+	receipts, _, skipped, err := processor.Process(block, statedb, evmcore.VMConfig(b.blockRules(n)), &gasUsed, func(*types.Log) {})
+	if err != nil {
+		return nil, err
+	}
+	// the skipped transactions aren't in the block, so the re-execution must include all of them
+	if len(skipped) != 0 || len(receipts) != len(block.Transactions) || gasUsed != block.GasUsed {
+		return nil, fmt.Errorf("re-execution differs from the block, skipped %d txs, gas used %d instead of %d", len(skipped), gasUsed, block.GasUsed)
+	}
+	return receipts, nil
+}
+
+// PrivateQuarantineAPI provides an admin API to inspect the data quarantined as corrupted.
+type PrivateQuarantineAPI struct {
+	s *Service
+}
+
+// NewPrivateQuarantineAPI creates a new quarantine admin API.
+func NewPrivateQuarantineAPI(s *Service) *PrivateQuarantineAPI {
+	return &PrivateQuarantineAPI{s}
+}
+
+// Quarantined returns the blocks of each API-only table, the data of which is quarantined as corrupted.
+func (api *PrivateQuarantineAPI) Quarantined() map[string][]hexutil.Uint64 {
+	res := make(map[string][]hexutil.Uint64)
+	for table, blocks := range api.s.store.evm.Quarantined() {
+		res[table] = make([]hexutil.Uint64, len(blocks))
+		for i, n := range blocks {
+			res[table][i] = hexutil.Uint64(n)
+		}
+	}
+	return res
+}
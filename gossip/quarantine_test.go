@@ -0,0 +1,43 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestRepairQuarantinedReceipts(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 2, t)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, big.NewInt(1)), env.Transfer(2, 1, big.NewInt(2)))
+	require.NoError(err)
+	n := idx.Block(receipts[0].BlockNumber.Uint64())
+	block := env.EthAPI.state.GetBlock(receipts[0].BlockHash, uint64(n))
+	require.NotNil(block)
+	want, err := env.store.evm.GetReceipts(n, env.EthAPI.signer, block.Hash, block.Transactions)
+	require.NoError(err)
+
+	// corrupt the receipts of the block, in the receipts table of the EVM store
+	require.NoError(env.store.mainDB.Put(append([]byte("r"), n.Bytes()...), []byte{0xff, 0x01}))
+	corrupted, err := env.store.evm.ScanApiTables(context.Background())
+	require.NoError(err)
+	require.Equal(1, corrupted)
+	require.Equal([]idx.Block{n}, env.store.evm.Quarantined()[evmstore.ReceiptsTable])
+
+	// the receipts are rebuilt by the re-execution of the block
+	env.store.evm.RepairQuarantined(newApiTablesScanner(env.Service).reexecute)
+	require.Empty(env.store.evm.Quarantined())
+	got, err := env.store.evm.GetReceipts(n, env.EthAPI.signer, block.Hash, block.Transactions)
+	require.NoError(err)
+	require.Equal(want, got)
+}
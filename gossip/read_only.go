@@ -0,0 +1,49 @@
+package gossip
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var errReadOnly = errors.New("node is in read-only mode")
+
+// ReadOnly returns true if the node is in the read-only mode.
+func (s *Service) ReadOnly() bool {
+	return s.handler.syncStatus.ReadOnly()
+}
+
+// SetReadOnly switches the read-only mode and returns the previous state.
+// In the read-only mode the node neither emits events nor accepts transactions, neither from RPC nor from peers,
+// but keeps processing the DAG of other validators and serving RPC from the current data.
+// The mode isn't persisted, the node is started in the normal mode.
+func (s *Service) SetReadOnly(readOnly bool) bool {
+	prev := s.handler.syncStatus.SetReadOnly(readOnly)
+	for _, em := range s.emitters {
+		em.SetPaused(readOnly)
+	}
+	if prev != readOnly {
+		log.Warn("Read-only mode switched", "enabled", readOnly)
+	}
+	return prev
+}
+
+// PrivateReadOnlyAPI provides an admin API to switch the read-only mode for the incident response.
+type PrivateReadOnlyAPI struct {
+	s *Service
+}
+
+// NewPrivateReadOnlyAPI creates a new read-only mode admin API.
+func NewPrivateReadOnlyAPI(s *Service) *PrivateReadOnlyAPI {
+	return &PrivateReadOnlyAPI{s}
+}
+
+// ReadOnly returns true if the node is in the read-only mode.
+func (api *PrivateReadOnlyAPI) ReadOnly() bool {
+	return api.s.ReadOnly()
+}
+
+// SetReadOnly switches the read-only mode and returns the previous state.
+func (api *PrivateReadOnlyAPI) SetReadOnly(readOnly bool) bool {
+	return api.s.SetReadOnly(readOnly)
+}
@@ -0,0 +1,141 @@
+package gossip
+
+import (
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	notify "github.com/ethereum/go-ethereum/event"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
+)
+
+// receiptsPruneBatch is a number of blocks pruned at once between the checks of the stop request
+const receiptsPruneBatch = 1000
+
+// receiptsPruner removes in background the receipts, txs positions, txs senders and logs index of the blocks
+// older than the retention window of epochs. It's checked once the epoch is sealed.
+type receiptsPruner struct {
+	svc       *Service
+	retention idx.Epoch
+
+	wake chan struct{}
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newReceiptsPruner(svc *Service, retention idx.Epoch) *receiptsPruner {
+	return &receiptsPruner{
+		svc:       svc,
+		retention: retention,
+		wake:      make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+	}
+}
+
+func (p *receiptsPruner) Start() {
+	heads := make(chan evmcore.ChainHeadNotify, 16)
+	sub := p.svc.feed.SubscribeNewBlock(heads)
+
+	p.wg.Add(2)
+	go p.listen(heads, sub)
+	go p.loop()
+	// prune the blocks left behind by the previous run
+	p.notify()
+}
+
+func (p *receiptsPruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *receiptsPruner) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *receiptsPruner) listen(heads <-chan evmcore.ChainHeadNotify, sub notify.Subscription) {
+	defer p.wg.Done()
+	defer sub.Unsubscribe()
+	var epoch idx.Epoch
+	for {
+		select {
+		case <-heads:
+			if e := p.svc.store.GetEpoch(); e != epoch {
+				epoch = e
+				p.notify()
+			}
+		case <-sub.Err():
+			return
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *receiptsPruner) loop() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.wake:
+		case <-p.quit:
+			return
+		}
+		p.prune()
+	}
+}
+
+// prune removes the data of the blocks before the first block of the retention window.
+func (p *receiptsPruner) prune() {
+	epoch := p.svc.store.GetEpoch()
+	if epoch <= p.retention {
+		return
+	}
+	// the history block state of an epoch refers to the last block of the previous epoch
+	bs, _ := p.svc.store.GetHistoryBlockEpochState(epoch - p.retention + 1)
+	if bs == nil {
+		return
+	}
+	to := bs.LastBlock.Idx
+
+	pruned := 0
+	for {
+		blocks := p.svc.store.evm.ReceiptsBlocks(to, receiptsPruneBatch)
+		if len(blocks) == 0 {
+			break
+		}
+		for _, n := range blocks {
+			block := p.svc.EthAPI.state.GetBlock(common.Hash{}, uint64(n))
+			if block == nil {
+				// the receipts record is deleted anyway, only its logs and tx positions can't be found
+				p.svc.Log.Warn("Failed to prune receipts logs, block is missing", "block", n)
+				p.svc.store.evm.DelReceipts(n, nil)
+				continue
+			}
+			receipts, err := p.svc.store.evm.GetReceipts(n, p.svc.EthAPI.signer, block.Hash, block.Transactions)
+			if err != nil {
+				// the record is deleted anyway, only its logs and tx positions can't be found
+				p.svc.Log.Warn("Failed to read pruned receipts", "block", n, "err", err)
+			}
+			p.svc.store.evm.DelReceipts(n, receipts)
+			for i, tx := range block.Transactions {
+				if sender, err := internaltx.Sender(p.svc.EthAPI.signer, tx); err == nil {
+					p.svc.store.evm.DelTxSender(sender, n, uint32(i))
+				}
+			}
+		}
+		pruned += len(blocks)
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+	}
+	if pruned != 0 {
+		p.svc.Log.Info("Pruned receipts", "blocks", pruned, "to", to)
+	}
+}
@@ -0,0 +1,59 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestRemovedLogsNotify(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 2, t)
+	defer env.Close()
+
+	// the number of the blocks processed before the tx is included depends on the events emission
+	const window = 1000
+	removed := make(chan evmcore.RemovedLogsNotify, window)
+	sub := env.feed.SubscribeRemovedLogs(removed)
+	defer sub.Unsubscribe()
+
+	// the receipts of the next blocks are indexed before the blocks are processed, e.g. by block records
+	latest := env.store.GetLatestBlockIndex()
+	for i := 1; i <= window; i++ {
+		n := latest + idx.Block(i)
+		env.store.evm.SetReceipts(n, types.Receipts{{
+			Status: types.ReceiptStatusSuccessful,
+			Logs:   []*types.Log{{Address: common.BigToAddress(big.NewInt(int64(i)))}},
+		}})
+	}
+
+	receipts, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, big.NewInt(1)))
+	require.NoError(err)
+	block := idx.Block(receipts[0].BlockNumber.Uint64())
+	require.Greater(block, latest)
+	require.LessOrEqual(block, latest+window)
+
+	for {
+		select {
+		case notify := <-removed:
+			require.Len(notify.Logs, 1)
+			require.True(notify.Logs[0].Removed)
+			require.Equal(common.BigToAddress(big.NewInt(int64(notify.Logs[0].BlockNumber)-int64(latest))), notify.Logs[0].Address)
+			if notify.Logs[0].BlockNumber == uint64(block) {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("removed logs aren't notified")
+		}
+	}
+}
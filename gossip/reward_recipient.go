@@ -0,0 +1,83 @@
+package gossip
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+)
+
+var errNotValidator = errors.New("node isn't running a validator")
+
+// PrivateValidatorAPI provides an admin API to manage the validator settings of the node.
+type PrivateValidatorAPI struct {
+	s *Service
+}
+
+// NewPrivateValidatorAPI creates a new validator admin API.
+func NewPrivateValidatorAPI(s *Service) *PrivateValidatorAPI {
+	return &PrivateValidatorAPI{s}
+}
+
+func (s *Service) validatorEmitter() *emitter.Emitter {
+	if len(s.emitters) == 0 {
+		return nil
+	}
+	return s.emitters[0]
+}
+
+// RewardRecipient returns the reward recipient of the validator.
+func (api *PrivateValidatorAPI) RewardRecipient() (common.Address, error) {
+	em := api.s.validatorEmitter()
+	if em == nil {
+		return common.Address{}, errNotValidator
+	}
+	return em.RewardRecipient(), nil
+}
+
+// SetRewardRecipient updates the reward recipient of the validator and returns the previous one.
+// The change is recorded into the audit log. It isn't persisted, the configured recipient is used after a restart.
+func (api *PrivateValidatorAPI) SetRewardRecipient(addr common.Address) (common.Address, error) {
+	em := api.s.validatorEmitter()
+	if em == nil {
+		return common.Address{}, errNotValidator
+	}
+	prev := em.SetRewardRecipient(addr)
+	auditRewardRecipient(api.s.config.RewardRecipientAudit, em, prev, addr)
+	return prev, nil
+}
+
+type rewardRecipientAuditRecord struct {
+	Time      time.Time      `json:"time"`
+	Validator uint32         `json:"validator"`
+	Previous  common.Address `json:"previous"`
+	Recipient common.Address `json:"recipient"`
+}
+
+// auditRewardRecipient records the reward recipient change into the audit log.
+func auditRewardRecipient(path string, em *emitter.Emitter, prev, addr common.Address) {
+	log.Warn("Validator reward recipient changed", "validator", em.ValidatorID(), "previous", prev, "recipient", addr)
+	if len(path) == 0 {
+		return
+	}
+	b, _ := json.Marshal(rewardRecipientAuditRecord{
+		Time:      time.Now().UTC(),
+		Validator: uint32(em.ValidatorID()),
+		Previous:  prev,
+		Recipient: addr,
+	})
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Error("Failed to open reward recipient audit log", "path", path, "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		log.Error("Failed to write reward recipient audit log", "path", path, "err", err)
+	}
+}
@@ -0,0 +1,53 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+var (
+	errRollbackDisabled  = errors.New("rollback is disabled (enable it with --rpc.allowrollback)")
+	errRollbackValidator = errors.New("rollback is not allowed on validator nodes, as it may lead to double-signing")
+)
+
+// rollbackTargetEpoch returns the epoch checkpoint the processed head has to be rolled back to,
+// for reverting the given block.
+func (s *Service) rollbackTargetEpoch(block idx.Block) (idx.Epoch, error) {
+	if !s.config.AllowRollback {
+		return 0, errRollbackDisabled
+	}
+	if len(s.emitters) != 0 {
+		return 0, errRollbackValidator
+	}
+	if block == 0 || block > s.store.GetLatestBlockIndex() {
+		return 0, fmt.Errorf("block %d is out of range", block)
+	}
+	blk := s.store.GetBlock(block)
+	if blk == nil {
+		return 0, fmt.Errorf("block %d is not found", block)
+	}
+	epoch := blk.Atropos.Epoch()
+	bs, es := s.store.GetHistoryBlockEpochState(epoch)
+	if bs == nil || es == nil {
+		return 0, fmt.Errorf("checkpoint of epoch %d is not available", epoch)
+	}
+	return epoch, nil
+}
+
+// scheduleRollback persists the rollback target and stops events processing.
+// The rollback is applied by healing the databases of the stopped node.
+func (s *Service) scheduleRollback(epoch idx.Epoch) error {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	if s.stopped {
+		return errStopped
+	}
+	s.stopped = true
+	s.blockProcWg.Wait()
+
+	s.store.SetRollbackTarget(epoch)
+	s.Log.Warn("Events processing stopped due to a scheduled rollback", "epoch", epoch)
+	return s.store.Commit()
+}
@@ -40,12 +40,18 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/blockproc/evmmodule"
 	"github.com/Fantom-foundation/go-opera/gossip/blockproc/sealmodule"
 	"github.com/Fantom-foundation/go-opera/gossip/blockproc/verwatcher"
+	"github.com/Fantom-foundation/go-opera/gossip/blocktiming"
+	"github.com/Fantom-foundation/go-opera/gossip/dialsched"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+	"github.com/Fantom-foundation/go-opera/gossip/eventtiming"
 	"github.com/Fantom-foundation/go-opera/gossip/filters"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
 	"github.com/Fantom-foundation/go-opera/gossip/proclogger"
+	"github.com/Fantom-foundation/go-opera/gossip/telemetry"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils/chaos"
+	"github.com/Fantom-foundation/go-opera/utils/membudget"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 	"github.com/Fantom-foundation/go-opera/utils/txtime"
 	"github.com/Fantom-foundation/go-opera/utils/wgmutex"
@@ -53,6 +59,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/vecmt"
 )
 
+// memBudgetPeriod is a period of enforcing the memory budget.
+const memBudgetPeriod = 3 * time.Second
+
 type ServiceFeed struct {
 	scope notify.SubscriptionScope
 
@@ -60,6 +69,7 @@ type ServiceFeed struct {
 	newEmittedEvent notify.Feed
 	newBlock        notify.Feed
 	newLogs         notify.Feed
+	removedLogs     notify.Feed
 }
 
 func (f *ServiceFeed) SubscribeNewEpoch(ch chan<- idx.Epoch) notify.Subscription {
@@ -78,6 +88,10 @@ func (f *ServiceFeed) SubscribeNewLogs(ch chan<- []*types.Log) notify.Subscripti
 	return f.scope.Track(f.newLogs.Subscribe(ch))
 }
 
+func (f *ServiceFeed) SubscribeRemovedLogs(ch chan<- evmcore.RemovedLogsNotify) notify.Subscription {
+	return f.scope.Track(f.removedLogs.Subscribe(ch))
+}
+
 type BlockProc struct {
 	SealerModule     blockproc.SealerModule
 	TxListenerModule blockproc.TxListenerModule
@@ -130,6 +144,7 @@ type Service struct {
 
 	blockBusyFlag uint32
 	eventBusyFlag uint32
+	executionLag  int64 // age of the last executed block at the moment of its execution, in nanoseconds
 
 	feed     ServiceFeed
 
@@ -139,17 +154,44 @@ type Service struct {
 	handler *handler
 
 	operaDialCandidates enode.Iterator
+	dialSched           *dialsched.Scheduler
+	redial              *dialsched.Backoff
 
 	EthAPI        *EthAPIBackend
 	netRPCService *ethapi.PublicNetAPI
+	traceSandbox  *ethapi.TraceSandbox
+	// historical states pinned by debug_openState
+	stateSessions *ethapi.StateSessions
 
-	procLogger *proclogger.Logger
+	procLogger   *proclogger.Logger
+	timings      *eventtiming.Tracker
+	blockTimings *blocktiming.Tracker
 
 	stopped   bool
 	haltCheck func(oldEpoch, newEpoch idx.Epoch, time time.Time) bool
+	// stoppedCleanly is set once the service is stopped and the final commit succeeded
+	stoppedCleanly bool
 
 	tflusher PeriodicFlusher
 
+	apiTablesScanner *apiTablesScanner
+
+	receiptsPruner *receiptsPruner
+
+	// rebroadcaster of the local transactions, nil if disabled
+	txRebroadcaster *txRebroadcaster
+
+	denyList *denyList
+
+	// notifier of the webhooks, nil if disabled
+	webhooks *webhookWatcher
+
+	// reporter of the anonymized telemetry, nil if disabled
+	telemetry *telemetry.Reporter
+
+	// accountant of the memory budget, nil if disabled
+	memBudget *membudget.Accountant
+
 	bootstrapping bool
 
 	logger.Instance
@@ -161,6 +203,9 @@ func NewService(stack *node.Node, config Config, store *Store, blockProc BlockPr
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
+	if err := store.verifyHistory(); err != nil {
+		return nil, err
+	}
 
 	svc, err := newService(config, store, blockProc, engine, dagIndexer, newTxPool)
 	if err != nil {
@@ -171,7 +216,7 @@ func NewService(stack *node.Node, config Config, store *Store, blockProc BlockPr
 	svc.accountManager = stack.AccountManager()
 	svc.EthAPI.SetExtRPCEnabled(stack.Config().ExtRPCEnabled())
 	// Create the net API service
-	svc.netRPCService = ethapi.NewPublicNetAPI(svc.p2pServer, store.GetRules().NetworkID)
+	svc.netRPCService = ethapi.NewPublicNetAPI(svc.p2pServer, svc.EthAPI)
 	svc.haltCheck = haltCheck
 
 	return svc, nil
@@ -189,6 +234,8 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 		engineMu:           new(sync.RWMutex),
 		uniqueEventIDs:     uniqueID{new(big.Int)},
 		procLogger:         proclogger.NewLogger(),
+		timings:            eventtiming.New(),
+		blockTimings:       blocktiming.New(),
 		Instance:           logger.New("gossip-service"),
 	}
 
@@ -218,7 +265,7 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 
 	// create checkers
 	net := store.GetRules()
-	txSigner := gsignercache.Wrap(types.LatestSignerForChainID(new(big.Int).SetUint64(net.NetworkID)))
+	txSigner := gsignercache.Wrap(types.LatestSignerForChainID(net.ChainID()))
 	svc.heavyCheckReader.Store = store
 	svc.heavyCheckReader.Pubkeys.Store(readEpochPubKeys(svc.store, svc.store.GetEpoch()))                                          // read pub keys of current epoch from DB
 	svc.gasPowerCheckReader.Ctx.Store(NewGasPowerContext(svc.store, svc.store.GetValidators(), svc.store.GetEpoch(), net.Economy)) // read gaspower check data from DB
@@ -235,17 +282,28 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 	if err != nil {
 		return nil, err
 	}
+	svc.redial = dialsched.NewBackoff(config.Protocol.PeerPolicy.Redial)
+	svc.operaDialCandidates = svc.redial.Filter(svc.operaDialCandidates)
+	if config.Dial.Enabled {
+		svc.dialSched = dialsched.New(config.Dial, svc.operaDialCandidates, dialsched.TCPProbe)
+		svc.operaDialCandidates = svc.dialSched
+	}
 
 	// create protocol manager
 	svc.handler, err = newHandler(handlerConfig{
-		config:   config,
-		notifier: &svc.feed,
-		txpool:   svc.txpool,
-		engineMu: svc.engineMu,
-		checkers: svc.checkers,
-		s:        store,
+		config:    config,
+		notifier:  &svc.feed,
+		txpool:    svc.txpool,
+		txSigner:  txSigner,
+		engineMu:  svc.engineMu,
+		checkers:  svc.checkers,
+		s:         store,
+		timings:   svc.timings,
+		dialSched: svc.dialSched,
+		redial:    svc.redial,
 		process: processCallback{
 			Event: func(event *inter.EventPayload) error {
+				svc.timings.Validated(event.ID(), time.Now())
 				done := svc.procLogger.EventConnectionStarted(event, false)
 				defer done()
 				return svc.processEvent(event)
@@ -265,12 +323,50 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 	rpc.SetBatchItemLimit(config.BatchRequestLimit)
 	tracers.SetConcurentJSLimit(config.JSTracerLimit)
 	ethapi.SetResponseSizeLimit(config.MaxResponseSize)
+	ethapi.SetSlowCallThreshold(config.RPCSlowCallThreshold)
 
 	// create API backend
+	svc.traceSandbox = ethapi.NewTraceSandbox(config.RPCTraceWorkers)
+	svc.stateSessions = ethapi.NewStateSessions()
 	svc.EthAPI = &EthAPIBackend{false, svc, stateReader, txSigner, config.AllowUnprotectedTxs}
 
 	svc.verWatcher = verwatcher.New(netVerStore)
 	svc.tflusher = svc.makePeriodicFlusher()
+	if config.ScanApiTables {
+		svc.apiTablesScanner = newApiTablesScanner(svc)
+	}
+	if retention := store.cfg.EVM.ReceiptsRetentionEpochs; retention != 0 {
+		svc.receiptsPruner = newReceiptsPruner(svc, retention)
+	}
+	if config.TxRebroadcast.Interval != 0 {
+		svc.txRebroadcaster = newTxRebroadcaster(svc)
+	}
+	if len(config.RPCDenyList) != 0 {
+		svc.denyList, err = newDenyList(config.RPCDenyList, config.RPCDenyListAudit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load RPC deny list: %w", err)
+		}
+	}
+	if config.Webhooks.Enabled() {
+		if err := config.Webhooks.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid webhooks config: %w", err)
+		}
+		svc.webhooks = newWebhookWatcher(svc)
+	}
+	if config.Telemetry.Enabled() {
+		if err := config.Telemetry.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid telemetry config: %w", err)
+		}
+		svc.telemetry = newTelemetryReporter(svc)
+	}
+	if config.MemoryBudget != 0 {
+		svc.memBudget = membudget.New(config.MemoryBudget)
+		store.RegisterMemory(svc.memBudget)
+		if pool, ok := svc.txpool.(interface{ MemoryUsage() uint64 }); ok {
+			svc.memBudget.Register("txpool", membudget.PriorityFixed, membudget.Func(pool.MemoryUsage))
+		}
+		svc.memBudget.Register("dag/buffers", membudget.PriorityFixed, membudget.Func(svc.handler.bufferedSize))
+	}
 
 	return svc, nil
 }
@@ -390,7 +486,7 @@ func (s *Service) Protocols() []p2p.Protocol {
 
 // APIs returns api methods the service wants to expose on rpc channels.
 func (s *Service) APIs() []rpc.API {
-	apis := ethapi.GetAPIs(s.EthAPI)
+	apis := ethapi.GetAPIs(s.EthAPI, s.stateSessions)
 
 	apis = append(apis, []rpc.API{
 		{
@@ -408,9 +504,70 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "dag",
+			Version:   "1.0",
+			Service:   eventtiming.NewPublicAPI(s.timings),
+			Public:    true,
+		}, {
+			Namespace: "dag",
+			Version:   "1.0",
+			Service:   blocktiming.NewPublicAPI(s.blockTimings),
+			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateValidatorAPI(s),
+			Public:    false,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateReadOnlyAPI(s),
+			Public:    false,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateQuarantineAPI(s),
+			Public:    false,
 		},
 	}...)
 
+	if len(s.config.FilterAPI.MempoolStreamToken) != 0 {
+		apis = append(apis, rpc.API{
+			Namespace: "mempool",
+			Version:   "1.0",
+			Service:   filters.NewMempoolAPI(s.EthAPI, s.config.FilterAPI.MempoolStreamToken),
+			Public:    false,
+		})
+	}
+
+	if s.telemetry != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   telemetry.NewPrivateAPI(s.telemetry),
+			Public:    false,
+		})
+	}
+
+	if chaos.Enabled {
+		apis = append(apis, rpc.API{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   chaos.NewPrivateAPI(),
+			Public:    false,
+		})
+	}
+
+	if s.txRebroadcaster != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPublicTxRebroadcastAPI(s),
+			Public:    true,
+		})
+	}
+
 	// eth-namespace is doubled as ftm-namespace for branding purpose
 	for _, api := range apis {
 		if api.Namespace == "eth" {
@@ -428,6 +585,9 @@ func (s *Service) APIs() []rpc.API {
 
 // Start method invoked when the node is ready to start the service.
 func (s *Service) Start() error {
+	if target := s.store.GetRollbackTarget(); target != nil {
+		return fmt.Errorf("rollback to epoch %d is scheduled, apply it with 'sonictool heal' before starting the node", *target)
+	}
 	s.gpo.Start(&GPOBackend{s.store, s.txpool})
 	// start tflusher before starting snapshots generation
 	s.tflusher.Start()
@@ -449,6 +609,32 @@ func (s *Service) Start() error {
 	}
 
 	s.verWatcher.Start()
+	s.stateSessions.Start()
+
+
+	if s.apiTablesScanner != nil {
+		s.apiTablesScanner.Start()
+	}
+
+	if s.receiptsPruner != nil {
+		s.receiptsPruner.Start()
+	}
+
+	if s.txRebroadcaster != nil {
+		s.txRebroadcaster.Start()
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Start()
+	}
+
+	if s.telemetry != nil {
+		s.telemetry.Start()
+	}
+
+	if s.memBudget != nil {
+		s.memBudget.Start(memBudgetPeriod)
+	}
 
 	if s.haltCheck != nil && s.haltCheck(s.store.GetEpoch(), s.store.GetEpoch(), s.store.GetBlockState().LastBlock.Time.Time()) {
 		// halt syncing
@@ -467,6 +653,26 @@ func (s *Service) WaitBlockEnd() {
 func (s *Service) Stop() error {
 	defer log.Info("Fantom service stopped")
 	s.verWatcher.Stop()
+	// release the pinned states before the state DB is closed
+	s.stateSessions.Stop()
+	if s.apiTablesScanner != nil {
+		s.apiTablesScanner.Stop()
+	}
+	if s.receiptsPruner != nil {
+		s.receiptsPruner.Stop()
+	}
+	if s.txRebroadcaster != nil {
+		s.txRebroadcaster.Stop()
+	}
+	if s.webhooks != nil {
+		s.webhooks.Stop()
+	}
+	if s.telemetry != nil {
+		s.telemetry.Stop()
+	}
+	if s.memBudget != nil {
+		s.memBudget.Stop()
+	}
 	for _, em := range s.emitters {
 		em.Stop()
 	}
@@ -493,7 +699,16 @@ func (s *Service) Stop() error {
 		return err
 	}
 
-	return s.store.Commit()
+	err = s.store.Commit()
+	s.stoppedCleanly = err == nil
+	return err
+}
+
+// StoppedCleanly returns true if the service is stopped and the final commit succeeded.
+func (s *Service) StoppedCleanly() bool {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	return s.stoppedCleanly
 }
 
 // AccountManager return node's account manager
@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/vecmt"
+)
+
+// NewStandaloneService creates a service without the P2P networking, e.g. for a simulated network of nodes.
+// The caller exchanges the events of the service by the emitters broadcasts and ConnectEvent.
+func NewStandaloneService(config Config, store *Store, blockProc BlockProc, engine lachesis.Consensus, dagIndexer *vecmt.Index, newTxPool func(evmcore.StateReader) TxPool) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return newService(config, store, blockProc, engine, dagIndexer, newTxPool)
+}
+
+// StartStandalone starts the blocks processing and the emitters of the standalone service.
+func (s *Service) StartStandalone() {
+	s.blockProcTasks.Start(1)
+	for _, em := range s.emitters {
+		em.Start()
+	}
+	s.verWatcher.Start()
+}
+
+// StopStandalone stops the standalone service and closes its store.
+func (s *Service) StopStandalone() error {
+	for _, em := range s.emitters {
+		em.Stop()
+	}
+	s.WaitBlockEnd()
+	s.verWatcher.Stop()
+	s.tflusher.Stop()
+	return s.store.Close()
+}
+
+// ConnectEvent validates and processes the received event the same way the protocol handler does.
+// It returns false if the event can't be connected yet, because some of its parents are missing.
+func (s *Service) ConnectEvent(e *inter.EventPayload) (bool, error) {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	if s.store.HasEvent(e.ID()) {
+		return true, nil
+	}
+	parents := make(inter.EventIs, len(e.Parents()))
+	for i, id := range e.Parents() {
+		p := s.store.GetEvent(id)
+		if p == nil {
+			return false, nil
+		}
+		parents[i] = p
+	}
+	if err := s.checkers.Validate(e, parents); err != nil {
+		return false, err
+	}
+	return true, s.processEvent(e)
+}
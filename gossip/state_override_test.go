@@ -0,0 +1,98 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/inter/state"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+const (
+	// storageReaderCode stores 42 into the slot 1 and deploys the code, which returns the value of the slot given by calldata
+	storageReaderCode = "0x602a600155600c6011600039600c6000f3" + "600035546000526020" + "6000f3"
+	// selfBalanceCode returns the balance of the called account
+	selfBalanceCode = "0x4760005260206000f3"
+)
+
+func TestStateOverrides(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 2, t)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(nextEpoch, env.Contract(1, nil, storageReaderCode))
+	require.NoError(err)
+	contract := receipts[0].ContractAddress
+	account := env.Address(2)
+
+	ctx := context.Background()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	apply := func(override ethapi.StateOverride, check func(statedb state.StateDB)) {
+		statedb, _, err := env.EthAPI.StateAndHeaderByNumberOrHash(ctx, latest)
+		require.NoError(err)
+		defer statedb.Release()
+		require.NoError(override.Apply(statedb))
+		check(statedb)
+		require.NoError(statedb.Error())
+	}
+
+	nonce := hexutil.Uint64(100)
+	apply(ethapi.StateOverride{account: {Nonce: &nonce}}, func(statedb state.StateDB) {
+		require.Equal(uint64(100), statedb.GetNonce(account))
+	})
+
+	code := hexutil.Bytes(hexutil.MustDecode(selfBalanceCode))
+	apply(ethapi.StateOverride{account: {Code: &code}}, func(statedb state.StateDB) {
+		require.Equal([]byte(code), statedb.GetCode(account))
+	})
+
+	for _, amount := range []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 100)} {
+		balance := (*hexutil.Big)(amount)
+		apply(ethapi.StateOverride{account: {Balance: &balance}}, func(statedb state.StateDB) {
+			require.Zero(amount.Cmp(statedb.GetBalance(account)))
+		})
+	}
+
+	slot1, slot2 := common.Hash{31: 1}, common.Hash{31: 2}
+	value := common.Hash{31: 7}
+	apply(ethapi.StateOverride{contract: {StateDiff: &map[common.Hash]common.Hash{slot2: value}}}, func(statedb state.StateDB) {
+		require.Equal(common.Hash{31: 42}, statedb.GetState(contract, slot1))
+		require.Equal(value, statedb.GetState(contract, slot2))
+	})
+	apply(ethapi.StateOverride{contract: {State: &map[common.Hash]common.Hash{slot2: value}}}, func(statedb state.StateDB) {
+		require.Equal(common.Hash{}, statedb.GetState(contract, slot1))
+		require.Equal(value, statedb.GetState(contract, slot2))
+		require.NotEmpty(statedb.GetCode(contract))
+	})
+
+	// the overrides are applied to the calls
+	api := ethapi.NewPublicBlockChainAPI(env.EthAPI)
+	data := hexutil.Bytes(slot1.Bytes())
+	res, err := api.Call(ctx, ethapi.TransactionArgs{To: &contract, Data: &data}, ethapi.BlockNumberOrHash(latest),
+		&ethapi.StateOverride{contract: {State: &map[common.Hash]common.Hash{slot1: value}}}, nil)
+	require.NoError(err)
+	require.Equal(value.Bytes(), []byte(res))
+
+	balance := (*hexutil.Big)(big.NewInt(12345))
+	override := &ethapi.StateOverride{account: {Code: &code, Balance: &balance}}
+	res, err = api.Call(ctx, ethapi.TransactionArgs{To: &account}, ethapi.BlockNumberOrHash(latest), override, nil)
+	require.NoError(err)
+	require.Equal(common.BigToHash(big.NewInt(12345)).Bytes(), []byte(res))
+
+	// and to the traced calls
+	debug := ethapi.NewPublicDebugAPI(env.EthAPI)
+	traced, err := debug.TraceCall(ctx, ethapi.TransactionArgs{To: &account}, ethapi.BlockNumberOrHash(latest), &ethapi.TraceCallConfig{StateOverrides: override})
+	require.NoError(err)
+	require.IsType(&ethapi.ExecutionResult{}, traced)
+	require.False(traced.(*ethapi.ExecutionResult).Failed)
+	require.Equal(common.Bytes2Hex(common.BigToHash(big.NewInt(12345)).Bytes()), traced.(*ethapi.ExecutionResult).ReturnValue)
+}
@@ -8,16 +8,22 @@ import (
 	"time"
 
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils/chaos"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/priowrite"
 	"github.com/Fantom-foundation/go-opera/utils/eventid"
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+	"github.com/Fantom-foundation/go-opera/utils/membudget"
 	"github.com/Fantom-foundation/go-opera/utils/randat"
 	"github.com/Fantom-foundation/go-opera/utils/rlpstore"
 	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/flushable"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
-	"github.com/Fantom-foundation/lachesis-base/utils/wlru"
 )
 
 // Store is a node persistent storage working over physical key-value database.
@@ -26,6 +32,7 @@ type Store struct {
 	cfg StoreConfig
 
 	mainDB          kvdb.Store
+	flushGate       *priowrite.Gate
 	evm             *evmstore.Store
 	table           struct {
 		Version kvdb.Store `table:"_"`
@@ -45,8 +52,12 @@ type Store struct {
 		// Network version
 		NetworkVersion kvdb.Store `table:"V"`
 
+		// Disaster recovery
+		RollbackTarget kvdb.Store `table:"K"`
+
 		// API-only
 		BlockHashes kvdb.Store `table:"B"`
+		FeeStats    kvdb.Store `table:"f"`
 
 		LlrState           kvdb.Store `table:"S"`
 		LlrBlockResults    kvdb.Store `table:"R"`
@@ -60,28 +71,28 @@ type Store struct {
 	}
 
 	prevFlushTime time.Time
+	flushLatency  int64 // duration of the last flush, in nanoseconds
 
 	epochStore atomic.Value
 
 	cache struct {
-		Events                 *wlru.Cache `cache:"-"` // store by pointer
+		Events                 *lru.Cache[hash.Event, *inter.EventPayload] `cache:"-"`
 		EventIDs               *eventid.Cache
-		EventsHeaders          *wlru.Cache  `cache:"-"` // store by pointer
-		Blocks                 *wlru.Cache  `cache:"-"` // store by pointer
-		BlockHashes            *wlru.Cache  `cache:"-"` // store by value
-		BRHashes               *wlru.Cache  `cache:"-"` // store by value
-		EvmBlocks              *wlru.Cache  `cache:"-"` // store by pointer
-		BlockEpochStateHistory *wlru.Cache  `cache:"-"` // store by pointer
-		BlockEpochState        atomic.Value // store by value
-		HighestLamport         atomic.Value // store by value
-		LastBVs                atomic.Value // store by pointer
-		LastEV                 atomic.Value // store by pointer
-		LlrState               atomic.Value // store by value
-		KvdbEvmSnap            atomic.Value // store by pointer
-		UpgradeHeights         atomic.Value // store by pointer
-		Genesis                atomic.Value // store by value
-		LlrBlockVotesIndex     *VotesCache  // store by pointer
-		LlrEpochVoteIndex      *VotesCache  // store by pointer
+		EventsHeaders          *lru.Cache[hash.Event, *inter.Event]    `cache:"-"`
+		Blocks                 *lru.Cache[idx.Block, *inter.Block]     `cache:"-"`
+		BlockHashes            *lru.Cache[hash.Event, idx.Block]       `cache:"-"`
+		BRHashes               *lru.Cache[idx.Block, hash.Hash]        `cache:"-"`
+		BlockEpochStateHistory *lru.Cache[idx.Epoch, *BlockEpochState] `cache:"-"`
+		BlockEpochState        atomic.Value                            // store by value
+		HighestLamport         atomic.Value                            // store by value
+		LastBVs                atomic.Value                            // store by pointer
+		LastEV                 atomic.Value                            // store by pointer
+		LlrState               atomic.Value                            // store by value
+		KvdbEvmSnap            atomic.Value                            // store by pointer
+		UpgradeHeights         atomic.Value                            // store by pointer
+		Genesis                atomic.Value                            // store by value
+		LlrBlockVotesIndex     *VotesCache                             // store by pointer
+		LlrEpochVoteIndex      *VotesCache                             // store by pointer
 	}
 
 	mutex struct {
@@ -118,10 +129,17 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) (*Store, error) {
 		rlp:           rlpstore.Helper{logger.New("rlp")},
 	}
 
+	// the EVM store compactions give way to the DB flushes
+	bulkDB := s.mainDB
+	if cfg.BulkCompactionMaxWait > 0 {
+		s.flushGate = priowrite.NewGate(cfg.BulkCompactionMaxWait)
+		bulkDB = priowrite.Bulk(s.mainDB, s.flushGate)
+	}
+
 	table.MigrateTables(&s.table, s.mainDB)
 
 	s.initCache()
-	s.evm = evmstore.NewStore(s.mainDB, cfg.EVM)
+	s.evm = evmstore.NewStore(bulkDB, cfg.EVM)
 
 	if err := s.migrateData(); err != nil {
 		return nil, fmt.Errorf("failed to migrate gossip db: %w", err)
@@ -131,23 +149,32 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) (*Store, error) {
 }
 
 func (s *Store) initCache() {
-	s.cache.Events = s.makeCache(s.cfg.Cache.EventsSize, s.cfg.Cache.EventsNum)
-	s.cache.Blocks = s.makeCache(s.cfg.Cache.BlocksSize, s.cfg.Cache.BlocksNum)
+	s.cache.Events = lru.New(s.cfg.Cache.EventsSize, s.cfg.Cache.EventsNum, func(_ hash.Event, e *inter.EventPayload) uint {
+		return uint(e.Size())
+	})
+	s.cache.Blocks = lru.New(s.cfg.Cache.BlocksSize, s.cfg.Cache.BlocksNum, func(_ idx.Block, b *inter.Block) uint {
+		return uint(b.EstimateSize())
+	})
 
 	blockHashesNum := s.cfg.Cache.BlocksNum
-	blockHashesCacheSize := nominalSize * uint(blockHashesNum)
-	s.cache.BlockHashes = s.makeCache(blockHashesCacheSize, blockHashesNum)
-	s.cache.BRHashes = s.makeCache(blockHashesCacheSize, blockHashesNum)
+	s.cache.BlockHashes = lru.New(blockHashMemSize*uint(blockHashesNum), blockHashesNum, func(hash.Event, idx.Block) uint {
+		return blockHashMemSize
+	})
+	s.cache.BRHashes = lru.New(blockHashMemSize*uint(blockHashesNum), blockHashesNum, func(idx.Block, hash.Hash) uint {
+		return blockHashMemSize
+	})
 
 	eventsHeadersNum := s.cfg.Cache.EventsNum
-	eventsHeadersCacheSize := nominalSize * uint(eventsHeadersNum)
-	s.cache.EventsHeaders = s.makeCache(eventsHeadersCacheSize, eventsHeadersNum)
+	s.cache.EventsHeaders = lru.New(eventHeaderMemSize*uint(eventsHeadersNum), eventsHeadersNum, func(hash.Event, *inter.Event) uint {
+		return eventHeaderMemSize
+	})
 
 	s.cache.EventIDs = eventid.NewCache(s.cfg.Cache.EventsIDsNum)
 
 	blockEpochStatesNum := s.cfg.Cache.BlockEpochStateNum
-	blockEpochStatesSize := nominalSize * uint(blockEpochStatesNum)
-	s.cache.BlockEpochStateHistory = s.makeCache(blockEpochStatesSize, blockEpochStatesNum)
+	s.cache.BlockEpochStateHistory = lru.New(blockEpochStateMemSize*uint(blockEpochStatesNum), blockEpochStatesNum, func(idx.Epoch, *BlockEpochState) uint {
+		return blockEpochStateMemSize
+	})
 
 	s.cache.LlrBlockVotesIndex = NewVotesCache(s.cfg.Cache.LlrBlockVotesIndexes, s.flushLlrBlockVoteWeight)
 	s.cache.LlrEpochVoteIndex = NewVotesCache(s.cfg.Cache.LlrEpochVotesIndexes, s.flushLlrEpochVoteWeight)
@@ -155,6 +182,11 @@ func (s *Store) initCache() {
 
 // Close closes underlying database.
 func (s *Store) Close() error {
+	// index the queued logs and record the queued state accesses while the DB is open
+	if err := s.evm.FlushLogsIndex(); err != nil {
+		s.Log.Error("Logs index is incomplete", "err", err)
+	}
+	s.evm.FlushStateExpiry()
 	// set all tables/caches fields to nil
 	table.MigrateTables(&s.table, nil)
 	table.MigrateCaches(&s.cache, func() interface{} {
@@ -200,28 +232,46 @@ func (s *Store) Commit() error {
 		es.FlushHeads()
 		es.FlushLastEvents()
 	}
+	// the logs index and the state expiry records must not lag behind the flushed blocks
+	if err := s.evm.FlushLogsIndex(); err != nil {
+		s.Log.Error("Logs index is incomplete", "err", err)
+	}
+	s.evm.FlushStateExpiry()
 	return s.flushDBs()
 }
 
 func (s *Store) flushDBs() error {
 	s.prevFlushTime = time.Now()
+	chaos.DelayFlush()
 	flushID := bigendian.Uint64ToBytes(uint64(s.prevFlushTime.UnixNano()))
-	return s.dbs.Flush(flushID)
+	var err error
+	if s.flushGate != nil {
+		err = s.flushGate.Critical(func() error {
+			return s.dbs.Flush(flushID)
+		})
+	} else {
+		err = s.dbs.Flush(flushID)
+	}
+	atomic.StoreInt64(&s.flushLatency, int64(time.Since(s.prevFlushTime)))
+	return err
+}
+
+// FlushLatency returns the duration of the last flush of the DBs.
+func (s *Store) FlushLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.flushLatency))
 }
 
 func (s *Store) EvmStore() *evmstore.Store {
 	return s.evm
 }
 
-/*
- * Utils:
- */
-
-func (s *Store) makeCache(weight uint, size int) *wlru.Cache {
-	cache, err := wlru.New(weight, size)
-	if err != nil {
-		s.Log.Crit("Failed to create LRU cache", "err", err)
-		return nil
-	}
-	return cache
+// RegisterMemory registers the caches of the store and of the EVM store in the memory accountant.
+func (s *Store) RegisterMemory(a *membudget.Accountant) {
+	a.Register("gossip/events", membudget.PriorityNormal, membudget.Cache(s.cache.Events))
+	a.Register("gossip/eventsheaders", membudget.PriorityHigh, membudget.Cache(s.cache.EventsHeaders))
+	a.Register("gossip/blocks", membudget.PriorityLow, membudget.Cache(s.cache.Blocks))
+	a.Register("gossip/blockhashes", membudget.PriorityLow, membudget.Cache(s.cache.BlockHashes))
+	a.Register("gossip/brhashes", membudget.PriorityNormal, membudget.Cache(s.cache.BRHashes))
+	a.Register("gossip/blockepochstates", membudget.PriorityNormal, membudget.Cache(s.cache.BlockEpochStateHistory))
+	s.evm.RegisterMemory(a)
 }
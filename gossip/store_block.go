@@ -51,7 +51,7 @@ func (s *Store) SetBlock(n idx.Block, b *inter.Block) {
 	s.rlp.Set(s.table.Blocks, n.Bytes(), b)
 
 	// Add to LRU cache.
-	s.cache.Blocks.Add(n, b, uint(b.EstimateSize()))
+	s.cache.Blocks.Add(n, b)
 }
 
 // GetBlock returns stored block.
@@ -65,14 +65,14 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 	}
 	// Get block from LRU cache first.
 	if c, ok := s.cache.Blocks.Get(n); ok {
-		return c.(*inter.Block)
+		return c
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
 
 	// Add to LRU cache.
 	if block != nil {
-		s.cache.Blocks.Add(n, block, uint(block.EstimateSize()))
+		s.cache.Blocks.Add(n, block)
 	}
 
 	return block
@@ -102,17 +102,13 @@ func (s *Store) SetBlockIndex(id hash.Event, n idx.Block) {
 		s.Log.Crit("Failed to put key-value", "err", err)
 	}
 
-	s.cache.BlockHashes.Add(id, n, nominalSize)
+	s.cache.BlockHashes.Add(id, n)
 }
 
 // GetBlockIndex returns stored block index.
 func (s *Store) GetBlockIndex(id hash.Event) *idx.Block {
-	nVal, ok := s.cache.BlockHashes.Get(id)
-	if ok {
-		n, ok := nVal.(idx.Block)
-		if ok {
-			return &n
-		}
+	if n, ok := s.cache.BlockHashes.Get(id); ok {
+		return &n
 	}
 
 	buf, err := s.table.BlockHashes.Get(id.Bytes())
@@ -128,7 +124,7 @@ func (s *Store) GetBlockIndex(id hash.Event) *idx.Block {
 	}
 	n := idx.BytesToBlock(buf)
 
-	s.cache.BlockHashes.Add(id, n, nominalSize)
+	s.cache.BlockHashes.Add(id, n)
 
 	return &n
 }
@@ -175,7 +171,7 @@ func (s *Store) SetEpochBlock(b idx.Block, e idx.Epoch) {
 
 func (s *Store) FindBlockEpoch(b idx.Block) idx.Epoch {
 	if c, ok := s.cache.Blocks.Get(b); ok {
-		return c.(*inter.Block).Atropos.Epoch()
+		return c.Atropos.Epoch()
 	}
 
 	it := s.table.EpochBlocks.NewIterator(nil, (math.MaxUint64 - b).Bytes())
@@ -193,7 +189,10 @@ func (s *Store) GetBlockTxs(n idx.Block, block *inter.Block) types.Transactions
 
 	transactions := make(types.Transactions, 0, len(block.Txs)+len(block.InternalTxs)+len(block.Events)*10)
 	for _, txid := range block.InternalTxs {
-		tx := s.evm.GetTx(txid)
+		tx, err := s.evm.GetTx(txid)
+		if err != nil {
+			log.Crit("Failed to get internal tx", "tx", txid.String(), "err", err)
+		}
 		if tx == nil {
 			log.Crit("Internal tx not found", "tx", txid.String())
 			continue
@@ -201,7 +200,10 @@ func (s *Store) GetBlockTxs(n idx.Block, block *inter.Block) types.Transactions
 		transactions = append(transactions, tx)
 	}
 	for _, txid := range block.Txs {
-		tx := s.evm.GetTx(txid)
+		tx, err := s.evm.GetTx(txid)
+		if err != nil {
+			log.Crit("Failed to get tx", "tx", txid.String(), "err", err)
+		}
 		if tx == nil {
 			log.Crit("Tx not found", "tx", txid.String())
 			continue
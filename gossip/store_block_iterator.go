@@ -0,0 +1,148 @@
+package gossip
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+)
+
+// blockIteratorReadahead is the number of blocks read ahead of the iterator consumer.
+const blockIteratorReadahead = 32
+
+// IteratedBlock is a canonical block with its transactions and receipts.
+type IteratedBlock struct {
+	*evmcore.EvmBlock
+	Receipts types.Receipts
+}
+
+// BlockIterator walks the canonical blocks in ascending order.
+// The blocks are read from the DB by a range scan in a background goroutine, ahead of the consumer.
+// An iterator must be used by a single goroutine, but any number of iterators may run concurrently
+// with each other and with the blocks processing. The iterator must be released before the store is closed.
+type BlockIterator struct {
+	blocks chan *IteratedBlock
+	quit   chan struct{}
+	once   sync.Once
+
+	cur *IteratedBlock
+	err error
+}
+
+// IterateBlocks returns an iterator over the stored blocks in the range [from, to].
+// The receipts bypass the receipts cache, so walking the chain doesn't evict the entries used by the API.
+func (s *Store) IterateBlocks(from, to idx.Block) *BlockIterator {
+	it := &BlockIterator{
+		blocks: make(chan *IteratedBlock, blockIteratorReadahead),
+		quit:   make(chan struct{}),
+	}
+	go func() {
+		defer close(it.blocks)
+		it.err = s.readBlocks(from, to, func(b *IteratedBlock) bool {
+			select {
+			case it.blocks <- b:
+				return true
+			case <-it.quit:
+				return false
+			}
+		})
+	}()
+	return it
+}
+
+// Next moves the iterator to the next block. Returns false when the iteration is over or failed.
+func (it *BlockIterator) Next() bool {
+	it.cur = <-it.blocks
+	return it.cur != nil
+}
+
+// Block returns the current block.
+func (it *BlockIterator) Block() *IteratedBlock {
+	return it.cur
+}
+
+// Error returns the error which stopped the iteration, if any.
+// It's valid only after Next returned false.
+func (it *BlockIterator) Error() error {
+	return it.err
+}
+
+// Release stops the iteration and waits until the DB iterator is released.
+func (it *BlockIterator) Release() {
+	it.once.Do(func() {
+		close(it.quit)
+	})
+	for range it.blocks {
+	}
+	it.cur = nil
+}
+
+func (s *Store) readBlocks(from, to idx.Block, fn func(*IteratedBlock) bool) error {
+	if from > to {
+		return nil
+	}
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(s.GetRules().ChainID()))
+
+	var (
+		prev      hash.Event
+		prevIndex idx.Block
+		epoch     idx.Epoch
+		rules     opera.Rules
+	)
+	dbIt := s.table.Blocks.NewIterator(nil, from.Bytes())
+	defer dbIt.Release()
+	for dbIt.Next() {
+		n := idx.BytesToBlock(dbIt.Key())
+		if n > to {
+			break
+		}
+		block := &inter.Block{}
+		if err := rlp.DecodeBytes(dbIt.Value(), block); err != nil {
+			return fmt.Errorf("failed to decode block %d: %w", n, err)
+		}
+
+		// the previous block is known unless the first one or a gap
+		if n != prevIndex+1 || prev == (hash.Event{}) {
+			prev = hash.Event{}
+			if n != 0 {
+				if b := s.GetBlock(n - 1); b != nil {
+					prev = b.Atropos
+				}
+			}
+		}
+		if e := block.Atropos.Epoch(); e != epoch {
+			epoch = e
+			rules = opera.Rules{}
+			if es := s.GetHistoryEpochState(epoch); es != nil {
+				rules = es.Rules
+			}
+		}
+
+		txs := s.GetBlockTxs(n, block)
+		evmBlock := evmcore.NewEvmBlock(evmcore.ToEvmHeader(block, n, prev, rules), txs)
+
+		var receipts types.Receipts
+		if receiptsStorage, _ := s.evm.GetRawReceipts(n); receiptsStorage != nil {
+			var err error
+			receipts, err = evmstore.UnwrapStorageReceipts(receiptsStorage, n, signer, evmBlock.Hash, txs)
+			if err != nil {
+				return fmt.Errorf("failed to derive receipts of block %d: %w", n, err)
+			}
+		}
+
+		if !fn(&IteratedBlock{EvmBlock: evmBlock, Receipts: receipts}) {
+			return nil
+		}
+		prev, prevIndex = block.Atropos, n
+	}
+	return dbIt.Error()
+}
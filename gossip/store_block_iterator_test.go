@@ -0,0 +1,71 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils"
+)
+
+func TestStoreIterateBlocks(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 3, t)
+	defer env.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+		require.NoError(err)
+	}
+
+	reader := env.GetEvmStateReader()
+	latest := env.store.GetLatestBlockIndex()
+	from := idx.Block(1)
+
+	// the genesis blocks may be missing, the iteration starts from the first stored one
+	expect := from
+	for env.store.GetBlock(expect) == nil {
+		expect++
+	}
+
+	it := env.store.IterateBlocks(from, latest)
+	defer it.Release()
+	for it.Next() {
+		b := it.Block()
+		require.Equal(uint64(expect), b.NumberU64())
+
+		block := reader.GetBlock(b.Hash, uint64(expect))
+		require.NotNil(block)
+		require.Equal(block.ParentHash, b.ParentHash)
+		require.Equal(block.Root, b.Root)
+		require.Equal(block.TxHash, b.TxHash)
+		require.Equal(block.Transactions.Len(), b.Transactions.Len())
+
+		receipts, err := env.store.evm.GetReceipts(expect, env.EthAPI.signer, b.Hash, b.Transactions)
+		require.NoError(err)
+		require.Equal(len(receipts), len(b.Receipts))
+		for i, r := range receipts {
+			require.Equal(r.TxHash, b.Receipts[i].TxHash)
+			require.Equal(r.GasUsed, b.Receipts[i].GasUsed)
+		}
+		expect++
+	}
+	require.NoError(it.Error())
+	require.Equal(latest+1, expect)
+
+	// the iteration can be stopped early
+	it = env.store.IterateBlocks(from, latest)
+	require.True(it.Next())
+	it.Release()
+	require.False(it.Next())
+
+	// empty range
+	it = env.store.IterateBlocks(latest+1, latest)
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+}
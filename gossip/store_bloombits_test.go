@@ -0,0 +1,38 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/flushable"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreBlockBloomKeepsBlocks(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	cfg := MemTestStoreConfig(t.TempDir())
+	cfg.EVM.BloomBitsIndexing = true
+	store, err := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	require.NoError(err)
+	defer store.Close()
+
+	n := idx.Block(5)
+	block := &inter.Block{Atropos: hash.Event{1}, GasUsed: 21000}
+	store.SetBlock(n, block)
+	store.evm.SetBlockBloom(n, types.Receipts{{Logs: []*types.Log{{Address: common.Address{1}}}}})
+
+	// the block and the bloom share the main DB, so the block is read bypassing the cache
+	stored, _ := store.rlp.Get(store.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	require.NotNil(stored)
+	require.Equal(block.Atropos, stored.Atropos)
+	require.Equal(block.GasUsed, stored.GasUsed)
+}
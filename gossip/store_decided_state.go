@@ -28,13 +28,12 @@ func (s *Store) SetHistoryBlockEpochState(epoch idx.Epoch, bs iblockproc.BlockSt
 	// Write to the DB
 	s.rlp.Set(s.table.BlockEpochStateHistory, epoch.Bytes(), bes)
 	// Save to the LRU cache
-	s.cache.BlockEpochStateHistory.Add(epoch, bes, nominalSize)
+	s.cache.BlockEpochStateHistory.Add(epoch, bes)
 }
 
 func (s *Store) GetHistoryBlockEpochState(epoch idx.Epoch) (*iblockproc.BlockState, *iblockproc.EpochState) {
 	// Get HistoryBlockEpochState from LRU cache first.
-	if v, ok := s.cache.BlockEpochStateHistory.Get(epoch); ok {
-		bes := v.(*BlockEpochState)
+	if bes, ok := s.cache.BlockEpochStateHistory.Get(epoch); ok {
 		if bes.EpochState.Epoch == epoch {
 			bs := bes.BlockState.Copy()
 			es := bes.EpochState.Copy()
@@ -47,7 +46,7 @@ func (s *Store) GetHistoryBlockEpochState(epoch idx.Epoch) (*iblockproc.BlockSta
 		return nil, nil
 	}
 	// Save to the LRU cache
-	s.cache.BlockEpochStateHistory.Add(epoch, v, nominalSize)
+	s.cache.BlockEpochStateHistory.Add(epoch, v)
 	bs := v.BlockState.Copy()
 	es := v.EpochState.Copy()
 	return &bs, &es
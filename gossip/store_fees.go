@@ -0,0 +1,61 @@
+package gossip
+
+/*
+	Fees accounting:
+	  'b' + block -> fee stats of the block
+	  'e' + epoch -> fee stats of the epoch
+*/
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+const (
+	blockFeesPrefix = 'b'
+	epochFeesPrefix = 'e'
+)
+
+// SetBlockFees stores the fee stats of the block and adds them to the stats of the epoch.
+// Stats of a re-processed block (e.g. after a rollback) replace the previous ones.
+func (s *Store) SetBlockFees(epoch idx.Epoch, n idx.Block, stats iblockproc.FeeStats) {
+	total := s.GetEpochFees(epoch)
+	if total == nil {
+		zero := iblockproc.NewFeeStats()
+		total = &zero
+	}
+	if prev := s.GetBlockFees(n); prev != nil {
+		total.Sub(*prev)
+	}
+	total.Add(stats)
+	s.rlp.Set(s.table.FeeStats, append([]byte{blockFeesPrefix}, n.Bytes()...), &stats)
+	s.rlp.Set(s.table.FeeStats, append([]byte{epochFeesPrefix}, epoch.Bytes()...), total)
+}
+
+// GetBlockFees returns the fee stats of the block, or nil if the block isn't accounted.
+func (s *Store) GetBlockFees(n idx.Block) *iblockproc.FeeStats {
+	stats, _ := s.rlp.Get(s.table.FeeStats, append([]byte{blockFeesPrefix}, n.Bytes()...), &iblockproc.FeeStats{}).(*iblockproc.FeeStats)
+	return stats
+}
+
+// GetEpochFees returns the fee stats of the epoch, or nil if the epoch isn't accounted.
+func (s *Store) GetEpochFees(epoch idx.Epoch) *iblockproc.FeeStats {
+	stats, _ := s.rlp.Get(s.table.FeeStats, append([]byte{epochFeesPrefix}, epoch.Bytes()...), &iblockproc.FeeStats{}).(*iblockproc.FeeStats)
+	return stats
+}
+
+// txFees calculates the fee paid by the transaction, and its part paid at the base fee rate.
+func txFees(tx *types.Transaction, r *types.Receipt, baseFee *big.Int) (fee, base *big.Int) {
+	gasUsed := new(big.Int).SetUint64(r.GasUsed)
+	price := tx.GasPrice()
+	if baseFee == nil {
+		return new(big.Int).Mul(gasUsed, price), new(big.Int)
+	}
+	price = math.BigMin(tx.GasFeeCap(), new(big.Int).Add(baseFee, tx.GasTipCap()))
+	return new(big.Int).Mul(gasUsed, price), new(big.Int).Mul(gasUsed, math.BigMin(price, baseFee))
+}
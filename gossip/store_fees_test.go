@@ -0,0 +1,86 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+func feeStats(fees, baseFees, originated int64) iblockproc.FeeStats {
+	return iblockproc.FeeStats{
+		Fees:       big.NewInt(fees),
+		BaseFees:   big.NewInt(baseFees),
+		Originated: big.NewInt(originated),
+	}
+}
+
+func requireFeeStats(t *testing.T, exp iblockproc.FeeStats, got *iblockproc.FeeStats) {
+	require.NotNil(t, got)
+	require.Equal(t, exp.Fees.String(), got.Fees.String())
+	require.Equal(t, exp.BaseFees.String(), got.BaseFees.String())
+	require.Equal(t, exp.Originated.String(), got.Originated.String())
+}
+
+func TestStoreFees(t *testing.T) {
+	require := require.New(t)
+
+	store, err := NewMemStore(t)
+	require.NoError(err)
+	defer store.Close()
+
+	require.Nil(store.GetBlockFees(1))
+	require.Nil(store.GetEpochFees(1))
+
+	store.SetBlockFees(1, 1, feeStats(10, 5, 10))
+	store.SetBlockFees(1, 2, feeStats(20, 10, 0))
+	store.SetBlockFees(2, 3, feeStats(1, 1, 1))
+
+	requireFeeStats(t, feeStats(20, 10, 0), store.GetBlockFees(2))
+	requireFeeStats(t, feeStats(30, 15, 10), store.GetEpochFees(1))
+	requireFeeStats(t, feeStats(1, 1, 1), store.GetEpochFees(2))
+
+	// re-processed block replaces the previous stats
+	store.SetBlockFees(1, 2, feeStats(4, 2, 4))
+	requireFeeStats(t, feeStats(14, 7, 14), store.GetEpochFees(1))
+}
+
+func TestEpochFeesSplit(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv(2, 3, t)
+	defer env.Close()
+
+	epoch := env.store.GetEpoch()
+	_, err := env.ApplyTxs(sameEpoch, env.Transfer(1, 2, big.NewInt(1)))
+	require.NoError(err)
+	// seal the epoch
+	_, err = env.ApplyTxs(nextEpoch, env.Transfer(1, 2, big.NewInt(1)))
+	require.NoError(err)
+	require.Greater(env.store.GetEpoch(), epoch)
+
+	api := ethapi.NewPublicBlockChainAPI(env.EthAPI)
+	res, err := api.GetEpochFees(context.Background(), rpc.BlockNumber(epoch))
+	require.NoError(err)
+	require.NotNil(res)
+	require.NotZero(res.Fees.ToInt().Sign())
+	require.NotNil(res.Rewards)
+	require.NotNil(res.Treasury)
+	require.NotNil(res.Burnt)
+	require.True(res.Rewards.ToInt().Cmp(res.Originated.ToInt()) <= 0)
+	total := new(big.Int).Add(res.Rewards.ToInt(), res.Treasury.ToInt())
+	total.Add(total, res.Burnt.ToInt())
+	require.Equal(res.Fees.ToInt().String(), total.String())
+
+	// the fees of the current epoch aren't split yet
+	res, err = api.GetEpochFees(context.Background(), rpc.LatestBlockNumber)
+	require.NoError(err)
+	if res != nil {
+		require.Nil(res.Rewards)
+	}
+}
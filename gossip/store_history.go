@@ -0,0 +1,49 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HistoryStart returns the first block, the txs, receipts and logs of which are imported.
+func (s *Store) HistoryStart() idx.Block {
+	return s.cfg.History.StartBlock
+}
+
+// hasHistory reports whether the txs, receipts and logs of the block are imported.
+func (s *Store) hasHistory(n idx.Block) bool {
+	return n >= s.cfg.History.StartBlock
+}
+
+// CheckHistoryAnchor verifies the block against the history anchor, if it's the first block of the history.
+func (s *Store) CheckHistoryAnchor(n idx.Block, atropos hash.Event) error {
+	h := s.cfg.History
+	if h.StartBlock == 0 || n != h.StartBlock {
+		return nil
+	}
+	if common.Hash(atropos) != h.Anchor {
+		return fmt.Errorf("block %d hash %s mismatches the history anchor %s", n, atropos.String(), h.Anchor.String())
+	}
+	return nil
+}
+
+// verifyHistory checks the history config and verifies the first block of the history
+// against the anchor, if the block is already known.
+func (s *Store) verifyHistory() error {
+	start := s.cfg.History.StartBlock
+	if start == 0 {
+		return nil
+	}
+	if s.cfg.History.Anchor == (common.Hash{}) {
+		return errors.New("history anchor isn't set for the partial history")
+	}
+	block := s.GetBlock(start)
+	if block == nil {
+		return nil
+	}
+	return s.CheckHistoryAnchor(start, block.Atropos)
+}
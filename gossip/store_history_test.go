@@ -0,0 +1,39 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreHistoryAnchor(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store, err := NewMemStore(t)
+	require.NoError(err)
+	defer store.Close()
+
+	anchor := hash.Event{1, 2, 3}
+	store.cfg.History = HistoryConfig{StartBlock: 10}
+	require.Error(store.verifyHistory(), "anchor is required")
+
+	store.cfg.History.Anchor = common.Hash(anchor)
+	require.NoError(store.verifyHistory(), "start block isn't known yet")
+	require.False(store.hasHistory(9))
+	require.True(store.hasHistory(10))
+
+	require.NoError(store.CheckHistoryAnchor(9, hash.Event{4}))
+	require.NoError(store.CheckHistoryAnchor(10, anchor))
+	require.Error(store.CheckHistoryAnchor(10, hash.Event{4}))
+
+	store.SetBlock(10, &inter.Block{Atropos: hash.Event{4}})
+	require.Error(store.verifyHistory())
+	store.SetBlock(10, &inter.Block{Atropos: anchor})
+	require.NoError(store.verifyHistory())
+}
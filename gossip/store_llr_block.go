@@ -139,8 +139,7 @@ func (s *Store) GetFullBlockRecord(n idx.Block) *ibr.LlrFullBlockRecord {
 func (s *Store) GetBlockRecordHash(n idx.Block) *hash.Hash {
 	// Get data from LRU cache first.
 	if s.cache.BRHashes != nil {
-		if c, ok := s.cache.BRHashes.Get(n); ok {
-			h := c.(hash.Hash)
+		if h, ok := s.cache.BRHashes.Get(n); ok {
 			return &h
 		}
 	}
@@ -150,7 +149,7 @@ func (s *Store) GetBlockRecordHash(n idx.Block) *hash.Hash {
 	}
 	brHash := br.Hash()
 	// Add to LRU cache.
-	s.cache.BRHashes.Add(n, brHash, nominalSize)
+	s.cache.BRHashes.Add(n, brHash)
 	return &brHash
 }
 
@@ -192,7 +191,10 @@ func (s *Store) IterateFullBlockRecordsRLP(start idx.Block, f func(b idx.Block,
 		}
 		n := idx.BytesToBlock(it.Key())
 		txs := s.GetBlockTxs(n, block)
-		receiptsRLP := s.EvmStore().GetRawReceiptsRLP(n)
+		receiptsRLP, err := s.EvmStore().GetRawReceiptsRLP(n)
+		if err != nil {
+			s.Log.Crit("Failed to get receipts", "err", err)
+		}
 		if receiptsRLP == nil {
 			receiptsRLP = emptyReceiptsRLP
 		}
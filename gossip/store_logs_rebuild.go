@@ -0,0 +1,27 @@
+package gossip
+
+import (
+	"context"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RebuildLogsIndex drops the EVM logs index and indexes the logs of the stored receipts of all the blocks again.
+// An interrupted rebuild is resumed by the next call.
+func (s *Store) RebuildLogsIndex(ctx context.Context) error {
+	return s.evm.RebuildLogsIndex(ctx, s.GetLatestBlockIndex(), func(n idx.Block) (common.Hash, types.Transactions, bool) {
+		block := s.GetBlock(n)
+		if block == nil {
+			return common.Hash{}, nil, false
+		}
+		return common.Hash(block.Atropos), s.GetBlockTxs(n, block), true
+	})
+}
+
+// MigrateLogsIndex converts the EVM logs index of the older releases into the current layout.
+// An interrupted migration is resumed by the next call.
+func (s *Store) MigrateLogsIndex(ctx context.Context) error {
+	return s.evm.MigrateLogsIndex(ctx)
+}
@@ -1,12 +1,15 @@
 package gossip
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/utils/migration"
 )
@@ -41,7 +44,19 @@ func (s *Store) migrations() *migration.Migration {
 		Next("erase gossip-async db", unsupportedMigration).
 		Next("erase SFC API table", unsupportedMigration).
 		Next("erase legacy genesis DB", unsupportedMigration).
-		Next("calculate upgrade heights", unsupportedMigration)
+		Next("calculate upgrade heights", unsupportedMigration).
+		Next("migrate logs index into bitmaps", s.migrateLogsIndex)
+}
+
+// migrateLogsIndex converts the EVM logs index of the older releases on the start,
+// so the logs queries aren't refused until it's migrated offline.
+func (s *Store) migrateLogsIndex() error {
+	err := s.evm.MigrateLogsIndex(context.Background())
+	if errors.Is(err, evmstore.ErrNoLocalLogsIndex) {
+		// the legacy index isn't in use, it's migrated by `sonictool migrate-logs` if the local index is enabled later
+		return nil
+	}
+	return err
 }
 
 func unsupportedMigration() error {
@@ -0,0 +1,43 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/flushable"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+	"github.com/Fantom-foundation/go-opera/utils/migration"
+)
+
+func TestStoreMigrateLogsIndexOnStart(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0})
+	cfg := MemTestStoreConfig(t.TempDir())
+	store, err := NewStore(dbs, cfg)
+	require.NoError(err)
+
+	// the DB of an older release with a key of the logs index, which isn't migrated
+	ids := store.migrations().IDs()
+	migration.NewKvdbIDStore(store.table.Version).SetID(ids[len(ids)-2])
+	require.NoError(store.mainDB.Put(append([]byte("t"), make([]byte, common.HashLength+1+8+common.HashLength+8)...), []byte{0}))
+	require.NoError(store.flushDBs())
+	require.NoError(store.Close())
+
+	// the index is migrated on the start, so the queries are served
+	store, err = NewStore(dbs, cfg)
+	require.NoError(err)
+	defer store.Close()
+	legacy, err := topicsdb.HasLegacy(store.mainDB)
+	require.NoError(err)
+	require.False(legacy)
+	_, err = store.EvmStore().EvmLogs.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{{}}})
+	require.NoError(err)
+	require.Equal(ids[len(ids)-1], migration.NewKvdbIDStore(store.table.Version).GetID())
+}
@@ -0,0 +1,32 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// SetRollbackTarget schedules a rollback of the processed head to the beginning of the epoch.
+func (s *Store) SetRollbackTarget(epoch idx.Epoch) {
+	if err := s.table.RollbackTarget.Put([]byte{}, epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetRollbackTarget returns the epoch of a scheduled rollback, or nil if no rollback is scheduled.
+func (s *Store) GetRollbackTarget() *idx.Epoch {
+	b, err := s.table.RollbackTarget.Get([]byte{})
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return nil
+	}
+	epoch := idx.BytesToEpoch(b)
+	return &epoch
+}
+
+// DelRollbackTarget removes the scheduled rollback.
+func (s *Store) DelRollbackTarget() {
+	if err := s.table.RollbackTarget.Delete([]byte{}); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
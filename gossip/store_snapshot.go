@@ -0,0 +1,44 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+
+	"github.com/Fantom-foundation/go-opera/utils/adapters/snap2kvdb"
+)
+
+// StoreSnapshot is a read-only view of the store over a consistent snapshot of the database.
+// The blocks, txs, receipts and txs positions read from it belong to the same state of the DB,
+// even if new blocks are written concurrently. The EVM state isn't available in the view.
+// The snapshot must be released once the reads are done.
+type StoreSnapshot struct {
+	*Store
+	snap kvdb.Snapshot
+}
+
+// Snapshot returns a read-only view of the store over the current state of the database.
+func (s *Store) Snapshot() (*StoreSnapshot, error) {
+	snap, err := s.mainDB.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	db := snap2kvdb.Wrap(snap)
+	view := &Store{
+		cfg:      s.cfg,
+		mainDB:   db,
+		Instance: s.Instance,
+		rlp:      s.rlp,
+	}
+	table.MigrateTables(&view.table, view.mainDB)
+	view.initCache()
+	view.evm = s.evm.View(db)
+	return &StoreSnapshot{
+		Store: view,
+		snap:  snap,
+	}, nil
+}
+
+// Release releases the snapshot of the database.
+func (s *StoreSnapshot) Release() {
+	s.snap.Release()
+}
@@ -0,0 +1,54 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils"
+)
+
+func TestStoreSnapshot(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 3, t)
+	defer env.Close()
+
+	_, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+	require.NoError(err)
+	latest := env.store.GetLatestBlockIndex()
+
+	snap, err := env.store.Snapshot()
+	require.NoError(err)
+	defer snap.Release()
+
+	// the blocks written after the snapshot aren't visible in it
+	_, err = env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+	require.NoError(err)
+	next := env.store.GetLatestBlockIndex()
+	require.Greater(next, latest)
+	require.NotNil(env.store.GetBlock(next))
+	require.Nil(snap.GetBlock(next))
+	receiptsRLP, err := snap.evm.GetRawReceiptsRLP(next)
+	require.NoError(err)
+	require.Nil(receiptsRLP)
+
+	// the blocks written before are
+	block := env.store.GetBlock(latest)
+	require.NotNil(block)
+	require.Equal(block.Atropos, snap.GetBlock(latest).Atropos)
+	expectRLP, err := env.store.evm.GetRawReceiptsRLP(latest)
+	require.NoError(err)
+	receiptsRLP, err = snap.evm.GetRawReceiptsRLP(latest)
+	require.NoError(err)
+	require.Equal(expectRLP, receiptsRLP)
+	for _, txHash := range block.Txs {
+		expectPos, err := env.store.evm.GetTxPosition(txHash)
+		require.NoError(err)
+		pos, err := snap.evm.GetTxPosition(txHash)
+		require.NoError(err)
+		require.Equal(expectPos, pos)
+	}
+}
@@ -0,0 +1,57 @@
+package gossip
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/topicsdb"
+)
+
+// tableTags returns the table prefixes of the tables struct by the table names.
+func tableTags(t *testing.T, owner reflect.Type, path ...string) map[string]string {
+	typ := owner
+	for _, name := range path {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+			typ = typ.Elem()
+		}
+		field, ok := typ.FieldByName(name)
+		require.True(t, ok, "%s has no field %s", typ, name)
+		typ = field.Type
+	}
+	tags := make(map[string]string)
+	for i := 0; i < typ.NumField(); i++ {
+		if tag, ok := typ.Field(i).Tag.Lookup("table"); ok {
+			tags[typ.Field(i).Name] = tag
+		}
+	}
+	require.NotEmpty(t, tags)
+	return tags
+}
+
+// TestStoreTablesUnique checks that the tables sharing the main DB have distinct prefixes.
+func TestStoreTablesUnique(t *testing.T) {
+	logsIndex := reflect.TypeOf(topicsdb.NewWithThreadPool(memorydb.New()))
+	owners := map[string]map[string]string{
+		"gossip":          tableTags(t, reflect.TypeOf(Store{}), "table"),
+		"evmstore":        tableTags(t, reflect.TypeOf(evmstore.Store{}), "table"),
+		"topicsdb":        tableTags(t, logsIndex, "index", "table"),
+		"topicsdb.legacy": tableTags(t, logsIndex, "index", "legacy"),
+	}
+	// the legacy logs records are told from the receipts by the key size, as in the older releases
+	delete(owners["topicsdb.legacy"], "Logrec")
+
+	seen := make(map[string]string)
+	for owner, tags := range owners {
+		for name, prefix := range tags {
+			table := owner + "." + name
+			if other, ok := seen[prefix]; ok {
+				t.Errorf("tables %s and %s share prefix %q", table, other, prefix)
+			}
+			seen[prefix] = table
+		}
+	}
+}
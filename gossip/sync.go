@@ -12,6 +12,7 @@ var isMaybeSyncedGauge = metrics.GetOrRegisterGauge("chain/maybeSynced", nil)
 
 type syncStatus struct {
 	maybeSynced uint32
+	readOnly    uint32
 }
 
 func (ss *syncStatus) MaybeSynced() bool {
@@ -32,7 +33,19 @@ func (ss *syncStatus) AcceptBlockRecords() bool {
 }
 
 func (ss *syncStatus) AcceptTxs() bool {
-	return ss.MaybeSynced()
+	return ss.MaybeSynced() && !ss.ReadOnly()
+}
+
+func (ss *syncStatus) ReadOnly() bool {
+	return atomic.LoadUint32(&ss.readOnly) != 0
+}
+
+func (ss *syncStatus) SetReadOnly(readOnly bool) bool {
+	v := uint32(0)
+	if readOnly {
+		v = 1
+	}
+	return atomic.SwapUint32(&ss.readOnly, v) != 0
 }
 
 func (ss *syncStatus) RequestLLR() bool {
@@ -0,0 +1,58 @@
+package gossip
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/gossip/blocktiming"
+	"github.com/Fantom-foundation/go-opera/gossip/telemetry"
+)
+
+// syncSpeed measures the blocks processing speed since the baseline,
+// the baseline is moved once it's older than the window.
+type syncSpeed struct {
+	window time.Duration
+
+	mu    sync.Mutex
+	time  time.Time
+	block idx.Block
+}
+
+func (s *syncSpeed) update(now time.Time, block idx.Block) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var speed float64
+	if elapsed := now.Sub(s.time); !s.time.IsZero() && elapsed > 0 && block >= s.block {
+		speed = float64(block-s.block) / elapsed.Seconds()
+	}
+	if s.time.IsZero() || now.Sub(s.time) >= s.window {
+		s.time = now
+		s.block = block
+	}
+	return speed
+}
+
+func latencyOf(d blocktiming.Distribution, samples int) telemetry.Latency {
+	return telemetry.Latency{
+		Samples: samples,
+		P50:     d.P50,
+		P90:     d.P90,
+		P99:     d.P99,
+	}
+}
+
+func newTelemetryReporter(svc *Service) *telemetry.Reporter {
+	speed := &syncSpeed{window: svc.config.Telemetry.Interval}
+	speed.update(time.Now(), svc.store.GetLatestBlockIndex())
+	return telemetry.New(svc.config.Telemetry, func() (uint64, telemetry.Sync, telemetry.Latency) {
+		stats := svc.blockTimings.Stats()
+		sync := telemetry.Sync{
+			BlocksPerSecond: speed.update(time.Now(), svc.store.GetLatestBlockIndex()),
+			Lag:             int64(time.Duration(atomic.LoadInt64(&svc.executionLag)) / time.Second),
+		}
+		return svc.store.GetRules().ChainID().Uint64(), sync, latencyOf(stats.Total, stats.Blocks)
+	})
+}
@@ -0,0 +1,16 @@
+package telemetry
+
+// PrivateAPI exposes the telemetry reports to the node operator.
+type PrivateAPI struct {
+	r *Reporter
+}
+
+// NewPrivateAPI creates a new telemetry API.
+func NewPrivateAPI(r *Reporter) *PrivateAPI {
+	return &PrivateAPI{r}
+}
+
+// TelemetryReport returns the report of the current data, exactly as it's sent to the telemetry endpoint.
+func (api *PrivateAPI) TelemetryReport() Report {
+	return api.r.Report()
+}
@@ -0,0 +1,227 @@
+// Package telemetry implements the opt-in reporting of anonymized performance data by HTTP POST requests.
+// It's disabled unless an endpoint is configured.
+//
+// A report is the JSON object of the Report type, the schema of which is versioned by SchemaVersion.
+// The fields aren't removed or changed within a schema version. The report contains no addresses,
+// keys, peers or any other data identifying the node or its operator: the hardware is reported
+// by classes, and the instance ID is random and changes with every restart of the node.
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-opera/utils/memory"
+	"github.com/Fantom-foundation/go-opera/version"
+)
+
+// SchemaVersion is the version of the Report schema.
+const SchemaVersion = 1
+
+var (
+	sentCounter   = metrics.GetOrRegisterCounter("telemetry/sent", nil)
+	failedCounter = metrics.GetOrRegisterCounter("telemetry/failed", nil)
+)
+
+// Config is the telemetry config.
+type Config struct {
+	// Endpoint receives the reports, the telemetry is disabled if empty
+	Endpoint string `toml:",omitempty"`
+	// Interval between the reports
+	Interval time.Duration
+	// Timeout limits a single POST request
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the default telemetry config, the telemetry is disabled by default.
+func DefaultConfig() Config {
+	return Config{
+		Interval: time.Hour,
+		Timeout:  10 * time.Second,
+	}
+}
+
+// Enabled returns true if the reports endpoint is configured.
+func (c Config) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// Validate checks the telemetry config.
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Interval < time.Minute {
+		return fmt.Errorf("telemetry interval %v is less than a minute", c.Interval)
+	}
+	return nil
+}
+
+type (
+	// Report is the body of a telemetry request.
+	Report struct {
+		// Schema is the SchemaVersion of the report
+		Schema int `json:"schema"`
+		// Instance is a random ID of the node process, it changes with every restart
+		Instance string `json:"instance"`
+		// Time is the unix time of the report, in seconds
+		Time int64 `json:"time"`
+		// Version is the node version
+		Version  string   `json:"version"`
+		ChainID  uint64   `json:"chainId"`
+		Hardware Hardware `json:"hardware"`
+		Sync     Sync     `json:"sync"`
+		// BlockProcessing is the distribution of the processing time of the latest blocks
+		BlockProcessing Latency `json:"blockProcessing"`
+	}
+
+	// Hardware is the class of the node hardware.
+	Hardware struct {
+		OS   string `json:"os"`
+		Arch string `json:"arch"`
+		// CPUs is a range of the number of the logical CPUs, e.g. "5-8"
+		CPUs string `json:"cpus"`
+		// Memory is a range of the total memory in GiB, e.g. "17-32"
+		Memory string `json:"memoryGiB"`
+	}
+
+	// Sync is the speed of the chain processing since the previous report.
+	Sync struct {
+		// BlocksPerSecond is the number of the processed blocks per second
+		BlocksPerSecond float64 `json:"blocksPerSecond"`
+		// Lag is the age of the last processed block at the moment of its processing, in seconds
+		Lag int64 `json:"lagSeconds"`
+	}
+
+	// Latency is a distribution of durations, in milliseconds.
+	Latency struct {
+		Samples int     `json:"samples"`
+		P50     float64 `json:"p50"`
+		P90     float64 `json:"p90"`
+		P99     float64 `json:"p99"`
+	}
+
+	// Source provides the chain data of a report.
+	Source func() (chainID uint64, sync Sync, blockProcessing Latency)
+)
+
+// classOf returns the power-of-two range of the value, e.g. "5-8".
+func classOf(v uint64) string {
+	if v <= 1 {
+		return fmt.Sprintf("%d", v)
+	}
+	upper := uint64(2)
+	for upper < v {
+		upper *= 2
+	}
+	return fmt.Sprintf("%d-%d", upper/2+1, upper)
+}
+
+// HardwareClass returns the class of the node hardware.
+func HardwareClass() Hardware {
+	return Hardware{
+		OS:     runtime.GOOS,
+		Arch:   runtime.GOARCH,
+		CPUs:   classOf(uint64(runtime.NumCPU())),
+		Memory: classOf((memory.TotalMemory() + (1<<30 - 1)) >> 30),
+	}
+}
+
+// Reporter sends the reports to the endpoint periodically in background.
+type Reporter struct {
+	cfg      Config
+	client   *http.Client
+	source   Source
+	instance string
+	hardware Hardware
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// New creates the reporter of the chain data provided by the source.
+func New(cfg Config, source Source) *Reporter {
+	instance := make([]byte, 16)
+	_, _ = rand.Read(instance)
+	return &Reporter{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		source:   source,
+		instance: hex.EncodeToString(instance),
+		hardware: HardwareClass(),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start starts sending the reports.
+func (r *Reporter) Start() {
+	log.Info("Anonymized telemetry is enabled", "endpoint", r.cfg.Endpoint, "interval", r.cfg.Interval)
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop stops sending the reports and waits until the pending request is done.
+func (r *Reporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *Reporter) loop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.send(r.Report()); err != nil {
+				failedCounter.Inc(1)
+				log.Debug("Failed to send telemetry report", "endpoint", r.cfg.Endpoint, "err", err)
+			} else {
+				sentCounter.Inc(1)
+			}
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// Report builds the report of the current data.
+func (r *Reporter) Report() Report {
+	chainID, sync, blockProcessing := r.source()
+	return Report{
+		Schema:          SchemaVersion,
+		Instance:        r.instance,
+		Time:            time.Now().Unix(),
+		Version:         version.AsString(),
+		ChainID:         chainID,
+		Hardware:        r.hardware,
+		Sync:            sync,
+		BlockProcessing: blockProcessing,
+	}
+}
+
+func (r *Reporter) send(report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Post(r.cfg.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassOf(t *testing.T) {
+	for v, expect := range map[uint64]string{
+		0:  "0",
+		1:  "1",
+		2:  "2-2",
+		3:  "3-4",
+		4:  "3-4",
+		5:  "5-8",
+		16: "9-16",
+		17: "17-32",
+	} {
+		require.Equal(t, expect, classOf(v), v)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.False(cfg.Enabled())
+	require.NoError(cfg.Validate())
+
+	cfg.Endpoint = "http://localhost"
+	require.True(cfg.Enabled())
+	require.NoError(cfg.Validate())
+
+	cfg.Interval = time.Second
+	require.Error(cfg.Validate())
+}
+
+func TestReporter(t *testing.T) {
+	require := require.New(t)
+
+	bodies := make(chan []byte, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.Endpoint = srv.URL
+	cfg.Interval = 10 * time.Millisecond
+	r := New(cfg, func() (uint64, Sync, Latency) {
+		return 250, Sync{BlocksPerSecond: 1.5, Lag: 2}, Latency{Samples: 10, P50: 1, P90: 2, P99: 3}
+	})
+	r.Start()
+	defer r.Stop()
+
+	var body []byte
+	select {
+	case body = <-bodies:
+	case <-time.After(5 * time.Second):
+		t.Fatal("report isn't received")
+	}
+
+	var report Report
+	require.NoError(json.Unmarshal(body, &report))
+	require.Equal(SchemaVersion, report.Schema)
+	require.Len(report.Instance, 32)
+	require.Equal(uint64(250), report.ChainID)
+	require.Equal(HardwareClass(), report.Hardware)
+	require.Equal(Sync{BlocksPerSecond: 1.5, Lag: 2}, report.Sync)
+	require.Equal(Latency{Samples: 10, P50: 1, P90: 2, P99: 3}, report.BlockProcessing)
+
+	// the published schema
+	var fields map[string]interface{}
+	require.NoError(json.Unmarshal(body, &fields))
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	require.ElementsMatch([]string{"schema", "instance", "time", "version", "chainId", "hardware", "sync", "blockProcessing"}, keys)
+	require.Equal(map[string]interface{}{"blocksPerSecond": 1.5, "lagSeconds": float64(2)}, fields["sync"])
+}
@@ -0,0 +1,21 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncSpeed(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Now()
+	speed := &syncSpeed{window: time.Minute}
+	require.Zero(speed.update(start, 100))
+	require.Equal(2.0, speed.update(start.Add(10*time.Second), 120))
+	// the baseline is kept within the window
+	require.Equal(1.0, speed.update(start.Add(time.Minute), 160))
+	// the baseline is moved after the window
+	require.Equal(0.5, speed.update(start.Add(2*time.Minute), 190))
+}
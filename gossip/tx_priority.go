@@ -0,0 +1,58 @@
+package gossip
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// prioritizeTxs orders the transactions by the likeliness of their inclusion, the same way the emitter
+// picks them: the transactions with a higher effective tip go first, while the transactions of a sender keep
+// the nonce order. The transactions which can't be ordered (e.g. underpriced) are put last in the original order.
+func prioritizeTxs(signer types.Signer, txs types.Transactions, baseFee *big.Int) types.Transactions {
+	bySender := make(map[common.Address]types.Transactions)
+	for _, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		bySender[from] = append(bySender[from], tx)
+	}
+	for _, senderTxs := range bySender {
+		sort.Sort(types.TxByNonce(senderTxs))
+	}
+
+	ordered := make(types.Transactions, 0, len(txs))
+	included := make(map[common.Hash]bool, len(txs))
+	sorted := types.NewTransactionsByPriceAndNonce(signer, bySender, baseFee)
+	for tx := sorted.Peek(); tx != nil; tx = sorted.Peek() {
+		ordered = append(ordered, tx)
+		included[tx.Hash()] = true
+		sorted.Shift()
+	}
+	for _, tx := range txs {
+		if !included[tx.Hash()] {
+			ordered = append(ordered, tx)
+		}
+	}
+	return ordered
+}
+
+// congested returns true if the peer's broadcast queue is filled above the configured level.
+func (h *handler) congested(p *peer) bool {
+	limit := h.config.Protocol.TxPriority.CongestedQueue
+	return limit > 0 && len(p.queue)*100 >= cap(p.queue)*limit
+}
+
+// txsToPropagate splits the transactions for the peer into the ones sent in full and the ones only announced.
+// A congested peer gets only the top priority share of the transactions in full.
+func (h *handler) txsToPropagate(p *peer, txs types.Transactions) (full types.Transactions, announced types.Transactions) {
+	if !h.config.Protocol.TxPriority.Enabled || !h.congested(p) {
+		return txs, nil
+	}
+	n := len(txs) * h.config.Protocol.TxPriority.CongestedFullShare / 100
+	congestedTxsCounter.Inc(int64(len(txs) - n))
+	return txs[:n], txs[n:]
+}
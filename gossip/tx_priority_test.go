@@ -0,0 +1,48 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrioritizeTxs(t *testing.T) {
+	require := require.New(t)
+
+	signer := types.HomesteadSigner{}
+	sign := func(key *ecdsa.PrivateKey, nonce uint64, gasPrice int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(gasPrice), nil), signer, key)
+		require.NoError(err)
+		return tx
+	}
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := crypto.GenerateKey()
+		require.NoError(err)
+		return key
+	}
+
+	a := newKey()
+	a0 := sign(a, 0, 10)
+	a1 := sign(a, 1, 100)
+	b0 := sign(newKey(), 0, 50)
+	underpriced := sign(newKey(), 0, 1)
+
+	ordered := prioritizeTxs(signer, types.Transactions{underpriced, a1, b0, a0}, big.NewInt(5))
+	// the higher tip goes first, but the nonce order of a sender is kept
+	require.Equal([]common.Hash{b0.Hash(), a0.Hash(), a1.Hash(), underpriced.Hash()}, hashesOf(ordered))
+
+	require.Empty(prioritizeTxs(signer, nil, big.NewInt(5)))
+}
+
+func hashesOf(txs types.Transactions) []common.Hash {
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
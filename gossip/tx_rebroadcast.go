@@ -0,0 +1,315 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+)
+
+// maxTxReplacements is a limit of the pre-signed replacements of a tracked transaction
+const maxTxReplacements = 8
+
+// statuses of the tracked transactions
+const (
+	txRebroadcastPending  = "pending"
+	txRebroadcastIncluded = "included"
+	txRebroadcastExpired  = "expired"
+	txRebroadcastDropped  = "dropped"
+)
+
+// rebroadcastTx is a local transaction tracked until its inclusion.
+type rebroadcastTx struct {
+	sent         []*types.Transaction // the transaction and its submitted replacements, the last one is rebroadcast
+	replacements []*types.Transaction // pre-signed replacements, which aren't submitted yet
+	submitted    time.Time
+	finished     time.Time
+	broadcasts   int // number of rebroadcasts since the last submission
+	status       string
+	included     common.Hash
+	position     *evmstore.TxPosition
+	err          error
+}
+
+// txRebroadcaster rebroadcasts periodically the local transactions until they are included or expired.
+// The peers may drop a transaction, e.g. once their pools are full, so it's sent to all the peers again,
+// whether they are known to have it or not. If the sender has provided the replacements of the transaction
+// with higher fees, the next one is submitted after a number of rebroadcasts without the inclusion.
+// The replacements are signed by the sender, so the node never bumps the fees on its own.
+type txRebroadcaster struct {
+	cfg    TxRebroadcastConfig
+	signer types.Signer
+
+	pool       TxPool
+	txPosition func(common.Hash) (*evmstore.TxPosition, error)
+	broadcast  func(types.Transactions)
+
+	mu  sync.Mutex
+	txs map[common.Hash]*rebroadcastTx
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newTxRebroadcaster(svc *Service) *txRebroadcaster {
+	return &txRebroadcaster{
+		cfg:        svc.config.TxRebroadcast,
+		signer:     svc.EthAPI.signer,
+		pool:       svc.txpool,
+		txPosition: svc.store.evm.GetTxPosition,
+		broadcast: func(txs types.Transactions) {
+			svc.handler.RebroadcastTxs(txs)
+		},
+		txs:  make(map[common.Hash]*rebroadcastTx),
+		quit: make(chan struct{}),
+	}
+}
+
+func (r *txRebroadcaster) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *txRebroadcaster) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *txRebroadcaster) loop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.process(now)
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// Track starts tracking the submitted local transaction.
+func (r *txRebroadcaster) Track(tx *types.Transaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.txs[tx.Hash()]; ok || len(r.txs) >= r.cfg.MaxTxs {
+		return
+	}
+	r.txs[tx.Hash()] = &rebroadcastTx{
+		sent:      []*types.Transaction{tx},
+		submitted: time.Now(),
+		status:    txRebroadcastPending,
+	}
+}
+
+// AddReplacements appends the pre-signed replacements of the tracked transaction.
+// The replacements must be signed by the same sender, have the same nonce and ascending fees.
+func (r *txRebroadcaster) AddReplacements(hash common.Hash, txs []*types.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.txs[hash]
+	if t == nil {
+		return errors.New("transaction isn't tracked")
+	}
+	if t.status != txRebroadcastPending {
+		return fmt.Errorf("transaction is %s", t.status)
+	}
+	if len(t.replacements)+len(txs) > maxTxReplacements {
+		return fmt.Errorf("too many replacements, the limit is %d", maxTxReplacements)
+	}
+	from, err := types.Sender(r.signer, t.sent[0])
+	if err != nil {
+		return err
+	}
+	prev := t.sent[len(t.sent)-1]
+	if len(t.replacements) != 0 {
+		prev = t.replacements[len(t.replacements)-1]
+	}
+	for _, tx := range txs {
+		sender, err := types.Sender(r.signer, tx)
+		if err != nil || sender != from {
+			return errors.New("replacement isn't signed by the transaction sender")
+		}
+		if tx.Nonce() != prev.Nonce() {
+			return errors.New("replacement nonce mismatches the transaction nonce")
+		}
+		if tx.GasFeeCapCmp(prev) <= 0 || tx.GasTipCapCmp(prev) <= 0 {
+			return errors.New("replacement fees aren't higher than the previous ones")
+		}
+		prev = tx
+	}
+	t.replacements = append(t.replacements, txs...)
+	return nil
+}
+
+// process updates the statuses of the tracked transactions and rebroadcasts the pending ones.
+func (r *txRebroadcaster) process(now time.Time) {
+	var batch types.Transactions
+	r.mu.Lock()
+	for hash, t := range r.txs {
+		if t.status != txRebroadcastPending {
+			// the finished transactions are kept for a while to report their statuses
+			if now.Sub(t.finished) > r.cfg.Expiry {
+				delete(r.txs, hash)
+			}
+			continue
+		}
+		if r.checkIncluded(t) {
+			t.status = txRebroadcastIncluded
+			t.finished = now
+			continue
+		}
+		if now.Sub(t.submitted) > r.cfg.Expiry {
+			t.status = txRebroadcastExpired
+			t.finished = now
+			continue
+		}
+
+		cur := t.sent[len(t.sent)-1]
+		if r.cfg.BumpAfter > 0 && t.broadcasts >= r.cfg.BumpAfter && len(t.replacements) != 0 {
+			next := t.replacements[0]
+			t.replacements = t.replacements[1:]
+			if err := r.pool.AddLocal(next); err != nil {
+				t.err = err
+			} else {
+				t.sent = append(t.sent, next)
+				t.broadcasts = 0
+				t.err = nil
+				cur = next
+			}
+		} else if r.pool.Get(cur.Hash()) == nil {
+			// the transaction is evicted from the pool, e.g. as underpriced
+			if err := r.pool.AddLocal(cur); err != nil {
+				t.status = txRebroadcastDropped
+				t.finished = now
+				t.err = err
+				continue
+			}
+		}
+		t.broadcasts++
+		batch = append(batch, cur)
+	}
+	r.mu.Unlock()
+
+	if len(batch) != 0 {
+		r.broadcast(batch)
+	}
+}
+
+// checkIncluded reports whether the transaction or any of its submitted replacements is included.
+func (r *txRebroadcaster) checkIncluded(t *rebroadcastTx) bool {
+	for _, tx := range t.sent {
+		position, err := r.txPosition(tx.Hash())
+		if err != nil {
+			log.Warn("Failed to get rebroadcast tx position", "tx", tx.Hash(), "err", err)
+			continue
+		}
+		if position != nil {
+			t.included = tx.Hash()
+			t.position = position
+			return true
+		}
+	}
+	return false
+}
+
+// TxRebroadcastStatus is the status of a tracked local transaction.
+type TxRebroadcastStatus struct {
+	Hash         common.Hash     `json:"hash"`
+	Status       string          `json:"status"`
+	Current      common.Hash     `json:"current"` // the transaction or its last submitted replacement
+	Submitted    hexutil.Uint64  `json:"submitted"`
+	Broadcasts   hexutil.Uint64  `json:"broadcasts"`
+	Bumps        hexutil.Uint64  `json:"bumps"`
+	Replacements hexutil.Uint64  `json:"replacements"` // number of the pre-signed replacements left
+	Included     *common.Hash    `json:"included,omitempty"`
+	BlockNumber  *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+func (r *txRebroadcaster) status(hash common.Hash, t *rebroadcastTx) *TxRebroadcastStatus {
+	res := &TxRebroadcastStatus{
+		Hash:         hash,
+		Status:       t.status,
+		Current:      t.sent[len(t.sent)-1].Hash(),
+		Submitted:    hexutil.Uint64(t.submitted.Unix()),
+		Broadcasts:   hexutil.Uint64(t.broadcasts),
+		Bumps:        hexutil.Uint64(len(t.sent) - 1),
+		Replacements: hexutil.Uint64(len(t.replacements)),
+	}
+	if t.position != nil {
+		included := t.included
+		block := hexutil.Uint64(t.position.Block)
+		res.Included = &included
+		res.BlockNumber = &block
+	}
+	if t.err != nil {
+		res.Error = t.err.Error()
+	}
+	return res
+}
+
+// Status returns the status of the tracked transaction, or nil if it isn't tracked.
+func (r *txRebroadcaster) Status(hash common.Hash) *TxRebroadcastStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.txs[hash]
+	if t == nil {
+		return nil
+	}
+	return r.status(hash, t)
+}
+
+// List returns the statuses of all the tracked transactions.
+func (r *txRebroadcaster) List() []*TxRebroadcastStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]*TxRebroadcastStatus, 0, len(r.txs))
+	for hash, t := range r.txs {
+		res = append(res, r.status(hash, t))
+	}
+	return res
+}
+
+// PublicTxRebroadcastAPI provides an API to inspect the rebroadcasting of the local transactions
+// and to provide the pre-signed replacements of them with higher fees.
+type PublicTxRebroadcastAPI struct {
+	r *txRebroadcaster
+}
+
+// NewPublicTxRebroadcastAPI creates a new rebroadcasting API.
+func NewPublicTxRebroadcastAPI(s *Service) *PublicTxRebroadcastAPI {
+	return &PublicTxRebroadcastAPI{s.txRebroadcaster}
+}
+
+// RebroadcastStatus returns the rebroadcasting status of the local transaction, or nil if it isn't tracked.
+func (api *PublicTxRebroadcastAPI) RebroadcastStatus(hash common.Hash) *TxRebroadcastStatus {
+	return api.r.Status(hash)
+}
+
+// Rebroadcasting returns the rebroadcasting statuses of all the tracked local transactions.
+func (api *PublicTxRebroadcastAPI) Rebroadcasting() []*TxRebroadcastStatus {
+	return api.r.List()
+}
+
+// AddReplacements adds the signed replacements of the local transaction with ascending fees.
+// The next replacement is submitted once the transaction isn't included after a number of rebroadcasts.
+func (api *PublicTxRebroadcastAPI) AddReplacements(ctx context.Context, hash common.Hash, encodedTxs []hexutil.Bytes) error {
+	txs := make([]*types.Transaction, len(encodedTxs))
+	for i, b := range encodedTxs {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(b); err != nil {
+			return err
+		}
+	}
+	return api.r.AddReplacements(hash, txs)
+}
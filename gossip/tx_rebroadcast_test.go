@@ -0,0 +1,141 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+)
+
+// rejectingTxPool is a dummyTxPool which fails to add the transactions once reject is set
+type rejectingTxPool struct {
+	dummyTxPool
+	reject error
+}
+
+func (p *rejectingTxPool) AddLocal(tx *types.Transaction) error {
+	if p.reject != nil {
+		return p.reject
+	}
+	return p.dummyTxPool.AddLocal(tx)
+}
+
+func TestTxRebroadcaster(t *testing.T) {
+	require := require.New(t)
+
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	sign := func(key *ecdsa.PrivateKey, nonce uint64, fee int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(fee),
+			GasFeeCap: big.NewInt(fee),
+			Gas:       21000,
+		})
+	}
+
+	pool := &rejectingTxPool{}
+	positions := map[common.Hash]*evmstore.TxPosition{}
+	var broadcasted []types.Transactions
+	r := &txRebroadcaster{
+		cfg: TxRebroadcastConfig{
+			Interval:  time.Second,
+			Expiry:    time.Minute,
+			BumpAfter: 2,
+			MaxTxs:    2,
+		},
+		signer: signer,
+		pool:   pool,
+		txPosition: func(h common.Hash) (*evmstore.TxPosition, error) {
+			return positions[h], nil
+		},
+		broadcast: func(txs types.Transactions) {
+			broadcasted = append(broadcasted, txs)
+		},
+		txs: make(map[common.Hash]*rebroadcastTx),
+	}
+
+	tx0 := sign(key, 0, 100)
+	tx1 := sign(key, 1, 100)
+	require.NoError(pool.AddLocal(tx0))
+	r.Track(tx0)
+	r.Track(tx1)
+	r.Track(sign(key, 2, 100))
+	require.Len(r.List(), 2, "MaxTxs is reached")
+
+	// replacements
+	bump1, bump2 := sign(key, 0, 200), sign(key, 0, 300)
+	require.Error(r.AddReplacements(common.Hash{1}, []*types.Transaction{bump1}), "not tracked")
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{sign(otherKey, 0, 200)}), "other sender")
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{sign(key, 1, 200)}), "other nonce")
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{sign(key, 0, 100)}), "same fees")
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{bump2, bump1}), "descending fees")
+	tooMany := make([]*types.Transaction, maxTxReplacements+1)
+	for i := range tooMany {
+		tooMany[i] = sign(key, 0, int64(200+i))
+	}
+	require.Error(r.AddReplacements(tx0.Hash(), tooMany), "too many")
+	require.NoError(r.AddReplacements(tx0.Hash(), []*types.Transaction{bump1}))
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{sign(key, 0, 150)}), "lower than the previous replacement")
+	require.NoError(r.AddReplacements(tx0.Hash(), []*types.Transaction{bump2}))
+	require.Equal(uint64(2), uint64(r.Status(tx0.Hash()).Replacements))
+
+	// rebroadcast, the missing tx is added into the pool again
+	now := time.Now()
+	r.process(now)
+	require.Len(broadcasted, 1)
+	require.ElementsMatch(types.Transactions{tx0, tx1}, broadcasted[0])
+	require.NotNil(pool.Get(tx1.Hash()))
+	r.process(now)
+	require.Equal(uint64(2), uint64(r.Status(tx0.Hash()).Broadcasts))
+
+	// fee bump after BumpAfter rebroadcasts
+	r.process(now)
+	require.ElementsMatch(types.Transactions{bump1, tx1}, broadcasted[2])
+	require.NotNil(pool.Get(bump1.Hash()))
+	status := r.Status(tx0.Hash())
+	require.Equal(bump1.Hash(), status.Current)
+	require.Equal(uint64(1), uint64(status.Bumps))
+	require.Equal(uint64(1), uint64(status.Replacements))
+	require.Equal(uint64(1), uint64(status.Broadcasts))
+
+	// inclusion of the replacement
+	positions[bump1.Hash()] = &evmstore.TxPosition{Block: 5}
+	// the dropped tx can't be added into the pool again
+	pool.Delete(tx1.Hash())
+	pool.reject = errors.New("underpriced")
+	r.process(now)
+	require.Len(broadcasted, 3)
+
+	status = r.Status(tx0.Hash())
+	require.Equal(txRebroadcastIncluded, status.Status)
+	require.Equal(bump1.Hash(), *status.Included)
+	require.Equal(uint64(5), uint64(*status.BlockNumber))
+	require.Error(r.AddReplacements(tx0.Hash(), []*types.Transaction{sign(key, 0, 400)}), "finished")
+
+	status = r.Status(tx1.Hash())
+	require.Equal(txRebroadcastDropped, status.Status)
+	require.Equal("underpriced", status.Error)
+
+	// the finished txs are forgotten after the expiry
+	pool.reject = nil
+	r.process(now.Add(2 * time.Minute))
+	require.Empty(r.List())
+
+	// expiry
+	tx2 := sign(key, 2, 100)
+	r.Track(tx2)
+	r.process(time.Now().Add(2 * time.Minute))
+	require.Equal(txRebroadcastExpired, r.Status(tx2.Hash()).Status)
+	require.Len(broadcasted, 3)
+}
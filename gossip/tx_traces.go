@@ -0,0 +1,71 @@
+package gossip
+
+import (
+	"encoding/json"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/txtrace"
+)
+
+type tracedTx struct {
+	hash   common.Hash
+	tracer *txtrace.TraceStructLogger
+}
+
+// blockTxTracer traces transactions during the block processing, so the traces are persisted
+// in the block callback and the trace API doesn't need to re-execute transactions of recent blocks.
+type blockTxTracer struct {
+	current *txtrace.TraceStructLogger
+	traced  []tracedTx
+	// applied is the number of the applied transactions, i.e. the position of the next transaction
+	// in the block, as the skipped transactions are excluded from the block
+	applied uint
+}
+
+func newBlockTxTracer() *blockTxTracer {
+	return &blockTxTracer{}
+}
+
+// StartTx returns the tracer of the transaction.
+// The index within the executed batch is ignored, the position in the block is counted by the tracer.
+func (t *blockTxTracer) StartTx(block *evmcore.EvmBlock, tx *types.Transaction, msg types.Message, _ int) vm.Tracer {
+	t.current = txtrace.NewTraceStructLogger(block, tx, msg, t.applied, 0)
+	return t.current
+}
+
+// EndTx records the trace of the applied transaction. Skipped transactions aren't recorded,
+// neither are the empty traces of failed transactions, the API replays them to build the error trace.
+func (t *blockTxTracer) EndTx(tx *types.Transaction, receipt *types.Receipt) {
+	if t.current == nil {
+		return
+	}
+	if receipt != nil {
+		t.applied++
+		if len(*t.current.GetResult()) != 0 {
+			t.current.SetGasUsed(receipt.GasUsed)
+			t.traced = append(t.traced, tracedTx{tx.Hash(), t.current})
+		}
+	}
+	t.current = nil
+}
+
+// persist stores the traces of the block and erases the traces which are out of the retention window.
+func (t *blockTxTracer) persist(store *Store, n idx.Block, retention idx.Block) {
+	if retention != 0 && n > retention {
+		store.evm.DelTxTraces(n - retention)
+	}
+	for _, tx := range t.traced {
+		buf, err := json.Marshal(tx.tracer.GetResult())
+		if err != nil {
+			store.Log.Warn("Failed to encode transaction traces", "block", n, "tx", tx.hash, "err", err)
+			continue
+		}
+		store.evm.SetTxTraces(n, tx.hash, buf)
+	}
+	store.evm.SetTxTracesIndex(n, len(t.traced))
+}
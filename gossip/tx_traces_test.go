@@ -0,0 +1,54 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils"
+)
+
+func TestTxTracesPersist(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	cfg := DefaultConfig(cachescale.Identity)
+	cfg.TxTracesPersist = true
+	cfg.TxTracesRetention = 2
+	env := newTestEnvWithConfig(2, 3, cfg, t)
+	defer env.Close()
+
+	var blocks []idx.Block
+	for i := 0; i < 4; i++ {
+		tx := env.Transfer(1, 2, utils.ToFtm(1))
+		receipts, err := env.ApplyTxs(sameEpoch, tx)
+		require.NoError(err)
+		require.Len(receipts, 1)
+		n := idx.Block(receipts[0].BlockNumber.Uint64())
+		blocks = append(blocks, n)
+
+		// the traces are stored by the block processing
+		require.True(env.store.evm.HasTxTraces(n))
+		traces, err := env.EthAPI.GetTxTraces(context.Background(), n, tx.Hash())
+		require.NoError(err)
+		require.NotNil(traces)
+		require.Len(*traces, 1)
+		require.Equal("call", (*traces)[0].TraceType)
+		require.Equal(tx.Hash(), (*traces)[0].TransactionHash)
+		require.EqualValues(receipts[0].TransactionIndex, (*traces)[0].TransactionPosition)
+		require.EqualValues(receipts[0].GasUsed, (*traces)[0].Result.GasUsed)
+	}
+
+	// the traces out of the retention window are erased along with the index
+	last := blocks[len(blocks)-1]
+	for _, n := range blocks {
+		if n+cfg.TxTracesRetention > last {
+			continue
+		}
+		require.False(env.store.evm.HasTxTraces(n), n)
+	}
+}
@@ -0,0 +1,294 @@
+// Package webhook implements the notifications of the chain conditions by HTTP POST requests.
+// A notification is a JSON object, which is optionally signed by HMAC-SHA256 of the body
+// in the SignatureHeader, so the receiver can authenticate it.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Kinds of the notified conditions.
+const (
+	// KindBlock is a new finalized block
+	KindBlock = "block"
+	// KindAddress is an activity of a watched address: a transaction from or to it, or a log emitted by it
+	KindAddress = "address"
+	// KindMissed is a validator which didn't emit events for too many blocks
+	KindMissed = "missed"
+	// KindReorg is a new block which doesn't extend the previous one
+	KindReorg = "reorg"
+)
+
+// SignatureHeader is the HTTP header of the body signature, formatted as "sha256=<hex>".
+const SignatureHeader = "X-Webhook-Signature"
+
+var (
+	sentCounter    = metrics.GetOrRegisterCounter("webhook/sent", nil)
+	failedCounter  = metrics.GetOrRegisterCounter("webhook/failed", nil)
+	droppedCounter = metrics.GetOrRegisterCounter("webhook/dropped", nil)
+)
+
+// HookConfig is a webhook endpoint and the conditions it's notified about.
+type HookConfig struct {
+	// URL receives the POST requests
+	URL string
+	// Secret is the HMAC key of the body signature, the requests aren't signed if empty
+	Secret string `toml:",omitempty"`
+	// Kinds are the notified conditions, see the Kind constants
+	Kinds []string
+	// Addresses are the watched addresses of the KindAddress notifications
+	Addresses []common.Address `toml:",omitempty"`
+}
+
+// Config is the webhooks config.
+type Config struct {
+	Hooks []HookConfig `toml:",omitempty"`
+	// Timeout limits a single POST request
+	Timeout time.Duration
+	// Retries is the number of the repeated attempts after a failed request
+	Retries int
+	// RetryDelay is the delay before the first retry, it's doubled for each next one
+	RetryDelay time.Duration
+	// QueueSize is the number of the notifications queued per hook, the new ones are dropped once it's full
+	QueueSize int
+	// MissedBlocks is the number of blocks without events of a validator, after which it's considered missing
+	MissedBlocks int
+}
+
+// DefaultConfig returns the default webhooks config.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:      5 * time.Second,
+		Retries:      3,
+		RetryDelay:   time.Second,
+		QueueSize:    1024,
+		MissedBlocks: 50,
+	}
+}
+
+// Enabled returns true if any hook is configured.
+func (c Config) Enabled() bool {
+	return len(c.Hooks) != 0
+}
+
+// Validate checks the hooks config.
+func (c Config) Validate() error {
+	for i, h := range c.Hooks {
+		if h.URL == "" {
+			return fmt.Errorf("webhook %d has no URL", i)
+		}
+		if len(h.Kinds) == 0 {
+			return fmt.Errorf("webhook %s has no kinds of notifications", h.URL)
+		}
+		for _, kind := range h.Kinds {
+			switch kind {
+			case KindBlock, KindMissed, KindReorg:
+			case KindAddress:
+				if len(h.Addresses) == 0 {
+					return fmt.Errorf("webhook %s watches address activity, but no addresses", h.URL)
+				}
+			default:
+				return fmt.Errorf("webhook %s has unknown kind of notifications %q", h.URL, kind)
+			}
+		}
+	}
+	return nil
+}
+
+// Notification is the body of a webhook request.
+type Notification struct {
+	Kind string      `json:"kind"`
+	Time int64       `json:"time"` // unix time of the notification, in seconds
+	Data interface{} `json:"data"`
+}
+
+type hook struct {
+	cfg       HookConfig
+	kinds     map[string]bool
+	addresses map[common.Address]bool
+	queue     chan []byte
+}
+
+// Notifier sends the notifications to the hooks in background. A slow hook doesn't delay the others.
+type Notifier struct {
+	cfg    Config
+	client *http.Client
+	hooks  []*hook
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// New creates the notifier of the configured hooks.
+func New(cfg Config) *Notifier {
+	n := &Notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		quit:   make(chan struct{}),
+	}
+	for _, hc := range cfg.Hooks {
+		h := &hook{
+			cfg:       hc,
+			kinds:     make(map[string]bool, len(hc.Kinds)),
+			addresses: make(map[common.Address]bool, len(hc.Addresses)),
+			queue:     make(chan []byte, cfg.QueueSize),
+		}
+		for _, kind := range hc.Kinds {
+			h.kinds[kind] = true
+		}
+		for _, addr := range hc.Addresses {
+			h.addresses[addr] = true
+		}
+		n.hooks = append(n.hooks, h)
+	}
+	return n
+}
+
+// Start starts sending the notifications.
+func (n *Notifier) Start() {
+	for _, h := range n.hooks {
+		n.wg.Add(1)
+		go n.loop(h)
+	}
+}
+
+// Stop stops sending, the queued notifications are dropped.
+func (n *Notifier) Stop() {
+	close(n.quit)
+	n.wg.Wait()
+}
+
+// Wants returns true if any hook is notified about the kind of conditions.
+func (n *Notifier) Wants(kind string) bool {
+	for _, h := range n.hooks {
+		if h.kinds[kind] {
+			return true
+		}
+	}
+	return false
+}
+
+// Watched returns true if any hook watches the activity of the address.
+func (n *Notifier) Watched(addr common.Address) bool {
+	for _, h := range n.hooks {
+		if h.kinds[KindAddress] && h.addresses[addr] {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify queues the notification for the hooks of its kind.
+func (n *Notifier) Notify(kind string, data interface{}) {
+	n.notify(kind, data, func(h *hook) bool {
+		return h.kinds[kind]
+	})
+}
+
+// NotifyAddress queues the notification of the address activity for the hooks watching the address.
+func (n *Notifier) NotifyAddress(addr common.Address, data interface{}) {
+	n.notify(KindAddress, data, func(h *hook) bool {
+		return h.kinds[KindAddress] && h.addresses[addr]
+	})
+}
+
+func (n *Notifier) notify(kind string, data interface{}, match func(h *hook) bool) {
+	var body []byte
+	for _, h := range n.hooks {
+		if !match(h) {
+			continue
+		}
+		if body == nil {
+			var err error
+			body, err = json.Marshal(Notification{
+				Kind: kind,
+				Time: time.Now().Unix(),
+				Data: data,
+			})
+			if err != nil {
+				log.Warn("Failed to encode webhook notification", "kind", kind, "err", err)
+				return
+			}
+		}
+		select {
+		case h.queue <- body:
+		default:
+			droppedCounter.Inc(1)
+			log.Debug("Webhook queue is full, notification dropped", "url", h.cfg.URL, "kind", kind)
+		}
+	}
+}
+
+func (n *Notifier) loop(h *hook) {
+	defer n.wg.Done()
+	for {
+		select {
+		case body := <-h.queue:
+			n.deliver(h, body)
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// deliver sends the notification, retrying with an exponential backoff.
+func (n *Notifier) deliver(h *hook, body []byte) {
+	delay := n.cfg.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := n.post(h, body)
+		if err == nil {
+			sentCounter.Inc(1)
+			return
+		}
+		if attempt >= n.cfg.Retries {
+			failedCounter.Inc(1)
+			log.Warn("Failed to deliver webhook notification", "url", h.cfg.URL, "attempts", attempt+1, "err", err)
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-n.quit:
+			return
+		}
+		delay *= 2
+	}
+}
+
+// Sign returns the signature of the body by the secret, as it's set in the SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) post(h *hook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(h.cfg.Secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	notification Notification
+	signature    string
+	body         []byte
+}
+
+func testServer(t *testing.T, failures int32) (*httptest.Server, chan received) {
+	ch := make(chan received, 16)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var n Notification
+		require.NoError(t, json.Unmarshal(body, &n))
+		ch <- received{n, r.Header.Get(SignatureHeader), body}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func testConfig(hooks ...HookConfig) Config {
+	cfg := DefaultConfig()
+	cfg.RetryDelay = time.Millisecond
+	cfg.Hooks = hooks
+	return cfg
+}
+
+func waitReceived(t *testing.T, ch chan received) received {
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification isn't received")
+		return received{}
+	}
+}
+
+func TestNotifierSignsAndRetries(t *testing.T) {
+	require := require.New(t)
+
+	srv, ch := testServer(t, 2)
+	n := New(testConfig(HookConfig{
+		URL:    srv.URL,
+		Secret: "secret",
+		Kinds:  []string{KindBlock},
+	}))
+	n.Start()
+	defer n.Stop()
+
+	require.True(n.Wants(KindBlock))
+	require.False(n.Wants(KindReorg))
+	n.Notify(KindReorg, "ignored")
+	n.Notify(KindBlock, map[string]uint64{"number": 7})
+
+	r := waitReceived(t, ch)
+	require.Equal(KindBlock, r.notification.Kind)
+	require.Equal(map[string]interface{}{"number": float64(7)}, r.notification.Data)
+	require.Equal(Sign("secret", r.body), r.signature)
+}
+
+func TestNotifierAddressFilter(t *testing.T) {
+	require := require.New(t)
+
+	watched := common.Address{1}
+	srv, ch := testServer(t, 0)
+	n := New(testConfig(HookConfig{
+		URL:       srv.URL,
+		Kinds:     []string{KindAddress},
+		Addresses: []common.Address{watched},
+	}))
+	n.Start()
+	defer n.Stop()
+
+	require.True(n.Watched(watched))
+	require.False(n.Watched(common.Address{2}))
+	n.NotifyAddress(common.Address{2}, "ignored")
+	n.NotifyAddress(watched, "activity")
+
+	r := waitReceived(t, ch)
+	require.Equal(KindAddress, r.notification.Kind)
+	require.Equal("activity", r.notification.Data)
+	// unsigned if no secret
+	require.Empty(r.signature)
+	select {
+	case r := <-ch:
+		t.Fatalf("unexpected notification %v", r.notification)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(DefaultConfig().Validate())
+	require.False(DefaultConfig().Enabled())
+
+	cfg := testConfig(HookConfig{URL: "http://localhost", Kinds: []string{KindBlock, KindMissed}})
+	require.NoError(cfg.Validate())
+	require.True(cfg.Enabled())
+
+	require.Error(testConfig(HookConfig{Kinds: []string{KindBlock}}).Validate())
+	require.Error(testConfig(HookConfig{URL: "http://localhost"}).Validate())
+	require.Error(testConfig(HookConfig{URL: "http://localhost", Kinds: []string{"unknown"}}).Validate())
+	require.Error(testConfig(HookConfig{URL: "http://localhost", Kinds: []string{KindAddress}}).Validate())
+}
@@ -0,0 +1,167 @@
+package gossip
+
+import (
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	notify "github.com/ethereum/go-ethereum/event"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/webhook"
+)
+
+// blockNotification is the data of the webhook.KindBlock and webhook.KindReorg notifications.
+type blockNotification struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Time       hexutil.Uint64 `json:"timestamp"`
+	Txs        int            `json:"transactions"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+}
+
+// reorgNotification is the data of the webhook.KindReorg notification.
+type reorgNotification struct {
+	Previous blockNotification `json:"previous"`
+	Block    blockNotification `json:"block"`
+}
+
+// addressNotification is the data of the webhook.KindAddress notification.
+type addressNotification struct {
+	Address     common.Address `json:"address"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	// Activity is "from" or "to" for a transaction, "log" for an emitted log
+	Activity string `json:"activity"`
+}
+
+// missedNotification is the data of the webhook.KindMissed notification.
+type missedNotification struct {
+	ValidatorID idx.ValidatorID `json:"validatorId"`
+	LastBlock   hexutil.Uint64  `json:"lastBlock"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+}
+
+// webhookWatcher detects the chain conditions of the new blocks and notifies the webhooks about them.
+type webhookWatcher struct {
+	svc      *Service
+	notifier *webhook.Notifier
+
+	prev   *blockNotification
+	missed map[idx.ValidatorID]bool // validators, which are notified as missing
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newWebhookWatcher(svc *Service) *webhookWatcher {
+	return &webhookWatcher{
+		svc:      svc,
+		notifier: webhook.New(svc.config.Webhooks),
+		missed:   make(map[idx.ValidatorID]bool),
+		quit:     make(chan struct{}),
+	}
+}
+
+func (w *webhookWatcher) Start() {
+	w.notifier.Start()
+
+	heads := make(chan evmcore.ChainHeadNotify, 16)
+	headsSub := w.svc.feed.SubscribeNewBlock(heads)
+	logs := make(chan []*types.Log, 16)
+	logsSub := w.svc.feed.SubscribeNewLogs(logs)
+
+	w.wg.Add(1)
+	go w.loop(heads, headsSub, logs, logsSub)
+}
+
+func (w *webhookWatcher) Stop() {
+	close(w.quit)
+	w.wg.Wait()
+	w.notifier.Stop()
+}
+
+func (w *webhookWatcher) loop(heads <-chan evmcore.ChainHeadNotify, headsSub notify.Subscription, logs <-chan []*types.Log, logsSub notify.Subscription) {
+	defer w.wg.Done()
+	defer headsSub.Unsubscribe()
+	defer logsSub.Unsubscribe()
+	for {
+		select {
+		case head := <-heads:
+			w.onBlock(head.Block)
+		case ll := <-logs:
+			w.onLogs(ll)
+		case <-headsSub.Err():
+			return
+		case <-logsSub.Err():
+			return
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+func (w *webhookWatcher) onBlock(block *evmcore.EvmBlock) {
+	b := blockNotification{
+		Number:     hexutil.Uint64(block.NumberU64()),
+		Hash:       block.Hash,
+		ParentHash: block.ParentHash,
+		Time:       hexutil.Uint64(block.Time.Unix()),
+		Txs:        len(block.Transactions),
+		GasUsed:    hexutil.Uint64(block.GasUsed),
+	}
+	// finalized blocks are never reverted, so it's a sign of a rollback or a corrupted database
+	if w.prev != nil && (b.Number != w.prev.Number+1 || b.ParentHash != w.prev.Hash) {
+		w.svc.Log.Warn("New block doesn't extend the previous one", "number", b.Number, "previous", w.prev.Number)
+		w.notifier.Notify(webhook.KindReorg, reorgNotification{*w.prev, b})
+	}
+	w.prev = &b
+	w.notifier.Notify(webhook.KindBlock, b)
+
+	if w.notifier.Wants(webhook.KindAddress) {
+		for _, tx := range block.Transactions {
+			if from, err := types.Sender(w.svc.EthAPI.signer, tx); err == nil && w.notifier.Watched(from) {
+				w.notifier.NotifyAddress(from, addressNotification{from, b.Number, tx.Hash(), "from"})
+			}
+			if to := tx.To(); to != nil && w.notifier.Watched(*to) {
+				w.notifier.NotifyAddress(*to, addressNotification{*to, b.Number, tx.Hash(), "to"})
+			}
+		}
+	}
+	if w.notifier.Wants(webhook.KindMissed) {
+		w.checkMissed(idx.Block(b.Number))
+	}
+}
+
+func (w *webhookWatcher) onLogs(logs []*types.Log) {
+	for _, l := range logs {
+		if w.notifier.Watched(l.Address) {
+			w.notifier.NotifyAddress(l.Address, addressNotification{l.Address, hexutil.Uint64(l.BlockNumber), l.TxHash, "log"})
+		}
+	}
+}
+
+// checkMissed notifies once about every validator, which didn't emit events for the configured number of blocks.
+// The validator is notified again only after it's back online.
+func (w *webhookWatcher) checkMissed(n idx.Block) {
+	limit := idx.Block(w.svc.config.Webhooks.MissedBlocks)
+	bs, es := w.svc.store.GetBlockEpochState()
+	for i, state := range bs.ValidatorStates {
+		if i >= int(es.Validators.Len()) {
+			break
+		}
+		id := es.Validators.GetID(idx.Validator(i))
+		if n <= state.LastBlock+limit {
+			delete(w.missed, id)
+			continue
+		}
+		if w.missed[id] {
+			continue
+		}
+		w.missed[id] = true
+		w.notifier.Notify(webhook.KindMissed, missedNotification{id, hexutil.Uint64(state.LastBlock), hexutil.Uint64(n)})
+	}
+}
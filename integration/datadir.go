@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// LayoutVersion is the version of the datadir layout of this release. The datadirs without a manifest
+// are of the version 0, their layout is the same, so they're migrated by writing the manifest.
+// The version is increased once a component of the datadir is moved, along with its relocation by MigrateDatadir.
+const LayoutVersion = 1
+
+// ManifestFile describes the layout of the datadir, it's located in the datadir root.
+const ManifestFile = "datadir.json"
+
+// ErrNewerLayout is returned for a datadir written by a newer release.
+var ErrNewerLayout = errors.New("datadir layout is newer than supported by this release")
+
+// DatadirManifest describes the layout of a datadir.
+type DatadirManifest struct {
+	Version int `json:"version"`
+	// Network is the name of the network rules, empty until the genesis is applied
+	Network string `json:"network,omitempty"`
+}
+
+// ReadManifest reads the manifest of the datadir, it returns nil if the datadir has no manifest.
+func ReadManifest(datadir string) (*DatadirManifest, error) {
+	b, err := os.ReadFile(filepath.Join(datadir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &DatadirManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("malformed datadir manifest: %w", err)
+	}
+	return m, nil
+}
+
+// WriteManifest replaces the manifest of the datadir atomically.
+func WriteManifest(datadir string, m DatadirManifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(datadir, ManifestFile+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(datadir, ManifestFile)); err != nil {
+		return err
+	}
+	return syncDir(datadir)
+}
+
+// DetectLayout returns the manifest of the datadir, the manifest of the version 0 is detected
+// for the initialized datadirs without a manifest. It returns nil for a not initialized datadir.
+func DetectLayout(datadir string) (*DatadirManifest, error) {
+	m, err := ReadManifest(datadir)
+	if err != nil || m != nil {
+		return m, err
+	}
+	if !dirExists(filepath.Join(datadir, "chaindata")) {
+		return nil, nil
+	}
+	return &DatadirManifest{Version: 0}, nil
+}
+
+// NeedsMigration returns true if the datadir of the manifest isn't in the layout of this release.
+func (m *DatadirManifest) NeedsMigration() bool {
+	return m.Version < LayoutVersion
+}
+
+// MigrateDatadir detects the layout of the datadir and migrates it into the layout of this release,
+// then the manifest is written. It returns the manifest of the migrated datadir, or nil for a not
+// initialized datadir.
+func MigrateDatadir(datadir string) (*DatadirManifest, error) {
+	m, err := DetectLayout(datadir)
+	if err != nil || m == nil {
+		return m, err
+	}
+	if m.Version > LayoutVersion {
+		return nil, fmt.Errorf("%w: datadir version is %d, supported version is %d, upgrade the node",
+			ErrNewerLayout, m.Version, LayoutVersion)
+	}
+	if !m.NeedsMigration() {
+		return m, nil
+	}
+
+	log.Warn("Migrating the datadir layout", "datadir", datadir, "from", m.Version, "to", LayoutVersion)
+	migrated := DatadirManifest{
+		Version: LayoutVersion,
+		Network: m.Network,
+	}
+	if err := WriteManifest(datadir, migrated); err != nil {
+		return nil, fmt.Errorf("failed to write the datadir manifest: %w", err)
+	}
+	log.Info("Datadir layout is migrated", "version", LayoutVersion)
+	return &migrated, nil
+}
+
+// SetDatadirNetwork records the network of the datadir into its manifest in the layout of this release.
+func SetDatadirNetwork(datadir string, network string) error {
+	m, err := ReadManifest(datadir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = &DatadirManifest{
+			Version: LayoutVersion,
+		}
+	}
+	if m.Network == network {
+		return nil
+	}
+	m.Network = network
+	return WriteManifest(datadir, *m)
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
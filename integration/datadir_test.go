@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDatadir(t *testing.T) {
+	require := require.New(t)
+
+	t.Run("not initialized", func(t *testing.T) {
+		dir := t.TempDir()
+		m, err := MigrateDatadir(dir)
+		require.NoError(err)
+		require.Nil(m)
+		require.NoFileExists(filepath.Join(dir, ManifestFile))
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(os.Mkdir(filepath.Join(dir, "chaindata"), 0700))
+
+		m, err := DetectLayout(dir)
+		require.NoError(err)
+		require.Equal(0, m.Version)
+		require.True(m.NeedsMigration())
+
+		m, err = MigrateDatadir(dir)
+		require.NoError(err)
+		require.Equal(LayoutVersion, m.Version)
+
+		got, err := ReadManifest(dir)
+		require.NoError(err)
+		require.Equal(m, got)
+		require.False(got.NeedsMigration())
+	})
+
+	t.Run("newer", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(WriteManifest(dir, DatadirManifest{Version: LayoutVersion + 1}))
+		_, err := MigrateDatadir(dir)
+		require.ErrorIs(err, ErrNewerLayout)
+	})
+}
+
+func TestSetDatadirNetwork(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(SetDatadirNetwork(dir, "fakenet"))
+	m, err := ReadManifest(dir)
+	require.NoError(err)
+	require.Equal(DatadirManifest{Version: LayoutVersion, Network: "fakenet"}, *m)
+
+	require.NoError(SetDatadirNetwork(dir, "main"))
+	m, err = ReadManifest(dir)
+	require.NoError(err)
+	require.Equal("main", m.Network)
+}
@@ -3,14 +3,16 @@ package integration
 import (
 	"fmt"
 	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/utils/chaos"
 	"github.com/Fantom-foundation/go-opera/utils/dbutil/dbcounter"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/pebbledb"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
 	"github.com/Fantom-foundation/go-opera/utils/dbutil/threads"
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/dag"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/cachedproducer"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/flaggedproducer"
-	"github.com/Fantom-foundation/lachesis-base/kvdb/pebble"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/skipkeys"
 	"github.com/ethereum/go-ethereum/metrics"
 	"io"
@@ -27,6 +29,12 @@ type DBCacheConfig struct {
 }
 
 func GetRawDbProducer(chaindataDir string, cfg DBCacheConfig) kvdb.IterableDBProducer {
+	rawProducer, _ := getRawDbProducer(chaindataDir, cfg)
+	return rawProducer
+}
+
+// getRawDbProducer returns the raw producer and its Pebble backend, which deletes the ranges of keys.
+func getRawDbProducer(chaindataDir string, cfg DBCacheConfig) (kvdb.IterableDBProducer, *rangedel.Backend) {
 	if chaindataDir == "inmemory" || chaindataDir == "" {
 		chaindataDir, _ = os.MkdirTemp("", "opera-tmp")
 	}
@@ -34,16 +42,17 @@ func GetRawDbProducer(chaindataDir string, cfg DBCacheConfig) kvdb.IterableDBPro
 		return int(cfg.Cache), int(cfg.Fdlimit)
 	}
 
-	rawProducer := dbcounter.Wrap(pebble.NewProducer(chaindataDir, cacher), true)
+	backend := rangedel.NewBackend(pebbledb.NewProducer(chaindataDir, cacher))
+	rawProducer := dbcounter.Wrap(backend, true)
 
 	if metrics.Enabled {
 		rawProducer = WrapDatabaseWithMetrics(rawProducer)
 	}
-	return rawProducer
+	return chaos.WrapProducer(rawProducer), backend
 }
 
 func GetDbProducer(chaindataDir string, cfg DBCacheConfig) (kvdb.FullDBProducer, error) {
-	rawProducer := GetRawDbProducer(chaindataDir, cfg)
+	rawProducer, backend := getRawDbProducer(chaindataDir, cfg)
 	scopedProducer := flaggedproducer.Wrap(rawProducer, FlushIDKey) // pebble-flg
 	_, err := scopedProducer.Initialize(rawProducer.Names(), nil)
 	if err != nil {
@@ -51,7 +60,7 @@ func GetDbProducer(chaindataDir string, cfg DBCacheConfig) (kvdb.FullDBProducer,
 	}
 	cachedProducer := cachedproducer.WrapAll(scopedProducer)
 	skippingProducer := skipkeys.WrapAllProducer(cachedProducer, MetadataPrefix)
-	return threads.CountedFullDBProducer(skippingProducer), nil
+	return rangedel.WrapProducer(threads.CountedFullDBProducer(skippingProducer), backend, MetadataPrefix), nil
 }
 
 func isEmpty(dir string) bool {
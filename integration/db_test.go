@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
+)
+
+func TestDbProducerDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	dbs, err := GetDbProducer(dir, DBCacheConfig{Cache: 16, Fdlimit: 16})
+	require.NoError(err)
+	db, err := dbs.OpenDB("gossip")
+	require.NoError(err)
+	for _, k := range []string{"a1", "a2", "b1"} {
+		require.NoError(db.Put([]byte(k), []byte{1}))
+	}
+	require.NoError(dbs.Flush([]byte{1}))
+
+	// the range is deleted by the range tombstone of Pebble
+	deleted, err := rangedel.DeleteRange(db, []byte("a"), []byte("b"))
+	require.NoError(err)
+	require.Equal(0, deleted)
+	require.NoError(dbs.Flush([]byte{2}))
+	require.NoError(db.Close())
+	require.NoError(dbs.Close())
+
+	dbs, err = GetDbProducer(dir, DBCacheConfig{Cache: 16, Fdlimit: 16})
+	require.NoError(err)
+	defer dbs.Close()
+	db, err = dbs.OpenDB("gossip")
+	require.NoError(err)
+	defer db.Close()
+	var keys []string
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	require.Equal([]string{"b1"}, keys)
+}
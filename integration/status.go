@@ -1,11 +1,55 @@
 package integration
 
 import (
+	"os"
 	"path"
 
 	"github.com/Fantom-foundation/go-opera/utils"
 )
 
+// dirtyFlagFile marks the databases as being used by a running node. It's created once the databases are opened
+// and removed only after they're committed and closed, so it's left behind by an unclean shutdown.
+const dirtyFlagFile = "dirty"
+
 func isInterrupted(chaindataDir string) bool {
 	return utils.FileExists(path.Join(chaindataDir, "unfinished"))
 }
+
+// IsDirty returns true if the node using the databases wasn't shut down cleanly.
+func IsDirty(chaindataDir string) bool {
+	return utils.FileExists(path.Join(chaindataDir, dirtyFlagFile))
+}
+
+// SetDirty marks the databases as being in use. The flag is synced to the disk before any change of the databases.
+func SetDirty(chaindataDir string) error {
+	f, err := os.Create(path.Join(chaindataDir, dirtyFlagFile))
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return syncDir(chaindataDir)
+}
+
+// SetClean removes the dirty flag. It must be called only after the databases are committed and closed.
+func SetClean(chaindataDir string) error {
+	if err := os.Remove(path.Join(chaindataDir, dirtyFlagFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return syncDir(chaindataDir)
+}
+
+// syncDir makes the creation or removal of a file in the directory durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
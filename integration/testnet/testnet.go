@@ -0,0 +1,384 @@
+// Package testnet simulates a network of validators in the process, each of which runs its own node.
+// The nodes exchange the events by a simulated network with latencies and partitions, which runs
+// on a simulated clock, so the network schedule is reproducible by a seed and no real time is waited.
+package testnet
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/abft"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+	"github.com/Fantom-foundation/go-opera/integration"
+	"github.com/Fantom-foundation/go-opera/integration/makefakegenesis"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/utils"
+	"github.com/Fantom-foundation/go-opera/utils/adapters/vecmt2dagidx"
+	"github.com/Fantom-foundation/go-opera/valkeystore"
+	"github.com/Fantom-foundation/go-opera/vecmt"
+)
+
+const (
+	genesisBalance   = 1e18
+	genesisStake     = 2 * 4e6
+	maxEpochDuration = time.Hour
+)
+
+// Config describes a simulated network of validators.
+type Config struct {
+	Validators idx.Validator
+	// Seed drives the emission order and the delivery latencies, so the network schedule is reproducible.
+	Seed int64
+	// Step is the simulated time between the emission rounds, every validator emits once per round.
+	Step time.Duration
+	// MinLatency and MaxLatency bound the simulated delays of the events delivery between nodes.
+	MinLatency, MaxLatency time.Duration
+}
+
+// Node is a node of the simulated network, running a single validator.
+type Node struct {
+	*gossip.Service
+	Store *gossip.Store
+
+	emitter *emitter.Emitter
+	txPool  *evmcore.TxPool
+	// orphans are the delivered events waiting for their parents
+	orphans []*inter.EventPayload
+}
+
+// delivery is an event in flight between nodes.
+type delivery struct {
+	at       time.Time
+	seq      uint64 // sending order, to deliver the simultaneous events deterministically
+	from, to int
+	e        *inter.EventPayload
+}
+
+// Network connects the nodes by a simulated network with latencies and partitions.
+// The network runs on a simulated clock, which is advanced by Step, so no real time is waited.
+type Network struct {
+	Nodes []*Node
+	// Genesis is the last block of the genesis
+	Genesis idx.Block
+
+	cfg Config
+	rnd *rand.Rand
+	now time.Time
+	seq uint64
+
+	latencies map[[2]int][2]time.Duration // per link latencies overriding the default ones
+	groups    []int                       // partition group of each node
+	inflight  []*delivery
+	held      []*delivery // deliveries between the partitioned nodes, released once healed
+	// trace records the delivered events, it's reproduced by the same seed
+	trace []string
+}
+
+type networkExternal struct {
+	emitter.External
+	net  *Network
+	from int
+}
+
+func (em networkExternal) Build(e *inter.MutableEventPayload, onIndexed func()) error {
+	e.SetCreationTime(inter.Timestamp(em.net.now.UnixNano()))
+	return em.External.Build(e, onIndexed)
+}
+
+func (em networkExternal) Broadcast(e *inter.EventPayload) {
+	em.net.broadcast(em.from, e)
+}
+
+func panics(name string) func(error) {
+	return func(err error) {
+		log.Crit(fmt.Sprintf("%s error", name), "err", err)
+	}
+}
+
+// New starts the nodes of the simulated network, the stores of the nodes are kept in memory.
+func New(tb testing.TB, cfg Config) (*Network, error) {
+	if cfg.Step == 0 {
+		cfg.Step = 100 * time.Millisecond
+	}
+	if cfg.MaxLatency < cfg.MinLatency {
+		cfg.MaxLatency = cfg.MinLatency
+	}
+	rules := opera.FakeNetRules()
+	rules.Epochs.MaxEpochDuration = inter.Timestamp(maxEpochDuration)
+	rules.Blocks.MaxEmptyBlockSkipPeriod = 0
+
+	net := &Network{
+		cfg:       cfg,
+		rnd:       rand.New(rand.NewSource(cfg.Seed)),
+		latencies: make(map[[2]int][2]time.Duration),
+		groups:    make([]int, cfg.Validators),
+	}
+	for i := idx.Validator(0); i < cfg.Validators; i++ {
+		node, err := net.newNode(tb, i, rules)
+		if err != nil {
+			net.Close()
+			return nil, fmt.Errorf("failed to start node %d: %w", i, err)
+		}
+		net.Nodes = append(net.Nodes, node)
+	}
+	return net, nil
+}
+
+func (net *Network) newNode(tb testing.TB, i idx.Validator, rules opera.Rules) (*Node, error) {
+	genStore := makefakegenesis.FakeGenesisStoreWithRulesAndStart(net.cfg.Validators, utils.ToFtm(genesisBalance), utils.ToFtm(genesisStake), rules, 1, 2)
+	defer genStore.Close()
+	store, err := gossip.NewMemStore(tb)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.ApplyGenesis(genStore.Genesis()); err != nil {
+		return nil, err
+	}
+	net.now = store.GetGenesisTime().Time()
+	net.Genesis = store.GetLatestBlockIndex()
+
+	cdb := abft.NewMemStore()
+	if err := cdb.ApplyGenesis(&abft.Genesis{
+		Epoch:      store.GetEpoch(),
+		Validators: store.GetValidators(),
+	}); err != nil {
+		return nil, err
+	}
+	vecClock := vecmt.NewIndex(panics("Vector clock"), vecmt.LiteConfig())
+	engine := abft.NewLachesis(cdb, &integration.GossipStoreAdapter{Store: store}, vecmt2dagidx.Wrap(vecClock), panics("Lachesis"), abft.LiteConfig())
+
+	node := &Node{Store: store}
+	txPoolCfg := evmcore.DefaultTxPoolConfig
+	txPoolCfg.Journal = ""
+	svc, err := gossip.NewStandaloneService(gossip.DefaultConfig(cachescale.Identity), store, gossip.DefaultBlockProc(), engine, vecClock, func(reader evmcore.StateReader) gossip.TxPool {
+		node.txPool = evmcore.NewTxPool(txPoolCfg, reader.Config(), reader)
+		return node.txPool
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := engine.Bootstrap(svc.GetConsensusCallbacks()); err != nil {
+		return nil, err
+	}
+	node.Service = svc
+
+	vid := store.GetValidators().GetID(i)
+	pubkey := store.GetEpochState().ValidatorProfiles[vid].PubKey
+	valKeystore := valkeystore.NewDefaultMemKeystore()
+	if err := valKeystore.Add(pubkey, crypto.FromECDSA(makefakegenesis.FakeKey(vid)), validatorpk.FakePassword); err != nil {
+		return nil, err
+	}
+	if err := valKeystore.Unlock(pubkey, validatorpk.FakePassword); err != nil {
+		return nil, err
+	}
+
+	emCfg := emitter.DefaultConfig()
+	emCfg.Validator = emitter.ValidatorConfig{
+		ID:     vid,
+		PubKey: pubkey,
+	}
+	emCfg.EmitIntervals = emitter.EmitIntervals{}
+	emCfg.MaxParents = idx.Event(net.cfg.Validators/2 + 1)
+	world := svc.EmitterWorld(valkeystore.NewSigner(valKeystore))
+	world.External = networkExternal{world.External, net, int(i)}
+	node.emitter = emitter.NewEmitter(emCfg, world)
+	svc.RegisterEmitter(node.emitter)
+	svc.StartStandalone()
+	return node, nil
+}
+
+// Close stops the nodes.
+func (net *Network) Close() {
+	for _, node := range net.Nodes {
+		if err := node.StopStandalone(); err != nil {
+			log.Error("Failed to stop node", "err", err)
+		}
+		node.txPool.Stop()
+	}
+}
+
+// SetLatency overrides the delivery latencies of the events sent from one node to another.
+func (net *Network) SetLatency(from, to int, min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	net.latencies[[2]int{from, to}] = [2]time.Duration{min, max}
+}
+
+// Partition splits the nodes into the given groups, the nodes of different groups don't exchange events.
+// The nodes which aren't listed form one more group.
+// The events sent across the partition are delivered once it's healed, as the nodes would sync them.
+func (net *Network) Partition(groups ...[]int) {
+	for i := range net.groups {
+		net.groups[i] = 0
+	}
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			net.groups[i] = g + 1
+		}
+	}
+}
+
+// Heal removes the partition, the held events are sent again.
+func (net *Network) Heal() {
+	net.Partition()
+	held := net.held
+	net.held = nil
+	for _, d := range held {
+		net.send(d.from, d.to, d.e)
+	}
+}
+
+func (net *Network) broadcast(from int, e *inter.EventPayload) {
+	for to := range net.Nodes {
+		if to != from {
+			net.send(from, to, e)
+		}
+	}
+}
+
+func (net *Network) send(from, to int, e *inter.EventPayload) {
+	latency, ok := net.latencies[[2]int{from, to}]
+	if !ok {
+		latency = [2]time.Duration{net.cfg.MinLatency, net.cfg.MaxLatency}
+	}
+	delay := latency[0]
+	if spread := latency[1] - latency[0]; spread > 0 {
+		delay += time.Duration(net.rnd.Int63n(int64(spread) + 1))
+	}
+	net.seq++
+	net.inflight = append(net.inflight, &delivery{
+		at:   net.now.Add(delay),
+		seq:  net.seq,
+		from: from,
+		to:   to,
+		e:    e,
+	})
+}
+
+// Step runs an emission round in a pseudo-random order of the validators, delivers the events
+// whose latency has passed and advances the simulated clock.
+func (net *Network) Step() error {
+	for _, i := range net.rnd.Perm(len(net.Nodes)) {
+		if _, err := net.Nodes[i].emitter.EmitEvent(); err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(net.inflight, func(i, j int) bool {
+		a, b := net.inflight[i], net.inflight[j]
+		if !a.at.Equal(b.at) {
+			return a.at.Before(b.at)
+		}
+		return a.seq < b.seq
+	})
+	n := sort.Search(len(net.inflight), func(i int) bool {
+		return net.inflight[i].at.After(net.now)
+	})
+	due := net.inflight[:n]
+	net.inflight = append([]*delivery(nil), net.inflight[n:]...)
+	for _, d := range due {
+		if net.groups[d.from] != net.groups[d.to] {
+			net.held = append(net.held, d)
+			continue
+		}
+		net.trace = append(net.trace, fmt.Sprintf("%d %d->%d %d:%d", d.at.UnixNano(), d.from, d.to, d.e.Creator(), d.e.Seq()))
+		if err := net.Nodes[d.to].deliver(d.e); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range net.Nodes {
+		node.WaitBlockEnd()
+	}
+	net.now = net.now.Add(net.cfg.Step)
+	return nil
+}
+
+// RunUntil steps the network until the condition is met, at most the given number of steps.
+func (net *Network) RunUntil(stop func() bool, maxSteps int) error {
+	for i := 0; !stop(); i++ {
+		if i == maxSteps {
+			return fmt.Errorf("condition isn't met in %d steps", maxSteps)
+		}
+		if err := net.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Trace returns the delivered events, the trace is reproduced by the same seed.
+func (net *Network) Trace() []string {
+	return net.trace
+}
+
+// deliver connects the received event, or keeps it until its parents are received.
+func (node *Node) deliver(e *inter.EventPayload) error {
+	node.orphans = append(node.orphans, e)
+	for connected := true; connected; {
+		connected = false
+		orphans := node.orphans[:0]
+		for _, e := range node.orphans {
+			ok, err := node.ConnectEvent(e)
+			if err != nil {
+				return err
+			}
+			if ok {
+				connected = true
+			} else {
+				orphans = append(orphans, e)
+			}
+		}
+		node.orphans = orphans
+	}
+	return nil
+}
+
+// LatestBlocks returns the latest block of each node.
+func (net *Network) LatestBlocks() []idx.Block {
+	blocks := make([]idx.Block, len(net.Nodes))
+	for i, node := range net.Nodes {
+		blocks[i] = node.Store.GetLatestBlockIndex()
+	}
+	return blocks
+}
+
+// CheckSameBlocks checks that the nodes decided the same blocks, up to the lowest block decided by all of them.
+// It returns the lowest block.
+func (net *Network) CheckSameBlocks() (idx.Block, error) {
+	lowest := net.LatestBlocks()[0]
+	for _, n := range net.LatestBlocks() {
+		if n < lowest {
+			lowest = n
+		}
+	}
+	for n := net.Genesis + 1; n <= lowest; n++ {
+		expect := net.Nodes[0].Store.GetBlock(n)
+		if expect == nil {
+			return lowest, fmt.Errorf("node 0 misses block %d", n)
+		}
+		for i, node := range net.Nodes[1:] {
+			got := node.Store.GetBlock(n)
+			if got == nil {
+				return lowest, fmt.Errorf("node %d misses block %d", i+1, n)
+			}
+			if got.Atropos != expect.Atropos {
+				return lowest, fmt.Errorf("node %d decided block %d by atropos %s, expected %s", i+1, n, got.Atropos, expect.Atropos)
+			}
+		}
+	}
+	return lowest, nil
+}
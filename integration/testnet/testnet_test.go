@@ -0,0 +1,99 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func newTestNetwork(t *testing.T, cfg Config) *Network {
+	net, err := New(t, cfg)
+	require.NoError(t, err)
+	t.Cleanup(net.Close)
+	return net
+}
+
+func TestNetworkConsensus(t *testing.T) {
+	logger.SetTestMode(t)
+
+	net := newTestNetwork(t, Config{
+		Validators: 4,
+		Seed:       1,
+		MinLatency: 50 * time.Millisecond,
+		MaxLatency: 400 * time.Millisecond,
+	})
+	// a slow link
+	net.SetLatency(0, 3, time.Second, 2*time.Second)
+
+	start := net.LatestBlocks()[0]
+	require.NoError(t, net.RunUntil(func() bool {
+		for _, n := range net.LatestBlocks() {
+			if n < start+5 {
+				return false
+			}
+		}
+		return true
+	}, 1000))
+	lowest, err := net.CheckSameBlocks()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, lowest, start+5)
+}
+
+func TestNetworkPartition(t *testing.T) {
+	logger.SetTestMode(t)
+
+	net := newTestNetwork(t, Config{
+		Validators: 4,
+		Seed:       2,
+		MinLatency: 10 * time.Millisecond,
+		MaxLatency: 200 * time.Millisecond,
+	})
+
+	// no half of the validators has the quorum, so the blocks aren't decided while partitioned
+	net.Partition([]int{0, 1}, []int{2, 3})
+	for i := 0; i < 20; i++ {
+		require.NoError(t, net.Step())
+	}
+	partitioned := net.LatestBlocks()
+	for i := 0; i < 50; i++ {
+		require.NoError(t, net.Step())
+	}
+	require.Equal(t, partitioned, net.LatestBlocks())
+
+	net.Heal()
+	require.NoError(t, net.RunUntil(func() bool {
+		for i, n := range net.LatestBlocks() {
+			if n < partitioned[i]+3 {
+				return false
+			}
+		}
+		return true
+	}, 1000))
+	_, err := net.CheckSameBlocks()
+	require.NoError(t, err)
+}
+
+func TestNetworkDeterministic(t *testing.T) {
+	logger.SetTestMode(t)
+
+	run := func(seed int64) []string {
+		net, err := New(t, Config{
+			Validators: 3,
+			Seed:       seed,
+			MaxLatency: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		defer net.Close()
+		for i := 0; i < 30; i++ {
+			require.NoError(t, net.Step())
+		}
+		return net.Trace()
+	}
+	trace := run(3)
+	require.NotEmpty(t, trace)
+	require.Equal(t, trace, run(3))
+	require.NotEqual(t, trace, run(4))
+}
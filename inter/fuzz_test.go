@@ -0,0 +1,67 @@
+package inter
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-opera/utils/cser"
+)
+
+// FuzzEventPayloadDecode checks that malformed events received from peers are rejected with an error,
+// and that a successfully decoded event survives the re-encoding.
+func FuzzEventPayloadDecode(f *testing.F) {
+	for ver := uint8(0); ver <= MaxSerializationVersion; ver++ {
+		e := emptyEvent(ver)
+		b, err := rlp.EncodeToBytes(&e)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var e EventPayload
+		if err := rlp.DecodeBytes(data, &e); err != nil {
+			return
+		}
+		b, err := rlp.EncodeToBytes(&e)
+		if err != nil {
+			t.Fatalf("failed to encode decoded event: %v", err)
+		}
+		var decoded EventPayload
+		if err := rlp.DecodeBytes(b, &decoded); err != nil {
+			t.Fatalf("failed to decode re-encoded event: %v", err)
+		}
+		if decoded.ID() != e.ID() {
+			t.Fatalf("event ID changed after re-encoding: %s != %s", decoded.ID(), e.ID())
+		}
+	})
+}
+
+// FuzzTransactionUnmarshalCSER checks that malformed transactions of legacy events are rejected with an error.
+func FuzzTransactionUnmarshalCSER(f *testing.F) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, Data: []byte{1, 2, 3}})
+	b, err := cser.MarshalBinaryAdapter(func(w *cser.Writer) error {
+		return TransactionMarshalCSER(w, tx)
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(b)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var decoded *types.Transaction
+		err := cser.UnmarshalBinaryAdapter(data, func(r *cser.Reader) (err error) {
+			decoded, err = TransactionUnmarshalCSER(r)
+			return err
+		})
+		if err != nil {
+			return
+		}
+		if _, err := cser.MarshalBinaryAdapter(func(w *cser.Writer) error {
+			return TransactionMarshalCSER(w, decoded)
+		}); err != nil {
+			t.Fatalf("failed to encode decoded transaction: %v", err)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package iblockproc
+
+import "math/big"
+
+// FeeStats is the accounting of the transactions fees of a block or an epoch.
+// The fees are distributed between validators rewards, treasury and burning by the SFC contract
+// on epoch sealing, using the fees originated by validators as the base of the rewards.
+type FeeStats struct {
+	// Fees is a total of the fees paid by transactions
+	Fees *big.Int
+	// BaseFees is a part of the fees paid at the base fee rate
+	BaseFees *big.Int
+	// Originated is a part of the fees paid by transactions originated by validators
+	Originated *big.Int
+}
+
+// NewFeeStats returns zero FeeStats.
+func NewFeeStats() FeeStats {
+	return FeeStats{
+		Fees:       new(big.Int),
+		BaseFees:   new(big.Int),
+		Originated: new(big.Int),
+	}
+}
+
+// Add adds the other stats to the stats.
+func (s FeeStats) Add(other FeeStats) {
+	s.Fees.Add(s.Fees, other.Fees)
+	s.BaseFees.Add(s.BaseFees, other.BaseFees)
+	s.Originated.Add(s.Originated, other.Originated)
+}
+
+// Sub subtracts the other stats from the stats.
+func (s FeeStats) Sub(other FeeStats) {
+	s.Fees.Sub(s.Fees, other.Fees)
+	s.BaseFees.Sub(s.BaseFees, other.BaseFees)
+	s.Originated.Sub(s.Originated, other.Originated)
+}
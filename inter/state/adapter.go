@@ -2,7 +2,6 @@ package state
 
 import (
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"math/big"
@@ -16,7 +15,7 @@ type StateDB interface {
 	TxIndex() int
 	GetProof(addr common.Address) ([][]byte, error)
 	GetStorageProof(a common.Address, key common.Hash) ([][]byte, error)
-	StorageTrie(addr common.Address) state.Trie
+	GetStorageRoot(addr common.Address) (common.Hash, error)
 	SetBalance(addr common.Address, amount *big.Int)
 	SetCode(addr common.Address, code []byte)
 	SetStorage(addr common.Address, storage map[common.Hash]common.Hash)
@@ -29,3 +28,12 @@ type StateDB interface {
 	EndBlock(number uint64)
 	Release()
 }
+
+// PartialStateDB is a StateDB, which keeps only a part of the state,
+// so the EVM execution over it would read the missing data.
+type PartialStateDB interface {
+	StateDB
+
+	// ExecutionError returns the reason the EVM execution over the state is refused.
+	ExecutionError() error
+}
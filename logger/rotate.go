@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the time format of the rotated log files names, it's sortable and valid in file names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationConfig is the rotation and retention policy of a log file.
+type RotationConfig struct {
+	// MaxSize is the size in bytes, after which the file is rotated. Zero disables the size based rotation.
+	MaxSize int64
+	// Interval is the time after which the file is rotated. Zero disables the time based rotation.
+	Interval time.Duration
+	// MaxBackups is the number of the rotated files to keep. Zero keeps all of them.
+	MaxBackups int
+	// MaxAge is the time to keep the rotated files for. Zero keeps them regardless of age.
+	MaxAge time.Duration
+	// Compress enables gzip compression of the rotated files.
+	Compress bool
+}
+
+// RotatingFile is a log file, which is rotated according to the RotationConfig.
+// The rotated files are named after the file with the rotation time, e.g. sonic-2006-01-02T15-04-05.000.log,
+// and are placed next to it. It's safe for concurrent use.
+type RotatingFile struct {
+	path string
+	cfg  RotationConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// the rotated files are compressed and cleaned up in background, one at a time
+	background sync.WaitGroup
+	cleanupMu  sync.Mutex
+
+	now func() time.Time
+}
+
+// OpenRotatingFile opens the log file for appending, creating it if needed.
+func OpenRotatingFile(path string, cfg RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path: path,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// Write writes the log record into the file, rotating the file beforehand if it's due.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.due(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// due returns true if the file has to be rotated before the next record is written.
+func (f *RotatingFile) due(next int64) bool {
+	if f.size == 0 {
+		// never leave an empty file behind
+		return false
+	}
+	if f.cfg.MaxSize > 0 && f.size+next > f.cfg.MaxSize {
+		return true
+	}
+	return f.cfg.Interval > 0 && f.now().Sub(f.openedAt) >= f.cfg.Interval
+}
+
+// Rotate rotates the file regardless of the policy, e.g. on an operator's request.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.background.Add(1)
+	go func() {
+		defer f.background.Done()
+		f.cleanupMu.Lock()
+		defer f.cleanupMu.Unlock()
+		if f.cfg.Compress {
+			if err := compressFile(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to compress rotated log file %s: %v\n", backup, err)
+			}
+		}
+		f.removeExpired()
+	}()
+	return nil
+}
+
+func (f *RotatingFile) splitPath() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	name := filepath.Base(f.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backupName returns the name of the file rotated at the given time, which isn't taken by another rotated file.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.splitPath()
+	for ; ; t = t.Add(time.Millisecond) {
+		name := filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+type logBackup struct {
+	path string
+	time time.Time
+}
+
+// backups returns the rotated files, the newest first.
+func (f *RotatingFile) backups() ([]logBackup, error) {
+	dir, prefix, ext := f.splitPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var res []logBackup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		res = append(res, logBackup{filepath.Join(dir, name), t})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].time.After(res[j].time)
+	})
+	return res, nil
+}
+
+// removeExpired removes the rotated files beyond the retention policy.
+func (f *RotatingFile) removeExpired() {
+	if f.cfg.MaxBackups == 0 && f.cfg.MaxAge == 0 {
+		return
+	}
+	backups, err := f.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list rotated log files: %v\n", err)
+		return
+	}
+	var cutoff time.Time
+	if f.cfg.MaxAge > 0 {
+		cutoff = f.now().Add(-f.cfg.MaxAge)
+	}
+	for i, b := range backups {
+		if (f.cfg.MaxBackups > 0 && i >= f.cfg.MaxBackups) || b.time.Before(cutoff) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Failed to remove rotated log file %s: %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+// Close closes the file and waits until the rotated files are compressed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+	f.background.Wait()
+	return err
+}
+
+// compressFile replaces the file with its gzipped copy.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sonic.log")
+	f, err := OpenRotatingFile(path, RotationConfig{
+		MaxSize:    10,
+		Interval:   time.Hour,
+		MaxBackups: 2,
+	})
+	require.NoError(err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time {
+		return now
+	}
+	write := func(s string) {
+		_, err := f.Write([]byte(s))
+		require.NoError(err)
+	}
+	read := func(path string) string {
+		b, err := os.ReadFile(path)
+		require.NoError(err)
+		return string(b)
+	}
+
+	// rotated by size
+	write("12345")
+	write("67890")
+	write("abc")
+	require.Equal("abc", read(path))
+	require.Equal("1234567890", read(filepath.Join(dir, "sonic-2024-01-01T00-00-00.000.log")))
+
+	// rotated within the same millisecond
+	write("defghijk")
+	require.Equal("defghijk", read(path))
+	require.Equal("abc", read(filepath.Join(dir, "sonic-2024-01-01T00-00-00.001.log")))
+
+	// rotated by time
+	now = now.Add(time.Hour)
+	write("l")
+	require.NoError(f.Close())
+	require.Equal("l", read(path))
+
+	// only the newest rotated files are kept
+	files, err := filepath.Glob(filepath.Join(dir, "sonic-*.log"))
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dir, "sonic-2024-01-01T00-00-00.001.log"),
+		filepath.Join(dir, "sonic-2024-01-01T01-00-00.000.log"),
+	}, files)
+
+	_, err = f.Write([]byte("closed"))
+	require.ErrorIs(err, os.ErrClosed)
+}
+
+func TestRotatingFileCompressAndMaxAge(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sonic.log")
+	// a rotated file left by a previous run
+	old := filepath.Join(dir, "sonic-2023-01-01T00-00-00.000.log")
+	require.NoError(os.WriteFile(old, []byte("old"), 0600))
+	// unrelated files are never removed
+	other := filepath.Join(dir, "other.log")
+	require.NoError(os.WriteFile(other, []byte("other"), 0600))
+
+	f, err := OpenRotatingFile(path, RotationConfig{
+		MaxAge:   24 * time.Hour,
+		Compress: true,
+	})
+	require.NoError(err)
+	f.now = func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	_, err = f.Write([]byte("record"))
+	require.NoError(err)
+	require.NoError(f.Rotate())
+	require.NoError(f.Close())
+
+	require.NoFileExists(old)
+	require.FileExists(other)
+	require.NoFileExists(filepath.Join(dir, "sonic-2024-01-01T00-00-00.000.log"))
+
+	gz, err := os.Open(filepath.Join(dir, "sonic-2024-01-01T00-00-00.000.log.gz"))
+	require.NoError(err)
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	require.NoError(err)
+	b, err := io.ReadAll(zr)
+	require.NoError(err)
+	require.Equal("record", string(b))
+}
@@ -20,6 +20,14 @@ func (r Rules) EncodeRLP(w io.Writer) error {
 	rType := uint8(0)
 	if r.Upgrades != (Upgrades{}) {
 		rType = 1
+	}
+	if r.Precompiles != (PrecompilesRules{}) {
+		rType = 2
+	}
+	if r.Contracts != (ContractsRules{}) {
+		rType = 3
+	}
+	if rType > 0 {
 		_, err := w.Write([]byte{rType})
 		if err != nil {
 			return err
@@ -38,6 +46,18 @@ func (r Rules) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	if rType > 1 {
+		err := rlp.Encode(w, &r.Precompiles)
+		if err != nil {
+			return err
+		}
+	}
+	if rType > 2 {
+		err := rlp.Encode(w, &r.Contracts)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -58,7 +78,7 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 			return errors.New("empty typed")
 		}
 		rType = b[0]
-		if rType == 0 || rType > 1 {
+		if rType == 0 || rType > 3 {
 			return errors.New("unknown type")
 		}
 	}
@@ -76,6 +96,18 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 			return err
 		}
 	}
+	if rType >= 2 {
+		err = s.Decode(&r.Precompiles)
+		if err != nil {
+			return err
+		}
+	}
+	if rType >= 3 {
+		err = s.Decode(&r.Contracts)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -93,6 +125,12 @@ func (u Upgrades) EncodeRLP(w io.Writer) error {
 	if u.Llr {
 		bitmap.V |= llrBit
 	}
+	if u.Eof {
+		bitmap.V |= eofBit
+	}
+	if u.Bls {
+		bitmap.V |= blsBit
+	}
 	return rlp.Encode(w, &bitmap)
 }
 
@@ -108,6 +146,8 @@ func (u *Upgrades) DecodeRLP(s *rlp.Stream) error {
 	u.Berlin = (bitmap.V & berlinBit) != 0
 	u.London = (bitmap.V & londonBit) != 0
 	u.Llr = (bitmap.V & llrBit) != 0
+	u.Eof = (bitmap.V & eofBit) != 0
+	u.Bls = (bitmap.V & blsBit) != 0
 	return nil
 }
 
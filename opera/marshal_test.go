@@ -79,6 +79,55 @@ func TestRulesLondonRLP(t *testing.T) {
 	require.True(decodedRules.Upgrades.London)
 }
 
+func TestRulesEofRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Eof = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Eof)
+	require.False(decodedRules.Upgrades.London)
+}
+
+func TestRulesPrecompilesRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Bls = true
+	rules.Precompiles.Bls12381.G1AddGas = 500
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Bls)
+	require.Equal(rules.Precompiles, decodedRules.Precompiles)
+}
+
+func TestRulesContractsRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Contracts.MaxInitCodeSize = 49152
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.Equal(rules.Contracts, decodedRules.Contracts)
+	require.Equal(PrecompilesRules{}, decodedRules.Precompiles)
+}
+
 func TestRulesBerlinCompatibilityRLP(t *testing.T) {
 	require := require.New(t)
 
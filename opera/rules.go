@@ -22,6 +22,8 @@ const (
 	berlinBit              = 1 << 0
 	londonBit              = 1 << 1
 	llrBit                 = 1 << 2
+	eofBit                 = 1 << 3
+	blsBit                 = 1 << 4
 )
 
 var DefaultVMConfig = vm.Config{
@@ -48,6 +50,12 @@ type RulesRLP struct {
 	Economy EconomyRules
 
 	Upgrades Upgrades `rlp:"-"`
+
+	// Precompiled contracts options
+	Precompiles PrecompilesRules `rlp:"-"`
+
+	// Contracts deployment options
+	Contracts ContractsRules `rlp:"-"`
 }
 
 // Rules describes opera net.
@@ -112,10 +120,40 @@ type BlocksRules struct {
 	MaxEmptyBlockSkipPeriod inter.Timestamp
 }
 
+// ContractsRules contains limits of the contracts deployment.
+// The deployed code size is limited by the EVM (EIP-170) at execution.
+type ContractsRules struct {
+	// MaxInitCodeSize limits size of the init code of contract creation transactions (EIP-3860).
+	// Zero disables the limit.
+	MaxInitCodeSize uint64
+}
+
+// PrecompilesRules contains options of the optional precompiled contracts.
+type PrecompilesRules struct {
+	Bls12381 Bls12381GasRules
+}
+
+// Bls12381GasRules is the gas schedule of the EIP-2537 precompiled contracts.
+// Zero values fall back to the EIP-2537 gas schedule.
+type Bls12381GasRules struct {
+	G1AddGas          uint64
+	G1MulGas          uint64
+	G2AddGas          uint64
+	G2MulGas          uint64
+	PairingBaseGas    uint64
+	PairingPerPairGas uint64
+	MapG1Gas          uint64
+	MapG2Gas          uint64
+}
+
 type Upgrades struct {
 	Berlin bool
 	London bool
 	Llr    bool
+	// Eof enables validation of EVM Object Format (EOF) v1 contracts on deployment.
+	Eof bool
+	// Bls enables the EIP-2537 BLS12-381 precompiled contracts.
+	Bls bool
 }
 
 type UpgradeHeight struct {
@@ -123,10 +161,16 @@ type UpgradeHeight struct {
 	Height   idx.Block
 }
 
+// ChainID returns the EIP-155 chain ID. It's the single source of the chain identity
+// reported by eth_chainId, net_version and the P2P handshake.
+func (r Rules) ChainID() *big.Int {
+	return new(big.Int).SetUint64(r.NetworkID)
+}
+
 // EvmChainConfig returns ChainConfig for transactions signing and execution
 func (r Rules) EvmChainConfig(hh []UpgradeHeight) *ethparams.ChainConfig {
 	cfg := *ethparams.AllEthashProtocolChanges
-	cfg.ChainID = new(big.Int).SetUint64(r.NetworkID)
+	cfg.ChainID = r.ChainID()
 	cfg.BerlinBlock = nil
 	cfg.LondonBlock = nil
 	for i, h := range hh {
@@ -0,0 +1,58 @@
+package opera
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ChainSpecVersion is the version of the chain spec format produced by EncodeChainSpec.
+const ChainSpecVersion = 1
+
+// ChainSpec is a standalone description of a network: the full rule set and the upgrade heights.
+type ChainSpec struct {
+	Version        uint64
+	Rules          Rules
+	UpgradeHeights []UpgradeHeight `json:",omitempty"`
+}
+
+// EncodeChainSpec serializes the chain spec as indented JSON.
+func EncodeChainSpec(spec ChainSpec) ([]byte, error) {
+	spec.Version = ChainSpecVersion
+	return json.MarshalIndent(&spec, "", "  ")
+}
+
+// DecodeChainSpec parses the chain spec. Unknown fields and unsupported versions are rejected,
+// so that a spec produced by a newer version isn't silently truncated.
+func DecodeChainSpec(data []byte) (*ChainSpec, error) {
+	var spec ChainSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, err
+	}
+	if spec.Version == 0 || spec.Version > ChainSpecVersion {
+		return nil, fmt.Errorf("unsupported chain spec version %d", spec.Version)
+	}
+	if spec.Rules.Economy.MinGasPrice == nil {
+		return nil, fmt.Errorf("chain spec misses Economy.MinGasPrice")
+	}
+	return &spec, nil
+}
+
+// GenesisRules returns the rules to launch a new network of the spec with. The network starts
+// with the upgrades of the latest upgrade height of the spec, as its blocks can't be replayed
+// from the earlier heights.
+func (spec ChainSpec) GenesisRules() Rules {
+	rules := spec.Rules.Copy()
+	var latest *UpgradeHeight
+	for i, h := range spec.UpgradeHeights {
+		if latest == nil || h.Height >= latest.Height {
+			latest = &spec.UpgradeHeights[i]
+		}
+	}
+	if latest != nil {
+		rules.Upgrades = latest.Upgrades
+	}
+	return rules
+}
@@ -0,0 +1,50 @@
+package opera
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainSpec(t *testing.T) {
+	require := require.New(t)
+
+	spec := ChainSpec{
+		Rules: FakeNetRules(),
+		UpgradeHeights: []UpgradeHeight{
+			{Upgrades: Upgrades{Berlin: true}, Height: 1},
+			{Upgrades: Upgrades{Berlin: true, London: true, Llr: true}, Height: 100},
+		},
+	}
+	b, err := EncodeChainSpec(spec)
+	require.NoError(err)
+
+	got, err := DecodeChainSpec(b)
+	require.NoError(err)
+	require.Equal(uint64(ChainSpecVersion), got.Version)
+	require.Equal(spec.Rules.String(), got.Rules.String())
+	require.Equal(spec.UpgradeHeights, got.UpgradeHeights)
+
+	_, err = DecodeChainSpec([]byte(`{"Version":2,"Rules":{"Economy":{"MinGasPrice":1}}}`))
+	require.Error(err, "unsupported version")
+	_, err = DecodeChainSpec([]byte(`{"Version":1,"Rules":{"Economy":{"MinGasPrice":1}},"Unknown":1}`))
+	require.Error(err, "unknown field")
+	_, err = DecodeChainSpec([]byte(`{"Version":1,"Rules":{}}`))
+	require.Error(err, "missing min gas price")
+}
+
+func TestChainSpecGenesisRules(t *testing.T) {
+	require := require.New(t)
+
+	spec := ChainSpec{Rules: FakeNetRules()}
+	require.Equal(spec.Rules.String(), spec.GenesisRules().String())
+
+	spec.Rules.Upgrades = Upgrades{}
+	spec.UpgradeHeights = []UpgradeHeight{
+		{Upgrades: Upgrades{Berlin: true, London: true, Llr: true}, Height: 100},
+		{Upgrades: Upgrades{Berlin: true}, Height: 1},
+	}
+	rules := spec.GenesisRules()
+	require.Equal(Upgrades{Berlin: true, London: true, Llr: true}, rules.Upgrades)
+	require.Equal(Upgrades{}, spec.Rules.Upgrades)
+}
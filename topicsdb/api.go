@@ -0,0 +1,56 @@
+package topicsdb
+
+import (
+	"context"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// APINamespace is the RPC namespace of the log indexer API.
+const APINamespace = "logindex"
+
+// API serves the queries of an Index over the RPC, so the index may be kept out of the node process.
+// It's the counterpart of NewRemote.
+type API struct {
+	index Index
+}
+
+// NewAPI creates the API serving the index.
+func NewAPI(index Index) *API {
+	return &API{index}
+}
+
+// FindInBlocks returns the logs of the blocks range matching the pattern.
+func (api *API) FindInBlocks(ctx context.Context, from, to hexutil.Uint64, pattern [][]common.Hash) ([]*types.Log, error) {
+	return api.index.FindInBlocks(ctx, idx.Block(from), idx.Block(to), pattern)
+}
+
+// MatchedBlocks returns the blocks of the range which have at least one log matching the pattern,
+// each block is repeated once per matched log.
+func (api *API) MatchedBlocks(ctx context.Context, from, to hexutil.Uint64, pattern [][]common.Hash) ([]hexutil.Uint64, error) {
+	blocks := []hexutil.Uint64{}
+	err := api.index.CountInBlocks(ctx, idx.Block(from), idx.Block(to), pattern, func(n idx.Block) bool {
+		blocks = append(blocks, hexutil.Uint64(n))
+		return true
+	})
+	return blocks, err
+}
+
+// FindPage returns the page of the query results, which starts after the query cursor.
+func (api *API) FindPage(ctx context.Context, q Query) (*Page, error) {
+	return api.index.FindPage(ctx, q)
+}
+
+// Count returns the number of the logs matching the query, the query limit, cursor and order are ignored.
+func (api *API) Count(ctx context.Context, q Query) (hexutil.Uint64, error) {
+	count, err := api.index.Count(ctx, q)
+	return hexutil.Uint64(count), err
+}
+
+// Exists reports whether there is a log matching the query, the query limit, cursor and order are ignored.
+func (api *API) Exists(ctx context.Context, q Query) (bool, error) {
+	return api.index.Exists(ctx, q)
+}
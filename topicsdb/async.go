@@ -0,0 +1,274 @@
+package topicsdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	ErrIndexClosed = errors.New("logs index is closed")
+
+	asyncQueueGauge = metrics.GetOrRegisterGauge("topicsdb/async/queue", nil)
+	asyncBatchMeter = metrics.GetOrRegisterMeter("topicsdb/async/batch", nil)
+)
+
+// asyncIndex pushes the records into the underlying index in background, so the indexing
+// doesn't stall the caller. Once the queue is full, Push blocks until there is a room in it.
+// The queries, deletions and Flush wait until the records pushed before them are indexed,
+// the queries stop waiting once their context is done.
+// The background worker runs only while there are records to index.
+type asyncIndex struct {
+	Index
+
+	queue chan asyncPush
+
+	mu       sync.Mutex
+	idle     *sync.Cond // signalled once a batch of the pushed records is indexed
+	pushed   uint64     // sequence number of the last Push call
+	indexed  uint64     // sequence number of the last indexed Push call
+	reported uint64     // sequence number of the last Push call waited by Flush
+	running  bool
+	closed   bool
+	failed   []failedRange // the blocks ranges the index misses the records of
+}
+
+// asyncPush is the records of a Push call.
+type asyncPush struct {
+	seq  uint64
+	recs []*types.Log
+}
+
+// failedRange is the blocks range of a batch which failed to be indexed.
+type failedRange struct {
+	seq      uint64
+	from, to idx.Block
+	err      error
+}
+
+// NewAsync creates an Index instance, which pushes the records into the underlying index in background.
+// The queue is limited by the given number of Push calls.
+func NewAsync(index Index, queueSize int) Index {
+	a := &asyncIndex{
+		Index: index,
+		queue: make(chan asyncPush, queueSize),
+	}
+	a.idle = sync.NewCond(&a.mu)
+	return a
+}
+
+func (a *asyncIndex) loop() {
+	for {
+		// batch all the queued records
+		next := <-a.queue
+		recs, seq := next.recs, next.seq
+		for more := true; more; {
+			select {
+			case next = <-a.queue:
+				recs = append(recs, next.recs...)
+				seq = next.seq
+			default:
+				more = false
+			}
+		}
+		asyncQueueGauge.Update(int64(len(a.queue)))
+		asyncBatchMeter.Mark(int64(len(recs)))
+		err := a.Index.Push(recs...)
+
+		a.mu.Lock()
+		if err != nil {
+			failed := failedRange{seq: seq, from: idx.Block(recs[0].BlockNumber), to: idx.Block(recs[0].BlockNumber), err: err}
+			for _, rec := range recs {
+				if n := idx.Block(rec.BlockNumber); n < failed.from {
+					failed.from = n
+				} else if n > failed.to {
+					failed.to = n
+				}
+			}
+			log.Error("Failed to index logs", "from", failed.from, "to", failed.to, "err", err)
+			a.failed = append(a.failed, failed)
+		}
+		a.indexed = seq
+		a.idle.Broadcast()
+		if a.indexed == a.pushed {
+			a.running = false
+			a.mu.Unlock()
+			return
+		}
+		a.mu.Unlock()
+	}
+}
+
+// wait blocks until the records of the Push calls up to the sequence number are indexed.
+// Must be called under the lock. The later pushes don't delay it.
+func (a *asyncIndex) wait(seq uint64) {
+	for a.indexed < seq {
+		a.idle.Wait()
+	}
+}
+
+// failure returns the error of the failed records of the blocks range, 0 "to" is unlimited.
+// Must be called under the lock.
+func (a *asyncIndex) failure(from, to idx.Block) error {
+	for _, f := range a.failed {
+		if f.to >= from && (to == 0 || f.from <= to) {
+			return fmt.Errorf("logs of blocks %d-%d aren't indexed: %w", f.from, f.to, f.err)
+		}
+	}
+	return nil
+}
+
+// Flush waits until the records pushed before are indexed.
+// It returns the first indexing error of the records pushed since the previous Flush, if any,
+// as the index misses the failed records.
+func (a *asyncIndex) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrIndexClosed
+	}
+	seq := a.pushed
+	a.wait(seq)
+	var err error
+	for _, f := range a.failed {
+		if f.seq > a.reported && f.seq <= seq {
+			err = fmt.Errorf("logs of blocks %d-%d aren't indexed: %w", f.from, f.to, f.err)
+			break
+		}
+	}
+	if seq > a.reported {
+		a.reported = seq
+	}
+	return err
+}
+
+// flush waits until the records pushed before are indexed, it's aborted once the context is done.
+// It returns the indexing error of the blocks range, 0 "to" is unlimited.
+func (a *asyncIndex) flush(ctx context.Context, from, to idx.Block) error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return ErrIndexClosed
+	}
+	seq := a.pushed
+	if ctx == nil || ctx.Done() == nil || a.indexed >= seq {
+		defer a.mu.Unlock()
+		a.wait(seq)
+		return a.failure(from, to)
+	}
+	a.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.wait(seq)
+		done <- a.failure(from, to)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *asyncIndex) Push(recs ...*types.Log) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return ErrIndexClosed
+	}
+	a.pushed++
+	seq := a.pushed
+	if !a.running {
+		a.running = true
+		go a.loop()
+	}
+	a.mu.Unlock()
+	// the worker keeps running until the records are received
+	a.queue <- asyncPush{seq, recs}
+	return nil
+}
+
+func (a *asyncIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error) {
+	if err := a.flush(ctx, from, to); err != nil {
+		return nil, err
+	}
+	return a.Index.FindInBlocks(ctx, from, to, pattern)
+}
+
+func (a *asyncIndex) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	if err := a.flush(ctx, from, to); err != nil {
+		return err
+	}
+	return a.Index.CountInBlocks(ctx, from, to, pattern, onBlock)
+}
+
+func (a *asyncIndex) FindPage(ctx context.Context, q Query) (*Page, error) {
+	if err := a.flush(ctx, q.From, q.To); err != nil {
+		return nil, err
+	}
+	return a.Index.FindPage(ctx, q)
+}
+
+func (a *asyncIndex) Count(ctx context.Context, q Query) (uint64, error) {
+	if err := a.flush(ctx, q.From, q.To); err != nil {
+		return 0, err
+	}
+	return a.Index.Count(ctx, q)
+}
+
+func (a *asyncIndex) Exists(ctx context.Context, q Query) (bool, error) {
+	if err := a.flush(ctx, q.From, q.To); err != nil {
+		return false, err
+	}
+	return a.Index.Exists(ctx, q)
+}
+
+func (a *asyncIndex) Delete(recs ...*types.Log) error {
+	if err := a.Flush(); err != nil {
+		return err
+	}
+	return a.Index.Delete(recs...)
+}
+
+func (a *asyncIndex) WrapTablesAsBatched() (unwrap func()) {
+	_ = a.Flush()
+	unwrapIndex := a.Index.WrapTablesAsBatched()
+	return func() {
+		_ = a.Flush()
+		unwrapIndex()
+	}
+}
+
+// Close indexes the queued records and closes the underlying index.
+func (a *asyncIndex) Close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	a.wait(a.pushed)
+	a.mu.Unlock()
+	a.Index.Close()
+}
+
+// Flush waits until the records pushed into the index before are indexed, if the index is asynchronous.
+func Flush(index Index) error {
+	if f, ok := index.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
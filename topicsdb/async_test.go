@@ -0,0 +1,140 @@
+package topicsdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestAsyncIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	addr := randAddress()
+	hash1 := common.BytesToHash([]byte("topic1"))
+	index := NewAsync(NewWithThreadPool(memorydb.New()), 2)
+
+	const blocks = 100
+	for n := 1; n <= blocks; n++ {
+		require.NoError(index.Push(&types.Log{
+			BlockNumber: uint64(n),
+			Address:     addr,
+			Topics:      []common.Hash{hash1},
+		}))
+	}
+
+	// the queries see all the records pushed before
+	got, err := index.FindInBlocks(context.Background(), 0, blocks, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Equal(blocks, len(got))
+
+	require.NoError(index.Delete(got[0]))
+	count := 0
+	require.NoError(index.CountInBlocks(context.Background(), 0, blocks, [][]common.Hash{{addr.Hash()}}, func(idx.Block) bool {
+		count++
+		return true
+	}))
+	require.Equal(blocks-1, count)
+
+	// the failed records are reported once by Flush and by the queries of their blocks only
+	require.NoError(index.Push(&types.Log{BlockNumber: 1, Address: addr, Topics: make([]common.Hash, maxTopicsCount+1)}))
+	require.ErrorIs(Flush(index), ErrTooBigTopics)
+	require.NoError(Flush(index))
+	_, err = index.FindInBlocks(context.Background(), 0, blocks, [][]common.Hash{{addr.Hash()}})
+	require.ErrorIs(err, ErrTooBigTopics)
+	got, err = index.FindInBlocks(context.Background(), 2, blocks, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Equal(blocks-1, len(got))
+	_, err = index.FindPage(context.Background(), Query{From: 0, To: 1, Addresses: []common.Address{addr}})
+	require.ErrorIs(err, ErrTooBigTopics)
+
+	index.Close()
+	require.ErrorIs(index.Push(&types.Log{BlockNumber: 1, Address: addr}), ErrIndexClosed)
+}
+
+// blockingIndex is an Index, which doesn't index the records until it's unblocked
+type blockingIndex struct {
+	Index
+	unblock chan struct{}
+}
+
+func (b *blockingIndex) Push(recs ...*types.Log) error {
+	<-b.unblock
+	return b.Index.Push(recs...)
+}
+
+func TestAsyncIndexQueryDeadline(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	addr := randAddress()
+	underlying := &blockingIndex{NewWithThreadPool(memorydb.New()), make(chan struct{})}
+	index := NewAsync(underlying, 2)
+	require.NoError(index.Push(&types.Log{BlockNumber: 1, Address: addr}))
+
+	// the query doesn't wait for the indexing after the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := index.FindInBlocks(ctx, 0, 10, [][]common.Hash{{addr.Hash()}})
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	close(underlying.unblock)
+	got, err := index.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Len(got, 1)
+	index.Close()
+}
+
+// steppingIndex is an Index, which indexes a batch of the records per step
+type steppingIndex struct {
+	Index
+	entered chan struct{}
+	step    chan struct{}
+}
+
+func (b *steppingIndex) Push(recs ...*types.Log) error {
+	b.entered <- struct{}{}
+	<-b.step
+	return b.Index.Push(recs...)
+}
+
+func TestAsyncIndexFlushIsNotDelayedByLaterPushes(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	addr := randAddress()
+	underlying := &steppingIndex{NewWithThreadPool(memorydb.New()), make(chan struct{}), make(chan struct{})}
+	index := NewAsync(underlying, 2)
+	require.NoError(index.Push(&types.Log{BlockNumber: 1, Address: addr}))
+	<-underlying.entered
+
+	flushed := make(chan error)
+	go func() {
+		flushed <- Flush(index)
+	}()
+	// the Flush waits only for the records pushed before it
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(index.Push(&types.Log{BlockNumber: 2, Address: addr}))
+	underlying.step <- struct{}{}
+	select {
+	case err := <-flushed:
+		require.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("flush waits for the later pushes")
+	}
+
+	<-underlying.entered
+	underlying.step <- struct{}{}
+	got, err := index.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Len(got, 2)
+	index.Close()
+}
@@ -0,0 +1,234 @@
+package topicsdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// The block numbers are indexed by roaring bitmaps: the higher bits of a block number select the bucket,
+// which is a separate DB key, and the lower bits are stored in the compressed container of the bucket.
+const (
+	bucketBits = 16
+	bucketSize = 1 << bucketBits
+
+	// arrayMaxCount is the max cardinality of the sparse container, the dense one is smaller above it
+	arrayMaxCount = 4096
+	denseWords    = bucketSize / 64
+
+	kindArray byte = 0
+	kindDense byte = 1
+)
+
+var errMalformedBitmap = errors.New("malformed logs index bitmap")
+
+// bitmap is a container of the lower bits of the block numbers of a bucket.
+type bitmap struct {
+	array []uint16 // sorted values of the sparse container
+	dense []uint64 // bits of the dense container, nil for the sparse one
+}
+
+func bucketOf(block uint64) uint64 {
+	return block >> bucketBits
+}
+
+func lowBits(block uint64) uint16 {
+	return uint16(block)
+}
+
+func blockOf(bucket uint64, v uint16) uint64 {
+	return bucket<<bucketBits | uint64(v)
+}
+
+func (b *bitmap) contains(v uint16) bool {
+	if b.dense != nil {
+		return b.dense[v>>6]&(1<<(v&63)) != 0
+	}
+	i := sort.Search(len(b.array), func(i int) bool { return b.array[i] >= v })
+	return i < len(b.array) && b.array[i] == v
+}
+
+func (b *bitmap) add(v uint16) {
+	if b.dense != nil {
+		b.dense[v>>6] |= 1 << (v & 63)
+		return
+	}
+	i := sort.Search(len(b.array), func(i int) bool { return b.array[i] >= v })
+	if i < len(b.array) && b.array[i] == v {
+		return
+	}
+	b.array = append(b.array, 0)
+	copy(b.array[i+1:], b.array[i:])
+	b.array[i] = v
+	if len(b.array) > arrayMaxCount {
+		b.toDense()
+	}
+}
+
+func (b *bitmap) remove(v uint16) {
+	if b.dense != nil {
+		b.dense[v>>6] &^= 1 << (v & 63)
+		if b.count() <= arrayMaxCount {
+			b.toArray()
+		}
+		return
+	}
+	i := sort.Search(len(b.array), func(i int) bool { return b.array[i] >= v })
+	if i < len(b.array) && b.array[i] == v {
+		b.array = append(b.array[:i], b.array[i+1:]...)
+	}
+}
+
+func (b *bitmap) count() int {
+	if b.dense == nil {
+		return len(b.array)
+	}
+	n := 0
+	for _, w := range b.dense {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (b *bitmap) isEmpty() bool {
+	return b.count() == 0
+}
+
+// values returns the sorted values of the container.
+func (b *bitmap) values() []uint16 {
+	if b.dense == nil {
+		return b.array
+	}
+	vv := make([]uint16, 0, b.count())
+	for i, w := range b.dense {
+		for w != 0 {
+			vv = append(vv, uint16(i*64+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+	return vv
+}
+
+func (b *bitmap) toDense() {
+	b.dense = make([]uint64, denseWords)
+	for _, v := range b.array {
+		b.dense[v>>6] |= 1 << (v & 63)
+	}
+	b.array = nil
+}
+
+func (b *bitmap) toArray() {
+	b.array = b.values()
+	b.dense = nil
+}
+
+// union adds the values of the other container.
+func (b *bitmap) union(o *bitmap) {
+	if b.dense == nil && o.dense == nil {
+		merged := make([]uint16, 0, len(b.array)+len(o.array))
+		i, j := 0, 0
+		for i < len(b.array) && j < len(o.array) {
+			switch {
+			case b.array[i] < o.array[j]:
+				merged = append(merged, b.array[i])
+				i++
+			case b.array[i] > o.array[j]:
+				merged = append(merged, o.array[j])
+				j++
+			default:
+				merged = append(merged, b.array[i])
+				i++
+				j++
+			}
+		}
+		merged = append(merged, b.array[i:]...)
+		merged = append(merged, o.array[j:]...)
+		b.array = merged
+		if len(b.array) > arrayMaxCount {
+			b.toDense()
+		}
+		return
+	}
+	if b.dense == nil {
+		b.toDense()
+	}
+	if o.dense != nil {
+		for i, w := range o.dense {
+			b.dense[i] |= w
+		}
+		return
+	}
+	for _, v := range o.array {
+		b.dense[v>>6] |= 1 << (v & 63)
+	}
+}
+
+// intersect returns the values contained by both the containers.
+func (b *bitmap) intersect(o *bitmap) *bitmap {
+	if b.dense != nil && o.dense != nil {
+		res := &bitmap{dense: make([]uint64, denseWords)}
+		for i := range res.dense {
+			res.dense[i] = b.dense[i] & o.dense[i]
+		}
+		if res.count() <= arrayMaxCount {
+			res.toArray()
+		}
+		return res
+	}
+	if b.dense != nil || (o.dense == nil && len(o.array) < len(b.array)) {
+		b, o = o, b
+	}
+	res := &bitmap{}
+	for _, v := range b.array {
+		if o.contains(v) {
+			res.array = append(res.array, v)
+		}
+	}
+	return res
+}
+
+func (b *bitmap) encode() []byte {
+	if b.dense != nil {
+		buf := make([]byte, 1+denseWords*8)
+		buf[0] = kindDense
+		for i, w := range b.dense {
+			binary.BigEndian.PutUint64(buf[1+i*8:], w)
+		}
+		return buf
+	}
+	buf := make([]byte, 1+len(b.array)*2)
+	buf[0] = kindArray
+	for i, v := range b.array {
+		binary.BigEndian.PutUint16(buf[1+i*2:], v)
+	}
+	return buf
+}
+
+func decodeBitmap(buf []byte) (*bitmap, error) {
+	if len(buf) == 0 {
+		return nil, errMalformedBitmap
+	}
+	b := &bitmap{}
+	switch buf[0] {
+	case kindArray:
+		if len(buf)%2 != 1 {
+			return nil, errMalformedBitmap
+		}
+		b.array = make([]uint16, (len(buf)-1)/2)
+		for i := range b.array {
+			b.array[i] = binary.BigEndian.Uint16(buf[1+i*2:])
+		}
+	case kindDense:
+		if len(buf) != 1+denseWords*8 {
+			return nil, errMalformedBitmap
+		}
+		b.dense = make([]uint64, denseWords)
+		for i := range b.dense {
+			b.dense[i] = binary.BigEndian.Uint64(buf[1+i*8:])
+		}
+	default:
+		return nil, errMalformedBitmap
+	}
+	return b, nil
+}
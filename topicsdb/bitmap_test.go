@@ -0,0 +1,119 @@
+package topicsdb
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitmap(t *testing.T) {
+	for name, count := range map[string]int{
+		"empty":  0,
+		"sparse": 100,
+		"dense":  arrayMaxCount * 2,
+	} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			exp := make(map[uint16]bool)
+			b := &bitmap{}
+			for len(exp) < count {
+				v := uint16(rand.Intn(bucketSize))
+				exp[v] = true
+				b.add(v)
+			}
+			require.Equal(count > arrayMaxCount, b.dense != nil)
+			require.Equal(count, b.count())
+			for v := range exp {
+				require.True(b.contains(v))
+			}
+
+			got, err := decodeBitmap(b.encode())
+			require.NoError(err)
+			require.Equal(sortedValues(exp), got.values())
+
+			// the dense container turns sparse once it's small enough
+			for v := range exp {
+				b.remove(v)
+				delete(exp, v)
+				if len(exp) == arrayMaxCount {
+					require.Nil(b.dense)
+				}
+			}
+			require.True(b.isEmpty())
+		})
+	}
+}
+
+func TestBitmapSetOperations(t *testing.T) {
+	for name, counts := range map[string][2]int{
+		"sparse": {100, 200},
+		"mixed":  {100, arrayMaxCount * 2},
+		"dense":  {arrayMaxCount * 2, arrayMaxCount * 3},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			var (
+				sets    [2]map[uint16]bool
+				bitmaps [2]*bitmap
+			)
+			for i, count := range counts {
+				sets[i] = make(map[uint16]bool)
+				bitmaps[i] = &bitmap{}
+				for len(sets[i]) < count {
+					v := uint16(rand.Intn(bucketSize / 2))
+					sets[i][v] = true
+					bitmaps[i].add(v)
+				}
+			}
+
+			intersection := make(map[uint16]bool)
+			union := make(map[uint16]bool)
+			for v := range sets[0] {
+				union[v] = true
+				if sets[1][v] {
+					intersection[v] = true
+				}
+			}
+			for v := range sets[1] {
+				union[v] = true
+			}
+
+			require.Equal(sortedValues(intersection), nonNil(bitmaps[0].intersect(bitmaps[1]).values()))
+			require.Equal(sortedValues(intersection), nonNil(bitmaps[1].intersect(bitmaps[0]).values()))
+			bitmaps[0].union(bitmaps[1])
+			require.Equal(sortedValues(union), bitmaps[0].values())
+		})
+	}
+}
+
+func TestDecodeMalformedBitmap(t *testing.T) {
+	for _, buf := range [][]byte{
+		nil,
+		{kindArray, 1},
+		{kindDense, 1, 2},
+		{2},
+	} {
+		_, err := decodeBitmap(buf)
+		require.ErrorIs(t, err, errMalformedBitmap)
+	}
+}
+
+func sortedValues(set map[uint16]bool) []uint16 {
+	vv := make([]uint16, 0, len(set))
+	for v := range set {
+		vv = append(vv, v)
+	}
+	sort.Slice(vv, func(i, j int) bool { return vv[i] < vv[j] })
+	return vv
+}
+
+func nonNil(vv []uint16) []uint16 {
+	if vv == nil {
+		return []uint16{}
+	}
+	return vv
+}
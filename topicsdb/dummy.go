@@ -14,10 +14,30 @@ func (n dummyIndex) FindInBlocks(ctx context.Context, from, to idx.Block, patter
 	return nil, ErrLogsNotRecorded
 }
 
+func (n dummyIndex) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	return ErrLogsNotRecorded
+}
+
+func (n dummyIndex) FindPage(ctx context.Context, q Query) (*Page, error) {
+	return nil, ErrLogsNotRecorded
+}
+
+func (n dummyIndex) Count(ctx context.Context, q Query) (uint64, error) {
+	return 0, ErrLogsNotRecorded
+}
+
+func (n dummyIndex) Exists(ctx context.Context, q Query) (bool, error) {
+	return false, ErrLogsNotRecorded
+}
+
 func (n dummyIndex) Push(recs ...*types.Log) error {
 	return nil
 }
 
+func (n dummyIndex) Delete(recs ...*types.Log) error {
+	return nil
+}
+
 func (n dummyIndex) Close() {}
 
 func (n dummyIndex) WrapTablesAsBatched() (unwrap func()) {
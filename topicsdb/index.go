@@ -2,6 +2,7 @@ package topicsdb
 
 import (
 	"context"
+	"sync"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
@@ -14,28 +15,54 @@ import (
 // index is a specialized indexes for log records storing and fetching.
 type index struct {
 	table struct {
+		// topic+topicN+(blockN>>16) -> bitmap of the lower 16 bits of blockN (where topicN=0 is for address)
+		Bitmap kvdb.Store `table:"m"`
+		// (blockN+TxHash+logIndex) -> topic_count, ordered topic_count topics, blockHash, address, data
+		Logrec kvdb.Store `table:"n"`
+	}
+	// per-log index of the older releases, it's migrated by MigrateLegacy
+	legacy struct {
 		// topic+topicN+(blockN+TxHash+logIndex) -> topic_count (where topicN=0 is for address)
 		Topic kvdb.Store `table:"t"`
 		// (blockN+TxHash+logIndex) -> ordered topic_count topics, blockHash, address, data
 		Logrec kvdb.Store `table:"r"`
 	}
+
+	// bitmapsMu serializes the bitmaps updates, which are read-modify-write
+	bitmapsMu sync.Mutex
+	// pending are the bitmaps updated in the batched mode, nil otherwise
+	pending map[string]*bitmap
+
+	// parallelism is the number of the pattern positions scanned concurrently
+	parallelism int
 }
 
+// maxPendingBitmaps is the number of the bitmaps updated in the batched mode, which are flushed at once
+const maxPendingBitmaps = 4096
+
 func newIndex(db kvdb.Store) *index {
-	tt := &index{}
+	tt := &index{
+		parallelism: DefaultConfig().Parallelism,
+	}
 	table.MigrateTables(&tt.table, db)
+	table.MigrateTables(&tt.legacy, db)
 	return tt
 }
 
 func (tt *index) WrapTablesAsBatched() (unwrap func()) {
 	origTables := tt.table
-	batchedTopic := batched.Wrap(tt.table.Topic)
-	tt.table.Topic = batchedTopic
 	batchedLogrec := batched.Wrap(tt.table.Logrec)
 	tt.table.Logrec = batchedLogrec
+	// the bitmaps are read before the update, so they are cached until flushed instead of the batch
+	tt.bitmapsMu.Lock()
+	tt.pending = make(map[string]*bitmap)
+	tt.bitmapsMu.Unlock()
 	return func() {
-		_ = batchedTopic.Flush()
 		_ = batchedLogrec.Flush()
+		tt.bitmapsMu.Lock()
+		_ = tt.flushPending()
+		tt.pending = nil
+		tt.bitmapsMu.Unlock()
 		tt.table = origTables
 	}
 }
@@ -66,71 +93,224 @@ func (tt *index) ForEachInBlocks(ctx context.Context, from, to idx.Block, patter
 	}
 
 	onMatched := func(rec *logrec) (gonext bool, err error) {
-		rec.fetch(tt.table.Logrec)
-		if rec.err != nil {
-			err = rec.err
-			return
-		}
 		gonext = onLog(rec.result)
 		return
 	}
 
-	return tt.searchParallel(ctx, pattern, uint64(from), uint64(to), onMatched, doNothing)
+	return tt.searchBitmaps(ctx, pattern, uint64(from), uint64(to), true, onMatched, doNothing)
+}
+
+// CountInBlocks calls onBlock for each log record of block range matched by pattern.
+// 1st pattern element is an address. The calls are serialized.
+func (tt *index) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	return tt.forEachID(ctx, from, to, pattern, func(id ID) bool {
+		return onBlock(idx.Block(id.BlockNumber()))
+	})
+}
+
+// forEachID calls onID for each log record of block range matched by pattern, the records aren't decoded.
+// 1st pattern element is an address.
+func (tt *index) forEachID(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onID func(ID) (gonext bool)) error {
+	if 0 < to && to < from {
+		return nil
+	}
+
+	pattern, err := limitPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	onMatched := func(rec *logrec) (gonext bool, err error) {
+		gonext = onID(rec.ID)
+		return
+	}
+
+	return tt.searchBitmaps(ctx, pattern, uint64(from), uint64(to), false, onMatched, doNothing)
+}
+
+// FindPage returns the page of the query results, which starts after the query cursor.
+func (tt *index) FindPage(ctx context.Context, q Query) (*Page, error) {
+	return findPage(ctx, q, tt.ForEachInBlocks)
+}
+
+// Count returns the number of the logs matching the query, the logs aren't decoded.
+func (tt *index) Count(ctx context.Context, q Query) (uint64, error) {
+	return countLogs(ctx, q, tt.forEachID)
+}
+
+// Exists reports whether there is a log matching the query, the logs aren't decoded.
+func (tt *index) Exists(ctx context.Context, q Query) (bool, error) {
+	return logsExist(ctx, q, tt.forEachID)
 }
 
 func doNothing() {}
 
+// recordBits calls add for the bitmap key and the block bits of each topic of the record, including the address.
+func recordBits(rec *types.Log, add func(key string, v uint16)) {
+	bucket, v := bucketOf(rec.BlockNumber), lowBits(rec.BlockNumber)
+	add(string(bitmapKey(rec.Address.Hash(), 0, bucket)), v)
+	for i, topic := range rec.Topics {
+		add(string(bitmapKey(topic, uint8(i+1), bucket)), v)
+	}
+}
+
 // Push log record to database batch
 func (tt *index) Push(recs ...*types.Log) error {
 	for _, rec := range recs {
 		if len(rec.Topics) > maxTopicsCount {
 			return ErrTooBigTopics
 		}
+	}
 
+	// write data
+	bits := make(map[string][]uint16)
+	for _, rec := range recs {
 		id := NewID(rec.BlockNumber, rec.TxHash, rec.Index)
-
-		// write data
-		buf := make([]byte, 0, common.HashLength*len(rec.Topics)+common.HashLength+common.AddressLength+len(rec.Data))
-		for _, topic := range rec.Topics {
-			buf = append(buf, topic.Bytes()...)
+		if err := tt.table.Logrec.Put(id.Bytes(), encodeLogrec(rec)); err != nil {
+			return err
 		}
-		buf = append(buf, rec.BlockHash.Bytes()...)
-		buf = append(buf, rec.Address.Bytes()...)
-		buf = append(buf, rec.Data...)
-		if err := tt.table.Logrec.Put(id.Bytes(), buf); err != nil {
+		recordBits(rec, func(key string, v uint16) {
+			bits[key] = append(bits[key], v)
+		})
+	}
+
+	// write index
+	return tt.updateBitmaps(bits, (*bitmap).add)
+}
+
+// Delete log records and their index from database batch
+func (tt *index) Delete(recs ...*types.Log) error {
+	// delete records first, so a record isn't matched after it's deleted
+	byBlock := make(map[uint64][]*types.Log)
+	for _, rec := range recs {
+		id := NewID(rec.BlockNumber, rec.TxHash, rec.Index)
+		if err := tt.table.Logrec.Delete(id.Bytes()); err != nil {
 			return err
 		}
+		byBlock[rec.BlockNumber] = append(byBlock[rec.BlockNumber], rec)
+	}
 
-		// write index
-		var (
-			count = posToBytes(uint8(len(rec.Topics)))
-			pos   uint8
-		)
-		pushIndex := func(topic common.Hash) error {
-			key := topicKey(topic, pos, id)
-			if err := tt.table.Topic.Put(key, count); err != nil {
+	// in the batched mode the block records aren't readable until flushed, so the bits are kept,
+	// a stale bit costs just a block scan as the records are matched by pattern
+	tt.bitmapsMu.Lock()
+	batchedMode := tt.pending != nil
+	tt.bitmapsMu.Unlock()
+	if batchedMode {
+		return nil
+	}
+
+	// the block bit is cleared once no rest record of the block has the topic in the position
+	bits := make(map[string][]uint16)
+	for block, deleted := range byBlock {
+		rest := make(map[string]bool)
+		it := tt.table.Logrec.NewIterator(uintToBytes(block), nil)
+		for it.Next() {
+			if len(it.Key()) != logrecKeySize {
+				continue
+			}
+			var id ID
+			copy(id[:], it.Key())
+			rec, err := decodeLogrec(id, common.CopyBytes(it.Value()))
+			if err != nil {
+				it.Release()
 				return err
 			}
-			pos++
-			return nil
+			recordBits(rec.result, func(key string, _ uint16) {
+				rest[key] = true
+			})
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+		for _, rec := range deleted {
+			recordBits(rec, func(key string, v uint16) {
+				if !rest[key] {
+					rest[key] = true
+					bits[key] = append(bits[key], v)
+				}
+			})
 		}
+	}
+
+	return tt.updateBitmaps(bits, (*bitmap).remove)
+}
 
-		if err := pushIndex(rec.Address.Hash()); err != nil {
+// updateBitmaps applies the update to the bits of the bitmaps, the empty bitmaps are deleted.
+func (tt *index) updateBitmaps(bits map[string][]uint16, update func(b *bitmap, v uint16)) error {
+	tt.bitmapsMu.Lock()
+	defer tt.bitmapsMu.Unlock()
+
+	for key, vv := range bits {
+		b, err := tt.getBitmap(key)
+		if err != nil {
 			return err
 		}
+		for _, v := range vv {
+			update(b, v)
+		}
+		if err := tt.putBitmap(key, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tt *index) getBitmap(key string) (*bitmap, error) {
+	if b, ok := tt.pending[key]; ok {
+		return b, nil
+	}
+	buf, err := tt.table.Bitmap.Get([]byte(key))
+	if err != nil || buf == nil {
+		return &bitmap{}, err
+	}
+	return decodeBitmap(buf)
+}
 
-		for _, topic := range rec.Topics {
-			if err := pushIndex(topic); err != nil {
+func (tt *index) putBitmap(key string, b *bitmap) error {
+	if tt.pending != nil {
+		tt.pending[key] = b
+		if len(tt.pending) >= maxPendingBitmaps {
+			return tt.flushPending()
+		}
+		return nil
+	}
+	if b.isEmpty() {
+		return tt.table.Bitmap.Delete([]byte(key))
+	}
+	return tt.table.Bitmap.Put([]byte(key), b.encode())
+}
+
+// flushPending writes the bitmaps updated in the batched mode.
+func (tt *index) flushPending() error {
+	batch := tt.table.Bitmap.NewBatch()
+	defer batch.Reset()
+	for key, b := range tt.pending {
+		var err error
+		if b.isEmpty() {
+			err = batch.Delete([]byte(key))
+		} else {
+			err = batch.Put([]byte(key), b.encode())
+		}
+		if err != nil {
+			return err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
 				return err
 			}
+			batch.Reset()
 		}
-
 	}
-
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	tt.pending = make(map[string]*bitmap)
 	return nil
 }
 
 func (tt *index) Close() {
-	_ = tt.table.Topic.Close()
+	_ = tt.table.Bitmap.Close()
 	_ = tt.table.Logrec.Close()
 }
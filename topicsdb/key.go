@@ -11,8 +11,9 @@ const (
 	hashSize   = common.HashLength
 
 	logrecKeySize = uint64Size + hashSize + uint64Size
-	topicKeySize  = hashSize + uint8Size + logrecKeySize
-	otherKeySize  = logrecKeySize + uint8Size
+	bitmapKeySize = hashSize + uint8Size + uint64Size
+	// legacyTopicKeySize is the key size of the per-log index of the older releases
+	legacyTopicKeySize = hashSize + uint8Size + logrecKeySize
 )
 
 type (
@@ -45,16 +46,20 @@ func (id *ID) Index() uint {
 		(*id)[uint64Size+hashSize : uint64Size+hashSize+uint64Size]))
 }
 
-func topicKey(topic common.Hash, pos uint8, logrec ID) []byte {
-	key := make([]byte, 0, topicKeySize)
+func bitmapKey(topic common.Hash, pos uint8, bucket uint64) []byte {
+	key := make([]byte, 0, bitmapKeySize)
 
 	key = append(key, topic.Bytes()...)
 	key = append(key, posToBytes(pos)...)
-	key = append(key, logrec.Bytes()...)
+	key = append(key, uintToBytes(bucket)...)
 
 	return key
 }
 
+func extractBucket(key []byte) uint64 {
+	return bytesToUint(key[hashSize+uint8Size:])
+}
+
 func posToBytes(pos uint8) []byte {
 	return []byte{pos}
 }
@@ -71,9 +76,9 @@ func bytesToUint(b []byte) uint64 {
 	return bigendian.BytesToUint64(b)
 }
 
-func extractLogrecID(key []byte) (id ID) {
+func extractLegacyLogrecID(key []byte) (id ID) {
 	switch len(key) {
-	case topicKeySize:
+	case legacyTopicKeySize:
 		copy(id[:], key[hashSize+uint8Size:])
 		return
 	default:
@@ -0,0 +1,130 @@
+package topicsdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// legacyMigrationChunk is the number of the legacy index keys migrated at once
+const legacyMigrationChunk = 10000
+
+// ErrLegacyIndex is returned by the queries of the index, which has the records of the older releases
+// not migrated yet.
+var ErrLegacyIndex = errors.New("logs index of an older release isn't migrated, stop the node and run `sonictool migrate-logs`")
+
+// HasLegacy reports whether the db has the logs index of the older releases, which isn't migrated yet.
+func HasLegacy(db kvdb.Store) (bool, error) {
+	keys, _, err := newIndex(db).readLegacyChunk(nil, 1)
+	return len(keys) != 0, err
+}
+
+// MigrateLegacy converts the logs index of the older releases into the bitmaps index.
+// The index must not be in use. An interrupted migration is resumed by the next call.
+func MigrateLegacy(ctx context.Context, db kvdb.Store) error {
+	return newIndex(db).migrateLegacy(ctx)
+}
+
+// migrateLegacy converts the per-log index of the older releases into the bitmaps index.
+// The legacy keys are deleted once migrated, so an interrupted migration is resumed by the next call.
+func (tt *index) migrateLegacy(ctx context.Context) error {
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		migrated = 0
+		from     []byte
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			log.Warn("Logs index migration is interrupted, run it again to resume", "keys", migrated)
+			return err
+		}
+		keys, counts, err := tt.readLegacyChunk(from, legacyMigrationChunk)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			break
+		}
+		if migrated == 0 {
+			log.Warn("Migrating the logs index into bitmaps, it may take a while")
+		}
+
+		// write the records and the bitmaps before the legacy keys are deleted
+		bits := make(map[string][]uint16)
+		records := tt.table.Logrec.NewBatch()
+		legacyRecords := tt.legacy.Logrec.NewBatch()
+		for i, key := range keys {
+			topic := common.BytesToHash(key[:hashSize])
+			pos := bytesToPos(key[hashSize:])
+			id := extractLegacyLogrecID(key)
+			block := id.BlockNumber()
+			k := string(bitmapKey(topic, pos, bucketOf(block)))
+			bits[k] = append(bits[k], lowBits(block))
+			if pos != 0 {
+				continue
+			}
+			buf, err := tt.legacy.Logrec.Get(id.Bytes())
+			if err != nil {
+				return err
+			}
+			if buf == nil {
+				// migrated before an interruption, or pruned
+				continue
+			}
+			if err := records.Put(id.Bytes(), append([]byte{counts[i]}, buf...)); err != nil {
+				return err
+			}
+			if err := legacyRecords.Delete(id.Bytes()); err != nil {
+				return err
+			}
+		}
+		if err := records.Write(); err != nil {
+			return err
+		}
+		if err := tt.updateBitmaps(bits, (*bitmap).add); err != nil {
+			return err
+		}
+		if err := legacyRecords.Write(); err != nil {
+			return err
+		}
+		legacyKeys := tt.legacy.Topic.NewBatch()
+		for _, key := range keys {
+			if err := legacyKeys.Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := legacyKeys.Write(); err != nil {
+			return err
+		}
+
+		migrated += len(keys)
+		from = append(common.CopyBytes(keys[len(keys)-1]), 0)
+		if time.Since(logged) >= 8*time.Second {
+			log.Info("Migrating the logs index", "keys", migrated, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if migrated != 0 {
+		log.Info("Logs index is migrated", "keys", migrated, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
+// readLegacyChunk reads up to limit next legacy index keys and the topics counts of their records.
+func (tt *index) readLegacyChunk(from []byte, limit int) (keys [][]byte, counts []uint8, err error) {
+	it := tt.legacy.Topic.NewIterator(nil, from)
+	defer it.Release()
+	for len(keys) < limit && it.Next() {
+		if len(it.Key()) != legacyTopicKeySize || len(it.Value()) != uint8Size {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(it.Key()))
+		counts = append(counts, bytesToPos(it.Value()))
+	}
+	return keys, counts, it.Error()
+}
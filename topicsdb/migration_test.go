@@ -0,0 +1,91 @@
+package topicsdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+// pushLegacy writes the records in the per-log index layout of the older releases.
+func pushLegacy(t *testing.T, db kvdb.Store, recs ...*types.Log) {
+	legacy := &struct {
+		Topic  kvdb.Store `table:"t"`
+		Logrec kvdb.Store `table:"r"`
+	}{}
+	table.MigrateTables(legacy, db)
+
+	for _, rec := range recs {
+		id := NewID(rec.BlockNumber, rec.TxHash, rec.Index)
+		buf := encodeLogrec(rec)[uint8Size:]
+		require.NoError(t, legacy.Logrec.Put(id.Bytes(), buf))
+
+		count := posToBytes(uint8(len(rec.Topics)))
+		key := func(topic common.Hash, pos uint8) []byte {
+			return append(append(topic.Bytes(), pos), id.Bytes()...)
+		}
+		require.NoError(t, legacy.Topic.Put(key(rec.Address.Hash(), 0), count))
+		for i, topic := range rec.Topics {
+			require.NoError(t, legacy.Topic.Put(key(topic, uint8(i+1)), count))
+		}
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	_, recs, _ := genTestData(100)
+	// the records of the further buckets
+	for i, rec := range recs[50:] {
+		rec.BlockNumber += bucketSize * uint64(1+i%3)
+	}
+
+	db := memorydb.New()
+	pushLegacy(t, db, recs...)
+	legacy, err := HasLegacy(db)
+	require.NoError(err)
+	require.True(legacy)
+	require.NoError(MigrateLegacy(context.Background(), db))
+	legacy, err = HasLegacy(db)
+	require.NoError(err)
+	require.False(legacy)
+	index := newIndex(db)
+
+	for _, rec := range recs {
+		got, err := index.FindInBlocks(nil, 0, 0, [][]common.Hash{{rec.Address.Hash()}, rec.Topics[:1]})
+		require.NoError(err)
+		require.Equal(1, len(got))
+		require.Equal(rec.BlockNumber, got[0].BlockNumber)
+		require.Equal(rec.Topics, got[0].Topics)
+		require.Equal(rec.Data, got[0].Data)
+	}
+
+	// the legacy keys are deleted
+	for _, legacy := range []kvdb.Store{index.legacy.Topic, index.legacy.Logrec} {
+		it := legacy.NewIterator(nil, nil)
+		require.False(it.Next())
+		it.Release()
+	}
+
+	// the migration is resumed
+	pushLegacy(t, db, recs[:10]...)
+	require.NoError(MigrateLegacy(context.Background(), db))
+	index = newIndex(db)
+	got, err := index.FindInBlocks(nil, 0, 0, [][]common.Hash{{}, recs[0].Topics[:1]})
+	require.NoError(err)
+	exp := 0
+	for _, rec := range recs {
+		if rec.Topics[0] == recs[0].Topics[0] {
+			exp++
+		}
+	}
+	require.Equal(exp, len(got))
+}
@@ -0,0 +1,231 @@
+package topicsdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type (
+	// Query selects the logs of the blocks range by addresses and topics, the results are returned by pages.
+	Query struct {
+		From      idx.Block        `json:"fromBlock"`
+		To        idx.Block        `json:"toBlock"`
+		Addresses []common.Address `json:"addresses"`
+		Topics    [][]common.Hash  `json:"topics"`
+		// Limit is the max number of logs in the page, 0 for no limit
+		Limit int `json:"limit"`
+		// Cursor is the continuation returned with the previous page, nil for the first page
+		Cursor *Cursor `json:"cursor"`
+		// Descending returns the newest logs first, the search goes from the To block backwards
+		// and stops once the page is collected. To must be specified.
+		Descending bool `json:"descending"`
+	}
+
+	// Page is a page of the query results, the logs are ordered by block, tx hash and log index,
+	// in the descending order for a descending query.
+	Page struct {
+		Logs []*types.Log `json:"logs"`
+		// Next is the cursor of the next page, nil if the page is the last one
+		Next *Cursor `json:"next"`
+	}
+
+	// Cursor points to the last log of a page, the next page starts right after it
+	// (right before it for a descending query).
+	Cursor ID
+)
+
+// MarshalText encodes the cursor as a hex string.
+func (c Cursor) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(c[:]).MarshalText()
+}
+
+// UnmarshalText decodes the cursor from a hex string.
+func (c *Cursor) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("Cursor", input, c[:])
+}
+
+// pattern returns the search pattern of the query, the 1st pattern element is an address.
+func (q *Query) pattern() [][]common.Hash {
+	pattern := make([][]common.Hash, 1+len(q.Topics))
+	for _, addr := range q.Addresses {
+		pattern[0] = append(pattern[0], addr.Hash())
+	}
+	copy(pattern[1:], q.Topics)
+	return pattern
+}
+
+type forEachFn func(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onLog func(*types.Log) (gonext bool)) error
+
+type forEachIDFn func(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onID func(ID) (gonext bool)) error
+
+// countLogs returns the number of the logs matched by forEachID. The query limit, cursor and order are ignored.
+func countLogs(ctx context.Context, q Query, forEachID forEachIDFn) (count uint64, err error) {
+	err = forEachID(ctx, q.From, q.To, q.pattern(), func(ID) bool {
+		count++
+		return true
+	})
+	return
+}
+
+// logsExist reports whether forEachID matches any log, the search stops at the first one.
+// The query limit, cursor and order are ignored.
+func logsExist(ctx context.Context, q Query, forEachID forEachIDFn) (exists bool, err error) {
+	err = forEachID(ctx, q.From, q.To, q.pattern(), func(ID) bool {
+		exists = true
+		return false
+	})
+	return
+}
+
+type matchedLog struct {
+	id  ID
+	log *types.Log
+}
+
+// findPage collects the page of the query results from the logs matched by forEach.
+// The logs of a block are matched in any order, but the blocks are matched in the ascending order,
+// possibly in several runs (see withThreadPool), so a run is stopped once the rest of it can't get into the page.
+func findPage(ctx context.Context, q Query, forEach forEachFn) (*Page, error) {
+	if q.Descending {
+		return findPageDescending(ctx, q, forEach)
+	}
+	from := q.From
+	if q.Cursor != nil {
+		after := ID(*q.Cursor)
+		if n := idx.Block(after.BlockNumber()); n > from {
+			from = n
+		}
+	}
+	if 0 < q.To && q.To < from {
+		return &Page{Logs: []*types.Log{}}, nil
+	}
+
+	// one more log is kept to know whether there is the next page
+	keep := q.Limit + 1
+	var found []matchedLog
+	err := forEach(ctx, from, q.To, q.pattern(), func(l *types.Log) bool {
+		rec := matchedLog{NewID(l.BlockNumber, l.TxHash, l.Index), l}
+		if q.Cursor != nil && bytes.Compare(rec.id[:], q.Cursor[:]) <= 0 {
+			return true
+		}
+		if q.Limit <= 0 {
+			found = append(found, rec)
+			return true
+		}
+		if len(found) == keep && l.BlockNumber > found[keep-1].log.BlockNumber {
+			return false
+		}
+		i := sort.Search(len(found), func(i int) bool {
+			return bytes.Compare(found[i].id[:], rec.id[:]) > 0
+		})
+		found = append(found, matchedLog{})
+		copy(found[i+1:], found[i:])
+		found[i] = rec
+		if len(found) > keep {
+			found = found[:keep]
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit <= 0 {
+		sort.Slice(found, func(i, j int) bool {
+			return bytes.Compare(found[i].id[:], found[j].id[:]) < 0
+		})
+	}
+
+	page := &Page{}
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[:q.Limit]
+		next := Cursor(found[q.Limit-1].id)
+		page.Next = &next
+	}
+	page.Logs = make([]*types.Log, len(found))
+	for i, rec := range found {
+		page.Logs[i] = rec.log
+	}
+	return page, nil
+}
+
+// descendingWindow is the first blocks range of a descending search, each next range is twice bigger
+const descendingWindow = 1024
+
+// ErrNoUpperBlock is returned for a descending query without the To block.
+var ErrNoUpperBlock = errors.New("descending query requires the upper block")
+
+// findPageDescending collects the page of the descending query results. As the index is ordered by blocks
+// ascending, the blocks ranges before the To block are searched one by one, each next range is twice bigger,
+// until the page is collected. So the search of the latest logs doesn't scan the whole history.
+func findPageDescending(ctx context.Context, q Query, forEach forEachFn) (*Page, error) {
+	if q.To == 0 {
+		return nil, ErrNoUpperBlock
+	}
+	to := q.To
+	if q.Cursor != nil {
+		before := ID(*q.Cursor)
+		if n := idx.Block(before.BlockNumber()); n < to {
+			to = n
+		}
+	}
+
+	// one more log is kept to know whether there is the next page
+	keep := q.Limit + 1
+	var found []matchedLog
+	pattern := q.pattern()
+	for window := idx.Block(descendingWindow); to >= q.From; window *= 2 {
+		from := q.From
+		if to-q.From >= window {
+			from = to - window + 1
+		}
+
+		// the logs of the range, in the descending order
+		var matched []matchedLog
+		err := forEach(ctx, from, to, pattern, func(l *types.Log) bool {
+			rec := matchedLog{NewID(l.BlockNumber, l.TxHash, l.Index), l}
+			if q.Cursor != nil && bytes.Compare(rec.id[:], q.Cursor[:]) >= 0 {
+				return true
+			}
+			i := sort.Search(len(matched), func(i int) bool {
+				return bytes.Compare(matched[i].id[:], rec.id[:]) < 0
+			})
+			if q.Limit > 0 && i >= keep-len(found) {
+				return true
+			}
+			matched = append(matched, matchedLog{})
+			copy(matched[i+1:], matched[i:])
+			matched[i] = rec
+			if q.Limit > 0 && len(found)+len(matched) > keep {
+				matched = matched[:keep-len(found)]
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, matched...)
+		if q.Limit > 0 && len(found) >= keep || from == q.From {
+			break
+		}
+		to = from - 1
+	}
+
+	page := &Page{}
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[:q.Limit]
+		next := Cursor(found[q.Limit-1].id)
+		page.Next = &next
+	}
+	page.Logs = make([]*types.Log, len(found))
+	for i, rec := range found {
+		page.Logs[i] = rec.log
+	}
+	return page, nil
+}
@@ -0,0 +1,246 @@
+package topicsdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/threads"
+)
+
+func TestIndexFindPage(t *testing.T) {
+	logger.SetTestMode(t)
+
+	const N = 100
+
+	topics, recs, _ := genTestData(N)
+	index := newTestIndex()
+	require.NoError(t, index.Push(recs...))
+
+	pooled := &withThreadPool{index}
+
+	// more variants than the threads pool has, so the pooled search goes in several runs
+	variants := make([]common.Hash, threads.GlobalPool.Cap()+1)
+	for i := range variants {
+		variants[i] = hash.FakeHash(int64(i))
+	}
+
+	expect := func(from, to uint64, match func(*types.Log) bool) []*types.Log {
+		var logs []*types.Log
+		for _, rec := range recs {
+			if from <= rec.BlockNumber && rec.BlockNumber <= to && match(rec) {
+				logs = append(logs, rec)
+			}
+		}
+		sort.Slice(logs, func(i, j int) bool {
+			a, b := NewID(logs[i].BlockNumber, logs[i].TxHash, logs[i].Index), NewID(logs[j].BlockNumber, logs[j].TxHash, logs[j].Index)
+			return bytes.Compare(a[:], b[:]) < 0
+		})
+		return logs
+	}
+
+	for dsc, index := range map[string]Index{
+		"index":  index,
+		"pooled": pooled,
+	} {
+		t.Run(dsc, func(t *testing.T) {
+			for _, tc := range []struct {
+				name   string
+				query  Query
+				expect []*types.Log
+			}{
+				{
+					name:  "topic",
+					query: Query{From: 2, To: 15, Topics: [][]common.Hash{{topics[1]}}},
+					expect: expect(2, 15, func(l *types.Log) bool {
+						return l.Topics[0] == topics[1]
+					}),
+				},
+				{
+					name:  "addresses",
+					query: Query{From: 0, To: 1000, Addresses: []common.Address{recs[3].Address, recs[50].Address, recs[97].Address}},
+					expect: expect(0, 1000, func(l *types.Log) bool {
+						return l == recs[3] || l == recs[50] || l == recs[97]
+					}),
+				},
+				{
+					name:  "many variants",
+					query: Query{From: 3, To: 17, Topics: [][]common.Hash{variants}},
+					expect: expect(3, 17, func(l *types.Log) bool {
+						return true
+					}),
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					require := require.New(t)
+
+					// the whole results
+					page, err := index.FindPage(context.Background(), tc.query)
+					require.NoError(err)
+					require.Nil(page.Next)
+					require.Equal(tc.expect, page.Logs)
+
+					// by pages
+					for _, limit := range []int{1, 4, 7, len(tc.expect)} {
+						q := tc.query
+						q.Limit = limit
+						var got []*types.Log
+						for {
+							page, err := index.FindPage(context.Background(), q)
+							require.NoError(err)
+							require.LessOrEqual(len(page.Logs), limit)
+							got = append(got, page.Logs...)
+							if page.Next == nil {
+								break
+							}
+							require.Len(page.Logs, limit)
+							q.Cursor = page.Next
+						}
+						require.Equal(tc.expect, got, limit)
+					}
+
+					// without the logs
+					count, err := index.Count(context.Background(), tc.query)
+					require.NoError(err)
+					require.Equal(uint64(len(tc.expect)), count)
+					exists, err := index.Exists(context.Background(), tc.query)
+					require.NoError(err)
+					require.Equal(len(tc.expect) != 0, exists)
+				})
+			}
+
+			unmatched := Query{From: 0, To: 1000, Topics: [][]common.Hash{{topics[1]}, {hash.FakeHash(-1)}}}
+			count, err := index.Count(context.Background(), unmatched)
+			require.NoError(t, err)
+			require.Zero(t, count)
+			exists, err := index.Exists(context.Background(), unmatched)
+			require.NoError(t, err)
+			require.False(t, exists)
+		})
+	}
+}
+
+func TestIndexFindPageDescending(t *testing.T) {
+	logger.SetTestMode(t)
+
+	const N = 3000
+	addrs := []common.Address{randAddress(), randAddress()}
+	recs := make([]*types.Log, N)
+	for i := range recs {
+		recs[i] = &types.Log{
+			BlockNumber: uint64(i * 3),
+			BlockHash:   hash.FakeHash(int64(i)),
+			TxHash:      hash.FakeHash(int64(i % 7)),
+			Index:       uint(i % 5),
+			Address:     addrs[i%len(addrs)],
+			Topics:      []common.Hash{hash.FakeHash(int64(i % 3))},
+			Data:        []byte{},
+		}
+	}
+	index := newTestIndex()
+	require.NoError(t, index.Push(recs...))
+
+	expect := func(from, to uint64, addr common.Address) []*types.Log {
+		var logs []*types.Log
+		for i := len(recs) - 1; i >= 0; i-- {
+			rec := recs[i]
+			if from <= rec.BlockNumber && rec.BlockNumber <= to && rec.Address == addr {
+				logs = append(logs, rec)
+			}
+		}
+		return logs
+	}
+
+	for dsc, index := range map[string]Index{
+		"index":  index,
+		"pooled": &withThreadPool{index},
+	} {
+		t.Run(dsc, func(t *testing.T) {
+			require := require.New(t)
+
+			_, err := index.FindPage(context.Background(), Query{Addresses: addrs[:1], Descending: true})
+			require.ErrorIs(err, ErrNoUpperBlock)
+
+			for _, q := range []Query{
+				{From: 0, To: N * 3, Addresses: addrs[:1], Descending: true},
+				{From: 100, To: 5000, Addresses: addrs[1:], Descending: true},
+			} {
+				expect := expect(uint64(q.From), uint64(q.To), q.Addresses[0])
+
+				page, err := index.FindPage(context.Background(), q)
+				require.NoError(err)
+				require.Nil(page.Next)
+				require.Equal(expect, page.Logs)
+
+				for _, limit := range []int{1, 7, 100, len(expect)} {
+					q.Limit = limit
+					q.Cursor = nil
+					var got []*types.Log
+					for {
+						page, err := index.FindPage(context.Background(), q)
+						require.NoError(err)
+						got = append(got, page.Logs...)
+						if page.Next == nil {
+							break
+						}
+						require.Len(page.Logs, limit)
+						q.Cursor = page.Next
+					}
+					require.Equal(expect, got, limit)
+				}
+			}
+		})
+	}
+
+	t.Run("early termination", func(t *testing.T) {
+		require := require.New(t)
+
+		var searched []idx.Block
+		forEach := func(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onLog func(*types.Log) bool) error {
+			searched = append(searched, from, to)
+			return index.ForEachInBlocks(ctx, from, to, pattern, onLog)
+		}
+		page, err := findPage(context.Background(), Query{To: N * 3, Addresses: addrs[:1], Limit: 100, Descending: true}, forEach)
+		require.NoError(err)
+		require.Equal(expect(0, N*3, addrs[0])[:100], page.Logs)
+		require.NotNil(page.Next)
+		// 100 logs of the address are within the 1st blocks range
+		require.Equal([]idx.Block{N*3 - descendingWindow + 1, N * 3}, searched)
+
+		// the next ranges are searched till the query start
+		searched = nil
+		page, err = findPage(context.Background(), Query{From: 100, To: N * 3, Addresses: []common.Address{{}}, Limit: 100, Descending: true}, forEach)
+		require.NoError(err)
+		require.Empty(page.Logs)
+		require.Equal([]idx.Block{
+			N*3 - descendingWindow + 1, N * 3,
+			N*3 - 3*descendingWindow + 1, N*3 - descendingWindow,
+			N*3 - 7*descendingWindow + 1, N*3 - 3*descendingWindow,
+			100, N*3 - 7*descendingWindow,
+		}, searched)
+	})
+}
+
+func TestCursorJSON(t *testing.T) {
+	require := require.New(t)
+
+	cursor := Cursor(NewID(5, hash.FakeHash(1), 3))
+	b, err := json.Marshal(Query{Limit: 10, Cursor: &cursor})
+	require.NoError(err)
+
+	var q Query
+	require.NoError(json.Unmarshal(b, &q))
+	require.Equal(10, q.Limit)
+	require.Equal(cursor, *q.Cursor)
+
+	require.Error(json.Unmarshal([]byte(`{"cursor":"0x01"}`), &q))
+}
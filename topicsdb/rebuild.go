@@ -0,0 +1,93 @@
+package topicsdb
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+)
+
+var rebuildProgressKey = []byte("next")
+
+// ErrUnfinishedRebuild is returned by the queries of the index, which rebuild is interrupted.
+var ErrUnfinishedRebuild = errors.New("logs index rebuild isn't finished, stop the node and run `sonictool reindex-logs` to resume it")
+
+type rebuildTables struct {
+	// "next" -> next block of the interrupted index rebuild
+	Progress kvdb.Store `table:"M"`
+}
+
+func newRebuildTables(db kvdb.Store) *rebuildTables {
+	tt := &rebuildTables{}
+	table.MigrateTables(tt, db)
+	return tt
+}
+
+// Reset deletes the logs index of the db, including the index of the older releases.
+// The index must not be in use.
+func Reset(db kvdb.Store) error {
+	tt := &index{}
+	table.MigrateTables(&tt.table, db)
+	table.MigrateTables(&tt.legacy, db)
+
+	for _, t := range []struct {
+		store   kvdb.Store
+		keySize int // 0 for any
+	}{
+		{tt.table.Bitmap, 0},
+		{tt.table.Logrec, 0},
+		{tt.legacy.Topic, 0},
+		// the legacy records table is shared with other keys
+		{tt.legacy.Logrec, logrecKeySize},
+	} {
+		if err := deleteKeys(t.store, t.keySize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteKeys(store kvdb.Store, keySize int) error {
+	batch := store.NewBatch()
+	defer batch.Reset()
+
+	it := store.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if keySize != 0 && len(it.Key()) != keySize {
+			continue
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// RebuildProgress returns the next block of the interrupted index rebuild, 0 if there is no rebuild in progress.
+func RebuildProgress(db kvdb.Store) (idx.Block, error) {
+	buf, err := newRebuildTables(db).Progress.Get(rebuildProgressKey)
+	if err != nil || buf == nil {
+		return 0, err
+	}
+	return idx.BytesToBlock(buf), nil
+}
+
+// SetRebuildProgress records the next block of the index rebuild, 0 once the rebuild is finished.
+func SetRebuildProgress(db kvdb.Store, next idx.Block) error {
+	tt := newRebuildTables(db)
+	if next == 0 {
+		return tt.Progress.Delete(rebuildProgressKey)
+	}
+	return tt.Progress.Put(rebuildProgressKey, next.Bytes())
+}
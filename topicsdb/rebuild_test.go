@@ -0,0 +1,63 @@
+package topicsdb
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestReset(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	_, recs, _ := genTestData(100)
+	db := memorydb.New()
+	index := newIndex(db)
+	require.NoError(index.Push(recs[:50]...))
+	pushLegacy(t, db, recs[50:]...)
+	// a foreign key of the table shared with the legacy records
+	foreign := append([]byte("r"), idx.Block(1).Bytes()...)
+	require.NoError(db.Put(foreign, []byte{1}))
+
+	require.NoError(Reset(db))
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	require.True(it.Next())
+	require.Equal(foreign, it.Key())
+	require.False(it.Next())
+
+	got, err := newIndex(db).FindInBlocks(nil, 0, 0, [][]common.Hash{{recs[0].Address.Hash()}})
+	require.NoError(err)
+	require.Empty(got)
+}
+
+func TestRebuildProgress(t *testing.T) {
+	require := require.New(t)
+
+	db := memorydb.New()
+	next, err := RebuildProgress(db)
+	require.NoError(err)
+	require.Zero(next)
+
+	require.NoError(SetRebuildProgress(db, 7))
+	next, err = RebuildProgress(db)
+	require.NoError(err)
+	require.Equal(idx.Block(7), next)
+
+	// the progress survives the index reset
+	require.NoError(Reset(db))
+	next, err = RebuildProgress(db)
+	require.NoError(err)
+	require.Equal(idx.Block(7), next)
+
+	require.NoError(SetRebuildProgress(db, 0))
+	next, err = RebuildProgress(db)
+	require.NoError(err)
+	require.Zero(next)
+}
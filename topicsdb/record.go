@@ -1,47 +1,50 @@
 package topicsdb
 
 import (
-	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+var errMalformedLogrec = errors.New("malformed log record")
+
 type (
 	logrec struct {
-		ID          ID
-		topicsCount uint8
-		result      *types.Log
-		err         error
-
-		matched int
+		ID     ID
+		result *types.Log
 	}
 )
 
-func newLogrec(rec ID, topicCount uint8) *logrec {
-	return &logrec{
-		ID:          rec,
-		topicsCount: topicCount,
+// encodeLogrec serializes the record's data: topic_count, ordered topic_count topics, blockHash, address, data.
+func encodeLogrec(rec *types.Log) []byte {
+	buf := make([]byte, 0, uint8Size+common.HashLength*len(rec.Topics)+common.HashLength+common.AddressLength+len(rec.Data))
+	buf = append(buf, posToBytes(uint8(len(rec.Topics)))...)
+	for _, topic := range rec.Topics {
+		buf = append(buf, topic.Bytes()...)
 	}
+	buf = append(buf, rec.BlockHash.Bytes()...)
+	buf = append(buf, rec.Address.Bytes()...)
+	buf = append(buf, rec.Data...)
+	return buf
 }
 
-// fetch record's data.
-func (rec *logrec) fetch(
-	logrecTable kvdb.Reader,
-) {
-	r := &types.Log{
-		BlockNumber: rec.ID.BlockNumber(),
-		TxHash:      rec.ID.TxHash(),
-		Index:       rec.ID.Index(),
-		Topics:      make([]common.Hash, rec.topicsCount),
+// decodeLogrec restores the record from its ID and data.
+func decodeLogrec(id ID, buf []byte) (*logrec, error) {
+	if len(buf) < uint8Size {
+		return nil, errMalformedLogrec
+	}
+	topicsCount := int(bytesToPos(buf))
+	offset := uint8Size
+	if len(buf) < offset+common.HashLength*topicsCount+common.HashLength+common.AddressLength {
+		return nil, errMalformedLogrec
 	}
 
-	var (
-		buf    []byte
-		offset int
-	)
-	buf, rec.err = logrecTable.Get(rec.ID.Bytes())
-	if rec.err != nil {
-		return
+	r := &types.Log{
+		BlockNumber: id.BlockNumber(),
+		TxHash:      id.TxHash(),
+		Index:       id.Index(),
+		Topics:      make([]common.Hash, topicsCount),
 	}
 
 	// topics
@@ -57,6 +60,70 @@ func (rec *logrec) fetch(
 	offset += common.AddressLength
 	r.Data = buf[offset:]
 
-	rec.result = r
-	return
+	return &logrec{
+		ID:     id,
+		result: r,
+	}, nil
+}
+
+// matches returns true if the record is matched by pattern. 1st pattern element is an address.
+func (rec *logrec) matches(pattern [][]common.Hash) bool {
+	if len(rec.result.Topics) < len(pattern)-1 {
+		return false
+	}
+	for pos, variants := range pattern {
+		if len(variants) == 0 {
+			continue
+		}
+		var topic common.Hash
+		if pos == 0 {
+			topic = rec.result.Address.Hash()
+		} else {
+			topic = rec.result.Topics[pos-1]
+		}
+		if !matchesVariant(variants, topic) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLogrec returns true if the serialized record is matched by pattern. The record isn't decoded.
+func matchLogrec(buf []byte, pattern [][]common.Hash) (bool, error) {
+	if len(buf) < uint8Size {
+		return false, errMalformedLogrec
+	}
+	topicsCount := int(bytesToPos(buf))
+	addressOffset := uint8Size + common.HashLength*topicsCount + common.HashLength
+	if len(buf) < addressOffset+common.AddressLength {
+		return false, errMalformedLogrec
+	}
+	if topicsCount < len(pattern)-1 {
+		return false, nil
+	}
+	for pos, variants := range pattern {
+		if len(variants) == 0 {
+			continue
+		}
+		var topic common.Hash
+		if pos == 0 {
+			topic = common.BytesToHash(buf[addressOffset : addressOffset+common.AddressLength])
+		} else {
+			offset := uint8Size + common.HashLength*(pos-1)
+			topic = common.BytesToHash(buf[offset : offset+common.HashLength])
+		}
+		if !matchesVariant(variants, topic) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchesVariant(variants []common.Hash, topic common.Hash) bool {
+	for _, variant := range variants {
+		if variant == topic {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,118 @@
+package topicsdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// remoteIndex is an Index served by an out-of-process log indexer over the RPC (see API).
+// The indexer follows the chain on its own, so the pushed records are ignored.
+type remoteIndex struct {
+	url string
+
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+// NewRemote creates an Index instance forwarding the queries to the log indexer at the given RPC endpoint.
+// The connection is established lazily, so the indexer may be started after the node.
+func NewRemote(url string) Index {
+	return &remoteIndex{url: url}
+}
+
+func (r *remoteIndex) dial(ctx context.Context) (*rpc.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		client, err := rpc.DialContext(ctx, r.url)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+	return r.client, nil
+}
+
+func (r *remoteIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error) {
+	client, err := r.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = client.CallContext(ctx, &logs, APINamespace+"_findInBlocks", hexutil.Uint64(from), hexutil.Uint64(to), pattern)
+	return
+}
+
+func (r *remoteIndex) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	client, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	var blocks []hexutil.Uint64
+	err = client.CallContext(ctx, &blocks, APINamespace+"_matchedBlocks", hexutil.Uint64(from), hexutil.Uint64(to), pattern)
+	if err != nil {
+		return err
+	}
+	for _, n := range blocks {
+		if !onBlock(idx.Block(n)) {
+			break
+		}
+	}
+	return nil
+}
+
+func (r *remoteIndex) FindPage(ctx context.Context, q Query) (*Page, error) {
+	client, err := r.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var page *Page
+	err = client.CallContext(ctx, &page, APINamespace+"_findPage", q)
+	return page, err
+}
+
+func (r *remoteIndex) Count(ctx context.Context, q Query) (uint64, error) {
+	client, err := r.dial(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var count hexutil.Uint64
+	err = client.CallContext(ctx, &count, APINamespace+"_count", q)
+	return uint64(count), err
+}
+
+func (r *remoteIndex) Exists(ctx context.Context, q Query) (bool, error) {
+	client, err := r.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	var exists bool
+	err = client.CallContext(ctx, &exists, APINamespace+"_exists", q)
+	return exists, err
+}
+
+func (r *remoteIndex) Push(recs ...*types.Log) error {
+	return nil
+}
+
+func (r *remoteIndex) Delete(recs ...*types.Log) error {
+	return nil
+}
+
+func (r *remoteIndex) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+}
+
+func (r *remoteIndex) WrapTablesAsBatched() (unwrap func()) {
+	return func() {}
+}
@@ -0,0 +1,85 @@
+package topicsdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestRemoteIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	var (
+		hash1 = common.BytesToHash([]byte("topic1"))
+		addr  = randAddress()
+	)
+	testdata := []*types.Log{{
+		BlockNumber: 1,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+		Data:        []byte{},
+	}, {
+		BlockNumber: 2,
+		Address:     addr,
+		Topics:      []common.Hash{hash1, hash1},
+		Data:        []byte{},
+		Index:       1,
+	}, {
+		BlockNumber: 2,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+		Data:        []byte{},
+		Index:       2,
+	}}
+
+	local := newTestIndex()
+	require.NoError(local.Push(testdata...))
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	require.NoError(srv.RegisterName(APINamespace, NewAPI(local)))
+	remote := &remoteIndex{client: rpc.DialInProc(srv)}
+	defer remote.Close()
+
+	// the pushed records are ignored, the indexer follows the chain on its own
+	require.NoError(remote.Push(&types.Log{BlockNumber: 3, Address: addr, Topics: []common.Hash{hash1}}))
+
+	got, err := remote.FindInBlocks(context.Background(), 0, 0xffffffff, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Equal(len(testdata), len(got))
+
+	var blocks []idx.Block
+	err = remote.CountInBlocks(context.Background(), 0, 0xffffffff, [][]common.Hash{{}, {hash1}}, func(n idx.Block) bool {
+		blocks = append(blocks, n)
+		return true
+	})
+	require.NoError(err)
+	require.ElementsMatch([]idx.Block{1, 2, 2}, blocks)
+
+	page, err := remote.FindPage(context.Background(), Query{To: 0xffffffff, Addresses: []common.Address{addr}, Limit: 2})
+	require.NoError(err)
+	require.Len(page.Logs, 2)
+	require.NotNil(page.Next)
+	page, err = remote.FindPage(context.Background(), Query{To: 0xffffffff, Addresses: []common.Address{addr}, Limit: 2, Cursor: page.Next})
+	require.NoError(err)
+	require.Len(page.Logs, 1)
+	require.Nil(page.Next)
+
+	count, err := remote.Count(context.Background(), Query{To: 0xffffffff, Topics: [][]common.Hash{{}, {hash1}}})
+	require.NoError(err)
+	require.Equal(uint64(1), count)
+	exists, err := remote.Exists(context.Background(), Query{From: 2, To: 0xffffffff, Addresses: []common.Address{addr}})
+	require.NoError(err)
+	require.True(exists)
+
+	_, err = remote.FindInBlocks(context.Background(), 0, 0xffffffff, [][]common.Hash{})
+	require.Error(err)
+}
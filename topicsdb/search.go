@@ -0,0 +1,218 @@
+package topicsdb
+
+import (
+	"context"
+	"math"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// logHandler is called for each matched record. The record has only the ID, unless the search decodes the records.
+type logHandler func(rec *logrec) (gonext bool, err error)
+
+// bitmapCursor iterates over the buckets of a pattern variant bitmaps.
+type bitmapCursor struct {
+	it     kvdb.Iterator
+	valid  bool
+	bucket uint64
+}
+
+func newBitmapCursor(table kvdb.Iteratee, variant common.Hash, pos uint8, start uint64) *bitmapCursor {
+	prefix := append(variant.Bytes(), posToBytes(pos)...)
+	c := &bitmapCursor{
+		it: table.NewIterator(prefix, uintToBytes(start)),
+	}
+	c.next()
+	return c
+}
+
+func (c *bitmapCursor) next() {
+	c.valid = c.it.Next()
+	if c.valid {
+		c.bucket = extractBucket(c.it.Key())
+	}
+}
+
+// seek skips the buckets before the given one.
+func (c *bitmapCursor) seek(bucket uint64) {
+	for c.valid && c.bucket < bucket {
+		c.next()
+	}
+}
+
+// searchBitmaps intersects the bitmaps of the pattern positions, each of which is the union of the position variants,
+// and matches the log records of the found blocks by pattern. The blocks are matched in the ascending order.
+// The matched records are decoded only if decode is set, it's not needed to count them.
+func (tt *index) searchBitmaps(ctx context.Context, pattern [][]common.Hash, blockStart, blockEnd uint64, decode bool, onMatched logHandler, onDbIterator func()) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tt.parallelism > 1 && countPositions(pattern) > 1 {
+		return tt.searchBitmapsParallel(ctx, pattern, blockStart, blockEnd, decode, onMatched, onDbIterator)
+	}
+
+	var positions [][]*bitmapCursor
+	defer func() {
+		for _, cursors := range positions {
+			for _, c := range cursors {
+				c.it.Release()
+			}
+		}
+	}()
+	for pos, variants := range pattern {
+		if len(variants) == 0 {
+			continue
+		}
+		cursors := make([]*bitmapCursor, 0, len(variants))
+		for _, variant := range variants {
+			onDbIterator()
+			cursors = append(cursors, newBitmapCursor(tt.table.Bitmap, variant, uint8(pos), bucketOf(blockStart)))
+		}
+		positions = append(positions, cursors)
+	}
+
+	lastBucket := uint64(math.MaxUint64)
+	if blockEnd > 0 {
+		lastBucket = bucketOf(blockEnd)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		bucket, ok, err := alignCursors(positions)
+		if err != nil || !ok || bucket > lastBucket {
+			return err
+		}
+		blocks, err := intersectBucket(positions, bucket)
+		if err != nil {
+			return err
+		}
+		for _, v := range blocks.values() {
+			block := blockOf(bucket, v)
+			if block < blockStart {
+				continue
+			}
+			if blockEnd > 0 && block > blockEnd {
+				return nil
+			}
+			gonext, err := tt.scanBlock(ctx, block, pattern, decode, onMatched)
+			if err != nil || !gonext {
+				return err
+			}
+		}
+	}
+}
+
+// countPositions returns the number of the pattern positions with variants.
+func countPositions(pattern [][]common.Hash) int {
+	n := 0
+	for _, variants := range pattern {
+		if len(variants) != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// alignCursors moves the cursors to the first bucket, which is present in every pattern position.
+func alignCursors(positions [][]*bitmapCursor) (bucket uint64, ok bool, err error) {
+	for {
+		var target uint64
+		for _, cursors := range positions {
+			min, found := uint64(0), false
+			for _, c := range cursors {
+				if err = c.it.Error(); err != nil {
+					return
+				}
+				if c.valid && (!found || c.bucket < min) {
+					min, found = c.bucket, true
+				}
+			}
+			if !found {
+				return
+			}
+			if min > target {
+				target = min
+			}
+		}
+
+		aligned := true
+		for _, cursors := range positions {
+			present := false
+			for _, c := range cursors {
+				c.seek(target)
+				present = present || (c.valid && c.bucket == target)
+			}
+			aligned = aligned && present
+		}
+		if aligned {
+			return target, true, nil
+		}
+	}
+}
+
+// intersectBucket returns the blocks of the bucket matched by every pattern position, the cursors are moved past the bucket.
+func intersectBucket(positions [][]*bitmapCursor, bucket uint64) (*bitmap, error) {
+	var res *bitmap
+	for _, cursors := range positions {
+		union := &bitmap{}
+		for _, c := range cursors {
+			if !c.valid || c.bucket != bucket {
+				continue
+			}
+			b, err := decodeBitmap(c.it.Value())
+			if err != nil {
+				return nil, err
+			}
+			union.union(b)
+			c.next()
+		}
+		if res == nil {
+			res = union
+		} else {
+			res = res.intersect(union)
+		}
+	}
+	return res, nil
+}
+
+// scanBlock matches the log records of the block by pattern.
+func (tt *index) scanBlock(ctx context.Context, block uint64, pattern [][]common.Hash, decode bool, onMatched logHandler) (gonext bool, err error) {
+	it := tt.table.Logrec.NewIterator(uintToBytes(block), nil)
+	defer it.Release()
+	for it.Next() {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if len(it.Key()) != logrecKeySize {
+			continue
+		}
+		var id ID
+		copy(id[:], it.Key())
+		rec := &logrec{ID: id}
+		if decode {
+			rec, err = decodeLogrec(id, common.CopyBytes(it.Value()))
+			if err != nil {
+				return
+			}
+			if !rec.matches(pattern) {
+				continue
+			}
+		} else {
+			var matched bool
+			matched, err = matchLogrec(it.Value(), pattern)
+			if err != nil {
+				return
+			}
+			if !matched {
+				continue
+			}
+		}
+		gonext, err = onMatched(rec)
+		if err != nil || !gonext {
+			return
+		}
+	}
+	return true, it.Error()
+}
@@ -2,120 +2,192 @@ package topicsdb
 
 import (
 	"context"
+	"math"
+	"sort"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
-type logHandler func(rec *logrec) (gonext bool, err error)
+// parallelWindow is the number of the buckets of each position variant loaded at once by the parallel search
+const parallelWindow = 16
 
-func (tt *index) searchParallel(ctx context.Context, pattern [][]common.Hash, blockStart, blockEnd uint64, onMatched logHandler, onDbIterator func()) error {
-	if ctx == nil {
-		ctx = context.Background()
+// positionBuckets are the union bitmaps of the next buckets of a pattern position.
+type positionBuckets struct {
+	bitmaps map[uint64]*bitmap
+	buckets []uint64 // ascending
+	// upTo is the last bucket loaded by every variant, so the union of the buckets up to it is complete.
+	// It's math.MaxUint64 if the variants have no more buckets.
+	upTo uint64
+}
+
+// searchBitmapsParallel is searchBitmaps, which scans the pattern positions concurrently. The positions are scanned
+// by windows of the buckets, the bitmaps of a window are intersected once every position is loaded.
+// The blocks are matched in the ascending order, the same as by the sequential search.
+func (tt *index) searchBitmapsParallel(ctx context.Context, pattern [][]common.Hash, blockStart, blockEnd uint64, decode bool, onMatched logHandler, onDbIterator func()) error {
+	var positions []uint8
+	for pos, variants := range pattern {
+		if len(variants) == 0 {
+			continue
+		}
+		positions = append(positions, uint8(pos))
+		for range variants {
+			onDbIterator()
+		}
 	}
 
-	var (
-		syncing      = newSynchronizator()
-		mu           sync.Mutex
-		foundByBlock = make(map[uint64]map[ID]*logrec)
-	)
+	lastBucket := uint64(math.MaxUint64)
+	if blockEnd > 0 {
+		lastBucket = bucketOf(blockEnd)
+	}
+	loaded := make([]*positionBuckets, len(positions))
+	for from := bucketOf(blockStart); from <= lastBucket; {
+		err := parallelDo(len(positions), tt.parallelism, func(i int) (err error) {
+			pos := positions[i]
+			loaded[i], err = tt.loadBuckets(ctx, pattern[pos], pos, from)
+			return
+		})
+		if err != nil {
+			return err
+		}
 
-	aggregator := func(pos, num int) logHandler {
-		return func(rec *logrec) (gonext bool, err error) {
-			if rec == nil {
-				syncing.FinishThread(pos, num)
-				return
+		// the buckets up to the window end are loaded completely by every position
+		windowEnd := lastBucket
+		for _, p := range loaded {
+			if p.upTo < windowEnd {
+				windowEnd = p.upTo
 			}
-
-			err = ctx.Err()
-			if err != nil {
-				return
+		}
+		for _, bucket := range loaded[0].buckets {
+			if bucket > windowEnd {
+				break
 			}
-
-			block := rec.ID.BlockNumber()
-			if blockEnd > 0 && block > blockEnd {
-				return
+			blocks := loaded[0].bitmaps[bucket]
+			for _, p := range loaded[1:] {
+				b, ok := p.bitmaps[bucket]
+				if !ok {
+					blocks = nil
+					break
+				}
+				blocks = blocks.intersect(b)
 			}
-			if rec.topicsCount < uint8(len(pattern)-1) {
-				gonext = true
-				return
+			if blocks == nil {
+				continue
 			}
-
-			var prevBlock uint64
-			prevBlock, gonext = syncing.GoNext(block)
-			if !gonext {
-				return
+			for _, v := range blocks.values() {
+				block := blockOf(bucket, v)
+				if block < blockStart {
+					continue
+				}
+				if blockEnd > 0 && block > blockEnd {
+					return nil
+				}
+				gonext, err := tt.scanBlock(ctx, block, pattern, decode, onMatched)
+				if err != nil || !gonext {
+					return err
+				}
 			}
+		}
+		if windowEnd >= lastBucket {
+			return nil
+		}
 
-			mu.Lock()
-			defer mu.Unlock()
-
-			if prevBlock > 0 {
-				delete(foundByBlock, prevBlock)
+		// skip the buckets which are known to be missing in some position
+		from = windowEnd + 1
+		for _, p := range loaded {
+			i := sort.Search(len(p.buckets), func(i int) bool {
+				return p.buckets[i] > windowEnd
+			})
+			if i == len(p.buckets) && p.upTo == math.MaxUint64 {
+				// no more buckets in the position
+				return nil
 			}
-
-			found, ok := foundByBlock[block]
-			if !ok {
-				found = make(map[ID]*logrec)
-				foundByBlock[block] = found
+			// the buckets after the last one loaded by every variant are unknown
+			next := uint64(math.MaxUint64)
+			if p.upTo != math.MaxUint64 {
+				next = p.upTo + 1
 			}
-
-			if before, ok := found[rec.ID]; ok {
-				rec = before
-			} else {
-				found[rec.ID] = rec
+			if i < len(p.buckets) && p.buckets[i] < next {
+				next = p.buckets[i]
 			}
-			rec.matched++
-			if rec.matched == syncing.PositionsCount() {
-				gonext, err = onMatched(rec)
-				if !gonext {
-					syncing.Halt()
-					return
-				}
+			if next > from {
+				from = next
 			}
-
-			return
 		}
 	}
+	return nil
+}
 
-	// start the threads
-	var preparing sync.WaitGroup
-	preparing.Add(1)
-	for pos := range pattern {
-		if len(pattern[pos]) == 0 {
-			continue
+// loadBuckets loads the union bitmaps of the position variants, up to parallelWindow buckets of each variant.
+func (tt *index) loadBuckets(ctx context.Context, variants []common.Hash, pos uint8, from uint64) (*positionBuckets, error) {
+	res := &positionBuckets{
+		bitmaps: make(map[uint64]*bitmap),
+		upTo:    math.MaxUint64,
+	}
+	for _, variant := range variants {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		for i, variant := range pattern[pos] {
-			syncing.StartThread(pos, i)
-			go func(pos, i int, variant common.Hash) {
-				onMatched := aggregator(pos, i)
-				preparing.Wait()
-				tt.scanPatternVariant(uint8(pos), variant, blockStart, onMatched, onDbIterator)
-			}(pos, i, variant)
+		c := newBitmapCursor(tt.table.Bitmap, variant, pos, from)
+		n := 0
+		for ; c.valid && n < parallelWindow; n++ {
+			b, err := decodeBitmap(c.it.Value())
+			if err != nil {
+				c.it.Release()
+				return nil, err
+			}
+			if union, ok := res.bitmaps[c.bucket]; ok {
+				union.union(b)
+			} else {
+				res.bitmaps[c.bucket] = b
+				res.buckets = append(res.buckets, c.bucket)
+			}
+			if n == parallelWindow-1 && c.bucket < res.upTo {
+				// the variant may have more buckets
+				res.upTo = c.bucket
+			}
+			c.next()
+		}
+		err := c.it.Error()
+		c.it.Release()
+		if err != nil {
+			return nil, err
 		}
 	}
-	preparing.Done()
-
-	syncing.WaitForThreads()
-
-	return ctx.Err()
+	sort.Slice(res.buckets, func(i, j int) bool {
+		return res.buckets[i] < res.buckets[j]
+	})
+	return res, nil
 }
 
-func (tt *index) scanPatternVariant(pos uint8, variant common.Hash, start uint64, onMatched logHandler, onDbIterator func()) {
-	prefix := append(variant.Bytes(), posToBytes(pos)...)
-
-	onDbIterator()
-	it := tt.table.Topic.NewIterator(prefix, uintToBytes(start))
-	defer it.Release()
-	for it.Next() {
-		id := extractLogrecID(it.Key())
-		topicCount := bytesToPos(it.Value())
-		rec := newLogrec(id, topicCount)
-
-		gonext, _ := onMatched(rec)
-		if !gonext {
-			break
+// parallelDo calls fn for each of n items, at most by the given number of goroutines, and returns the first error.
+func parallelDo(n, parallelism int, fn func(i int) error) error {
+	if parallelism > n {
+		parallelism = n
+	}
+	var (
+		wg   sync.WaitGroup
+		next = make(chan int)
+		errs = make([]error, n)
+	)
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
 	}
-	onMatched(nil)
+	return nil
 }
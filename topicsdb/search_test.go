@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
@@ -50,3 +51,65 @@ func BenchmarkSearch(b *testing.B) {
 		})
 	}
 }
+
+func TestIndexSearchParallel(t *testing.T) {
+	var (
+		dense  = common.BytesToHash([]byte("dense"))
+		sparse = common.BytesToHash([]byte("sparse"))
+		other  = common.BytesToHash([]byte("other"))
+		addr1  = randAddress()
+		addr2  = randAddress()
+	)
+	db := memorydb.New()
+	sequential := newIndex(db)
+	sequential.parallelism = 1
+	parallel := newIndex(db)
+	parallel.parallelism = 4
+
+	// the records span much more buckets than a window of the parallel search
+	for i := uint64(0); i < 3*parallelWindow*4; i++ {
+		rec := &types.Log{
+			BlockNumber: i * bucketSize / 4,
+			Address:     addr1,
+			Topics:      []common.Hash{dense, other},
+		}
+		if i%2 == 1 {
+			rec.Address = addr2
+		}
+		if i%7 == 0 {
+			rec.Topics[1] = sparse
+		}
+		require.NoError(t, sequential.Push(rec))
+	}
+
+	for name, pattern := range map[string][][]common.Hash{
+		"address and dense":     {{addr1.Hash()}, {dense}},
+		"address and sparse":    {{addr1.Hash()}, {}, {sparse}},
+		"all positions":         {{addr1.Hash(), addr2.Hash()}, {dense}, {sparse, other}},
+		"dense and sparse":      {{}, {dense}, {sparse}},
+		"unmatched combination": {{addr2.Hash()}, {sparse}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			for _, r := range [][2]idx.Block{{0, 0}, {1, 3 * bucketSize}, {bucketSize, 40 * bucketSize}} {
+				exp, err := sequential.FindInBlocks(nil, r[0], r[1], pattern)
+				require.NoError(err)
+				got, err := parallel.FindInBlocks(nil, r[0], r[1], pattern)
+				require.NoError(err)
+				require.Equal(exp, got)
+			}
+
+			// the early stop is the same
+			var exp, got []idx.Block
+			require.NoError(sequential.CountInBlocks(nil, 0, 0, pattern, func(n idx.Block) bool {
+				exp = append(exp, n)
+				return len(exp) < 5
+			}))
+			require.NoError(parallel.CountInBlocks(nil, 0, 0, pattern, func(n idx.Block) bool {
+				got = append(got, n)
+				return len(got) < 5
+			}))
+			require.Equal(exp, got)
+		})
+	}
+}
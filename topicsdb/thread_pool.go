@@ -40,21 +40,63 @@ func (tt *withThreadPool) ForEachInBlocks(ctx context.Context, from, to idx.Bloc
 		ctx = context.Background()
 	}
 
-	pattern, err := limitPattern(pattern)
-	if err != nil {
-		return err
+	onMatched := func(rec *logrec) (gonext bool, err error) {
+		gonext = onLog(rec.result)
+		return
+	}
+
+	return tt.forEachMatched(ctx, pattern, from, to, true, onMatched)
+}
+
+// CountInBlocks calls onBlock for each log record of block range matched by pattern.
+// 1st pattern element is an address. The calls are serialized.
+func (tt *withThreadPool) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	return tt.forEachID(ctx, from, to, pattern, func(id ID) bool {
+		return onBlock(idx.Block(id.BlockNumber()))
+	})
+}
+
+// forEachID calls onID for each log record of block range matched by pattern, the records aren't decoded.
+// 1st pattern element is an address.
+func (tt *withThreadPool) forEachID(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onID func(ID) (gonext bool)) error {
+	if 0 < to && to < from {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
 	onMatched := func(rec *logrec) (gonext bool, err error) {
-		rec.fetch(tt.table.Logrec)
-		if rec.err != nil {
-			err = rec.err
-			return
-		}
-		gonext = onLog(rec.result)
+		gonext = onID(rec.ID)
 		return
 	}
 
+	return tt.forEachMatched(ctx, pattern, from, to, false, onMatched)
+}
+
+// FindPage returns the page of the query results, which starts after the query cursor.
+func (tt *withThreadPool) FindPage(ctx context.Context, q Query) (*Page, error) {
+	return findPage(ctx, q, tt.ForEachInBlocks)
+}
+
+// Count returns the number of the logs matching the query, the logs aren't decoded.
+func (tt *withThreadPool) Count(ctx context.Context, q Query) (uint64, error) {
+	return countLogs(ctx, q, tt.forEachID)
+}
+
+// Exists reports whether there is a log matching the query, the logs aren't decoded.
+func (tt *withThreadPool) Exists(ctx context.Context, q Query) (bool, error) {
+	return logsExist(ctx, q, tt.forEachID)
+}
+
+// forEachMatched runs the search of the pattern variants, the DB iterators in use are limited by the threads pool.
+func (tt *withThreadPool) forEachMatched(ctx context.Context, pattern [][]common.Hash, from, to idx.Block, decode bool, onMatched logHandler) error {
+	pattern, err := limitPattern(pattern)
+	if err != nil {
+		return err
+	}
+
 	splitby := 0
 	parallels := 0
 	for i := range pattern {
@@ -88,7 +130,7 @@ func (tt *withThreadPool) ForEachInBlocks(ctx context.Context, from, to idx.Bloc
 
 		pattern[splitby] = rest[:got-parallels]
 		rest = rest[got-parallels:]
-		err = tt.searchParallel(ctx, pattern, uint64(from), uint64(to), onMatched, onDbIterator)
+		err = tt.searchBitmaps(ctx, pattern, uint64(from), uint64(to), decode, onMatched, onDbIterator)
 		if err != nil {
 			return err
 		}
@@ -20,15 +20,41 @@ var (
 
 type Index interface {
 	FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error)
+	CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error
+	FindPage(ctx context.Context, q Query) (*Page, error)
+	// Count and Exists match the query logs without decoding them, the query limit, cursor and order are ignored.
+	Count(ctx context.Context, q Query) (uint64, error)
+	Exists(ctx context.Context, q Query) (bool, error)
 	Push(recs ...*types.Log) error
+	Delete(recs ...*types.Log) error
 	Close()
 
 	WrapTablesAsBatched() (unwrap func())
 }
 
+// Config is the config of the logs index.
+type Config struct {
+	// Parallelism is the number of the topic positions and the address scanned concurrently
+	// by a search of several positions, 1 scans them sequentially.
+	Parallelism int
+}
+
+// DefaultConfig returns the default config of the logs index.
+func DefaultConfig() Config {
+	return Config{
+		Parallelism: 4,
+	}
+}
+
 // NewWithThreadPool creates an Index instance consuming a limited number of threads.
 func NewWithThreadPool(db kvdb.Store) Index {
+	return NewWithThreadPoolConfig(db, DefaultConfig())
+}
+
+// NewWithThreadPoolConfig creates an Index instance with the given config consuming a limited number of threads.
+func NewWithThreadPoolConfig(db kvdb.Store, cfg Config) Index {
 	tt := newIndex(db)
+	tt.parallelism = cfg.Parallelism
 	return &withThreadPool{tt}
 }
 
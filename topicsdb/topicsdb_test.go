@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -240,6 +241,63 @@ func TestIndexSearchShortCircuits(t *testing.T) {
 	}
 }
 
+func TestIndexSearchCancel(t *testing.T) {
+	logger.SetTestMode(t)
+
+	topics, recs, _ := genTestData(1000)
+	index := newTestIndex()
+	require.NoError(t, index.Push(recs...))
+
+	pooled := withThreadPool{index}
+
+	for dsc, method := range map[string]func(context.Context, idx.Block, idx.Block, [][]common.Hash, func(*types.Log) bool) error{
+		"index":  index.ForEachInBlocks,
+		"pooled": pooled.ForEachInBlocks,
+	} {
+		t.Run(dsc, func(t *testing.T) {
+			pattern := [][]common.Hash{{}, {topics[0], topics[1]}}
+
+			t.Run("cancelled", func(t *testing.T) {
+				require := require.New(t)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				got := 0
+				err := method(ctx, 0, 1000, pattern, func(*types.Log) bool {
+					got++
+					return true
+				})
+				require.ErrorIs(err, context.Canceled)
+				require.Zero(got)
+			})
+
+			t.Run("cancelled during search", func(t *testing.T) {
+				require := require.New(t)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				got := 0
+				err := method(ctx, 0, 1000, pattern, func(*types.Log) bool {
+					got++
+					cancel()
+					return true
+				})
+				require.ErrorIs(err, context.Canceled)
+				require.Equal(1, got)
+			})
+
+			t.Run("deadline", func(t *testing.T) {
+				require := require.New(t)
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				err := method(ctx, 0, 1000, pattern, func(*types.Log) bool {
+					<-ctx.Done()
+					return true
+				})
+				require.ErrorIs(err, context.DeadlineExceeded)
+			})
+		})
+	}
+}
+
 func TestIndexSearchSingleVariant(t *testing.T) {
 	logger.SetTestMode(t)
 
@@ -363,6 +421,138 @@ func TestIndexSearchSimple(t *testing.T) {
 
 }
 
+func TestIndexDelete(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	var (
+		hash1 = common.BytesToHash([]byte("topic1"))
+		addr  = randAddress()
+	)
+	testdata := []*types.Log{{
+		BlockNumber: 1,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+	}, {
+		BlockNumber: 2,
+		Address:     addr,
+		Topics:      []common.Hash{hash1, hash1},
+	}}
+
+	index := newTestIndex()
+	require.NoError(index.Push(testdata...))
+	require.NoError(index.Delete(testdata[0]))
+
+	got, err := index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Equal(1, len(got))
+	require.Equal(uint64(2), got[0].BlockNumber)
+
+	got, err = index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{}, {hash1}})
+	require.NoError(err)
+	require.Equal(1, len(got))
+
+	// the records are deleted along with their index
+	it := index.table.Bitmap.NewIterator(nil, nil)
+	defer it.Release()
+	keys := 0
+	for it.Next() {
+		keys++
+		b, err := decodeBitmap(it.Value())
+		require.NoError(err)
+		require.Equal([]uint16{2}, b.values())
+	}
+	require.Equal(3, keys)
+}
+
+func TestIndexDeleteBatched(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	var (
+		hash1 = common.BytesToHash([]byte("topic1"))
+		addr  = randAddress()
+	)
+	testdata := []*types.Log{{
+		BlockNumber: 1,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+	}, {
+		BlockNumber: 2,
+		Address:     addr,
+		Topics:      []common.Hash{hash1},
+	}}
+
+	index := newTestIndex()
+	require.NoError(index.Push(testdata...))
+	unwrap := index.WrapTablesAsBatched()
+	require.NoError(index.Delete(testdata[0]))
+	unwrap()
+
+	// the bits of the deleted record are kept in the batched mode
+	b, err := index.getBitmap(string(bitmapKey(hash1, 1, 0)))
+	require.NoError(err)
+	require.Equal([]uint16{1, 2}, b.values())
+
+	// the stale bits don't match the deleted record
+	ctx := context.Background()
+	for _, pattern := range [][][]common.Hash{{{addr.Hash()}}, {{}, {hash1}}} {
+		got, err := index.FindInBlocks(ctx, 0, 0xffffffff, pattern)
+		require.NoError(err)
+		require.Len(got, 1)
+		require.Equal(uint64(2), got[0].BlockNumber)
+
+		var blocks []idx.Block
+		require.NoError(index.CountInBlocks(ctx, 0, 0xffffffff, pattern, func(n idx.Block) bool {
+			blocks = append(blocks, n)
+			return true
+		}))
+		require.Equal([]idx.Block{2}, blocks)
+	}
+	q := Query{To: 0xffffffff, Addresses: []common.Address{addr}, Topics: [][]common.Hash{{hash1}}}
+	page, err := index.FindPage(ctx, q)
+	require.NoError(err)
+	require.Len(page.Logs, 1)
+	require.Equal(uint64(2), page.Logs[0].BlockNumber)
+	count, err := index.Count(ctx, q)
+	require.NoError(err)
+	require.Equal(uint64(1), count)
+
+	q = Query{From: 1, To: 1, Addresses: []common.Address{addr}}
+	exists, err := index.Exists(ctx, q)
+	require.NoError(err)
+	require.False(exists)
+	count, err = index.Count(ctx, q)
+	require.NoError(err)
+	require.Zero(count)
+}
+
+func TestIndexBatched(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	topics, recs, _ := genTestData(maxPendingBitmaps)
+	index := newTestIndex()
+
+	unwrap := index.WrapTablesAsBatched()
+	for _, rec := range recs {
+		require.NoError(index.Push(rec))
+	}
+	unwrap()
+
+	for _, topic := range topics {
+		exp := 0
+		for _, rec := range recs {
+			if rec.Topics[0] == topic {
+				exp++
+			}
+		}
+		got, err := index.FindInBlocks(nil, 0, 0, [][]common.Hash{{}, {topic}})
+		require.NoError(err)
+		require.Equal(exp, len(got))
+	}
+}
+
 func TestMaxTopicsCount(t *testing.T) {
 	logger.SetTestMode(t)
 
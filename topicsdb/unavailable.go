@@ -0,0 +1,56 @@
+package topicsdb
+
+import (
+	"context"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// unavailableIndex is an Index, which keeps indexing the records, but refuses the queries
+// while its content is incomplete, e.g. until the index of an older release is migrated.
+type unavailableIndex struct {
+	Index
+	err error
+}
+
+// NewUnavailable wraps the index, so its queries return the given error.
+// The records are still pushed into and deleted from the underlying index.
+func NewUnavailable(index Index, err error) Index {
+	return &unavailableIndex{index, err}
+}
+
+// UnwrapUnavailable returns the underlying index, if the index refuses the queries with the given error,
+// e.g. once the index of an older release is migrated.
+func UnwrapUnavailable(index Index, err error) Index {
+	if u, ok := index.(*unavailableIndex); ok && u.err == err {
+		return u.Index
+	}
+	return index
+}
+
+func (u *unavailableIndex) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error) {
+	return nil, u.err
+}
+
+func (u *unavailableIndex) CountInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onBlock func(idx.Block) (gonext bool)) error {
+	return u.err
+}
+
+func (u *unavailableIndex) FindPage(ctx context.Context, q Query) (*Page, error) {
+	return nil, u.err
+}
+
+func (u *unavailableIndex) Count(ctx context.Context, q Query) (uint64, error) {
+	return 0, u.err
+}
+
+func (u *unavailableIndex) Exists(ctx context.Context, q Query) (bool, error) {
+	return false, u.err
+}
+
+// Flush waits until the records pushed into the underlying index are indexed.
+func (u *unavailableIndex) Flush() error {
+	return Flush(u.Index)
+}
@@ -0,0 +1,43 @@
+package topicsdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestUnavailableIndex(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	addr := randAddress()
+	errIncomplete := errors.New("incomplete")
+	underlying := NewAsync(NewWithThreadPool(memorydb.New()), 2)
+	index := NewUnavailable(underlying, errIncomplete)
+
+	// the records are indexed, but the queries are refused
+	require.NoError(index.Push(&types.Log{BlockNumber: 1, Address: addr}))
+	require.NoError(Flush(index))
+	_, err := index.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{addr.Hash()}})
+	require.ErrorIs(err, errIncomplete)
+	_, err = index.FindPage(context.Background(), Query{Addresses: []common.Address{addr}})
+	require.ErrorIs(err, errIncomplete)
+	_, err = index.Exists(context.Background(), Query{Addresses: []common.Address{addr}})
+	require.ErrorIs(err, errIncomplete)
+
+	got, err := underlying.FindInBlocks(context.Background(), 0, 10, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Len(got, 1)
+
+	// the queries are served by the underlying index once it's complete
+	require.Equal(index, UnwrapUnavailable(index, ErrLegacyIndex))
+	require.Equal(underlying, UnwrapUnavailable(index, errIncomplete))
+	index.Close()
+}
@@ -0,0 +1,155 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// callFrame is a call of the callTracer result, the fields are in the order of the JS callTracer.
+type callFrame struct {
+	Type    string      `json:"type"`
+	From    string      `json:"from"`
+	To      string      `json:"to,omitempty"`
+	Value   string      `json:"value,omitempty"`
+	Gas     string      `json:"gas"`
+	GasUsed string      `json:"gasUsed"`
+	Input   string      `json:"input"`
+	Output  string      `json:"output,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Calls   []callFrame `json:"calls,omitempty"`
+}
+
+type callTracerConfig struct {
+	// OnlyTopCall skips the inner calls
+	OnlyTopCall bool `json:"onlyTopCall"`
+}
+
+// callTracer is the native implementation of the callTracer, which reports the tree of the calls of a transaction.
+type callTracer struct {
+	interruption
+	env       *vm.EVM
+	config    callTracerConfig
+	callstack []callFrame
+}
+
+func newCallTracer(config json.RawMessage) (NativeTracer, error) {
+	t := &callTracer{
+		callstack: make([]callFrame, 1),
+	}
+	if len(config) != 0 {
+		if err := json.Unmarshal(config, &t.config); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.callstack[0] = callFrame{
+		Type:  vm.CALL.String(),
+		From:  addrToHex(from),
+		To:    addrToHex(to),
+		Value: hexutil.EncodeBig(valueOrZero(value)),
+		Gas:   hexutil.EncodeUint64(gas),
+		Input: hexutil.Encode(input),
+	}
+	if create {
+		t.callstack[0].Type = vm.CREATE.String()
+	}
+}
+
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	root := &t.callstack[0]
+	root.GasUsed = hexutil.EncodeUint64(gasUsed)
+	if err == nil {
+		root.Output = hexutil.Encode(output)
+		return
+	}
+	root.Error = err.Error()
+	// the output of a reverted call is the revert reason
+	if errors.Is(err, vm.ErrExecutionReverted) && len(output) != 0 {
+		root.Output = hexutil.Encode(output)
+	}
+}
+
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.config.OnlyTopCall {
+		return
+	}
+	if t.isInterrupted() {
+		t.env.Cancel()
+		return
+	}
+	t.callstack = append(t.callstack, callFrame{
+		Type:  typ.String(),
+		From:  addrToHex(from),
+		To:    addrToHex(to),
+		Value: bigToHex(value),
+		Gas:   hexutil.EncodeUint64(gas),
+		Input: hexutil.Encode(input),
+	})
+}
+
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.config.OnlyTopCall {
+		return
+	}
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	size--
+
+	call.GasUsed = hexutil.EncodeUint64(gasUsed)
+	if err == nil {
+		call.Output = hexutil.Encode(output)
+	} else {
+		call.Error = err.Error()
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			call.To = ""
+		}
+	}
+	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
+}
+
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if t.env == nil {
+		// the EVM fails the top-level call before it's traced, e.g. by the insufficient balance
+		return nil, errors.New("the call wasn't executed")
+	}
+	if len(t.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	res, err := json.Marshal(t.callstack[0])
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+func addrToHex(a common.Address) string {
+	return strings.ToLower(a.Hex())
+}
+
+func valueOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
@@ -0,0 +1,80 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// fourByteTracer is the native implementation of the 4byteTracer, which counts the 4-byte method selectors
+// of the calls of a transaction by the size of the call data. The precompiled contracts aren't counted.
+type fourByteTracer struct {
+	interruption
+	env               *vm.EVM
+	ids               map[string]int
+	activePrecompiles map[common.Address]bool
+}
+
+func newFourByteTracer(json.RawMessage) (NativeTracer, error) {
+	return &fourByteTracer{
+		ids: make(map[string]int),
+	}, nil
+}
+
+// store counts the selector of the call data of the given size, the selector excluded.
+func (t *fourByteTracer) store(id []byte, size int) {
+	t.ids[hexutil.Encode(id)+"-"+strconv.Itoa(size)]++
+}
+
+func (t *fourByteTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.activePrecompiles = make(map[common.Address]bool)
+	for _, addr := range vm.ActivePrecompiles(env.ChainConfig().Rules(env.Context.BlockNumber)) {
+		t.activePrecompiles[addr] = true
+	}
+	if len(input) >= 4 {
+		t.store(input[:4], len(input)-4)
+	}
+}
+
+func (t *fourByteTracer) CaptureEnter(op vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.isInterrupted() {
+		t.env.Cancel()
+		return
+	}
+	if len(input) < 4 {
+		return
+	}
+	if op != vm.CALL && op != vm.CALLCODE && op != vm.DELEGATECALL && op != vm.STATICCALL {
+		return
+	}
+	if t.activePrecompiles[to] {
+		return
+	}
+	t.store(input[:4], len(input)-4)
+}
+
+func (t *fourByteTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+func (t *fourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *fourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *fourByteTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
@@ -0,0 +1,63 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// NativeTracer is a debug tracer implemented in Go. It produces the same output as the JS tracer
+// of the same name, but it doesn't occupy a JS VM and it's much faster on the deep call trees.
+type NativeTracer interface {
+	vm.Tracer
+	// GetResult returns the JSON result of the trace.
+	GetResult() (json.RawMessage, error)
+	// Stop interrupts the traced execution with the given reason.
+	Stop(err error)
+}
+
+// nativeTracers are the constructors of the native tracers by their names.
+var nativeTracers = map[string]func(config json.RawMessage) (NativeTracer, error){
+	"callTracer":  newCallTracer,
+	"4byteTracer": newFourByteTracer,
+}
+
+// NewNativeTracer creates the native tracer of the given name with the given tracer config.
+// It returns false if there's no native tracer of the name, so the name has to be resolved as a JS tracer.
+func NewNativeTracer(name string, config json.RawMessage) (NativeTracer, bool, error) {
+	create, ok := nativeTracers[name]
+	if !ok {
+		return nil, false, nil
+	}
+	tracer, err := create(config)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid %s config: %w", name, err)
+	}
+	return tracer, true, nil
+}
+
+// interruption is the state of an interrupted native tracer.
+type interruption struct {
+	interrupted uint32
+	reason      error
+}
+
+func (i *interruption) Stop(err error) {
+	i.reason = err
+	atomic.StoreUint32(&i.interrupted, 1)
+}
+
+func (i *interruption) isInterrupted() bool {
+	return atomic.LoadUint32(&i.interrupted) != 0
+}
+
+func bigToHex(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return hexutil.EncodeBig(n)
+}
@@ -0,0 +1,123 @@
+package txtrace
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func getNativeTracer(t *testing.T, name string, config string) NativeTracer {
+	var raw json.RawMessage
+	if config != "" {
+		raw = json.RawMessage(config)
+	}
+	tracer, ok, err := NewNativeTracer(name, raw)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return tracer
+}
+
+// TestNativeCallTracerMatchesJS checks the native callTracer produces the same output as the JS one
+func TestNativeCallTracerMatchesJS(t *testing.T) {
+	for name, run := range map[string]func(tracer vm.Tracer){
+		"complex call": executeCallbacks,
+		"failed calls": func(tracer vm.Tracer) {
+			tracer.CaptureStart(getEVMEnv(), from, to, false, inputData, 1000, value)
+			tracer.CaptureEnter(vm.CALL, to, toInner, inputDataInner, 600, nil)
+			tracer.CaptureExit(outputDataInner, 600, vm.ErrExecutionReverted)
+			tracer.CaptureEnter(vm.CREATE, to, toInner, inputDataInner, 300, value)
+			tracer.CaptureExit(nil, 300, vm.ErrOutOfGas)
+			tracer.CaptureEnd(outputData, 1000, time.Duration(0), vm.ErrExecutionReverted)
+		},
+		"failed root": func(tracer vm.Tracer) {
+			tracer.CaptureStart(getEVMEnv(), from, to, true, inputData, 1000, value)
+			tracer.CaptureEnd(nil, 1000, time.Duration(0), vm.ErrOutOfGas)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			js := getJSTracer("callTracer", t)
+			defer js.Destroy()
+			run(js)
+			exp, err := js.GetResult()
+			require.NoError(t, err)
+
+			native := getNativeTracer(t, "callTracer", "")
+			run(native)
+			got, err := native.GetResult()
+			require.NoError(t, err)
+			require.JSONEq(t, string(exp), string(got))
+		})
+	}
+}
+
+func TestNativeCallTracerOnlyTopCall(t *testing.T) {
+	tracer := getNativeTracer(t, "callTracer", `{"onlyTopCall": true}`)
+	executeCallbacks(tracer)
+	result, err := tracer.GetResult()
+	require.NoError(t, err)
+	checkTracerResult(t, result, `{
+    "type": "CALL",
+    "from": "0x0000000000000000000000000000000000000001",
+    "to": "0x0000000000000000000000000000000000000002",
+    "value": "0x5",
+    "gas": "0x3e8",
+    "gasUsed": "0x64",
+    "input": "0x2f7468610000000000000000000000000000000000000000000000000000000000000008",
+    "output": "0x45"
+}`)
+
+	_, _, err = NewNativeTracer("callTracer", json.RawMessage(`{"onlyTopCall": 1}`))
+	require.Error(t, err)
+}
+
+func TestNativeCallTracerStop(t *testing.T) {
+	tracer := getNativeTracer(t, "callTracer", "")
+	_, err := tracer.GetResult()
+	require.Error(t, err, "nothing is traced")
+
+	env := getEVMEnv()
+	tracer.CaptureStart(env, from, to, false, inputData, 1000, value)
+	stop := errors.New("execution timeout")
+	tracer.Stop(stop)
+	tracer.CaptureEnter(vm.CALL, to, toInner, inputDataInner, 600, value)
+	require.True(t, env.Cancelled())
+	tracer.CaptureEnd(outputData, 100, time.Duration(0), nil)
+	_, err = tracer.GetResult()
+	require.Equal(t, stop, err)
+}
+
+func TestNativeFourByteTracer(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x100")
+		data     = common.Hex2Bytes("a9059cbb000000000000000000000000000000000000000000000000000000000000000a")
+	)
+	tracer := getNativeTracer(t, "4byteTracer", "")
+	tracer.CaptureStart(getEVMEnv(), from, contract, false, data, 1000, value)
+	tracer.CaptureEnter(vm.CALL, contract, contract, data, 600, value)
+	tracer.CaptureExit(nil, 100, nil)
+	tracer.CaptureEnter(vm.STATICCALL, contract, contract, data[:6], 500, nil)
+	tracer.CaptureExit(nil, 100, nil)
+	// the precompiled contracts, the creations and the short call data aren't counted
+	tracer.CaptureEnter(vm.STATICCALL, contract, toInner, data, 500, nil)
+	tracer.CaptureExit(nil, 100, nil)
+	tracer.CaptureEnter(vm.CREATE, contract, contract, data, 500, nil)
+	tracer.CaptureExit(nil, 100, nil)
+	tracer.CaptureEnter(vm.CALL, contract, contract, data[:3], 500, nil)
+	tracer.CaptureExit(nil, 100, nil)
+	tracer.CaptureEnd(nil, 500, time.Duration(0), nil)
+
+	result, err := tracer.GetResult()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"0xa9059cbb-32": 2, "0xa9059cbb-2": 1}`, string(result))
+}
+
+func TestNewNativeTracerUnknown(t *testing.T) {
+	_, ok, err := NewNativeTracer("prestateTracer", nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
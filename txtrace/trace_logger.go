@@ -363,6 +363,15 @@ func (trace *ActionTrace) processOutput(output []byte, err error, rootTrace bool
 	}
 }
 
+// SetGasUsed sets the gas used by the transaction, once it's known after the tracing,
+// e.g. for the transactions traced during the block processing.
+func (tr *TraceStructLogger) SetGasUsed(gasUsed uint64) {
+	tr.gasUsed = gasUsed
+	if tr.rootTrace != nil && len(tr.rootTrace.Actions) != 0 && tr.rootTrace.Actions[0].Result != nil {
+		tr.rootTrace.Actions[0].Result.GasUsed = hexutil.Uint64(gasUsed)
+	}
+}
+
 // GetResult returns action traces after recording evm process
 func (tr *TraceStructLogger) GetResult() *[]ActionTrace {
 	if tr.rootTrace != nil {
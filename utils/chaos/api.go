@@ -0,0 +1,38 @@
+package chaos
+
+import (
+	"errors"
+)
+
+// PrivateAPI allows the resilience tests to control the injected faults.
+// It's registered only by the binaries built with the "chaos" build tag.
+type PrivateAPI struct{}
+
+// NewPrivateAPI creates a new fault injection API.
+func NewPrivateAPI() *PrivateAPI {
+	return &PrivateAPI{}
+}
+
+// SetFaults replaces the injected faults and resets the stats.
+func (api *PrivateAPI) SetFaults(faults Faults) error {
+	if faults.KVErrorRate < 0 || faults.KVErrorRate > 1 || faults.P2PDropRate < 0 || faults.P2PDropRate > 1 {
+		return errors.New("fault rates must be within [0, 1]")
+	}
+	Set(faults)
+	return nil
+}
+
+// Faults returns the injected faults.
+func (api *PrivateAPI) Faults() Faults {
+	return Get()
+}
+
+// FaultStats returns the numbers of the faults injected since the faults were set.
+func (api *PrivateAPI) FaultStats() Stats {
+	return GetStats()
+}
+
+// ClearFaults stops the fault injection.
+func (api *PrivateAPI) ClearFaults() {
+	Set(Faults{})
+}
@@ -0,0 +1,30 @@
+// Package chaos provides the fault injection points of the store and the network layers,
+// so the crash recovery, the quarantine and the retries can be exercised by the resilience tests.
+//
+// The faults are injected only by the binaries built with the "chaos" build tag,
+// otherwise the injection points are no-ops, which are inlined by the compiler.
+package chaos
+
+import "errors"
+
+// ErrInjected is the error returned by the DB operations failed by the fault injection.
+var ErrInjected = errors.New("injected DB fault")
+
+// Faults is the configuration of the injected faults. The zero value injects no faults.
+type Faults struct {
+	// KVErrorRate is the probability of a DB write or read failing with ErrInjected
+	KVErrorRate float64 `json:"kvErrorRate"`
+	// KVErrorDBs are the names of the DBs with the failing operations, all the DBs if empty
+	KVErrorDBs []string `json:"kvErrorDBs,omitempty"`
+	// FlushDelayMs is the delay of each flush of the DBs in milliseconds
+	FlushDelayMs uint64 `json:"flushDelayMs"`
+	// P2PDropRate is the probability of a received P2P message being dropped
+	P2PDropRate float64 `json:"p2pDropRate"`
+}
+
+// Stats are the numbers of the faults injected since the faults were set.
+type Stats struct {
+	KVErrors       uint64 `json:"kvErrors"`
+	DelayedFlushes uint64 `json:"delayedFlushes"`
+	DroppedMsgs    uint64 `json:"droppedMsgs"`
+}
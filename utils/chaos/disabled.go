@@ -0,0 +1,35 @@
+//go:build !chaos
+// +build !chaos
+
+package chaos
+
+import "github.com/Fantom-foundation/lachesis-base/kvdb"
+
+// Enabled tells whether the binary is built with the fault injection.
+const Enabled = false
+
+// Set is a no-op without the "chaos" build tag.
+func Set(Faults) {}
+
+// Get returns no faults without the "chaos" build tag.
+func Get() Faults {
+	return Faults{}
+}
+
+// GetStats returns no stats without the "chaos" build tag.
+func GetStats() Stats {
+	return Stats{}
+}
+
+// WrapProducer returns the producer as is without the "chaos" build tag.
+func WrapProducer(p kvdb.IterableDBProducer) kvdb.IterableDBProducer {
+	return p
+}
+
+// DelayFlush is a no-op without the "chaos" build tag.
+func DelayFlush() {}
+
+// DropMessage never drops a message without the "chaos" build tag.
+func DropMessage() bool {
+	return false
+}
@@ -0,0 +1,170 @@
+//go:build chaos
+// +build chaos
+
+package chaos
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+)
+
+// Enabled tells whether the binary is built with the fault injection.
+const Enabled = true
+
+var (
+	mu     sync.RWMutex
+	faults Faults
+	dbs    map[string]bool
+	rnd    = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rndMu  sync.Mutex
+
+	kvErrors       uint64
+	delayedFlushes uint64
+	droppedMsgs    uint64
+)
+
+// Set replaces the injected faults and resets the stats.
+func Set(f Faults) {
+	mu.Lock()
+	defer mu.Unlock()
+	faults = f
+	dbs = nil
+	if len(f.KVErrorDBs) != 0 {
+		dbs = make(map[string]bool, len(f.KVErrorDBs))
+		for _, name := range f.KVErrorDBs {
+			dbs[name] = true
+		}
+	}
+	atomic.StoreUint64(&kvErrors, 0)
+	atomic.StoreUint64(&delayedFlushes, 0)
+	atomic.StoreUint64(&droppedMsgs, 0)
+}
+
+// Get returns the injected faults.
+func Get() Faults {
+	mu.RLock()
+	defer mu.RUnlock()
+	return faults
+}
+
+// GetStats returns the numbers of the faults injected since the faults were set.
+func GetStats() Stats {
+	return Stats{
+		KVErrors:       atomic.LoadUint64(&kvErrors),
+		DelayedFlushes: atomic.LoadUint64(&delayedFlushes),
+		DroppedMsgs:    atomic.LoadUint64(&droppedMsgs),
+	}
+}
+
+func chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	rndMu.Lock()
+	defer rndMu.Unlock()
+	return rnd.Float64() < rate
+}
+
+// kvError returns ErrInjected if the operation of the named DB is chosen to fail.
+func kvError(name string) error {
+	mu.RLock()
+	rate := faults.KVErrorRate
+	if dbs != nil && !dbs[name] {
+		rate = 0
+	}
+	mu.RUnlock()
+	if !chance(rate) {
+		return nil
+	}
+	atomic.AddUint64(&kvErrors, 1)
+	return ErrInjected
+}
+
+// DelayFlush sleeps for the flush delay before a flush of the DBs.
+func DelayFlush() {
+	delay := time.Duration(Get().FlushDelayMs) * time.Millisecond
+	if delay == 0 {
+		return
+	}
+	atomic.AddUint64(&delayedFlushes, 1)
+	time.Sleep(delay)
+}
+
+// DropMessage tells whether a received P2P message is chosen to be dropped.
+func DropMessage() bool {
+	if !chance(Get().P2PDropRate) {
+		return false
+	}
+	atomic.AddUint64(&droppedMsgs, 1)
+	return true
+}
+
+// WrapProducer wraps the DBs of the producer, so their operations fail by the injected faults.
+func WrapProducer(p kvdb.IterableDBProducer) kvdb.IterableDBProducer {
+	return &dbProducer{p}
+}
+
+type dbProducer struct {
+	kvdb.IterableDBProducer
+}
+
+func (p *dbProducer) OpenDB(name string) (kvdb.Store, error) {
+	s, err := p.IterableDBProducer.OpenDB(name)
+	if err != nil {
+		return nil, err
+	}
+	return &store{s, name}, nil
+}
+
+type store struct {
+	kvdb.Store
+	name string
+}
+
+func (s *store) Has(key []byte) (bool, error) {
+	if err := kvError(s.name); err != nil {
+		return false, err
+	}
+	return s.Store.Has(key)
+}
+
+func (s *store) Get(key []byte) ([]byte, error) {
+	if err := kvError(s.name); err != nil {
+		return nil, err
+	}
+	return s.Store.Get(key)
+}
+
+func (s *store) Put(key []byte, value []byte) error {
+	if err := kvError(s.name); err != nil {
+		return err
+	}
+	return s.Store.Put(key, value)
+}
+
+func (s *store) Delete(key []byte) error {
+	if err := kvError(s.name); err != nil {
+		return err
+	}
+	return s.Store.Delete(key)
+}
+
+func (s *store) NewBatch() kvdb.Batch {
+	return &batch{s.Store.NewBatch(), s.name}
+}
+
+type batch struct {
+	kvdb.Batch
+	name string
+}
+
+func (b *batch) Write() error {
+	if err := kvError(b.name); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
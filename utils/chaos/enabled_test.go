@@ -0,0 +1,56 @@
+//go:build chaos
+// +build chaos
+
+package chaos
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVFaults(t *testing.T) {
+	require := require.New(t)
+	defer Set(Faults{})
+
+	producer := WrapProducer(memorydb.NewProducer(""))
+	failing, err := producer.OpenDB("failing")
+	require.NoError(err)
+	healthy, err := producer.OpenDB("healthy")
+	require.NoError(err)
+
+	Set(Faults{
+		KVErrorRate: 1,
+		KVErrorDBs:  []string{"failing"},
+	})
+	require.ErrorIs(failing.Put([]byte{1}, []byte{1}), ErrInjected)
+	b := failing.NewBatch()
+	require.NoError(b.Put([]byte{1}, []byte{1}))
+	require.ErrorIs(b.Write(), ErrInjected)
+	_, err = failing.Get([]byte{1})
+	require.ErrorIs(err, ErrInjected)
+	require.NoError(healthy.Put([]byte{1}, []byte{1}))
+	require.Equal(uint64(3), GetStats().KVErrors)
+
+	Set(Faults{})
+	require.NoError(failing.Put([]byte{1}, []byte{1}))
+	got, err := failing.Get([]byte{1})
+	require.NoError(err)
+	require.Equal([]byte{1}, got)
+	require.Equal(uint64(0), GetStats().KVErrors)
+}
+
+func TestDropMessages(t *testing.T) {
+	require := require.New(t)
+	defer Set(Faults{})
+
+	require.False(DropMessage())
+	api := NewPrivateAPI()
+	require.Error(api.SetFaults(Faults{P2PDropRate: 2}))
+	require.NoError(api.SetFaults(Faults{P2PDropRate: 1}))
+	require.True(DropMessage())
+	require.Equal(uint64(1), api.FaultStats().DroppedMsgs)
+	api.ClearFaults()
+	require.False(DropMessage())
+}
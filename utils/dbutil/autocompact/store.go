@@ -6,6 +6,8 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/status-im/keycard-go/hexutils"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
 )
 
 // Store implements automatic compacting of recently inserted/erased data according to provided strategy
@@ -105,6 +107,26 @@ func (s *Store) Delete(key []byte) error {
 	return s.Store.Delete(key)
 }
 
+// DeleteRange deletes the keys in [start, limit) and schedules the compaction of the range.
+func (s *Store) DeleteRange(start, limit []byte) error {
+	deleted, err := rangedel.DeleteRange(s.Store, start, limit)
+	// a range tombstone is accounted as a single deleted key
+	size := estSize(len(start), 0) * uint64(max(deleted, 1))
+	s.compMu.Lock()
+	defer s.compMu.Unlock()
+	s.cont.Add(start, size)
+	if limit != nil {
+		s.cont.Add(limit, 0)
+	}
+	s.mayCompact(false)
+	return err
+}
+
+// CompactPending compacts the accumulated ranges, which haven't reached the limit yet.
+func (s *Store) CompactPending() {
+	s.compact()
+}
+
 func (s *Store) Close() error {
 	s.compact()
 	return s.Store.Close()
@@ -0,0 +1,66 @@
+// Package pebbledb is the Pebble key-value store of lachesis-base, which also deletes the ranges of keys
+// by the range tombstones of Pebble instead of a tombstone per key.
+package pebbledb
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/pebble"
+	cpebble "github.com/cockroachdb/pebble"
+)
+
+// Database is the Pebble store of lachesis-base with the range deletes.
+type Database struct {
+	*pebble.Database
+}
+
+// underlyingField is the field of the lachesis-base store with the Pebble instance, which isn't exported.
+var underlyingField = func() reflect.StructField {
+	f, ok := reflect.TypeOf(pebble.Database{}).FieldByName("underlying")
+	if !ok || f.Type != reflect.TypeOf((*cpebble.DB)(nil)) {
+		panic("lachesis-base Pebble store doesn't keep the Pebble instance")
+	}
+	return f
+}()
+
+// New opens the lachesis-base Pebble store with the range deletes.
+func New(path string, cache int, handles int, onClose func() error, onDrop func()) (*Database, error) {
+	db, err := pebble.New(path, cache, handles, onClose, onDrop)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(db), nil
+}
+
+// Wrap the lachesis-base Pebble store with the range deletes.
+func Wrap(db *pebble.Database) *Database {
+	return &Database{db}
+}
+
+// underlying returns the Pebble instance of the store, nil if the store is closed.
+func (db *Database) underlying() *cpebble.DB {
+	return *(**cpebble.DB)(unsafe.Add(unsafe.Pointer(db.Database), underlyingField.Offset))
+}
+
+// DeleteRange deletes all the keys in [start, limit) by a single range tombstone.
+// Nil limit means the end of the keyspace.
+func (db *Database) DeleteRange(start, limit []byte) error {
+	underlying := db.underlying()
+	if underlying == nil {
+		return fmt.Errorf("%s: %w", db.Path(), cpebble.ErrClosed)
+	}
+	if limit == nil {
+		// the range tombstone needs an exclusive upper bound, so it's the key following the last one
+		it := underlying.NewIter(&cpebble.IterOptions{LowerBound: start})
+		if !it.Last() {
+			return it.Close()
+		}
+		limit = append(append([]byte{}, it.Key()...), 0)
+		if err := it.Close(); err != nil {
+			return err
+		}
+	}
+	return underlying.DeleteRange(start, limit, cpebble.NoSync)
+}
@@ -0,0 +1,65 @@
+package pebbledb
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+func keys(db kvdb.Store) []string {
+	var res []string
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		res = append(res, string(it.Key()))
+	}
+	return res
+}
+
+func TestDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	db, err := New(t.TempDir(), 16, 16, nil, nil)
+	require.NoError(err)
+	defer db.Close()
+	for _, k := range []string{"a1", "a2", "b1", "b2", "b3", "c1"} {
+		require.NoError(db.Put([]byte(k), []byte{1}))
+	}
+
+	require.NoError(db.DeleteRange([]byte("a2"), []byte("b3")))
+	require.Equal([]string{"a1", "b3", "c1"}, keys(db))
+	ok, err := db.Has([]byte("b1"))
+	require.NoError(err)
+	require.False(ok)
+
+	// nil limit is the end of the keyspace
+	require.NoError(db.DeleteRange([]byte("b"), nil))
+	require.Equal([]string{"a1"}, keys(db))
+	require.NoError(db.DeleteRange([]byte("b"), nil))
+	require.Equal([]string{"a1"}, keys(db))
+
+	// the keys written after the range delete aren't deleted
+	require.NoError(db.Put([]byte("b2"), []byte{2}))
+	require.Equal([]string{"a1", "b2"}, keys(db))
+}
+
+func TestProducerDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	producer := NewProducer(t.TempDir(), func(string) (int, int) { return 16, 16 })
+	store, err := producer.OpenDB("test")
+	require.NoError(err)
+	db, ok := store.(*Database)
+	require.True(ok)
+	require.Equal([]string{"test"}, producer.Names())
+
+	require.NoError(db.Put([]byte("a1"), []byte{1}))
+	require.NoError(db.Put([]byte("a2"), []byte{1}))
+	require.NoError(db.DeleteRange([]byte("a2"), nil))
+	require.Equal([]string{"a1"}, keys(db))
+
+	// the closed store isn't accessed
+	require.NoError(db.Close())
+	require.Error(db.DeleteRange([]byte("a"), []byte("b")))
+}
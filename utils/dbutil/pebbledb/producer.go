@@ -0,0 +1,25 @@
+package pebbledb
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/pebble"
+)
+
+// Producer of the lachesis-base Pebble stores with the range deletes.
+type Producer struct {
+	kvdb.IterableDBProducer
+}
+
+// NewProducer of Pebble db.
+func NewProducer(datadir string, getCacheFdLimit func(string) (int, int)) kvdb.IterableDBProducer {
+	return &Producer{pebble.NewProducer(datadir, getCacheFdLimit)}
+}
+
+// OpenDB or create db with name.
+func (p *Producer) OpenDB(name string) (kvdb.Store, error) {
+	db, err := p.IterableDBProducer.OpenDB(name)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(db.(*pebble.Database)), nil
+}
@@ -0,0 +1,106 @@
+// Package priowrite gives the consensus-critical DB flushes priority over the bulk compactions.
+// The writes of a flushable DB are buffered in memory, so the disk I/O happens on the flush
+// and on the compactions. The compactions of the bulk tables, like the API-only tables and
+// the logs index, wait while the critical flush of the events and the epoch state is in progress,
+// so the flush isn't queued behind them. The individual writes aren't delayed.
+// The compactions wait for a limited time, so they aren't starved by a steady stream of the flushes.
+package priowrite
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/rangedel"
+)
+
+var (
+	bulkWaitTimer = metrics.GetOrRegisterTimer("chaindata/bulk/wait", nil)
+)
+
+// Gate orders the critical flushes and the bulk compactions.
+type Gate struct {
+	maxWait time.Duration
+
+	mu       sync.Mutex
+	critical int
+	idle     chan struct{} // closed while no critical flushes are in progress
+}
+
+// NewGate creates a gate, which delays the bulk compactions by no more than maxWait.
+func NewGate(maxWait time.Duration) *Gate {
+	idle := make(chan struct{})
+	close(idle)
+	return &Gate{
+		maxWait: maxWait,
+		idle:    idle,
+	}
+}
+
+func (g *Gate) beginCritical() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.critical == 0 {
+		g.idle = make(chan struct{})
+	}
+	g.critical++
+}
+
+func (g *Gate) endCritical() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.critical--
+	if g.critical == 0 {
+		close(g.idle)
+	}
+}
+
+// Critical runs the critical flush, the bulk compactions wait until it's finished.
+func (g *Gate) Critical(flush func() error) error {
+	g.beginCritical()
+	defer g.endCritical()
+	return flush()
+}
+
+// waitBulk waits until no critical flushes are in progress, but no more than maxWait.
+func (g *Gate) waitBulk() {
+	g.mu.Lock()
+	idle := g.idle
+	g.mu.Unlock()
+	select {
+	case <-idle:
+		return
+	default:
+	}
+	start := time.Now()
+	timer := time.NewTimer(g.maxWait)
+	defer timer.Stop()
+	select {
+	case <-idle:
+	case <-timer.C:
+	}
+	bulkWaitTimer.UpdateSince(start)
+}
+
+// Bulk wraps the store, so its compactions wait for the critical flushes of the gate.
+func Bulk(s kvdb.Store, g *Gate) kvdb.Store {
+	return &bulkStore{s, g}
+}
+
+type bulkStore struct {
+	kvdb.Store
+	g *Gate
+}
+
+func (s *bulkStore) Compact(start []byte, limit []byte) error {
+	s.g.waitBulk()
+	return s.Store.Compact(start, limit)
+}
+
+// DeleteRange deletes the range of keys by the underlying store, if it's able to.
+func (s *bulkStore) DeleteRange(start []byte, limit []byte) error {
+	_, err := rangedel.DeleteRange(s.Store, start, limit)
+	return err
+}
@@ -0,0 +1,92 @@
+package priowrite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCompactionWaitsForCritical(t *testing.T) {
+	require := require.New(t)
+
+	g := NewGate(time.Hour)
+	db := memorydb.New()
+	bulk := Bulk(db, g)
+
+	// no critical flushes, the bulk compaction isn't delayed
+	require.NoError(bulk.Compact(nil, nil))
+
+	flushing := make(chan struct{})
+	release := make(chan struct{})
+	flushed := make(chan error)
+	go func() {
+		flushed <- g.Critical(func() error {
+			close(flushing)
+			<-release
+			return errors.New("flush")
+		})
+	}()
+	<-flushing
+
+	// the writes aren't delayed by the critical flush
+	require.NoError(bulk.Put([]byte{1}, []byte{1}))
+	compacted := make(chan struct{})
+	go func() {
+		defer close(compacted)
+		require.NoError(bulk.Compact(nil, nil))
+	}()
+	select {
+	case <-compacted:
+		t.Fatal("bulk compaction isn't delayed by the critical flush")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	require.EqualError(<-flushed, "flush")
+	<-compacted
+}
+
+func TestBulkCompactionMaxWait(t *testing.T) {
+	require := require.New(t)
+
+	g := NewGate(10 * time.Millisecond)
+	bulk := Bulk(memorydb.New(), g)
+
+	g.beginCritical()
+	defer g.endCritical()
+	start := time.Now()
+	require.NoError(bulk.Compact(nil, nil))
+	require.GreaterOrEqual(time.Since(start), 10*time.Millisecond)
+}
+
+// BenchmarkBulkWrites compares the writes of the bulk store with the ones of the underlying store,
+// the gate doesn't add a per-key latency.
+func BenchmarkBulkWrites(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		wrap func(kvdb.Store) kvdb.Store
+	}{
+		{"raw", func(s kvdb.Store) kvdb.Store { return s }},
+		{"bulk", func(s kvdb.Store) kvdb.Store { return Bulk(s, NewGate(time.Second)) }},
+		{"bulk-during-flush", func(s kvdb.Store) kvdb.Store {
+			g := NewGate(time.Second)
+			g.beginCritical()
+			return Bulk(s, g)
+		}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			db := bench.wrap(memorydb.New())
+			key := make([]byte, 8)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key[0], key[1], key[2], key[3] = byte(i), byte(i>>8), byte(i>>16), byte(i>>24)
+				if err := db.Put(key, key); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package rangedel
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+)
+
+// Backend is the producer of the stores, which delete the ranges of keys, e.g. the Pebble stores.
+// It keeps the opened stores, so the stores of the producers built over it delete the ranges by them.
+type Backend struct {
+	kvdb.IterableDBProducer
+
+	mu     sync.Mutex
+	stores map[string]kvdb.Store
+}
+
+// NewBackend wraps the producer of the stores, which delete the ranges of keys.
+func NewBackend(p kvdb.IterableDBProducer) *Backend {
+	return &Backend{
+		IterableDBProducer: p,
+		stores:             make(map[string]kvdb.Store),
+	}
+}
+
+// OpenDB opens the store and keeps it for the range deletes.
+func (b *Backend) OpenDB(name string) (kvdb.Store, error) {
+	s, err := b.IterableDBProducer.OpenDB(name)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.stores[name] = s
+	b.mu.Unlock()
+	return s, nil
+}
+
+func (b *Backend) rangeDeleter(name string) RangeDeleter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rd, _ := b.stores[name].(RangeDeleter)
+	return rd
+}
+
+// Producer wraps the stores of a producer built over the Backend, so they delete the ranges of keys by the backend
+// stores. The range deletes bypass the wrappers in between, so a range delete is preceded by a point delete of its
+// start key through them, which accounts the modification of the store, e.g. marks it as not flushed.
+// The ranges, which overlap the keys skipped by the wrappers, are deleted by the point deletes.
+type Producer struct {
+	kvdb.FullDBProducer
+	backend    *Backend
+	skipPrefix []byte
+}
+
+// WrapProducer wraps the producer built over the backend.
+func WrapProducer(p kvdb.FullDBProducer, backend *Backend, skipPrefix []byte) *Producer {
+	return &Producer{
+		FullDBProducer: p,
+		backend:        backend,
+		skipPrefix:     skipPrefix,
+	}
+}
+
+// OpenDB opens the store, which deletes the ranges of keys by the backend store.
+func (p *Producer) OpenDB(name string) (kvdb.Store, error) {
+	s, err := p.FullDBProducer.OpenDB(name)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		Store:    s,
+		name:     name,
+		producer: p,
+	}, nil
+}
+
+type store struct {
+	kvdb.Store
+	name     string
+	producer *Producer
+}
+
+// DeleteRange deletes all the keys in [start, limit). Nil limit means the end of the keyspace.
+func (s *store) DeleteRange(start, limit []byte) error {
+	rd := s.producer.backend.rangeDeleter(s.name)
+	if rd == nil || s.skips(start, limit) {
+		_, err := deletePoints(s.Store, start, limit)
+		return err
+	}
+	if err := s.Store.Delete(start); err != nil {
+		return err
+	}
+	return rd.DeleteRange(start, limit)
+}
+
+// skips returns true if the range overlaps the keys skipped by the wrappers.
+func (s *store) skips(start, limit []byte) bool {
+	skip := s.producer.skipPrefix
+	if len(skip) == 0 {
+		return false
+	}
+	skipLimit := PrefixLimit(skip)
+	return (skipLimit == nil || bytes.Compare(start, skipLimit) < 0) && (limit == nil || bytes.Compare(limit, skip) > 0)
+}
@@ -0,0 +1,71 @@
+// Package rangedel deletes ranges of keys. The stores which support it register a single range tombstone
+// instead of a tombstone per key, which avoids the write amplification of deleting millions of keys.
+package rangedel
+
+import (
+	"bytes"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RangeDeleter is implemented by the stores which are able to delete a range of keys at once.
+type RangeDeleter interface {
+	// DeleteRange deletes all the keys in [start, limit). Nil limit means the end of the keyspace.
+	DeleteRange(start, limit []byte) error
+}
+
+// DeleteRange deletes all the keys in [start, limit) of the store. Nil limit means the end of the keyspace.
+// If the store doesn't implement RangeDeleter, the keys are deleted by batches of point deletes.
+// Returns the number of the point deletes.
+func DeleteRange(db kvdb.Store, start, limit []byte) (int, error) {
+	if rd, ok := db.(RangeDeleter); ok {
+		return 0, rd.DeleteRange(start, limit)
+	}
+	return deletePoints(db, start, limit)
+}
+
+// deletePoints deletes the keys in [start, limit) by batches of point deletes.
+func deletePoints(db kvdb.Store, start, limit []byte) (int, error) {
+	it := db.NewIterator(nil, start)
+	defer it.Release()
+	batch := db.NewBatch()
+	deleted := 0
+	for it.Next() {
+		if limit != nil && bytes.Compare(it.Key(), limit) >= 0 {
+			break
+		}
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return deleted, err
+		}
+		deleted++
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return deleted, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return deleted, err
+	}
+	return deleted, batch.Write()
+}
+
+// DeletePrefix deletes all the keys with the prefix.
+func DeletePrefix(db kvdb.Store, prefix []byte) (int, error) {
+	return DeleteRange(db, prefix, PrefixLimit(prefix))
+}
+
+// PrefixLimit returns the smallest key, which is greater than all the keys with the prefix,
+// or nil if there is no such key.
+func PrefixLimit(prefix []byte) []byte {
+	limit := common.CopyBytes(prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] != 0xff {
+			limit[i]++
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package rangedel
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+)
+
+type rangeDeleterStore struct {
+	kvdb.Store
+	ranges [][2][]byte
+}
+
+func (s *rangeDeleterStore) DeleteRange(start, limit []byte) error {
+	s.ranges = append(s.ranges, [2][]byte{start, limit})
+	return nil
+}
+
+func keys(db kvdb.Store) []string {
+	var res []string
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		res = append(res, string(it.Key()))
+	}
+	return res
+}
+
+func TestDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	db := memorydb.New()
+	for _, k := range []string{"a1", "a2", "b1", "b2", "b3", "c1"} {
+		require.NoError(db.Put([]byte(k), []byte{1}))
+	}
+
+	deleted, err := DeleteRange(db, []byte("a2"), []byte("b3"))
+	require.NoError(err)
+	require.Equal(3, deleted)
+	require.Equal([]string{"a1", "b3", "c1"}, keys(db))
+
+	deleted, err = DeletePrefix(db, []byte("c"))
+	require.NoError(err)
+	require.Equal(1, deleted)
+	require.Equal([]string{"a1", "b3"}, keys(db))
+
+	deleted, err = DeleteRange(db, []byte("b"), nil)
+	require.NoError(err)
+	require.Equal(1, deleted)
+	require.Equal([]string{"a1"}, keys(db))
+}
+
+func TestDeleteRangeNative(t *testing.T) {
+	require := require.New(t)
+
+	db := &rangeDeleterStore{Store: memorydb.New()}
+	require.NoError(db.Put([]byte("a1"), []byte{1}))
+
+	deleted, err := DeletePrefix(db, []byte("a"))
+	require.NoError(err)
+	require.Equal(0, deleted)
+	require.Equal([][2][]byte{{[]byte("a"), []byte("b")}}, db.ranges)
+}
+
+func TestPrefixLimit(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]byte{1, 3}, PrefixLimit([]byte{1, 2}))
+	require.Equal([]byte{2}, PrefixLimit([]byte{1, 0xff}))
+	require.Nil(PrefixLimit([]byte{0xff, 0xff}))
+	require.Nil(PrefixLimit(nil))
+}
+
+func TestTableDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	db := &rangeDeleterStore{Store: memorydb.New()}
+	tbl := NewTable(db, []byte("t"))
+	require.NoError(tbl.Put([]byte("1"), []byte{1}))
+
+	_, err := DeleteRange(tbl, []byte("1"), []byte("2"))
+	require.NoError(err)
+	_, err = DeleteRange(tbl, []byte("2"), nil)
+	require.NoError(err)
+	require.Equal([][2][]byte{{[]byte("t1"), []byte("t2")}, {[]byte("t2"), []byte("u")}}, db.ranges)
+}
+
+// writesCounter counts the point writes, like the wrappers which account the store modifications.
+type writesCounter struct {
+	kvdb.Store
+	writes int
+}
+
+func (s *writesCounter) Delete(key []byte) error {
+	s.writes++
+	return s.Store.Delete(key)
+}
+
+type producer struct {
+	kvdb.FullDBProducer
+	open func(name string) (kvdb.Store, error)
+}
+
+func (p *producer) OpenDB(name string) (kvdb.Store, error) {
+	return p.open(name)
+}
+
+func TestProducerDeleteRange(t *testing.T) {
+	require := require.New(t)
+
+	raw := &rangeDeleterStore{Store: memorydb.New()}
+	backend := NewBackend(&producer{open: func(string) (kvdb.Store, error) {
+		return raw, nil
+	}})
+	wrapper := &writesCounter{}
+	dbs := WrapProducer(&producer{open: func(name string) (kvdb.Store, error) {
+		s, err := backend.OpenDB(name)
+		wrapper.Store = s
+		return wrapper, err
+	}}, backend, []byte("m"))
+
+	db, err := dbs.OpenDB("db")
+	require.NoError(err)
+	for _, k := range []string{"a1", "b1", "m1", "z1"} {
+		require.NoError(db.Put([]byte(k), []byte{1}))
+	}
+
+	// the range is deleted by the backend store, the wrappers account the modification
+	deleted, err := DeleteRange(db, []byte("a"), []byte("b"))
+	require.NoError(err)
+	require.Equal(0, deleted)
+	require.Equal([][2][]byte{{[]byte("a"), []byte("b")}}, raw.ranges)
+	require.Equal(1, wrapper.writes)
+
+	// the range of the skipped keys is deleted by the point deletes through the wrappers
+	_, err = DeleteRange(db, []byte("b"), []byte("n"))
+	require.NoError(err)
+	require.Len(raw.ranges, 1)
+	require.Equal([]string{"a1", "z1"}, keys(raw))
+	_, err = DeleteRange(db, []byte("x"), nil)
+	require.NoError(err)
+	require.Len(raw.ranges, 2)
+}
@@ -0,0 +1,33 @@
+package rangedel
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Table is a table of the keys with the prefix, which deletes the ranges of keys by the underlying store.
+type Table struct {
+	*table.Table
+	db     kvdb.Store
+	prefix []byte
+}
+
+// NewTable returns the table of the keys with the prefix in the store.
+func NewTable(db kvdb.Store, prefix []byte) *Table {
+	return &Table{
+		Table:  table.New(db, prefix),
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+// DeleteRange deletes all the keys of the table in [start, limit). Nil limit means the end of the table.
+func (t *Table) DeleteRange(start, limit []byte) error {
+	end := PrefixLimit(t.prefix)
+	if limit != nil {
+		end = append(common.CopyBytes(t.prefix), limit...)
+	}
+	_, err := DeleteRange(t.db, append(common.CopyBytes(t.prefix), start...), end)
+	return err
+}
@@ -0,0 +1,230 @@
+// Package lru implements a thread-safe typed LRU cache, limited by both the number of entries and their total weight.
+// The weight of an entry is calculated by the weigher function of the cache, so eviction follows the memory held
+// by the entries rather than their number. Keys and values are stored without boxing into interfaces.
+package lru
+
+import "sync"
+
+// Weigher returns the weight of an entry, normally an estimation of the memory held by the entry in bytes.
+type Weigher[K comparable, V any] func(key K, value V) uint
+
+type entry[K comparable, V any] struct {
+	key    K
+	value  V
+	weight uint
+
+	prev, next *entry[K, V]
+}
+
+// Cache is a thread-safe LRU cache limited by the number of entries and their total weight.
+type Cache[K comparable, V any] struct {
+	maxWeight uint
+	maxSize   int
+	weigher   Weigher[K, V]
+	onEvict   func(key K, value V)
+
+	mu     sync.Mutex
+	items  map[K]*entry[K, V]
+	weight uint
+	// root is a sentinel of the circular list, root.next is the newest entry
+	root entry[K, V]
+}
+
+// New creates a cache limited by the given total weight and number of entries.
+// If the weigher is nil, every entry weights 1.
+func New[K comparable, V any](maxWeight uint, maxSize int, weigher Weigher[K, V]) *Cache[K, V] {
+	return NewWithEvict(maxWeight, maxSize, weigher, nil)
+}
+
+// NewWithEvict creates a cache with the callback called for the entries evicted due to the limits.
+func NewWithEvict[K comparable, V any](maxWeight uint, maxSize int, weigher Weigher[K, V], onEvict func(key K, value V)) *Cache[K, V] {
+	if maxSize < 0 {
+		maxSize = 0
+	}
+	c := &Cache[K, V]{
+		maxWeight: maxWeight,
+		maxSize:   maxSize,
+		weigher:   weigher,
+		onEvict:   onEvict,
+		items:     make(map[K]*entry[K, V]),
+	}
+	c.root.next = &c.root
+	c.root.prev = &c.root
+	return c
+}
+
+func (c *Cache[K, V]) weigh(key K, value V) uint {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(key, value)
+}
+
+func (c *Cache[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &c.root
+	e.next = c.root.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+func (c *Cache[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+}
+
+func (c *Cache[K, V]) removeEntry(e *entry[K, V]) {
+	c.unlink(e)
+	delete(c.items, e.key)
+	c.weight -= e.weight
+}
+
+// evict removes the oldest entries until the limits are met, returns the number of evicted entries.
+func (c *Cache[K, V]) evict() (evicted int) {
+	for len(c.items) != 0 && (c.weight > c.maxWeight || len(c.items) > c.maxSize) {
+		e := c.root.prev
+		c.removeEntry(e)
+		if c.onEvict != nil {
+			c.onEvict(e.key, e.value)
+		}
+		evicted++
+	}
+	return evicted
+}
+
+// Add adds or updates the entry and makes it the newest one. Returns the number of evicted entries.
+func (c *Cache[K, V]) Add(key K, value V) (evicted int) {
+	weight := c.weigh(key, value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.unlink(e)
+		c.weight = c.weight - e.weight + weight
+		e.value = value
+		e.weight = weight
+		c.pushFront(e)
+	} else {
+		e := &entry[K, V]{key: key, value: value, weight: weight}
+		c.items[key] = e
+		c.weight += weight
+		c.pushFront(e)
+	}
+	return c.evict()
+}
+
+// Get returns the value of the key and makes the entry the newest one.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	c.unlink(e)
+	c.pushFront(e)
+	return e.value, true
+}
+
+// Peek returns the value of the key without updating the recentness of the entry.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Contains checks if the key is in the cache, without updating the recentness of the entry.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.items[key]
+	return ok
+}
+
+// Remove removes the key from the cache. Returns true if the key was present.
+func (c *Cache[K, V]) Remove(key K) (present bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if ok {
+		c.removeEntry(e)
+	}
+	return ok
+}
+
+// RemoveOldest removes the oldest entry from the cache.
+func (c *Cache[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.items) == 0 {
+		return key, value, false
+	}
+	e := c.root.prev
+	c.removeEntry(e)
+	return e.key, e.value, true
+}
+
+// ShrinkTo evicts the oldest entries until the total weight isn't above the given one.
+// The weight limit is clamped to the given weight, so the cache doesn't refill over it.
+// Returns the number of evicted entries.
+func (c *Cache[K, V]) ShrinkTo(weight uint) (evicted int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if weight < c.maxWeight {
+		c.maxWeight = weight
+	}
+	for len(c.items) != 0 && c.weight > weight {
+		c.removeEntry(c.root.prev)
+		evicted++
+	}
+	return evicted
+}
+
+// Keys returns the keys from the oldest to the newest.
+func (c *Cache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, len(c.items))
+	for e := c.root.prev; e != &c.root; e = e.prev {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Purge removes all the entries.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*entry[K, V])
+	c.weight = 0
+	c.root.next = &c.root
+	c.root.prev = &c.root
+}
+
+// Resize changes the limits of the cache. Returns the number of evicted entries.
+func (c *Cache[K, V]) Resize(maxWeight uint, maxSize int) (evicted int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxWeight = maxWeight
+	c.maxSize = maxSize
+	return c.evict()
+}
+
+// Len returns the number of entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Weight returns the total weight of the entries.
+func (c *Cache[K, V]) Weight() uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.weight
+}
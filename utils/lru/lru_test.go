@@ -0,0 +1,108 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheLimits(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	c := NewWithEvict[int, string](10, 3, func(_ int, v string) uint {
+		return uint(len(v))
+	}, func(k int, _ string) {
+		evicted = append(evicted, k)
+	})
+
+	require.Equal(0, c.Add(1, "aaa"))
+	require.Equal(0, c.Add(2, "bbb"))
+	require.Equal(0, c.Add(3, "ccc"))
+	require.Equal(uint(9), c.Weight())
+
+	// the number of entries is limited
+	require.Equal(1, c.Add(4, "d"))
+	require.Equal([]int{1}, evicted)
+	require.Equal([]int{2, 3, 4}, c.Keys())
+
+	// the weight is limited, the used entry isn't evicted
+	_, ok := c.Get(2)
+	require.True(ok)
+	require.Equal(2, c.Add(5, "eeeeeee"))
+	require.Equal([]int{1, 3, 4}, evicted)
+	require.Equal([]int{2, 5}, c.Keys())
+	require.Equal(uint(10), c.Weight())
+
+	// updating changes the weight
+	require.Equal(0, c.Add(2, "b"))
+	require.Equal(uint(8), c.Weight())
+	v, ok := c.Peek(2)
+	require.True(ok)
+	require.Equal("b", v)
+	require.Equal([]int{5, 2}, c.Keys())
+
+	// an entry heavier than the limit isn't kept
+	require.Equal(3, c.Add(6, "ffffffffffff"))
+	require.Equal(0, c.Len())
+	require.Equal(uint(0), c.Weight())
+}
+
+func TestCacheRemove(t *testing.T) {
+	require := require.New(t)
+
+	c := New[string, int](100, 100, nil)
+	for i, k := range []string{"a", "b", "c"} {
+		c.Add(k, i)
+	}
+	require.Equal(uint(3), c.Weight())
+
+	require.True(c.Remove("b"))
+	require.False(c.Remove("b"))
+	require.False(c.Contains("b"))
+
+	k, v, ok := c.RemoveOldest()
+	require.True(ok)
+	require.Equal("a", k)
+	require.Equal(0, v)
+
+	require.Equal(1, c.Resize(0, 0))
+	require.Equal(0, c.Len())
+	_, _, ok = c.RemoveOldest()
+	require.False(ok)
+
+	c.Resize(100, 100)
+	c.Add("d", 3)
+	c.Purge()
+	require.Equal(0, c.Len())
+	require.Equal(uint(0), c.Weight())
+	_, ok = c.Get("d")
+	require.False(ok)
+}
+
+func TestCacheShrinkTo(t *testing.T) {
+	require := require.New(t)
+
+	c := New[int, int](100, 100, nil)
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+
+	// the oldest entries are evicted
+	require.Equal(6, c.ShrinkTo(4))
+	require.Equal([]int{6, 7, 8, 9}, c.Keys())
+
+	// the weight limit is clamped
+	require.Equal(1, c.Add(10, 10))
+	require.Equal([]int{7, 8, 9, 10}, c.Keys())
+
+	// a greater weight doesn't raise the limit
+	require.Equal(0, c.ShrinkTo(10))
+	require.Equal(1, c.Add(11, 11))
+	require.Equal(uint(4), c.Weight())
+
+	// the limits are restored by resizing
+	c.Resize(100, 100)
+	require.Equal(0, c.Add(12, 12))
+	require.Equal(uint(5), c.Weight())
+}
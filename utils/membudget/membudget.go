@@ -0,0 +1,218 @@
+// Package membudget accounts the memory held by the node components against a single budget.
+// Components which exceed their share are shrunk in the order of their priorities,
+// so that independently sized caches can't outgrow the memory available to the node.
+package membudget
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+// Priority of a component. Components of lower priorities are shrunk first.
+type Priority int
+
+const (
+	// PriorityLow is for caches which are cheap to refill, e.g. API-only caches.
+	PriorityLow Priority = iota
+	// PriorityNormal is for caches used in events and blocks processing.
+	PriorityNormal
+	// PriorityHigh is for caches whose misses slow down the consensus.
+	PriorityHigh
+	// PriorityFixed is for components which are accounted, but never shrunk.
+	PriorityFixed
+)
+
+// Component is a memory consumer.
+type Component interface {
+	// Size returns the number of bytes held by the component.
+	Size() uint64
+	// Shrink releases memory until at most the given number of bytes is held.
+	Shrink(size uint64)
+}
+
+// Func returns a component with the given size getter, which is accounted but never shrunk.
+type Func func() uint64
+
+// Size returns the number of bytes held by the component.
+func (f Func) Size() uint64 {
+	return f()
+}
+
+// Shrink is a no-op.
+func (f Func) Shrink(uint64) {}
+
+// WeightedCache is a cache whose entries are weighted in bytes, such as lru.Cache.
+type WeightedCache interface {
+	// Weight returns the total weight of the entries.
+	Weight() uint
+	// ShrinkTo evicts the oldest entries until the total weight isn't above the given one,
+	// and clamps the weight limit of the cache to it.
+	ShrinkTo(weight uint) (evicted int)
+}
+
+type cacheComponent struct {
+	cache WeightedCache
+}
+
+// Cache returns a component for the cache. The cache is shrunk by evicting
+// the oldest entries, and its capacity is clamped to the shrunk size,
+// so the cache doesn't refill over the budget.
+func Cache(cache WeightedCache) Component {
+	return cacheComponent{cache}
+}
+
+func (c cacheComponent) Size() uint64 {
+	return uint64(c.cache.Weight())
+}
+
+func (c cacheComponent) Shrink(size uint64) {
+	c.cache.ShrinkTo(uint(size))
+}
+
+type entry struct {
+	name     string
+	priority Priority
+	c        Component
+	gauge    metrics.Gauge
+}
+
+// Accountant tracks the memory of the registered components against the budget.
+type Accountant struct {
+	budget uint64
+
+	mu         sync.Mutex
+	components []*entry
+
+	usedGauge     metrics.Gauge
+	releasedMeter metrics.Meter
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+
+	logger.Instance
+}
+
+// New returns an accountant with the given budget in bytes.
+func New(budget uint64) *Accountant {
+	return &Accountant{
+		budget:        budget,
+		usedGauge:     metrics.GetOrRegisterGauge("membudget/used", nil),
+		releasedMeter: metrics.GetOrRegisterMeter("membudget/released", nil),
+		Instance:      logger.New("membudget"),
+	}
+}
+
+// Budget returns the budget in bytes.
+func (a *Accountant) Budget() uint64 {
+	return a.budget
+}
+
+// Register adds the component under the given name.
+func (a *Accountant) Register(name string, priority Priority, c Component) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.components = append(a.components, &entry{
+		name:     name,
+		priority: priority,
+		c:        c,
+		gauge:    metrics.GetOrRegisterGauge("membudget/component/"+name, nil),
+	})
+	// keep the shrinking order: low priorities first, the registration order within a priority
+	sort.SliceStable(a.components, func(i, j int) bool {
+		return a.components[i].priority < a.components[j].priority
+	})
+}
+
+// Usage returns the bytes held by each of the components.
+func (a *Accountant) Usage() map[string]uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usage := make(map[string]uint64, len(a.components))
+	for _, e := range a.components {
+		usage[e.name] += e.c.Size()
+	}
+	return usage
+}
+
+// Enforce shrinks the components if the total usage exceeds the budget.
+// Lower priority components are shrunk first, each one down to zero if needed.
+// Returns the number of released bytes.
+func (a *Accountant) Enforce() (released uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sizes := make([]uint64, len(a.components))
+	total := uint64(0)
+	for i, e := range a.components {
+		sizes[i] = e.c.Size()
+		total += sizes[i]
+		e.gauge.Update(int64(sizes[i]))
+	}
+	a.usedGauge.Update(int64(total))
+	if total <= a.budget {
+		return 0
+	}
+
+	excess := total - a.budget
+	for i, e := range a.components {
+		if excess == 0 || e.priority == PriorityFixed {
+			break
+		}
+		target := uint64(0)
+		if sizes[i] > excess {
+			target = sizes[i] - excess
+		}
+		e.c.Shrink(target)
+		after := e.c.Size()
+		if after < sizes[i] {
+			freed := sizes[i] - after
+			released += freed
+			if freed >= excess {
+				excess = 0
+			} else {
+				excess -= freed
+			}
+		}
+	}
+	a.releasedMeter.Mark(int64(released))
+	if excess != 0 {
+		a.Log.Warn("Memory budget is exceeded by the fixed components", "budget", a.budget, "excess", excess)
+	}
+	return released
+}
+
+// Start enforces the budget periodically, until stopped.
+func (a *Accountant) Start(period time.Duration) {
+	a.quit = make(chan struct{})
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if released := a.Enforce(); released != 0 {
+					a.Log.Debug("Shrunk caches to fit the memory budget", "released", released)
+				}
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop the periodic enforcement.
+func (a *Accountant) Stop() {
+	if a.quit == nil {
+		return
+	}
+	close(a.quit)
+	a.wg.Wait()
+	a.quit = nil
+}
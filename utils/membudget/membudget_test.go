@@ -0,0 +1,60 @@
+package membudget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
+)
+
+func newTestCache(entries int, weight uint) *lru.Cache[int, int] {
+	c := lru.New[int, int](uint(entries)*weight, entries, func(int, int) uint {
+		return weight
+	})
+	for i := 0; i < entries; i++ {
+		c.Add(i, i)
+	}
+	return c
+}
+
+func TestAccountant(t *testing.T) {
+	require := require.New(t)
+
+	low := newTestCache(10, 100)
+	normal := newTestCache(10, 100)
+	fixed := uint64(500)
+
+	a := New(2000)
+	a.Register("normal", PriorityNormal, Cache(normal))
+	a.Register("fixed", PriorityFixed, Func(func() uint64 { return fixed }))
+	a.Register("low", PriorityLow, Cache(low))
+	require.Equal(map[string]uint64{"low": 1000, "normal": 1000, "fixed": 500}, a.Usage())
+
+	// the low priority cache is shrunk first
+	require.Equal(uint64(500), a.Enforce())
+	require.Equal(uint(500), low.Weight())
+	require.Equal(uint(1000), normal.Weight())
+	// the newest entries are kept
+	require.True(low.Contains(9))
+	require.False(low.Contains(0))
+	// the capacity is clamped, so the cache doesn't refill over the shrunk size
+	for i := 10; i < 20; i++ {
+		low.Add(i, i)
+	}
+	require.Equal(uint(500), low.Weight())
+	require.True(low.Contains(19))
+
+	require.Equal(uint64(0), a.Enforce())
+
+	// then the next priority
+	fixed = 1400
+	require.Equal(uint64(900), a.Enforce())
+	require.Equal(uint(0), low.Weight())
+	require.Equal(uint(600), normal.Weight())
+
+	// the fixed components are never shrunk
+	fixed = 2500
+	require.Equal(uint64(600), a.Enforce())
+	require.Equal(uint64(2500), a.Usage()["fixed"])
+}
@@ -1,6 +1,8 @@
 package rlpstore
 
 import (
+	"fmt"
+
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -38,3 +40,22 @@ func (s *Helper) Get(table kvdb.Store, key []byte, to interface{}) interface{} {
 	}
 	return to
 }
+
+// TryGet returns RLP value, or an error if the value can't be read or is malformed.
+// It's intended for the records read by API, the failed reads of consensus records are fatal.
+func (s *Helper) TryGet(table kvdb.Store, key []byte, to interface{}) (interface{}, error) {
+	buf, err := table.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if buf == nil {
+		return nil, nil
+	}
+
+	err = rlp.DecodeBytes(buf, to)
+	if err != nil {
+		s.Log.Error("Failed to decode rlp", "err", err, "size", len(buf))
+		return nil, fmt.Errorf("malformed record: %w", err)
+	}
+	return to, nil
+}
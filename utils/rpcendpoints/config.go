@@ -0,0 +1,79 @@
+// Package rpcendpoints serves the additional HTTP RPC endpoints of a node. Each endpoint has its own
+// set of the API namespaces, CORS domains, rate limit and caps, so a single node may serve a public endpoint
+// along with an internal endpoint of a power user.
+package rpcendpoints
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// Config is a config of a virtual RPC endpoint.
+type Config struct {
+	// Name identifies the endpoint in the logs and the metrics
+	Name string
+	// Host and Port are the address of the own HTTP server of the endpoint.
+	// If Port is zero, the endpoint is mounted on the HTTP server of the node at PathPrefix instead.
+	Host       string `toml:",omitempty"`
+	Port       int    `toml:",omitempty"`
+	PathPrefix string `toml:",omitempty"`
+	// Modules are the API namespaces served by the endpoint. The public namespaces are served if it's empty.
+	Modules     []string
+	CorsDomains []string `toml:",omitempty"`
+	// VirtualHosts are the allowed host names of the requests, localhost by default
+	VirtualHosts []string `toml:",omitempty"`
+	// RateLimit is the maximum number of the requests per second, zero means no limit.
+	// RateBurst is the number of the requests allowed over the limit at once, RateLimit by default.
+	RateLimit float64 `toml:",omitempty"`
+	RateBurst int     `toml:",omitempty"`
+	// MaxConcurrent is the maximum number of the requests served at once, the others wait for their turn.
+	// Zero means no limit.
+	MaxConcurrent int `toml:",omitempty"`
+	// MaxRequestSize is the maximum size of a request body in bytes, zero means the default limit of the RPC server.
+	MaxRequestSize int64 `toml:",omitempty"`
+}
+
+// Validate checks the endpoints configs against the node config.
+func Validate(configs []Config, nodeCfg *node.Config) error {
+	names := make(map[string]bool)
+	ports := make(map[int]bool)
+	prefixes := make(map[string]bool)
+	for _, cfg := range configs {
+		if cfg.Name == "" {
+			return errors.New("RPC endpoint name is empty")
+		}
+		if names[cfg.Name] {
+			return fmt.Errorf("RPC endpoint %s is defined twice", cfg.Name)
+		}
+		names[cfg.Name] = true
+		if cfg.RateLimit < 0 || cfg.RateBurst < 0 || cfg.MaxConcurrent < 0 || cfg.MaxRequestSize < 0 {
+			return fmt.Errorf("RPC endpoint %s has a negative limit", cfg.Name)
+		}
+		if cfg.Port != 0 {
+			if cfg.Port < 0 || cfg.Port > 65535 {
+				return fmt.Errorf("RPC endpoint %s has invalid port %d", cfg.Name, cfg.Port)
+			}
+			if ports[cfg.Port] || nodeCfg.HTTPHost != "" && cfg.Port == nodeCfg.HTTPPort ||
+				nodeCfg.WSHost != "" && cfg.Port == nodeCfg.WSPort {
+				return fmt.Errorf("RPC endpoint %s port %d is already used", cfg.Name, cfg.Port)
+			}
+			ports[cfg.Port] = true
+			continue
+		}
+		// the endpoint is mounted on the node HTTP server
+		if nodeCfg.HTTPHost == "" {
+			return fmt.Errorf("RPC endpoint %s has no port, and the HTTP server of the node is disabled", cfg.Name)
+		}
+		if !strings.HasPrefix(cfg.PathPrefix, "/") || cfg.PathPrefix == "/" {
+			return fmt.Errorf("RPC endpoint %s has no port, and its path prefix %q isn't a subpath", cfg.Name, cfg.PathPrefix)
+		}
+		if prefixes[cfg.PathPrefix] || cfg.PathPrefix == nodeCfg.HTTPPathPrefix {
+			return fmt.Errorf("RPC endpoint %s path prefix %s is already used", cfg.Name, cfg.PathPrefix)
+		}
+		prefixes[cfg.PathPrefix] = true
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package rpcendpoints
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// Endpoints are the virtual RPC endpoints of a node, which have their own HTTP servers.
+// The endpoints mounted on the HTTP server of the node are served along with it.
+type Endpoints struct {
+	rpcs    []*rpc.Server
+	servers []*server
+}
+
+type server struct {
+	cfg  Config
+	http *http.Server
+}
+
+// Register creates the endpoints serving the given APIs. The endpoints without own ports are mounted
+// on the HTTP server of the node, the others are started and stopped along with the node.
+func Register(stack *node.Node, apis []rpc.API, configs []Config) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	if err := Validate(configs, stack.Config()); err != nil {
+		return err
+	}
+	e := &Endpoints{}
+	for _, cfg := range configs {
+		srv, handler, err := newHandler(cfg, apis)
+		if err != nil {
+			return err
+		}
+		e.rpcs = append(e.rpcs, srv)
+		if cfg.Port == 0 {
+			stack.RegisterHandler("RPC endpoint "+cfg.Name, cfg.PathPrefix, handler)
+			continue
+		}
+		e.servers = append(e.servers, &server{
+			cfg: cfg,
+			http: &http.Server{
+				Handler:      handler,
+				ReadTimeout:  rpc.DefaultHTTPTimeouts.ReadTimeout,
+				WriteTimeout: rpc.DefaultHTTPTimeouts.WriteTimeout,
+				IdleTimeout:  rpc.DefaultHTTPTimeouts.IdleTimeout,
+			},
+		})
+	}
+	stack.RegisterLifecycle(e)
+	return nil
+}
+
+// Start starts the HTTP servers of the endpoints.
+func (e *Endpoints) Start() error {
+	for i, s := range e.servers {
+		host := s.cfg.Host
+		if host == "" {
+			host = node.DefaultHTTPHost
+		}
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, fmt.Sprint(s.cfg.Port)))
+		if err != nil {
+			for _, started := range e.servers[:i] {
+				_ = started.http.Close()
+			}
+			return fmt.Errorf("failed to open RPC endpoint %s: %w", s.cfg.Name, err)
+		}
+		go func(s *server) {
+			_ = s.http.Serve(listener)
+		}(s)
+		log.Info("RPC endpoint opened", "name", s.cfg.Name, "url", "http://"+listener.Addr().String(), "modules", s.cfg.Modules)
+	}
+	return nil
+}
+
+// Stop stops the endpoints.
+func (e *Endpoints) Stop() error {
+	for _, srv := range e.rpcs {
+		srv.Stop()
+	}
+	for _, s := range e.servers {
+		_ = s.http.Close()
+		log.Info("RPC endpoint closed", "name", s.cfg.Name)
+	}
+	return nil
+}
+
+// newHandler creates the RPC server of the endpoint and its HTTP handler.
+func newHandler(cfg Config, apis []rpc.API) (*rpc.Server, http.Handler, error) {
+	srv := rpc.NewServer()
+	if err := node.RegisterApis(apis, cfg.Modules, srv, false); err != nil {
+		return nil, nil, fmt.Errorf("failed to register APIs of RPC endpoint %s: %w", cfg.Name, err)
+	}
+	limited := &limiter{
+		next:    srv,
+		maxSize: cfg.MaxRequestSize,
+		limited: metrics.GetOrRegisterMeter("rpc/endpoints/"+cfg.Name+"/limited", nil),
+	}
+	if cfg.RateLimit > 0 {
+		burst := cfg.RateBurst
+		if burst == 0 {
+			burst = int(cfg.RateLimit)
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		limited.rate = rate.NewLimiter(rate.Limit(cfg.RateLimit), burst)
+	}
+	if cfg.MaxConcurrent > 0 {
+		limited.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	vhosts := cfg.VirtualHosts
+	if len(vhosts) == 0 {
+		vhosts = node.DefaultConfig.HTTPVirtualHosts
+	}
+	return srv, node.NewHTTPHandlerStack(limited, cfg.CorsDomains, vhosts), nil
+}
+
+// limiter applies the limits of the endpoint to the requests.
+type limiter struct {
+	next    http.Handler
+	rate    *rate.Limiter // nil if unlimited
+	slots   chan struct{} // nil if unlimited
+	maxSize int64
+	limited metrics.Meter
+}
+
+func (l *limiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.maxSize > 0 {
+		if r.ContentLength > l.maxSize {
+			l.limited.Mark(1)
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, l.maxSize)
+	}
+	if l.rate != nil && !l.rate.Allow() {
+		l.limited.Mark(1)
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			defer func() { <-l.slots }()
+		case <-r.Context().Done():
+			return
+		}
+	}
+	l.next.ServeHTTP(w, r)
+}
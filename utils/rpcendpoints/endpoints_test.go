@@ -0,0 +1,111 @@
+package rpcendpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type testAPI struct{}
+
+func (testAPI) Echo(s string) string {
+	return s
+}
+
+var testAPIs = []rpc.API{
+	{Namespace: "pub", Service: testAPI{}, Public: true},
+	{Namespace: "internal", Service: testAPI{}, Public: false},
+}
+
+func call(t *testing.T, h http.Handler, method string) (int, map[string]interface{}) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  []string{"hi"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var resp map[string]interface{}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec.Code, resp
+}
+
+func TestEndpointModules(t *testing.T) {
+	require := require.New(t)
+
+	_, public, err := newHandler(Config{Name: "public"}, testAPIs)
+	require.NoError(err)
+	_, internal, err := newHandler(Config{Name: "internal", Modules: []string{"pub", "internal"}}, testAPIs)
+	require.NoError(err)
+
+	code, resp := call(t, public, "pub_echo")
+	require.Equal(http.StatusOK, code)
+	require.Equal("hi", resp["result"])
+	_, resp = call(t, public, "internal_echo")
+	require.NotNil(resp["error"])
+
+	_, resp = call(t, internal, "internal_echo")
+	require.Equal("hi", resp["result"])
+}
+
+func TestEndpointLimits(t *testing.T) {
+	require := require.New(t)
+
+	_, h, err := newHandler(Config{Name: "limited", RateLimit: 0.001, RateBurst: 2}, testAPIs)
+	require.NoError(err)
+	for i := 0; i < 2; i++ {
+		code, _ := call(t, h, "pub_echo")
+		require.Equal(http.StatusOK, code)
+	}
+	code, _ := call(t, h, "pub_echo")
+	require.Equal(http.StatusTooManyRequests, code)
+
+	_, h, err = newHandler(Config{Name: "small", MaxRequestSize: 16}, testAPIs)
+	require.NoError(err)
+	code, _ = call(t, h, "pub_echo")
+	require.Equal(http.StatusRequestEntityTooLarge, code)
+}
+
+func TestValidate(t *testing.T) {
+	nodeCfg := node.DefaultConfig
+	nodeCfg.HTTPHost = "localhost"
+
+	for name, test := range map[string]struct {
+		configs []Config
+		valid   bool
+	}{
+		"Own port":        {[]Config{{Name: "a", Port: 8600}}, true},
+		"Mounted":         {[]Config{{Name: "a", PathPrefix: "/internal"}, {Name: "b", Port: 8600}}, true},
+		"No name":         {[]Config{{Port: 8600}}, false},
+		"Same name":       {[]Config{{Name: "a", Port: 8600}, {Name: "a", Port: 8601}}, false},
+		"Same port":       {[]Config{{Name: "a", Port: 8600}, {Name: "b", Port: 8600}}, false},
+		"Node port":       {[]Config{{Name: "a", Port: nodeCfg.HTTPPort}}, false},
+		"Root prefix":     {[]Config{{Name: "a", PathPrefix: "/"}}, false},
+		"Same prefix":     {[]Config{{Name: "a", PathPrefix: "/x"}, {Name: "b", PathPrefix: "/x"}}, false},
+		"Negative limits": {[]Config{{Name: "a", Port: 8600, RateLimit: -1}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(test.configs, &nodeCfg)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	disabled := node.DefaultConfig
+	disabled.HTTPHost = ""
+	require.Error(t, Validate([]Config{{Name: "a", PathPrefix: "/internal"}}, &disabled))
+}
@@ -3,15 +3,16 @@ package gsignercache
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/Fantom-foundation/go-opera/utils/lru"
 )
 
 var (
-	globalCache, _ = lru.New(40000)
+	globalCache = lru.New[common.Hash, types.CachedSender](40000, 40000, nil)
 )
 
 type WlruCache struct {
-	Cache *lru.Cache
+	Cache *lru.Cache[common.Hash, types.CachedSender]
 }
 
 func (w *WlruCache) Add(txid common.Hash, c types.CachedSender) {
@@ -19,11 +20,10 @@ func (w *WlruCache) Add(txid common.Hash, c types.CachedSender) {
 }
 
 func (w *WlruCache) Get(txid common.Hash) *types.CachedSender {
-	ic, ok := w.Cache.Get(txid)
+	c, ok := w.Cache.Get(txid)
 	if !ok {
 		return nil
 	}
-	c := ic.(types.CachedSender)
 	return &c
 }
 
@@ -0,0 +1,21 @@
+package version
+
+import (
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ClientIdentifier to advertise over the network and the RPC.
+const ClientIdentifier = "Sonic"
+
+var (
+	// Git SHA1 commit hash of the release (set via linker flags).
+	GitCommit = ""
+	// Git commit date of the release (set via linker flags).
+	GitDate = ""
+)
+
+// WithCommit returns the version with the metadata, the commit hash and the date of the release.
+// It's the single source of the version reported by web3_clientVersion, the P2P handshake and the CLI.
+func WithCommit() string {
+	return params.VersionWithCommit(GitCommit, GitDate)
+}
@@ -39,3 +39,17 @@ func TestAsBigInt(t *testing.T) {
 		prev = next
 	}
 }
+
+func TestWithCommit(t *testing.T) {
+	require := require.New(t)
+
+	defer func(commit, date string) {
+		GitCommit, GitDate = commit, date
+	}(GitCommit, GitDate)
+
+	GitCommit, GitDate = "", ""
+	require.Equal(AsString()+"-g", WithCommit())
+
+	GitCommit, GitDate = "0123456789abcdef", "1700000000"
+	require.Equal(AsString()+"-g-01234567-1700000000", WithCommit())
+}